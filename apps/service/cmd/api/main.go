@@ -25,7 +25,7 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer" followed by a space and JWT token.
+// @description Type "Bearer" followed by a space and JWT token or shop API key.
 
 func main() {
 	config, err := config.Load()
@@ -39,7 +39,10 @@ func main() {
 		log.Fatalf("Failed to connect database %v", err)
 	}
 
-	server := http.NewServer(config, database.DB())
+	server, err := http.NewServer(config, database.DB())
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
 
 	if err := server.Start(); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	AccessActionGrant     = "grant"
+	AccessActionRevoke    = "revoke"
+	AccessActionSuspend   = "suspend"
+	AccessActionReinstate = "reinstate"
+)
+
+// AccessAuditLog records a single change to the access of a staff member:
+// a permission or a role that was granted or revoked, or a suspension or
+// reinstatement, and who did it. Grants and revocations set exactly one of
+// Permission and RoleID.
+type AccessAuditLog struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	ShopID     uint64     `gorm:"column:shop_id;not null" json:"shop_id"`
+	StaffID    uint64     `gorm:"column:staff_id;not null" json:"staff_id"`
+	ActorID    uint64     `gorm:"column:actor_id;not null" json:"actor_id"`
+	Action     string     `gorm:"column:action;not null" json:"action"`
+	Permission Permission `gorm:"column:permission;type:varchar(100)" json:"permission,omitempty"`
+	RoleID     *uint64    `gorm:"column:role_id" json:"role_id,omitempty"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (AccessAuditLog) TableName() string {
+	return "access_audit_logs"
+}
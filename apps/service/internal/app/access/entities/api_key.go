@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"time"
+)
+
+// APIKeyTokenPrefix starts every API key, which tells them apart from
+// access tokens in the Authorization header.
+const APIKeyTokenPrefix = "wsk_"
+
+// APIKey lets an integration call the routes of one shop without a login.
+// It acts as the staff membership of the user who created it, narrowed to
+// the permissions selected for the key, so it stops working once that user
+// leaves the shop. Prefix is shown in listings to tell keys apart; only the
+// SHA-256 hash of the full key is stored.
+type APIKey struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	ShopID     uint64     `gorm:"column:shop_id;not null;index:idx_api_keys_shop_id" json:"shop_id"`
+	UserID     uint64     `gorm:"column:user_id;not null" json:"user_id"`
+	Name       string     `gorm:"column:name;not null" json:"name"`
+	Prefix     string     `gorm:"column:prefix;not null;uniqueIndex:idx_api_keys_prefix" json:"prefix"`
+	KeyHash    string     `gorm:"column:key_hash;not null" json:"-"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+
+	Permissions []APIKeyPermission `gorm:"foreignKey:APIKeyID" json:"permissions,omitempty"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key has been neither revoked nor expired.
+func (k APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// PermissionNames lists the permissions selected for the key.
+func (k APIKey) PermissionNames() []Permission {
+	permissions := make([]Permission, len(k.Permissions))
+	for i, permission := range k.Permissions {
+		permissions[i] = permission.Permission
+	}
+	return permissions
+}
+
+// Allows reports whether permission was selected for the key. The creator
+// must still hold it for a request to go through.
+func (k APIKey) Allows(permission Permission) bool {
+	for _, selected := range k.Permissions {
+		if selected.Permission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyPermission selects a permission for an API key.
+type APIKeyPermission struct {
+	APIKeyID   uint64     `gorm:"primaryKey;column:api_key_id;autoIncrement:false" json:"api_key_id"`
+	Permission Permission `gorm:"primaryKey;column:permission;type:varchar(100)" json:"permission"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (APIKeyPermission) TableName() string {
+	return "api_key_permissions"
+}
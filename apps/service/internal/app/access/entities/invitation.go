@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// Invitation offers a role in a shop to someone identified by email or
+// phone, who may not have an account yet. UserID is filled in once the
+// contact belongs to a user. Only the SHA-256 hash of the token is stored.
+type Invitation struct {
+	ID          uint64     `gorm:"primaryKey;column:id" json:"id"`
+	ShopID      uint64     `gorm:"column:shop_id;not null;index:idx_invitations_shop_id" json:"shop_id"`
+	RoleID      uint64     `gorm:"column:role_id;not null" json:"role_id"`
+	InviterID   uint64     `gorm:"column:inviter_id;not null" json:"inviter_id"`
+	UserID      *uint64    `gorm:"column:user_id;index:idx_invitations_user_id" json:"user_id"`
+	Email       string     `gorm:"column:email;not null;default:''" json:"email,omitempty"`
+	Phone       string     `gorm:"column:phone;not null;default:''" json:"phone,omitempty"`
+	TokenHash   string     `gorm:"column:token_hash;not null;uniqueIndex:idx_invitations_token_hash" json:"-"`
+	Status      string     `gorm:"column:status;not null" json:"status"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	RespondedAt *time.Time `gorm:"column:responded_at" json:"responded_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at" json:"updated_at"`
+
+	Shop *shopentities.Shop `gorm:"foreignKey:ShopID" json:"shop,omitempty"`
+	Role *Role              `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+}
+
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+// IsPending reports whether the invitation can still be accepted or
+// declined.
+func (i Invitation) IsPending(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"time"
+)
+
+// Permission names an action a staff member may perform within a shop.
+type Permission string
+
+const (
+	PermissionShopUpdate      Permission = "shop.update"
+	PermissionShopDelete      Permission = "shop.delete"
+	PermissionStaffView       Permission = "staff.view"
+	PermissionStaffAssign     Permission = "staff.assign"
+	PermissionStaffRemove     Permission = "staff.remove"
+	PermissionStaffUnlock     Permission = "staff.unlock"
+	PermissionRoleManage      Permission = "role.manage"
+	PermissionAPIKeyManage    Permission = "api_key.manage"
+	PermissionCatalogManage   Permission = "catalog.manage"
+	PermissionInventoryView   Permission = "inventory.view"
+	PermissionInventoryAdjust Permission = "inventory.adjust"
+	PermissionSalesCreate     Permission = "sales.create"
+	PermissionReportsView     Permission = "reports.view"
+)
+
+type PermissionDefinition struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
+
+// PermissionCatalog lists every permission a role can be granted.
+var PermissionCatalog = []PermissionDefinition{
+	{Name: PermissionShopUpdate, Description: "Edit the shop profile"},
+	{Name: PermissionShopDelete, Description: "Delete the shop"},
+	{Name: PermissionStaffView, Description: "View staff members"},
+	{Name: PermissionStaffAssign, Description: "Add staff members and change their roles"},
+	{Name: PermissionStaffRemove, Description: "Remove staff members"},
+	{Name: PermissionStaffUnlock, Description: "Lift login lockouts of staff members"},
+	{Name: PermissionRoleManage, Description: "Create, edit and delete roles"},
+	{Name: PermissionAPIKeyManage, Description: "Create and revoke API keys for integrations"},
+	{Name: PermissionCatalogManage, Description: "Manage products and prices"},
+	{Name: PermissionInventoryView, Description: "View stock levels"},
+	{Name: PermissionInventoryAdjust, Description: "Adjust stock levels"},
+	{Name: PermissionSalesCreate, Description: "Ring up sales"},
+	{Name: PermissionReportsView, Description: "View sales reports"},
+}
+
+// AllPermissions returns the name of every permission in the catalog.
+func AllPermissions() []Permission {
+	permissions := make([]Permission, len(PermissionCatalog))
+	for i, definition := range PermissionCatalog {
+		permissions[i] = definition.Name
+	}
+	return permissions
+}
+
+// IsKnownPermission reports whether permission is part of the catalog.
+func IsKnownPermission(permission Permission) bool {
+	for _, definition := range PermissionCatalog {
+		if definition.Name == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolePermission grants a permission to every staff member holding the role.
+type RolePermission struct {
+	RoleID     uint64     `gorm:"primaryKey;column:role_id;autoIncrement:false" json:"role_id"`
+	Permission Permission `gorm:"primaryKey;column:permission;type:varchar(100)" json:"permission"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
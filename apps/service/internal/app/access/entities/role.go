@@ -6,11 +6,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// OwnerRoleName is the name of the role every shop is created with for the
+// user who created it.
+const OwnerRoleName = "Owner"
+
+// Role groups permissions handed to staff members of a shop. System roles,
+// such as the Owner role, are created with the shop and cannot be edited or
+// deleted.
 type Role struct {
 	ID          uint64         `gorm:"primaryKey;column:id" json:"id"`
 	Name        string         `gorm:"column:name;not null" json:"name"`
 	Description string         `gorm:"column:description;not null" json:"description"`
 	ShopID      uint64         `gorm:"column:shop_id;not null" json:"shop_id"`
+	IsSystem    bool           `gorm:"column:is_system;not null;default:false" json:"is_system"`
 	CreatedAt   time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
@@ -0,0 +1,53 @@
+package entities
+
+// RoleTemplate describes a role seeded into every new shop.
+type RoleTemplate struct {
+	Name        string
+	Description string
+	Permissions []Permission
+}
+
+// OwnerRoleTemplate is the system role held by the creator of a shop.
+var OwnerRoleTemplate = RoleTemplate{
+	Name:        OwnerRoleName,
+	Description: "Shop owner with full access to manage the shop",
+	Permissions: AllPermissions(),
+}
+
+// DefaultRoleTemplates are the editable roles every shop starts with next to
+// the Owner role.
+var DefaultRoleTemplates = []RoleTemplate{
+	{
+		Name:        "Manager",
+		Description: "Runs the shop day to day, including staff and stock",
+		Permissions: []Permission{
+			PermissionShopUpdate,
+			PermissionStaffView,
+			PermissionStaffAssign,
+			PermissionStaffRemove,
+			PermissionStaffUnlock,
+			PermissionCatalogManage,
+			PermissionInventoryView,
+			PermissionInventoryAdjust,
+			PermissionSalesCreate,
+			PermissionReportsView,
+		},
+	},
+	{
+		Name:        "Cashier",
+		Description: "Rings up sales at the register",
+		Permissions: []Permission{
+			PermissionInventoryView,
+			PermissionSalesCreate,
+		},
+	},
+	{
+		Name:        "Stock Keeper",
+		Description: "Receives and counts stock",
+		Permissions: []Permission{
+			PermissionCatalogManage,
+			PermissionInventoryView,
+			PermissionInventoryAdjust,
+		},
+	},
+}
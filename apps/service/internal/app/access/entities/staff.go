@@ -8,15 +8,27 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	StaffStatusActive    = "active"
+	StaffStatusSuspended = "suspended"
+)
+
+// Staff is the membership of a user in a shop. Suspended staff members keep
+// their role, grants and history but hold no permissions until reinstated.
+// LocationIDs, when loaded, are the locations the staff member is limited to;
+// none means every location of the shop. See StaffLocation.
 type Staff struct {
 	ID        uint64         `gorm:"primaryKey;column:id" json:"id"`
 	UserID    uint64         `gorm:"column:user_id;not null" json:"user_id"`
 	RoleID    uint64         `gorm:"column:role_id;not null" json:"role_id"`
 	ShopID    uint64         `gorm:"column:shop_id;not null" json:"shop_id"`
+	Status    string         `gorm:"column:status;not null;default:active" json:"status"`
 	CreatedAt time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
 
+	LocationIDs []uint64 `gorm:"-" json:"location_ids"`
+
 	User *userentities.User `gorm:"foreignKey:UserID" json:"user"`
 	Role *Role              `gorm:"foreignKey:RoleID" json:"role"`
 	Shop *shopentities.Shop `gorm:"foreignKey:ShopID" json:"shop"`
@@ -25,3 +37,21 @@ type Staff struct {
 func (Staff) TableName() string {
 	return "staffs"
 }
+
+func (s Staff) IsSuspended() bool {
+	return s.Status == StaffStatusSuspended
+}
+
+// CanAccessLocation reports whether the staff member works at the location.
+// It only holds for staff loaded with their LocationIDs.
+func (s Staff) CanAccessLocation(locationID uint64) bool {
+	if len(s.LocationIDs) == 0 {
+		return true
+	}
+	for _, id := range s.LocationIDs {
+		if id == locationID {
+			return true
+		}
+	}
+	return false
+}
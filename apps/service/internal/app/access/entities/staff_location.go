@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+)
+
+// StaffLocation assigns a staff member to a location of their shop. Staff
+// assigned to any location only work at those; staff without assignments
+// work at every location of the shop.
+type StaffLocation struct {
+	StaffID    uint64    `gorm:"primaryKey;column:staff_id;autoIncrement:false" json:"staff_id"`
+	LocationID uint64    `gorm:"primaryKey;column:location_id;autoIncrement:false" json:"location_id"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (StaffLocation) TableName() string {
+	return "staff_locations"
+}
@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+)
+
+// StaffPermission grants a permission to a single staff member, on top of
+// the permissions of their role.
+type StaffPermission struct {
+	StaffID    uint64     `gorm:"primaryKey;column:staff_id;autoIncrement:false" json:"staff_id"`
+	Permission Permission `gorm:"primaryKey;column:permission;type:varchar(100)" json:"permission"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (StaffPermission) TableName() string {
+	return "staff_permissions"
+}
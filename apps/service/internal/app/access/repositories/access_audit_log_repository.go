@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type AccessAuditLogRepository interface {
+	// FindByStaffID returns the access changes of a staff member, newest first.
+	FindByStaffID(ctx context.Context, staffID uint64) []entities.AccessAuditLog
+	Create(ctx context.Context, log entities.AccessAuditLog) (entities.AccessAuditLog, error)
+}
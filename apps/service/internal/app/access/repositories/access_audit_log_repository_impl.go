@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type accessAuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessAuditLogRepository(db *gorm.DB) AccessAuditLogRepository {
+	return &accessAuditLogRepository{
+		db: db,
+	}
+}
+
+func (r *accessAuditLogRepository) FindByStaffID(ctx context.Context, staffID uint64) []entities.AccessAuditLog {
+	var logs []entities.AccessAuditLog
+	r.db.WithContext(ctx).Where("staff_id = ?", staffID).Order("created_at DESC, id DESC").Find(&logs)
+	return logs
+}
+
+func (r *accessAuditLogRepository) Create(ctx context.Context, log entities.AccessAuditLog) (entities.AccessAuditLog, error) {
+	err := r.db.WithContext(ctx).Create(&log).Error
+	if err != nil {
+		return log, err
+	}
+	return log, nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestAccessAuditLogRepository_FindByStaffID(t *testing.T) {
+	t.Run("returns changes of the staff member newest first", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.AccessAuditLog{})
+		repo := NewAccessAuditLogRepository(db)
+
+		roleID := uint64(7)
+		_, err := repo.Create(ctx, entities.AccessAuditLog{ShopID: 1, StaffID: 1, ActorID: 9, Action: entities.AccessActionGrant, Permission: entities.PermissionSalesCreate})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, entities.AccessAuditLog{ShopID: 1, StaffID: 2, ActorID: 9, Action: entities.AccessActionGrant, Permission: entities.PermissionSalesCreate})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, entities.AccessAuditLog{ShopID: 1, StaffID: 1, ActorID: 9, Action: entities.AccessActionRevoke, RoleID: &roleID})
+		require.NoError(t, err)
+
+		logs := repo.FindByStaffID(ctx, 1)
+		require.Len(t, logs, 2)
+		assert.Equal(t, entities.AccessActionRevoke, logs[0].Action)
+		assert.Equal(t, &roleID, logs[0].RoleID)
+		assert.Equal(t, entities.AccessActionGrant, logs[1].Action)
+		assert.Equal(t, entities.PermissionSalesCreate, logs[1].Permission)
+	})
+
+	t.Run("returns empty slice when the staff member has no changes", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.AccessAuditLog{})
+		repo := NewAccessAuditLogRepository(db)
+
+		assert.Empty(t, repo.FindByStaffID(ctx, 1))
+	})
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+// APIKeyRepository loads keys with their permissions.
+type APIKeyRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (entities.APIKey, error)
+	// FindByShopID includes revoked and expired keys.
+	FindByShopID(ctx context.Context, shopID uint64) []entities.APIKey
+	// Create stores the key together with its Permissions.
+	Create(ctx context.Context, key entities.APIKey) (entities.APIKey, error)
+	// Revoke fails with "api key already revoked" when the key was revoked
+	// before, concurrently or not.
+	Revoke(ctx context.Context, id uint64, at time.Time) error
+	TouchLastUsed(ctx context.Context, id uint64, at time.Time) error
+}
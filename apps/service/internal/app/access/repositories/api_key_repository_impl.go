@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) FindByID(ctx context.Context, id uint64) (entities.APIKey, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *apiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (entities.APIKey, error) {
+	return r.first(ctx, "prefix = ?", prefix)
+}
+
+func (r *apiKeyRepository) first(ctx context.Context, query string, args ...any) (entities.APIKey, error) {
+	var key entities.APIKey
+	err := r.db.WithContext(ctx).Where(query, args...).Preload("Permissions", orderPermissions).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return key, errors.New("api key not found")
+		}
+		return key, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) FindByShopID(ctx context.Context, shopID uint64) []entities.APIKey {
+	var keys []entities.APIKey
+	r.db.WithContext(ctx).
+		Where("shop_id = ?", shopID).
+		Preload("Permissions", orderPermissions).
+		Order("id").
+		Find(&keys)
+	return keys
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key entities.APIKey) (entities.APIKey, error) {
+	err := r.db.WithContext(ctx).Create(&key).Error
+	if err != nil {
+		return key, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint64, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("api key already revoked")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
+
+func orderPermissions(db *gorm.DB) *gorm.DB {
+	return db.Order("permission")
+}
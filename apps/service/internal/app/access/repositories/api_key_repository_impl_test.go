@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func newTestAPIKey(shopID uint64, prefix string, permissions ...entities.Permission) entities.APIKey {
+	key := entities.APIKey{
+		ShopID:  shopID,
+		UserID:  1,
+		Name:    "Integration",
+		Prefix:  prefix,
+		KeyHash: "hash-" + prefix,
+	}
+	for _, permission := range permissions {
+		key.Permissions = append(key.Permissions, entities.APIKeyPermission{Permission: permission})
+	}
+	return key
+}
+
+func setupAPIKeyTest(t *testing.T) APIKeyRepository {
+	db := testutil.SetupTestDB(t, &entities.APIKey{}, &entities.APIKeyPermission{})
+	return NewAPIKeyRepository(db)
+}
+
+func TestAPIKeyRepository_Create(t *testing.T) {
+	t.Run("stores the key with its permissions", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupAPIKeyTest(t)
+
+		created, err := repo.Create(ctx, newTestAPIKey(10, "wsk_000000000001", entities.PermissionSalesCreate, entities.PermissionInventoryView))
+		require.NoError(t, err)
+		assert.NotZero(t, created.ID)
+
+		found, err := repo.FindByPrefix(ctx, "wsk_000000000001")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, []entities.Permission{entities.PermissionInventoryView, entities.PermissionSalesCreate}, found.PermissionNames())
+		assert.True(t, found.Allows(entities.PermissionSalesCreate))
+		assert.False(t, found.Allows(entities.PermissionReportsView))
+	})
+
+	t.Run("fails for a prefix that is already taken", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupAPIKeyTest(t)
+
+		_, err := repo.Create(ctx, newTestAPIKey(10, "wsk_000000000001"))
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, newTestAPIKey(11, "wsk_000000000001"))
+		assert.Error(t, err)
+	})
+}
+
+func TestAPIKeyRepository_FindByPrefix(t *testing.T) {
+	t.Run("returns error when key not found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupAPIKeyTest(t)
+
+		_, err := repo.FindByPrefix(ctx, "wsk_missing")
+		assert.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+	})
+}
+
+func TestAPIKeyRepository_FindByShopID(t *testing.T) {
+	t.Run("returns the keys of the shop including revoked ones", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupAPIKeyTest(t)
+
+		first, err := repo.Create(ctx, newTestAPIKey(10, "wsk_000000000001"))
+		require.NoError(t, err)
+		second, err := repo.Create(ctx, newTestAPIKey(10, "wsk_000000000002", entities.PermissionReportsView))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestAPIKey(11, "wsk_000000000003"))
+		require.NoError(t, err)
+		require.NoError(t, repo.Revoke(ctx, first.ID, time.Now()))
+
+		keys := repo.FindByShopID(ctx, 10)
+		require.Len(t, keys, 2)
+		assert.Equal(t, first.ID, keys[0].ID)
+		assert.NotNil(t, keys[0].RevokedAt)
+		assert.Equal(t, second.ID, keys[1].ID)
+		assert.Equal(t, []entities.Permission{entities.PermissionReportsView}, keys[1].PermissionNames())
+	})
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	t.Run("revokes a key once", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupAPIKeyTest(t)
+
+		created, err := repo.Create(ctx, newTestAPIKey(10, "wsk_000000000001"))
+		require.NoError(t, err)
+		assert.True(t, created.IsActive(time.Now()))
+
+		require.NoError(t, repo.Revoke(ctx, created.ID, time.Now()))
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsActive(time.Now()))
+
+		err = repo.Revoke(ctx, created.ID, time.Now())
+		assert.Error(t, err)
+		assert.Equal(t, "api key already revoked", err.Error())
+	})
+}
+
+func TestAPIKeyRepository_TouchLastUsed(t *testing.T) {
+	t.Run("records the last use", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupAPIKeyTest(t)
+
+		created, err := repo.Create(ctx, newTestAPIKey(10, "wsk_000000000001"))
+		require.NoError(t, err)
+		assert.Nil(t, created.LastUsedAt)
+
+		usedAt := time.Now()
+		require.NoError(t, repo.TouchLastUsed(ctx, created.ID, usedAt))
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.LastUsedAt)
+		assert.WithinDuration(t, usedAt, *found.LastUsedAt, time.Second)
+	})
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type InvitationRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Invitation, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (entities.Invitation, error)
+	// FindPendingByShopID and FindPendingByUserID leave out invitations that
+	// have expired by now.
+	FindPendingByShopID(ctx context.Context, shopID uint64, now time.Time) []entities.Invitation
+	FindPendingByUserID(ctx context.Context, userID uint64, now time.Time) []entities.Invitation
+	// HasPending reports whether the shop has an unexpired pending invitation
+	// for the email or, when email is empty, the phone.
+	HasPending(ctx context.Context, shopID uint64, email, phone string, now time.Time) (bool, error)
+	Create(ctx context.Context, invitation entities.Invitation) (entities.Invitation, error)
+	// Respond atomically moves a pending invitation to status. It fails with
+	// "invitation is no longer pending" if it was answered or revoked
+	// concurrently.
+	Respond(ctx context.Context, id uint64, status string, at time.Time) error
+	// AttachToUser links the pending invitations sent to email or phone to a
+	// user who did not have an account when they were invited.
+	AttachToUser(ctx context.Context, userID uint64, email, phone string) error
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{
+		db: db,
+	}
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id uint64) (entities.Invitation, error) {
+	var invitation entities.Invitation
+	err := r.db.WithContext(ctx).Where("id = ?", id).Preload("Role").First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invitation, errors.New("invitation not found")
+		}
+		return invitation, err
+	}
+	return invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (entities.Invitation, error) {
+	var invitation entities.Invitation
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Preload("Role").First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invitation, errors.New("invitation not found")
+		}
+		return invitation, err
+	}
+	return invitation, nil
+}
+
+func (r *invitationRepository) FindPendingByShopID(ctx context.Context, shopID uint64, now time.Time) []entities.Invitation {
+	var invitations []entities.Invitation
+	r.db.WithContext(ctx).
+		Where("shop_id = ? AND status = ? AND expires_at > ?", shopID, entities.InvitationStatusPending, now).
+		Preload("Role").
+		Order("id").
+		Find(&invitations)
+	return invitations
+}
+
+func (r *invitationRepository) FindPendingByUserID(ctx context.Context, userID uint64, now time.Time) []entities.Invitation {
+	var invitations []entities.Invitation
+	r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, entities.InvitationStatusPending, now).
+		Preload("Shop").
+		Preload("Role").
+		Order("id").
+		Find(&invitations)
+	return invitations
+}
+
+func (r *invitationRepository) HasPending(ctx context.Context, shopID uint64, email, phone string, now time.Time) (bool, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.Invitation{}).
+		Where("shop_id = ? AND status = ? AND expires_at > ?", shopID, entities.InvitationStatusPending, now)
+	if email != "" {
+		query = query.Where("email = ?", email)
+	} else {
+		query = query.Where("phone = ?", phone)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation entities.Invitation) (entities.Invitation, error) {
+	err := r.db.WithContext(ctx).Create(&invitation).Error
+	if err != nil {
+		return invitation, err
+	}
+	return invitation, nil
+}
+
+func (r *invitationRepository) Respond(ctx context.Context, id uint64, status string, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.Invitation{}).
+		Where("id = ? AND status = ?", id, entities.InvitationStatusPending).
+		Updates(map[string]any{"status": status, "responded_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invitation is no longer pending")
+	}
+	return nil
+}
+
+func (r *invitationRepository) AttachToUser(ctx context.Context, userID uint64, email, phone string) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.Invitation{}).
+		Where("user_id IS NULL AND status = ?", entities.InvitationStatusPending).
+		Where(r.db.Where("email <> '' AND email = ?", email).Or("phone <> '' AND phone = ?", phone)).
+		Update("user_id", userID).Error
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func newTestInvitation(shopID uint64, email, phone, tokenHash string) entities.Invitation {
+	return entities.Invitation{
+		ShopID:    shopID,
+		RoleID:    1,
+		InviterID: 1,
+		Email:     email,
+		Phone:     phone,
+		TokenHash: tokenHash,
+		Status:    entities.InvitationStatusPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func setupInvitationTest(t *testing.T) InvitationRepository {
+	db := testutil.SetupTestDB(t, &shopentities.Shop{}, &entities.Role{}, &entities.Invitation{})
+	return NewInvitationRepository(db)
+}
+
+func TestInvitationRepository_FindByTokenHash(t *testing.T) {
+	t.Run("returns invitation when found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupInvitationTest(t)
+
+		created, err := repo.Create(ctx, newTestInvitation(10, "jane@example.com", "", "hash-1"))
+		require.NoError(t, err)
+
+		found, err := repo.FindByTokenHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.True(t, found.IsPending(time.Now()))
+	})
+
+	t.Run("returns error when invitation not found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupInvitationTest(t)
+
+		_, err := repo.FindByTokenHash(ctx, "missing")
+		assert.Error(t, err)
+		assert.Equal(t, "invitation not found", err.Error())
+	})
+}
+
+func TestInvitationRepository_FindPendingByShopID(t *testing.T) {
+	t.Run("leaves out answered and expired invitations", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupInvitationTest(t)
+
+		pending, err := repo.Create(ctx, newTestInvitation(10, "jane@example.com", "", "hash-1"))
+		require.NoError(t, err)
+		answered, err := repo.Create(ctx, newTestInvitation(10, "john@example.com", "", "hash-2"))
+		require.NoError(t, err)
+		require.NoError(t, repo.Respond(ctx, answered.ID, entities.InvitationStatusDeclined, time.Now()))
+		expired := newTestInvitation(10, "joe@example.com", "", "hash-3")
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		_, err = repo.Create(ctx, expired)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestInvitation(11, "jane@example.com", "", "hash-4"))
+		require.NoError(t, err)
+
+		invitations := repo.FindPendingByShopID(ctx, 10, time.Now())
+		require.Len(t, invitations, 1)
+		assert.Equal(t, pending.ID, invitations[0].ID)
+	})
+}
+
+func TestInvitationRepository_HasPending(t *testing.T) {
+	t.Run("matches by email or phone", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupInvitationTest(t)
+
+		_, err := repo.Create(ctx, newTestInvitation(10, "jane@example.com", "", "hash-1"))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestInvitation(10, "", "1234567890", "hash-2"))
+		require.NoError(t, err)
+
+		ok, err := repo.HasPending(ctx, 10, "jane@example.com", "", time.Now())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.HasPending(ctx, 10, "", "1234567890", time.Now())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.HasPending(ctx, 11, "jane@example.com", "", time.Now())
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestInvitationRepository_Respond(t *testing.T) {
+	t.Run("answers the invitation only once", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupInvitationTest(t)
+
+		created, err := repo.Create(ctx, newTestInvitation(10, "jane@example.com", "", "hash-1"))
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Respond(ctx, created.ID, entities.InvitationStatusAccepted, time.Now()))
+
+		err = repo.Respond(ctx, created.ID, entities.InvitationStatusDeclined, time.Now())
+		assert.Error(t, err)
+		assert.Equal(t, "invitation is no longer pending", err.Error())
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.InvitationStatusAccepted, found.Status)
+		assert.NotNil(t, found.RespondedAt)
+	})
+}
+
+func TestInvitationRepository_AttachToUser(t *testing.T) {
+	t.Run("links pending invitations sent to the contact", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupInvitationTest(t)
+
+		byEmail, err := repo.Create(ctx, newTestInvitation(10, "jane@example.com", "", "hash-1"))
+		require.NoError(t, err)
+		byPhone, err := repo.Create(ctx, newTestInvitation(11, "", "1234567890", "hash-2"))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestInvitation(12, "john@example.com", "", "hash-3"))
+		require.NoError(t, err)
+
+		require.NoError(t, repo.AttachToUser(ctx, 7, "jane@example.com", "1234567890"))
+
+		invitations := repo.FindPendingByUserID(ctx, 7, time.Now())
+		require.Len(t, invitations, 2)
+		assert.Equal(t, byEmail.ID, invitations[0].ID)
+		assert.Equal(t, byPhone.ID, invitations[1].ID)
+	})
+}
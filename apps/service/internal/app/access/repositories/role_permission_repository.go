@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type RolePermissionRepository interface {
+	FindByRoleID(ctx context.Context, roleID uint64) []entities.Permission
+	HasPermission(ctx context.Context, roleID uint64, permission entities.Permission) (bool, error)
+	// ReplaceForRole deletes every permission of the role and grants the given ones.
+	ReplaceForRole(ctx context.Context, roleID uint64, permissions []entities.Permission) error
+}
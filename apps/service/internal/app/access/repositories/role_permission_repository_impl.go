@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type rolePermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewRolePermissionRepository(db *gorm.DB) RolePermissionRepository {
+	return &rolePermissionRepository{
+		db: db,
+	}
+}
+
+func (r *rolePermissionRepository) FindByRoleID(ctx context.Context, roleID uint64) []entities.Permission {
+	var permissions []entities.Permission
+	r.db.WithContext(ctx).
+		Model(&entities.RolePermission{}).
+		Where("role_id = ?", roleID).
+		Order("permission").
+		Pluck("permission", &permissions)
+	return permissions
+}
+
+func (r *rolePermissionRepository) HasPermission(ctx context.Context, roleID uint64, permission entities.Permission) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.RolePermission{}).
+		Where("role_id = ? AND permission = ?", roleID, permission).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *rolePermissionRepository) ReplaceForRole(ctx context.Context, roleID uint64, permissions []entities.Permission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&entities.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		grants := make([]entities.RolePermission, 0, len(permissions))
+		for _, permission := range permissions {
+			grants = append(grants, entities.RolePermission{
+				RoleID:     roleID,
+				Permission: permission,
+			})
+		}
+		return tx.Create(&grants).Error
+	})
+}
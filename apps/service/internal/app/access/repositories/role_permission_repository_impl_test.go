@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestRolePermissionRepository_ReplaceForRole(t *testing.T) {
+	t.Run("replaces previous permissions of the role only", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RolePermission{})
+		repo := NewRolePermissionRepository(db)
+
+		require.NoError(t, repo.ReplaceForRole(ctx, 1, []entities.Permission{entities.PermissionShopUpdate, entities.PermissionShopDelete}))
+		require.NoError(t, repo.ReplaceForRole(ctx, 2, []entities.Permission{entities.PermissionSalesCreate}))
+		require.NoError(t, repo.ReplaceForRole(ctx, 1, []entities.Permission{entities.PermissionStaffView}))
+
+		assert.Equal(t, []entities.Permission{entities.PermissionStaffView}, repo.FindByRoleID(ctx, 1))
+		assert.Equal(t, []entities.Permission{entities.PermissionSalesCreate}, repo.FindByRoleID(ctx, 2))
+	})
+
+	t.Run("clears permissions when given none", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RolePermission{})
+		repo := NewRolePermissionRepository(db)
+
+		require.NoError(t, repo.ReplaceForRole(ctx, 1, []entities.Permission{entities.PermissionShopUpdate}))
+		require.NoError(t, repo.ReplaceForRole(ctx, 1, nil))
+
+		assert.Empty(t, repo.FindByRoleID(ctx, 1))
+	})
+}
+
+func TestRolePermissionRepository_HasPermission(t *testing.T) {
+	t.Run("reports granted permissions", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RolePermission{})
+		repo := NewRolePermissionRepository(db)
+
+		require.NoError(t, repo.ReplaceForRole(ctx, 1, []entities.Permission{entities.PermissionShopUpdate}))
+
+		ok, err := repo.HasPermission(ctx, 1, entities.PermissionShopUpdate)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.HasPermission(ctx, 1, entities.PermissionShopDelete)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.HasPermission(ctx, 2, entities.PermissionShopUpdate)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -7,6 +7,8 @@ import (
 )
 
 type RoleRepository interface {
+	// All returns the roles of every shop. Within a shop request in row-level
+	// security mode it only sees the current shop.
 	All(ctx context.Context) []entities.Role
 	FindByID(ctx context.Context, id uint64) (entities.Role, error)
 	FindByShopID(ctx context.Context, shopID uint64) []entities.Role
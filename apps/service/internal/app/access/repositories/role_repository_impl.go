@@ -39,7 +39,7 @@ func (r *roleRepository) FindByID(ctx context.Context, id uint64) (entities.Role
 
 func (r *roleRepository) FindByShopID(ctx context.Context, shopID uint64) []entities.Role {
 	var roles []entities.Role
-	r.db.WithContext(ctx).Where("shop_id = ?", shopID).Find(&roles)
+	r.db.WithContext(ctx).Where("shop_id = ?", shopID).Order("id").Find(&roles)
 	return roles
 }
 
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+)
+
+type StaffLocationRepository interface {
+	// FindByStaffID returns the IDs of the locations the staff member is
+	// assigned to, in ascending order.
+	FindByStaffID(ctx context.Context, staffID uint64) []uint64
+	// ReplaceForStaff assigns the staff member to exactly locationIDs. An
+	// empty list lifts every assignment.
+	ReplaceForStaff(ctx context.Context, staffID uint64, locationIDs []uint64) error
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type staffLocationRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffLocationRepository(db *gorm.DB) StaffLocationRepository {
+	return &staffLocationRepository{
+		db: db,
+	}
+}
+
+func (r *staffLocationRepository) FindByStaffID(ctx context.Context, staffID uint64) []uint64 {
+	var locationIDs []uint64
+	r.db.WithContext(ctx).
+		Model(&entities.StaffLocation{}).
+		Where("staff_id = ?", staffID).
+		Order("location_id").
+		Pluck("location_id", &locationIDs)
+	return locationIDs
+}
+
+func (r *staffLocationRepository) ReplaceForStaff(ctx context.Context, staffID uint64, locationIDs []uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("staff_id = ?", staffID).Delete(&entities.StaffLocation{}).Error; err != nil {
+			return err
+		}
+
+		if len(locationIDs) == 0 {
+			return nil
+		}
+
+		assignments := make([]entities.StaffLocation, 0, len(locationIDs))
+		for _, locationID := range locationIDs {
+			assignments = append(assignments, entities.StaffLocation{
+				StaffID:    staffID,
+				LocationID: locationID,
+			})
+		}
+		return tx.Create(&assignments).Error
+	})
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestStaffLocationRepository_ReplaceForStaff(t *testing.T) {
+	t.Run("replaces the locations of the staff member only", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffLocation{})
+		repo := NewStaffLocationRepository(db)
+
+		require.NoError(t, repo.ReplaceForStaff(ctx, 1, []uint64{3, 2}))
+		require.NoError(t, repo.ReplaceForStaff(ctx, 2, []uint64{2}))
+		assert.Equal(t, []uint64{2, 3}, repo.FindByStaffID(ctx, 1))
+
+		require.NoError(t, repo.ReplaceForStaff(ctx, 1, []uint64{4}))
+		assert.Equal(t, []uint64{4}, repo.FindByStaffID(ctx, 1))
+		assert.Equal(t, []uint64{2}, repo.FindByStaffID(ctx, 2))
+	})
+
+	t.Run("lifts every assignment with an empty list", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffLocation{})
+		repo := NewStaffLocationRepository(db)
+
+		require.NoError(t, repo.ReplaceForStaff(ctx, 1, []uint64{2}))
+		require.NoError(t, repo.ReplaceForStaff(ctx, 1, nil))
+		assert.Empty(t, repo.FindByStaffID(ctx, 1))
+	})
+}
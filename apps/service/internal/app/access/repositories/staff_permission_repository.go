@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type StaffPermissionRepository interface {
+	FindByStaffID(ctx context.Context, staffID uint64) []entities.Permission
+	HasPermission(ctx context.Context, staffID uint64, permission entities.Permission) (bool, error)
+	Grant(ctx context.Context, staffID uint64, permission entities.Permission) error
+	// Revoke fails with "permission not granted" when the staff member does
+	// not hold permission directly.
+	Revoke(ctx context.Context, staffID uint64, permission entities.Permission) error
+	DeleteByStaffID(ctx context.Context, staffID uint64) error
+}
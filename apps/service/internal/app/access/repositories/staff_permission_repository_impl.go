@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+type staffPermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffPermissionRepository(db *gorm.DB) StaffPermissionRepository {
+	return &staffPermissionRepository{
+		db: db,
+	}
+}
+
+func (r *staffPermissionRepository) FindByStaffID(ctx context.Context, staffID uint64) []entities.Permission {
+	var permissions []entities.Permission
+	r.db.WithContext(ctx).
+		Model(&entities.StaffPermission{}).
+		Where("staff_id = ?", staffID).
+		Order("permission").
+		Pluck("permission", &permissions)
+	return permissions
+}
+
+func (r *staffPermissionRepository) HasPermission(ctx context.Context, staffID uint64, permission entities.Permission) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.StaffPermission{}).
+		Where("staff_id = ? AND permission = ?", staffID, permission).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *staffPermissionRepository) Grant(ctx context.Context, staffID uint64, permission entities.Permission) error {
+	return r.db.WithContext(ctx).Create(&entities.StaffPermission{
+		StaffID:    staffID,
+		Permission: permission,
+	}).Error
+}
+
+func (r *staffPermissionRepository) Revoke(ctx context.Context, staffID uint64, permission entities.Permission) error {
+	result := r.db.WithContext(ctx).
+		Where("staff_id = ? AND permission = ?", staffID, permission).
+		Delete(&entities.StaffPermission{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("permission not granted")
+	}
+	return nil
+}
+
+func (r *staffPermissionRepository) DeleteByStaffID(ctx context.Context, staffID uint64) error {
+	return r.db.WithContext(ctx).Where("staff_id = ?", staffID).Delete(&entities.StaffPermission{}).Error
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestStaffPermissionRepository_Grant(t *testing.T) {
+	t.Run("grants permissions to the staff member only", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffPermission{})
+		repo := NewStaffPermissionRepository(db)
+
+		require.NoError(t, repo.Grant(ctx, 1, entities.PermissionSalesCreate))
+		require.NoError(t, repo.Grant(ctx, 1, entities.PermissionInventoryView))
+		require.NoError(t, repo.Grant(ctx, 2, entities.PermissionReportsView))
+
+		assert.Equal(t, []entities.Permission{entities.PermissionInventoryView, entities.PermissionSalesCreate}, repo.FindByStaffID(ctx, 1))
+
+		ok, err := repo.HasPermission(ctx, 1, entities.PermissionSalesCreate)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.HasPermission(ctx, 1, entities.PermissionReportsView)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("fails for a permission that is already granted", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffPermission{})
+		repo := NewStaffPermissionRepository(db)
+
+		require.NoError(t, repo.Grant(ctx, 1, entities.PermissionSalesCreate))
+		assert.Error(t, repo.Grant(ctx, 1, entities.PermissionSalesCreate))
+	})
+}
+
+func TestStaffPermissionRepository_Revoke(t *testing.T) {
+	t.Run("revokes a granted permission", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffPermission{})
+		repo := NewStaffPermissionRepository(db)
+
+		require.NoError(t, repo.Grant(ctx, 1, entities.PermissionSalesCreate))
+		require.NoError(t, repo.Revoke(ctx, 1, entities.PermissionSalesCreate))
+
+		assert.Empty(t, repo.FindByStaffID(ctx, 1))
+	})
+
+	t.Run("returns error when the permission is not granted", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffPermission{})
+		repo := NewStaffPermissionRepository(db)
+
+		err := repo.Revoke(ctx, 1, entities.PermissionSalesCreate)
+		assert.Error(t, err)
+		assert.Equal(t, "permission not granted", err.Error())
+	})
+}
+
+func TestStaffPermissionRepository_DeleteByStaffID(t *testing.T) {
+	t.Run("deletes every permission of the staff member", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StaffPermission{})
+		repo := NewStaffPermissionRepository(db)
+
+		require.NoError(t, repo.Grant(ctx, 1, entities.PermissionSalesCreate))
+		require.NoError(t, repo.Grant(ctx, 1, entities.PermissionReportsView))
+		require.NoError(t, repo.Grant(ctx, 2, entities.PermissionReportsView))
+
+		require.NoError(t, repo.DeleteByStaffID(ctx, 1))
+
+		assert.Empty(t, repo.FindByStaffID(ctx, 1))
+		assert.Len(t, repo.FindByStaffID(ctx, 2), 1)
+	})
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 
 	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
 )
 
 type StaffRepository interface {
+	// All returns the staff members of every shop. Within a shop request in row-level
+	// security mode it only sees the current shop.
 	All(ctx context.Context) []entities.Staff
 	FindByID(ctx context.Context, id uint64) (entities.Staff, error)
 	FindByShopID(ctx context.Context, shopID uint64) []entities.Staff
 	FindByUserID(ctx context.Context, userID uint64) []entities.Staff
+	FindMemberships(ctx context.Context, userID uint64, query shoprepositories.ShopQuery) []entities.Staff
 	FindByRoleID(ctx context.Context, roleID uint64) []entities.Staff
+	// LockByRoleID loads the staff members of a role like FindByRoleID and,
+	// within a transaction, locks their rows until the transaction ends, so
+	// that no other transaction changes them in the meantime.
+	LockByRoleID(ctx context.Context, roleID uint64) []entities.Staff
 	FindByShopIDAndUserID(ctx context.Context, shopID uint64, userID uint64) (entities.Staff, error)
 	Create(ctx context.Context, staff entities.Staff) (entities.Staff, error)
 	Update(ctx context.Context, staff entities.Staff) (entities.Staff, error)
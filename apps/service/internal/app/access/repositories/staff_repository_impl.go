@@ -5,8 +5,10 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
 )
 
 type staffRepository struct {
@@ -55,12 +57,36 @@ func (r *staffRepository) FindByUserID(ctx context.Context, userID uint64) []ent
 	return staffs
 }
 
+// FindMemberships returns the staff records of the user in shops that still
+// exist, with the shop and role loaded, filtered and ordered by the shop.
+func (r *staffRepository) FindMemberships(ctx context.Context, userID uint64, query shoprepositories.ShopQuery) []entities.Staff {
+	var staffs []entities.Staff
+	r.db.WithContext(ctx).
+		Joins("JOIN shops ON shops.id = staffs.shop_id AND shops.deleted_at IS NULL").
+		Where("staffs.user_id = ?", userID).
+		Scopes(shoprepositories.ShopQueryScope(query)).
+		Preload("Role").Preload("Shop").
+		Find(&staffs)
+	return staffs
+}
+
 func (r *staffRepository) FindByRoleID(ctx context.Context, roleID uint64) []entities.Staff {
 	var staffs []entities.Staff
 	r.db.WithContext(ctx).Where("role_id = ?", roleID).Find(&staffs)
 	return staffs
 }
 
+func (r *staffRepository) LockByRoleID(ctx context.Context, roleID uint64) []entities.Staff {
+	// Rows are locked in ID order, so concurrent callers cannot deadlock.
+	var staffs []entities.Staff
+	r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("role_id = ?", roleID).
+		Order("id").
+		Find(&staffs)
+	return staffs
+}
+
 func (r *staffRepository) FindByShopIDAndUserID(ctx context.Context, shopID uint64, userID uint64) (entities.Staff, error) {
 	var staff entities.Staff
 	err := r.db.WithContext(ctx).Where("shop_id = ? AND user_id = ?", shopID, userID).First(&staff).Error
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
 	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
@@ -361,6 +362,54 @@ func TestStaffRepository_FindByUserID(t *testing.T) {
 	})
 }
 
+func TestStaffRepository_FindMemberships(t *testing.T) {
+	t.Run("returns the shops of the user with their role", func(t *testing.T) {
+		ctx := context.Background()
+		shopRepo, roleRepo, userRepo, staffRepo := setupStaffTest(t)
+		user := createTestUser(t, ctx, userRepo)
+		other, err := userRepo.Create(ctx, userentities.User{FullName: "Other", Phone: "0987654321", Email: "other@example.com", Password: "hashedpassword"})
+		require.NoError(t, err)
+
+		member := func(name string, userID uint64) shopentities.Shop {
+			shop, err := shopRepo.Create(ctx, shopentities.Shop{Name: name, Phone: "1234567890", Email: "shop@example.com"})
+			require.NoError(t, err)
+			role := createTestRole(t, ctx, roleRepo, shop.ID)
+			_, err = staffRepo.Create(ctx, entities.Staff{UserID: userID, ShopID: shop.ID, RoleID: role.ID})
+			require.NoError(t, err)
+			return shop
+		}
+		member("Zephyr Books", user.ID)
+		member("Acme Hardware", user.ID)
+		member("Someone Else's Shop", other.ID)
+		closed := member("Closed Shop", user.ID)
+		require.NoError(t, shopRepo.Delete(ctx, closed))
+
+		memberships := staffRepo.FindMemberships(ctx, user.ID, repositories.ShopQuery{})
+		require.Len(t, memberships, 2)
+		assert.Equal(t, "Acme Hardware", memberships[0].Shop.Name)
+		assert.Equal(t, "Zephyr Books", memberships[1].Shop.Name)
+		require.NotNil(t, memberships[0].Role)
+		assert.Equal(t, "Test Role", memberships[0].Role.Name)
+
+		memberships = staffRepo.FindMemberships(ctx, user.ID, repositories.ShopQuery{Search: "books"})
+		require.Len(t, memberships, 1)
+		assert.Equal(t, "Zephyr Books", memberships[0].Shop.Name)
+	})
+
+	t.Run("excludes removed memberships", func(t *testing.T) {
+		ctx := context.Background()
+		shopRepo, roleRepo, userRepo, staffRepo := setupStaffTest(t)
+		user := createTestUser(t, ctx, userRepo)
+		shop := createTestShop(t, ctx, shopRepo)
+		role := createTestRole(t, ctx, roleRepo, shop.ID)
+		staff, err := staffRepo.Create(ctx, entities.Staff{UserID: user.ID, ShopID: shop.ID, RoleID: role.ID})
+		require.NoError(t, err)
+		require.NoError(t, staffRepo.Delete(ctx, staff))
+
+		assert.Empty(t, staffRepo.FindMemberships(ctx, user.ID, repositories.ShopQuery{}))
+	})
+}
+
 func TestStaffRepository_FindByRoleID(t *testing.T) {
 	t.Run("returns staffs for a role", func(t *testing.T) {
 		ctx := context.Background()
@@ -439,6 +488,38 @@ func TestStaffRepository_FindByRoleID(t *testing.T) {
 	})
 }
 
+func TestStaffRepository_LockByRoleID(t *testing.T) {
+	t.Run("returns the live staffs of a role within a transaction", func(t *testing.T) {
+		ctx := context.Background()
+		shopRepo, roleRepo, userRepo, staffRepo := setupStaffTest(t)
+		shop := createTestShop(t, ctx, shopRepo)
+		user := createTestUser(t, ctx, userRepo)
+		otherUser, err := userRepo.Create(ctx, userentities.User{
+			FullName: "User Two",
+			Phone:    "0987654321",
+			Email:    "user2@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+		role := createTestRole(t, ctx, roleRepo, shop.ID)
+
+		kept, err := staffRepo.Create(ctx, entities.Staff{UserID: user.ID, RoleID: role.ID, ShopID: shop.ID})
+		require.NoError(t, err)
+		removed, err := staffRepo.Create(ctx, entities.Staff{UserID: otherUser.ID, RoleID: role.ID, ShopID: shop.ID})
+		require.NoError(t, err)
+		require.NoError(t, staffRepo.Delete(ctx, removed))
+
+		var staffs []entities.Staff
+		err = staffRepo.(*staffRepository).db.Transaction(func(tx *gorm.DB) error {
+			staffs = NewStaffRepository(tx).LockByRoleID(ctx, role.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, staffs, 1)
+		assert.Equal(t, kept.ID, staffs[0].ID)
+	})
+}
+
 func TestStaffRepository_FindByShopIDAndUserID(t *testing.T) {
 	t.Run("returns staff when found", func(t *testing.T) {
 		ctx := context.Background()
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	authservices "github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+)
+
+// apiKeyPrefixBytes is the amount of randomness in the public part of a key.
+// It only has to keep prefixes apart; the secret part carries the strength.
+const apiKeyPrefixBytes = 6
+
+// GenerateAPIKey returns a new key in the form wsk_<prefix>_<secret>,
+// together with the prefix it is looked up by and the hash that should be
+// persisted in its place.
+func GenerateAPIKey() (key string, prefix string, hash string, err error) {
+	buf := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	prefix = entities.APIKeyTokenPrefix + hex.EncodeToString(buf)
+
+	secret, _, err := authservices.GenerateOpaqueToken()
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key = prefix + "_" + secret
+	return key, prefix, authservices.HashOpaqueToken(key), nil
+}
+
+// IsAPIKey reports whether token looks like an API key rather than an
+// access token.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, entities.APIKeyTokenPrefix)
+}
+
+// ParseAPIKey returns the prefix of a key produced by GenerateAPIKey. The
+// secret part is random URL-safe base64 and may itself contain underscores,
+// but the hex prefix never does.
+func ParseAPIKey(key string) (string, bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+	random, secret, found := strings.Cut(strings.TrimPrefix(key, entities.APIKeyTokenPrefix), "_")
+	if !found || len(random) != 2*apiKeyPrefixBytes || secret == "" {
+		return "", false
+	}
+	if _, err := hex.DecodeString(random); err != nil {
+		return "", false
+	}
+	return entities.APIKeyTokenPrefix + random, true
+}
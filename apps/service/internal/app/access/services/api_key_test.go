@@ -0,0 +1,59 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authservices "github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	t.Run("returns a key that parses back to its prefix", func(t *testing.T) {
+		key, prefix, hash, err := GenerateAPIKey()
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(key, prefix+"_"))
+		assert.True(t, IsAPIKey(key))
+		assert.Equal(t, authservices.HashOpaqueToken(key), hash)
+
+		parsed, ok := ParseAPIKey(key)
+		require.True(t, ok)
+		assert.Equal(t, prefix, parsed)
+	})
+
+	t.Run("returns distinct keys", func(t *testing.T) {
+		first, firstPrefix, _, err := GenerateAPIKey()
+		require.NoError(t, err)
+		second, secondPrefix, _, err := GenerateAPIKey()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+		assert.NotEqual(t, firstPrefix, secondPrefix)
+	})
+}
+
+func TestParseAPIKey(t *testing.T) {
+	t.Run("keeps underscores of the secret out of the prefix", func(t *testing.T) {
+		prefix, ok := ParseAPIKey("wsk_0123456789ab_se_cr-et")
+		require.True(t, ok)
+		assert.Equal(t, "wsk_0123456789ab", prefix)
+	})
+
+	t.Run("rejects malformed keys", func(t *testing.T) {
+		for _, key := range []string{
+			"",
+			"eyJhbGciOiJIUzI1NiJ9.e30.sig",
+			"wsk_",
+			"wsk_0123456789ab",
+			"wsk_0123456789ab_",
+			"wsk_0123_secret",
+			"wsk_0123456789zz_secret",
+		} {
+			_, ok := ParseAPIKey(key)
+			assert.False(t, ok, key)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+// Authorizer answers whether a user may perform an action in a shop, based
+// on the role of their staff membership there and the permissions granted
+// to the membership directly.
+type Authorizer struct {
+	staffRepository           repositories.StaffRepository
+	rolePermissionRepository  repositories.RolePermissionRepository
+	staffPermissionRepository repositories.StaffPermissionRepository
+}
+
+func NewAuthorizer(staffRepository repositories.StaffRepository, rolePermissionRepository repositories.RolePermissionRepository, staffPermissionRepository repositories.StaffPermissionRepository) *Authorizer {
+	return &Authorizer{
+		staffRepository:           staffRepository,
+		rolePermissionRepository:  rolePermissionRepository,
+		staffPermissionRepository: staffPermissionRepository,
+	}
+}
+
+// Can reports whether userID holds permission in shopID. Users who are not
+// staff of the shop hold no permissions.
+func (a *Authorizer) Can(ctx context.Context, userID uint64, shopID uint64, permission entities.Permission) (bool, error) {
+	staff, err := a.staffRepository.FindByShopIDAndUserID(ctx, shopID, userID)
+	if err != nil {
+		if err.Error() == "staff not found" {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return a.StaffCan(ctx, staff, permission)
+}
+
+// StaffCan reports whether an already loaded staff record holds permission.
+// Suspended staff members hold no permissions.
+func (a *Authorizer) StaffCan(ctx context.Context, staff entities.Staff, permission entities.Permission) (bool, error) {
+	if staff.IsSuspended() {
+		return false, nil
+	}
+	ok, err := a.rolePermissionRepository.HasPermission(ctx, staff.RoleID, permission)
+	if err != nil || ok {
+		return ok, err
+	}
+	return a.staffPermissionRepository.HasPermission(ctx, staff.ID, permission)
+}
+
+// Authorize is Can for callers that only need to stop: it fails with a
+// "forbidden: ..." error unless userID holds permission in shopID.
+func (a *Authorizer) Authorize(ctx context.Context, userID uint64, shopID uint64, permission entities.Permission) error {
+	ok, err := a.Can(ctx, userID, shopID, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("forbidden: missing permission %s", permission)
+	}
+	return nil
+}
+
+// Permissions lists every permission userID holds in shopID.
+func (a *Authorizer) Permissions(ctx context.Context, userID uint64, shopID uint64) ([]entities.Permission, error) {
+	staff, err := a.staffRepository.FindByShopIDAndUserID(ctx, shopID, userID)
+	if err != nil {
+		if err.Error() == "staff not found" {
+			return []entities.Permission{}, nil
+		}
+		return nil, err
+	}
+	if staff.IsSuspended() {
+		return []entities.Permission{}, nil
+	}
+
+	return a.StaffPermissions(ctx, staff), nil
+}
+
+// StaffPermissions lists every permission an already loaded staff record
+// holds, through its role or directly, in catalog order. It does not look at
+// suspension, so it also describes what a suspended staff member would hold
+// once reinstated.
+func (a *Authorizer) StaffPermissions(ctx context.Context, staff entities.Staff) []entities.Permission {
+	held := make(map[entities.Permission]bool)
+	for _, permission := range a.rolePermissionRepository.FindByRoleID(ctx, staff.RoleID) {
+		held[permission] = true
+	}
+	for _, permission := range a.staffPermissionRepository.FindByStaffID(ctx, staff.ID) {
+		held[permission] = true
+	}
+
+	permissions := []entities.Permission{}
+	for _, permission := range entities.AllPermissions() {
+		if held[permission] {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
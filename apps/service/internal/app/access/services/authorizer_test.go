@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupAuthorizerTest(t *testing.T) (*Authorizer, repositories.StaffRepository, repositories.RolePermissionRepository, repositories.StaffPermissionRepository) {
+	db := testutil.SetupTestDB(t, &shopentities.Shop{}, &userentities.User{}, &entities.Role{}, &entities.Staff{}, &entities.RolePermission{}, &entities.StaffPermission{})
+	staffRepo := repositories.NewStaffRepository(db)
+	rolePermissionRepo := repositories.NewRolePermissionRepository(db)
+	staffPermissionRepo := repositories.NewStaffPermissionRepository(db)
+	return NewAuthorizer(staffRepo, rolePermissionRepo, staffPermissionRepo), staffRepo, rolePermissionRepo, staffPermissionRepo
+}
+
+func TestAuthorizer_Can(t *testing.T) {
+	t.Run("grants permissions of the staff role", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, rolePermissionRepo, _ := setupAuthorizerTest(t)
+
+		_, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100})
+		require.NoError(t, err)
+		require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, 100, []entities.Permission{entities.PermissionShopUpdate}))
+
+		ok, err := authorizer.Can(ctx, 1, 10, entities.PermissionShopUpdate)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = authorizer.Can(ctx, 1, 10, entities.PermissionShopDelete)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("denies users outside the shop", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, rolePermissionRepo, _ := setupAuthorizerTest(t)
+
+		_, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100})
+		require.NoError(t, err)
+		require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, 100, []entities.Permission{entities.PermissionShopUpdate}))
+
+		ok, err := authorizer.Can(ctx, 2, 10, entities.PermissionShopUpdate)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = authorizer.Can(ctx, 1, 11, entities.PermissionShopUpdate)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("grants permissions given to the staff member directly", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, _, staffPermissionRepo := setupAuthorizerTest(t)
+
+		staff, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100})
+		require.NoError(t, err)
+		require.NoError(t, staffPermissionRepo.Grant(ctx, staff.ID, entities.PermissionReportsView))
+
+		ok, err := authorizer.Can(ctx, 1, 10, entities.PermissionReportsView)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("denies suspended staff", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, rolePermissionRepo, staffPermissionRepo := setupAuthorizerTest(t)
+
+		staff, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100, Status: entities.StaffStatusSuspended})
+		require.NoError(t, err)
+		require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, 100, []entities.Permission{entities.PermissionShopUpdate}))
+		require.NoError(t, staffPermissionRepo.Grant(ctx, staff.ID, entities.PermissionReportsView))
+
+		ok, err := authorizer.Can(ctx, 1, 10, entities.PermissionShopUpdate)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = authorizer.Can(ctx, 1, 10, entities.PermissionReportsView)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		permissions, err := authorizer.Permissions(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	t.Run("returns forbidden error without the permission", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, _, _ := setupAuthorizerTest(t)
+
+		_, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100})
+		require.NoError(t, err)
+
+		err = authorizer.Authorize(ctx, 1, 10, entities.PermissionShopDelete)
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: missing permission shop.delete", err.Error())
+	})
+}
+
+func TestAuthorizer_Permissions(t *testing.T) {
+	t.Run("lists permissions of the staff role", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, rolePermissionRepo, _ := setupAuthorizerTest(t)
+
+		_, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100})
+		require.NoError(t, err)
+		require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, 100, []entities.Permission{entities.PermissionStaffView, entities.PermissionShopUpdate}))
+
+		permissions, err := authorizer.Permissions(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []entities.Permission{entities.PermissionShopUpdate, entities.PermissionStaffView}, permissions)
+	})
+
+	t.Run("merges role and direct permissions in catalog order", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, staffRepo, rolePermissionRepo, staffPermissionRepo := setupAuthorizerTest(t)
+
+		staff, err := staffRepo.Create(ctx, entities.Staff{UserID: 1, ShopID: 10, RoleID: 100})
+		require.NoError(t, err)
+		require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, 100, []entities.Permission{entities.PermissionSalesCreate, entities.PermissionStaffView}))
+		require.NoError(t, staffPermissionRepo.Grant(ctx, staff.ID, entities.PermissionSalesCreate))
+		require.NoError(t, staffPermissionRepo.Grant(ctx, staff.ID, entities.PermissionShopUpdate))
+
+		permissions, err := authorizer.Permissions(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []entities.Permission{entities.PermissionShopUpdate, entities.PermissionStaffView, entities.PermissionSalesCreate}, permissions)
+	})
+
+	t.Run("returns no permissions for non-members", func(t *testing.T) {
+		ctx := context.Background()
+		authorizer, _, _, _ := setupAuthorizerTest(t)
+
+		permissions, err := authorizer.Permissions(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+}
@@ -2,39 +2,49 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
 	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
 	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
 	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/validationutil"
 )
 
 type AssignStaffUsecase struct {
-	validator       *validator.Validate
-	staffRepository repositories.StaffRepository
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
 }
 
-func NewAssignStaffUsecase(staffRepository repositories.StaffRepository) *AssignStaffUsecase {
+func NewAssignStaffUsecase(db *gorm.DB, authorizer *services.Authorizer) *AssignStaffUsecase {
 	return &AssignStaffUsecase{
-		validator:       validator.New(),
-		staffRepository: staffRepository,
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
 	}
 }
 
 type AssignStaffParam struct {
-	User *userentities.User   `validate:"required"`
-	Shop *shopentities.Shop   `validate:"required"`
-	Role *accessentities.Role `validate:"required"`
+	ActorID uint64               `validate:"required"`
+	User    *userentities.User   `validate:"required"`
+	Shop    *shopentities.Shop   `validate:"required"`
+	Role    *accessentities.Role `validate:"required"`
 }
 
 type AssignStaffResult struct {
 	Staff *accessentities.Staff
 }
 
+// Execute adds the user to the shop with the role. As with grants, the actor
+// must hold every permission of the role, and only an Owner can assign the
+// Owner role.
 func (u *AssignStaffUsecase) Execute(ctx context.Context, params AssignStaffParam) (*AssignStaffResult, error) {
 	if err := u.validator.Struct(params); err != nil {
 		var validationErrors []string
@@ -44,8 +54,29 @@ func (u *AssignStaffUsecase) Execute(ctx context.Context, params AssignStaffPara
 		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
 	}
 
+	db := u.db.WithContext(ctx)
+	staffRepository := repositories.NewStaffRepository(db)
+
+	if params.Role.Name == accessentities.OwnerRoleName {
+		owner, err := actorIsOwner(ctx, db, params.ActorID, params.Shop.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !owner {
+			return nil, errors.New("forbidden: only an Owner can assign the Owner role")
+		}
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, params.ActorID, params.Shop.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureHeld(held, repositories.NewRolePermissionRepository(db).FindByRoleID(ctx, params.Role.ID)); err != nil {
+		return nil, err
+	}
+
 	// Check if staff already exists
-	_, err := u.staffRepository.FindByShopIDAndUserID(ctx, params.Shop.ID, params.User.ID)
+	_, err = staffRepository.FindByShopIDAndUserID(ctx, params.Shop.ID, params.User.ID)
 	if err == nil {
 		return nil, fmt.Errorf("staff already assigned to this shop")
 	}
@@ -54,15 +85,16 @@ func (u *AssignStaffUsecase) Execute(ctx context.Context, params AssignStaffPara
 		UserID: params.User.ID,
 		ShopID: params.Shop.ID,
 		RoleID: params.Role.ID,
+		Status: accessentities.StaffStatusActive,
 	}
 
-	createdStaff, err := u.staffRepository.Create(ctx, staff)
+	createdStaff, err := staffRepository.Create(ctx, staff)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create staff: %w", err)
 	}
 
 	// Reload with relations
-	staffWithRelations, err := u.staffRepository.FindByID(ctx, createdStaff.ID)
+	staffWithRelations, err := staffRepository.FindByID(ctx, createdStaff.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load staff: %w", err)
 	}
@@ -71,3 +103,24 @@ func (u *AssignStaffUsecase) Execute(ctx context.Context, params AssignStaffPara
 		Staff: &staffWithRelations,
 	}, nil
 }
+
+// actorIsOwner reports whether the actor is an active staff member of the
+// shop on its Owner role.
+func actorIsOwner(ctx context.Context, db *gorm.DB, actorID, shopID uint64) (bool, error) {
+	actor, err := repositories.NewStaffRepository(db).FindByShopIDAndUserID(ctx, shopID, actorID)
+	if err != nil {
+		if err.Error() == "staff not found" {
+			return false, nil
+		}
+		return false, err
+	}
+	if actor.IsSuspended() {
+		return false, nil
+	}
+
+	role, err := findShopRole(ctx, repositories.NewRoleRepository(db), shopID, actor.RoleID)
+	if err != nil {
+		return false, err
+	}
+	return role.Name == accessentities.OwnerRoleName, nil
+}
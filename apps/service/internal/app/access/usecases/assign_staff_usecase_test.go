@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,7 @@ import (
 
 	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
 	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
 	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
 	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
 	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
@@ -17,27 +19,49 @@ import (
 )
 
 func setupAssignStaffTest(t *testing.T) (*AssignStaffUsecase, accessrepositories.StaffRepository, shoprepositories.ShopRepository, accessrepositories.RoleRepository, userrepositories.UserRepository) {
-	db := testutil.SetupTestDB(t, &shopentities.Shop{}, &accessentities.Role{}, &userentities.User{}, &accessentities.Staff{})
+	db := testutil.SetupTestDB(t, &shopentities.Shop{}, &accessentities.Role{}, &userentities.User{}, &accessentities.Staff{}, &accessentities.RolePermission{}, &accessentities.StaffPermission{})
 	shopRepo := shoprepositories.NewShopRepository(db)
 	roleRepo := accessrepositories.NewRoleRepository(db)
 	userRepo := userrepositories.NewUserRepository(db)
 	staffRepo := accessrepositories.NewStaffRepository(db)
-	usecase := NewAssignStaffUsecase(staffRepo)
+	authorizer := accessservices.NewAuthorizer(staffRepo, accessrepositories.NewRolePermissionRepository(db), accessrepositories.NewStaffPermissionRepository(db))
+	usecase := NewAssignStaffUsecase(db, authorizer)
 	return usecase, staffRepo, shopRepo, roleRepo, userRepo
 }
 
+// createTestOwner adds an Owner holding every permission to the shop and
+// returns their user ID, to act as the one assigning staff.
+func createTestOwner(t *testing.T, ctx context.Context, usecase *AssignStaffUsecase, shopID uint64) uint64 {
+	role, err := accessrepositories.NewRoleRepository(usecase.db).Create(ctx, accessentities.Role{Name: accessentities.OwnerRoleName, Description: "Owner", ShopID: shopID, IsSystem: true})
+	require.NoError(t, err)
+	require.NoError(t, accessrepositories.NewRolePermissionRepository(usecase.db).ReplaceForRole(ctx, role.ID, accessentities.AllPermissions()))
+
+	user, err := userrepositories.NewUserRepository(usecase.db).Create(ctx, userentities.User{
+		FullName: "Shop Owner",
+		Phone:    fmt.Sprintf("9%09d", shopID),
+		Email:    fmt.Sprintf("owner%d@example.com", shopID),
+		Password: "hashedpassword",
+	})
+	require.NoError(t, err)
+	_, err = accessrepositories.NewStaffRepository(usecase.db).Create(ctx, accessentities.Staff{UserID: user.ID, ShopID: shopID, RoleID: role.ID})
+	require.NoError(t, err)
+	return user.ID
+}
+
 func TestAssignStaffUsecase_Execute(t *testing.T) {
 	t.Run("assigns staff successfully", func(t *testing.T) {
 		ctx := context.Background()
 		usecase, staffRepo, shopRepo, roleRepo, userRepo := setupAssignStaffTest(t)
 		shop := createTestShop(t, ctx, shopRepo)
+		actorID := createTestOwner(t, ctx, usecase, shop.ID)
 		user := createTestUser(t, ctx, userRepo)
 		role := createTestRole(t, ctx, roleRepo, shop.ID)
 
 		result, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: &shop,
-			Role: &role,
+			ActorID: actorID,
+			User:    &user,
+			Shop:    &shop,
+			Role:    &role,
 		})
 		require.NoError(t, err)
 		assert.NotNil(t, result.Staff)
@@ -60,22 +84,25 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 		ctx := context.Background()
 		usecase, _, shopRepo, roleRepo, userRepo := setupAssignStaffTest(t)
 		shop := createTestShop(t, ctx, shopRepo)
+		actorID := createTestOwner(t, ctx, usecase, shop.ID)
 		user := createTestUser(t, ctx, userRepo)
 		role := createTestRole(t, ctx, roleRepo, shop.ID)
 
 		// Assign staff first time
 		_, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: &shop,
-			Role: &role,
+			ActorID: actorID,
+			User:    &user,
+			Shop:    &shop,
+			Role:    &role,
 		})
 		require.NoError(t, err)
 
 		// Try to assign same user to same shop again
 		result, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: &shop,
-			Role: &role,
+			ActorID: actorID,
+			User:    &user,
+			Shop:    &shop,
+			Role:    &role,
 		})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "staff already assigned")
@@ -97,6 +124,8 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 		}
 		createdShop2, err := shopRepo.Create(ctx, shop2)
 		require.NoError(t, err)
+		actorID1 := createTestOwner(t, ctx, usecase, shop1.ID)
+		actorID2 := createTestOwner(t, ctx, usecase, createdShop2.ID)
 
 		user := createTestUser(t, ctx, userRepo)
 		role1 := createTestRole(t, ctx, roleRepo, shop1.ID)
@@ -104,18 +133,20 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 
 		// Assign user to shop1
 		result1, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: &shop1,
-			Role: &role1,
+			ActorID: actorID1,
+			User:    &user,
+			Shop:    &shop1,
+			Role:    &role1,
 		})
 		require.NoError(t, err)
 		assert.NotNil(t, result1.Staff)
 
 		// Assign same user to shop2
 		result2, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: &createdShop2,
-			Role: &role2,
+			ActorID: actorID2,
+			User:    &user,
+			Shop:    &createdShop2,
+			Role:    &role2,
 		})
 		require.NoError(t, err)
 		assert.NotNil(t, result2.Staff)
@@ -126,6 +157,7 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 		ctx := context.Background()
 		usecase, _, shopRepo, roleRepo, userRepo := setupAssignStaffTest(t)
 		shop := createTestShop(t, ctx, shopRepo)
+		actorID := createTestOwner(t, ctx, usecase, shop.ID)
 		user1 := createTestUser(t, ctx, userRepo)
 		user2 := userentities.User{
 			FullName: "Another User",
@@ -139,18 +171,20 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 
 		// Assign user1 to shop
 		result1, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user1,
-			Shop: &shop,
-			Role: &role,
+			ActorID: actorID,
+			User:    &user1,
+			Shop:    &shop,
+			Role:    &role,
 		})
 		require.NoError(t, err)
 		assert.NotNil(t, result1.Staff)
 
 		// Assign user2 to same shop
 		result2, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &createdUser2,
-			Shop: &shop,
-			Role: &role,
+			ActorID: actorID,
+			User:    &createdUser2,
+			Shop:    &shop,
+			Role:    &role,
 		})
 		require.NoError(t, err)
 		assert.NotNil(t, result2.Staff)
@@ -161,12 +195,14 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 		ctx := context.Background()
 		usecase, _, shopRepo, roleRepo, _ := setupAssignStaffTest(t)
 		shop := createTestShop(t, ctx, shopRepo)
+		actorID := createTestOwner(t, ctx, usecase, shop.ID)
 		role := createTestRole(t, ctx, roleRepo, shop.ID)
 
 		result, err := usecase.Execute(ctx, AssignStaffParam{
-			User: nil,
-			Shop: &shop,
-			Role: &role,
+			ActorID: actorID,
+			User:    nil,
+			Shop:    &shop,
+			Role:    &role,
 		})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "validation failed")
@@ -184,9 +220,10 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 		}
 
 		result, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: nil,
-			Role: &role,
+			ActorID: 1,
+			User:    &user,
+			Shop:    nil,
+			Role:    &role,
 		})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "validation failed")
@@ -197,15 +234,99 @@ func TestAssignStaffUsecase_Execute(t *testing.T) {
 		ctx := context.Background()
 		usecase, _, shopRepo, _, userRepo := setupAssignStaffTest(t)
 		shop := createTestShop(t, ctx, shopRepo)
+		actorID := createTestOwner(t, ctx, usecase, shop.ID)
 		user := createTestUser(t, ctx, userRepo)
 
 		result, err := usecase.Execute(ctx, AssignStaffParam{
-			User: &user,
-			Shop: &shop,
-			Role: nil,
+			ActorID: actorID,
+			User:    &user,
+			Shop:    &shop,
+			Role:    nil,
 		})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "validation failed")
 		assert.Nil(t, result)
 	})
+
+	t.Run("forbids a Manager from assigning the Owner role", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewAssignStaffUsecase(fixture.db, fixture.authorizer)
+		user := createTestAssignee(t, ctx, fixture)
+
+		result, err := usecase.Execute(ctx, AssignStaffParam{
+			ActorID: fixture.manager.UserID,
+			User:    &user,
+			Shop:    &fixture.shop,
+			Role:    &fixture.ownerRole,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden: only an Owner can assign the Owner role")
+		assert.Nil(t, result)
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByShopIDAndUserID(ctx, fixture.shop.ID, user.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("forbids assigning a role with permissions the actor lacks", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewAssignStaffUsecase(fixture.db, fixture.authorizer)
+		user := createTestAssignee(t, ctx, fixture)
+
+		role, err := accessrepositories.NewRoleRepository(fixture.db).Create(ctx, accessentities.Role{Name: "Stock Keeper", Description: "Stock Keeper", ShopID: fixture.shop.ID})
+		require.NoError(t, err)
+		require.NoError(t, accessrepositories.NewRolePermissionRepository(fixture.db).ReplaceForRole(ctx, role.ID, []accessentities.Permission{accessentities.PermissionSalesCreate, accessentities.PermissionInventoryAdjust}))
+
+		result, err := usecase.Execute(ctx, AssignStaffParam{
+			ActorID: fixture.manager.UserID,
+			User:    &user,
+			Shop:    &fixture.shop,
+			Role:    &role,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden: cannot grant inventory.adjust")
+		assert.Nil(t, result)
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByShopIDAndUserID(ctx, fixture.shop.ID, user.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("lets a Manager assign a role within their own permissions", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewAssignStaffUsecase(fixture.db, fixture.authorizer)
+		user := createTestAssignee(t, ctx, fixture)
+
+		result, err := usecase.Execute(ctx, AssignStaffParam{
+			ActorID: fixture.manager.UserID,
+			User:    &user,
+			Shop:    &fixture.shop,
+			Role:    &fixture.cashierRole,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.cashierRole.ID, result.Staff.RoleID)
+	})
+
+	t.Run("lets an Owner assign the Owner role", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewAssignStaffUsecase(fixture.db, fixture.authorizer)
+		user := createTestAssignee(t, ctx, fixture)
+
+		result, err := usecase.Execute(ctx, AssignStaffParam{
+			ActorID: fixture.owner.UserID,
+			User:    &user,
+			Shop:    &fixture.shop,
+			Role:    &fixture.ownerRole,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.ownerRole.ID, result.Staff.RoleID)
+	})
+}
+
+func createTestAssignee(t *testing.T, ctx context.Context, fixture *accessFixture) userentities.User {
+	user, err := userrepositories.NewUserRepository(fixture.db).Create(ctx, userentities.User{FullName: "New Hire", Email: "hire@example.com", Phone: "4444444444", Password: "hashedpassword"})
+	require.NoError(t, err)
+	return user
 }
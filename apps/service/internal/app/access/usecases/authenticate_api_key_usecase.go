@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	authservices "github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+// lastUsedResolution limits how often the last use of a key is written, so
+// that a busy integration does not turn every request into a write.
+const lastUsedResolution = time.Minute
+
+type AuthenticateAPIKeyUsecase struct {
+	apiKeyRepository repositories.APIKeyRepository
+	userRepository   userrepositories.UserRepository
+}
+
+func NewAuthenticateAPIKeyUsecase(apiKeyRepository repositories.APIKeyRepository, userRepository userrepositories.UserRepository) *AuthenticateAPIKeyUsecase {
+	return &AuthenticateAPIKeyUsecase{
+		apiKeyRepository: apiKeyRepository,
+		userRepository:   userRepository,
+	}
+}
+
+// Execute resolves a key presented by a client and records its use. Unknown,
+// revoked and expired keys and keys of suspended users all fail with the
+// same "invalid api key" error. Whether the creator is still staff of the
+// shop is left to the membership check of the route.
+func (u *AuthenticateAPIKeyUsecase) Execute(ctx context.Context, key string) (*entities.APIKey, error) {
+	prefix, ok := services.ParseAPIKey(key)
+	if !ok {
+		return nil, errors.New("invalid api key")
+	}
+
+	apiKey, err := u.apiKeyRepository.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, errors.New("invalid api key")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(authservices.HashOpaqueToken(key))) != 1 || !apiKey.IsActive(now) {
+		return nil, errors.New("invalid api key")
+	}
+
+	user, err := u.userRepository.FindByID(ctx, apiKey.UserID)
+	if err != nil || user.IsSuspended() {
+		return nil, errors.New("invalid api key")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		// Failing to record the use is no reason to turn the request away.
+		if err := u.apiKeyRepository.TouchLastUsed(ctx, apiKey.ID, now); err == nil {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return &apiKey, nil
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+func TestAuthenticateAPIKeyUsecase_Execute(t *testing.T) {
+	setup := func(t *testing.T) (*accessFixture, *AuthenticateAPIKeyUsecase, *CreateAPIKeyResult) {
+		fixture := setupAccessTest(t)
+		apiKeyRepo := accessrepositories.NewAPIKeyRepository(fixture.db)
+		created, err := NewCreateAPIKeyUsecase(apiKeyRepo, fixture.authorizer).Execute(context.Background(), CreateAPIKeyParam{
+			ActorID:     fixture.cashier.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "Register",
+			Permissions: []accessentities.Permission{accessentities.PermissionSalesCreate},
+		})
+		require.NoError(t, err)
+		return fixture, NewAuthenticateAPIKeyUsecase(apiKeyRepo, userrepositories.NewUserRepository(fixture.db)), created
+	}
+
+	t.Run("resolves the key and records its use", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, usecase, created := setup(t)
+
+		apiKey, err := usecase.Execute(ctx, created.Key)
+		require.NoError(t, err)
+		assert.Equal(t, created.APIKey.ID, apiKey.ID)
+		assert.Equal(t, fixture.shop.ID, apiKey.ShopID)
+		assert.True(t, apiKey.Allows(accessentities.PermissionSalesCreate))
+		require.NotNil(t, apiKey.LastUsedAt)
+
+		found, err := accessrepositories.NewAPIKeyRepository(fixture.db).FindByID(ctx, apiKey.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found.LastUsedAt)
+	})
+
+	t.Run("rejects unknown and tampered keys", func(t *testing.T) {
+		ctx := context.Background()
+		_, usecase, created := setup(t)
+
+		for _, key := range []string{"not-a-key", created.APIKey.Prefix + "_wrong-secret", "wsk_ffffffffffff_secret"} {
+			_, err := usecase.Execute(ctx, key)
+			assert.Error(t, err)
+			assert.Equal(t, "invalid api key", err.Error())
+		}
+	})
+
+	t.Run("rejects revoked keys", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, usecase, created := setup(t)
+		require.NoError(t, accessrepositories.NewAPIKeyRepository(fixture.db).Revoke(ctx, created.APIKey.ID, time.Now()))
+
+		_, err := usecase.Execute(ctx, created.Key)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid api key", err.Error())
+	})
+
+	t.Run("rejects expired keys", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, usecase, created := setup(t)
+		require.NoError(t, fixture.db.Model(&accessentities.APIKey{}).Where("id = ?", created.APIKey.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		_, err := usecase.Execute(ctx, created.Key)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid api key", err.Error())
+	})
+
+	t.Run("rejects keys of suspended users", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, usecase, created := setup(t)
+		require.NoError(t, fixture.db.Model(&userentities.User{}).Where("id = ?", fixture.cashier.UserID).Update("suspended_at", time.Now()).Error)
+
+		_, err := usecase.Execute(ctx, created.Key)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid api key", err.Error())
+	})
+}
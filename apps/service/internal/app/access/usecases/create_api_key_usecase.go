@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CreateAPIKeyUsecase struct {
+	apiKeyRepository repositories.APIKeyRepository
+	authorizer       *services.Authorizer
+	validator        *validator.Validate
+}
+
+func NewCreateAPIKeyUsecase(apiKeyRepository repositories.APIKeyRepository, authorizer *services.Authorizer) *CreateAPIKeyUsecase {
+	return &CreateAPIKeyUsecase{
+		apiKeyRepository: apiKeyRepository,
+		authorizer:       authorizer,
+		validator:        validator.New(),
+	}
+}
+
+// CreateAPIKeyParam describes a key for ActorID in ShopID. A nil ExpiresAt
+// creates a key that is valid until revoked.
+type CreateAPIKeyParam struct {
+	ActorID     uint64 `validate:"required"`
+	ShopID      uint64 `validate:"required"`
+	Name        string `validate:"required,min=2,max=100"`
+	Permissions []entities.Permission
+	ExpiresAt   *time.Time
+}
+
+// CreateAPIKeyResult carries the only copy of the full key; it cannot be
+// shown again.
+type CreateAPIKeyResult struct {
+	APIKey *entities.APIKey
+	Key    string
+}
+
+// Execute creates a key that acts as the actor in the shop. Like grants to
+// staff members, a key can only be given permissions the actor holds.
+func (u *CreateAPIKeyUsecase) Execute(ctx context.Context, param CreateAPIKeyParam) (*CreateAPIKeyResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	permissions, err := normalizePermissions(param.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) == 0 {
+		return nil, errors.New("validation failed: Permissions is required")
+	}
+	now := time.Now()
+	if param.ExpiresAt != nil && !param.ExpiresAt.After(now) {
+		return nil, errors.New("validation failed: ExpiresAt must be in the future")
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureHeld(held, permissions); err != nil {
+		return nil, err
+	}
+
+	key, prefix, hash, err := services.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := entities.APIKey{
+		ShopID:    param.ShopID,
+		UserID:    param.ActorID,
+		Name:      strings.TrimSpace(param.Name),
+		Prefix:    prefix,
+		KeyHash:   hash,
+		ExpiresAt: param.ExpiresAt,
+	}
+	for _, permission := range permissions {
+		apiKey.Permissions = append(apiKey.Permissions, entities.APIKeyPermission{Permission: permission})
+	}
+
+	created, err := u.apiKeyRepository.Create(ctx, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &CreateAPIKeyResult{
+		APIKey: &created,
+		Key:    key,
+	}, nil
+}
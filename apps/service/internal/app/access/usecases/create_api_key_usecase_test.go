@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestCreateAPIKeyUsecase_Execute(t *testing.T) {
+	t.Run("creates a key acting as the actor", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateAPIKeyUsecase(accessrepositories.NewAPIKeyRepository(fixture.db), fixture.authorizer)
+		expiresAt := time.Now().Add(24 * time.Hour)
+
+		result, err := usecase.Execute(ctx, CreateAPIKeyParam{
+			ActorID:     fixture.manager.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "  Accounting export  ",
+			Permissions: []accessentities.Permission{accessentities.PermissionSalesCreate, accessentities.PermissionStaffAssign, accessentities.PermissionSalesCreate},
+			ExpiresAt:   &expiresAt,
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.Key, result.APIKey.Prefix+"_"))
+		assert.Equal(t, "Accounting export", result.APIKey.Name)
+		assert.Equal(t, fixture.manager.UserID, result.APIKey.UserID)
+
+		found, err := accessrepositories.NewAPIKeyRepository(fixture.db).FindByID(ctx, result.APIKey.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, result.Key, found.KeyHash)
+		assert.Equal(t, []accessentities.Permission{accessentities.PermissionSalesCreate, accessentities.PermissionStaffAssign}, found.PermissionNames())
+		require.NotNil(t, found.ExpiresAt)
+	})
+
+	t.Run("refuses permissions the actor does not hold", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateAPIKeyUsecase(accessrepositories.NewAPIKeyRepository(fixture.db), fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, CreateAPIKeyParam{
+			ActorID:     fixture.cashier.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "Reports",
+			Permissions: []accessentities.Permission{accessentities.PermissionReportsView},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: cannot grant reports.view without holding it", err.Error())
+		assert.Empty(t, accessrepositories.NewAPIKeyRepository(fixture.db).FindByShopID(ctx, fixture.shop.ID))
+	})
+
+	t.Run("validates the key", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateAPIKeyUsecase(accessrepositories.NewAPIKeyRepository(fixture.db), fixture.authorizer)
+		param := CreateAPIKeyParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, Name: "Export"}
+
+		_, err := usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: Permissions is required", err.Error())
+
+		param.Permissions = []accessentities.Permission{"shop.teleport"}
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: unknown permission shop.teleport", err.Error())
+
+		expired := time.Now().Add(-time.Minute)
+		param.Permissions = []accessentities.Permission{accessentities.PermissionReportsView}
+		param.ExpiresAt = &expired
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: ExpiresAt must be in the future", err.Error())
+
+		param.ExpiresAt = nil
+		param.Name = ""
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
+
+func TestRevokeAPIKeyUsecase_Execute(t *testing.T) {
+	t.Run("revokes a key of the shop only", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		apiKeyRepo := accessrepositories.NewAPIKeyRepository(fixture.db)
+		created, err := NewCreateAPIKeyUsecase(apiKeyRepo, fixture.authorizer).Execute(ctx, CreateAPIKeyParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "Export",
+			Permissions: []accessentities.Permission{accessentities.PermissionReportsView},
+		})
+		require.NoError(t, err)
+		usecase := NewRevokeAPIKeyUsecase(apiKeyRepo)
+
+		err = usecase.Execute(ctx, fixture.shop.ID+1, created.APIKey.ID)
+		assert.Error(t, err)
+		assert.Equal(t, "api key not found", err.Error())
+
+		require.NoError(t, usecase.Execute(ctx, fixture.shop.ID, created.APIKey.ID))
+
+		err = usecase.Execute(ctx, fixture.shop.ID, created.APIKey.ID)
+		assert.Error(t, err)
+		assert.Equal(t, "api key already revoked", err.Error())
+	})
+}
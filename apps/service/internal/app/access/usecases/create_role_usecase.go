@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CreateRoleUsecase struct {
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewCreateRoleUsecase(db *gorm.DB, authorizer *services.Authorizer) *CreateRoleUsecase {
+	return &CreateRoleUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
+}
+
+type CreateRoleParam struct {
+	ActorID     uint64 `validate:"required"`
+	ShopID      uint64 `validate:"required"`
+	Name        string `validate:"required,min=2,max=100"`
+	Description string `validate:"omitempty,max=1000"`
+	Permissions []entities.Permission
+}
+
+// RoleInfo is a role together with its permissions and the number of staff
+// members holding it.
+type RoleInfo struct {
+	Role        *entities.Role
+	Permissions []entities.Permission
+	StaffCount  int
+}
+
+type CreateRoleResult struct {
+	Role RoleInfo
+}
+
+// Execute creates a role in the shop. Like grants to single staff members,
+// a role can only be given permissions the actor holds.
+func (u *CreateRoleUsecase) Execute(ctx context.Context, param CreateRoleParam) (*CreateRoleResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	permissions, err := normalizePermissions(param.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureHeld(held, permissions); err != nil {
+		return nil, err
+	}
+
+	var result *CreateRoleResult
+	err = u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRoleRepo := repositories.NewRoleRepository(tx)
+
+		name := strings.TrimSpace(param.Name)
+		if err := ensureRoleNameAvailable(ctx, txRoleRepo, param.ShopID, name, 0); err != nil {
+			return err
+		}
+
+		role, err := txRoleRepo.Create(ctx, entities.Role{
+			Name:        name,
+			Description: strings.TrimSpace(param.Description),
+			ShopID:      param.ShopID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create role: %w", err)
+		}
+
+		if err := repositories.NewRolePermissionRepository(tx).ReplaceForRole(ctx, role.ID, permissions); err != nil {
+			return fmt.Errorf("failed to grant role permissions: %w", err)
+		}
+
+		result = &CreateRoleResult{
+			Role: RoleInfo{Role: &role, Permissions: permissions},
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// normalizePermissions checks every permission against the catalog and
+// returns them without duplicates, in catalog order.
+func normalizePermissions(permissions []entities.Permission) ([]entities.Permission, error) {
+	requested := make(map[entities.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		if !entities.IsKnownPermission(permission) {
+			return nil, fmt.Errorf("validation failed: unknown permission %s", permission)
+		}
+		requested[permission] = true
+	}
+
+	normalized := []entities.Permission{}
+	for _, permission := range entities.AllPermissions() {
+		if requested[permission] {
+			normalized = append(normalized, permission)
+		}
+	}
+	return normalized, nil
+}
+
+// ensureRoleNameAvailable fails when another role of the shop, other than
+// exceptID, already has the name. Names are compared case-insensitively.
+func ensureRoleNameAvailable(ctx context.Context, roleRepository repositories.RoleRepository, shopID uint64, name string, exceptID uint64) error {
+	for _, role := range roleRepository.FindByShopID(ctx, shopID) {
+		if role.ID != exceptID && strings.EqualFold(role.Name, name) {
+			return errors.New("role with this name already exists")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestCreateRoleUsecase_Execute(t *testing.T) {
+	t.Run("creates a role with its permissions", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateRoleUsecase(fixture.db, fixture.authorizer)
+
+		result, err := usecase.Execute(ctx, CreateRoleParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        " Supervisor ",
+			Description: "Looks after the floor",
+			Permissions: []accessentities.Permission{accessentities.PermissionReportsView, accessentities.PermissionSalesCreate, accessentities.PermissionReportsView},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Supervisor", result.Role.Role.Name)
+		assert.False(t, result.Role.Role.IsSystem)
+		assert.Equal(t, []accessentities.Permission{accessentities.PermissionSalesCreate, accessentities.PermissionReportsView}, result.Role.Permissions)
+
+		stored := accessrepositories.NewRolePermissionRepository(fixture.db).FindByRoleID(ctx, result.Role.Role.ID)
+		assert.ElementsMatch(t, result.Role.Permissions, stored)
+	})
+
+	t.Run("returns error when the name is taken", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateRoleUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, CreateRoleParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, Name: "cashier"})
+		assert.Error(t, err)
+		assert.Equal(t, "role with this name already exists", err.Error())
+	})
+
+	t.Run("refuses permissions the actor does not hold", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateRoleUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, CreateRoleParam{
+			ActorID:     fixture.manager.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "Auditor",
+			Permissions: []accessentities.Permission{accessentities.PermissionReportsView},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: cannot grant reports.view without holding it", err.Error())
+	})
+
+	t.Run("validates permissions and name", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewCreateRoleUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, CreateRoleParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "Auditor",
+			Permissions: []accessentities.Permission{"reports.forge"},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: unknown permission reports.forge", err.Error())
+
+		_, err = usecase.Execute(ctx, CreateRoleParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, Name: "A"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type DeleteRoleUsecase struct {
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewDeleteRoleUsecase(db *gorm.DB, authorizer *services.Authorizer) *DeleteRoleUsecase {
+	return &DeleteRoleUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
+}
+
+// DeleteRoleParam deletes a role. Staff members holding it are moved to
+// ReassignToRoleID, which is required while the role is in use.
+type DeleteRoleParam struct {
+	ActorID          uint64 `validate:"required"`
+	ShopID           uint64 `validate:"required"`
+	RoleID           uint64 `validate:"required"`
+	ReassignToRoleID uint64
+}
+
+// Execute deletes a role of the shop. System roles cannot be deleted. Moving
+// staff members to another role is recorded in the access audit log like any
+// other role grant, and needs the actor to hold every permission of it.
+func (u *DeleteRoleUsecase) Execute(ctx context.Context, param DeleteRoleParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.ReassignToRoleID == param.RoleID {
+		return errors.New("validation failed: a role cannot be reassigned to itself")
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return err
+	}
+
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRoleRepo := repositories.NewRoleRepository(tx)
+		txRolePermissionRepo := repositories.NewRolePermissionRepository(tx)
+		txStaffRepo := repositories.NewStaffRepository(tx)
+		txAuditLogRepo := repositories.NewAccessAuditLogRepository(tx)
+
+		role, err := findShopRole(ctx, txRoleRepo, param.ShopID, param.RoleID)
+		if err != nil {
+			return err
+		}
+		if role.IsSystem {
+			return errors.New("forbidden: system roles cannot be deleted")
+		}
+
+		staffs := txStaffRepo.FindByRoleID(ctx, role.ID)
+		if len(staffs) > 0 {
+			if param.ReassignToRoleID == 0 {
+				return fmt.Errorf("role is in use by %d staff, reassign them first", len(staffs))
+			}
+
+			target, err := findShopRole(ctx, txRoleRepo, param.ShopID, param.ReassignToRoleID)
+			if err != nil {
+				return err
+			}
+			if err := ensureHeld(held, txRolePermissionRepo.FindByRoleID(ctx, target.ID)); err != nil {
+				return err
+			}
+
+			for _, staff := range staffs {
+				staff.RoleID = target.ID
+				if _, err := txStaffRepo.Update(ctx, staff); err != nil {
+					return fmt.Errorf("failed to reassign staff: %w", err)
+				}
+
+				_, err := txAuditLogRepo.Create(ctx, entities.AccessAuditLog{
+					ShopID:  param.ShopID,
+					StaffID: staff.ID,
+					ActorID: param.ActorID,
+					Action:  entities.AccessActionGrant,
+					RoleID:  &target.ID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to record access change: %w", err)
+				}
+			}
+		}
+
+		if err := txRolePermissionRepo.ReplaceForRole(ctx, role.ID, nil); err != nil {
+			return fmt.Errorf("failed to revoke role permissions: %w", err)
+		}
+		if err := txRoleRepo.Delete(ctx, role); err != nil {
+			return fmt.Errorf("failed to delete role: %w", err)
+		}
+		return nil
+	})
+}
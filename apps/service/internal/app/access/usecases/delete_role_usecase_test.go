@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestDeleteRoleUsecase_Execute(t *testing.T) {
+	t.Run("deletes an unused role", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		created, err := NewCreateRoleUsecase(fixture.db, fixture.authorizer).Execute(ctx, CreateRoleParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			Name:        "Temp",
+			Permissions: []accessentities.Permission{accessentities.PermissionSalesCreate},
+		})
+		require.NoError(t, err)
+		usecase := NewDeleteRoleUsecase(fixture.db, fixture.authorizer)
+
+		err = usecase.Execute(ctx, DeleteRoleParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, RoleID: created.Role.Role.ID})
+		require.NoError(t, err)
+
+		_, err = accessrepositories.NewRoleRepository(fixture.db).FindByID(ctx, created.Role.Role.ID)
+		assert.Error(t, err)
+		assert.Empty(t, accessrepositories.NewRolePermissionRepository(fixture.db).FindByRoleID(ctx, created.Role.Role.ID))
+	})
+
+	t.Run("refuses to delete a role in use without reassignment", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewDeleteRoleUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, DeleteRoleParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, RoleID: fixture.cashierRole.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "role is in use by 1 staff, reassign them first", err.Error())
+	})
+
+	t.Run("reassigns staff before deleting the role", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewDeleteRoleUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, DeleteRoleParam{
+			ActorID:          fixture.owner.UserID,
+			ShopID:           fixture.shop.ID,
+			RoleID:           fixture.cashierRole.ID,
+			ReassignToRoleID: fixture.managerRole.ID,
+		})
+		require.NoError(t, err)
+
+		staff, err := accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.cashier.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fixture.managerRole.ID, staff.RoleID)
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, &fixture.managerRole.ID, logs[0].RoleID)
+	})
+
+	t.Run("refuses reassignment to a role with permissions the actor does not hold", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewDeleteRoleUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, DeleteRoleParam{
+			ActorID:          fixture.manager.UserID,
+			ShopID:           fixture.shop.ID,
+			RoleID:           fixture.cashierRole.ID,
+			ReassignToRoleID: fixture.ownerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+	})
+
+	t.Run("refuses to delete system roles", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewDeleteRoleUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, DeleteRoleParam{
+			ActorID:          fixture.owner.UserID,
+			ShopID:           fixture.shop.ID,
+			RoleID:           fixture.ownerRole.ID,
+			ReassignToRoleID: fixture.managerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: system roles cannot be deleted", err.Error())
+	})
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+// GetShopRoleUsecase loads a role of a given shop along with its
+// permissions. Roles of other shops are reported as not found.
+type GetShopRoleUsecase struct {
+	roleRepository           repositories.RoleRepository
+	rolePermissionRepository repositories.RolePermissionRepository
+	staffRepository          repositories.StaffRepository
+}
+
+func NewGetShopRoleUsecase(roleRepository repositories.RoleRepository, rolePermissionRepository repositories.RolePermissionRepository, staffRepository repositories.StaffRepository) *GetShopRoleUsecase {
+	return &GetShopRoleUsecase{
+		roleRepository:           roleRepository,
+		rolePermissionRepository: rolePermissionRepository,
+		staffRepository:          staffRepository,
+	}
+}
+
+type GetShopRoleResult struct {
+	Role RoleInfo
+}
+
+func (u *GetShopRoleUsecase) Execute(ctx context.Context, shopID uint64, roleID uint64) (*GetShopRoleResult, error) {
+	role, err := findShopRole(ctx, u.roleRepository, shopID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetShopRoleResult{
+		Role: loadRoleInfo(ctx, u.rolePermissionRepository, u.staffRepository, &role),
+	}, nil
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+)
+
+type GetStaffAccessUsecase struct {
+	staffRepository           repositories.StaffRepository
+	staffPermissionRepository repositories.StaffPermissionRepository
+	staffLocationRepository   repositories.StaffLocationRepository
+	accessAuditLogRepository  repositories.AccessAuditLogRepository
+	authorizer                *services.Authorizer
+}
+
+func NewGetStaffAccessUsecase(staffRepository repositories.StaffRepository, staffPermissionRepository repositories.StaffPermissionRepository, staffLocationRepository repositories.StaffLocationRepository, accessAuditLogRepository repositories.AccessAuditLogRepository, authorizer *services.Authorizer) *GetStaffAccessUsecase {
+	return &GetStaffAccessUsecase{
+		staffRepository:           staffRepository,
+		staffPermissionRepository: staffPermissionRepository,
+		staffLocationRepository:   staffLocationRepository,
+		accessAuditLogRepository:  accessAuditLogRepository,
+		authorizer:                authorizer,
+	}
+}
+
+type GetStaffAccessResult struct {
+	Staff *entities.Staff
+	// Permissions are the ones granted to the staff member directly.
+	Permissions []entities.Permission
+	// EffectivePermissions also include the permissions of the role.
+	EffectivePermissions []entities.Permission
+	History              []entities.AccessAuditLog
+}
+
+func (u *GetStaffAccessUsecase) Execute(ctx context.Context, shopID uint64, staffID uint64) (*GetStaffAccessResult, error) {
+	staff, err := findShopStaff(ctx, u.staffRepository, shopID, staffID)
+	if err != nil {
+		return nil, err
+	}
+	staff.LocationIDs = u.staffLocationRepository.FindByStaffID(ctx, staff.ID)
+
+	return &GetStaffAccessResult{
+		Staff:                &staff,
+		Permissions:          u.staffPermissionRepository.FindByStaffID(ctx, staff.ID),
+		EffectivePermissions: u.authorizer.StaffPermissions(ctx, staff),
+		History:              u.accessAuditLogRepository.FindByStaffID(ctx, staff.ID),
+	}, nil
+}
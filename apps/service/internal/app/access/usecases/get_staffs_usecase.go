@@ -30,8 +30,10 @@ type GetStaffsParam struct {
 }
 
 type StaffInfo struct {
-	User *userentities.User   `json:"user"`
-	Role *accessentities.Role `json:"role"`
+	ID     uint64               `json:"id"`
+	Status string               `json:"status"`
+	User   *userentities.User   `json:"user"`
+	Role   *accessentities.Role `json:"role"`
 }
 
 type GetStaffsResult struct {
@@ -53,8 +55,10 @@ func (u *GetStaffsUsecase) Execute(ctx context.Context, params GetStaffsParam) (
 	staffsResult := make([]StaffInfo, len(staffs))
 	for i, staff := range staffs {
 		staffsResult[i] = StaffInfo{
-			User: staff.User,
-			Role: staff.Role,
+			ID:     staff.ID,
+			Status: staff.Status,
+			User:   staff.User,
+			Role:   staff.Role,
 		}
 	}
 
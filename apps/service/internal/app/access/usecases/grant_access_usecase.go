@@ -1,15 +1,280 @@
 package usecases
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
 )
 
 type GrantAccessUsecase struct {
-	validator       *validator.Validate
-	staffRepository repositories.StaffRepository
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewGrantAccessUsecase(db *gorm.DB, authorizer *services.Authorizer) *GrantAccessUsecase {
+	return &GrantAccessUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
+}
+
+// GrantAccessParam grants either a single permission or a role to a staff
+// member of the shop. Granting a role replaces the staff member's current
+// role.
+type GrantAccessParam struct {
+	ActorID    uint64 `validate:"required"`
+	ShopID     uint64 `validate:"required"`
+	StaffID    uint64 `validate:"required"`
+	Permission entities.Permission
+	RoleID     uint64
+}
+
+type GrantAccessResult struct {
+	Staff       *entities.Staff
+	Permissions []entities.Permission
+}
+
+// Execute grants the access and records it in the audit log. Actors can only
+// hand out permissions they hold themselves, and a role only when they hold
+// every permission of it and of the staff member's current access.
+func (u *GrantAccessUsecase) Execute(ctx context.Context, param GrantAccessParam) (*GrantAccessResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if err := validateAccessTarget(param.Permission, param.RoleID); err != nil {
+		return nil, err
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *GrantAccessResult
+	err = u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStaffRepo := repositories.NewStaffRepository(tx)
+		txStaffPermissionRepo := repositories.NewStaffPermissionRepository(tx)
+
+		staff, err := findShopStaff(ctx, txStaffRepo, param.ShopID, param.StaffID)
+		if err != nil {
+			return err
+		}
+
+		log := entities.AccessAuditLog{
+			ShopID:  param.ShopID,
+			StaffID: staff.ID,
+			ActorID: param.ActorID,
+			Action:  entities.AccessActionGrant,
+		}
+
+		if param.Permission != "" {
+			if err := ensureHeld(held, []entities.Permission{param.Permission}); err != nil {
+				return err
+			}
+
+			granted, err := txStaffPermissionRepo.HasPermission(ctx, staff.ID, param.Permission)
+			if err != nil {
+				return fmt.Errorf("failed to check permission: %w", err)
+			}
+			if granted {
+				return errors.New("permission already granted to this staff")
+			}
+
+			if err := txStaffPermissionRepo.Grant(ctx, staff.ID, param.Permission); err != nil {
+				return fmt.Errorf("failed to grant permission: %w", err)
+			}
+			log.Permission = param.Permission
+		} else {
+			if err := ensureCanManage(ctx, tx, held, param.ActorID, staff); err != nil {
+				return err
+			}
+			role, err := changeRole(ctx, tx, held, staff, param.RoleID)
+			if err != nil {
+				return err
+			}
+			log.RoleID = &role.ID
+		}
+
+		if _, err := repositories.NewAccessAuditLogRepository(tx).Create(ctx, log); err != nil {
+			return fmt.Errorf("failed to record access change: %w", err)
+		}
+
+		updated, err := txStaffRepo.FindByID(ctx, staff.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load staff: %w", err)
+		}
+
+		result = &GrantAccessResult{
+			Staff:       &updated,
+			Permissions: txStaffPermissionRepo.FindByStaffID(ctx, staff.ID),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateAccessTarget checks that exactly one of a permission and a role is
+// given, and that the permission is part of the catalog.
+func validateAccessTarget(permission entities.Permission, roleID uint64) error {
+	if (permission == "") == (roleID == 0) {
+		return errors.New("validation failed: exactly one of permission or role_id is required")
+	}
+	if permission != "" && !entities.IsKnownPermission(permission) {
+		return fmt.Errorf("validation failed: unknown permission %s", permission)
+	}
+	return nil
+}
+
+// actorPermissions returns the set of permissions the actor holds in the
+// shop.
+func actorPermissions(ctx context.Context, authorizer *services.Authorizer, actorID, shopID uint64) (map[entities.Permission]bool, error) {
+	permissions, err := authorizer.Permissions(ctx, actorID, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+
+	held := make(map[entities.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		held[permission] = true
+	}
+	return held, nil
+}
+
+// ensureHeld fails unless every one of permissions is in held, so that
+// nobody can hand out more access than they have.
+func ensureHeld(held map[entities.Permission]bool, permissions []entities.Permission) error {
+	for _, permission := range permissions {
+		if !held[permission] {
+			return fmt.Errorf("forbidden: cannot grant %s without holding it", permission)
+		}
+	}
+	return nil
+}
+
+// findShopStaff loads a staff member by ID, treating staff of other shops as
+// not found.
+func findShopStaff(ctx context.Context, staffRepository repositories.StaffRepository, shopID, staffID uint64) (entities.Staff, error) {
+	staff, err := staffRepository.FindByID(ctx, staffID)
+	if err != nil {
+		return staff, err
+	}
+	if staff.ShopID != shopID {
+		return entities.Staff{}, errors.New("staff not found")
+	}
+	return staff, nil
+}
+
+// findShopRole loads a role by ID, treating roles of other shops as not
+// found.
+func findShopRole(ctx context.Context, roleRepository repositories.RoleRepository, shopID, roleID uint64) (entities.Role, error) {
+	role, err := roleRepository.FindByID(ctx, roleID)
+	if err != nil {
+		return role, err
+	}
+	if role.ShopID != shopID {
+		return entities.Role{}, errors.New("role not found")
+	}
+	return role, nil
+}
+
+// changeRole moves staff to the role with roleID in place of its current
+// one. The actor must hold every permission of the new role, and the last
+// Owner of the shop cannot be moved.
+func changeRole(ctx context.Context, tx *gorm.DB, held map[entities.Permission]bool, staff entities.Staff, roleID uint64) (entities.Role, error) {
+	txStaffRepo := repositories.NewStaffRepository(tx)
+
+	role, err := findShopRole(ctx, repositories.NewRoleRepository(tx), staff.ShopID, roleID)
+	if err != nil {
+		return role, err
+	}
+	if staff.RoleID == role.ID {
+		return role, errors.New("role already granted to this staff")
+	}
+
+	if err := ensureHeld(held, repositories.NewRolePermissionRepository(tx).FindByRoleID(ctx, role.ID)); err != nil {
+		return role, err
+	}
+
+	if err := ensureNotLastOwner(ctx, txStaffRepo, staff, "revoke"); err != nil {
+		return role, err
+	}
+
+	staff.RoleID = role.ID
+	staff.User, staff.Role, staff.Shop = nil, nil, nil
+	if _, err := txStaffRepo.Update(ctx, staff); err != nil {
+		return role, fmt.Errorf("failed to update staff role: %w", err)
+	}
+	return role, nil
+}
+
+// ensureCanManage fails unless the actor may change staff: only an Owner can
+// change an Owner, and nobody can change a staff member who holds
+// permissions they do not hold themselves.
+func ensureCanManage(ctx context.Context, tx *gorm.DB, held map[entities.Permission]bool, actorID uint64, staff entities.Staff) error {
+	if staff.Role != nil && staff.Role.Name == entities.OwnerRoleName {
+		owner, err := actorIsOwner(ctx, tx, actorID, staff.ShopID)
+		if err != nil {
+			return err
+		}
+		if !owner {
+			return errors.New("forbidden: only an Owner can change an Owner")
+		}
+	}
+
+	authorizer := services.NewAuthorizer(repositories.NewStaffRepository(tx), repositories.NewRolePermissionRepository(tx), repositories.NewStaffPermissionRepository(tx))
+	for _, permission := range authorizer.StaffPermissions(ctx, staff) {
+		if !held[permission] {
+			return fmt.Errorf("forbidden: cannot change a staff member holding %s without holding it", permission)
+		}
+	}
+	return nil
+}
+
+// ensureNotLastOwner fails when staff is the only active Owner of its shop,
+// so that doing action to it would leave the shop without one. Suspended
+// Owners do not count, as they cannot act for the shop. It must run in the
+// transaction that makes the change: the Owners stay locked until it ends,
+// so two Owners cannot be taken away at once.
+func ensureNotLastOwner(ctx context.Context, staffRepository repositories.StaffRepository, staff entities.Staff, action string) error {
+	if staff.Role == nil || staff.Role.Name != entities.OwnerRoleName {
+		return nil
+	}
+	for _, other := range staffRepository.LockByRoleID(ctx, staff.RoleID) {
+		if other.ID != staff.ID && !other.IsSuspended() {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot %s the last Owner of the shop", action)
 }
 
-func NewGrantAccessUsecase() *GrantAccessUsecase {
-	return &GrantAccessUsecase{}
+// removeStaff takes staff out of the shop together with the permissions
+// granted to it directly. The staff record is soft deleted, so its access
+// history is kept.
+func removeStaff(ctx context.Context, tx *gorm.DB, staff entities.Staff) error {
+	if err := repositories.NewStaffPermissionRepository(tx).DeleteByStaffID(ctx, staff.ID); err != nil {
+		return fmt.Errorf("failed to revoke permissions: %w", err)
+	}
+	if err := repositories.NewStaffRepository(tx).Delete(ctx, staff); err != nil {
+		return fmt.Errorf("failed to remove staff: %w", err)
+	}
+	return nil
 }
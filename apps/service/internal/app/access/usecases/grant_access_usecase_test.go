@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+// accessFixture is a shop with an Owner, a Manager who may assign staff and
+// ring up sales, and a Cashier who may only ring up sales.
+type accessFixture struct {
+	db          *gorm.DB
+	authorizer  *accessservices.Authorizer
+	shop        shopentities.Shop
+	ownerRole   accessentities.Role
+	managerRole accessentities.Role
+	cashierRole accessentities.Role
+	owner       accessentities.Staff
+	manager     accessentities.Staff
+	cashier     accessentities.Staff
+}
+
+func setupAccessTest(t *testing.T) *accessFixture {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &shopentities.Shop{}, &accessentities.Role{}, &userentities.User{}, &accessentities.Staff{}, &accessentities.RolePermission{}, &accessentities.StaffPermission{}, &accessentities.AccessAuditLog{}, &accessentities.Invitation{}, &accessentities.APIKey{}, &accessentities.APIKeyPermission{})
+	shopRepo := shoprepositories.NewShopRepository(db)
+	roleRepo := accessrepositories.NewRoleRepository(db)
+	userRepo := userrepositories.NewUserRepository(db)
+	staffRepo := accessrepositories.NewStaffRepository(db)
+	rolePermissionRepo := accessrepositories.NewRolePermissionRepository(db)
+
+	fixture := &accessFixture{
+		db:         db,
+		authorizer: accessservices.NewAuthorizer(staffRepo, rolePermissionRepo, accessrepositories.NewStaffPermissionRepository(db)),
+		shop:       createTestShop(t, ctx, shopRepo),
+	}
+
+	createRole := func(name string, permissions ...accessentities.Permission) accessentities.Role {
+		role, err := roleRepo.Create(ctx, accessentities.Role{Name: name, Description: name, ShopID: fixture.shop.ID, IsSystem: name == accessentities.OwnerRoleName})
+		require.NoError(t, err)
+		require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, role.ID, permissions))
+		return role
+	}
+	fixture.ownerRole = createRole(accessentities.OwnerRoleName, accessentities.AllPermissions()...)
+	fixture.managerRole = createRole("Manager", accessentities.PermissionStaffAssign, accessentities.PermissionSalesCreate)
+	fixture.cashierRole = createRole("Cashier", accessentities.PermissionSalesCreate)
+
+	createStaff := func(email, phone string, role accessentities.Role) accessentities.Staff {
+		user, err := userRepo.Create(ctx, userentities.User{FullName: "Test User", Email: email, Phone: phone, Password: "hashedpassword"})
+		require.NoError(t, err)
+		staff, err := staffRepo.Create(ctx, accessentities.Staff{UserID: user.ID, ShopID: fixture.shop.ID, RoleID: role.ID})
+		require.NoError(t, err)
+		return staff
+	}
+	fixture.owner = createStaff("owner@example.com", "1111111111", fixture.ownerRole)
+	fixture.manager = createStaff("manager@example.com", "2222222222", fixture.managerRole)
+	fixture.cashier = createStaff("cashier@example.com", "3333333333", fixture.cashierRole)
+
+	return fixture
+}
+
+func TestGrantAccessUsecase_Execute(t *testing.T) {
+	t.Run("grants a permission and records it", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		result, err := usecase.Execute(ctx, GrantAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionReportsView,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []accessentities.Permission{accessentities.PermissionReportsView}, result.Permissions)
+
+		ok, err := fixture.authorizer.Can(ctx, fixture.cashier.UserID, fixture.shop.ID, accessentities.PermissionReportsView)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, accessentities.AccessActionGrant, logs[0].Action)
+		assert.Equal(t, accessentities.PermissionReportsView, logs[0].Permission)
+		assert.Equal(t, fixture.owner.UserID, logs[0].ActorID)
+		assert.Nil(t, logs[0].RoleID)
+	})
+
+	t.Run("grants a role in place of the current one", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		result, err := usecase.Execute(ctx, GrantAccessParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.managerRole.ID, result.Staff.RoleID)
+		require.NotNil(t, result.Staff.Role)
+		assert.Equal(t, "Manager", result.Staff.Role.Name)
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, &fixture.managerRole.ID, logs[0].RoleID)
+	})
+
+	t.Run("refuses permissions the actor does not hold", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, GrantAccessParam{
+			ActorID:    fixture.manager.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionShopDelete,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: cannot grant shop.delete without holding it", err.Error())
+		assert.Empty(t, accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID))
+	})
+
+	t.Run("refuses roles with permissions the actor does not hold", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, GrantAccessParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.ownerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+	})
+
+	t.Run("refuses to demote the last Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, GrantAccessParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "cannot revoke the last Owner of the shop", err.Error())
+
+		found, err := accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.owner.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fixture.ownerRole.ID, found.RoleID)
+	})
+
+	t.Run("returns error when the permission is already granted", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+		param := GrantAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionReportsView,
+		}
+
+		_, err := usecase.Execute(ctx, param)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "permission already granted to this staff", err.Error())
+	})
+
+	t.Run("returns error for staff of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, GrantAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID + 1,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionReportsView,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "staff not found", err.Error())
+	})
+
+	t.Run("validates the access to grant", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, GrantAccessParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, StaffID: fixture.cashier.ID})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, GrantAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionReportsView,
+			RoleID:     fixture.managerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, GrantAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: "shop.teleport",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: unknown permission shop.teleport", err.Error())
+	})
+}
@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	authservices "github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type InviteStaffUsecase struct {
+	invitationRepository     repositories.InvitationRepository
+	roleRepository           repositories.RoleRepository
+	rolePermissionRepository repositories.RolePermissionRepository
+	staffRepository          repositories.StaffRepository
+	userRepository           userrepositories.UserRepository
+	authorizer               *services.Authorizer
+	mailer                   mail.Mailer
+	smsSender                sms.Sender
+	expiresIn                time.Duration
+	acceptURL                string
+	validator                *validator.Validate
+}
+
+func NewInviteStaffUsecase(invitationRepository repositories.InvitationRepository, roleRepository repositories.RoleRepository, rolePermissionRepository repositories.RolePermissionRepository, staffRepository repositories.StaffRepository, userRepository userrepositories.UserRepository, authorizer *services.Authorizer, mailer mail.Mailer, smsSender sms.Sender, expiresIn time.Duration, acceptURL string) *InviteStaffUsecase {
+	return &InviteStaffUsecase{
+		invitationRepository:     invitationRepository,
+		roleRepository:           roleRepository,
+		rolePermissionRepository: rolePermissionRepository,
+		staffRepository:          staffRepository,
+		userRepository:           userRepository,
+		authorizer:               authorizer,
+		mailer:                   mailer,
+		smsSender:                smsSender,
+		expiresIn:                expiresIn,
+		acceptURL:                acceptURL,
+		validator:                validator.New(),
+	}
+}
+
+// InviteStaffParam invites someone by email or by phone; exactly one of the
+// two must be given.
+type InviteStaffParam struct {
+	ActorID uint64 `validate:"required"`
+	ShopID  uint64 `validate:"required"`
+	Email   string `validate:"omitempty,email"`
+	Phone   string `validate:"omitempty,min=10,max=20"`
+	RoleID  uint64 `validate:"required"`
+}
+
+type InviteStaffResult struct {
+	Invitation *entities.Invitation
+}
+
+// Execute records the invitation and sends its token to the invitee. As with
+// grants, the actor can only invite into a role whose permissions they hold.
+// Invitations to a contact that already belongs to a user are linked to that
+// user straight away.
+func (u *InviteStaffUsecase) Execute(ctx context.Context, param InviteStaffParam) (*InviteStaffResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if (param.Email == "") == (param.Phone == "") {
+		return nil, errors.New("validation failed: exactly one of email or phone is required")
+	}
+	if param.Phone != "" {
+		if err := sms.RequireDelivery(u.smsSender); err != nil {
+			return nil, err
+		}
+	}
+
+	role, err := findShopRole(ctx, u.roleRepository, param.ShopID, param.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureHeld(held, u.rolePermissionRepository.FindByRoleID(ctx, role.ID)); err != nil {
+		return nil, err
+	}
+
+	invitation := entities.Invitation{
+		ShopID:    param.ShopID,
+		RoleID:    role.ID,
+		InviterID: param.ActorID,
+		Email:     param.Email,
+		Phone:     param.Phone,
+		Status:    entities.InvitationStatusPending,
+	}
+
+	user, err := u.findInvitee(ctx, param.Email, param.Phone)
+	if err == nil {
+		if _, err := u.staffRepository.FindByShopIDAndUserID(ctx, param.ShopID, user); err == nil {
+			return nil, errors.New("staff already assigned to this shop")
+		}
+		invitation.UserID = &user
+	}
+
+	now := time.Now()
+	pending, err := u.invitationRepository.HasPending(ctx, param.ShopID, param.Email, param.Phone, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check invitations: %w", err)
+	}
+	if pending {
+		return nil, errors.New("invitation for this contact already exists")
+	}
+
+	token, tokenHash, err := authservices.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = now.Add(u.expiresIn)
+
+	created, err := u.invitationRepository.Create(ctx, invitation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	created.Role = &role
+
+	if err := u.deliver(ctx, created, role, token); err != nil {
+		return nil, fmt.Errorf("failed to send invitation: %w", err)
+	}
+
+	return &InviteStaffResult{
+		Invitation: &created,
+	}, nil
+}
+
+// findInvitee returns the ID of the user the contact belongs to.
+func (u *InviteStaffUsecase) findInvitee(ctx context.Context, email, phone string) (uint64, error) {
+	if email != "" {
+		user, err := u.userRepository.FindByEmail(ctx, email)
+		return user.ID, err
+	}
+	user, err := u.userRepository.FindByPhone(ctx, phone)
+	return user.ID, err
+}
+
+func (u *InviteStaffUsecase) deliver(ctx context.Context, invitation entities.Invitation, role entities.Role, token string) error {
+	link := token
+	if u.acceptURL != "" {
+		link = fmt.Sprintf("%s?token=%s", u.acceptURL, url.QueryEscape(token))
+	}
+
+	if invitation.Email != "" {
+		var body strings.Builder
+		fmt.Fprintf(&body, "Hi,\n\nYou have been invited to join a shop on Weiss as %s.\n\n", role.Name)
+		if u.acceptURL != "" {
+			fmt.Fprintf(&body, "Open the link below to accept or decline the invitation:\n%s\n\n", link)
+		} else {
+			fmt.Fprintf(&body, "Use the following token to accept or decline the invitation:\n%s\n\n", link)
+		}
+		fmt.Fprintf(&body, "If you do not have an account yet, register with this email address first. The invitation expires in %s.\n", u.expiresIn)
+
+		return u.mailer.Send(ctx, mail.Message{
+			To:      invitation.Email,
+			Subject: "You have been invited to join a shop",
+			Body:    body.String(),
+		})
+	}
+
+	return u.smsSender.Send(ctx, sms.Message{
+		To:   invitation.Phone,
+		Body: fmt.Sprintf("You have been invited to join a shop on Weiss as %s: %s", role.Name, link),
+	})
+}
@@ -0,0 +1,168 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+)
+
+func newTestInviteStaffUsecase(fixture *accessFixture, mailer mail.Mailer, smsSender sms.Sender) *InviteStaffUsecase {
+	return NewInviteStaffUsecase(
+		accessrepositories.NewInvitationRepository(fixture.db),
+		accessrepositories.NewRoleRepository(fixture.db),
+		accessrepositories.NewRolePermissionRepository(fixture.db),
+		accessrepositories.NewStaffRepository(fixture.db),
+		userrepositories.NewUserRepository(fixture.db),
+		fixture.authorizer,
+		mailer,
+		smsSender,
+		24*time.Hour,
+		"https://app.example.com/invitations",
+	)
+}
+
+// tokenFromMessage returns the token of the accept link in an invitation.
+func tokenFromMessage(t *testing.T, body string) string {
+	_, token, found := strings.Cut(body, "?token=")
+	require.True(t, found)
+	token, _, _ = strings.Cut(token, "\n")
+	return token
+}
+
+func TestInviteStaffUsecase_Execute(t *testing.T) {
+	t.Run("invites by email and mails the token", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		mailer := mail.NewMemoryMailer()
+		usecase := newTestInviteStaffUsecase(fixture, mailer, sms.NewMemorySender())
+
+		result, err := usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			Email:   "new@example.com",
+			RoleID:  fixture.cashierRole.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, accessentities.InvitationStatusPending, result.Invitation.Status)
+		assert.Nil(t, result.Invitation.UserID)
+
+		message, ok := mailer.LastMessageTo("new@example.com")
+		require.True(t, ok)
+		assert.Contains(t, message.Body, "Cashier")
+		assert.NotEmpty(t, tokenFromMessage(t, message.Body))
+	})
+
+	t.Run("invites by phone and links existing users", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		user, err := userrepositories.NewUserRepository(fixture.db).Create(ctx, userentities.User{FullName: "Jane", Email: "jane@example.com", Phone: "4444444444", Password: "hashedpassword"})
+		require.NoError(t, err)
+		smsSender := sms.NewMemorySender()
+		usecase := newTestInviteStaffUsecase(fixture, mail.NewMemoryMailer(), smsSender)
+
+		result, err := usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			Phone:   "4444444444",
+			RoleID:  fixture.cashierRole.ID,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, result.Invitation.UserID)
+		assert.Equal(t, user.ID, *result.Invitation.UserID)
+
+		_, ok := smsSender.LastMessageTo("4444444444")
+		assert.True(t, ok)
+	})
+
+	t.Run("refuses phone invitations when SMS is not delivered", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := newTestInviteStaffUsecase(fixture, mail.NewMemoryMailer(), sms.NewLogSender(false))
+
+		_, err := usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			Phone:   "4444444444",
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.ErrorIs(t, err, sms.ErrUnavailable)
+	})
+
+	t.Run("refuses roles with permissions the actor does not hold", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := newTestInviteStaffUsecase(fixture, mail.NewMemoryMailer(), sms.NewMemorySender())
+
+		_, err := usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			Email:   "new@example.com",
+			RoleID:  fixture.ownerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+	})
+
+	t.Run("refuses existing staff and duplicate invitations", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := newTestInviteStaffUsecase(fixture, mail.NewMemoryMailer(), sms.NewMemorySender())
+
+		_, err := usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			Email:   "cashier@example.com",
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "staff already assigned to this shop", err.Error())
+
+		param := InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			Email:   "new@example.com",
+			RoleID:  fixture.cashierRole.ID,
+		}
+		_, err = usecase.Execute(ctx, param)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "invitation for this contact already exists", err.Error())
+	})
+
+	t.Run("requires exactly one contact", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := newTestInviteStaffUsecase(fixture, mail.NewMemoryMailer(), sms.NewMemorySender())
+
+		_, err := usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, InviteStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			Email:   "new@example.com",
+			Phone:   "4444444444",
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+type ListAPIKeysUsecase struct {
+	apiKeyRepository repositories.APIKeyRepository
+}
+
+func NewListAPIKeysUsecase(apiKeyRepository repositories.APIKeyRepository) *ListAPIKeysUsecase {
+	return &ListAPIKeysUsecase{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+type ListAPIKeysResult struct {
+	APIKeys []entities.APIKey
+}
+
+// Execute lists every key of the shop, including revoked and expired ones,
+// so that their last use stays visible.
+func (u *ListAPIKeysUsecase) Execute(ctx context.Context, shopID uint64) ListAPIKeysResult {
+	return ListAPIKeysResult{
+		APIKeys: u.apiKeyRepository.FindByShopID(ctx, shopID),
+	}
+}
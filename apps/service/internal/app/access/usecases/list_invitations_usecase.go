@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+type ListInvitationsUsecase struct {
+	invitationRepository repositories.InvitationRepository
+}
+
+func NewListInvitationsUsecase(invitationRepository repositories.InvitationRepository) *ListInvitationsUsecase {
+	return &ListInvitationsUsecase{
+		invitationRepository: invitationRepository,
+	}
+}
+
+type ListInvitationsResult struct {
+	Invitations []entities.Invitation
+}
+
+// Execute lists the pending invitations a shop has sent.
+func (u *ListInvitationsUsecase) Execute(ctx context.Context, shopID uint64) *ListInvitationsResult {
+	return &ListInvitationsResult{
+		Invitations: u.invitationRepository.FindPendingByShopID(ctx, shopID, time.Now()),
+	}
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+type ListRolesUsecase struct {
+	roleRepository           repositories.RoleRepository
+	rolePermissionRepository repositories.RolePermissionRepository
+	staffRepository          repositories.StaffRepository
+}
+
+func NewListRolesUsecase(roleRepository repositories.RoleRepository, rolePermissionRepository repositories.RolePermissionRepository, staffRepository repositories.StaffRepository) *ListRolesUsecase {
+	return &ListRolesUsecase{
+		roleRepository:           roleRepository,
+		rolePermissionRepository: rolePermissionRepository,
+		staffRepository:          staffRepository,
+	}
+}
+
+type ListRolesResult struct {
+	Roles []RoleInfo
+}
+
+func (u *ListRolesUsecase) Execute(ctx context.Context, shopID uint64) (*ListRolesResult, error) {
+	roles := u.roleRepository.FindByShopID(ctx, shopID)
+
+	infos := make([]RoleInfo, len(roles))
+	for i := range roles {
+		infos[i] = loadRoleInfo(ctx, u.rolePermissionRepository, u.staffRepository, &roles[i])
+	}
+
+	return &ListRolesResult{
+		Roles: infos,
+	}, nil
+}
+
+// loadRoleInfo completes a role with its permissions and staff count.
+func loadRoleInfo(ctx context.Context, rolePermissionRepository repositories.RolePermissionRepository, staffRepository repositories.StaffRepository, role *entities.Role) RoleInfo {
+	return RoleInfo{
+		Role:        role,
+		Permissions: rolePermissionRepository.FindByRoleID(ctx, role.ID),
+		StaffCount:  len(staffRepository.FindByRoleID(ctx, role.ID)),
+	}
+}
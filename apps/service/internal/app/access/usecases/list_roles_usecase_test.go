@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestListRolesUsecase_Execute(t *testing.T) {
+	t.Run("lists roles of the shop with permissions and staff count", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewListRolesUsecase(accessrepositories.NewRoleRepository(fixture.db), accessrepositories.NewRolePermissionRepository(fixture.db), accessrepositories.NewStaffRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, fixture.shop.ID)
+		require.NoError(t, err)
+		require.Len(t, result.Roles, 3)
+		assert.Equal(t, accessentities.OwnerRoleName, result.Roles[0].Role.Name)
+		assert.Equal(t, "Cashier", result.Roles[2].Role.Name)
+		assert.Equal(t, []accessentities.Permission{accessentities.PermissionSalesCreate}, result.Roles[2].Permissions)
+		assert.Equal(t, 1, result.Roles[2].StaffCount)
+	})
+
+	t.Run("returns empty list for shops without roles", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewListRolesUsecase(accessrepositories.NewRoleRepository(fixture.db), accessrepositories.NewRolePermissionRepository(fixture.db), accessrepositories.NewStaffRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, fixture.shop.ID+1)
+		require.NoError(t, err)
+		assert.Empty(t, result.Roles)
+	})
+}
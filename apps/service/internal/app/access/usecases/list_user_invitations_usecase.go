@@ -0,0 +1,26 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+type ListUserInvitationsUsecase struct {
+	invitationRepository repositories.InvitationRepository
+}
+
+func NewListUserInvitationsUsecase(invitationRepository repositories.InvitationRepository) *ListUserInvitationsUsecase {
+	return &ListUserInvitationsUsecase{
+		invitationRepository: invitationRepository,
+	}
+}
+
+// Execute lists the pending invitations linked to a user, with the shop and
+// role each one offers.
+func (u *ListUserInvitationsUsecase) Execute(ctx context.Context, userID uint64) *ListInvitationsResult {
+	return &ListInvitationsResult{
+		Invitations: u.invitationRepository.FindPendingByUserID(ctx, userID, time.Now()),
+	}
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type RemoveStaffUsecase struct {
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewRemoveStaffUsecase(db *gorm.DB, authorizer *services.Authorizer) *RemoveStaffUsecase {
+	return &RemoveStaffUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
+}
+
+type RemoveStaffParam struct {
+	ActorID uint64 `validate:"required"`
+	ShopID  uint64 `validate:"required"`
+	StaffID uint64 `validate:"required"`
+}
+
+// Execute removes a staff member from the shop and records the revoked role
+// in the audit log. Only an Owner can remove an Owner, nobody can remove a
+// staff member holding permissions they lack, and the last active Owner of a
+// shop cannot be removed.
+func (u *RemoveStaffUsecase) Execute(ctx context.Context, param RemoveStaffParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return err
+	}
+
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStaffRepo := repositories.NewStaffRepository(tx)
+
+		staff, err := findShopStaff(ctx, txStaffRepo, param.ShopID, param.StaffID)
+		if err != nil {
+			return err
+		}
+		if err := ensureCanManage(ctx, tx, held, param.ActorID, staff); err != nil {
+			return err
+		}
+
+		if err := ensureNotLastOwner(ctx, txStaffRepo, staff, "remove"); err != nil {
+			return err
+		}
+
+		if err := removeStaff(ctx, tx, staff); err != nil {
+			return err
+		}
+
+		if _, err := repositories.NewAccessAuditLogRepository(tx).Create(ctx, entities.AccessAuditLog{
+			ShopID:  param.ShopID,
+			StaffID: staff.ID,
+			ActorID: param.ActorID,
+			Action:  entities.AccessActionRevoke,
+			RoleID:  &staff.RoleID,
+		}); err != nil {
+			return fmt.Errorf("failed to record access change: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestRemoveStaffUsecase_Execute(t *testing.T) {
+	t.Run("removes a staff member and keeps the history", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		staffPermissionRepo := accessrepositories.NewStaffPermissionRepository(fixture.db)
+		require.NoError(t, staffPermissionRepo.Grant(ctx, fixture.cashier.ID, accessentities.PermissionReportsView))
+		usecase := NewRemoveStaffUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RemoveStaffParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, StaffID: fixture.cashier.ID})
+		require.NoError(t, err)
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.cashier.ID)
+		assert.Error(t, err)
+		assert.Empty(t, staffPermissionRepo.FindByStaffID(ctx, fixture.cashier.ID))
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, accessentities.AccessActionRevoke, logs[0].Action)
+		assert.Equal(t, &fixture.cashierRole.ID, logs[0].RoleID)
+	})
+
+	t.Run("refuses to remove the last Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRemoveStaffUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RemoveStaffParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, StaffID: fixture.owner.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "cannot remove the last Owner of the shop", err.Error())
+	})
+
+	t.Run("forbids a non-Owner from removing an Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRemoveStaffUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RemoveStaffParam{ActorID: fixture.manager.UserID, ShopID: fixture.shop.ID, StaffID: fixture.owner.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: only an Owner can change an Owner", err.Error())
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.owner.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("forbids removing a staff member holding permissions the actor lacks", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		require.NoError(t, accessrepositories.NewStaffPermissionRepository(fixture.db).Grant(ctx, fixture.cashier.ID, accessentities.PermissionReportsView))
+		usecase := NewRemoveStaffUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RemoveStaffParam{ActorID: fixture.manager.UserID, ShopID: fixture.shop.ID, StaffID: fixture.cashier.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: cannot change a staff member holding reports.view without holding it", err.Error())
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.cashier.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error for staff of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRemoveStaffUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RemoveStaffParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID + 1, StaffID: fixture.cashier.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "staff not found", err.Error())
+	})
+}
@@ -0,0 +1,148 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	authservices "github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type RespondInvitationUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewRespondInvitationUsecase(db *gorm.DB) *RespondInvitationUsecase {
+	return &RespondInvitationUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+// RespondInvitationParam accepts or declines the invitation a token was
+// sent for, on behalf of the signed in user.
+type RespondInvitationParam struct {
+	UserID uint64 `validate:"required"`
+	Token  string `validate:"required"`
+	Accept bool
+}
+
+type RespondInvitationResult struct {
+	Invitation *entities.Invitation
+	// Staff is the new membership when the invitation was accepted.
+	Staff *entities.Staff
+}
+
+// Execute answers the invitation. Only the user it was sent to, by the
+// contact it names or by being linked to it, can answer it. Accepting adds
+// the user to the shop with the invited role and records the grant in the
+// audit log on behalf of the inviter.
+func (u *RespondInvitationUsecase) Execute(ctx context.Context, param RespondInvitationParam) (*RespondInvitationResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	now := time.Now()
+
+	var result *RespondInvitationResult
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txInvitationRepo := repositories.NewInvitationRepository(tx)
+		txStaffRepo := repositories.NewStaffRepository(tx)
+
+		invitation, err := txInvitationRepo.FindByTokenHash(ctx, authservices.HashOpaqueToken(param.Token))
+		if err != nil || !invitation.IsPending(now) {
+			return errors.New("invalid or expired invitation")
+		}
+
+		user, err := userrepositories.NewUserRepository(tx).FindByID(ctx, param.UserID)
+		if err != nil {
+			return err
+		}
+		if !isInvitee(invitation, user) {
+			return errors.New("forbidden: invitation was sent to someone else")
+		}
+
+		status := entities.InvitationStatusDeclined
+		if param.Accept {
+			status = entities.InvitationStatusAccepted
+		}
+		if err := txInvitationRepo.Respond(ctx, invitation.ID, status, now); err != nil {
+			return err
+		}
+		invitation.Status = status
+		invitation.RespondedAt = &now
+
+		result = &RespondInvitationResult{Invitation: &invitation}
+		if !param.Accept {
+			return nil
+		}
+
+		if _, err := txStaffRepo.FindByShopIDAndUserID(ctx, invitation.ShopID, user.ID); err == nil {
+			return errors.New("staff already assigned to this shop")
+		}
+
+		role, err := findShopRole(ctx, repositories.NewRoleRepository(tx), invitation.ShopID, invitation.RoleID)
+		if err != nil {
+			return err
+		}
+
+		staff, err := txStaffRepo.Create(ctx, entities.Staff{
+			UserID: user.ID,
+			ShopID: invitation.ShopID,
+			RoleID: role.ID,
+			Status: entities.StaffStatusActive,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create staff: %w", err)
+		}
+
+		if _, err := repositories.NewAccessAuditLogRepository(tx).Create(ctx, entities.AccessAuditLog{
+			ShopID:  invitation.ShopID,
+			StaffID: staff.ID,
+			ActorID: invitation.InviterID,
+			Action:  entities.AccessActionGrant,
+			RoleID:  &role.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to record access change: %w", err)
+		}
+
+		created, err := txStaffRepo.FindByID(ctx, staff.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load staff: %w", err)
+		}
+		result.Staff = &created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// isInvitee reports whether the invitation was sent to user.
+func isInvitee(invitation entities.Invitation, user userentities.User) bool {
+	switch {
+	case invitation.UserID != nil:
+		return *invitation.UserID == user.ID
+	case invitation.Email != "":
+		return strings.EqualFold(invitation.Email, user.Email)
+	default:
+		return invitation.Phone == user.Phone
+	}
+}
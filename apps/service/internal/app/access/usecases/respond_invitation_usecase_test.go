@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+)
+
+// inviteTestUser invites email to the fixture shop as a Cashier and returns
+// the token that was mailed.
+func inviteTestUser(t *testing.T, fixture *accessFixture, email string) string {
+	mailer := mail.NewMemoryMailer()
+	_, err := newTestInviteStaffUsecase(fixture, mailer, sms.NewMemorySender()).Execute(context.Background(), InviteStaffParam{
+		ActorID: fixture.owner.UserID,
+		ShopID:  fixture.shop.ID,
+		Email:   email,
+		RoleID:  fixture.cashierRole.ID,
+	})
+	require.NoError(t, err)
+
+	message, ok := mailer.LastMessageTo(email)
+	require.True(t, ok)
+	return tokenFromMessage(t, message.Body)
+}
+
+func createInvitationTestUser(t *testing.T, fixture *accessFixture, email, phone string) userentities.User {
+	user, err := userrepositories.NewUserRepository(fixture.db).Create(context.Background(), userentities.User{FullName: "Invitee", Email: email, Phone: phone, Password: "hashedpassword"})
+	require.NoError(t, err)
+	return user
+}
+
+func TestRespondInvitationUsecase_Execute(t *testing.T) {
+	t.Run("accepting adds the user to the shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		token := inviteTestUser(t, fixture, "new@example.com")
+		user := createInvitationTestUser(t, fixture, "new@example.com", "4444444444")
+		usecase := NewRespondInvitationUsecase(fixture.db)
+
+		result, err := usecase.Execute(ctx, RespondInvitationParam{UserID: user.ID, Token: token, Accept: true})
+		require.NoError(t, err)
+		assert.Equal(t, accessentities.InvitationStatusAccepted, result.Invitation.Status)
+		require.NotNil(t, result.Staff)
+		assert.Equal(t, fixture.cashierRole.ID, result.Staff.RoleID)
+
+		ok, err := fixture.authorizer.Can(ctx, user.ID, fixture.shop.ID, accessentities.PermissionSalesCreate)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, result.Staff.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, fixture.owner.UserID, logs[0].ActorID)
+
+		_, err = usecase.Execute(ctx, RespondInvitationParam{UserID: user.ID, Token: token, Accept: true})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired invitation", err.Error())
+	})
+
+	t.Run("declining leaves the shop unchanged", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		token := inviteTestUser(t, fixture, "new@example.com")
+		user := createInvitationTestUser(t, fixture, "new@example.com", "4444444444")
+		usecase := NewRespondInvitationUsecase(fixture.db)
+
+		result, err := usecase.Execute(ctx, RespondInvitationParam{UserID: user.ID, Token: token})
+		require.NoError(t, err)
+		assert.Equal(t, accessentities.InvitationStatusDeclined, result.Invitation.Status)
+		assert.Nil(t, result.Staff)
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByShopIDAndUserID(ctx, fixture.shop.ID, user.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("refuses users the invitation was not sent to", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		token := inviteTestUser(t, fixture, "new@example.com")
+		usecase := NewRespondInvitationUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, RespondInvitationParam{UserID: fixture.cashier.UserID, Token: token, Accept: true})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: invitation was sent to someone else", err.Error())
+	})
+
+	t.Run("refuses revoked and expired invitations", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		token := inviteTestUser(t, fixture, "new@example.com")
+		user := createInvitationTestUser(t, fixture, "new@example.com", "4444444444")
+		invitationRepo := accessrepositories.NewInvitationRepository(fixture.db)
+		invitations := invitationRepo.FindPendingByShopID(ctx, fixture.shop.ID, time.Now())
+		require.Len(t, invitations, 1)
+		require.NoError(t, NewRevokeInvitationUsecase(invitationRepo).Execute(ctx, fixture.shop.ID, invitations[0].ID))
+		usecase := NewRespondInvitationUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, RespondInvitationParam{UserID: user.ID, Token: token, Accept: true})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired invitation", err.Error())
+
+		require.NoError(t, fixture.db.Model(&accessentities.Invitation{}).Where("id = ?", invitations[0].ID).
+			Updates(map[string]any{"status": accessentities.InvitationStatusPending, "expires_at": time.Now().Add(-time.Minute)}).Error)
+
+		_, err = usecase.Execute(ctx, RespondInvitationParam{UserID: user.ID, Token: token, Accept: true})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired invitation", err.Error())
+	})
+}
@@ -1,15 +1,115 @@
 package usecases
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
 )
 
 type RevokeAccessUsecase struct {
-	validator       *validator.Validate
-	staffRepository repositories.StaffRepository
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewRevokeAccessUsecase(db *gorm.DB, authorizer *services.Authorizer) *RevokeAccessUsecase {
+	return &RevokeAccessUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
 }
 
-func NewRevokeAccessUsecase() *RevokeAccessUsecase {
-	return &RevokeAccessUsecase{}
+// RevokeAccessParam revokes either a permission granted directly to a staff
+// member or the staff member's role. A staff member cannot be without a
+// role, so revoking it removes them from the shop.
+type RevokeAccessParam struct {
+	ActorID    uint64 `validate:"required"`
+	ShopID     uint64 `validate:"required"`
+	StaffID    uint64 `validate:"required"`
+	Permission entities.Permission
+	RoleID     uint64
+}
+
+// Execute revokes the access and records it in the audit log. Revoking a
+// role requires the staff.remove permission, only an Owner can revoke access
+// of an Owner, nobody can revoke access of a staff member holding
+// permissions they lack, and the last Owner of a shop cannot lose the role.
+func (u *RevokeAccessUsecase) Execute(ctx context.Context, param RevokeAccessParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if err := validateAccessTarget(param.Permission, param.RoleID); err != nil {
+		return err
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return err
+	}
+	if param.RoleID != 0 && !held[entities.PermissionStaffRemove] {
+		return fmt.Errorf("forbidden: missing permission %s", entities.PermissionStaffRemove)
+	}
+
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStaffRepo := repositories.NewStaffRepository(tx)
+		txStaffPermissionRepo := repositories.NewStaffPermissionRepository(tx)
+
+		staff, err := findShopStaff(ctx, txStaffRepo, param.ShopID, param.StaffID)
+		if err != nil {
+			return err
+		}
+		if err := ensureCanManage(ctx, tx, held, param.ActorID, staff); err != nil {
+			return err
+		}
+
+		log := entities.AccessAuditLog{
+			ShopID:  param.ShopID,
+			StaffID: staff.ID,
+			ActorID: param.ActorID,
+			Action:  entities.AccessActionRevoke,
+		}
+
+		if param.Permission != "" {
+			err := txStaffPermissionRepo.Revoke(ctx, staff.ID, param.Permission)
+			if err != nil {
+				if err.Error() == "permission not granted" {
+					return errors.New("permission not granted to this staff")
+				}
+				return fmt.Errorf("failed to revoke permission: %w", err)
+			}
+			log.Permission = param.Permission
+		} else {
+			if staff.RoleID != param.RoleID {
+				return errors.New("role not granted to this staff")
+			}
+
+			if err := ensureNotLastOwner(ctx, txStaffRepo, staff, "revoke"); err != nil {
+				return err
+			}
+
+			if err := removeStaff(ctx, tx, staff); err != nil {
+				return err
+			}
+			log.RoleID = &param.RoleID
+		}
+
+		if _, err := repositories.NewAccessAuditLogRepository(tx).Create(ctx, log); err != nil {
+			return fmt.Errorf("failed to record access change: %w", err)
+		}
+		return nil
+	})
 }
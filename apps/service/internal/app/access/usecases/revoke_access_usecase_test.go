@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestRevokeAccessUsecase_Execute(t *testing.T) {
+	t.Run("revokes a permission and records it", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		staffPermissionRepo := accessrepositories.NewStaffPermissionRepository(fixture.db)
+		require.NoError(t, staffPermissionRepo.Grant(ctx, fixture.cashier.ID, accessentities.PermissionReportsView))
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionReportsView,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, staffPermissionRepo.FindByStaffID(ctx, fixture.cashier.ID))
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, accessentities.AccessActionRevoke, logs[0].Action)
+		assert.Equal(t, accessentities.PermissionReportsView, logs[0].Permission)
+	})
+
+	t.Run("returns error when the permission is not granted directly", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID:    fixture.owner.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionSalesCreate,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "permission not granted to this staff", err.Error())
+	})
+
+	t.Run("revoking the role removes the staff member", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		staffPermissionRepo := accessrepositories.NewStaffPermissionRepository(fixture.db)
+		require.NoError(t, staffPermissionRepo.Grant(ctx, fixture.cashier.ID, accessentities.PermissionReportsView))
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		require.NoError(t, err)
+
+		_, err = accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.cashier.ID)
+		assert.Error(t, err)
+		assert.Empty(t, staffPermissionRepo.FindByStaffID(ctx, fixture.cashier.ID))
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, &fixture.cashierRole.ID, logs[0].RoleID)
+	})
+
+	t.Run("revoking a role requires the staff.remove permission", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: missing permission staff.remove", err.Error())
+	})
+
+	t.Run("forbids revoking access of a staff member holding permissions the actor lacks", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		staffPermissionRepo := accessrepositories.NewStaffPermissionRepository(fixture.db)
+		require.NoError(t, staffPermissionRepo.Grant(ctx, fixture.cashier.ID, accessentities.PermissionReportsView))
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID:    fixture.manager.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.cashier.ID,
+			Permission: accessentities.PermissionReportsView,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: cannot change a staff member holding reports.view without holding it", err.Error())
+		assert.Equal(t, []accessentities.Permission{accessentities.PermissionReportsView}, staffPermissionRepo.FindByStaffID(ctx, fixture.cashier.ID))
+	})
+
+	t.Run("forbids a non-Owner from revoking access of an Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		staffPermissionRepo := accessrepositories.NewStaffPermissionRepository(fixture.db)
+		require.NoError(t, staffPermissionRepo.Grant(ctx, fixture.owner.ID, accessentities.PermissionSalesCreate))
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID:    fixture.manager.UserID,
+			ShopID:     fixture.shop.ID,
+			StaffID:    fixture.owner.ID,
+			Permission: accessentities.PermissionSalesCreate,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: only an Owner can change an Owner", err.Error())
+	})
+
+	t.Run("refuses to revoke the last Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			RoleID:  fixture.ownerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "cannot revoke the last Owner of the shop", err.Error())
+	})
+
+	t.Run("revokes an Owner when another remains", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		grant := NewGrantAccessUsecase(fixture.db, fixture.authorizer)
+		_, err := grant.Execute(ctx, GrantAccessParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.manager.ID,
+			RoleID:  fixture.ownerRole.ID,
+		})
+		require.NoError(t, err)
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err = usecase.Execute(ctx, RevokeAccessParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			RoleID:  fixture.ownerRole.ID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("returns error when the role is not the staff member's", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewRevokeAccessUsecase(fixture.db, fixture.authorizer)
+
+		err := usecase.Execute(ctx, RevokeAccessParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "role not granted to this staff", err.Error())
+	})
+}
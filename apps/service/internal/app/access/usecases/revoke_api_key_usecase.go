@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+type RevokeAPIKeyUsecase struct {
+	apiKeyRepository repositories.APIKeyRepository
+}
+
+func NewRevokeAPIKeyUsecase(apiKeyRepository repositories.APIKeyRepository) *RevokeAPIKeyUsecase {
+	return &RevokeAPIKeyUsecase{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+// Execute revokes a key of the shop. It stops working with the next
+// request.
+func (u *RevokeAPIKeyUsecase) Execute(ctx context.Context, shopID, apiKeyID uint64) error {
+	apiKey, err := u.apiKeyRepository.FindByID(ctx, apiKeyID)
+	if err != nil {
+		return err
+	}
+	if apiKey.ShopID != shopID {
+		return errors.New("api key not found")
+	}
+
+	return u.apiKeyRepository.Revoke(ctx, apiKey.ID, time.Now())
+}
@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+type RevokeInvitationUsecase struct {
+	invitationRepository repositories.InvitationRepository
+}
+
+func NewRevokeInvitationUsecase(invitationRepository repositories.InvitationRepository) *RevokeInvitationUsecase {
+	return &RevokeInvitationUsecase{
+		invitationRepository: invitationRepository,
+	}
+}
+
+// Execute withdraws a pending invitation of the shop so its token can no
+// longer be used.
+func (u *RevokeInvitationUsecase) Execute(ctx context.Context, shopID, invitationID uint64) error {
+	invitation, err := u.invitationRepository.FindByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.ShopID != shopID {
+		return errors.New("invitation not found")
+	}
+
+	return u.invitationRepository.Respond(ctx, invitation.ID, entities.InvitationStatusRevoked, time.Now())
+}
@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type SetStaffLocationsUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewSetStaffLocationsUsecase(db *gorm.DB) *SetStaffLocationsUsecase {
+	return &SetStaffLocationsUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+// SetStaffLocationsParam names the locations a staff member works at. An
+// empty list lets them work at every location of the shop.
+type SetStaffLocationsParam struct {
+	ActorID     uint64   `validate:"required"`
+	ShopID      uint64   `validate:"required"`
+	StaffID     uint64   `validate:"required"`
+	LocationIDs []uint64 `validate:"max=100"`
+}
+
+type SetStaffLocationsResult struct {
+	Staff *entities.Staff
+}
+
+// Execute replaces the locations of the staff member. As with permissions,
+// nobody can hand out more than they have: actors who are themselves limited
+// to some locations can only assign those, and cannot lift the limit.
+func (u *SetStaffLocationsUsecase) Execute(ctx context.Context, param SetStaffLocationsParam) (*SetStaffLocationsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	locationIDs := slices.Clone(param.LocationIDs)
+	slices.Sort(locationIDs)
+	locationIDs = slices.Compact(locationIDs)
+
+	var result *SetStaffLocationsResult
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStaffRepo := repositories.NewStaffRepository(tx)
+		txStaffLocationRepo := repositories.NewStaffLocationRepository(tx)
+		txLocationRepo := shoprepositories.NewLocationRepository(tx)
+
+		staff, err := findShopStaff(ctx, txStaffRepo, param.ShopID, param.StaffID)
+		if err != nil {
+			return err
+		}
+
+		actor, err := txStaffRepo.FindByShopIDAndUserID(ctx, param.ShopID, param.ActorID)
+		if err != nil {
+			if err.Error() == "staff not found" {
+				return errors.New("forbidden: not a member of this shop")
+			}
+			return fmt.Errorf("failed to load membership: %w", err)
+		}
+		actor.LocationIDs = txStaffLocationRepo.FindByStaffID(ctx, actor.ID)
+		if len(actor.LocationIDs) > 0 && len(locationIDs) == 0 {
+			return errors.New("forbidden: cannot give access to every location while limited to some")
+		}
+
+		for _, locationID := range locationIDs {
+			location, err := txLocationRepo.FindByID(ctx, locationID)
+			if err != nil {
+				if err.Error() == "location not found" {
+					return err
+				}
+				return fmt.Errorf("failed to get location: %w", err)
+			}
+			if location.ShopID != param.ShopID {
+				return errors.New("location not found")
+			}
+			if !actor.CanAccessLocation(locationID) {
+				return fmt.Errorf("forbidden: cannot assign location %d without working there", locationID)
+			}
+		}
+
+		if err := txStaffLocationRepo.ReplaceForStaff(ctx, staff.ID, locationIDs); err != nil {
+			return fmt.Errorf("failed to assign locations: %w", err)
+		}
+
+		staff.LocationIDs = txStaffLocationRepo.FindByStaffID(ctx, staff.ID)
+		result = &SetStaffLocationsResult{
+			Staff: &staff,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+func setupStaffLocationsTest(t *testing.T) (*accessFixture, []shopentities.Location) {
+	fixture := setupAccessTest(t)
+	require.NoError(t, fixture.db.AutoMigrate(&shopentities.Location{}, &accessentities.StaffLocation{}))
+
+	locations := []shopentities.Location{
+		{ShopID: fixture.shop.ID, Name: "Main"},
+		{ShopID: fixture.shop.ID, Name: "Airport"},
+		{ShopID: fixture.shop.ID + 1, Name: "Elsewhere"},
+	}
+	for i := range locations {
+		require.NoError(t, fixture.db.Create(&locations[i]).Error)
+	}
+	return fixture, locations
+}
+
+func TestSetStaffLocationsUsecase_Execute(t *testing.T) {
+	t.Run("assigns the staff member to locations", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, locations := setupStaffLocationsTest(t)
+		usecase := NewSetStaffLocationsUsecase(fixture.db)
+
+		result, err := usecase.Execute(ctx, SetStaffLocationsParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			StaffID:     fixture.cashier.ID,
+			LocationIDs: []uint64{locations[1].ID, locations[1].ID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{locations[1].ID}, result.Staff.LocationIDs)
+		assert.True(t, result.Staff.CanAccessLocation(locations[1].ID))
+		assert.False(t, result.Staff.CanAccessLocation(locations[0].ID))
+
+		result, err = usecase.Execute(ctx, SetStaffLocationsParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result.Staff.LocationIDs)
+		assert.True(t, result.Staff.CanAccessLocation(locations[0].ID))
+	})
+
+	t.Run("returns error for locations of other shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, locations := setupStaffLocationsTest(t)
+
+		_, err := NewSetStaffLocationsUsecase(fixture.db).Execute(ctx, SetStaffLocationsParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			StaffID:     fixture.cashier.ID,
+			LocationIDs: []uint64{locations[2].ID},
+		})
+		assert.EqualError(t, err, "location not found")
+	})
+
+	t.Run("limited actors only hand out their own locations", func(t *testing.T) {
+		ctx := context.Background()
+		fixture, locations := setupStaffLocationsTest(t)
+		require.NoError(t, accessrepositories.NewStaffLocationRepository(fixture.db).ReplaceForStaff(ctx, fixture.manager.ID, []uint64{locations[0].ID}))
+		usecase := NewSetStaffLocationsUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, SetStaffLocationsParam{
+			ActorID:     fixture.manager.UserID,
+			ShopID:      fixture.shop.ID,
+			StaffID:     fixture.cashier.ID,
+			LocationIDs: []uint64{locations[1].ID},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+
+		_, err = usecase.Execute(ctx, SetStaffLocationsParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+
+		_, err = usecase.Execute(ctx, SetStaffLocationsParam{
+			ActorID:     fixture.manager.UserID,
+			ShopID:      fixture.shop.ID,
+			StaffID:     fixture.cashier.ID,
+			LocationIDs: []uint64{locations[0].ID},
+		})
+		assert.NoError(t, err)
+	})
+}
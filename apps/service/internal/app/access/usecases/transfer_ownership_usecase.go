@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type TransferOwnershipUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewTransferOwnershipUsecase(db *gorm.DB) *TransferOwnershipUsecase {
+	return &TransferOwnershipUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+// TransferOwnershipParam hands the Owner role of the actor to another staff
+// member of the shop, moving the actor to RoleID.
+type TransferOwnershipParam struct {
+	ActorID uint64 `validate:"required"`
+	ShopID  uint64 `validate:"required"`
+	StaffID uint64 `validate:"required"`
+	RoleID  uint64 `validate:"required"`
+}
+
+type TransferOwnershipResult struct {
+	Owner    *entities.Staff
+	Previous *entities.Staff
+}
+
+// Execute swaps the roles in one transaction, so the shop has an Owner at
+// every point, and records both changes in the audit log. Only an Owner can
+// transfer ownership, and only to an active staff member.
+func (u *TransferOwnershipUsecase) Execute(ctx context.Context, param TransferOwnershipParam) (*TransferOwnershipResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	var result *TransferOwnershipResult
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStaffRepo := repositories.NewStaffRepository(tx)
+		txRoleRepo := repositories.NewRoleRepository(tx)
+
+		actor, err := txStaffRepo.FindByShopIDAndUserID(ctx, param.ShopID, param.ActorID)
+		if err != nil {
+			if err.Error() == "staff not found" {
+				return errors.New("forbidden: not a member of this shop")
+			}
+			return err
+		}
+		ownerRole, err := findShopRole(ctx, txRoleRepo, param.ShopID, actor.RoleID)
+		if err != nil {
+			return err
+		}
+		if ownerRole.Name != entities.OwnerRoleName || actor.IsSuspended() {
+			return errors.New("forbidden: only an Owner can transfer ownership")
+		}
+
+		staff, err := findShopStaff(ctx, txStaffRepo, param.ShopID, param.StaffID)
+		if err != nil {
+			return err
+		}
+		if staff.ID == actor.ID {
+			return errors.New("validation failed: cannot transfer ownership to yourself")
+		}
+		if staff.IsSuspended() {
+			return errors.New("cannot transfer ownership to a suspended staff member")
+		}
+
+		newRole, err := findShopRole(ctx, txRoleRepo, param.ShopID, param.RoleID)
+		if err != nil {
+			return err
+		}
+		if newRole.ID == ownerRole.ID {
+			return errors.New("validation failed: role_id must be a role other than Owner")
+		}
+
+		txAuditLogRepo := repositories.NewAccessAuditLogRepository(tx)
+		for _, change := range []struct {
+			staff  entities.Staff
+			roleID uint64
+		}{
+			{staff: staff, roleID: ownerRole.ID},
+			{staff: actor, roleID: newRole.ID},
+		} {
+			if change.staff.RoleID == change.roleID {
+				continue
+			}
+
+			changed := change.staff
+			changed.RoleID = change.roleID
+			changed.User, changed.Role, changed.Shop = nil, nil, nil
+			if _, err := txStaffRepo.Update(ctx, changed); err != nil {
+				return fmt.Errorf("failed to update staff role: %w", err)
+			}
+			if _, err := txAuditLogRepo.Create(ctx, entities.AccessAuditLog{
+				ShopID:  param.ShopID,
+				StaffID: changed.ID,
+				ActorID: param.ActorID,
+				Action:  entities.AccessActionGrant,
+				RoleID:  &change.roleID,
+			}); err != nil {
+				return fmt.Errorf("failed to record access change: %w", err)
+			}
+		}
+
+		owner, err := txStaffRepo.FindByID(ctx, staff.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load staff: %w", err)
+		}
+		previous, err := txStaffRepo.FindByID(ctx, actor.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load staff: %w", err)
+		}
+
+		result = &TransferOwnershipResult{
+			Owner:    &owner,
+			Previous: &previous,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
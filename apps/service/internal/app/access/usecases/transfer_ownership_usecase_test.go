@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestTransferOwnershipUsecase_Execute(t *testing.T) {
+	t.Run("swaps the Owner role", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewTransferOwnershipUsecase(fixture.db)
+
+		result, err := usecase.Execute(ctx, TransferOwnershipParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.manager.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.ownerRole.ID, result.Owner.RoleID)
+		assert.Equal(t, fixture.managerRole.ID, result.Previous.RoleID)
+
+		owners := accessrepositories.NewStaffRepository(fixture.db).FindByRoleID(ctx, fixture.ownerRole.ID)
+		require.Len(t, owners, 1)
+		assert.Equal(t, fixture.manager.ID, owners[0].ID)
+
+		auditLogRepo := accessrepositories.NewAccessAuditLogRepository(fixture.db)
+		assert.Len(t, auditLogRepo.FindByStaffID(ctx, fixture.manager.ID), 1)
+		assert.Len(t, auditLogRepo.FindByStaffID(ctx, fixture.owner.ID), 1)
+	})
+
+	t.Run("only an Owner can transfer ownership", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewTransferOwnershipUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, TransferOwnershipParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: only an Owner can transfer ownership", err.Error())
+	})
+
+	t.Run("refuses suspended staff members", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		_, err := NewUpdateStaffUsecase(fixture.db, fixture.authorizer).Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.manager.ID,
+			Status:  accessentities.StaffStatusSuspended,
+		})
+		require.NoError(t, err)
+		usecase := NewTransferOwnershipUsecase(fixture.db)
+
+		_, err = usecase.Execute(ctx, TransferOwnershipParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.manager.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "cannot transfer ownership to a suspended staff member", err.Error())
+	})
+
+	t.Run("validates the transfer", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewTransferOwnershipUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, TransferOwnershipParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, TransferOwnershipParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.manager.ID,
+			RoleID:  fixture.ownerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
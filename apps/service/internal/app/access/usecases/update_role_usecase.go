@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateRoleUsecase struct {
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewUpdateRoleUsecase(db *gorm.DB, authorizer *services.Authorizer) *UpdateRoleUsecase {
+	return &UpdateRoleUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
+}
+
+// UpdateRoleParam replaces the name, description and permissions of a role.
+type UpdateRoleParam struct {
+	ActorID     uint64 `validate:"required"`
+	ShopID      uint64 `validate:"required"`
+	RoleID      uint64 `validate:"required"`
+	Name        string `validate:"required,min=2,max=100"`
+	Description string `validate:"omitempty,max=1000"`
+	Permissions []entities.Permission
+}
+
+type UpdateRoleResult struct {
+	Role RoleInfo
+}
+
+// Execute updates a role of the shop. System roles cannot be changed, and
+// the actor must hold every permission the role ends up with.
+func (u *UpdateRoleUsecase) Execute(ctx context.Context, param UpdateRoleParam) (*UpdateRoleResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	permissions, err := normalizePermissions(param.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureHeld(held, permissions); err != nil {
+		return nil, err
+	}
+
+	var result *UpdateRoleResult
+	err = u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRoleRepo := repositories.NewRoleRepository(tx)
+		txRolePermissionRepo := repositories.NewRolePermissionRepository(tx)
+		txStaffRepo := repositories.NewStaffRepository(tx)
+
+		role, err := findShopRole(ctx, txRoleRepo, param.ShopID, param.RoleID)
+		if err != nil {
+			return err
+		}
+		if role.IsSystem {
+			return errors.New("forbidden: system roles cannot be changed")
+		}
+
+		name := strings.TrimSpace(param.Name)
+		if err := ensureRoleNameAvailable(ctx, txRoleRepo, param.ShopID, name, role.ID); err != nil {
+			return err
+		}
+
+		role.Name = name
+		role.Description = strings.TrimSpace(param.Description)
+		updated, err := txRoleRepo.Update(ctx, role)
+		if err != nil {
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+
+		if err := txRolePermissionRepo.ReplaceForRole(ctx, role.ID, permissions); err != nil {
+			return fmt.Errorf("failed to update role permissions: %w", err)
+		}
+
+		result = &UpdateRoleResult{
+			Role: loadRoleInfo(ctx, txRolePermissionRepo, txStaffRepo, &updated),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
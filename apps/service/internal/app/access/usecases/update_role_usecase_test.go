@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+func TestUpdateRoleUsecase_Execute(t *testing.T) {
+	t.Run("replaces name, description and permissions", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateRoleUsecase(fixture.db, fixture.authorizer)
+
+		result, err := usecase.Execute(ctx, UpdateRoleParam{
+			ActorID:     fixture.owner.UserID,
+			ShopID:      fixture.shop.ID,
+			RoleID:      fixture.cashierRole.ID,
+			Name:        "Senior Cashier",
+			Description: "Rings up sales and sees reports",
+			Permissions: []accessentities.Permission{accessentities.PermissionSalesCreate, accessentities.PermissionReportsView},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Senior Cashier", result.Role.Role.Name)
+		assert.Equal(t, 1, result.Role.StaffCount)
+
+		ok, err := fixture.authorizer.Can(ctx, fixture.cashier.UserID, fixture.shop.ID, accessentities.PermissionReportsView)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("refuses to change system roles", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateRoleUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateRoleParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			RoleID:  fixture.ownerRole.ID,
+			Name:    "Boss",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: system roles cannot be changed", err.Error())
+	})
+
+	t.Run("returns error when the name is taken by another role", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateRoleUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateRoleParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			RoleID:  fixture.cashierRole.ID,
+			Name:    "Manager",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "role with this name already exists", err.Error())
+	})
+
+	t.Run("returns error for roles of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateRoleUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateRoleParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID + 1,
+			RoleID:  fixture.cashierRole.ID,
+			Name:    "Cashier",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "role not found", err.Error())
+	})
+}
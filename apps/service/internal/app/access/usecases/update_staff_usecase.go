@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateStaffUsecase struct {
+	db         *gorm.DB
+	authorizer *services.Authorizer
+	validator  *validator.Validate
+}
+
+func NewUpdateStaffUsecase(db *gorm.DB, authorizer *services.Authorizer) *UpdateStaffUsecase {
+	return &UpdateStaffUsecase{
+		db:         db,
+		authorizer: authorizer,
+		validator:  validator.New(),
+	}
+}
+
+// UpdateStaffParam changes the role of a staff member, their status, or
+// both. Fields left empty are not changed.
+type UpdateStaffParam struct {
+	ActorID uint64 `validate:"required"`
+	ShopID  uint64 `validate:"required"`
+	StaffID uint64 `validate:"required"`
+	RoleID  uint64
+	Status  string `validate:"omitempty,oneof=active suspended"`
+}
+
+type UpdateStaffResult struct {
+	Staff *entities.Staff
+}
+
+// Execute applies the changes and records each of them in the audit log.
+// Changing the role requires the staff.assign permission and, as with
+// grants, every permission of the new role. Suspending or reinstating
+// requires the staff.remove permission. Only an Owner can change an Owner,
+// nobody can change a staff member holding permissions they lack, and the
+// last active Owner of a shop can be neither moved to another role nor
+// suspended.
+func (u *UpdateStaffUsecase) Execute(ctx context.Context, param UpdateStaffParam) (*UpdateStaffResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.RoleID == 0 && param.Status == "" {
+		return nil, errors.New("validation failed: role_id or status is required")
+	}
+
+	held, err := actorPermissions(ctx, u.authorizer, param.ActorID, param.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if param.RoleID != 0 && !held[entities.PermissionStaffAssign] {
+		return nil, fmt.Errorf("forbidden: missing permission %s", entities.PermissionStaffAssign)
+	}
+	if param.Status != "" && !held[entities.PermissionStaffRemove] {
+		return nil, fmt.Errorf("forbidden: missing permission %s", entities.PermissionStaffRemove)
+	}
+
+	var result *UpdateStaffResult
+	err = u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStaffRepo := repositories.NewStaffRepository(tx)
+		txAuditLogRepo := repositories.NewAccessAuditLogRepository(tx)
+
+		staff, err := findShopStaff(ctx, txStaffRepo, param.ShopID, param.StaffID)
+		if err != nil {
+			return err
+		}
+		if err := ensureCanManage(ctx, tx, held, param.ActorID, staff); err != nil {
+			return err
+		}
+
+		if param.Status != "" && param.Status != staff.Status {
+			log := entities.AccessAuditLog{
+				ShopID:  param.ShopID,
+				StaffID: staff.ID,
+				ActorID: param.ActorID,
+				Action:  entities.AccessActionReinstate,
+			}
+			if param.Status == entities.StaffStatusSuspended {
+				if err := ensureNotLastOwner(ctx, txStaffRepo, staff, "suspend"); err != nil {
+					return err
+				}
+				log.Action = entities.AccessActionSuspend
+			}
+
+			staff.Status = param.Status
+			changed := staff
+			changed.User, changed.Role, changed.Shop = nil, nil, nil
+			if _, err := txStaffRepo.Update(ctx, changed); err != nil {
+				return fmt.Errorf("failed to update staff status: %w", err)
+			}
+			if _, err := txAuditLogRepo.Create(ctx, log); err != nil {
+				return fmt.Errorf("failed to record access change: %w", err)
+			}
+		}
+
+		if param.RoleID != 0 && param.RoleID != staff.RoleID {
+			role, err := changeRole(ctx, tx, held, staff, param.RoleID)
+			if err != nil {
+				return err
+			}
+			if _, err := txAuditLogRepo.Create(ctx, entities.AccessAuditLog{
+				ShopID:  param.ShopID,
+				StaffID: staff.ID,
+				ActorID: param.ActorID,
+				Action:  entities.AccessActionGrant,
+				RoleID:  &role.ID,
+			}); err != nil {
+				return fmt.Errorf("failed to record access change: %w", err)
+			}
+		}
+
+		updated, err := txStaffRepo.FindByID(ctx, staff.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load staff: %w", err)
+		}
+
+		result = &UpdateStaffResult{
+			Staff: &updated,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+)
+
+func TestUpdateStaffUsecase_Execute(t *testing.T) {
+	t.Run("changes the role of a staff member", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		result, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.managerRole.ID, result.Staff.RoleID)
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 1)
+		assert.Equal(t, accessentities.AccessActionGrant, logs[0].Action)
+		assert.Equal(t, &fixture.managerRole.ID, logs[0].RoleID)
+	})
+
+	t.Run("suspends and reinstates a staff member", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		result, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			Status:  accessentities.StaffStatusSuspended,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Staff.IsSuspended())
+		assert.Equal(t, fixture.cashierRole.ID, result.Staff.RoleID)
+
+		ok, err := fixture.authorizer.Can(ctx, fixture.cashier.UserID, fixture.shop.ID, accessentities.PermissionSalesCreate)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		result, err = usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			Status:  accessentities.StaffStatusActive,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Staff.IsSuspended())
+
+		ok, err = fixture.authorizer.Can(ctx, fixture.cashier.UserID, fixture.shop.ID, accessentities.PermissionSalesCreate)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		logs := accessrepositories.NewAccessAuditLogRepository(fixture.db).FindByStaffID(ctx, fixture.cashier.ID)
+		require.Len(t, logs, 2)
+		assert.ElementsMatch(t, []string{accessentities.AccessActionSuspend, accessentities.AccessActionReinstate}, []string{logs[0].Action, logs[1].Action})
+	})
+
+	t.Run("suspending requires the staff.remove permission", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			Status:  accessentities.StaffStatusSuspended,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: missing permission staff.remove", err.Error())
+	})
+
+	t.Run("forbids a non-Owner from changing the role of an Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: only an Owner can change an Owner", err.Error())
+
+		owner, err := accessrepositories.NewStaffRepository(fixture.db).FindByID(ctx, fixture.owner.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fixture.ownerRole.ID, owner.RoleID)
+	})
+
+	t.Run("forbids changing a staff member holding permissions the actor lacks", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		require.NoError(t, accessrepositories.NewStaffPermissionRepository(fixture.db).Grant(ctx, fixture.cashier.ID, accessentities.PermissionReportsView))
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.manager.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.cashier.ID,
+			RoleID:  fixture.managerRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: cannot change a staff member holding reports.view without holding it", err.Error())
+	})
+
+	t.Run("refuses to suspend the last active Owner", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			Status:  accessentities.StaffStatusSuspended,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "cannot suspend the last Owner of the shop", err.Error())
+	})
+
+	t.Run("does not count suspended Owners", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.manager.ID,
+			RoleID:  fixture.ownerRole.ID,
+			Status:  accessentities.StaffStatusSuspended,
+		})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, UpdateStaffParam{
+			ActorID: fixture.owner.UserID,
+			ShopID:  fixture.shop.ID,
+			StaffID: fixture.owner.ID,
+			RoleID:  fixture.cashierRole.ID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "cannot revoke the last Owner of the shop", err.Error())
+	})
+
+	t.Run("validates the changes", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAccessTest(t)
+		usecase := NewUpdateStaffUsecase(fixture.db, fixture.authorizer)
+
+		_, err := usecase.Execute(ctx, UpdateStaffParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, StaffID: fixture.cashier.ID})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, UpdateStaffParam{ActorID: fixture.owner.UserID, ShopID: fixture.shop.ID, StaffID: fixture.cashier.ID, Status: "fired"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
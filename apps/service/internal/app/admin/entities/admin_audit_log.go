@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	AdminActionSuspend     = "suspend"
+	AdminActionRestore     = "restore"
+	AdminActionImpersonate = "impersonate"
+)
+
+const (
+	AdminTargetUser = "user"
+	AdminTargetShop = "shop"
+)
+
+// AdminAuditLog records an action a platform administrator took on a user
+// or a shop, with the reason they gave. Impersonations also record the ID
+// of the token issued for them, which every request made with the token
+// carries.
+type AdminAuditLog struct {
+	ID         uint64    `gorm:"primaryKey;column:id" json:"id"`
+	AdminID    uint64    `gorm:"column:admin_id;not null" json:"admin_id"`
+	Action     string    `gorm:"column:action;not null" json:"action"`
+	TargetType string    `gorm:"column:target_type;not null" json:"target_type"`
+	TargetID   uint64    `gorm:"column:target_id;not null" json:"target_id"`
+	Reason     string    `gorm:"column:reason;not null" json:"reason"`
+	TokenID    string    `gorm:"column:token_id;not null;default:''" json:"token_id,omitempty"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// AdminAuditLogQuery filters and pages the audit log. Logs are listed newest
+// first, and After, when set, resumes the list past the log it points to.
+type AdminAuditLogQuery struct {
+	TargetType string
+	TargetID   uint64
+	After      *pagination.Cursor
+	Limit      int
+}
+
+type AdminAuditLogRepository interface {
+	Find(ctx context.Context, query AdminAuditLogQuery) []entities.AdminAuditLog
+	Create(ctx context.Context, log entities.AdminAuditLog) (entities.AdminAuditLog, error)
+}
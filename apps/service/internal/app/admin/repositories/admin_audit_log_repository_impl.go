@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+)
+
+type adminAuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminAuditLogRepository(db *gorm.DB) AdminAuditLogRepository {
+	return &adminAuditLogRepository{
+		db: db,
+	}
+}
+
+func (r *adminAuditLogRepository) Find(ctx context.Context, query AdminAuditLogQuery) []entities.AdminAuditLog {
+	db := r.db.WithContext(ctx)
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+	if query.TargetID != 0 {
+		db = db.Where("target_id = ?", query.TargetID)
+	}
+	if query.After != nil {
+		db = db.Where("id < ?", query.After.ID)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var logs []entities.AdminAuditLog
+	db.Order("id DESC").Find(&logs)
+	return logs
+}
+
+func (r *adminAuditLogRepository) Create(ctx context.Context, log entities.AdminAuditLog) (entities.AdminAuditLog, error) {
+	err := r.db.WithContext(ctx).Create(&log).Error
+	if err != nil {
+		return log, err
+	}
+	return log, nil
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestAdminAuditLogRepository_Find(t *testing.T) {
+	setup := func(t *testing.T) AdminAuditLogRepository {
+		repo := NewAdminAuditLogRepository(testutil.SetupTestDB(t, &entities.AdminAuditLog{}))
+		for _, log := range []entities.AdminAuditLog{
+			{AdminID: 1, Action: entities.AdminActionSuspend, TargetType: entities.AdminTargetUser, TargetID: 2, Reason: "Chargeback fraud"},
+			{AdminID: 1, Action: entities.AdminActionSuspend, TargetType: entities.AdminTargetShop, TargetID: 2, Reason: "Unpaid invoices"},
+			{AdminID: 1, Action: entities.AdminActionImpersonate, TargetType: entities.AdminTargetUser, TargetID: 3, Reason: "Ticket 42", TokenID: "jti"},
+		} {
+			_, err := repo.Create(context.Background(), log)
+			require.NoError(t, err)
+		}
+		return repo
+	}
+
+	t.Run("returns logs newest first", func(t *testing.T) {
+		logs := setup(t).Find(context.Background(), AdminAuditLogQuery{})
+		require.Len(t, logs, 3)
+		assert.Equal(t, entities.AdminActionImpersonate, logs[0].Action)
+		assert.Equal(t, "jti", logs[0].TokenID)
+		assert.Equal(t, "Chargeback fraud", logs[2].Reason)
+	})
+
+	t.Run("filters by target", func(t *testing.T) {
+		logs := setup(t).Find(context.Background(), AdminAuditLogQuery{TargetType: entities.AdminTargetUser, TargetID: 2})
+		require.Len(t, logs, 1)
+		assert.Equal(t, "Chargeback fraud", logs[0].Reason)
+	})
+
+	t.Run("pages with a cursor", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setup(t)
+
+		first := repo.Find(ctx, AdminAuditLogQuery{Limit: 2})
+		require.Len(t, first, 2)
+
+		rest := repo.Find(ctx, AdminAuditLogQuery{After: &pagination.Cursor{ID: first[1].ID}})
+		require.Len(t, rest, 1)
+		assert.Equal(t, "Chargeback fraud", rest[0].Reason)
+	})
+}
@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+func newTestTokenService(t *testing.T) *services.TokenService {
+	tokenService, err := services.NewTokenService(&config.Config{
+		JwtSecret:         "test-secret-key-minimum-32-characters-long",
+		JwtIssuer:         "test-issuer",
+		JwtAccessExpires:  "15m",
+		JwtRefreshExpires: "168h",
+	})
+	require.NoError(t, err)
+	return tokenService
+}
+
+func TestImpersonateUserUsecase_Execute(t *testing.T) {
+	t.Run("issues a token for the user on behalf of the admin", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		tokenService := newTestTokenService(t)
+		usecase := NewImpersonateUserUsecase(fixture.userRepo, fixture.logRepo, tokenService)
+
+		result, err := usecase.Execute(ctx, ImpersonateUserParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Reason: "Ticket 4521"})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.user.ID, result.User.ID)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), result.ExpiresAt, 5*time.Second)
+
+		claims, err := tokenService.VerifyAccessToken(result.AccessToken)
+		require.NoError(t, err)
+		userID, err := tokenService.GetUserID(claims)
+		require.NoError(t, err)
+		assert.Equal(t, fixture.user.ID, userID)
+		assert.Equal(t, fixture.admin.ID, claims.ImpersonatorID)
+
+		logs := fixture.logRepo.Find(ctx, repositories.AdminAuditLogQuery{})
+		require.Len(t, logs, 1)
+		assert.Equal(t, entities.AdminActionImpersonate, logs[0].Action)
+		assert.Equal(t, fixture.user.ID, logs[0].TargetID)
+		assert.Equal(t, claims.ID, logs[0].TokenID)
+		assert.Equal(t, "Ticket 4521", logs[0].Reason)
+	})
+
+	t.Run("refuses admins, suspended users and the caller", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewImpersonateUserUsecase(fixture.userRepo, fixture.logRepo, newTestTokenService(t))
+
+		_, err := usecase.Execute(ctx, ImpersonateUserParam{AdminID: fixture.admin.ID, UserID: fixture.admin.ID, Reason: "Ticket 4521"})
+		assert.EqualError(t, err, "forbidden: cannot impersonate yourself")
+
+		other := fixture.admin
+		other.ID = 0
+		other.Email = "other-admin@example.com"
+		other.Phone = "3333333333"
+		other, err = fixture.userRepo.Create(ctx, other)
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, ImpersonateUserParam{AdminID: fixture.admin.ID, UserID: other.ID, Reason: "Ticket 4521"})
+		assert.EqualError(t, err, "forbidden: cannot impersonate a platform administrator")
+
+		suspendedAt := time.Now()
+		fixture.user.SuspendedAt = &suspendedAt
+		_, err = fixture.userRepo.Update(ctx, fixture.user)
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, ImpersonateUserParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Reason: "Ticket 4521"})
+		assert.EqualError(t, err, "cannot impersonate a suspended user")
+
+		_, err = usecase.Execute(ctx, ImpersonateUserParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID})
+		assert.EqualError(t, err, "validation failed: Reason is required")
+
+		assert.Empty(t, fixture.logRepo.Find(ctx, repositories.AdminAuditLogQuery{}))
+	})
+}
@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListAuditLogsUsecase struct {
+	adminAuditLogRepository repositories.AdminAuditLogRepository
+	validator               *validator.Validate
+}
+
+func NewListAuditLogsUsecase(adminAuditLogRepository repositories.AdminAuditLogRepository) *ListAuditLogsUsecase {
+	return &ListAuditLogsUsecase{
+		adminAuditLogRepository: adminAuditLogRepository,
+		validator:               validator.New(),
+	}
+}
+
+type ListAuditLogsParam struct {
+	TargetType string `validate:"omitempty,oneof=user shop"`
+	TargetID   uint64
+	Cursor     string
+	Limit      int
+}
+
+type ListAuditLogsResult struct {
+	Logs       []entities.AdminAuditLog
+	NextCursor string
+}
+
+// Execute lists the actions administrators took, newest first, one page at
+// a time. NextCursor is empty on the last page.
+func (u *ListAuditLogsUsecase) Execute(ctx context.Context, param ListAuditLogsParam) (*ListAuditLogsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	after, err := decodeIDCursor(param.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := pagination.Limit(param.Limit)
+	logs := u.adminAuditLogRepository.Find(ctx, repositories.AdminAuditLogQuery{
+		TargetType: param.TargetType,
+		TargetID:   param.TargetID,
+		After:      after,
+		Limit:      limit + 1,
+	})
+
+	result := &ListAuditLogsResult{Logs: logs}
+	if len(logs) > limit {
+		result.Logs = logs[:limit]
+		result.NextCursor = idCursor(result.Logs[limit-1].ID)
+	}
+	return result, nil
+}
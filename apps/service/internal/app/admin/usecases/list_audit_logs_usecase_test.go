@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+)
+
+func TestListAuditLogsUsecase_Execute(t *testing.T) {
+	t.Run("lists and filters logs newest first", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewListAuditLogsUsecase(fixture.logRepo)
+		for _, log := range []entities.AdminAuditLog{
+			{AdminID: fixture.admin.ID, Action: entities.AdminActionSuspend, TargetType: entities.AdminTargetUser, TargetID: fixture.user.ID, Reason: "First"},
+			{AdminID: fixture.admin.ID, Action: entities.AdminActionSuspend, TargetType: entities.AdminTargetShop, TargetID: 1, Reason: "Second"},
+			{AdminID: fixture.admin.ID, Action: entities.AdminActionRestore, TargetType: entities.AdminTargetUser, TargetID: fixture.user.ID, Reason: "Third"},
+		} {
+			_, err := fixture.logRepo.Create(ctx, log)
+			require.NoError(t, err)
+		}
+
+		first, err := usecase.Execute(ctx, ListAuditLogsParam{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, first.Logs, 2)
+		assert.Equal(t, "Third", first.Logs[0].Reason)
+		require.NotEmpty(t, first.NextCursor)
+
+		rest, err := usecase.Execute(ctx, ListAuditLogsParam{Limit: 2, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, rest.Logs, 1)
+		assert.Equal(t, "First", rest.Logs[0].Reason)
+		assert.Empty(t, rest.NextCursor)
+
+		users, err := usecase.Execute(ctx, ListAuditLogsParam{TargetType: entities.AdminTargetUser})
+		require.NoError(t, err)
+		assert.Len(t, users.Logs, 2)
+	})
+
+	t.Run("validates the target type", func(t *testing.T) {
+		fixture := setupAdminTest(t)
+		usecase := NewListAuditLogsUsecase(fixture.logRepo)
+
+		_, err := usecase.Execute(context.Background(), ListAuditLogsParam{TargetType: "planet"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type SearchUsersUsecase struct {
+	userRepository userrepositories.UserRepository
+	validator      *validator.Validate
+}
+
+func NewSearchUsersUsecase(userRepository userrepositories.UserRepository) *SearchUsersUsecase {
+	return &SearchUsersUsecase{
+		userRepository: userRepository,
+		validator:      validator.New(),
+	}
+}
+
+type SearchUsersParam struct {
+	Search string `validate:"omitempty,max=100"`
+	Cursor string
+	Limit  int
+}
+
+type SearchUsersResult struct {
+	Users      []userentities.User
+	NextCursor string
+}
+
+// Execute lists the users matching the search, one page at a time.
+// NextCursor is empty on the last page.
+func (u *SearchUsersUsecase) Execute(ctx context.Context, param SearchUsersParam) (*SearchUsersResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	after, err := decodeIDCursor(param.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := pagination.Limit(param.Limit)
+	users := u.userRepository.Search(ctx, userrepositories.UserQuery{
+		Search: param.Search,
+		After:  after,
+		Limit:  limit + 1,
+	})
+
+	result := &SearchUsersResult{Users: users}
+	if len(users) > limit {
+		result.Users = users[:limit]
+		result.NextCursor = idCursor(result.Users[limit-1].ID)
+	}
+	return result, nil
+}
+
+// Lists ordered by ID alone use cursors sorted by "id".
+const idCursorSort = "id"
+
+func idCursor(id uint64) string {
+	return pagination.Cursor{Sort: idCursorSort, ID: id}.Encode()
+}
+
+func decodeIDCursor(value string) (*pagination.Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	cursor, err := pagination.Decode(value)
+	if err != nil || cursor.Sort != idCursorSort {
+		return nil, errors.New("validation failed: invalid cursor")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+)
+
+func TestSearchUsersUsecase_Execute(t *testing.T) {
+	t.Run("searches users", func(t *testing.T) {
+		fixture := setupAdminTest(t)
+		usecase := NewSearchUsersUsecase(fixture.userRepo)
+
+		result, err := usecase.Execute(context.Background(), SearchUsersParam{Search: "customer@"})
+		require.NoError(t, err)
+		require.Len(t, result.Users, 1)
+		assert.Equal(t, fixture.user.ID, result.Users[0].ID)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("pages through the users", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewSearchUsersUsecase(fixture.userRepo)
+		for i := 0; i < 3; i++ {
+			_, err := fixture.userRepo.Create(ctx, userentities.User{FullName: "Extra", Email: fmt.Sprintf("extra%d@example.com", i), Phone: fmt.Sprintf("555000000%d", i), Password: "hashedpassword"})
+			require.NoError(t, err)
+		}
+
+		var seen int
+		param := SearchUsersParam{Limit: 2}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 3)
+			result, err := usecase.Execute(ctx, param)
+			require.NoError(t, err)
+			seen += len(result.Users)
+			if result.NextCursor == "" {
+				break
+			}
+			param.Cursor = result.NextCursor
+		}
+		assert.Equal(t, 5, seen)
+	})
+
+	t.Run("rejects invalid cursors", func(t *testing.T) {
+		fixture := setupAdminTest(t)
+		usecase := NewSearchUsersUsecase(fixture.userRepo)
+
+		_, err := usecase.Execute(context.Background(), SearchUsersParam{Cursor: "garbage"})
+		assert.EqualError(t, err, "validation failed: invalid cursor")
+	})
+}
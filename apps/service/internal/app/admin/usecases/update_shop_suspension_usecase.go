@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateShopSuspensionUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewUpdateShopSuspensionUsecase(db *gorm.DB) *UpdateShopSuspensionUsecase {
+	return &UpdateShopSuspensionUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type UpdateShopSuspensionParam struct {
+	AdminID uint64 `validate:"required"`
+	ShopID  uint64 `validate:"required"`
+	Suspend bool
+	Reason  string `validate:"required,min=3,max=500"`
+}
+
+type UpdateShopSuspensionResult struct {
+	Shop *shopentities.Shop
+}
+
+// Execute suspends or restores a shop and records it in the admin audit
+// log. A suspended shop is closed to all of its staff.
+func (u *UpdateShopSuspensionUsecase) Execute(ctx context.Context, param UpdateShopSuspensionParam) (*UpdateShopSuspensionResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	now := time.Now()
+
+	var result *UpdateShopSuspensionResult
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txShopRepo := shoprepositories.NewShopRepository(tx)
+
+		shop, err := txShopRepo.FindByID(ctx, param.ShopID)
+		if err != nil {
+			return err
+		}
+
+		action := entities.AdminActionRestore
+		if param.Suspend {
+			if shop.IsSuspended() {
+				return errors.New("shop is already suspended")
+			}
+			action = entities.AdminActionSuspend
+			shop.SuspendedAt = &now
+		} else {
+			if !shop.IsSuspended() {
+				return errors.New("shop is not suspended")
+			}
+			shop.SuspendedAt = nil
+		}
+
+		shop, err = txShopRepo.Update(ctx, shop)
+		if err != nil {
+			return fmt.Errorf("failed to update shop: %w", err)
+		}
+
+		_, err = repositories.NewAdminAuditLogRepository(tx).Create(ctx, entities.AdminAuditLog{
+			AdminID:    param.AdminID,
+			Action:     action,
+			TargetType: entities.AdminTargetShop,
+			TargetID:   shop.ID,
+			Reason:     strings.TrimSpace(param.Reason),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit log: %w", err)
+		}
+
+		result = &UpdateShopSuspensionResult{Shop: &shop}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
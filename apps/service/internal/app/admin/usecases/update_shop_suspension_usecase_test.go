@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+)
+
+func TestUpdateShopSuspensionUsecase_Execute(t *testing.T) {
+	t.Run("suspends and restores the shop and records both", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewUpdateShopSuspensionUsecase(fixture.db)
+		shopRepo := shoprepositories.NewShopRepository(fixture.db)
+
+		shop, err := shopRepo.Create(ctx, shopentities.Shop{Name: "Corner Shop", Phone: "1234567890", Email: "shop@example.com"})
+		require.NoError(t, err)
+
+		result, err := usecase.Execute(ctx, UpdateShopSuspensionParam{AdminID: fixture.admin.ID, ShopID: shop.ID, Suspend: true, Reason: "Unpaid invoices"})
+		require.NoError(t, err)
+		assert.True(t, result.Shop.IsSuspended())
+		assert.Equal(t, "Corner Shop", result.Shop.Name)
+
+		_, err = usecase.Execute(ctx, UpdateShopSuspensionParam{AdminID: fixture.admin.ID, ShopID: shop.ID, Suspend: true, Reason: "Unpaid invoices"})
+		assert.EqualError(t, err, "shop is already suspended")
+
+		result, err = usecase.Execute(ctx, UpdateShopSuspensionParam{AdminID: fixture.admin.ID, ShopID: shop.ID, Reason: "Invoices paid"})
+		require.NoError(t, err)
+		assert.False(t, result.Shop.IsSuspended())
+
+		found, err := shopRepo.FindByID(ctx, shop.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsSuspended())
+
+		logs := fixture.logRepo.Find(ctx, repositories.AdminAuditLogQuery{TargetType: entities.AdminTargetShop, TargetID: shop.ID})
+		require.Len(t, logs, 2)
+		assert.Equal(t, entities.AdminActionRestore, logs[0].Action)
+		assert.Equal(t, entities.AdminActionSuspend, logs[1].Action)
+	})
+
+	t.Run("returns errors for unknown or active shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewUpdateShopSuspensionUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, UpdateShopSuspensionParam{AdminID: fixture.admin.ID, ShopID: 999, Suspend: true, Reason: "Unpaid invoices"})
+		assert.EqualError(t, err, "shop not found")
+
+		shop, err := shoprepositories.NewShopRepository(fixture.db).Create(ctx, shopentities.Shop{Name: "Corner Shop", Phone: "1234567890", Email: "shop@example.com"})
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, UpdateShopSuspensionParam{AdminID: fixture.admin.ID, ShopID: shop.ID, Reason: "Invoices paid"})
+		assert.EqualError(t, err, "shop is not suspended")
+		assert.Empty(t, fixture.logRepo.Find(ctx, repositories.AdminAuditLogQuery{}))
+	})
+}
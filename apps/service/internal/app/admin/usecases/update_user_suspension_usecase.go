@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateUserSuspensionUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewUpdateUserSuspensionUsecase(db *gorm.DB) *UpdateUserSuspensionUsecase {
+	return &UpdateUserSuspensionUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type UpdateUserSuspensionParam struct {
+	AdminID uint64 `validate:"required"`
+	UserID  uint64 `validate:"required"`
+	Suspend bool
+	Reason  string `validate:"required,min=3,max=500"`
+}
+
+type UpdateUserSuspensionResult struct {
+	User *userentities.User
+}
+
+// Execute suspends or restores a user and records it in the admin audit
+// log. Suspending also signs the user out of every device, and the auth
+// middleware refuses the access tokens already issued from then on.
+func (u *UpdateUserSuspensionUsecase) Execute(ctx context.Context, param UpdateUserSuspensionParam) (*UpdateUserSuspensionResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	if param.Suspend && param.AdminID == param.UserID {
+		return nil, errors.New("forbidden: cannot suspend yourself")
+	}
+
+	now := time.Now()
+
+	var result *UpdateUserSuspensionResult
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txUserRepo := userrepositories.NewUserRepository(tx)
+
+		user, err := txUserRepo.FindByID(ctx, param.UserID)
+		if err != nil {
+			return err
+		}
+
+		action := entities.AdminActionRestore
+		if param.Suspend {
+			if user.IsSuspended() {
+				return errors.New("user is already suspended")
+			}
+			action = entities.AdminActionSuspend
+			user.SuspendedAt = &now
+		} else {
+			if !user.IsSuspended() {
+				return errors.New("user is not suspended")
+			}
+			user.SuspendedAt = nil
+		}
+
+		user, err = txUserRepo.Update(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		if param.Suspend {
+			if err := authrepositories.NewSessionRepository(tx).RevokeAllByUserID(ctx, user.ID, now); err != nil {
+				return fmt.Errorf("failed to revoke sessions: %w", err)
+			}
+		}
+
+		_, err = repositories.NewAdminAuditLogRepository(tx).Create(ctx, entities.AdminAuditLog{
+			AdminID:    param.AdminID,
+			Action:     action,
+			TargetType: entities.AdminTargetUser,
+			TargetID:   user.ID,
+			Reason:     strings.TrimSpace(param.Reason),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit log: %w", err)
+		}
+
+		result = &UpdateUserSuspensionResult{User: &user}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
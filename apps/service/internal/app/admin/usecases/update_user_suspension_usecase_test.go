@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+// adminFixture is a platform administrator and a regular user.
+type adminFixture struct {
+	db       *gorm.DB
+	userRepo userrepositories.UserRepository
+	logRepo  repositories.AdminAuditLogRepository
+	admin    userentities.User
+	user     userentities.User
+}
+
+func setupAdminTest(t *testing.T) *adminFixture {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &userentities.User{}, &shopentities.Shop{}, &authentities.Session{}, &entities.AdminAuditLog{})
+	userRepo := userrepositories.NewUserRepository(db)
+
+	admin, err := userRepo.Create(ctx, userentities.User{FullName: "Admin", Email: "admin@example.com", Phone: "1111111111", Password: "hashedpassword", IsAdmin: true})
+	require.NoError(t, err)
+	user, err := userRepo.Create(ctx, userentities.User{FullName: "Customer", Email: "customer@example.com", Phone: "2222222222", Password: "hashedpassword"})
+	require.NoError(t, err)
+
+	return &adminFixture{
+		db:       db,
+		userRepo: userRepo,
+		logRepo:  repositories.NewAdminAuditLogRepository(db),
+		admin:    admin,
+		user:     user,
+	}
+}
+
+func TestUpdateUserSuspensionUsecase_Execute(t *testing.T) {
+	t.Run("suspends the user, signs them out and records it", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewUpdateUserSuspensionUsecase(fixture.db)
+
+		sessionRepo := authrepositories.NewSessionRepository(fixture.db)
+		_, err := sessionRepo.Create(ctx, authentities.Session{UserID: fixture.user.ID, TokenID: "jti", ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: time.Now()})
+		require.NoError(t, err)
+
+		result, err := usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Suspend: true, Reason: "Chargeback fraud"})
+		require.NoError(t, err)
+		assert.True(t, result.User.IsSuspended())
+		assert.Empty(t, sessionRepo.FindActiveByUserID(ctx, fixture.user.ID))
+
+		logs := fixture.logRepo.Find(ctx, repositories.AdminAuditLogQuery{})
+		require.Len(t, logs, 1)
+		assert.Equal(t, entities.AdminActionSuspend, logs[0].Action)
+		assert.Equal(t, entities.AdminTargetUser, logs[0].TargetType)
+		assert.Equal(t, fixture.user.ID, logs[0].TargetID)
+		assert.Equal(t, fixture.admin.ID, logs[0].AdminID)
+		assert.Equal(t, "Chargeback fraud", logs[0].Reason)
+	})
+
+	t.Run("restores a suspended user", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewUpdateUserSuspensionUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Suspend: true, Reason: "Chargeback fraud"})
+		require.NoError(t, err)
+
+		result, err := usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Reason: "Dispute resolved"})
+		require.NoError(t, err)
+		assert.False(t, result.User.IsSuspended())
+
+		found, err := fixture.userRepo.FindByID(ctx, fixture.user.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsSuspended())
+
+		logs := fixture.logRepo.Find(ctx, repositories.AdminAuditLogQuery{})
+		require.Len(t, logs, 2)
+		assert.Equal(t, entities.AdminActionRestore, logs[0].Action)
+	})
+
+	t.Run("returns errors for invalid transitions", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupAdminTest(t)
+		usecase := NewUpdateUserSuspensionUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Reason: "Just because"})
+		assert.EqualError(t, err, "user is not suspended")
+
+		_, err = usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Suspend: true, Reason: "Chargeback fraud"})
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Suspend: true, Reason: "Chargeback fraud"})
+		assert.EqualError(t, err, "user is already suspended")
+
+		_, err = usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.admin.ID, Suspend: true, Reason: "Oops"})
+		assert.EqualError(t, err, "forbidden: cannot suspend yourself")
+
+		_, err = usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: 999, Suspend: true, Reason: "Chargeback fraud"})
+		assert.EqualError(t, err, "user not found")
+
+		_, err = usecase.Execute(ctx, UpdateUserSuspensionParam{AdminID: fixture.admin.ID, UserID: fixture.user.ID, Suspend: true})
+		assert.EqualError(t, err, "validation failed: Reason is required")
+	})
+}
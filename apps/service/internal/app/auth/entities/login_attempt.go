@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+)
+
+// LoginAttempt counts recent failed logins for a subject, such as an email
+// address or a client IP, and records how long the subject is locked out.
+type LoginAttempt struct {
+	Subject      string     `gorm:"primaryKey;column:subject" json:"subject"`
+	Failures     int        `gorm:"column:failures;not null;default:0" json:"failures"`
+	LastFailedAt time.Time  `gorm:"column:last_failed_at;not null" json:"last_failed_at"`
+	LockedUntil  *time.Time `gorm:"column:locked_until" json:"locked_until"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked reports whether the subject is still locked out at the given time.
+func (a LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
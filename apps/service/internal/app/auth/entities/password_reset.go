@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+)
+
+// PasswordReset is a single-use password reset token. Only the SHA-256 hash of
+// the token is stored.
+type PasswordReset struct {
+	ID        uint64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID    uint64     `gorm:"column:user_id;not null;index:idx_password_resets_user_id" json:"user_id"`
+	TokenHash string     `gorm:"column:token_hash;not null;uniqueIndex:idx_password_resets_token_hash" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (PasswordReset) TableName() string {
+	return "password_resets"
+}
+
+// IsUsable reports whether the token has not been used and has not expired.
+func (p PasswordReset) IsUsable(now time.Time) bool {
+	return p.UsedAt == nil && now.Before(p.ExpiresAt)
+}
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+)
+
+// Principal is the authenticated caller of a request, resolved from a verified access token.
+// ImpersonatorID is set when a platform administrator is acting as the user.
+// APIKey is set when the caller authenticated with an API key instead; the
+// caller is then the user who created the key.
+type Principal struct {
+	UserID         uint64
+	Email          string
+	Phone          string
+	TokenID        string
+	ImpersonatorID uint64
+	APIKey         *accessentities.APIKey
+}
+
+// IsImpersonated reports whether an administrator is acting as the user.
+func (p Principal) IsImpersonated() bool {
+	return p.ImpersonatorID != 0
+}
+
+// UsesAPIKey reports whether the request was authenticated with an API key.
+func (p Principal) UsesAPIKey() bool {
+	return p.APIKey != nil
+}
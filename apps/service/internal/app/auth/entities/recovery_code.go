@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+)
+
+// RecoveryCode is a single-use backup code for logging in without the
+// authenticator app. Only the hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID    uint64     `gorm:"column:user_id;not null;index:idx_recovery_codes_user_id" json:"user_id"`
+	CodeHash  string     `gorm:"column:code_hash;not null;uniqueIndex:idx_recovery_codes_code_hash" json:"-"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
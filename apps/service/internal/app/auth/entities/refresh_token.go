@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+)
+
+// RefreshToken records a single issued refresh token. Tokens issued from one
+// login share a FamilyID (the ID of their session); every refresh rotates the
+// presented token and issues its successor in the same family.
+type RefreshToken struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	TokenID    string     `gorm:"column:token_id;not null;uniqueIndex:idx_refresh_tokens_token_id" json:"token_id"`
+	FamilyID   uint64     `gorm:"column:family_id;not null;index:idx_refresh_tokens_family_id" json:"family_id"`
+	UserID     uint64     `gorm:"column:user_id;not null;index:idx_refresh_tokens_user_id" json:"user_id"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	RotatedAt  *time.Time `gorm:"column:rotated_at" json:"rotated_at"`
+	ReplacedBy string     `gorm:"column:replaced_by;not null;default:''" json:"replaced_by"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsRotated reports whether the token has already been exchanged for a successor.
+func (t RefreshToken) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
+// IsUsable reports whether the token can be exchanged for a new token pair.
+func (t RefreshToken) IsUsable(now time.Time) bool {
+	return t.RotatedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+)
+
+type Session struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID     uint64     `gorm:"column:user_id;not null;index:idx_sessions_user_id" json:"user_id"`
+	TokenID    string     `gorm:"column:token_id;not null;uniqueIndex:idx_sessions_token_id" json:"token_id"`
+	UserAgent  string     `gorm:"column:user_agent;not null" json:"user_agent"`
+	IPAddress  string     `gorm:"column:ip_address;not null" json:"ip_address"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	LastUsedAt time.Time  `gorm:"column:last_used_at;not null" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsActive reports whether the session can still be used to refresh tokens.
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
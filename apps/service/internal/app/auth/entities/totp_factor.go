@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+)
+
+// TotpFactor is a user's authenticator app enrollment. It only protects logins
+// once ConfirmedAt is set, which proves the user copied the secret correctly.
+type TotpFactor struct {
+	ID           uint64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID       uint64     `gorm:"column:user_id;not null;uniqueIndex:idx_totp_factors_user_id" json:"user_id"`
+	Secret       string     `gorm:"column:secret;not null" json:"-"`
+	ConfirmedAt  *time.Time `gorm:"column:confirmed_at" json:"confirmed_at"`
+	LastUsedStep int64      `gorm:"column:last_used_step;not null;default:0" json:"-"`
+	CreatedAt    time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (TotpFactor) TableName() string {
+	return "totp_factors"
+}
+
+func (f TotpFactor) IsConfirmed() bool {
+	return f.ConfirmedAt != nil
+}
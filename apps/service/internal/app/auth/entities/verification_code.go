@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	VerificationChannelEmail = "email"
+	VerificationChannelPhone = "phone"
+
+	// Codes confirming a change of email or phone are sent to the new contact
+	// and kept apart from codes verifying the current one.
+	VerificationChannelEmailChange = "email_change"
+	VerificationChannelPhoneChange = "phone_change"
+)
+
+// VerificationCode is a short numeric code sent to an email address or phone
+// number to prove the user owns it. Only the hash of the code is stored.
+type VerificationCode struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID     uint64     `gorm:"column:user_id;not null;index:idx_verification_codes_user_channel" json:"user_id"`
+	Channel    string     `gorm:"column:channel;not null;index:idx_verification_codes_user_channel" json:"channel"`
+	Target     string     `gorm:"column:target;not null" json:"target"`
+	CodeHash   string     `gorm:"column:code_hash;not null" json:"-"`
+	Attempts   int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at" json:"consumed_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (VerificationCode) TableName() string {
+	return "verification_codes"
+}
+
+// IsUsable reports whether the code can still be confirmed.
+func (v VerificationCode) IsUsable(now time.Time, maxAttempts int) bool {
+	return v.ConsumedAt == nil && v.Attempts < maxAttempts && now.Before(v.ExpiresAt)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+// LoginAttemptStore tracks failed logins per subject so repeated guessing can
+// be slowed down across requests.
+type LoginAttemptStore interface {
+	Find(ctx context.Context, subject string) (entities.LoginAttempt, error)
+	// RecordFailure atomically counts a failed attempt and returns the updated
+	// record. Failures last recorded before since are forgotten and the count
+	// starts over at one.
+	RecordFailure(ctx context.Context, subject string, at time.Time, since time.Time) (entities.LoginAttempt, error)
+	Lock(ctx context.Context, subject string, until time.Time) error
+	Reset(ctx context.Context, subject string) error
+}
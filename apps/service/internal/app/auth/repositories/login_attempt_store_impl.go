@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type loginAttemptStore struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptStore returns a LoginAttemptStore backed by the database, so
+// counters are shared by every instance of the service.
+func NewLoginAttemptStore(db *gorm.DB) LoginAttemptStore {
+	return &loginAttemptStore{
+		db: db,
+	}
+}
+
+func (s *loginAttemptStore) Find(ctx context.Context, subject string) (entities.LoginAttempt, error) {
+	var attempt entities.LoginAttempt
+	err := s.db.WithContext(ctx).Where("subject = ?", subject).First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return attempt, errors.New("login attempt not found")
+		}
+		return attempt, err
+	}
+	return attempt, nil
+}
+
+func (s *loginAttemptStore) RecordFailure(ctx context.Context, subject string, at time.Time, since time.Time) (entities.LoginAttempt, error) {
+	attempt := entities.LoginAttempt{
+		Subject:      subject,
+		Failures:     1,
+		LastFailedAt: at,
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":       gorm.Expr("CASE WHEN login_attempts.last_failed_at < ? THEN 1 ELSE login_attempts.failures + 1 END", since),
+			"last_failed_at": at,
+		}),
+	}).Create(&attempt).Error
+	if err != nil {
+		return attempt, err
+	}
+
+	return s.Find(ctx, subject)
+}
+
+func (s *loginAttemptStore) Lock(ctx context.Context, subject string, until time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&entities.LoginAttempt{}).
+		Where("subject = ?", subject).
+		Update("locked_until", until).Error
+}
+
+func (s *loginAttemptStore) Reset(ctx context.Context, subject string) error {
+	return s.db.WithContext(ctx).Where("subject = ?", subject).Delete(&entities.LoginAttempt{}).Error
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type inMemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]entities.LoginAttempt
+}
+
+// NewInMemoryLoginAttemptStore returns a LoginAttemptStore kept in process
+// memory. Counters are per instance and lost on restart, which is fine for a
+// single instance deployment and for tests.
+func NewInMemoryLoginAttemptStore() LoginAttemptStore {
+	return &inMemoryLoginAttemptStore{
+		attempts: make(map[string]entities.LoginAttempt),
+	}
+}
+
+func (s *inMemoryLoginAttemptStore) Find(ctx context.Context, subject string) (entities.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[subject]
+	if !ok {
+		return entities.LoginAttempt{}, errors.New("login attempt not found")
+	}
+	return attempt, nil
+}
+
+func (s *inMemoryLoginAttemptStore) RecordFailure(ctx context.Context, subject string, at time.Time, since time.Time) (entities.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[subject]
+	if !ok || attempt.LastFailedAt.Before(since) {
+		attempt.Subject = subject
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailedAt = at
+	s.attempts[subject] = attempt
+	return attempt, nil
+}
+
+func (s *inMemoryLoginAttemptStore) Lock(ctx context.Context, subject string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[subject]
+	if !ok {
+		return nil
+	}
+
+	attempt.LockedUntil = &until
+	s.attempts[subject] = attempt
+	return nil
+}
+
+func (s *inMemoryLoginAttemptStore) Reset(ctx context.Context, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, subject)
+	return nil
+}
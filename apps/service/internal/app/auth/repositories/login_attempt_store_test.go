@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestLoginAttemptStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) LoginAttemptStore{
+		"gorm": func(t *testing.T) LoginAttemptStore {
+			return NewLoginAttemptStore(testutil.SetupTestDB(t, &entities.LoginAttempt{}))
+		},
+		"in-memory": func(t *testing.T) LoginAttemptStore {
+			return NewInMemoryLoginAttemptStore()
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			testLoginAttemptStore(t, newStore)
+		})
+	}
+}
+
+func testLoginAttemptStore(t *testing.T, newStore func(t *testing.T) LoginAttemptStore) {
+	t.Run("returns error when subject has no failures", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+
+		_, err := store.Find(ctx, "email:john@example.com")
+		assert.Error(t, err)
+		assert.Equal(t, "login attempt not found", err.Error())
+	})
+
+	t.Run("counts failures per subject", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		now := time.Now()
+		since := now.Add(-time.Hour)
+
+		for i := 1; i <= 3; i++ {
+			attempt, err := store.RecordFailure(ctx, "email:john@example.com", now, since)
+			require.NoError(t, err)
+			assert.Equal(t, i, attempt.Failures)
+		}
+
+		attempt, err := store.RecordFailure(ctx, "ip:127.0.0.1", now, since)
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+
+		found, err := store.Find(ctx, "email:john@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, 3, found.Failures)
+		assert.False(t, found.IsLocked(now))
+	})
+
+	t.Run("forgets failures older than the window", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		past := time.Now().Add(-2 * time.Hour)
+
+		_, err := store.RecordFailure(ctx, "email:john@example.com", past, past.Add(-time.Hour))
+		require.NoError(t, err)
+		_, err = store.RecordFailure(ctx, "email:john@example.com", past, past.Add(-time.Hour))
+		require.NoError(t, err)
+
+		now := time.Now()
+		attempt, err := store.RecordFailure(ctx, "email:john@example.com", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+	})
+
+	t.Run("locks and resets subject", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		now := time.Now()
+
+		_, err := store.RecordFailure(ctx, "email:john@example.com", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		require.NoError(t, store.Lock(ctx, "email:john@example.com", now.Add(time.Minute)))
+
+		found, err := store.Find(ctx, "email:john@example.com")
+		require.NoError(t, err)
+		assert.True(t, found.IsLocked(now))
+		assert.False(t, found.IsLocked(now.Add(2*time.Minute)))
+
+		require.NoError(t, store.Reset(ctx, "email:john@example.com"))
+
+		_, err = store.Find(ctx, "email:john@example.com")
+		assert.Error(t, err)
+	})
+}
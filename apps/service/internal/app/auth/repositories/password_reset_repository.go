@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type PasswordResetRepository interface {
+	FindByTokenHash(ctx context.Context, tokenHash string) (entities.PasswordReset, error)
+	Create(ctx context.Context, reset entities.PasswordReset) (entities.PasswordReset, error)
+	// MarkUsed atomically consumes an unused token. It fails with
+	// "password reset token already used" if the token was consumed concurrently.
+	MarkUsed(ctx context.Context, id uint64, at time.Time) error
+	InvalidateByUserID(ctx context.Context, userID uint64, at time.Time) error
+}
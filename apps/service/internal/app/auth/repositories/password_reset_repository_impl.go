@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{
+		db: db,
+	}
+}
+
+func (r *passwordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (entities.PasswordReset, error) {
+	var reset entities.PasswordReset
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&reset).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return reset, errors.New("password reset token not found")
+		}
+		return reset, err
+	}
+	return reset, nil
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, reset entities.PasswordReset) (entities.PasswordReset, error) {
+	err := r.db.WithContext(ctx).Create(&reset).Error
+	if err != nil {
+		return reset, err
+	}
+	return reset, nil
+}
+
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id uint64, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("password reset token already used")
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) InvalidateByUserID(ctx context.Context, userID uint64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func newTestPasswordReset(userID uint64, tokenHash string) entities.PasswordReset {
+	return entities.PasswordReset{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestPasswordResetRepository_FindByTokenHash(t *testing.T) {
+	t.Run("returns reset when found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.PasswordReset{})
+		repo := NewPasswordResetRepository(db)
+
+		created, err := repo.Create(ctx, newTestPasswordReset(1, "hash-1"))
+		require.NoError(t, err)
+
+		found, err := repo.FindByTokenHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.True(t, found.IsUsable(time.Now()))
+	})
+
+	t.Run("returns error when reset not found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.PasswordReset{})
+		repo := NewPasswordResetRepository(db)
+
+		_, err := repo.FindByTokenHash(ctx, "missing")
+		assert.Error(t, err)
+		assert.Equal(t, "password reset token not found", err.Error())
+	})
+}
+
+func TestPasswordResetRepository_MarkUsed(t *testing.T) {
+	t.Run("consumes the token only once", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.PasswordReset{})
+		repo := NewPasswordResetRepository(db)
+
+		created, err := repo.Create(ctx, newTestPasswordReset(1, "hash-1"))
+		require.NoError(t, err)
+
+		err = repo.MarkUsed(ctx, created.ID, time.Now())
+		require.NoError(t, err)
+
+		err = repo.MarkUsed(ctx, created.ID, time.Now())
+		assert.Error(t, err)
+		assert.Equal(t, "password reset token already used", err.Error())
+	})
+}
+
+func TestPasswordResetRepository_InvalidateByUserID(t *testing.T) {
+	t.Run("invalidates only the user's unused tokens", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.PasswordReset{})
+		repo := NewPasswordResetRepository(db)
+
+		_, err := repo.Create(ctx, newTestPasswordReset(1, "hash-1"))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestPasswordReset(2, "hash-2"))
+		require.NoError(t, err)
+
+		err = repo.InvalidateByUserID(ctx, 1, time.Now())
+		require.NoError(t, err)
+
+		first, err := repo.FindByTokenHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.False(t, first.IsUsable(time.Now()))
+
+		second, err := repo.FindByTokenHash(ctx, "hash-2")
+		require.NoError(t, err)
+		assert.True(t, second.IsUsable(time.Now()))
+	})
+}
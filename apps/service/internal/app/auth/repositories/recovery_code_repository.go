@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+type RecoveryCodeRepository interface {
+	CountUnusedByUserID(ctx context.Context, userID uint64) int64
+	// ReplaceForUser deletes every recovery code of the user and stores the given hashes.
+	ReplaceForUser(ctx context.Context, userID uint64, codeHashes []string) error
+	// Consume atomically marks an unused code of the user as used. It fails
+	// with "recovery code not found" when no such code exists.
+	Consume(ctx context.Context, userID uint64, codeHash string, at time.Time) error
+	DeleteByUserID(ctx context.Context, userID uint64) error
+}
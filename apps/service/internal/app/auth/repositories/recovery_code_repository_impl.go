@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		db: db,
+	}
+}
+
+func (r *recoveryCodeRepository) CountUnusedByUserID(ctx context.Context, userID uint64) int64 {
+	var count int64
+	r.db.WithContext(ctx).
+		Model(&entities.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count)
+	return count
+}
+
+func (r *recoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uint64, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entities.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]entities.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, entities.RecoveryCode{
+				UserID:   userID,
+				CodeHash: hash,
+			})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *recoveryCodeRepository) Consume(ctx context.Context, userID uint64, codeHash string, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("recovery code not found")
+	}
+	return nil
+}
+
+func (r *recoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uint64) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.RecoveryCode{}).Error
+}
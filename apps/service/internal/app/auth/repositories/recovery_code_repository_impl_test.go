@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestRecoveryCodeRepository_ReplaceForUser(t *testing.T) {
+	t.Run("replaces previous codes of the user only", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RecoveryCode{})
+		repo := NewRecoveryCodeRepository(db)
+
+		require.NoError(t, repo.ReplaceForUser(ctx, 1, []string{"a", "b", "c"}))
+		require.NoError(t, repo.ReplaceForUser(ctx, 2, []string{"d"}))
+		require.NoError(t, repo.ReplaceForUser(ctx, 1, []string{"e", "f"}))
+
+		assert.Equal(t, int64(2), repo.CountUnusedByUserID(ctx, 1))
+		assert.Equal(t, int64(1), repo.CountUnusedByUserID(ctx, 2))
+		assert.Error(t, repo.Consume(ctx, 1, "a", time.Now()))
+	})
+}
+
+func TestRecoveryCodeRepository_Consume(t *testing.T) {
+	t.Run("consumes a code only once", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RecoveryCode{})
+		repo := NewRecoveryCodeRepository(db)
+
+		require.NoError(t, repo.ReplaceForUser(ctx, 1, []string{"a", "b"}))
+
+		require.NoError(t, repo.Consume(ctx, 1, "a", time.Now()))
+		assert.Equal(t, int64(1), repo.CountUnusedByUserID(ctx, 1))
+
+		err := repo.Consume(ctx, 1, "a", time.Now())
+		assert.Error(t, err)
+		assert.Equal(t, "recovery code not found", err.Error())
+	})
+
+	t.Run("does not consume another user's code", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RecoveryCode{})
+		repo := NewRecoveryCodeRepository(db)
+
+		require.NoError(t, repo.ReplaceForUser(ctx, 1, []string{"a"}))
+
+		err := repo.Consume(ctx, 2, "a", time.Now())
+		assert.Error(t, err)
+		assert.Equal(t, int64(1), repo.CountUnusedByUserID(ctx, 1))
+	})
+}
+
+func TestRecoveryCodeRepository_DeleteByUserID(t *testing.T) {
+	t.Run("deletes every code of the user", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RecoveryCode{})
+		repo := NewRecoveryCodeRepository(db)
+
+		require.NoError(t, repo.ReplaceForUser(ctx, 1, []string{"a", "b"}))
+		require.NoError(t, repo.DeleteByUserID(ctx, 1))
+
+		assert.Equal(t, int64(0), repo.CountUnusedByUserID(ctx, 1))
+	})
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+// RefreshTokenStore tracks issued refresh tokens by jti so that each one can
+// be used only once and a whole family can be revoked on reuse.
+type RefreshTokenStore interface {
+	Save(ctx context.Context, token entities.RefreshToken) (entities.RefreshToken, error)
+	FindByTokenID(ctx context.Context, tokenID string) (entities.RefreshToken, error)
+	// MarkRotated atomically marks a usable token as rotated. It fails with
+	// "refresh token already used" when the token was rotated or revoked concurrently.
+	MarkRotated(ctx context.Context, tokenID string, replacedBy string, at time.Time) error
+	RevokeFamily(ctx context.Context, familyID uint64, at time.Time) error
+}
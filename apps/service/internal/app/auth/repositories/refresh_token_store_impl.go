@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type refreshTokenStore struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenStore(db *gorm.DB) RefreshTokenStore {
+	return &refreshTokenStore{
+		db: db,
+	}
+}
+
+func (r *refreshTokenStore) Save(ctx context.Context, token entities.RefreshToken) (entities.RefreshToken, error) {
+	err := r.db.WithContext(ctx).Create(&token).Error
+	if err != nil {
+		return token, err
+	}
+	return token, nil
+}
+
+func (r *refreshTokenStore) FindByTokenID(ctx context.Context, tokenID string) (entities.RefreshToken, error) {
+	var token entities.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_id = ?", tokenID).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return token, errors.New("refresh token not found")
+		}
+		return token, err
+	}
+	return token, nil
+}
+
+func (r *refreshTokenStore) MarkRotated(ctx context.Context, tokenID string, replacedBy string, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.RefreshToken{}).
+		Where("token_id = ? AND rotated_at IS NULL AND revoked_at IS NULL", tokenID).
+		Updates(map[string]interface{}{
+			"rotated_at":  at,
+			"replaced_by": replacedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("refresh token already used")
+	}
+	return nil
+}
+
+func (r *refreshTokenStore) RevokeFamily(ctx context.Context, familyID uint64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type inMemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	nextID uint64
+	tokens map[string]entities.RefreshToken
+}
+
+// NewInMemoryRefreshTokenStore returns a RefreshTokenStore kept in process
+// memory. It is intended for unit tests.
+func NewInMemoryRefreshTokenStore() RefreshTokenStore {
+	return &inMemoryRefreshTokenStore{
+		tokens: make(map[string]entities.RefreshToken),
+	}
+}
+
+func (s *inMemoryRefreshTokenStore) Save(ctx context.Context, token entities.RefreshToken) (entities.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tokens[token.TokenID]; exists {
+		return token, errors.New("refresh token already exists")
+	}
+
+	s.nextID++
+	token.ID = s.nextID
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	s.tokens[token.TokenID] = token
+	return token, nil
+}
+
+func (s *inMemoryRefreshTokenStore) FindByTokenID(ctx context.Context, tokenID string) (entities.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[tokenID]
+	if !ok {
+		return entities.RefreshToken{}, errors.New("refresh token not found")
+	}
+	return token, nil
+}
+
+func (s *inMemoryRefreshTokenStore) MarkRotated(ctx context.Context, tokenID string, replacedBy string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[tokenID]
+	if !ok || token.RotatedAt != nil || token.RevokedAt != nil {
+		return errors.New("refresh token already used")
+	}
+
+	token.RotatedAt = &at
+	token.ReplacedBy = replacedBy
+	s.tokens[tokenID] = token
+	return nil
+}
+
+func (s *inMemoryRefreshTokenStore) RevokeFamily(ctx context.Context, familyID uint64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for tokenID, token := range s.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			revokedAt := at
+			token.RevokedAt = &revokedAt
+			s.tokens[tokenID] = token
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestRefreshTokenStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) RefreshTokenStore{
+		"gorm": func(t *testing.T) RefreshTokenStore {
+			return NewRefreshTokenStore(testutil.SetupTestDB(t, &entities.RefreshToken{}))
+		},
+		"in-memory": func(t *testing.T) RefreshTokenStore {
+			return NewInMemoryRefreshTokenStore()
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			testRefreshTokenStore(t, newStore)
+		})
+	}
+}
+
+func newTestRefreshToken(familyID uint64, tokenID string) entities.RefreshToken {
+	return entities.RefreshToken{
+		TokenID:   tokenID,
+		FamilyID:  familyID,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func testRefreshTokenStore(t *testing.T, newStore func(t *testing.T) RefreshTokenStore) {
+	t.Run("saves and finds token", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+
+		saved, err := store.Save(ctx, newTestRefreshToken(1, "token-1"))
+		require.NoError(t, err)
+		assert.NotZero(t, saved.ID)
+
+		found, err := store.FindByTokenID(ctx, "token-1")
+		require.NoError(t, err)
+		assert.Equal(t, saved.ID, found.ID)
+		assert.True(t, found.IsUsable(time.Now()))
+	})
+
+	t.Run("returns error when token not found", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+
+		_, err := store.FindByTokenID(ctx, "missing")
+		assert.Error(t, err)
+		assert.Equal(t, "refresh token not found", err.Error())
+	})
+
+	t.Run("returns error on duplicate token ID", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+
+		_, err := store.Save(ctx, newTestRefreshToken(1, "token-1"))
+		require.NoError(t, err)
+
+		_, err = store.Save(ctx, newTestRefreshToken(2, "token-1"))
+		assert.Error(t, err)
+	})
+
+	t.Run("marks token as rotated only once", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+
+		_, err := store.Save(ctx, newTestRefreshToken(1, "token-1"))
+		require.NoError(t, err)
+
+		err = store.MarkRotated(ctx, "token-1", "token-2", time.Now())
+		require.NoError(t, err)
+
+		found, err := store.FindByTokenID(ctx, "token-1")
+		require.NoError(t, err)
+		assert.True(t, found.IsRotated())
+		assert.Equal(t, "token-2", found.ReplacedBy)
+
+		err = store.MarkRotated(ctx, "token-1", "token-3", time.Now())
+		assert.Error(t, err)
+		assert.Equal(t, "refresh token already used", err.Error())
+	})
+
+	t.Run("revokes every token of a family", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+
+		_, err := store.Save(ctx, newTestRefreshToken(1, "token-1"))
+		require.NoError(t, err)
+		_, err = store.Save(ctx, newTestRefreshToken(1, "token-2"))
+		require.NoError(t, err)
+		_, err = store.Save(ctx, newTestRefreshToken(2, "other-family"))
+		require.NoError(t, err)
+
+		err = store.RevokeFamily(ctx, 1, time.Now())
+		require.NoError(t, err)
+
+		for _, tokenID := range []string{"token-1", "token-2"} {
+			found, err := store.FindByTokenID(ctx, tokenID)
+			require.NoError(t, err)
+			assert.NotNil(t, found.RevokedAt, tokenID)
+			assert.False(t, found.IsUsable(time.Now()), tokenID)
+		}
+
+		other, err := store.FindByTokenID(ctx, "other-family")
+		require.NoError(t, err)
+		assert.Nil(t, other.RevokedAt)
+
+		err = store.MarkRotated(ctx, "token-2", "token-3", time.Now())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type SessionRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Session, error)
+	FindByTokenID(ctx context.Context, tokenID string) (entities.Session, error)
+	FindActiveByUserID(ctx context.Context, userID uint64) []entities.Session
+	Create(ctx context.Context, session entities.Session) (entities.Session, error)
+	Update(ctx context.Context, session entities.Session) (entities.Session, error)
+	RevokeAllByUserID(ctx context.Context, userID uint64, at time.Time) error
+}
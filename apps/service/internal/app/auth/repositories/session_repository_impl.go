@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id uint64) (entities.Session, error) {
+	var session entities.Session
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, errors.New("session not found")
+		}
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) FindByTokenID(ctx context.Context, tokenID string) (entities.Session, error) {
+	var session entities.Session
+	err := r.db.WithContext(ctx).Where("token_id = ?", tokenID).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, errors.New("session not found")
+		}
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) FindActiveByUserID(ctx context.Context, userID uint64) []entities.Session {
+	var sessions []entities.Session
+	r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions)
+	return sessions
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session entities.Session) (entities.Session, error) {
+	err := r.db.WithContext(ctx).Create(&session).Error
+	if err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) Update(ctx context.Context, session entities.Session) (entities.Session, error) {
+	err := r.db.WithContext(ctx).Save(&session).Error
+	if err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) RevokeAllByUserID(ctx context.Context, userID uint64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func newTestSession(userID uint64, tokenID string) entities.Session {
+	now := time.Now()
+	return entities.Session{
+		UserID:     userID,
+		TokenID:    tokenID,
+		UserAgent:  "test-agent",
+		IPAddress:  "127.0.0.1",
+		ExpiresAt:  now.Add(time.Hour),
+		LastUsedAt: now,
+	}
+}
+
+func TestSessionRepository_FindByID(t *testing.T) {
+	t.Run("returns session when found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		created, err := repo.Create(ctx, newTestSession(1, "token-1"))
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, "token-1", found.TokenID)
+	})
+
+	t.Run("returns error when session not found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		_, err := repo.FindByID(ctx, 999)
+		assert.Error(t, err)
+		assert.Equal(t, "session not found", err.Error())
+	})
+}
+
+func TestSessionRepository_FindByTokenID(t *testing.T) {
+	t.Run("returns session when found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		created, err := repo.Create(ctx, newTestSession(1, "token-1"))
+		require.NoError(t, err)
+
+		found, err := repo.FindByTokenID(ctx, "token-1")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, uint64(1), found.UserID)
+	})
+
+	t.Run("returns error when session not found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		_, err := repo.FindByTokenID(ctx, "missing")
+		assert.Error(t, err)
+		assert.Equal(t, "session not found", err.Error())
+	})
+}
+
+func TestSessionRepository_FindActiveByUserID(t *testing.T) {
+	t.Run("returns only active sessions of the user", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		_, err := repo.Create(ctx, newTestSession(1, "active"))
+		require.NoError(t, err)
+
+		revoked := newTestSession(1, "revoked")
+		revokedAt := time.Now()
+		revoked.RevokedAt = &revokedAt
+		_, err = repo.Create(ctx, revoked)
+		require.NoError(t, err)
+
+		expired := newTestSession(1, "expired")
+		expired.ExpiresAt = time.Now().Add(-time.Hour)
+		_, err = repo.Create(ctx, expired)
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, newTestSession(2, "other-user"))
+		require.NoError(t, err)
+
+		sessions := repo.FindActiveByUserID(ctx, 1)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "active", sessions[0].TokenID)
+	})
+}
+
+func TestSessionRepository_Create(t *testing.T) {
+	t.Run("returns error on duplicate token ID", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		_, err := repo.Create(ctx, newTestSession(1, "token-1"))
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, newTestSession(2, "token-1"))
+		assert.Error(t, err)
+	})
+}
+
+func TestSessionRepository_Update(t *testing.T) {
+	t.Run("updates session successfully", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		created, err := repo.Create(ctx, newTestSession(1, "token-1"))
+		require.NoError(t, err)
+
+		revokedAt := time.Now()
+		created.RevokedAt = &revokedAt
+		_, err = repo.Update(ctx, created)
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+		assert.False(t, found.IsActive(time.Now()))
+	})
+}
+
+func TestSessionRepository_RevokeAllByUserID(t *testing.T) {
+	t.Run("revokes every session of the user", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Session{})
+		repo := NewSessionRepository(db)
+
+		_, err := repo.Create(ctx, newTestSession(1, "token-1"))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestSession(1, "token-2"))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestSession(2, "token-3"))
+		require.NoError(t, err)
+
+		err = repo.RevokeAllByUserID(ctx, 1, time.Now())
+		require.NoError(t, err)
+
+		assert.Empty(t, repo.FindActiveByUserID(ctx, 1))
+		assert.Len(t, repo.FindActiveByUserID(ctx, 2), 1)
+	})
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type TotpFactorRepository interface {
+	FindByUserID(ctx context.Context, userID uint64) (entities.TotpFactor, error)
+	Create(ctx context.Context, factor entities.TotpFactor) (entities.TotpFactor, error)
+	Update(ctx context.Context, factor entities.TotpFactor) (entities.TotpFactor, error)
+	// MarkStepUsed records the time step of an accepted code. It fails with
+	// "totp code already used" unless the step is newer than the last one, so
+	// a code cannot be replayed within its validity window.
+	MarkStepUsed(ctx context.Context, id uint64, step int64) error
+	Delete(ctx context.Context, factor entities.TotpFactor) error
+}
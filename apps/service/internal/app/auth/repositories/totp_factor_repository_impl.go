@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type totpFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTotpFactorRepository(db *gorm.DB) TotpFactorRepository {
+	return &totpFactorRepository{
+		db: db,
+	}
+}
+
+func (r *totpFactorRepository) FindByUserID(ctx context.Context, userID uint64) (entities.TotpFactor, error) {
+	var factor entities.TotpFactor
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&factor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return factor, errors.New("totp factor not found")
+		}
+		return factor, err
+	}
+	return factor, nil
+}
+
+func (r *totpFactorRepository) Create(ctx context.Context, factor entities.TotpFactor) (entities.TotpFactor, error) {
+	err := r.db.WithContext(ctx).Create(&factor).Error
+	if err != nil {
+		return factor, err
+	}
+	return factor, nil
+}
+
+func (r *totpFactorRepository) Update(ctx context.Context, factor entities.TotpFactor) (entities.TotpFactor, error) {
+	err := r.db.WithContext(ctx).Save(&factor).Error
+	if err != nil {
+		return factor, err
+	}
+	return factor, nil
+}
+
+func (r *totpFactorRepository) MarkStepUsed(ctx context.Context, id uint64, step int64) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.TotpFactor{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("totp code already used")
+	}
+	return nil
+}
+
+func (r *totpFactorRepository) Delete(ctx context.Context, factor entities.TotpFactor) error {
+	return r.db.WithContext(ctx).Delete(&factor).Error
+}
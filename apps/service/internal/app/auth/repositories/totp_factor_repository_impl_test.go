@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestTotpFactorRepository_FindByUserID(t *testing.T) {
+	t.Run("returns factor when found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.TotpFactor{})
+		repo := NewTotpFactorRepository(db)
+
+		created, err := repo.Create(ctx, entities.TotpFactor{UserID: 1, Secret: "SECRET"})
+		require.NoError(t, err)
+
+		found, err := repo.FindByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, "SECRET", found.Secret)
+		assert.False(t, found.IsConfirmed())
+	})
+
+	t.Run("returns error when factor not found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.TotpFactor{})
+		repo := NewTotpFactorRepository(db)
+
+		_, err := repo.FindByUserID(ctx, 1)
+		assert.Error(t, err)
+		assert.Equal(t, "totp factor not found", err.Error())
+	})
+}
+
+func TestTotpFactorRepository_Create(t *testing.T) {
+	t.Run("allows only one factor per user", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.TotpFactor{})
+		repo := NewTotpFactorRepository(db)
+
+		_, err := repo.Create(ctx, entities.TotpFactor{UserID: 1, Secret: "FIRST"})
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, entities.TotpFactor{UserID: 1, Secret: "SECOND"})
+		assert.Error(t, err)
+	})
+}
+
+func TestTotpFactorRepository_Update(t *testing.T) {
+	t.Run("confirms factor and records last used step", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.TotpFactor{})
+		repo := NewTotpFactorRepository(db)
+
+		factor, err := repo.Create(ctx, entities.TotpFactor{UserID: 1, Secret: "SECRET"})
+		require.NoError(t, err)
+
+		now := time.Now()
+		factor.ConfirmedAt = &now
+		factor.LastUsedStep = 42
+		_, err = repo.Update(ctx, factor)
+		require.NoError(t, err)
+
+		found, err := repo.FindByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, found.IsConfirmed())
+		assert.Equal(t, int64(42), found.LastUsedStep)
+	})
+}
+
+func TestTotpFactorRepository_MarkStepUsed(t *testing.T) {
+	t.Run("accepts each step only once", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.TotpFactor{})
+		repo := NewTotpFactorRepository(db)
+
+		factor, err := repo.Create(ctx, entities.TotpFactor{UserID: 1, Secret: "SECRET"})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.MarkStepUsed(ctx, factor.ID, 100))
+
+		err = repo.MarkStepUsed(ctx, factor.ID, 100)
+		assert.Error(t, err)
+		assert.Equal(t, "totp code already used", err.Error())
+
+		assert.Error(t, repo.MarkStepUsed(ctx, factor.ID, 99))
+		assert.NoError(t, repo.MarkStepUsed(ctx, factor.ID, 101))
+	})
+}
+
+func TestTotpFactorRepository_Delete(t *testing.T) {
+	t.Run("removes the factor", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.TotpFactor{})
+		repo := NewTotpFactorRepository(db)
+
+		factor, err := repo.Create(ctx, entities.TotpFactor{UserID: 1, Secret: "SECRET"})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, factor))
+
+		_, err = repo.FindByUserID(ctx, 1)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type VerificationCodeRepository interface {
+	// FindLatest returns the most recently issued code of a user for a channel.
+	FindLatest(ctx context.Context, userID uint64, channel string) (entities.VerificationCode, error)
+	Create(ctx context.Context, code entities.VerificationCode) (entities.VerificationCode, error)
+	Update(ctx context.Context, code entities.VerificationCode) (entities.VerificationCode, error)
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+type verificationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewVerificationCodeRepository(db *gorm.DB) VerificationCodeRepository {
+	return &verificationCodeRepository{
+		db: db,
+	}
+}
+
+func (r *verificationCodeRepository) FindLatest(ctx context.Context, userID uint64, channel string) (entities.VerificationCode, error) {
+	var code entities.VerificationCode
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND channel = ?", userID, channel).
+		Order("created_at DESC, id DESC").
+		First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code, errors.New("verification code not found")
+		}
+		return code, err
+	}
+	return code, nil
+}
+
+func (r *verificationCodeRepository) Create(ctx context.Context, code entities.VerificationCode) (entities.VerificationCode, error) {
+	err := r.db.WithContext(ctx).Create(&code).Error
+	if err != nil {
+		return code, err
+	}
+	return code, nil
+}
+
+func (r *verificationCodeRepository) Update(ctx context.Context, code entities.VerificationCode) (entities.VerificationCode, error) {
+	err := r.db.WithContext(ctx).Save(&code).Error
+	if err != nil {
+		return code, err
+	}
+	return code, nil
+}
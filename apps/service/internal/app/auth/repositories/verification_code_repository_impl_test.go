@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func newTestVerificationCode(userID uint64, channel, codeHash string) entities.VerificationCode {
+	return entities.VerificationCode{
+		UserID:    userID,
+		Channel:   channel,
+		Target:    "john@example.com",
+		CodeHash:  codeHash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestVerificationCodeRepository_FindLatest(t *testing.T) {
+	t.Run("returns the most recent code of the channel", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.VerificationCode{})
+		repo := NewVerificationCodeRepository(db)
+
+		_, err := repo.Create(ctx, newTestVerificationCode(1, entities.VerificationChannelEmail, "old"))
+		require.NoError(t, err)
+		latest, err := repo.Create(ctx, newTestVerificationCode(1, entities.VerificationChannelEmail, "new"))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, newTestVerificationCode(1, entities.VerificationChannelPhone, "phone"))
+		require.NoError(t, err)
+
+		found, err := repo.FindLatest(ctx, 1, entities.VerificationChannelEmail)
+		require.NoError(t, err)
+		assert.Equal(t, latest.ID, found.ID)
+		assert.Equal(t, "new", found.CodeHash)
+	})
+
+	t.Run("returns error when no code exists", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.VerificationCode{})
+		repo := NewVerificationCodeRepository(db)
+
+		_, err := repo.FindLatest(ctx, 1, entities.VerificationChannelEmail)
+		assert.Error(t, err)
+		assert.Equal(t, "verification code not found", err.Error())
+	})
+}
+
+func TestVerificationCodeRepository_Update(t *testing.T) {
+	t.Run("updates attempts and consumption", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.VerificationCode{})
+		repo := NewVerificationCodeRepository(db)
+
+		code, err := repo.Create(ctx, newTestVerificationCode(1, entities.VerificationChannelEmail, "hash"))
+		require.NoError(t, err)
+
+		now := time.Now()
+		code.Attempts = 2
+		code.ConsumedAt = &now
+		_, err = repo.Update(ctx, code)
+		require.NoError(t, err)
+
+		found, err := repo.FindLatest(ctx, 1, entities.VerificationChannelEmail)
+		require.NoError(t, err)
+		assert.Equal(t, 2, found.Attempts)
+		assert.NotNil(t, found.ConsumedAt)
+		assert.False(t, found.IsUsable(time.Now(), 5))
+	})
+}
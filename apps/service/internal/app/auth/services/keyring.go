@@ -0,0 +1,304 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey is a single key of the keyring, identified by its kid.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// Keyring holds the active signing key and any retiring keys that are still
+// accepted for verification until the tokens they signed have expired.
+type Keyring struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// JSONWebKey is the public part of a signing key as described in RFC 7517.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// NewKeyring creates a keyring from the active key and optional retiring keys.
+func NewKeyring(active *SigningKey, retiring ...*SigningKey) (*Keyring, error) {
+	if active == nil {
+		return nil, errors.New("active signing key is required")
+	}
+
+	keyring := &Keyring{
+		active: active,
+		keys:   make(map[string]*SigningKey, len(retiring)+1),
+	}
+
+	for _, key := range append([]*SigningKey{active}, retiring...) {
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key ID %q", key.ID)
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	return keyring, nil
+}
+
+// NewKeyringFromConfig builds the keyring described by the JWT_* settings.
+// HS256 uses JWT_SECRET. RS256 and EdDSA load every key listed in
+// JWT_PRIVATE_KEY_FILES and sign with JWT_ACTIVE_KEY_ID; the remaining keys
+// only verify. A JWT_SECRET set alongside an asymmetric algorithm keeps
+// verifying tokens issued before the switch, which carry no kid.
+func NewKeyringFromConfig(config *config.Config) (*Keyring, error) {
+	algorithm := config.JwtAlgorithm
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+
+	switch algorithm {
+	case AlgorithmHS256:
+		if config.JwtSecret == "" {
+			return nil, errors.New("JWT secret is required")
+		}
+		return NewKeyring(NewHMACSigningKey(config.JwtActiveKeyID, []byte(config.JwtSecret)))
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+
+	if config.JwtActiveKeyID == "" {
+		return nil, errors.New("JWT active key ID is required")
+	}
+
+	keyFiles, err := parseKeyFiles(config.JwtPrivateKeyFiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyFiles) == 0 {
+		return nil, errors.New("JWT private key files are required")
+	}
+
+	var active *SigningKey
+	var retiring []*SigningKey
+	for _, kid := range sortedKeys(keyFiles) {
+		data, err := os.ReadFile(keyFiles[kid])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT key %q: %w", kid, err)
+		}
+
+		key, err := ParsePrivateKeyPEM(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT key %q: %w", kid, err)
+		}
+
+		if kid == config.JwtActiveKeyID {
+			if key.Method.Alg() != algorithm {
+				return nil, fmt.Errorf("active JWT key %q is not a %s key", kid, algorithm)
+			}
+			active = key
+			continue
+		}
+		retiring = append(retiring, key)
+	}
+
+	if active == nil {
+		return nil, fmt.Errorf("active JWT key %q is not configured", config.JwtActiveKeyID)
+	}
+
+	if config.JwtSecret != "" {
+		retiring = append(retiring, NewHMACSigningKey("", []byte(config.JwtSecret)))
+	}
+
+	return NewKeyring(active, retiring...)
+}
+
+// NewHMACSigningKey creates an HS256 key from a shared secret.
+func NewHMACSigningKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{
+		ID:        kid,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+}
+
+// NewAsymmetricSigningKey creates an RS256 or EdDSA key from a private key.
+func NewAsymmetricSigningKey(kid string, privateKey crypto.Signer) (*SigningKey, error) {
+	if kid == "" {
+		return nil, errors.New("key ID is required")
+	}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, signKey: key, verifyKey: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, signKey: key, verifyKey: key.Public()}, nil
+	default:
+		return nil, errors.New("unsupported private key type")
+	}
+}
+
+// ParsePrivateKeyPEM parses a PKCS#8 or PKCS#1 encoded RSA or Ed25519 private key.
+func ParsePrivateKeyPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+
+	return NewAsymmetricSigningKey(kid, signer)
+}
+
+// Active returns the key new tokens are signed with.
+func (k *Keyring) Active() *SigningKey {
+	return k.active
+}
+
+// Sign signs the token with the active key and stamps its kid in the header.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	if k.active.ID != "" {
+		token.Header["kid"] = k.active.ID
+	}
+	return token.SignedString(k.active.signKey)
+}
+
+// Keyfunc resolves the verification key for a parsed token by its kid and
+// refuses tokens whose algorithm does not match that key.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public keys of every asymmetric key in the keyring.
+// Shared HMAC secrets are never published.
+func (k *Keyring) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	for _, kid := range sortedKeys(k.keys) {
+		key := k.keys[kid]
+		switch publicKey := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return set
+}
+
+// parseKeyFiles parses a comma separated list of kid=path pairs.
+func parseKeyFiles(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return result, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path, found := strings.Cut(entry, "=")
+		kid = strings.TrimSpace(kid)
+		path = strings.TrimSpace(path)
+		if !found || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid JWT key file entry %q, expected kid=path", entry)
+		}
+		if _, exists := result[kid]; exists {
+			return nil, fmt.Errorf("duplicate signing key ID %q", kid)
+		}
+		result[kid] = path
+	}
+
+	return result, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
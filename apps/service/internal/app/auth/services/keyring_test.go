@@ -0,0 +1,260 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+func writeRSAKey(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func writeEd25519Key(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func setupAsymmetricConfig(algorithm, activeKeyID string, keyFiles ...string) *config.Config {
+	return &config.Config{
+		JwtAlgorithm:       algorithm,
+		JwtActiveKeyID:     activeKeyID,
+		JwtPrivateKeyFiles: strings.Join(keyFiles, ","),
+		JwtIssuer:          "test-issuer",
+		JwtAccessExpires:   "15m",
+		JwtRefreshExpires:  "168h",
+	}
+}
+
+func TestNewKeyringFromConfig(t *testing.T) {
+	t.Run("defaults to HS256", func(t *testing.T) {
+		keyring, err := NewKeyringFromConfig(setupTestConfig())
+		require.NoError(t, err)
+		assert.Equal(t, AlgorithmHS256, keyring.Active().Method.Alg())
+	})
+
+	t.Run("loads RS256 keys from PEM files", func(t *testing.T) {
+		dir := t.TempDir()
+		current := writeRSAKey(t, dir, "current.pem")
+		previous := writeRSAKey(t, dir, "previous.pem")
+
+		keyring, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmRS256, "2026-02", "2026-02="+current, "2026-01="+previous))
+		require.NoError(t, err)
+		assert.Equal(t, "2026-02", keyring.Active().ID)
+		assert.Equal(t, AlgorithmRS256, keyring.Active().Method.Alg())
+		assert.Len(t, keyring.JWKS().Keys, 2)
+	})
+
+	t.Run("loads EdDSA keys from PEM files", func(t *testing.T) {
+		dir := t.TempDir()
+		current := writeEd25519Key(t, dir, "current.pem")
+
+		keyring, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmEdDSA, "ed-1", "ed-1="+current))
+		require.NoError(t, err)
+		assert.Equal(t, AlgorithmEdDSA, keyring.Active().Method.Alg())
+	})
+
+	t.Run("returns error for unsupported algorithm", func(t *testing.T) {
+		_, err := NewKeyringFromConfig(setupAsymmetricConfig("none", "kid"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported JWT algorithm")
+	})
+
+	t.Run("returns error when active key ID is missing", func(t *testing.T) {
+		_, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmRS256, ""))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "JWT active key ID is required")
+	})
+
+	t.Run("returns error when active key is not configured", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeRSAKey(t, dir, "key.pem")
+
+		_, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmRS256, "missing", "present="+path))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not configured")
+	})
+
+	t.Run("returns error when active key does not match algorithm", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeEd25519Key(t, dir, "key.pem")
+
+		_, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmRS256, "ed", "ed="+path))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a RS256 key")
+	})
+
+	t.Run("returns error for malformed key file entry", func(t *testing.T) {
+		_, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmRS256, "kid", "no-separator"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected kid=path")
+	})
+
+	t.Run("returns error when key file cannot be read", func(t *testing.T) {
+		_, err := NewKeyringFromConfig(setupAsymmetricConfig(AlgorithmRS256, "kid", "kid=/does/not/exist.pem"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read JWT key")
+	})
+}
+
+func TestKeyring_SignAndVerify(t *testing.T) {
+	t.Run("stamps the active kid and verifies with it", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeRSAKey(t, dir, "key.pem")
+
+		ts, err := NewTokenService(setupAsymmetricConfig(AlgorithmRS256, "key-1", "key-1="+path))
+		require.NoError(t, err)
+
+		tokenString, err := ts.GenerateAccessToken(1, "test@example.com", "", 1)
+		require.NoError(t, err)
+
+		token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+		require.NoError(t, err)
+		assert.Equal(t, "key-1", token.Header["kid"])
+		assert.Equal(t, AlgorithmRS256, token.Method.Alg())
+
+		claims, err := ts.VerifyAccessToken(tokenString)
+		require.NoError(t, err)
+		assert.Equal(t, "1", claims.Subject)
+	})
+
+	t.Run("keeps verifying tokens signed by a retiring key", func(t *testing.T) {
+		dir := t.TempDir()
+		oldKey := writeRSAKey(t, dir, "old.pem")
+		newKey := writeEd25519Key(t, dir, "new.pem")
+
+		before, err := NewTokenService(setupAsymmetricConfig(AlgorithmRS256, "old", "old="+oldKey))
+		require.NoError(t, err)
+		tokenString, err := before.GenerateAccessToken(1, "test@example.com", "", 1)
+		require.NoError(t, err)
+
+		after, err := NewTokenService(setupAsymmetricConfig(AlgorithmEdDSA, "new", "new="+newKey, "old="+oldKey))
+		require.NoError(t, err)
+
+		_, err = after.VerifyAccessToken(tokenString)
+		assert.NoError(t, err)
+
+		removed, err := NewTokenService(setupAsymmetricConfig(AlgorithmEdDSA, "new", "new="+newKey))
+		require.NoError(t, err)
+
+		_, err = removed.VerifyAccessToken(tokenString)
+		assert.Error(t, err)
+	})
+
+	t.Run("keeps verifying HS256 tokens while a secret is configured", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeRSAKey(t, dir, "key.pem")
+
+		legacy, err := NewTokenService(setupTestConfig())
+		require.NoError(t, err)
+		tokenString, err := legacy.GenerateAccessToken(1, "test@example.com", "", 1)
+		require.NoError(t, err)
+
+		config := setupAsymmetricConfig(AlgorithmRS256, "key-1", "key-1="+path)
+		config.JwtSecret = setupTestConfig().JwtSecret
+		ts, err := NewTokenService(config)
+		require.NoError(t, err)
+
+		_, err = ts.VerifyAccessToken(tokenString)
+		assert.NoError(t, err)
+		assert.Len(t, ts.JWKS().Keys, 1)
+	})
+
+	t.Run("rejects a token whose algorithm does not match its key", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeRSAKey(t, dir, "key.pem")
+
+		ts, err := NewTokenService(setupAsymmetricConfig(AlgorithmRS256, "key-1", "key-1="+path))
+		require.NoError(t, err)
+
+		// Classic algorithm confusion: an HS256 token claiming the RSA kid.
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Type: "access"})
+		forged.Header["kid"] = "key-1"
+		tokenString, err := forged.SignedString([]byte("guessed"))
+		require.NoError(t, err)
+
+		_, err = ts.VerifyToken(tokenString)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects a token with an unknown kid", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeRSAKey(t, dir, "key.pem")
+		otherPath := writeRSAKey(t, dir, "other.pem")
+
+		ts, err := NewTokenService(setupAsymmetricConfig(AlgorithmRS256, "key-1", "key-1="+path))
+		require.NoError(t, err)
+		other, err := NewTokenService(setupAsymmetricConfig(AlgorithmRS256, "key-2", "key-2="+otherPath))
+		require.NoError(t, err)
+
+		tokenString, err := other.GenerateAccessToken(1, "test@example.com", "", 1)
+		require.NoError(t, err)
+
+		_, err = ts.VerifyToken(tokenString)
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyring_JWKS(t *testing.T) {
+	t.Run("does not publish HMAC secrets", func(t *testing.T) {
+		ts, err := NewTokenService(setupTestConfig())
+		require.NoError(t, err)
+
+		assert.Empty(t, ts.JWKS().Keys)
+	})
+
+	t.Run("publishes RSA and Ed25519 public keys", func(t *testing.T) {
+		dir := t.TempDir()
+		rsaPath := writeRSAKey(t, dir, "rsa.pem")
+		edPath := writeEd25519Key(t, dir, "ed.pem")
+
+		ts, err := NewTokenService(setupAsymmetricConfig(AlgorithmEdDSA, "ed", "ed="+edPath, "rsa="+rsaPath))
+		require.NoError(t, err)
+
+		keys := ts.JWKS().Keys
+		require.Len(t, keys, 2)
+
+		assert.Equal(t, "ed", keys[0].Kid)
+		assert.Equal(t, "OKP", keys[0].Kty)
+		assert.Equal(t, "Ed25519", keys[0].Crv)
+		assert.Equal(t, AlgorithmEdDSA, keys[0].Alg)
+		assert.NotEmpty(t, keys[0].X)
+
+		assert.Equal(t, "rsa", keys[1].Kid)
+		assert.Equal(t, "RSA", keys[1].Kty)
+		assert.Equal(t, AlgorithmRS256, keys[1].Alg)
+		assert.Equal(t, "AQAB", keys[1].E)
+		assert.NotEmpty(t, keys[1].N)
+		assert.Equal(t, "sig", keys[1].Use)
+	})
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+)
+
+// LoginThrottlePolicy describes how quickly repeated failed logins are slowed
+// down. Once a subject reaches its limit it is locked out for LockoutDuration,
+// and every further failure doubles the lockout up to MaxLockoutDuration.
+type LoginThrottlePolicy struct {
+	MaxAttempts        int
+	MaxAttemptsPerIP   int
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+	// Window is how long a failure is remembered. A failure after a quiet
+	// window starts the count over.
+	Window time.Duration
+}
+
+// LoginThrottleService tracks failed logins per identifier (email, phone or
+// user) and per client IP. The identifier limit protects a single account
+// from guessing; the higher IP limit catches one client trying many accounts.
+type LoginThrottleService struct {
+	store  repositories.LoginAttemptStore
+	policy LoginThrottlePolicy
+	now    func() time.Time
+}
+
+func NewLoginThrottleService(store repositories.LoginAttemptStore, policy LoginThrottlePolicy) *LoginThrottleService {
+	return &LoginThrottleService{
+		store:  store,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// Check returns how long the caller has to wait before trying again, or zero
+// when neither the identifier nor the IP address is locked out.
+func (s *LoginThrottleService) Check(ctx context.Context, identifier, ipAddress string) time.Duration {
+	now := s.now()
+
+	var wait time.Duration
+	for _, subject := range s.subjects(identifier, ipAddress) {
+		attempt, err := s.store.Find(ctx, subject)
+		if err != nil || !attempt.IsLocked(now) {
+			continue
+		}
+		if remaining := attempt.LockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	return wait
+}
+
+// RecordFailure counts a failed attempt and locks out every subject that has
+// reached its limit.
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, identifier, ipAddress string) error {
+	now := s.now()
+	since := now.Add(-s.policy.Window)
+
+	for _, subject := range s.subjects(identifier, ipAddress) {
+		attempt, err := s.store.RecordFailure(ctx, subject, now, since)
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+
+		limit := s.policy.MaxAttempts
+		if strings.HasPrefix(subject, "ip:") {
+			limit = s.policy.MaxAttemptsPerIP
+		}
+		if limit <= 0 || attempt.Failures < limit {
+			continue
+		}
+
+		if err := s.store.Lock(ctx, subject, now.Add(s.lockoutFor(attempt.Failures-limit))); err != nil {
+			return fmt.Errorf("failed to lock login attempts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Reset clears the failures of the given identifiers, after a successful login
+// or when an account is unlocked. IP counters are left alone so that logging
+// into one account does not reset guessing at others.
+func (s *LoginThrottleService) Reset(ctx context.Context, identifiers ...string) error {
+	for _, identifier := range identifiers {
+		if strings.TrimSpace(identifier) == "" {
+			continue
+		}
+		if err := s.store.Reset(ctx, identifierSubject(identifier)); err != nil {
+			return fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockoutFor doubles the base lockout for every failure past the limit.
+func (s *LoginThrottleService) lockoutFor(excess int) time.Duration {
+	lockout := s.policy.LockoutDuration
+	for i := 0; i < excess && lockout < s.policy.MaxLockoutDuration; i++ {
+		lockout *= 2
+	}
+	if s.policy.MaxLockoutDuration > 0 && lockout > s.policy.MaxLockoutDuration {
+		lockout = s.policy.MaxLockoutDuration
+	}
+	return lockout
+}
+
+func (s *LoginThrottleService) subjects(identifier, ipAddress string) []string {
+	var subjects []string
+	if strings.TrimSpace(identifier) != "" {
+		subjects = append(subjects, identifierSubject(identifier))
+	}
+	if ipAddress != "" {
+		subjects = append(subjects, "ip:"+ipAddress)
+	}
+	return subjects
+}
+
+func identifierSubject(identifier string) string {
+	return "identifier:" + strings.ToLower(strings.TrimSpace(identifier))
+}
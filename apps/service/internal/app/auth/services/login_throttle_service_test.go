@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+)
+
+func setupLoginThrottleTest() (*LoginThrottleService, *time.Time) {
+	now := time.Now()
+	throttle := NewLoginThrottleService(repositories.NewInMemoryLoginAttemptStore(), LoginThrottlePolicy{
+		MaxAttempts:        3,
+		MaxAttemptsPerIP:   5,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: 5 * time.Minute,
+		Window:             time.Hour,
+	})
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestLoginThrottleService(t *testing.T) {
+	t.Run("locks the identifier once the limit is reached", func(t *testing.T) {
+		ctx := context.Background()
+		throttle, _ := setupLoginThrottleTest()
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", "10.0.0.1"))
+			assert.Zero(t, throttle.Check(ctx, "john@example.com", "10.0.0.1"))
+		}
+
+		require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", "10.0.0.1"))
+		assert.Equal(t, time.Minute, throttle.Check(ctx, "john@example.com", "10.0.0.2"))
+		assert.Equal(t, time.Minute, throttle.Check(ctx, "JOHN@example.com ", ""))
+		assert.Zero(t, throttle.Check(ctx, "jane@example.com", "10.0.0.1"))
+	})
+
+	t.Run("backs off exponentially up to the maximum", func(t *testing.T) {
+		ctx := context.Background()
+		throttle, now := setupLoginThrottleTest()
+
+		expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+		for i := 0; i < 2; i++ {
+			require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", ""))
+		}
+		for _, lockout := range expected {
+			require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", ""))
+			assert.Equal(t, lockout, throttle.Check(ctx, "john@example.com", ""))
+
+			*now = now.Add(lockout)
+			assert.Zero(t, throttle.Check(ctx, "john@example.com", ""))
+		}
+	})
+
+	t.Run("locks the IP address across identifiers", func(t *testing.T) {
+		ctx := context.Background()
+		throttle, _ := setupLoginThrottleTest()
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, throttle.RecordFailure(ctx, "user"+string(rune('a'+i))+"@example.com", "10.0.0.1"))
+		}
+
+		assert.Equal(t, time.Minute, throttle.Check(ctx, "fresh@example.com", "10.0.0.1"))
+		assert.Zero(t, throttle.Check(ctx, "fresh@example.com", "10.0.0.2"))
+	})
+
+	t.Run("forgets failures after the window", func(t *testing.T) {
+		ctx := context.Background()
+		throttle, now := setupLoginThrottleTest()
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", ""))
+		}
+
+		*now = now.Add(2 * time.Hour)
+		require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", ""))
+		assert.Zero(t, throttle.Check(ctx, "john@example.com", ""))
+	})
+
+	t.Run("reset unlocks the identifier", func(t *testing.T) {
+		ctx := context.Background()
+		throttle, _ := setupLoginThrottleTest()
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, throttle.RecordFailure(ctx, "john@example.com", ""))
+		}
+		require.NotZero(t, throttle.Check(ctx, "john@example.com", ""))
+
+		require.NoError(t, throttle.Reset(ctx, "john@example.com", ""))
+		assert.Zero(t, throttle.Check(ctx, "john@example.com", ""))
+	})
+}
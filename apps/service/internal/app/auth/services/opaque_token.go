@@ -0,0 +1,72 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// GenerateOpaqueToken returns a random URL-safe token together with the hash
+// that should be persisted in its place. Only the hash is ever stored, so a
+// database leak does not expose usable tokens.
+func GenerateOpaqueToken() (token string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken hashes a token produced by GenerateOpaqueToken for lookup.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateNumericCode returns a random code of the given number of digits,
+// suitable for typing in from an SMS or email.
+func GenerateNumericCode(digits int) (string, error) {
+	var code strings.Builder
+	for i := 0; i < digits; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate code: %w", err)
+		}
+		code.WriteByte(byte('0' + n.Int64()))
+	}
+	return code.String(), nil
+}
+
+// recoveryCodeAlphabet leaves out characters that are easily confused when
+// copied from paper: 0/o, 1/l/i.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCode returns a random code in the form xxxxx-xxxxx.
+func GenerateRecoveryCode() (string, error) {
+	var code strings.Builder
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// HashRecoveryCode hashes a recovery code for lookup. Case, spaces and dashes
+// are ignored so the code can be typed back however the user wrote it down.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(code)
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+	return HashOpaqueToken(normalized)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	t.Run("returns token and matching hash", func(t *testing.T) {
+		token, hash, err := GenerateOpaqueToken()
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.Len(t, hash, 64)
+		assert.Equal(t, hash, HashOpaqueToken(token))
+		assert.NotEqual(t, token, hash)
+	})
+
+	t.Run("generates different tokens", func(t *testing.T) {
+		first, _, err := GenerateOpaqueToken()
+		require.NoError(t, err)
+		second, _, err := GenerateOpaqueToken()
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestGenerateNumericCode(t *testing.T) {
+	t.Run("returns code with requested number of digits", func(t *testing.T) {
+		code, err := GenerateNumericCode(6)
+		require.NoError(t, err)
+		assert.Len(t, code, 6)
+		for _, r := range code {
+			assert.True(t, r >= '0' && r <= '9')
+		}
+	})
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	t.Run("returns dashed code", func(t *testing.T) {
+		code, err := GenerateRecoveryCode()
+		require.NoError(t, err)
+		assert.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+	})
+
+	t.Run("hash ignores case and separators", func(t *testing.T) {
+		code, err := GenerateRecoveryCode()
+		require.NoError(t, err)
+
+		typed := strings.ToUpper(strings.ReplaceAll(code, "-", " "))
+		assert.Equal(t, HashRecoveryCode(code), HashRecoveryCode(typed))
+	})
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	params := Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hasher := NewArgon2idHasher(params)
+
+	t.Run("produces PHC strings", func(t *testing.T) {
+		hashed, err := hasher.Hash("password123")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(hashed, "$argon2id$v=19$m=1024,t=1,p=1$"))
+		assert.Len(t, strings.Split(hashed, "$"), 6)
+		assert.True(t, hasher.Recognizes(hashed))
+		assert.False(t, hasher.NeedsRehash(hashed))
+	})
+
+	t.Run("verifies passwords", func(t *testing.T) {
+		hashed, err := hasher.Hash("password123")
+		require.NoError(t, err)
+
+		assert.True(t, hasher.Verify(hashed, "password123"))
+		assert.False(t, hasher.Verify(hashed, "wrongpassword"))
+	})
+
+	t.Run("salts every hash", func(t *testing.T) {
+		hashed1, err := hasher.Hash("password123")
+		require.NoError(t, err)
+		hashed2, err := hasher.Hash("password123")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, hashed1, hashed2)
+	})
+
+	t.Run("rejects malformed hashes", func(t *testing.T) {
+		for _, encoded := range []string{
+			"$argon2id$",
+			"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=1024,t=1,p=1$!!$a2V5",
+		} {
+			assert.False(t, hasher.Verify(encoded, "password123"), encoded)
+			assert.True(t, hasher.NeedsRehash(encoded), encoded)
+		}
+	})
+
+	t.Run("does not recognize bcrypt hashes", func(t *testing.T) {
+		hashed, err := NewBcryptHasher(4).Hash("password123")
+		require.NoError(t, err)
+
+		assert.False(t, hasher.Recognizes(hashed))
+		assert.True(t, NewBcryptHasher(4).Recognizes(hashed))
+	})
+}
@@ -1,35 +1,74 @@
 package services
 
 import (
+	"fmt"
+
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/reno1r/weiss/apps/service/internal/config"
 )
 
+// PasswordService hashes new passwords with the configured algorithm and
+// verifies hashes made by any supported one.
 type PasswordService struct {
-	cost int
+	hasher  PasswordHasher
+	hashers []PasswordHasher
 }
 
-func NewPasswordService(config *config.Config) *PasswordService {
+func NewPasswordService(config *config.Config) (*PasswordService, error) {
 	cost := config.BcryptCost
 	if cost == 0 {
 		cost = bcrypt.DefaultCost
 	}
 
-	return &PasswordService{
-		cost: cost,
+	params := DefaultArgon2idParams
+	if config.Argon2Memory > 0 {
+		params.Memory = uint32(config.Argon2Memory)
+	}
+	if config.Argon2Iterations > 0 {
+		params.Iterations = uint32(config.Argon2Iterations)
+	}
+	if config.Argon2Parallelism > 0 {
+		params.Parallelism = uint8(config.Argon2Parallelism)
+	}
+
+	hashers := map[string]PasswordHasher{
+		PasswordHashBcrypt:   NewBcryptHasher(cost),
+		PasswordHashArgon2id: NewArgon2idHasher(params),
+	}
+
+	algorithm := config.PasswordHashAlgorithm
+	if algorithm == "" {
+		algorithm = PasswordHashBcrypt
+	}
+
+	hasher, ok := hashers[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", algorithm)
 	}
+
+	return &PasswordService{
+		hasher:  hasher,
+		hashers: []PasswordHasher{hashers[PasswordHashBcrypt], hashers[PasswordHashArgon2id]},
+	}, nil
 }
 
 func (ps *PasswordService) HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), ps.cost)
-	if err != nil {
-		return "", err
-	}
-	return string(bytes), nil
+	return ps.hasher.Hash(password)
 }
 
-func (ps *PasswordService) VerifyPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
+// VerifyPassword checks password against hashedPassword. needsRehash is only
+// set for a correct password whose hash was made with another algorithm or
+// outdated parameters; the caller should then store HashPassword(password).
+func (ps *PasswordService) VerifyPassword(hashedPassword, password string) (ok bool, needsRehash bool) {
+	for _, hasher := range ps.hashers {
+		if !hasher.Recognizes(hashedPassword) {
+			continue
+		}
+		if !hasher.Verify(hashedPassword, password) {
+			return false, false
+		}
+		return true, hasher != ps.hasher || hasher.NeedsRehash(hashedPassword)
+	}
+	return false, false
 }
@@ -11,31 +11,37 @@ import (
 )
 
 func TestNewPasswordService(t *testing.T) {
-	t.Run("uses configured cost", func(t *testing.T) {
-		config := &config.Config{
-			BcryptCost: 12,
-		}
-
-		service := NewPasswordService(config)
-		assert.Equal(t, 12, service.cost)
+	t.Run("uses configured bcrypt cost", func(t *testing.T) {
+		service, err := NewPasswordService(&config.Config{BcryptCost: 12})
+		require.NoError(t, err)
+		assert.Equal(t, &bcryptHasher{cost: 12}, service.hasher)
 	})
 
 	t.Run("uses default cost when config is 0", func(t *testing.T) {
-		config := &config.Config{
-			BcryptCost: 0,
-		}
-
-		service := NewPasswordService(config)
-		assert.Equal(t, bcrypt.DefaultCost, service.cost)
+		service, err := NewPasswordService(&config.Config{BcryptCost: 0})
+		require.NoError(t, err)
+		assert.Equal(t, &bcryptHasher{cost: bcrypt.DefaultCost}, service.hasher)
 	})
 
-	t.Run("uses provided cost even when negative", func(t *testing.T) {
-		config := &config.Config{
-			BcryptCost: -1,
-		}
+	t.Run("uses argon2id with configured parameters", func(t *testing.T) {
+		service, err := NewPasswordService(&config.Config{
+			PasswordHashAlgorithm: PasswordHashArgon2id,
+			Argon2Memory:          1024,
+			Argon2Iterations:      3,
+			Argon2Parallelism:     2,
+		})
+		require.NoError(t, err)
+
+		params := DefaultArgon2idParams
+		params.Memory = 1024
+		params.Iterations = 3
+		params.Parallelism = 2
+		assert.Equal(t, &argon2idHasher{params: params}, service.hasher)
+	})
 
-		service := NewPasswordService(config)
-		assert.Equal(t, -1, service.cost)
+	t.Run("returns error for unsupported algorithm", func(t *testing.T) {
+		_, err := NewPasswordService(&config.Config{PasswordHashAlgorithm: "md5"})
+		assert.Error(t, err)
 	})
 }
 
@@ -43,7 +49,8 @@ func TestPasswordService_HashPassword(t *testing.T) {
 	config := &config.Config{
 		BcryptCost: bcrypt.MinCost,
 	}
-	service := NewPasswordService(config)
+	service, err := NewPasswordService(config)
+	require.NoError(t, err)
 
 	t.Run("hashes password successfully", func(t *testing.T) {
 		password := "testpassword123"
@@ -69,7 +76,7 @@ func TestPasswordService_HashPassword(t *testing.T) {
 		hashed, err := service.HashPassword(password)
 		require.NoError(t, err)
 
-		isValid := service.VerifyPassword(hashed, password)
+		isValid, _ := service.VerifyPassword(hashed, password)
 		assert.True(t, isValid)
 	})
 }
@@ -78,14 +85,15 @@ func TestPasswordService_VerifyPassword(t *testing.T) {
 	config := &config.Config{
 		BcryptCost: bcrypt.MinCost,
 	}
-	service := NewPasswordService(config)
+	service, err := NewPasswordService(config)
+	require.NoError(t, err)
 
 	t.Run("verifies correct password", func(t *testing.T) {
 		password := "testpassword123"
 		hashed, err := service.HashPassword(password)
 		require.NoError(t, err)
 
-		isValid := service.VerifyPassword(hashed, password)
+		isValid, _ := service.VerifyPassword(hashed, password)
 		assert.True(t, isValid)
 	})
 
@@ -95,7 +103,7 @@ func TestPasswordService_VerifyPassword(t *testing.T) {
 		hashed, err := service.HashPassword(password)
 		require.NoError(t, err)
 
-		isValid := service.VerifyPassword(hashed, wrongPassword)
+		isValid, _ := service.VerifyPassword(hashed, wrongPassword)
 		assert.False(t, isValid)
 	})
 
@@ -104,13 +112,13 @@ func TestPasswordService_VerifyPassword(t *testing.T) {
 		hashed, err := service.HashPassword(password)
 		require.NoError(t, err)
 
-		isValid := service.VerifyPassword(hashed, "")
+		isValid, _ := service.VerifyPassword(hashed, "")
 		assert.False(t, isValid)
 	})
 
 	t.Run("rejects invalid hash", func(t *testing.T) {
 		invalidHash := "invalidhash"
-		isValid := service.VerifyPassword(invalidHash, "password")
+		isValid, _ := service.VerifyPassword(invalidHash, "password")
 		assert.False(t, isValid)
 	})
 
@@ -125,8 +133,76 @@ func TestPasswordService_VerifyPassword(t *testing.T) {
 			hashed, err := service.HashPassword(password)
 			require.NoError(t, err)
 
-			isValid := service.VerifyPassword(hashed, password)
+			isValid, _ := service.VerifyPassword(hashed, password)
 			assert.True(t, isValid, "password: %s", password)
 		}
 	})
 }
+
+func TestPasswordService_Rehash(t *testing.T) {
+	newService := func(t *testing.T, config *config.Config) *PasswordService {
+		service, err := NewPasswordService(config)
+		require.NoError(t, err)
+		return service
+	}
+
+	t.Run("does not flag hashes with current parameters", func(t *testing.T) {
+		service := newService(t, &config.Config{BcryptCost: bcrypt.MinCost})
+		hashed, err := service.HashPassword("password123")
+		require.NoError(t, err)
+
+		ok, needsRehash := service.VerifyPassword(hashed, "password123")
+		assert.True(t, ok)
+		assert.False(t, needsRehash)
+	})
+
+	t.Run("flags bcrypt hashes with another cost", func(t *testing.T) {
+		old := newService(t, &config.Config{BcryptCost: bcrypt.MinCost})
+		hashed, err := old.HashPassword("password123")
+		require.NoError(t, err)
+
+		service := newService(t, &config.Config{BcryptCost: bcrypt.MinCost + 1})
+		ok, needsRehash := service.VerifyPassword(hashed, "password123")
+		assert.True(t, ok)
+		assert.True(t, needsRehash)
+	})
+
+	t.Run("flags bcrypt hashes when argon2id is configured", func(t *testing.T) {
+		old := newService(t, &config.Config{BcryptCost: bcrypt.MinCost})
+		hashed, err := old.HashPassword("password123")
+		require.NoError(t, err)
+
+		service := newService(t, &config.Config{PasswordHashAlgorithm: PasswordHashArgon2id, Argon2Memory: 1024, Argon2Iterations: 1})
+		ok, needsRehash := service.VerifyPassword(hashed, "password123")
+		assert.True(t, ok)
+		assert.True(t, needsRehash)
+
+		upgraded, err := service.HashPassword("password123")
+		require.NoError(t, err)
+		ok, needsRehash = service.VerifyPassword(upgraded, "password123")
+		assert.True(t, ok)
+		assert.False(t, needsRehash)
+	})
+
+	t.Run("flags argon2id hashes with other parameters", func(t *testing.T) {
+		old := newService(t, &config.Config{PasswordHashAlgorithm: PasswordHashArgon2id, Argon2Memory: 1024, Argon2Iterations: 1})
+		hashed, err := old.HashPassword("password123")
+		require.NoError(t, err)
+
+		service := newService(t, &config.Config{PasswordHashAlgorithm: PasswordHashArgon2id, Argon2Memory: 2048, Argon2Iterations: 1})
+		ok, needsRehash := service.VerifyPassword(hashed, "password123")
+		assert.True(t, ok)
+		assert.True(t, needsRehash)
+	})
+
+	t.Run("never flags wrong passwords", func(t *testing.T) {
+		old := newService(t, &config.Config{BcryptCost: bcrypt.MinCost})
+		hashed, err := old.HashPassword("password123")
+		require.NoError(t, err)
+
+		service := newService(t, &config.Config{BcryptCost: bcrypt.MinCost + 1})
+		ok, needsRehash := service.VerifyPassword(hashed, "wrongpassword")
+		assert.False(t, ok)
+		assert.False(t, needsRehash)
+	})
+}
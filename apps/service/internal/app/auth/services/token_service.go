@@ -11,15 +11,16 @@ import (
 )
 
 type TokenService struct {
-	secretKey        []byte
+	keyring          *Keyring
 	issuer           string
 	accessExpiresIn  time.Duration
 	refreshExpiresIn time.Duration
 }
 
 func NewTokenService(config *config.Config) (*TokenService, error) {
-	if config.JwtSecret == "" {
-		return nil, errors.New("JWT secret is required")
+	keyring, err := NewKeyringFromConfig(config)
+	if err != nil {
+		return nil, err
 	}
 
 	if config.JwtIssuer == "" {
@@ -37,137 +38,180 @@ func NewTokenService(config *config.Config) (*TokenService, error) {
 	}
 
 	return &TokenService{
-		secretKey:        []byte(config.JwtSecret),
+		keyring:          keyring,
 		issuer:           config.JwtIssuer,
 		accessExpiresIn:  accessExpiresIn,
 		refreshExpiresIn: refreshExpiresIn,
 	}, nil
 }
 
+// Claims are the claims of every token the service issues. SessionID is set
+// on access tokens issued with a refresh token and names the session they
+// belong to, so that revoking the session also ends them. ImpersonatorID is
+// only set on impersonation tokens, where it names the administrator acting
+// as the subject.
 type Claims struct {
-	Email string `json:"email,omitempty"`
-	Phone string `json:"phone,omitempty"`
-	Type  string `json:"type"`
+	Email          string `json:"email,omitempty"`
+	Phone          string `json:"phone,omitempty"`
+	Type           string `json:"type"`
+	SessionID      uint64 `json:"sid,omitempty"`
+	ImpersonatorID uint64 `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type TokenPair struct {
-	AccessToken  string
-	RefreshToken string
+	AccessToken      string
+	RefreshToken     string
+	RefreshTokenID   string
+	RefreshExpiresAt time.Time
 }
 
-func (ts *TokenService) GenerateAccessToken(userID uint64, email, phone string) (string, error) {
-	now := time.Now()
-	expirationTime := now.Add(ts.accessExpiresIn)
-	jti, err := uuid.NewRandom()
-	if err != nil {
-		return "", fmt.Errorf("failed to generate JWT ID: %w", err)
-	}
+// GenerateAccessToken issues an access token belonging to the session
+// sessionID.
+func (ts *TokenService) GenerateAccessToken(userID uint64, email, phone string, sessionID uint64) (string, error) {
+	tokenString, _, err := ts.generateClaims(&Claims{Email: email, Phone: phone, Type: "access", SessionID: sessionID}, userID, ts.accessExpiresIn)
+	return tokenString, err
+}
+
+// GenerateRefreshToken issues a refresh token and returns it together with
+// its claims.
+func (ts *TokenService) GenerateRefreshToken(userID uint64, email, phone string) (string, *Claims, error) {
+	return ts.generateToken(userID, email, phone, "refresh", ts.refreshExpiresIn)
+}
+
+// mfaChallengeExpiresIn bounds how long a user has to enter their second
+// factor after a successful password check.
+const mfaChallengeExpiresIn = 5 * time.Minute
+
+// GenerateMfaToken issues a short-lived challenge token proving the password
+// step of a login succeeded. It is not accepted as an access token.
+func (ts *TokenService) GenerateMfaToken(userID uint64, email, phone string) (string, error) {
+	tokenString, _, err := ts.generateToken(userID, email, phone, "mfa", mfaChallengeExpiresIn)
+	return tokenString, err
+}
 
-	claims := &Claims{
-		Email: email,
-		Phone: phone,
-		Type:  "access",
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    ts.issuer,
-			Subject:   fmt.Sprintf("%d", userID),
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			ID:        jti.String(),
-		},
+// impersonationExpiresIn bounds how long an administrator can act as another
+// user with a single impersonation token.
+const impersonationExpiresIn = 15 * time.Minute
+
+// GenerateImpersonationToken issues a short-lived access token for userID on
+// behalf of the administrator impersonatorID. No refresh token comes with
+// it, so the impersonation ends when the token expires. It returns the
+// token together with its claims.
+func (ts *TokenService) GenerateImpersonationToken(userID uint64, email, phone string, impersonatorID uint64) (string, *Claims, error) {
+	return ts.generateClaims(&Claims{Email: email, Phone: phone, Type: "access", ImpersonatorID: impersonatorID}, userID, impersonationExpiresIn)
+}
+
+// GenerateTokenPair issues a refresh token and an access token for the
+// existing session sessionID.
+func (ts *TokenService) GenerateTokenPair(userID uint64, email, phone string, sessionID uint64) (*TokenPair, error) {
+	accessToken, err := ts.GenerateAccessToken(userID, email, phone, sessionID)
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(ts.secretKey)
+	refreshToken, refreshClaims, err := ts.GenerateRefreshToken(userID, email, phone)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return tokenString, nil
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshTokenID:   refreshClaims.ID,
+		RefreshExpiresAt: refreshClaims.ExpiresAt.Time,
+	}, nil
+}
+
+func (ts *TokenService) generateToken(userID uint64, email, phone, tokenType string, expiresIn time.Duration) (string, *Claims, error) {
+	return ts.generateClaims(&Claims{Email: email, Phone: phone, Type: tokenType}, userID, expiresIn)
 }
 
-func (ts *TokenService) GenerateRefreshToken(userID uint64, email, phone string) (string, error) {
+// generateClaims fills in the registered claims and signs the token.
+func (ts *TokenService) generateClaims(claims *Claims, userID uint64, expiresIn time.Duration) (string, *Claims, error) {
 	now := time.Now()
-	expirationTime := now.Add(ts.refreshExpiresIn)
+	expirationTime := now.Add(expiresIn)
 	jti, err := uuid.NewRandom()
 	if err != nil {
-		return "", fmt.Errorf("failed to generate JWT ID: %w", err)
+		return "", nil, fmt.Errorf("failed to generate JWT ID: %w", err)
 	}
 
-	claims := &Claims{
-		Email: email,
-		Phone: phone,
-		Type:  "refresh",
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    ts.issuer,
-			Subject:   fmt.Sprintf("%d", userID),
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			ID:        jti.String(),
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    ts.issuer,
+		Subject:   fmt.Sprintf("%d", userID),
+		ExpiresAt: jwt.NewNumericDate(expirationTime),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ID:        jti.String(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(ts.secretKey)
+	tokenString, err := ts.keyring.Sign(claims)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
-func (ts *TokenService) GenerateTokenPair(userID uint64, email, phone string) (*TokenPair, error) {
-	accessToken, err := ts.GenerateAccessToken(userID, email, phone)
+func (ts *TokenService) VerifyToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, ts.keyring.Keyfunc)
+
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := ts.GenerateRefreshToken(userID, email, phone)
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+func (ts *TokenService) VerifyRefreshToken(tokenString string) (*Claims, error) {
+	claims, err := ts.VerifyToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
-}
+	if claims.Type != "refresh" {
+		return nil, errors.New("invalid token type")
+	}
 
-func (ts *TokenService) VerifyToken(tokenString string) (*Claims, error) {
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return ts.secretKey, nil
-	})
+	return claims, nil
+}
 
+func (ts *TokenService) VerifyMfaToken(tokenString string) (*Claims, error) {
+	claims, err := ts.VerifyToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+	if claims.Type != "mfa" {
+		return nil, errors.New("invalid token type")
 	}
 
 	return claims, nil
 }
 
-func (ts *TokenService) VerifyRefreshToken(tokenString string) (*Claims, error) {
+func (ts *TokenService) VerifyAccessToken(tokenString string) (*Claims, error) {
 	claims, err := ts.VerifyToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims.Type != "refresh" {
+	if claims.Type != "access" {
 		return nil, errors.New("invalid token type")
 	}
 
 	return claims, nil
 }
 
+// JWKS returns the public keys that verify tokens issued by this service.
+func (ts *TokenService) JWKS() JSONWebKeySet {
+	return ts.keyring.JWKS()
+}
+
 func (ts *TokenService) GetUserID(claims *Claims) (uint64, error) {
 	if claims.Subject == "" {
 		return 0, errors.New("subject claim is missing")
@@ -181,17 +225,3 @@ func (ts *TokenService) GetUserID(claims *Claims) (uint64, error) {
 
 	return userID, nil
 }
-
-func (ts *TokenService) RefreshAccessToken(refreshToken string) (*TokenPair, error) {
-	claims, err := ts.VerifyRefreshToken(refreshToken)
-	if err != nil {
-		return nil, err
-	}
-
-	userID, err := ts.GetUserID(claims)
-	if err != nil {
-		return nil, err
-	}
-
-	return ts.GenerateTokenPair(userID, claims.Email, claims.Phone)
-}
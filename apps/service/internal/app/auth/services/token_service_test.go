@@ -74,16 +74,16 @@ func TestTokenService_GenerateAccessToken(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("generates access token successfully", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 	})
 
 	t.Run("generates different tokens for same user", func(t *testing.T) {
-		token1, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token1, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
-		token2, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token2, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		assert.NotEqual(t, token1, token2)
@@ -94,7 +94,7 @@ func TestTokenService_GenerateAccessToken(t *testing.T) {
 		email := "test@example.com"
 		phone := "1234567890"
 
-		token, err := service.GenerateAccessToken(userID, email, phone)
+		token, err := service.GenerateAccessToken(userID, email, phone, 7)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
@@ -103,13 +103,14 @@ func TestTokenService_GenerateAccessToken(t *testing.T) {
 		assert.Equal(t, "access", claims.Type)
 		assert.Equal(t, email, claims.Email)
 		assert.Equal(t, phone, claims.Phone)
+		assert.Equal(t, uint64(7), claims.SessionID)
 		assert.Equal(t, "test-issuer", claims.Issuer)
 		assert.Equal(t, "123", claims.Subject)
 		assert.NotEmpty(t, claims.ID)
 	})
 
 	t.Run("token has correct expiration", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
@@ -129,7 +130,7 @@ func TestTokenService_GenerateRefreshToken(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("generates refresh token successfully", func(t *testing.T) {
-		token, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
+		token, _, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 	})
@@ -139,7 +140,7 @@ func TestTokenService_GenerateRefreshToken(t *testing.T) {
 		email := "test@example.com"
 		phone := "1234567890"
 
-		token, err := service.GenerateRefreshToken(userID, email, phone)
+		token, _, err := service.GenerateRefreshToken(userID, email, phone)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyRefreshToken(token)
@@ -154,7 +155,7 @@ func TestTokenService_GenerateRefreshToken(t *testing.T) {
 	})
 
 	t.Run("token has correct expiration", func(t *testing.T) {
-		token, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
+		token, _, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
 		require.NoError(t, err)
 
 		claims, err := service.VerifyRefreshToken(token)
@@ -174,24 +175,35 @@ func TestTokenService_GenerateTokenPair(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("generates both tokens successfully", func(t *testing.T) {
-		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890")
+		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 		assert.NotEmpty(t, tokenPair.AccessToken)
 		assert.NotEmpty(t, tokenPair.RefreshToken)
 		assert.NotEqual(t, tokenPair.AccessToken, tokenPair.RefreshToken)
 	})
 
+	t.Run("exposes refresh token ID and expiration", func(t *testing.T) {
+		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890", 1)
+		require.NoError(t, err)
+
+		claims, err := service.VerifyRefreshToken(tokenPair.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, claims.ID, tokenPair.RefreshTokenID)
+		assert.WithinDuration(t, claims.ExpiresAt.Time, tokenPair.RefreshExpiresAt, 1*time.Second)
+	})
+
 	t.Run("access token is valid", func(t *testing.T) {
-		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890")
+		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890", 3)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(tokenPair.AccessToken)
 		require.NoError(t, err)
 		assert.Equal(t, "access", claims.Type)
+		assert.Equal(t, uint64(3), claims.SessionID)
 	})
 
 	t.Run("refresh token is valid", func(t *testing.T) {
-		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890")
+		tokenPair, err := service.GenerateTokenPair(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyRefreshToken(tokenPair.RefreshToken)
@@ -200,13 +212,45 @@ func TestTokenService_GenerateTokenPair(t *testing.T) {
 	})
 }
 
+func TestTokenService_GenerateImpersonationToken(t *testing.T) {
+	config := setupTestConfig()
+	service, err := NewTokenService(config)
+	require.NoError(t, err)
+
+	t.Run("carries both user IDs and is accepted as an access token", func(t *testing.T) {
+		token, issued, err := service.GenerateImpersonationToken(2, "user@example.com", "1234567890", 1)
+		require.NoError(t, err)
+
+		claims, err := service.VerifyAccessToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "2", claims.Subject)
+		assert.Equal(t, uint64(1), claims.ImpersonatorID)
+		assert.Equal(t, issued.ID, claims.ID)
+	})
+
+	t.Run("expires after the impersonation window", func(t *testing.T) {
+		_, claims, err := service.GenerateImpersonationToken(2, "user@example.com", "1234567890", 1)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(impersonationExpiresIn), claims.ExpiresAt.Time, 5*time.Second)
+	})
+
+	t.Run("regular access tokens carry no impersonator", func(t *testing.T) {
+		token, err := service.GenerateAccessToken(2, "user@example.com", "1234567890", 1)
+		require.NoError(t, err)
+
+		claims, err := service.VerifyAccessToken(token)
+		require.NoError(t, err)
+		assert.Zero(t, claims.ImpersonatorID)
+	})
+}
+
 func TestTokenService_VerifyToken(t *testing.T) {
 	config := setupTestConfig()
 	service, err := NewTokenService(config)
 	require.NoError(t, err)
 
 	t.Run("verifies valid token", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
@@ -234,7 +278,7 @@ func TestTokenService_VerifyToken(t *testing.T) {
 		otherService, err := NewTokenService(otherconfig)
 		require.NoError(t, err)
 
-		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := otherService.VerifyToken(token)
@@ -249,7 +293,7 @@ func TestTokenService_VerifyRefreshToken(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("verifies valid refresh token", func(t *testing.T) {
-		token, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
+		token, _, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
 		require.NoError(t, err)
 
 		claims, err := service.VerifyRefreshToken(token)
@@ -259,7 +303,7 @@ func TestTokenService_VerifyRefreshToken(t *testing.T) {
 	})
 
 	t.Run("rejects access token", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyRefreshToken(token)
@@ -276,13 +320,78 @@ func TestTokenService_VerifyRefreshToken(t *testing.T) {
 	})
 }
 
+func TestTokenService_VerifyAccessToken(t *testing.T) {
+	config := setupTestConfig()
+	service, err := NewTokenService(config)
+	require.NoError(t, err)
+
+	t.Run("verifies valid access token", func(t *testing.T) {
+		token, err := service.GenerateAccessToken(1, "test@example.com", "1234567890", 1)
+		require.NoError(t, err)
+
+		claims, err := service.VerifyAccessToken(token)
+		require.NoError(t, err)
+		assert.NotNil(t, claims)
+		assert.Equal(t, "access", claims.Type)
+	})
+
+	t.Run("rejects refresh token", func(t *testing.T) {
+		token, _, err := service.GenerateRefreshToken(1, "test@example.com", "1234567890")
+		require.NoError(t, err)
+
+		claims, err := service.VerifyAccessToken(token)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+		assert.Contains(t, err.Error(), "invalid token type")
+	})
+
+	t.Run("rejects invalid token", func(t *testing.T) {
+		claims, err := service.VerifyAccessToken("invalid.token.here")
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+}
+
+func TestTokenService_VerifyMfaToken(t *testing.T) {
+	config := setupTestConfig()
+	ts, err := NewTokenService(config)
+	require.NoError(t, err)
+
+	t.Run("verifies valid MFA token", func(t *testing.T) {
+		token, err := ts.GenerateMfaToken(123, "test@example.com", "")
+		require.NoError(t, err)
+
+		claims, err := ts.VerifyMfaToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "mfa", claims.Type)
+		assert.WithinDuration(t, time.Now().Add(mfaChallengeExpiresIn), claims.ExpiresAt.Time, 2*time.Second)
+	})
+
+	t.Run("is not accepted as an access token", func(t *testing.T) {
+		token, err := ts.GenerateMfaToken(123, "test@example.com", "")
+		require.NoError(t, err)
+
+		_, err = ts.VerifyAccessToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects access token", func(t *testing.T) {
+		token, err := ts.GenerateAccessToken(123, "test@example.com", "", 1)
+		require.NoError(t, err)
+
+		_, err = ts.VerifyMfaToken(token)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid token type")
+	})
+}
+
 func TestTokenService_GetUserID(t *testing.T) {
 	config := setupTestConfig()
 	service, err := NewTokenService(config)
 	require.NoError(t, err)
 
 	t.Run("extracts user ID from claims", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(123, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(123, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
@@ -321,7 +430,7 @@ func TestTokenService_GetUserID(t *testing.T) {
 
 	t.Run("handles large user IDs", func(t *testing.T) {
 		largeID := uint64(999999999999)
-		token, err := service.GenerateAccessToken(largeID, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(largeID, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
@@ -333,60 +442,13 @@ func TestTokenService_GetUserID(t *testing.T) {
 	})
 }
 
-func TestTokenService_RefreshAccessToken(t *testing.T) {
-	config := setupTestConfig()
-	service, err := NewTokenService(config)
-	require.NoError(t, err)
-
-	t.Run("refreshes token successfully", func(t *testing.T) {
-		refreshToken, err := service.GenerateRefreshToken(123, "test@example.com", "1234567890")
-		require.NoError(t, err)
-
-		tokenPair, err := service.RefreshAccessToken(refreshToken)
-		require.NoError(t, err)
-		assert.NotEmpty(t, tokenPair.AccessToken)
-		assert.NotEmpty(t, tokenPair.RefreshToken)
-	})
-
-	t.Run("new tokens are valid", func(t *testing.T) {
-		refreshToken, err := service.GenerateRefreshToken(123, "test@example.com", "1234567890")
-		require.NoError(t, err)
-
-		tokenPair, err := service.RefreshAccessToken(refreshToken)
-		require.NoError(t, err)
-
-		accessClaims, err := service.VerifyToken(tokenPair.AccessToken)
-		require.NoError(t, err)
-		assert.Equal(t, "access", accessClaims.Type)
-
-		refreshClaims, err := service.VerifyRefreshToken(tokenPair.RefreshToken)
-		require.NoError(t, err)
-		assert.Equal(t, "refresh", refreshClaims.Type)
-	})
-
-	t.Run("returns error with access token", func(t *testing.T) {
-		accessToken, err := service.GenerateAccessToken(123, "test@example.com", "1234567890")
-		require.NoError(t, err)
-
-		tokenPair, err := service.RefreshAccessToken(accessToken)
-		assert.Error(t, err)
-		assert.Nil(t, tokenPair)
-	})
-
-	t.Run("returns error with invalid token", func(t *testing.T) {
-		tokenPair, err := service.RefreshAccessToken("invalid.token")
-		assert.Error(t, err)
-		assert.Nil(t, tokenPair)
-	})
-}
-
 func TestTokenService_RFC7519_Compliance(t *testing.T) {
 	config := setupTestConfig()
 	service, err := NewTokenService(config)
 	require.NoError(t, err)
 
 	t.Run("includes all RFC 7519 standard claims", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(123, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(123, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
@@ -401,7 +463,7 @@ func TestTokenService_RFC7519_Compliance(t *testing.T) {
 	})
 
 	t.Run("nbf claim prevents early use", func(t *testing.T) {
-		token, err := service.GenerateAccessToken(123, "test@example.com", "1234567890")
+		token, err := service.GenerateAccessToken(123, "test@example.com", "1234567890", 1)
 		require.NoError(t, err)
 
 		claims, err := service.VerifyToken(token)
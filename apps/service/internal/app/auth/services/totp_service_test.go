@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA-1 seed used by the test vectors in RFC 6238, appendix B.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func newTotpServiceAt(at time.Time) *TotpService {
+	service := NewTotpService("Weiss")
+	service.now = func() time.Time { return at }
+	return service
+}
+
+func TestTotpService_Code(t *testing.T) {
+	t.Run("matches RFC 6238 test vectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+
+		for unix, expected := range vectors {
+			code, err := newTotpServiceAt(time.Unix(unix, 0)).Code(rfc6238Secret)
+			require.NoError(t, err)
+			assert.Equal(t, expected, code, "time %d", unix)
+		}
+	})
+}
+
+func TestTotpService_Validate(t *testing.T) {
+	t.Run("accepts the current code", func(t *testing.T) {
+		service := newTotpServiceAt(time.Unix(1111111111, 0))
+
+		step, ok := service.Validate(rfc6238Secret, "050471")
+		assert.True(t, ok)
+		assert.Equal(t, int64(1111111111/30), step)
+	})
+
+	t.Run("accepts a code from the previous period", func(t *testing.T) {
+		service := newTotpServiceAt(time.Unix(1111111111+30, 0))
+
+		_, ok := service.Validate(rfc6238Secret, "050471")
+		assert.True(t, ok)
+	})
+
+	t.Run("rejects a code outside the allowed skew", func(t *testing.T) {
+		service := newTotpServiceAt(time.Unix(1111111111+90, 0))
+
+		_, ok := service.Validate(rfc6238Secret, "050471")
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects malformed codes", func(t *testing.T) {
+		service := newTotpServiceAt(time.Unix(1111111111, 0))
+
+		_, ok := service.Validate(rfc6238Secret, "12345")
+		assert.False(t, ok)
+		_, ok = service.Validate("not base32!", "050471")
+		assert.False(t, ok)
+	})
+}
+
+func TestTotpService_GenerateSecret(t *testing.T) {
+	t.Run("generates a 160 bit base32 secret", func(t *testing.T) {
+		service := NewTotpService("Weiss")
+
+		secret, err := service.GenerateSecret()
+		require.NoError(t, err)
+		assert.Len(t, secret, 32)
+
+		other, err := service.GenerateSecret()
+		require.NoError(t, err)
+		assert.NotEqual(t, secret, other)
+	})
+}
+
+func TestTotpService_URI(t *testing.T) {
+	t.Run("builds an otpauth URI", func(t *testing.T) {
+		service := NewTotpService("Weiss")
+
+		uri := service.URI("JBSWY3DPEHPK3PXP", "john@example.com")
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Weiss:john@example.com?"))
+		assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+		assert.Contains(t, uri, "issuer=Weiss")
+		assert.Contains(t, uri, "digits=6")
+		assert.Contains(t, uri, "period=30")
+	})
+}
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+type AuthenticateAccessTokenUsecase struct {
+	userRepository    repositories.UserRepository
+	sessionRepository authrepositories.SessionRepository
+	tokenService      *services.TokenService
+}
+
+func NewAuthenticateAccessTokenUsecase(userRepository repositories.UserRepository, sessionRepository authrepositories.SessionRepository, tokenService *services.TokenService) *AuthenticateAccessTokenUsecase {
+	return &AuthenticateAccessTokenUsecase{
+		userRepository:    userRepository,
+		sessionRepository: sessionRepository,
+		tokenService:      tokenService,
+	}
+}
+
+// Execute resolves the caller of an access token. Tokens that fail
+// verification, tokens whose session has been revoked or has expired and
+// tokens of users that no longer exist fail with "invalid access token".
+// Tokens of suspended users fail with "account suspended: ...". Both take
+// effect on the next request rather than when the token expires.
+// Impersonation tokens have no session; they are short-lived instead.
+func (u *AuthenticateAccessTokenUsecase) Execute(ctx context.Context, token string) (*authentities.Principal, error) {
+	claims, err := u.tokenService.VerifyAccessToken(token)
+	if err != nil {
+		return nil, errors.New("invalid access token")
+	}
+
+	userID, err := u.tokenService.GetUserID(claims)
+	if err != nil {
+		return nil, errors.New("invalid access token")
+	}
+
+	user, err := u.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.New("invalid access token")
+		}
+		return nil, err
+	}
+	// Suspending also revokes the sessions of the user, so this comes first
+	// to tell them why they were signed out.
+	if user.IsSuspended() {
+		return nil, errors.New("account suspended: contact support")
+	}
+
+	if claims.ImpersonatorID == 0 {
+		session, err := u.sessionRepository.FindByID(ctx, claims.SessionID)
+		if err != nil {
+			if err.Error() == "session not found" {
+				return nil, errors.New("invalid access token")
+			}
+			return nil, err
+		}
+		if session.UserID != userID || !session.IsActive(time.Now()) {
+			return nil, errors.New("invalid access token")
+		}
+	}
+
+	return &authentities.Principal{
+		UserID:         userID,
+		Email:          claims.Email,
+		Phone:          claims.Phone,
+		TokenID:        claims.ID,
+		ImpersonatorID: claims.ImpersonatorID,
+	}, nil
+}
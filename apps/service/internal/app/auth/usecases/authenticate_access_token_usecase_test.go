@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/config"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+type authenticateAccessTokenFixture struct {
+	userRepo     repositories.UserRepository
+	sessionRepo  authrepositories.SessionRepository
+	tokenService *services.TokenService
+	user         entities.User
+	session      authentities.Session
+}
+
+func setupAuthenticateAccessTokenTest(t *testing.T) (*AuthenticateAccessTokenUsecase, *authenticateAccessTokenFixture) {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.Session{})
+	fixture := &authenticateAccessTokenFixture{
+		userRepo:    repositories.NewUserRepository(db),
+		sessionRepo: authrepositories.NewSessionRepository(db),
+	}
+
+	tokenService, err := services.NewTokenService(&config.Config{
+		JwtSecret:         "test-secret-key-minimum-32-characters-long",
+		JwtIssuer:         "test-issuer",
+		JwtAccessExpires:  "15m",
+		JwtRefreshExpires: "168h",
+	})
+	require.NoError(t, err)
+	fixture.tokenService = tokenService
+
+	fixture.user, err = fixture.userRepo.Create(ctx, entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: "hashed",
+	})
+	require.NoError(t, err)
+
+	fixture.session, err = fixture.sessionRepo.Create(ctx, authentities.Session{
+		UserID:     fixture.user.ID,
+		TokenID:    "token-1",
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastUsedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	return NewAuthenticateAccessTokenUsecase(fixture.userRepo, fixture.sessionRepo, tokenService), fixture
+}
+
+func (f *authenticateAccessTokenFixture) accessToken(t *testing.T) string {
+	token, err := f.tokenService.GenerateAccessToken(f.user.ID, f.user.Email, f.user.Phone, f.session.ID)
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthenticateAccessTokenUsecase_Execute(t *testing.T) {
+	t.Run("resolves the principal of the token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+
+		principal, err := usecase.Execute(ctx, fixture.accessToken(t))
+		require.NoError(t, err)
+		assert.Equal(t, fixture.user.ID, principal.UserID)
+		assert.Equal(t, fixture.user.Email, principal.Email)
+		assert.NotEmpty(t, principal.TokenID)
+		assert.False(t, principal.IsImpersonated())
+	})
+
+	t.Run("accepts impersonation tokens without a session", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+
+		token, _, err := fixture.tokenService.GenerateImpersonationToken(fixture.user.ID, fixture.user.Email, fixture.user.Phone, 42)
+		require.NoError(t, err)
+
+		principal, err := usecase.Execute(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), principal.ImpersonatorID)
+	})
+
+	t.Run("rejects invalid and refresh tokens", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+
+		refreshToken, _, err := fixture.tokenService.GenerateRefreshToken(fixture.user.ID, fixture.user.Email, fixture.user.Phone)
+		require.NoError(t, err)
+
+		for _, token := range []string{"not-a-token", refreshToken} {
+			_, err := usecase.Execute(ctx, token)
+			assert.Error(t, err)
+			assert.Equal(t, "invalid access token", err.Error())
+		}
+	})
+
+	t.Run("rejects tokens without a session", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+
+		token, err := fixture.tokenService.GenerateAccessToken(fixture.user.ID, fixture.user.Email, fixture.user.Phone, 0)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, token)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid access token", err.Error())
+	})
+
+	t.Run("rejects tokens of revoked sessions", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+		token := fixture.accessToken(t)
+
+		require.NoError(t, fixture.sessionRepo.RevokeAllByUserID(ctx, fixture.user.ID, time.Now()))
+
+		_, err := usecase.Execute(ctx, token)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid access token", err.Error())
+	})
+
+	t.Run("rejects tokens naming the session of another user", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+
+		other, err := fixture.userRepo.Create(ctx, entities.User{
+			FullName: "Jane Doe",
+			Phone:    "1234567891",
+			Email:    "jane@example.com",
+			Password: "hashed",
+		})
+		require.NoError(t, err)
+
+		token, err := fixture.tokenService.GenerateAccessToken(other.ID, other.Email, other.Phone, fixture.session.ID)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, token)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid access token", err.Error())
+	})
+
+	t.Run("rejects tokens of deleted users", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+
+		session, err := fixture.sessionRepo.Create(ctx, authentities.Session{
+			UserID:     999,
+			TokenID:    "token-2",
+			ExpiresAt:  time.Now().Add(time.Hour),
+			LastUsedAt: time.Now(),
+		})
+		require.NoError(t, err)
+
+		token, err := fixture.tokenService.GenerateAccessToken(999, "ghost@example.com", "", session.ID)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, token)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid access token", err.Error())
+	})
+
+	t.Run("rejects tokens of suspended users", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupAuthenticateAccessTokenTest(t)
+		token := fixture.accessToken(t)
+
+		suspendedAt := time.Now()
+		fixture.user.SuspendedAt = &suspendedAt
+		_, err := fixture.userRepo.Update(ctx, fixture.user)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, token)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "account suspended")
+	})
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ChangePasswordUsecase struct {
+	db              *gorm.DB
+	passwordService *services.PasswordService
+	loginThrottle   *services.LoginThrottleService
+	validator       *validator.Validate
+}
+
+func NewChangePasswordUsecase(db *gorm.DB, passwordService *services.PasswordService, loginThrottle *services.LoginThrottleService) *ChangePasswordUsecase {
+	return &ChangePasswordUsecase{
+		db:              db,
+		passwordService: passwordService,
+		loginThrottle:   loginThrottle,
+		validator:       validator.New(),
+	}
+}
+
+type ChangePasswordParam struct {
+	UserID          uint64 `validate:"required"`
+	CurrentPassword string `validate:"required"`
+	NewPassword     string `validate:"required,min=6,max=100"`
+}
+
+// Execute replaces the password of a signed in user after checking the
+// current one. Like a password reset it signs out every session, so a
+// refresh token stolen before the change stops working. Wrong current
+// passwords are throttled per user.
+func (u *ChangePasswordUsecase) Execute(ctx context.Context, param ChangePasswordParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	user, err := repositories.NewUserRepository(u.db).FindByID(ctx, param.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkCurrentPassword(ctx, u.loginThrottle, u.passwordService, user, param.CurrentPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.passwordService.HashPassword(param.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txUserRepo := repositories.NewUserRepository(tx)
+		txSessionRepo := authrepositories.NewSessionRepository(tx)
+
+		user, err := txUserRepo.FindByID(ctx, param.UserID)
+		if err != nil {
+			return err
+		}
+
+		user.Password = hashedPassword
+		if _, err := txUserRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		if err := txSessionRepo.RevokeAllByUserID(ctx, user.ID, time.Now()); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupChangePasswordTest(t *testing.T) (*ChangePasswordUsecase, *gorm.DB, *services.PasswordService, entities.User) {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.Session{})
+	passwordService := newTestPasswordService(t)
+
+	hashedPassword, err := passwordService.HashPassword("password123")
+	require.NoError(t, err)
+
+	user, err := repositories.NewUserRepository(db).Create(ctx, entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: hashedPassword,
+	})
+	require.NoError(t, err)
+
+	_, err = authrepositories.NewSessionRepository(db).Create(ctx, authentities.Session{
+		UserID:     user.ID,
+		TokenID:    "token-1",
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastUsedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	return NewChangePasswordUsecase(db, passwordService, newTestLoginThrottle()), db, passwordService, user
+}
+
+func TestChangePasswordUsecase_Execute(t *testing.T) {
+	t.Run("changes the password and signs out every session", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, passwordService, user := setupChangePasswordTest(t)
+
+		err := usecase.Execute(ctx, ChangePasswordParam{UserID: user.ID, CurrentPassword: "password123", NewPassword: "newpassword123"})
+		require.NoError(t, err)
+
+		found, err := repositories.NewUserRepository(db).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		ok, _ := passwordService.VerifyPassword(found.Password, "newpassword123")
+		assert.True(t, ok)
+		ok, _ = passwordService.VerifyPassword(found.Password, "password123")
+		assert.False(t, ok)
+		assert.Empty(t, authrepositories.NewSessionRepository(db).FindActiveByUserID(ctx, user.ID))
+	})
+
+	t.Run("returns error when current password is wrong", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, passwordService, user := setupChangePasswordTest(t)
+
+		err := usecase.Execute(ctx, ChangePasswordParam{UserID: user.ID, CurrentPassword: "wrongpassword", NewPassword: "newpassword123"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid credentials", err.Error())
+
+		found, err := repositories.NewUserRepository(db).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		ok, _ := passwordService.VerifyPassword(found.Password, "password123")
+		assert.True(t, ok)
+		assert.Len(t, authrepositories.NewSessionRepository(db).FindActiveByUserID(ctx, user.ID), 1)
+	})
+
+	t.Run("locks out after repeated wrong passwords", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, passwordService, user := setupChangePasswordTest(t)
+
+		for i := 0; i < 3; i++ {
+			err := usecase.Execute(ctx, ChangePasswordParam{UserID: user.ID, CurrentPassword: "wrongpassword", NewPassword: "newpassword123"})
+			assert.Equal(t, "invalid credentials", err.Error())
+		}
+
+		err := usecase.Execute(ctx, ChangePasswordParam{UserID: user.ID, CurrentPassword: "password123", NewPassword: "newpassword123"})
+		var retryErr *RetryAfterError
+		require.True(t, errors.As(err, &retryErr))
+		assert.Positive(t, retryErr.RetryAfter)
+
+		found, err := repositories.NewUserRepository(db).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		ok, _ := passwordService.VerifyPassword(found.Password, "password123")
+		assert.True(t, ok)
+	})
+
+	t.Run("validates new password length", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _, user := setupChangePasswordTest(t)
+
+		err := usecase.Execute(ctx, ChangePasswordParam{UserID: user.ID, CurrentPassword: "password123", NewPassword: "123"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
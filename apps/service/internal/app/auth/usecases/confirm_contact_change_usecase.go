@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ConfirmContactChangeUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewConfirmContactChangeUsecase(db *gorm.DB) *ConfirmContactChangeUsecase {
+	return &ConfirmContactChangeUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+// ConfirmContactChangeParam confirms a pending change of the email
+// (Channel "email") or phone (Channel "phone") of a user.
+type ConfirmContactChangeParam struct {
+	UserID  uint64 `validate:"required"`
+	Channel string `validate:"required,oneof=email phone"`
+	Code    string `validate:"required,len=6,numeric"`
+}
+
+type ConfirmContactChangeResult struct {
+	User *entities.User
+}
+
+func (u *ConfirmContactChangeUsecase) Execute(ctx context.Context, param ConfirmContactChangeParam) (*ConfirmContactChangeResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	changeChannel := authentities.VerificationChannelEmailChange
+	if param.Channel == authentities.VerificationChannelPhone {
+		changeChannel = authentities.VerificationChannelPhoneChange
+	}
+
+	invalidCode := errors.New("invalid or expired verification code")
+	now := time.Now()
+
+	var result *ConfirmContactChangeResult
+	var wrongCode bool
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txUserRepo := repositories.NewUserRepository(tx)
+		txCodeRepo := authrepositories.NewVerificationCodeRepository(tx)
+
+		user, err := txUserRepo.FindByID(ctx, param.UserID)
+		if err != nil {
+			return err
+		}
+
+		code, err := txCodeRepo.FindLatest(ctx, user.ID, changeChannel)
+		if err != nil || !code.IsUsable(now, maxVerificationAttempts) {
+			return invalidCode
+		}
+
+		if subtle.ConstantTimeCompare([]byte(code.CodeHash), []byte(services.HashOpaqueToken(param.Code))) != 1 {
+			code.Attempts++
+			if _, err := txCodeRepo.Update(ctx, code); err != nil {
+				return fmt.Errorf("failed to record verification attempt: %w", err)
+			}
+			wrongCode = true
+			return nil
+		}
+
+		// Someone may have registered the contact since the code was sent.
+		if changeChannel == authentities.VerificationChannelEmailChange {
+			if _, err := txUserRepo.FindByEmail(ctx, code.Target); err == nil {
+				return errors.New("user with this email already exists")
+			}
+			user.Email = code.Target
+			user.EmailVerifiedAt = &now
+		} else {
+			if _, err := txUserRepo.FindByPhone(ctx, code.Target); err == nil {
+				return errors.New("user with this phone already exists")
+			}
+			user.Phone = code.Target
+			user.PhoneVerifiedAt = &now
+		}
+
+		code.ConsumedAt = &now
+		if _, err := txCodeRepo.Update(ctx, code); err != nil {
+			return fmt.Errorf("failed to consume verification code: %w", err)
+		}
+
+		updatedUser, err := txUserRepo.Update(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to change contact: %w", err)
+		}
+
+		result = &ConfirmContactChangeResult{
+			User: &updatedUser,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// As with verification, the failed attempt is committed before the wrong
+	// code is reported.
+	if wrongCode {
+		return nil, invalidCode
+	}
+
+	return result, nil
+}
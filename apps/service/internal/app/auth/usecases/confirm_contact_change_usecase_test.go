@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+)
+
+func TestConfirmContactChangeUsecase_Execute(t *testing.T) {
+	t.Run("switches and verifies the email", func(t *testing.T) {
+		ctx := context.Background()
+		request, fixture, user := setupContactChangeTest(t)
+		usecase := NewConfirmContactChangeUsecase(fixture.db)
+
+		require.NoError(t, request.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "password123"}))
+		message, ok := fixture.mailer.LastMessageTo("new@example.com")
+		require.True(t, ok)
+
+		result, err := usecase.Execute(ctx, ConfirmContactChangeParam{UserID: user.ID, Channel: "email", Code: lastCode(t, message.Body)})
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", result.User.Email)
+		assert.NotNil(t, result.User.EmailVerifiedAt)
+
+		stored, err := fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelEmailChange)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.ConsumedAt)
+	})
+
+	t.Run("switches and verifies the phone", func(t *testing.T) {
+		ctx := context.Background()
+		request, fixture, user := setupContactChangeTest(t)
+		usecase := NewConfirmContactChangeUsecase(fixture.db)
+
+		require.NoError(t, request.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Phone: "0987654321", Password: "password123"}))
+		message, ok := fixture.smsSender.LastMessageTo("0987654321")
+		require.True(t, ok)
+
+		result, err := usecase.Execute(ctx, ConfirmContactChangeParam{UserID: user.ID, Channel: "phone", Code: lastCode(t, message.Body)})
+		require.NoError(t, err)
+		assert.Equal(t, "0987654321", result.User.Phone)
+		assert.NotNil(t, result.User.PhoneVerifiedAt)
+	})
+
+	t.Run("records attempts for wrong codes", func(t *testing.T) {
+		ctx := context.Background()
+		request, fixture, user := setupContactChangeTest(t)
+		usecase := NewConfirmContactChangeUsecase(fixture.db)
+
+		require.NoError(t, request.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "password123"}))
+		message, _ := fixture.mailer.LastMessageTo("new@example.com")
+		wrong := "000000"
+		if lastCode(t, message.Body) == wrong {
+			wrong = "111111"
+		}
+
+		_, err := usecase.Execute(ctx, ConfirmContactChangeParam{UserID: user.ID, Channel: "email", Code: wrong})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired verification code", err.Error())
+
+		stored, err := fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelEmailChange)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.Attempts)
+
+		found, err := fixture.userRepo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", found.Email)
+	})
+
+	t.Run("returns error when the contact was taken in the meantime", func(t *testing.T) {
+		ctx := context.Background()
+		request, fixture, user := setupContactChangeTest(t)
+		usecase := NewConfirmContactChangeUsecase(fixture.db)
+
+		require.NoError(t, request.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "password123"}))
+		message, _ := fixture.mailer.LastMessageTo("new@example.com")
+
+		_, err := fixture.userRepo.Create(ctx, entities.User{
+			FullName: "Jane Doe",
+			Phone:    "0987654321",
+			Email:    "new@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, ConfirmContactChangeParam{UserID: user.ID, Channel: "email", Code: lastCode(t, message.Body)})
+		assert.Error(t, err)
+		assert.Equal(t, "user with this email already exists", err.Error())
+	})
+
+	t.Run("returns error without a pending change", func(t *testing.T) {
+		ctx := context.Background()
+		_, fixture, user := setupContactChangeTest(t)
+		usecase := NewConfirmContactChangeUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, ConfirmContactChangeParam{UserID: user.ID, Channel: "email", Code: "123456"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired verification code", err.Error())
+	})
+}
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+// recoveryCodeCount is how many recovery codes are handed out when two-factor
+// authentication is enabled.
+const recoveryCodeCount = 10
+
+type ConfirmTotpUsecase struct {
+	db          *gorm.DB
+	totpService *services.TotpService
+	validator   *validator.Validate
+}
+
+func NewConfirmTotpUsecase(db *gorm.DB, totpService *services.TotpService) *ConfirmTotpUsecase {
+	return &ConfirmTotpUsecase{
+		db:          db,
+		totpService: totpService,
+		validator:   validator.New(),
+	}
+}
+
+type ConfirmTotpParam struct {
+	UserID uint64
+	Code   string `validate:"required,len=6,numeric"`
+}
+
+type ConfirmTotpResult struct {
+	// RecoveryCodes are returned in plain text only once; just their hashes
+	// are stored.
+	RecoveryCodes []string
+}
+
+func (u *ConfirmTotpUsecase) Execute(ctx context.Context, param ConfirmTotpParam) (*ConfirmTotpResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := services.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, services.HashRecoveryCode(code))
+	}
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txFactorRepo := authrepositories.NewTotpFactorRepository(tx)
+		txRecoveryCodeRepo := authrepositories.NewRecoveryCodeRepository(tx)
+
+		factor, err := txFactorRepo.FindByUserID(ctx, param.UserID)
+		if err != nil {
+			return errors.New("two-factor enrollment not started")
+		}
+		if factor.IsConfirmed() {
+			return errors.New("two-factor authentication is already enabled")
+		}
+
+		step, ok := u.totpService.Validate(factor.Secret, param.Code)
+		if !ok {
+			return errors.New("invalid two-factor code")
+		}
+
+		now := time.Now()
+		factor.ConfirmedAt = &now
+		factor.LastUsedStep = step
+		if _, err := txFactorRepo.Update(ctx, factor); err != nil {
+			return fmt.Errorf("failed to confirm two-factor enrollment: %w", err)
+		}
+
+		if err := txRecoveryCodeRepo.ReplaceForUser(ctx, param.UserID, hashes); err != nil {
+			return fmt.Errorf("failed to store recovery codes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfirmTotpResult{
+		RecoveryCodes: codes,
+	}, nil
+}
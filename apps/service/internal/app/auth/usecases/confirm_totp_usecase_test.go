@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+func TestConfirmTotpUsecase_Execute(t *testing.T) {
+	t.Run("enables the factor and returns recovery codes once", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		enroll := NewEnrollTotpUsecase(repositories.NewUserRepository(db), authrepositories.NewTotpFactorRepository(db), totpService)
+		usecase := NewConfirmTotpUsecase(db, totpService)
+
+		enrollment, err := enroll.Execute(ctx, EnrollTotpParam{UserID: user.ID})
+		require.NoError(t, err)
+		code, err := totpService.Code(enrollment.Secret)
+		require.NoError(t, err)
+
+		result, err := usecase.Execute(ctx, ConfirmTotpParam{UserID: user.ID, Code: code})
+		require.NoError(t, err)
+		assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+
+		factor, err := authrepositories.NewTotpFactorRepository(db).FindByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, factor.IsConfirmed())
+		assert.NotZero(t, factor.LastUsedStep)
+
+		recoveryCodeRepo := authrepositories.NewRecoveryCodeRepository(db)
+		assert.Equal(t, int64(recoveryCodeCount), recoveryCodeRepo.CountUnusedByUserID(ctx, user.ID))
+	})
+
+	t.Run("returns error for a wrong code", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		enroll := NewEnrollTotpUsecase(repositories.NewUserRepository(db), authrepositories.NewTotpFactorRepository(db), totpService)
+		usecase := NewConfirmTotpUsecase(db, totpService)
+
+		enrollment, err := enroll.Execute(ctx, EnrollTotpParam{UserID: user.ID})
+		require.NoError(t, err)
+		code, err := totpService.Code(enrollment.Secret)
+		require.NoError(t, err)
+
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		_, err = usecase.Execute(ctx, ConfirmTotpParam{UserID: user.ID, Code: wrong})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid two-factor code", err.Error())
+
+		factor, err := authrepositories.NewTotpFactorRepository(db).FindByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, factor.IsConfirmed())
+	})
+
+	t.Run("returns error when enrollment was not started", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		usecase := NewConfirmTotpUsecase(db, totpService)
+
+		_, err := usecase.Execute(ctx, ConfirmTotpParam{UserID: user.ID, Code: "123456"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not started")
+	})
+
+	t.Run("validates code format", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		usecase := NewConfirmTotpUsecase(db, totpService)
+
+		_, err := usecase.Execute(ctx, ConfirmTotpParam{UserID: user.ID, Code: "12ab"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
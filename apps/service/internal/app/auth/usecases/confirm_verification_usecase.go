@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+// maxVerificationAttempts is how many wrong codes are tolerated before the
+// code is burned and a new one has to be requested.
+const maxVerificationAttempts = 5
+
+type ConfirmVerificationUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewConfirmVerificationUsecase(db *gorm.DB) *ConfirmVerificationUsecase {
+	return &ConfirmVerificationUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type ConfirmVerificationParam struct {
+	Email string `validate:"omitempty,email"`
+	Phone string `validate:"omitempty,min=10,max=20"`
+	Code  string `validate:"required,len=6,numeric"`
+}
+
+type ConfirmVerificationResult struct {
+	User *entities.User
+}
+
+func (u *ConfirmVerificationUsecase) Execute(ctx context.Context, param ConfirmVerificationParam) (*ConfirmVerificationResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	if param.Email == "" && param.Phone == "" {
+		return nil, errors.New("email or phone is required")
+	}
+
+	invalidCode := errors.New("invalid or expired verification code")
+	now := time.Now()
+
+	var result *ConfirmVerificationResult
+	var wrongCode bool
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txUserRepo := repositories.NewUserRepository(tx)
+		txCodeRepo := authrepositories.NewVerificationCodeRepository(tx)
+
+		user, channel, err := findUserByContact(ctx, txUserRepo, param.Email, param.Phone)
+		if err != nil {
+			return invalidCode
+		}
+
+		code, err := txCodeRepo.FindLatest(ctx, user.ID, channel)
+		if err != nil || !code.IsUsable(now, maxVerificationAttempts) || code.Target != contactTarget(user, channel) {
+			return invalidCode
+		}
+
+		if subtle.ConstantTimeCompare([]byte(code.CodeHash), []byte(services.HashOpaqueToken(param.Code))) != 1 {
+			code.Attempts++
+			if _, err := txCodeRepo.Update(ctx, code); err != nil {
+				return fmt.Errorf("failed to record verification attempt: %w", err)
+			}
+			wrongCode = true
+			return nil
+		}
+
+		code.ConsumedAt = &now
+		if _, err := txCodeRepo.Update(ctx, code); err != nil {
+			return fmt.Errorf("failed to consume verification code: %w", err)
+		}
+
+		if channel == authentities.VerificationChannelEmail {
+			user.EmailVerifiedAt = &now
+		} else {
+			user.PhoneVerifiedAt = &now
+		}
+
+		updatedUser, err := txUserRepo.Update(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to mark contact as verified: %w", err)
+		}
+
+		result = &ConfirmVerificationResult{
+			User: &updatedUser,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The failed attempt is committed before reporting the wrong code, so
+	// guesses count against the attempt limit.
+	if wrongCode {
+		return nil, invalidCode
+	}
+
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+func TestConfirmVerificationUsecase_Execute(t *testing.T) {
+	t.Run("marks the email as verified", func(t *testing.T) {
+		ctx := context.Background()
+		sendUsecase, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+		createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, sendUsecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+		message, _ := fixture.mailer.LastMessageTo("john@example.com")
+
+		result, err := usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: lastCode(t, message.Body)})
+		require.NoError(t, err)
+		assert.NotNil(t, result.User.EmailVerifiedAt)
+		assert.Nil(t, result.User.PhoneVerifiedAt)
+		assert.True(t, result.User.IsVerified())
+	})
+
+	t.Run("marks the phone as verified", func(t *testing.T) {
+		ctx := context.Background()
+		sendUsecase, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+		createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, sendUsecase.Execute(ctx, SendVerificationParam{Phone: "1234567890"}))
+		message, _ := fixture.smsSender.LastMessageTo("1234567890")
+
+		result, err := usecase.Execute(ctx, ConfirmVerificationParam{Phone: "1234567890", Code: lastCode(t, message.Body)})
+		require.NoError(t, err)
+		assert.NotNil(t, result.User.PhoneVerifiedAt)
+	})
+
+	t.Run("rejects a code that was already used", func(t *testing.T) {
+		ctx := context.Background()
+		sendUsecase, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+		createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, sendUsecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+		message, _ := fixture.mailer.LastMessageTo("john@example.com")
+		code := lastCode(t, message.Body)
+
+		_, err := usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: code})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: code})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired verification code", err.Error())
+	})
+
+	t.Run("burns the code after too many wrong attempts", func(t *testing.T) {
+		ctx := context.Background()
+		sendUsecase, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+		user := createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, sendUsecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+		message, _ := fixture.mailer.LastMessageTo("john@example.com")
+		code := lastCode(t, message.Body)
+
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+		for i := 0; i < maxVerificationAttempts; i++ {
+			_, err := usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: wrong})
+			assert.Error(t, err)
+		}
+
+		stored, err := fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelEmail)
+		require.NoError(t, err)
+		assert.Equal(t, maxVerificationAttempts, stored.Attempts)
+
+		_, err = usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: code})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired verification code", err.Error())
+	})
+
+	t.Run("rejects an expired code", func(t *testing.T) {
+		ctx := context.Background()
+		sendUsecase, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+		user := createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, sendUsecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+		message, _ := fixture.mailer.LastMessageTo("john@example.com")
+
+		stored, err := fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelEmail)
+		require.NoError(t, err)
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+		_, err = fixture.codeRepo.Update(ctx, stored)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: lastCode(t, message.Body)})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired verification code", err.Error())
+	})
+
+	t.Run("rejects a code issued for a previous address", func(t *testing.T) {
+		ctx := context.Background()
+		sendUsecase, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+		user := createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, sendUsecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+		message, _ := fixture.mailer.LastMessageTo("john@example.com")
+
+		user.Email = "johnny@example.com"
+		_, err := fixture.userRepo.Update(ctx, user)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, ConfirmVerificationParam{Email: "johnny@example.com", Code: lastCode(t, message.Body)})
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error when code is malformed", func(t *testing.T) {
+		ctx := context.Background()
+		_, fixture := setupVerificationTest(t, 0)
+		usecase := NewConfirmVerificationUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, ConfirmVerificationParam{Email: "john@example.com", Code: "abc"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
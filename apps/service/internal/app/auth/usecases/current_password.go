@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+)
+
+// checkCurrentPassword confirms the password of a signed in user before a
+// sensitive change. Wrong guesses are throttled per user like failed logins,
+// so a stolen access token cannot be used to guess the password.
+func checkCurrentPassword(ctx context.Context, loginThrottle *services.LoginThrottleService, passwordService *services.PasswordService, user entities.User, password string) error {
+	identifier := PasswordThrottleIdentifier(user.ID)
+	if wait := loginThrottle.Check(ctx, identifier, ""); wait > 0 {
+		return &RetryAfterError{
+			Message:    "too many failed password attempts, try again later",
+			RetryAfter: wait,
+		}
+	}
+
+	if ok, _ := passwordService.VerifyPassword(user.Password, password); !ok {
+		if err := loginThrottle.RecordFailure(ctx, identifier, ""); err != nil {
+			return err
+		}
+		return errors.New("invalid credentials")
+	}
+
+	return loginThrottle.Reset(ctx, identifier)
+}
+
+// PasswordThrottleIdentifier is the identifier failed current password
+// checks of a user are counted under.
+func PasswordThrottleIdentifier(userID uint64) string {
+	return fmt.Sprintf("password:%d", userID)
+}
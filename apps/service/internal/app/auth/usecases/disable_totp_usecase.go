@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type DisableTotpUsecase struct {
+	db              *gorm.DB
+	passwordService *services.PasswordService
+	totpService     *services.TotpService
+	validator       *validator.Validate
+}
+
+func NewDisableTotpUsecase(db *gorm.DB, passwordService *services.PasswordService, totpService *services.TotpService) *DisableTotpUsecase {
+	return &DisableTotpUsecase{
+		db:              db,
+		passwordService: passwordService,
+		totpService:     totpService,
+		validator:       validator.New(),
+	}
+}
+
+// DisableTotpParam requires both the password and a current code, so a stolen
+// access token alone cannot switch the second factor off.
+type DisableTotpParam struct {
+	UserID   uint64
+	Password string `validate:"required"`
+	Code     string `validate:"required,len=6,numeric"`
+}
+
+func (u *DisableTotpUsecase) Execute(ctx context.Context, param DisableTotpParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txUserRepo := repositories.NewUserRepository(tx)
+		txFactorRepo := authrepositories.NewTotpFactorRepository(tx)
+		txRecoveryCodeRepo := authrepositories.NewRecoveryCodeRepository(tx)
+
+		user, err := txUserRepo.FindByID(ctx, param.UserID)
+		if err != nil {
+			return err
+		}
+		if ok, _ := u.passwordService.VerifyPassword(user.Password, param.Password); !ok {
+			return errors.New("invalid credentials")
+		}
+
+		factor, err := txFactorRepo.FindByUserID(ctx, user.ID)
+		if err != nil || !factor.IsConfirmed() {
+			return errors.New("two-factor authentication is not enabled")
+		}
+
+		if _, ok := u.totpService.Validate(factor.Secret, param.Code); !ok {
+			return errors.New("invalid two-factor code")
+		}
+
+		if err := txFactorRepo.Delete(ctx, factor); err != nil {
+			return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+		}
+
+		return txRecoveryCodeRepo.DeleteByUserID(ctx, user.ID)
+	})
+}
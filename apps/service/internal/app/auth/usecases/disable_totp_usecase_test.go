@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+func setupDisableTotpTest(t *testing.T) (*DisableTotpUsecase, *services.TotpService, context.Context, uint64, string) {
+	ctx := context.Background()
+	db, totpService, user := setupTotpTest(t)
+	passwordService := newTestPasswordService(t)
+
+	hashedPassword, err := passwordService.HashPassword("password123")
+	require.NoError(t, err)
+	user.Password = hashedPassword
+	_, err = repositories.NewUserRepository(db).Update(ctx, user)
+	require.NoError(t, err)
+
+	secret, _ := createConfirmedTotpFactor(t, ctx, db, totpService, user.ID)
+
+	return NewDisableTotpUsecase(db, passwordService, totpService), totpService, ctx, user.ID, secret
+}
+
+func TestDisableTotpUsecase_Execute(t *testing.T) {
+	t.Run("removes the factor and recovery codes", func(t *testing.T) {
+		usecase, totpService, ctx, userID, secret := setupDisableTotpTest(t)
+
+		code, err := totpService.Code(secret)
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, DisableTotpParam{UserID: userID, Password: "password123", Code: code})
+		require.NoError(t, err)
+
+		_, err = authrepositories.NewTotpFactorRepository(usecase.db).FindByUserID(ctx, userID)
+		assert.Error(t, err)
+		assert.Equal(t, int64(0), authrepositories.NewRecoveryCodeRepository(usecase.db).CountUnusedByUserID(ctx, userID))
+	})
+
+	t.Run("returns error when password is wrong", func(t *testing.T) {
+		usecase, totpService, ctx, userID, secret := setupDisableTotpTest(t)
+
+		code, err := totpService.Code(secret)
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, DisableTotpParam{UserID: userID, Password: "wrongpassword", Code: code})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid credentials", err.Error())
+
+		_, err = authrepositories.NewTotpFactorRepository(usecase.db).FindByUserID(ctx, userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error when code is wrong", func(t *testing.T) {
+		usecase, totpService, ctx, userID, secret := setupDisableTotpTest(t)
+
+		code, err := totpService.Code(secret)
+		require.NoError(t, err)
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		err = usecase.Execute(ctx, DisableTotpParam{UserID: userID, Password: "password123", Code: wrong})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid two-factor code", err.Error())
+	})
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+type EnrollTotpUsecase struct {
+	userRepository       repositories.UserRepository
+	totpFactorRepository authrepositories.TotpFactorRepository
+	totpService          *services.TotpService
+}
+
+func NewEnrollTotpUsecase(userRepository repositories.UserRepository, totpFactorRepository authrepositories.TotpFactorRepository, totpService *services.TotpService) *EnrollTotpUsecase {
+	return &EnrollTotpUsecase{
+		userRepository:       userRepository,
+		totpFactorRepository: totpFactorRepository,
+		totpService:          totpService,
+	}
+}
+
+type EnrollTotpParam struct {
+	UserID uint64
+}
+
+type EnrollTotpResult struct {
+	Secret string
+	URI    string
+}
+
+// Execute starts (or restarts) an enrollment with a fresh secret. The factor
+// stays inactive until it is confirmed with a code from the authenticator.
+func (u *EnrollTotpUsecase) Execute(ctx context.Context, param EnrollTotpParam) (*EnrollTotpResult, error) {
+	user, err := u.userRepository.FindByID(ctx, param.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := u.totpService.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	factor, err := u.totpFactorRepository.FindByUserID(ctx, user.ID)
+	if err == nil {
+		if factor.IsConfirmed() {
+			return nil, errors.New("two-factor authentication is already enabled")
+		}
+
+		factor.Secret = secret
+		if _, err := u.totpFactorRepository.Update(ctx, factor); err != nil {
+			return nil, fmt.Errorf("failed to update two-factor enrollment: %w", err)
+		}
+	} else {
+		_, err = u.totpFactorRepository.Create(ctx, authentities.TotpFactor{
+			UserID: user.ID,
+			Secret: secret,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create two-factor enrollment: %w", err)
+		}
+	}
+
+	accountName := user.Email
+	if accountName == "" {
+		accountName = user.Phone
+	}
+
+	return &EnrollTotpResult{
+		Secret: secret,
+		URI:    u.totpService.URI(secret, accountName),
+	}, nil
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupTotpTest(t *testing.T) (*gorm.DB, *services.TotpService, entities.User) {
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.Session{}, &authentities.TotpFactor{}, &authentities.RecoveryCode{})
+
+	user, err := repositories.NewUserRepository(db).Create(context.Background(), entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: "hashedpassword",
+	})
+	require.NoError(t, err)
+
+	return db, services.NewTotpService("Weiss"), user
+}
+
+// createConfirmedTotpFactor enables two-factor authentication for the user
+// directly in the store and returns the secret and the recovery codes.
+func createConfirmedTotpFactor(t *testing.T, ctx context.Context, db *gorm.DB, totpService *services.TotpService, userID uint64) (string, []string) {
+	secret, err := totpService.GenerateSecret()
+	require.NoError(t, err)
+
+	confirmedAt := time.Now()
+	_, err = authrepositories.NewTotpFactorRepository(db).Create(ctx, authentities.TotpFactor{
+		UserID:      userID,
+		Secret:      secret,
+		ConfirmedAt: &confirmedAt,
+	})
+	require.NoError(t, err)
+
+	var codes, hashes []string
+	for i := 0; i < 3; i++ {
+		code, err := services.GenerateRecoveryCode()
+		require.NoError(t, err)
+		codes = append(codes, code)
+		hashes = append(hashes, services.HashRecoveryCode(code))
+	}
+	require.NoError(t, authrepositories.NewRecoveryCodeRepository(db).ReplaceForUser(ctx, userID, hashes))
+
+	return secret, codes
+}
+
+func TestEnrollTotpUsecase_Execute(t *testing.T) {
+	t.Run("returns a secret and otpauth URI", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		usecase := NewEnrollTotpUsecase(repositories.NewUserRepository(db), authrepositories.NewTotpFactorRepository(db), totpService)
+
+		result, err := usecase.Execute(ctx, EnrollTotpParam{UserID: user.ID})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Secret)
+
+		uri, err := url.Parse(result.URI)
+		require.NoError(t, err)
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, result.Secret, uri.Query().Get("secret"))
+		assert.Contains(t, uri.Path, "john@example.com")
+
+		factor, err := authrepositories.NewTotpFactorRepository(db).FindByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, factor.IsConfirmed())
+	})
+
+	t.Run("replaces the secret of an unconfirmed enrollment", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		usecase := NewEnrollTotpUsecase(repositories.NewUserRepository(db), authrepositories.NewTotpFactorRepository(db), totpService)
+
+		first, err := usecase.Execute(ctx, EnrollTotpParam{UserID: user.ID})
+		require.NoError(t, err)
+		second, err := usecase.Execute(ctx, EnrollTotpParam{UserID: user.ID})
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Secret, second.Secret)
+
+		factor, err := authrepositories.NewTotpFactorRepository(db).FindByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, second.Secret, factor.Secret)
+	})
+
+	t.Run("returns error when already enabled", func(t *testing.T) {
+		ctx := context.Background()
+		db, totpService, user := setupTotpTest(t)
+		usecase := NewEnrollTotpUsecase(repositories.NewUserRepository(db), authrepositories.NewTotpFactorRepository(db), totpService)
+
+		createConfirmedTotpFactor(t, ctx, db, totpService, user.ID)
+
+		_, err := usecase.Execute(ctx, EnrollTotpParam{UserID: user.ID})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "already enabled")
+	})
+}
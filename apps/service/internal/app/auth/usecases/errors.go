@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"time"
+)
+
+// RetryAfterError is returned when an action is throttled. RetryAfter tells
+// the caller how long to wait before trying again.
+type RetryAfterError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ForgotPasswordUsecase struct {
+	userRepository          repositories.UserRepository
+	passwordResetRepository authrepositories.PasswordResetRepository
+	mailer                  mail.Mailer
+	expiresIn               time.Duration
+	resetURL                string
+	validator               *validator.Validate
+}
+
+func NewForgotPasswordUsecase(userRepository repositories.UserRepository, passwordResetRepository authrepositories.PasswordResetRepository, mailer mail.Mailer, expiresIn time.Duration, resetURL string) *ForgotPasswordUsecase {
+	return &ForgotPasswordUsecase{
+		userRepository:          userRepository,
+		passwordResetRepository: passwordResetRepository,
+		mailer:                  mailer,
+		expiresIn:               expiresIn,
+		resetURL:                resetURL,
+		validator:               validator.New(),
+	}
+}
+
+type ForgotPasswordParam struct {
+	Email string `validate:"required,email"`
+}
+
+// Execute issues a reset token and mails it to the user. It succeeds silently
+// for unknown addresses, and when the email cannot be sent, so that the
+// endpoint cannot be used to discover which emails are registered.
+func (u *ForgotPasswordUsecase) Execute(ctx context.Context, param ForgotPasswordParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	user, err := u.userRepository.FindByEmail(ctx, param.Email)
+	if err != nil {
+		return nil
+	}
+
+	token, tokenHash, err := services.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	// Only the most recently requested link works.
+	now := time.Now()
+	if err := u.passwordResetRepository.InvalidateByUserID(ctx, user.ID, now); err != nil {
+		return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+	}
+
+	_, err = u.passwordResetRepository.Create(ctx, authentities.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(u.expiresIn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	err = u.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    u.buildBody(user.FullName, token),
+	})
+	if err != nil {
+		// Failing here would tell the caller that the address is registered.
+		log.Printf("failed to send password reset email to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+func (u *ForgotPasswordUsecase) buildBody(fullName, token string) string {
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "Hi %s,\n\n", fullName)
+	body.WriteString("We received a request to reset your password.\n\n")
+	if u.resetURL != "" {
+		fmt.Fprintf(&body, "Open the link below to choose a new password:\n%s?token=%s\n\n", u.resetURL, url.QueryEscape(token))
+	} else {
+		fmt.Fprintf(&body, "Use the following token to choose a new password:\n%s\n\n", token)
+	}
+	fmt.Fprintf(&body, "The link expires in %s and can be used once. If you did not request a reset, you can ignore this email.\n", u.expiresIn)
+
+	return body.String()
+}
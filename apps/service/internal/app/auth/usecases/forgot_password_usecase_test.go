@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupForgotPasswordTest(t *testing.T) (*ForgotPasswordUsecase, repositories.UserRepository, authrepositories.PasswordResetRepository, *mail.MemoryMailer) {
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.PasswordReset{})
+	userRepo := repositories.NewUserRepository(db)
+	resetRepo := authrepositories.NewPasswordResetRepository(db)
+	mailer := mail.NewMemoryMailer()
+
+	usecase := NewForgotPasswordUsecase(userRepo, resetRepo, mailer, time.Hour, "https://app.example.com/reset-password")
+
+	return usecase, userRepo, resetRepo, mailer
+}
+
+// resetTokenFromMessage extracts the token from the link in a reset email.
+func resetTokenFromMessage(t *testing.T, message mail.Message) string {
+	const marker = "?token="
+	start := strings.Index(message.Body, marker)
+	require.NotEqual(t, -1, start, "reset link not found in email body")
+
+	rest := message.Body[start+len(marker):]
+	end := strings.IndexAny(rest, "\n ")
+	if end == -1 {
+		end = len(rest)
+	}
+	return rest[:end]
+}
+
+// failingMailer fails every delivery, as when the mail server is down.
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, message mail.Message) error {
+	return errors.New("connection refused")
+}
+
+func TestForgotPasswordUsecase_Execute(t *testing.T) {
+	t.Run("sends a reset link to the user", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, resetRepo, mailer := setupForgotPasswordTest(t)
+
+		user, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, ForgotPasswordParam{Email: "john@example.com"})
+		require.NoError(t, err)
+
+		message, ok := mailer.LastMessageTo("john@example.com")
+		require.True(t, ok)
+		assert.Contains(t, message.Body, "https://app.example.com/reset-password?token=")
+
+		token := resetTokenFromMessage(t, message)
+		reset, err := resetRepo.FindByTokenHash(ctx, services.HashOpaqueToken(token))
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, reset.UserID)
+		assert.True(t, reset.IsUsable(time.Now()))
+		assert.NotEqual(t, token, reset.TokenHash)
+	})
+
+	t.Run("succeeds silently for unknown email", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _, mailer := setupForgotPasswordTest(t)
+
+		err := usecase.Execute(ctx, ForgotPasswordParam{Email: "nobody@example.com"})
+		require.NoError(t, err)
+		assert.Empty(t, mailer.Messages())
+	})
+
+	t.Run("succeeds silently when the email cannot be sent", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, _, _ := setupForgotPasswordTest(t)
+		usecase.mailer = failingMailer{}
+
+		_, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, ForgotPasswordParam{Email: "john@example.com"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalidates previously issued tokens", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, resetRepo, mailer := setupForgotPasswordTest(t)
+
+		_, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, usecase.Execute(ctx, ForgotPasswordParam{Email: "john@example.com"}))
+		first := resetTokenFromMessage(t, mailer.Messages()[0])
+
+		require.NoError(t, usecase.Execute(ctx, ForgotPasswordParam{Email: "john@example.com"}))
+		second := resetTokenFromMessage(t, mailer.Messages()[1])
+
+		firstReset, err := resetRepo.FindByTokenHash(ctx, services.HashOpaqueToken(first))
+		require.NoError(t, err)
+		assert.False(t, firstReset.IsUsable(time.Now()))
+
+		secondReset, err := resetRepo.FindByTokenHash(ctx, services.HashOpaqueToken(second))
+		require.NoError(t, err)
+		assert.True(t, secondReset.IsUsable(time.Now()))
+	})
+
+	t.Run("returns error when email is invalid", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _, _ := setupForgotPasswordTest(t)
+
+		err := usecase.Execute(ctx, ForgotPasswordParam{Email: "not-an-email"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"context"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+)
+
+type ListSessionsUsecase struct {
+	sessionRepository authrepositories.SessionRepository
+}
+
+func NewListSessionsUsecase(sessionRepository authrepositories.SessionRepository) *ListSessionsUsecase {
+	return &ListSessionsUsecase{
+		sessionRepository: sessionRepository,
+	}
+}
+
+type ListSessionsResult struct {
+	Sessions []authentities.Session
+}
+
+func (u *ListSessionsUsecase) Execute(ctx context.Context, userID uint64) *ListSessionsResult {
+	sessions := u.sessionRepository.FindActiveByUserID(ctx, userID)
+	return &ListSessionsResult{
+		Sessions: sessions,
+	}
+}
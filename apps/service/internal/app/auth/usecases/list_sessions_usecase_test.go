@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupSessionsTest(t *testing.T) authrepositories.SessionRepository {
+	db := testutil.SetupTestDB(t, &authentities.Session{})
+	return authrepositories.NewSessionRepository(db)
+}
+
+func createTestSession(t *testing.T, ctx context.Context, sessionRepo authrepositories.SessionRepository, userID uint64, tokenID string) authentities.Session {
+	session, err := sessionRepo.Create(ctx, authentities.Session{
+		UserID:     userID,
+		TokenID:    tokenID,
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastUsedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func TestListSessionsUsecase_Execute(t *testing.T) {
+	t.Run("returns active sessions of the user", func(t *testing.T) {
+		ctx := context.Background()
+		sessionRepo := setupSessionsTest(t)
+		usecase := NewListSessionsUsecase(sessionRepo)
+
+		createTestSession(t, ctx, sessionRepo, 1, "token-1")
+		createTestSession(t, ctx, sessionRepo, 1, "token-2")
+		createTestSession(t, ctx, sessionRepo, 2, "token-3")
+
+		result := usecase.Execute(ctx, 1)
+		assert.Len(t, result.Sessions, 2)
+	})
+
+	t.Run("returns empty list when user has no sessions", func(t *testing.T) {
+		ctx := context.Background()
+		sessionRepo := setupSessionsTest(t)
+		usecase := NewListSessionsUsecase(sessionRepo)
+
+		result := usecase.Execute(ctx, 1)
+		assert.Empty(t, result.Sessions)
+	})
+}
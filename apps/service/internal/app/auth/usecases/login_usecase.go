@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
@@ -14,31 +15,47 @@ import (
 )
 
 type LoginUsecase struct {
-	userRepository  repositories.UserRepository
-	tokenService    *services.TokenService
-	passwordService *services.PasswordService
-	validator       *validator.Validate
+	userRepository       repositories.UserRepository
+	sessionRepository    authrepositories.SessionRepository
+	refreshTokenStore    authrepositories.RefreshTokenStore
+	totpFactorRepository authrepositories.TotpFactorRepository
+	tokenService         *services.TokenService
+	passwordService      *services.PasswordService
+	loginThrottle        *services.LoginThrottleService
+	requireVerified      bool
+	validator            *validator.Validate
 }
 
-func NewLoginUsecase(userRepository repositories.UserRepository, tokenService *services.TokenService, passwordService *services.PasswordService) *LoginUsecase {
+func NewLoginUsecase(userRepository repositories.UserRepository, sessionRepository authrepositories.SessionRepository, refreshTokenStore authrepositories.RefreshTokenStore, totpFactorRepository authrepositories.TotpFactorRepository, tokenService *services.TokenService, passwordService *services.PasswordService, loginThrottle *services.LoginThrottleService, requireVerified bool) *LoginUsecase {
 	return &LoginUsecase{
-		userRepository:  userRepository,
-		tokenService:    tokenService,
-		passwordService: passwordService,
-		validator:       validator.New(),
+		userRepository:       userRepository,
+		sessionRepository:    sessionRepository,
+		refreshTokenStore:    refreshTokenStore,
+		totpFactorRepository: totpFactorRepository,
+		tokenService:         tokenService,
+		passwordService:      passwordService,
+		loginThrottle:        loginThrottle,
+		requireVerified:      requireVerified,
+		validator:            validator.New(),
 	}
 }
 
 type LoginParam struct {
-	Email    string `validate:"omitempty,email"`
-	Phone    string `validate:"omitempty,min=10,max=20"`
-	Password string `validate:"required,min=1"`
+	Email     string `validate:"omitempty,email"`
+	Phone     string `validate:"omitempty,min=10,max=20"`
+	Password  string `validate:"required,min=1"`
+	UserAgent string
+	IPAddress string
 }
 
+// LoginResult carries either the issued tokens or, when MfaRequired is set,
+// only the MFA challenge token.
 type LoginResult struct {
 	User         *entities.User
 	AccessToken  string
 	RefreshToken string
+	MfaRequired  bool
+	MfaToken     string
 }
 
 func (u *LoginUsecase) Execute(ctx context.Context, param LoginParam) (*LoginResult, error) {
@@ -54,30 +71,88 @@ func (u *LoginUsecase) Execute(ctx context.Context, param LoginParam) (*LoginRes
 		return nil, errors.New("email or phone is required")
 	}
 
+	identifier := param.Email
+	if identifier == "" {
+		identifier = param.Phone
+	}
+
+	if wait := u.loginThrottle.Check(ctx, identifier, param.IPAddress); wait > 0 {
+		return nil, &RetryAfterError{
+			Message:    "too many failed login attempts, try again later",
+			RetryAfter: wait,
+		}
+	}
+
 	var user entities.User
 	var err error
 
 	if param.Email != "" {
 		user, err = u.userRepository.FindByEmail(ctx, param.Email)
-		if err != nil {
-			return nil, errors.New("invalid credentials")
-		}
-	} else if param.Phone != "" {
-		user, err = u.userRepository.FindByPhone(ctx, param.Phone)
-		if err != nil {
-			return nil, errors.New("invalid credentials")
-		}
 	} else {
-		return nil, errors.New("email or phone is required")
+		user, err = u.userRepository.FindByPhone(ctx, param.Phone)
 	}
 
-	if !u.passwordService.VerifyPassword(user.Password, param.Password) {
+	var verified, needsRehash bool
+	if err == nil {
+		verified, needsRehash = u.passwordService.VerifyPassword(user.Password, param.Password)
+	}
+
+	// Unknown accounts count as failures too, so the throttle does not reveal
+	// which identifiers exist.
+	if !verified {
+		if err := u.loginThrottle.RecordFailure(ctx, identifier, param.IPAddress); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid credentials")
 	}
 
-	tokenPair, err := u.tokenService.GenerateTokenPair(user.ID, user.Email, user.Phone)
+	if err := u.loginThrottle.Reset(ctx, identifier); err != nil {
+		return nil, err
+	}
+
+	// The plaintext is only at hand here, so hashes made with an older
+	// algorithm or cost are upgraded now. The login does not depend on it.
+	if needsRehash {
+		if hashedPassword, err := u.passwordService.HashPassword(param.Password); err == nil {
+			if replaced, err := u.userRepository.ReplacePasswordHash(ctx, user.ID, user.Password, hashedPassword); err == nil && replaced {
+				user.Password = hashedPassword
+			}
+		}
+	}
+
+	// Only revealed to callers who know the password.
+	if user.IsSuspended() {
+		return nil, errors.New("account suspended: contact support")
+	}
+
+	if u.requireVerified && !user.IsVerified() {
+		return nil, errors.New("account not verified: verify your email or phone before logging in")
+	}
+
+	// With a confirmed second factor the password alone is not enough: the
+	// caller gets a short-lived challenge token to exchange together with a
+	// code at the MFA verification step. A factor that cannot be loaded fails
+	// the login rather than letting the password through on its own.
+	factor, err := u.totpFactorRepository.FindByUserID(ctx, user.ID)
+	if err != nil && err.Error() != "totp factor not found" {
+		return nil, fmt.Errorf("failed to load second factor: %w", err)
+	}
+	if err == nil && factor.IsConfirmed() {
+		mfaToken, err := u.tokenService.GenerateMfaToken(user.ID, user.Email, user.Phone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate MFA token: %w", err)
+		}
+
+		return &LoginResult{
+			User:        &user,
+			MfaRequired: true,
+			MfaToken:    mfaToken,
+		}, nil
+	}
+
+	tokenPair, err := startSession(ctx, u.sessionRepository, u.refreshTokenStore, u.tokenService, user, param.UserAgent, param.IPAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, err
 	}
 
 	return &LoginResult{
@@ -2,12 +2,16 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
@@ -15,9 +19,28 @@ import (
 	"github.com/reno1r/weiss/apps/service/internal/testutil"
 )
 
-func setupLoginTest(t *testing.T) (*LoginUsecase, repositories.UserRepository) {
-	db := testutil.SetupTestDB(t, &entities.User{})
+func newTestPasswordService(t *testing.T) *services.PasswordService {
+	passwordService, err := services.NewPasswordService(&config.Config{BcryptCost: bcrypt.MinCost})
+	require.NoError(t, err)
+	return passwordService
+}
+
+func newTestLoginThrottle() *services.LoginThrottleService {
+	return services.NewLoginThrottleService(authrepositories.NewInMemoryLoginAttemptStore(), services.LoginThrottlePolicy{
+		MaxAttempts:        3,
+		MaxAttemptsPerIP:   10,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: 15 * time.Minute,
+		Window:             time.Hour,
+	})
+}
+
+func setupLoginTest(t *testing.T) (*LoginUsecase, repositories.UserRepository, authrepositories.SessionRepository, authrepositories.RefreshTokenStore) {
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.Session{}, &authentities.TotpFactor{})
 	userRepo := repositories.NewUserRepository(db)
+	sessionRepo := authrepositories.NewSessionRepository(db)
+	refreshTokenStore := authrepositories.NewInMemoryRefreshTokenStore()
+	totpFactorRepo := authrepositories.NewTotpFactorRepository(db)
 
 	config := &config.Config{
 		BcryptCost:        bcrypt.MinCost,
@@ -27,22 +50,32 @@ func setupLoginTest(t *testing.T) (*LoginUsecase, repositories.UserRepository) {
 		JwtRefreshExpires: "168h",
 	}
 
-	passwordService := services.NewPasswordService(config)
+	passwordService := newTestPasswordService(t)
 	tokenService, err := services.NewTokenService(config)
 	require.NoError(t, err)
 
-	loginUsecase := NewLoginUsecase(userRepo, tokenService, passwordService)
+	loginUsecase := NewLoginUsecase(userRepo, sessionRepo, refreshTokenStore, totpFactorRepo, tokenService, passwordService, newTestLoginThrottle(), false)
+
+	return loginUsecase, userRepo, sessionRepo, refreshTokenStore
+}
+
+// failingTotpFactorRepository fails every factor lookup, as when the
+// database is unavailable.
+type failingTotpFactorRepository struct {
+	authrepositories.TotpFactorRepository
+}
 
-	return loginUsecase, userRepo
+func (failingTotpFactorRepository) FindByUserID(ctx context.Context, userID uint64) (authentities.TotpFactor, error) {
+	return authentities.TotpFactor{}, errors.New("connection refused")
 }
 
 func TestLoginUsecase_Execute(t *testing.T) {
 	t.Run("logs in successfully with email", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo := setupLoginTest(t)
+		usecase, userRepo, _, _ := setupLoginTest(t)
 
 		password := "password123"
-		hashedPassword, err := services.NewPasswordService(&config.Config{BcryptCost: bcrypt.MinCost}).HashPassword(password)
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
 		require.NoError(t, err)
 
 		user := entities.User{
@@ -68,12 +101,41 @@ func TestLoginUsecase_Execute(t *testing.T) {
 		assert.NotEqual(t, resp.AccessToken, resp.RefreshToken)
 	})
 
+	t.Run("upgrades outdated password hashes", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, _, _ := setupLoginTest(t)
+
+		outdated, err := services.NewBcryptHasher(bcrypt.MinCost + 1).Hash("password123")
+		require.NoError(t, err)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: outdated,
+		})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: "password123"})
+		require.NoError(t, err)
+
+		found, err := userRepo.FindByID(ctx, createdUser.ID)
+		require.NoError(t, err)
+		cost, err := bcrypt.Cost([]byte(found.Password))
+		require.NoError(t, err)
+		assert.Equal(t, bcrypt.MinCost, cost)
+
+		ok, needsRehash := newTestPasswordService(t).VerifyPassword(found.Password, "password123")
+		assert.True(t, ok)
+		assert.False(t, needsRehash)
+	})
+
 	t.Run("logs in successfully with phone", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo := setupLoginTest(t)
+		usecase, userRepo, _, _ := setupLoginTest(t)
 
 		password := "password123"
-		hashedPassword, err := services.NewPasswordService(&config.Config{BcryptCost: bcrypt.MinCost}).HashPassword(password)
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
 		require.NoError(t, err)
 
 		user := entities.User{
@@ -99,7 +161,7 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when email not found", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _ := setupLoginTest(t)
+		usecase, _, _, _ := setupLoginTest(t)
 
 		param := LoginParam{
 			Email:    "notfound@example.com",
@@ -114,7 +176,7 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when phone not found", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _ := setupLoginTest(t)
+		usecase, _, _, _ := setupLoginTest(t)
 
 		param := LoginParam{
 			Phone:    "9999999999",
@@ -129,10 +191,10 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when password is incorrect", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo := setupLoginTest(t)
+		usecase, userRepo, _, _ := setupLoginTest(t)
 
 		password := "password123"
-		hashedPassword, err := services.NewPasswordService(&config.Config{BcryptCost: bcrypt.MinCost}).HashPassword(password)
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
 		require.NoError(t, err)
 
 		user := entities.User{
@@ -157,7 +219,7 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("validates password is required", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _ := setupLoginTest(t)
+		usecase, _, _, _ := setupLoginTest(t)
 
 		param := LoginParam{
 			Email:    "john@example.com",
@@ -172,7 +234,7 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("validates email format when provided", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _ := setupLoginTest(t)
+		usecase, _, _, _ := setupLoginTest(t)
 
 		param := LoginParam{
 			Email:    "invalid-email",
@@ -187,7 +249,7 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("validates phone length when provided", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _ := setupLoginTest(t)
+		usecase, _, _, _ := setupLoginTest(t)
 
 		param := LoginParam{
 			Phone:    "123",
@@ -202,7 +264,7 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when both email and phone are empty", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _ := setupLoginTest(t)
+		usecase, _, _, _ := setupLoginTest(t)
 
 		param := LoginParam{
 			Email:    "",
@@ -218,10 +280,10 @@ func TestLoginUsecase_Execute(t *testing.T) {
 
 	t.Run("generates valid tokens", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo := setupLoginTest(t)
+		usecase, userRepo, _, _ := setupLoginTest(t)
 
 		password := "password123"
-		hashedPassword, err := services.NewPasswordService(&config.Config{BcryptCost: bcrypt.MinCost}).HashPassword(password)
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
 		require.NoError(t, err)
 
 		user := entities.User{
@@ -261,4 +323,241 @@ func TestLoginUsecase_Execute(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "refresh", refreshClaims.Type)
 	})
+
+	t.Run("creates a session for the refresh token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, sessionRepo, refreshTokenStore := setupLoginTest(t)
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: hashedPassword,
+		})
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, LoginParam{
+			Email:     "john@example.com",
+			Password:  password,
+			UserAgent: "test-agent",
+			IPAddress: "127.0.0.1",
+		})
+		require.NoError(t, err)
+
+		sessions := sessionRepo.FindActiveByUserID(ctx, createdUser.ID)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "test-agent", sessions[0].UserAgent)
+		assert.Equal(t, "127.0.0.1", sessions[0].IPAddress)
+		assert.NotEmpty(t, resp.RefreshToken)
+
+		storedToken, err := refreshTokenStore.FindByTokenID(ctx, sessions[0].TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, sessions[0].ID, storedToken.FamilyID)
+		assert.Equal(t, createdUser.ID, storedToken.UserID)
+		assert.False(t, storedToken.IsRotated())
+	})
+
+	t.Run("rejects unverified users when verification is required", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, sessionRepo, refreshTokenStore := setupLoginTest(t)
+		usecase = NewLoginUsecase(userRepo, sessionRepo, refreshTokenStore, usecase.totpFactorRepository, usecase.tokenService, usecase.passwordService, usecase.loginThrottle, true)
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: hashedPassword,
+		})
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "account not verified")
+		assert.Nil(t, resp)
+
+		verifiedAt := time.Now()
+		createdUser.PhoneVerifiedAt = &verifiedAt
+		_, err = userRepo.Update(ctx, createdUser)
+		require.NoError(t, err)
+
+		resp, err = usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.AccessToken)
+	})
+
+	t.Run("rejects suspended users", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, _, _ := setupLoginTest(t)
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		suspendedAt := time.Now()
+		_, err = userRepo.Create(ctx, entities.User{
+			FullName:    "John Doe",
+			Phone:       "1234567890",
+			Email:       "john@example.com",
+			Password:    hashedPassword,
+			SuspendedAt: &suspendedAt,
+		})
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: "wrongpassword"})
+		assert.EqualError(t, err, "invalid credentials")
+		assert.Nil(t, resp)
+
+		resp, err = usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+		assert.EqualError(t, err, "account suspended: contact support")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("returns an MFA challenge instead of tokens when TOTP is enabled", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, sessionRepo, _ := setupLoginTest(t)
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: hashedPassword,
+		})
+		require.NoError(t, err)
+
+		_, err = usecase.totpFactorRepository.Create(ctx, authentities.TotpFactor{UserID: createdUser.ID, Secret: "JBSWY3DPEHPK3PXP"})
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+		require.NoError(t, err)
+		assert.False(t, resp.MfaRequired, "an unconfirmed enrollment must not affect login")
+
+		factor, err := usecase.totpFactorRepository.FindByUserID(ctx, createdUser.ID)
+		require.NoError(t, err)
+		confirmedAt := time.Now()
+		factor.ConfirmedAt = &confirmedAt
+		_, err = usecase.totpFactorRepository.Update(ctx, factor)
+		require.NoError(t, err)
+
+		resp, err = usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+		require.NoError(t, err)
+		assert.True(t, resp.MfaRequired)
+		assert.Empty(t, resp.AccessToken)
+		assert.Empty(t, resp.RefreshToken)
+
+		claims, err := usecase.tokenService.VerifyMfaToken(resp.MfaToken)
+		require.NoError(t, err)
+		assert.Equal(t, "mfa", claims.Type)
+		assert.Len(t, sessionRepo.FindActiveByUserID(ctx, createdUser.ID), 1)
+	})
+
+	t.Run("fails without tokens when the second factor cannot be loaded", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, sessionRepo, _ := setupLoginTest(t)
+		usecase.totpFactorRepository = failingTotpFactorRepository{usecase.totpFactorRepository}
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: hashedPassword,
+		})
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load second factor")
+		assert.Nil(t, resp)
+		assert.Empty(t, sessionRepo.FindActiveByUserID(ctx, createdUser.ID))
+	})
+
+	t.Run("locks out the identifier after repeated failures", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, _, _ := setupLoginTest(t)
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		_, err = userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: hashedPassword,
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: "wrongpassword", IPAddress: "10.0.0.1"})
+			assert.Equal(t, "invalid credentials", err.Error())
+		}
+
+		// Even the right password is refused while locked out.
+		_, err = usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password, IPAddress: "10.0.0.2"})
+		var retryErr *RetryAfterError
+		require.ErrorAs(t, err, &retryErr)
+		assert.Equal(t, time.Minute, retryErr.RetryAfter.Round(time.Second))
+
+		require.NoError(t, usecase.loginThrottle.Reset(ctx, "john@example.com"))
+
+		resp, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password, IPAddress: "10.0.0.2"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.AccessToken)
+	})
+
+	t.Run("counts failures for unknown accounts", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _, _ := setupLoginTest(t)
+
+		for i := 0; i < 3; i++ {
+			_, err := usecase.Execute(ctx, LoginParam{Email: "nobody@example.com", Password: "password123"})
+			assert.Equal(t, "invalid credentials", err.Error())
+		}
+
+		_, err := usecase.Execute(ctx, LoginParam{Email: "nobody@example.com", Password: "password123"})
+		var retryErr *RetryAfterError
+		assert.ErrorAs(t, err, &retryErr)
+	})
+
+	t.Run("clears failures after a successful login", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, _, _ := setupLoginTest(t)
+
+		password := "password123"
+		hashedPassword, err := newTestPasswordService(t).HashPassword(password)
+		require.NoError(t, err)
+
+		_, err = userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: hashedPassword,
+		})
+		require.NoError(t, err)
+
+		for round := 0; round < 2; round++ {
+			for i := 0; i < 2; i++ {
+				_, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: "wrongpassword"})
+				assert.Equal(t, "invalid credentials", err.Error())
+			}
+
+			_, err := usecase.Execute(ctx, LoginParam{Email: "john@example.com", Password: password})
+			require.NoError(t, err)
+		}
+	})
 }
@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+)
+
+type LogoutUsecase struct {
+	sessionRepository authrepositories.SessionRepository
+	tokenService      *services.TokenService
+}
+
+func NewLogoutUsecase(sessionRepository authrepositories.SessionRepository, tokenService *services.TokenService) *LogoutUsecase {
+	return &LogoutUsecase{
+		sessionRepository: sessionRepository,
+		tokenService:      tokenService,
+	}
+}
+
+type LogoutParam struct {
+	UserID       uint64
+	RefreshToken string
+}
+
+func (u *LogoutUsecase) Execute(ctx context.Context, param LogoutParam) error {
+	if param.RefreshToken == "" {
+		return errors.New("refresh token is required")
+	}
+
+	claims, err := u.tokenService.VerifyRefreshToken(param.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to verify refresh token: %w", err)
+	}
+
+	session, err := u.sessionRepository.FindByTokenID(ctx, claims.ID)
+	if err != nil || session.UserID != param.UserID {
+		return errors.New("session not found")
+	}
+
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	if _, err := u.sessionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
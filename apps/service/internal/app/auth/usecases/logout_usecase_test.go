@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/config"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupLogoutTest(t *testing.T) (*LogoutUsecase, *refreshTokenFixture, *services.TokenService) {
+	db := testutil.SetupTestDB(t, &authentities.Session{})
+	fixture := &refreshTokenFixture{
+		sessionRepo: authrepositories.NewSessionRepository(db),
+		tokenStore:  authrepositories.NewInMemoryRefreshTokenStore(),
+	}
+
+	tokenService, err := services.NewTokenService(&config.Config{
+		JwtSecret:         "test-secret-key-minimum-32-characters-long",
+		JwtIssuer:         "test-issuer",
+		JwtAccessExpires:  "15m",
+		JwtRefreshExpires: "168h",
+	})
+	require.NoError(t, err)
+
+	return NewLogoutUsecase(fixture.sessionRepo, tokenService), fixture, tokenService
+}
+
+func TestLogoutUsecase_Execute(t *testing.T) {
+	t.Run("revokes the session of the refresh token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, tokenService := setupLogoutTest(t)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, 1, "john@example.com", "1234567890")
+
+		err := usecase.Execute(ctx, LogoutParam{UserID: 1, RefreshToken: refreshToken})
+		require.NoError(t, err)
+
+		assert.Empty(t, fixture.sessionRepo.FindActiveByUserID(ctx, 1))
+	})
+
+	t.Run("is idempotent for an already revoked session", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, tokenService := setupLogoutTest(t)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, 1, "john@example.com", "1234567890")
+
+		require.NoError(t, usecase.Execute(ctx, LogoutParam{UserID: 1, RefreshToken: refreshToken}))
+		assert.NoError(t, usecase.Execute(ctx, LogoutParam{UserID: 1, RefreshToken: refreshToken}))
+	})
+
+	t.Run("returns error when session belongs to another user", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, tokenService := setupLogoutTest(t)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, 1, "john@example.com", "1234567890")
+
+		err := usecase.Execute(ctx, LogoutParam{UserID: 2, RefreshToken: refreshToken})
+		assert.Error(t, err)
+		assert.Equal(t, "session not found", err.Error())
+		assert.Len(t, fixture.sessionRepo.FindActiveByUserID(ctx, 1), 1)
+	})
+
+	t.Run("returns error when refresh token is empty", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _ := setupLogoutTest(t)
+
+		err := usecase.Execute(ctx, LogoutParam{UserID: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "refresh token is required")
+	})
+
+	t.Run("returns error when refresh token is invalid", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _ := setupLogoutTest(t)
+
+		err := usecase.Execute(ctx, LogoutParam{UserID: 1, RefreshToken: "invalid.token.here"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to verify refresh token")
+	})
+}
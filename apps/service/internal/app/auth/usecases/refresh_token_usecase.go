@@ -4,26 +4,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
 )
 
 type RefreshTokenUsecase struct {
-	userRepository repositories.UserRepository
-	tokenService   *services.TokenService
+	userRepository    repositories.UserRepository
+	sessionRepository authrepositories.SessionRepository
+	refreshTokenStore authrepositories.RefreshTokenStore
+	tokenService      *services.TokenService
 }
 
-func NewRefreshTokenUsecase(userRepository repositories.UserRepository, tokenService *services.TokenService) *RefreshTokenUsecase {
+func NewRefreshTokenUsecase(userRepository repositories.UserRepository, sessionRepository authrepositories.SessionRepository, refreshTokenStore authrepositories.RefreshTokenStore, tokenService *services.TokenService) *RefreshTokenUsecase {
 	return &RefreshTokenUsecase{
-		userRepository: userRepository,
-		tokenService:   tokenService,
+		userRepository:    userRepository,
+		sessionRepository: sessionRepository,
+		refreshTokenStore: refreshTokenStore,
+		tokenService:      tokenService,
 	}
 }
 
 type RefreshTokenParam struct {
 	RefreshToken string
+	UserAgent    string
+	IPAddress    string
 }
 
 type RefreshTokenResult struct {
@@ -47,22 +56,78 @@ func (u *RefreshTokenUsecase) Execute(ctx context.Context, param RefreshTokenPar
 		return nil, fmt.Errorf("failed to get user ID from token: %w", err)
 	}
 
-	tokenPair, err := u.tokenService.GenerateTokenPair(userID, claims.Email, claims.Phone)
+	now := time.Now()
+	storedToken, err := u.refreshTokenStore.FindByTokenID(ctx, claims.ID)
+	if err != nil || storedToken.UserID != userID {
+		return nil, errors.New("invalid refresh token: token is not recognized")
+	}
+
+	// A refresh token can only be exchanged once. Seeing a rotated token again
+	// means it was copied, so the whole family is revoked and both the thief
+	// and the legitimate client have to log in again.
+	if storedToken.IsRotated() {
+		if err := u.revokeFamily(ctx, storedToken.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token reuse detected: all sessions of this login have been revoked")
+	}
+
+	if !storedToken.IsUsable(now) {
+		return nil, errors.New("invalid refresh token: token has been revoked")
+	}
+
+	session, err := u.sessionRepository.FindByID(ctx, storedToken.FamilyID)
+	if err != nil || session.UserID != userID || !session.IsActive(now) {
+		return nil, errors.New("invalid refresh token: session is no longer active")
+	}
+
+	// The email and phone in the claims are whatever the user had at login and
+	// may since belong to someone else, so the user is looked up by ID.
+	user, err := u.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.IsSuspended() {
+		return nil, errors.New("account suspended: contact support")
+	}
+
+	tokenPair, err := u.tokenService.GenerateTokenPair(user.ID, user.Email, user.Phone, session.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	var user entities.User
-	if claims.Email != "" {
-		user, err = u.userRepository.FindByEmail(ctx, claims.Email)
-	} else if claims.Phone != "" {
-		user, err = u.userRepository.FindByPhone(ctx, claims.Phone)
-	} else {
-		return nil, errors.New("invalid token claims")
+	// Losing the race against a concurrent refresh with the same token is
+	// treated exactly like reuse.
+	if err := u.refreshTokenStore.MarkRotated(ctx, storedToken.TokenID, tokenPair.RefreshTokenID, now); err != nil {
+		if revokeErr := u.revokeFamily(ctx, storedToken.FamilyID, now); revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, errors.New("refresh token reuse detected: all sessions of this login have been revoked")
 	}
 
+	_, err = u.refreshTokenStore.Save(ctx, authentities.RefreshToken{
+		TokenID:   tokenPair.RefreshTokenID,
+		FamilyID:  storedToken.FamilyID,
+		UserID:    userID,
+		ExpiresAt: tokenPair.RefreshExpiresAt,
+	})
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	session.TokenID = tokenPair.RefreshTokenID
+	session.ExpiresAt = tokenPair.RefreshExpiresAt
+	session.LastUsedAt = now
+	if param.UserAgent != "" {
+		session.UserAgent = param.UserAgent
+	}
+	if param.IPAddress != "" {
+		session.IPAddress = param.IPAddress
+	}
+
+	if _, err := u.sessionRepository.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
 	}
 
 	return &RefreshTokenResult{
@@ -71,3 +136,23 @@ func (u *RefreshTokenUsecase) Execute(ctx context.Context, param RefreshTokenPar
 		RefreshToken: tokenPair.RefreshToken,
 	}, nil
 }
+
+// revokeFamily revokes every refresh token issued for a login along with the
+// session that represents it.
+func (u *RefreshTokenUsecase) revokeFamily(ctx context.Context, familyID uint64, now time.Time) error {
+	if err := u.refreshTokenStore.RevokeFamily(ctx, familyID, now); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+
+	session, err := u.sessionRepository.FindByID(ctx, familyID)
+	if err != nil || session.RevokedAt != nil {
+		return nil
+	}
+
+	session.RevokedAt = &now
+	if _, err := u.sessionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
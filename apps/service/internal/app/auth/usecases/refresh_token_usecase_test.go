@@ -3,10 +3,13 @@ package usecases
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
@@ -14,9 +17,13 @@ import (
 	"github.com/reno1r/weiss/apps/service/internal/testutil"
 )
 
-func setupRefreshTokenTest(t *testing.T) (*RefreshTokenUsecase, repositories.UserRepository, *services.TokenService) {
-	db := testutil.SetupTestDB(t, &entities.User{})
+func setupRefreshTokenTest(t *testing.T) (*RefreshTokenUsecase, repositories.UserRepository, *refreshTokenFixture, *services.TokenService) {
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.Session{})
 	userRepo := repositories.NewUserRepository(db)
+	fixture := &refreshTokenFixture{
+		sessionRepo: authrepositories.NewSessionRepository(db),
+		tokenStore:  authrepositories.NewInMemoryRefreshTokenStore(),
+	}
 
 	config := &config.Config{
 		JwtSecret:         "test-secret-key-minimum-32-characters-long",
@@ -28,15 +35,45 @@ func setupRefreshTokenTest(t *testing.T) (*RefreshTokenUsecase, repositories.Use
 	tokenService, err := services.NewTokenService(config)
 	require.NoError(t, err)
 
-	refreshUsecase := NewRefreshTokenUsecase(userRepo, tokenService)
+	refreshUsecase := NewRefreshTokenUsecase(userRepo, fixture.sessionRepo, fixture.tokenStore, tokenService)
+
+	return refreshUsecase, userRepo, fixture, tokenService
+}
 
-	return refreshUsecase, userRepo, tokenService
+type refreshTokenFixture struct {
+	sessionRepo authrepositories.SessionRepository
+	tokenStore  authrepositories.RefreshTokenStore
+}
+
+// issueRefreshToken generates a refresh token and persists the session and
+// token family it belongs to, the same way LoginUsecase does.
+func issueRefreshToken(t *testing.T, ctx context.Context, tokenService *services.TokenService, fixture *refreshTokenFixture, userID uint64, email, phone string) string {
+	refreshToken, claims, err := tokenService.GenerateRefreshToken(userID, email, phone)
+	require.NoError(t, err)
+
+	session, err := fixture.sessionRepo.Create(ctx, authentities.Session{
+		UserID:     userID,
+		TokenID:    claims.ID,
+		ExpiresAt:  claims.ExpiresAt.Time,
+		LastUsedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	_, err = fixture.tokenStore.Save(ctx, authentities.RefreshToken{
+		TokenID:   claims.ID,
+		FamilyID:  session.ID,
+		UserID:    userID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	require.NoError(t, err)
+
+	return refreshToken
 }
 
 func TestRefreshTokenUsecase_Execute(t *testing.T) {
 	t.Run("refreshes token successfully with email", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo, tokenService := setupRefreshTokenTest(t)
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
 
 		user := entities.User{
 			FullName: "John Doe",
@@ -47,8 +84,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 		createdUser, err := userRepo.Create(ctx, user)
 		require.NoError(t, err)
 
-		refreshToken, err := tokenService.GenerateRefreshToken(createdUser.ID, createdUser.Email, createdUser.Phone)
-		require.NoError(t, err)
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
 
 		param := RefreshTokenParam{
 			RefreshToken: refreshToken,
@@ -60,11 +96,18 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 		assert.NotEmpty(t, resp.AccessToken)
 		assert.NotEmpty(t, resp.RefreshToken)
 		assert.NotEqual(t, refreshToken, resp.RefreshToken)
+
+		// The new access token still belongs to the session of the login.
+		sessions := fixture.sessionRepo.FindActiveByUserID(ctx, createdUser.ID)
+		require.Len(t, sessions, 1)
+		claims, err := tokenService.VerifyAccessToken(resp.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, sessions[0].ID, claims.SessionID)
 	})
 
 	t.Run("refreshes token successfully with phone", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo, tokenService := setupRefreshTokenTest(t)
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
 
 		user := entities.User{
 			FullName: "John Doe",
@@ -75,8 +118,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 		createdUser, err := userRepo.Create(ctx, user)
 		require.NoError(t, err)
 
-		refreshToken, err := tokenService.GenerateRefreshToken(createdUser.ID, createdUser.Email, createdUser.Phone)
-		require.NoError(t, err)
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
 
 		param := RefreshTokenParam{
 			RefreshToken: refreshToken,
@@ -91,7 +133,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when refresh token is empty", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _, _ := setupRefreshTokenTest(t)
+		usecase, _, _, _ := setupRefreshTokenTest(t)
 
 		param := RefreshTokenParam{
 			RefreshToken: "",
@@ -105,7 +147,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when refresh token is invalid", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _, _ := setupRefreshTokenTest(t)
+		usecase, _, _, _ := setupRefreshTokenTest(t)
 
 		param := RefreshTokenParam{
 			RefreshToken: "invalid.token.here",
@@ -119,7 +161,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when access token is used instead", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo, tokenService := setupRefreshTokenTest(t)
+		usecase, userRepo, _, tokenService := setupRefreshTokenTest(t)
 
 		user := entities.User{
 			FullName: "John Doe",
@@ -130,7 +172,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 		createdUser, err := userRepo.Create(ctx, user)
 		require.NoError(t, err)
 
-		accessToken, err := tokenService.GenerateAccessToken(createdUser.ID, createdUser.Email, createdUser.Phone)
+		accessToken, err := tokenService.GenerateAccessToken(createdUser.ID, createdUser.Email, createdUser.Phone, 1)
 		require.NoError(t, err)
 
 		param := RefreshTokenParam{
@@ -145,10 +187,9 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 
 	t.Run("returns error when user not found", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _, tokenService := setupRefreshTokenTest(t)
+		usecase, _, fixture, tokenService := setupRefreshTokenTest(t)
 
-		refreshToken, err := tokenService.GenerateRefreshToken(999, "notfound@example.com", "9999999999")
-		require.NoError(t, err)
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, 999, "notfound@example.com", "9999999999")
 
 		param := RefreshTokenParam{
 			RefreshToken: refreshToken,
@@ -162,7 +203,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 
 	t.Run("generates new token pair on refresh", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, userRepo, tokenService := setupRefreshTokenTest(t)
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
 
 		user := entities.User{
 			FullName: "John Doe",
@@ -173,8 +214,7 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 		createdUser, err := userRepo.Create(ctx, user)
 		require.NoError(t, err)
 
-		originalRefreshToken, err := tokenService.GenerateRefreshToken(createdUser.ID, createdUser.Email, createdUser.Phone)
-		require.NoError(t, err)
+		originalRefreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
 
 		param := RefreshTokenParam{
 			RefreshToken: originalRefreshToken,
@@ -196,20 +236,239 @@ func TestRefreshTokenUsecase_Execute(t *testing.T) {
 		assert.Equal(t, createdUser.ID, userID)
 	})
 
-	t.Run("returns error when token claims have no email or phone", func(t *testing.T) {
+	t.Run("identifies the user by ID rather than the contact claims", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, _, tokenService := setupRefreshTokenTest(t)
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
 
-		refreshToken, err := tokenService.GenerateRefreshToken(1, "", "")
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+		// The token was issued before John changed his email, and someone
+		// else has taken the old address since.
+		_, err = userRepo.Create(ctx, entities.User{
+			FullName: "Jane Doe",
+			Phone:    "0987654321",
+			Email:    "old@example.com",
+			Password: "hashedpassword",
+		})
 		require.NoError(t, err)
 
-		param := RefreshTokenParam{
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, "old@example.com", "")
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: refreshToken})
+		require.NoError(t, err)
+		assert.Equal(t, createdUser.ID, resp.User.ID)
+
+		accessClaims, err := tokenService.VerifyToken(resp.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", accessClaims.Email)
+		assert.Equal(t, "1234567890", accessClaims.Phone)
+	})
+
+	t.Run("returns error when refresh token has no session", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, _, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		refreshToken, _, err := tokenService.GenerateRefreshToken(createdUser.ID, createdUser.Email, createdUser.Phone)
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: refreshToken})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid refresh token")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("returns error when session is revoked", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+
+		claims, err := tokenService.VerifyRefreshToken(refreshToken)
+		require.NoError(t, err)
+		session, err := fixture.sessionRepo.FindByTokenID(ctx, claims.ID)
+		require.NoError(t, err)
+		revokedAt := time.Now()
+		session.RevokedAt = &revokedAt
+		_, err = fixture.sessionRepo.Update(ctx, session)
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: refreshToken})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid refresh token")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("moves session to the new refresh token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+		oldClaims, err := tokenService.VerifyRefreshToken(refreshToken)
+		require.NoError(t, err)
+		oldSession, err := fixture.sessionRepo.FindByTokenID(ctx, oldClaims.ID)
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{
 			RefreshToken: refreshToken,
-		}
+			UserAgent:    "new-agent",
+			IPAddress:    "10.0.0.1",
+		})
+		require.NoError(t, err)
 
-		resp, err := usecase.Execute(ctx, param)
+		newClaims, err := tokenService.VerifyRefreshToken(resp.RefreshToken)
+		require.NoError(t, err)
+		newSession, err := fixture.sessionRepo.FindByTokenID(ctx, newClaims.ID)
+		require.NoError(t, err)
+		assert.Equal(t, oldSession.ID, newSession.ID)
+		assert.Equal(t, "new-agent", newSession.UserAgent)
+		assert.Equal(t, "10.0.0.1", newSession.IPAddress)
+
+		_, err = fixture.sessionRepo.FindByTokenID(ctx, oldClaims.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects a refresh token that was already rotated", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+
+		_, err = usecase.Execute(ctx, RefreshTokenParam{RefreshToken: refreshToken})
+		require.NoError(t, err)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: refreshToken})
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid token claims")
+		assert.Contains(t, err.Error(), "refresh token reuse detected")
 		assert.Nil(t, resp)
 	})
+
+	t.Run("revokes the whole token family on reuse", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		stolenToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+
+		rotated, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: stolenToken})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, RefreshTokenParam{RefreshToken: stolenToken})
+		require.Error(t, err)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: rotated.RefreshToken})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid refresh token")
+		assert.Nil(t, resp)
+
+		claims, err := tokenService.VerifyRefreshToken(rotated.RefreshToken)
+		require.NoError(t, err)
+		storedToken, err := fixture.tokenStore.FindByTokenID(ctx, claims.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, storedToken.RevokedAt)
+
+		sessions := fixture.sessionRepo.FindActiveByUserID(ctx, createdUser.ID)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("does not affect other token families on reuse", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		stolenToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+		otherDeviceToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+
+		_, err = usecase.Execute(ctx, RefreshTokenParam{RefreshToken: stolenToken})
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, RefreshTokenParam{RefreshToken: stolenToken})
+		require.Error(t, err)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: otherDeviceToken})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.RefreshToken)
+	})
+
+	t.Run("marks the exchanged token as replaced by its successor", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, fixture, tokenService := setupRefreshTokenTest(t)
+
+		createdUser, err := userRepo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		refreshToken := issueRefreshToken(t, ctx, tokenService, fixture, createdUser.ID, createdUser.Email, createdUser.Phone)
+
+		resp, err := usecase.Execute(ctx, RefreshTokenParam{RefreshToken: refreshToken})
+		require.NoError(t, err)
+
+		oldClaims, err := tokenService.VerifyRefreshToken(refreshToken)
+		require.NoError(t, err)
+		newClaims, err := tokenService.VerifyRefreshToken(resp.RefreshToken)
+		require.NoError(t, err)
+
+		oldToken, err := fixture.tokenStore.FindByTokenID(ctx, oldClaims.ID)
+		require.NoError(t, err)
+		assert.True(t, oldToken.IsRotated())
+		assert.Equal(t, newClaims.ID, oldToken.ReplacedBy)
+
+		newToken, err := fixture.tokenStore.FindByTokenID(ctx, newClaims.ID)
+		require.NoError(t, err)
+		assert.Equal(t, oldToken.FamilyID, newToken.FamilyID)
+		assert.True(t, newToken.IsUsable(time.Now()))
+	})
 }
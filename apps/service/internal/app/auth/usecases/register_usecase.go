@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
@@ -14,16 +15,18 @@ import (
 )
 
 type RegisterUsecase struct {
-	userRepository  repositories.UserRepository
-	passwordService *services.PasswordService
-	validator       *validator.Validate
+	userRepository       repositories.UserRepository
+	invitationRepository accessrepositories.InvitationRepository
+	passwordService      *services.PasswordService
+	validator            *validator.Validate
 }
 
-func NewRegisterUsecase(userRepository repositories.UserRepository, passwordService *services.PasswordService) *RegisterUsecase {
+func NewRegisterUsecase(userRepository repositories.UserRepository, invitationRepository accessrepositories.InvitationRepository, passwordService *services.PasswordService) *RegisterUsecase {
 	return &RegisterUsecase{
-		userRepository:  userRepository,
-		passwordService: passwordService,
-		validator:       validator.New(),
+		userRepository:       userRepository,
+		invitationRepository: invitationRepository,
+		passwordService:      passwordService,
+		validator:            validator.New(),
 	}
 }
 
@@ -74,6 +77,11 @@ func (u *RegisterUsecase) Execute(ctx context.Context, param RegisterParam) (*Re
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// Shop invitations sent before the user had an account now belong to it.
+	if err := u.invitationRepository.AttachToUser(ctx, createdUser.ID, createdUser.Email, createdUser.Phone); err != nil {
+		return nil, fmt.Errorf("failed to attach invitations: %w", err)
+	}
+
 	return &RegisterResult{
 		User: &createdUser,
 	}, nil
@@ -3,28 +3,25 @@ package usecases
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"golang.org/x/crypto/bcrypt"
 
-	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
-	"github.com/reno1r/weiss/apps/service/internal/config"
 	"github.com/reno1r/weiss/apps/service/internal/testutil"
 )
 
 func setupRegisterTest(t *testing.T) (*RegisterUsecase, repositories.UserRepository) {
-	db := testutil.SetupTestDB(t, &entities.User{})
+	db := testutil.SetupTestDB(t, &entities.User{}, &accessentities.Invitation{})
 	userRepo := repositories.NewUserRepository(db)
 
-	config := &config.Config{
-		BcryptCost: bcrypt.MinCost,
-	}
-	passwordService := services.NewPasswordService(config)
+	passwordService := newTestPasswordService(t)
 
-	registerUsecase := NewRegisterUsecase(userRepo, passwordService)
+	registerUsecase := NewRegisterUsecase(userRepo, accessrepositories.NewInvitationRepository(db), passwordService)
 
 	return registerUsecase, userRepo
 }
@@ -263,3 +260,33 @@ func TestRegisterUsecase_Execute(t *testing.T) {
 		assert.True(t, len(user.Password) > 50)
 	})
 }
+
+func TestRegisterUsecase_AttachesInvitations(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &entities.User{}, &accessentities.Invitation{})
+	invitationRepo := accessrepositories.NewInvitationRepository(db)
+	usecase := NewRegisterUsecase(repositories.NewUserRepository(db), invitationRepo, newTestPasswordService(t))
+
+	invitation, err := invitationRepo.Create(ctx, accessentities.Invitation{
+		ShopID:    1,
+		RoleID:    1,
+		InviterID: 1,
+		Email:     "john@example.com",
+		TokenHash: "hash",
+		Status:    accessentities.InvitationStatusPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	resp, err := usecase.Execute(ctx, RegisterParam{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	invitations := invitationRepo.FindPendingByUserID(ctx, resp.User.ID, time.Now())
+	require.Len(t, invitations, 1)
+	assert.Equal(t, invitation.ID, invitations[0].ID)
+}
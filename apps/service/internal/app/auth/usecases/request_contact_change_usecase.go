@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type RequestContactChangeUsecase struct {
+	userRepository             repositories.UserRepository
+	verificationCodeRepository authrepositories.VerificationCodeRepository
+	passwordService            *services.PasswordService
+	loginThrottle              *services.LoginThrottleService
+	mailer                     mail.Mailer
+	smsSender                  sms.Sender
+	expiresIn                  time.Duration
+	resendInterval             time.Duration
+	validator                  *validator.Validate
+}
+
+func NewRequestContactChangeUsecase(userRepository repositories.UserRepository, verificationCodeRepository authrepositories.VerificationCodeRepository, passwordService *services.PasswordService, loginThrottle *services.LoginThrottleService, mailer mail.Mailer, smsSender sms.Sender, expiresIn time.Duration, resendInterval time.Duration) *RequestContactChangeUsecase {
+	return &RequestContactChangeUsecase{
+		userRepository:             userRepository,
+		verificationCodeRepository: verificationCodeRepository,
+		passwordService:            passwordService,
+		loginThrottle:              loginThrottle,
+		mailer:                     mailer,
+		smsSender:                  smsSender,
+		expiresIn:                  expiresIn,
+		resendInterval:             resendInterval,
+		validator:                  validator.New(),
+	}
+}
+
+// RequestContactChangeParam carries the new email or phone of a user. The
+// current password is required so a hijacked session cannot redirect the
+// account's contact, and with it password resets, elsewhere. Wrong passwords
+// are throttled per user.
+type RequestContactChangeParam struct {
+	UserID   uint64 `validate:"required"`
+	Email    string `validate:"omitempty,email"`
+	Phone    string `validate:"omitempty,min=10,max=20"`
+	Password string `validate:"required"`
+}
+
+// Execute sends a verification code to the new contact. The user keeps the
+// current contact until the code is confirmed.
+func (u *RequestContactChangeUsecase) Execute(ctx context.Context, param RequestContactChangeParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	if (param.Email == "") == (param.Phone == "") {
+		return errors.New("email or phone is required")
+	}
+	if param.Phone != "" {
+		if err := sms.RequireDelivery(u.smsSender); err != nil {
+			return err
+		}
+	}
+
+	user, err := u.userRepository.FindByID(ctx, param.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkCurrentPassword(ctx, u.loginThrottle, u.passwordService, user, param.Password); err != nil {
+		return err
+	}
+
+	// The same uniqueness rules as registration apply to the new contact.
+	channel, target := authentities.VerificationChannelEmailChange, param.Email
+	if param.Email != "" {
+		if param.Email == user.Email {
+			return errors.New("validation failed: email must differ from the current one")
+		}
+		if _, err := u.userRepository.FindByEmail(ctx, param.Email); err == nil {
+			return errors.New("user with this email already exists")
+		}
+	} else {
+		channel, target = authentities.VerificationChannelPhoneChange, param.Phone
+		if param.Phone == user.Phone {
+			return errors.New("validation failed: phone must differ from the current one")
+		}
+		if _, err := u.userRepository.FindByPhone(ctx, param.Phone); err == nil {
+			return errors.New("user with this phone already exists")
+		}
+	}
+
+	now := time.Now()
+	latest, err := u.verificationCodeRepository.FindLatest(ctx, user.ID, channel)
+	if err == nil {
+		if wait := latest.CreatedAt.Add(u.resendInterval).Sub(now); wait > 0 {
+			return &RetryAfterError{
+				Message:    "verification code was sent recently",
+				RetryAfter: wait,
+			}
+		}
+	}
+
+	code, err := services.GenerateNumericCode(verificationCodeDigits)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.verificationCodeRepository.Create(ctx, authentities.VerificationCode{
+		UserID:    user.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  services.HashOpaqueToken(code),
+		ExpiresAt: now.Add(u.expiresIn),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verification code: %w", err)
+	}
+
+	err = deliverVerificationCode(ctx, u.mailer, u.smsSender, channel, target, user.FullName, code, u.expiresIn)
+	if err != nil {
+		return fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	return nil
+}
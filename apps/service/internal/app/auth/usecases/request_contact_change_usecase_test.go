@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupContactChangeTest(t *testing.T) (*RequestContactChangeUsecase, *verificationFixture, entities.User) {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.VerificationCode{})
+	fixture := &verificationFixture{
+		db:        db,
+		userRepo:  repositories.NewUserRepository(db),
+		codeRepo:  authrepositories.NewVerificationCodeRepository(db),
+		mailer:    mail.NewMemoryMailer(),
+		smsSender: sms.NewMemorySender(),
+	}
+	passwordService := newTestPasswordService(t)
+
+	hashedPassword, err := passwordService.HashPassword("password123")
+	require.NoError(t, err)
+
+	user, err := fixture.userRepo.Create(ctx, entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: hashedPassword,
+	})
+	require.NoError(t, err)
+
+	usecase := NewRequestContactChangeUsecase(fixture.userRepo, fixture.codeRepo, passwordService, newTestLoginThrottle(), fixture.mailer, fixture.smsSender, 15*time.Minute, time.Minute)
+
+	return usecase, fixture, user
+}
+
+func TestRequestContactChangeUsecase_Execute(t *testing.T) {
+	t.Run("emails a code to the new address", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, user := setupContactChangeTest(t)
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "password123"})
+		require.NoError(t, err)
+
+		_, ok := fixture.mailer.LastMessageTo("new@example.com")
+		assert.True(t, ok)
+
+		stored, err := fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelEmailChange)
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", stored.Target)
+
+		// The current email stays in place until the change is confirmed.
+		found, err := fixture.userRepo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", found.Email)
+	})
+
+	t.Run("texts a code to the new phone", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, user := setupContactChangeTest(t)
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Phone: "0987654321", Password: "password123"})
+		require.NoError(t, err)
+
+		_, ok := fixture.smsSender.LastMessageTo("0987654321")
+		assert.True(t, ok)
+	})
+
+	t.Run("returns error for wrong password", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, user := setupContactChangeTest(t)
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "wrongpassword"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid credentials", err.Error())
+		assert.Empty(t, fixture.mailer.Messages())
+	})
+
+	t.Run("locks out after repeated wrong passwords", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, user := setupContactChangeTest(t)
+
+		for i := 0; i < 3; i++ {
+			err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "wrongpassword"})
+			assert.Equal(t, "invalid credentials", err.Error())
+		}
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "password123"})
+		var retryErr *RetryAfterError
+		require.True(t, errors.As(err, &retryErr))
+		assert.Positive(t, retryErr.RetryAfter)
+		assert.Empty(t, fixture.mailer.Messages())
+	})
+
+	t.Run("returns error when the contact belongs to another user", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, user := setupContactChangeTest(t)
+
+		_, err := fixture.userRepo.Create(ctx, entities.User{
+			FullName: "Jane Doe",
+			Phone:    "0987654321",
+			Email:    "jane@example.com",
+			Password: "hashedpassword",
+		})
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "jane@example.com", Password: "password123"})
+		assert.Error(t, err)
+		assert.Equal(t, "user with this email already exists", err.Error())
+
+		err = usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Phone: "0987654321", Password: "password123"})
+		assert.Error(t, err)
+		assert.Equal(t, "user with this phone already exists", err.Error())
+	})
+
+	t.Run("returns error when the contact is unchanged", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, user := setupContactChangeTest(t)
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "john@example.com", Password: "password123"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("returns error unless exactly one contact is given", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, user := setupContactChangeTest(t)
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Password: "password123"})
+		assert.Error(t, err)
+		assert.Equal(t, "email or phone is required", err.Error())
+
+		err = usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Phone: "0987654321", Password: "password123"})
+		assert.Error(t, err)
+	})
+
+	t.Run("throttles resends", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture, user := setupContactChangeTest(t)
+
+		require.NoError(t, usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "new@example.com", Password: "password123"}))
+
+		err := usecase.Execute(ctx, RequestContactChangeParam{UserID: user.ID, Email: "other@example.com", Password: "password123"})
+		var retryErr *RetryAfterError
+		require.True(t, errors.As(err, &retryErr))
+		assert.Len(t, fixture.mailer.Messages(), 1)
+	})
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ResetPasswordUsecase struct {
+	db              *gorm.DB
+	passwordService *services.PasswordService
+	validator       *validator.Validate
+}
+
+func NewResetPasswordUsecase(db *gorm.DB, passwordService *services.PasswordService) *ResetPasswordUsecase {
+	return &ResetPasswordUsecase{
+		db:              db,
+		passwordService: passwordService,
+		validator:       validator.New(),
+	}
+}
+
+type ResetPasswordParam struct {
+	Token    string `validate:"required"`
+	Password string `validate:"required,min=6,max=100"`
+}
+
+func (u *ResetPasswordUsecase) Execute(ctx context.Context, param ResetPasswordParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	hashedPassword, err := u.passwordService.HashPassword(param.Password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+
+	// Consuming the token, changing the password and signing out every device
+	// must happen together.
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txResetRepo := authrepositories.NewPasswordResetRepository(tx)
+		txSessionRepo := authrepositories.NewSessionRepository(tx)
+		txUserRepo := repositories.NewUserRepository(tx)
+
+		reset, err := txResetRepo.FindByTokenHash(ctx, services.HashOpaqueToken(param.Token))
+		if err != nil || !reset.IsUsable(now) {
+			return errors.New("invalid or expired reset token")
+		}
+
+		if err := txResetRepo.MarkUsed(ctx, reset.ID, now); err != nil {
+			return errors.New("invalid or expired reset token")
+		}
+
+		user, err := txUserRepo.FindByID(ctx, reset.UserID)
+		if err != nil {
+			return errors.New("invalid or expired reset token")
+		}
+
+		user.Password = hashedPassword
+		if _, err := txUserRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		if err := txResetRepo.InvalidateByUserID(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+		}
+
+		if err := txSessionRepo.RevokeAllByUserID(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+
+		return nil
+	})
+}
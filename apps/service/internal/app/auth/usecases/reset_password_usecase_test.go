@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupResetPasswordTest(t *testing.T) (*ResetPasswordUsecase, *gorm.DB, *services.PasswordService) {
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.PasswordReset{}, &authentities.Session{})
+	passwordService := newTestPasswordService(t)
+
+	return NewResetPasswordUsecase(db, passwordService), db, passwordService
+}
+
+// issuePasswordReset creates a user together with a reset token for them.
+func issuePasswordReset(t *testing.T, ctx context.Context, db *gorm.DB, expiresAt time.Time) (entities.User, string) {
+	user, err := repositories.NewUserRepository(db).Create(ctx, entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: "hashedpassword",
+	})
+	require.NoError(t, err)
+
+	token, tokenHash, err := services.GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	_, err = authrepositories.NewPasswordResetRepository(db).Create(ctx, authentities.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+
+	return user, token
+}
+
+func TestResetPasswordUsecase_Execute(t *testing.T) {
+	t.Run("changes the password", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, passwordService := setupResetPasswordTest(t)
+
+		user, token := issuePasswordReset(t, ctx, db, time.Now().Add(time.Hour))
+
+		err := usecase.Execute(ctx, ResetPasswordParam{Token: token, Password: "newpassword123"})
+		require.NoError(t, err)
+
+		updated, err := repositories.NewUserRepository(db).FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		ok, _ := passwordService.VerifyPassword(updated.Password, "newpassword123")
+		assert.True(t, ok)
+	})
+
+	t.Run("revokes every session of the user", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, _ := setupResetPasswordTest(t)
+
+		user, token := issuePasswordReset(t, ctx, db, time.Now().Add(time.Hour))
+
+		sessionRepo := authrepositories.NewSessionRepository(db)
+		_, err := sessionRepo.Create(ctx, authentities.Session{
+			UserID:     user.ID,
+			TokenID:    "token-1",
+			ExpiresAt:  time.Now().Add(time.Hour),
+			LastUsedAt: time.Now(),
+		})
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, ResetPasswordParam{Token: token, Password: "newpassword123"})
+		require.NoError(t, err)
+
+		assert.Empty(t, sessionRepo.FindActiveByUserID(ctx, user.ID))
+	})
+
+	t.Run("rejects a token that was already used", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, _ := setupResetPasswordTest(t)
+
+		_, token := issuePasswordReset(t, ctx, db, time.Now().Add(time.Hour))
+
+		require.NoError(t, usecase.Execute(ctx, ResetPasswordParam{Token: token, Password: "newpassword123"}))
+
+		err := usecase.Execute(ctx, ResetPasswordParam{Token: token, Password: "anotherpassword"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired reset token", err.Error())
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, db, _ := setupResetPasswordTest(t)
+
+		_, token := issuePasswordReset(t, ctx, db, time.Now().Add(-time.Minute))
+
+		err := usecase.Execute(ctx, ResetPasswordParam{Token: token, Password: "newpassword123"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired reset token", err.Error())
+	})
+
+	t.Run("rejects an unknown token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _ := setupResetPasswordTest(t)
+
+		err := usecase.Execute(ctx, ResetPasswordParam{Token: "unknown", Password: "newpassword123"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired reset token", err.Error())
+	})
+
+	t.Run("returns error when password is too short", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _ := setupResetPasswordTest(t)
+
+		err := usecase.Execute(ctx, ResetPasswordParam{Token: "token", Password: "123"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
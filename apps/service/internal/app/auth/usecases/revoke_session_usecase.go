@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+)
+
+type RevokeSessionUsecase struct {
+	sessionRepository authrepositories.SessionRepository
+}
+
+func NewRevokeSessionUsecase(sessionRepository authrepositories.SessionRepository) *RevokeSessionUsecase {
+	return &RevokeSessionUsecase{
+		sessionRepository: sessionRepository,
+	}
+}
+
+type RevokeSessionParam struct {
+	UserID    uint64
+	SessionID uint64
+}
+
+func (u *RevokeSessionUsecase) Execute(ctx context.Context, param RevokeSessionParam) error {
+	session, err := u.sessionRepository.FindByID(ctx, param.SessionID)
+	if err != nil || session.UserID != param.UserID {
+		return errors.New("session not found")
+	}
+
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	if _, err := u.sessionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeSessionUsecase_Execute(t *testing.T) {
+	t.Run("revokes session owned by the user", func(t *testing.T) {
+		ctx := context.Background()
+		sessionRepo := setupSessionsTest(t)
+		usecase := NewRevokeSessionUsecase(sessionRepo)
+
+		session := createTestSession(t, ctx, sessionRepo, 1, "token-1")
+
+		err := usecase.Execute(ctx, RevokeSessionParam{UserID: 1, SessionID: session.ID})
+		require.NoError(t, err)
+
+		found, err := sessionRepo.FindByID(ctx, session.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+	})
+
+	t.Run("returns error when session belongs to another user", func(t *testing.T) {
+		ctx := context.Background()
+		sessionRepo := setupSessionsTest(t)
+		usecase := NewRevokeSessionUsecase(sessionRepo)
+
+		session := createTestSession(t, ctx, sessionRepo, 1, "token-1")
+
+		err := usecase.Execute(ctx, RevokeSessionParam{UserID: 2, SessionID: session.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "session not found", err.Error())
+	})
+
+	t.Run("returns error when session does not exist", func(t *testing.T) {
+		ctx := context.Background()
+		sessionRepo := setupSessionsTest(t)
+		usecase := NewRevokeSessionUsecase(sessionRepo)
+
+		err := usecase.Execute(ctx, RevokeSessionParam{UserID: 1, SessionID: 999})
+		assert.Error(t, err)
+		assert.Equal(t, "session not found", err.Error())
+	})
+}
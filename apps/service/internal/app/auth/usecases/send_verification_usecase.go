@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+const verificationCodeDigits = 6
+
+type SendVerificationUsecase struct {
+	userRepository             repositories.UserRepository
+	verificationCodeRepository authrepositories.VerificationCodeRepository
+	mailer                     mail.Mailer
+	smsSender                  sms.Sender
+	expiresIn                  time.Duration
+	resendInterval             time.Duration
+	validator                  *validator.Validate
+}
+
+func NewSendVerificationUsecase(userRepository repositories.UserRepository, verificationCodeRepository authrepositories.VerificationCodeRepository, mailer mail.Mailer, smsSender sms.Sender, expiresIn time.Duration, resendInterval time.Duration) *SendVerificationUsecase {
+	return &SendVerificationUsecase{
+		userRepository:             userRepository,
+		verificationCodeRepository: verificationCodeRepository,
+		mailer:                     mailer,
+		smsSender:                  smsSender,
+		expiresIn:                  expiresIn,
+		resendInterval:             resendInterval,
+		validator:                  validator.New(),
+	}
+}
+
+// SendVerificationParam identifies the channel to verify by either an email
+// address or a phone number.
+type SendVerificationParam struct {
+	Email string `validate:"omitempty,email"`
+	Phone string `validate:"omitempty,min=10,max=20"`
+}
+
+// Execute sends a verification code to the given email or phone. Like the
+// password reset flow it succeeds silently for unknown or already verified
+// contacts, so it cannot be used to probe which accounts exist.
+func (u *SendVerificationUsecase) Execute(ctx context.Context, param SendVerificationParam) error {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.Email == "" && param.Phone != "" {
+		if err := sms.RequireDelivery(u.smsSender); err != nil {
+			return err
+		}
+	}
+
+	user, channel, err := findUserByContact(ctx, u.userRepository, param.Email, param.Phone)
+	if err != nil {
+		if err.Error() == "email or phone is required" {
+			return err
+		}
+		return nil
+	}
+
+	if isChannelVerified(user, channel) {
+		return nil
+	}
+
+	now := time.Now()
+	latest, err := u.verificationCodeRepository.FindLatest(ctx, user.ID, channel)
+	if err == nil {
+		if wait := latest.CreatedAt.Add(u.resendInterval).Sub(now); wait > 0 {
+			return &RetryAfterError{
+				Message:    "verification code was sent recently",
+				RetryAfter: wait,
+			}
+		}
+	}
+
+	code, err := services.GenerateNumericCode(verificationCodeDigits)
+	if err != nil {
+		return err
+	}
+
+	target := contactTarget(user, channel)
+	_, err = u.verificationCodeRepository.Create(ctx, authentities.VerificationCode{
+		UserID:    user.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  services.HashOpaqueToken(code),
+		ExpiresAt: now.Add(u.expiresIn),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verification code: %w", err)
+	}
+
+	err = deliverVerificationCode(ctx, u.mailer, u.smsSender, channel, target, user.FullName, code, u.expiresIn)
+	if err != nil {
+		return fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	return nil
+}
+
+// deliverVerificationCode sends a code to an email address or by SMS,
+// depending on the channel it was issued for.
+func deliverVerificationCode(ctx context.Context, mailer mail.Mailer, smsSender sms.Sender, channel, target, fullName, code string, expiresIn time.Duration) error {
+	if channel == authentities.VerificationChannelEmail || channel == authentities.VerificationChannelEmailChange {
+		return mailer.Send(ctx, mail.Message{
+			To:      target,
+			Subject: "Verify your email address",
+			Body:    fmt.Sprintf("Hi %s,\n\nYour verification code is %s. It expires in %s.\n", fullName, code, expiresIn),
+		})
+	}
+
+	return smsSender.Send(ctx, sms.Message{
+		To:   target,
+		Body: fmt.Sprintf("Your Weiss verification code is %s", code),
+	})
+}
+
+// findUserByContact looks a user up by email when given, otherwise by phone,
+// and reports which channel was used.
+func findUserByContact(ctx context.Context, userRepository repositories.UserRepository, email, phone string) (entities.User, string, error) {
+	switch {
+	case email != "":
+		user, err := userRepository.FindByEmail(ctx, email)
+		return user, authentities.VerificationChannelEmail, err
+	case phone != "":
+		user, err := userRepository.FindByPhone(ctx, phone)
+		return user, authentities.VerificationChannelPhone, err
+	default:
+		return entities.User{}, "", errors.New("email or phone is required")
+	}
+}
+
+func isChannelVerified(user entities.User, channel string) bool {
+	if channel == authentities.VerificationChannelEmail {
+		return user.EmailVerifiedAt != nil
+	}
+	return user.PhoneVerifiedAt != nil
+}
+
+func contactTarget(user entities.User, channel string) string {
+	if channel == authentities.VerificationChannelEmail {
+		return user.Email
+	}
+	return user.Phone
+}
@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+type verificationFixture struct {
+	db        *gorm.DB
+	userRepo  repositories.UserRepository
+	codeRepo  authrepositories.VerificationCodeRepository
+	mailer    *mail.MemoryMailer
+	smsSender *sms.MemorySender
+}
+
+func setupVerificationTest(t *testing.T, resendInterval time.Duration) (*SendVerificationUsecase, *verificationFixture) {
+	db := testutil.SetupTestDB(t, &entities.User{}, &authentities.VerificationCode{})
+	fixture := &verificationFixture{
+		db:        db,
+		userRepo:  repositories.NewUserRepository(db),
+		codeRepo:  authrepositories.NewVerificationCodeRepository(db),
+		mailer:    mail.NewMemoryMailer(),
+		smsSender: sms.NewMemorySender(),
+	}
+
+	usecase := NewSendVerificationUsecase(fixture.userRepo, fixture.codeRepo, fixture.mailer, fixture.smsSender, 15*time.Minute, resendInterval)
+
+	return usecase, fixture
+}
+
+func createVerificationUser(t *testing.T, ctx context.Context, userRepo repositories.UserRepository) entities.User {
+	user, err := userRepo.Create(ctx, entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: "hashedpassword",
+	})
+	require.NoError(t, err)
+	return user
+}
+
+// lastCode extracts the six digit code from a verification message.
+func lastCode(t *testing.T, body string) string {
+	for _, field := range strings.Fields(body) {
+		field = strings.Trim(field, ".,")
+		if len(field) == verificationCodeDigits && strings.Trim(field, "0123456789") == "" {
+			return field
+		}
+	}
+	t.Fatalf("no verification code in %q", body)
+	return ""
+}
+
+func TestSendVerificationUsecase_Execute(t *testing.T) {
+	t.Run("emails a code for the email channel", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerificationTest(t, time.Minute)
+		user := createVerificationUser(t, ctx, fixture.userRepo)
+
+		err := usecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"})
+		require.NoError(t, err)
+
+		message, ok := fixture.mailer.LastMessageTo("john@example.com")
+		require.True(t, ok)
+		code := lastCode(t, message.Body)
+
+		stored, err := fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelEmail)
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", stored.Target)
+		assert.NotEqual(t, code, stored.CodeHash)
+	})
+
+	t.Run("texts a code for the phone channel", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerificationTest(t, time.Minute)
+		createVerificationUser(t, ctx, fixture.userRepo)
+
+		err := usecase.Execute(ctx, SendVerificationParam{Phone: "1234567890"})
+		require.NoError(t, err)
+
+		_, ok := fixture.smsSender.LastMessageTo("1234567890")
+		assert.True(t, ok)
+		assert.Empty(t, fixture.mailer.Messages())
+	})
+
+	t.Run("refuses the phone channel when SMS is not delivered", func(t *testing.T) {
+		ctx := context.Background()
+		_, fixture := setupVerificationTest(t, time.Minute)
+		user := createVerificationUser(t, ctx, fixture.userRepo)
+		usecase := NewSendVerificationUsecase(fixture.userRepo, fixture.codeRepo, fixture.mailer, sms.NewLogSender(false), 15*time.Minute, time.Minute)
+
+		err := usecase.Execute(ctx, SendVerificationParam{Phone: "1234567890"})
+		assert.ErrorIs(t, err, sms.ErrUnavailable)
+
+		_, err = fixture.codeRepo.FindLatest(ctx, user.ID, authentities.VerificationChannelPhone)
+		assert.Error(t, err)
+
+		require.NoError(t, usecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+	})
+
+	t.Run("throttles resends", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerificationTest(t, time.Minute)
+		createVerificationUser(t, ctx, fixture.userRepo)
+
+		require.NoError(t, usecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"}))
+
+		err := usecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"})
+		var retryErr *RetryAfterError
+		require.True(t, errors.As(err, &retryErr))
+		assert.Greater(t, retryErr.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, retryErr.RetryAfter, time.Minute)
+		assert.Len(t, fixture.mailer.Messages(), 1)
+
+		// Throttling is per channel.
+		assert.NoError(t, usecase.Execute(ctx, SendVerificationParam{Phone: "1234567890"}))
+	})
+
+	t.Run("succeeds silently for unknown contacts", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerificationTest(t, time.Minute)
+
+		err := usecase.Execute(ctx, SendVerificationParam{Email: "nobody@example.com"})
+		require.NoError(t, err)
+		assert.Empty(t, fixture.mailer.Messages())
+	})
+
+	t.Run("does not send codes for verified channels", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerificationTest(t, time.Minute)
+		user := createVerificationUser(t, ctx, fixture.userRepo)
+
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+		_, err := fixture.userRepo.Update(ctx, user)
+		require.NoError(t, err)
+
+		err = usecase.Execute(ctx, SendVerificationParam{Email: "john@example.com"})
+		require.NoError(t, err)
+		assert.Empty(t, fixture.mailer.Messages())
+	})
+
+	t.Run("returns error when no contact is given", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _ := setupVerificationTest(t, time.Minute)
+
+		err := usecase.Execute(ctx, SendVerificationParam{})
+		assert.Error(t, err)
+		assert.Equal(t, "email or phone is required", err.Error())
+	})
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+)
+
+// startSession issues a token pair for a fully authenticated user, records the
+// session it belongs to and starts a new refresh token family for it. The
+// access token carries the ID of the session, which is only known once the
+// session is stored, so it is issued last.
+func startSession(ctx context.Context, sessionRepository authrepositories.SessionRepository, refreshTokenStore authrepositories.RefreshTokenStore, tokenService *services.TokenService, user entities.User, userAgent, ipAddress string) (*services.TokenPair, error) {
+	refreshToken, refreshClaims, err := tokenService.GenerateRefreshToken(user.ID, user.Email, user.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	session, err := sessionRepository.Create(ctx, authentities.Session{
+		UserID:     user.ID,
+		TokenID:    refreshClaims.ID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		ExpiresAt:  refreshClaims.ExpiresAt.Time,
+		LastUsedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// The family is identified by its session.
+	_, err = refreshTokenStore.Save(ctx, authentities.RefreshToken{
+		TokenID:   refreshClaims.ID,
+		FamilyID:  session.ID,
+		UserID:    user.ID,
+		ExpiresAt: refreshClaims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	accessToken, err := tokenService.GenerateAccessToken(user.ID, user.Email, user.Phone, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	return &services.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshTokenID:   refreshClaims.ID,
+		RefreshExpiresAt: refreshClaims.ExpiresAt.Time,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+)
+
+type UnlockAccountUsecase struct {
+	userRepository  repositories.UserRepository
+	staffRepository accessrepositories.StaffRepository
+	authorizer      *accessservices.Authorizer
+	loginThrottle   *services.LoginThrottleService
+}
+
+func NewUnlockAccountUsecase(userRepository repositories.UserRepository, staffRepository accessrepositories.StaffRepository, authorizer *accessservices.Authorizer, loginThrottle *services.LoginThrottleService) *UnlockAccountUsecase {
+	return &UnlockAccountUsecase{
+		userRepository:  userRepository,
+		staffRepository: staffRepository,
+		authorizer:      authorizer,
+		loginThrottle:   loginThrottle,
+	}
+}
+
+// UnlockAccountParam identifies a staff member of a shop whose login lockout
+// should be lifted by ActorID, who needs the staff.unlock permission there.
+type UnlockAccountParam struct {
+	ActorID uint64
+	ShopID  uint64
+	UserID  uint64
+}
+
+func (u *UnlockAccountUsecase) Execute(ctx context.Context, param UnlockAccountParam) error {
+	if err := u.authorizer.Authorize(ctx, param.ActorID, param.ShopID, accessentities.PermissionStaffUnlock); err != nil {
+		return err
+	}
+
+	if _, err := u.staffRepository.FindByShopIDAndUserID(ctx, param.ShopID, param.UserID); err != nil {
+		return err
+	}
+
+	user, err := u.userRepository.FindByID(ctx, param.UserID)
+	if err != nil {
+		return err
+	}
+
+	return u.loginThrottle.Reset(ctx, user.Email, user.Phone, MfaThrottleIdentifier(user.ID), PasswordThrottleIdentifier(user.ID))
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+type unlockAccountFixture struct {
+	throttle *services.LoginThrottleService
+	shopID   uint64
+	owner    entities.User
+	cashier  entities.User
+	outsider entities.User
+}
+
+func setupUnlockAccountTest(t *testing.T) (*UnlockAccountUsecase, *unlockAccountFixture) {
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t, &entities.User{}, &shopentities.Shop{}, &accessentities.Role{}, &accessentities.Staff{}, &accessentities.RolePermission{}, &accessentities.StaffPermission{})
+	userRepo := repositories.NewUserRepository(db)
+	staffRepo := accessrepositories.NewStaffRepository(db)
+	roleRepo := accessrepositories.NewRoleRepository(db)
+	rolePermissionRepo := accessrepositories.NewRolePermissionRepository(db)
+
+	createUser := func(email, phone string) entities.User {
+		user, err := userRepo.Create(ctx, entities.User{FullName: "Test User", Email: email, Phone: phone, Password: "hashedpassword"})
+		require.NoError(t, err)
+		return user
+	}
+
+	fixture := &unlockAccountFixture{
+		throttle: newTestLoginThrottle(),
+		shopID:   1,
+		owner:    createUser("owner@example.com", "1111111111"),
+		cashier:  createUser("cashier@example.com", "2222222222"),
+		outsider: createUser("outsider@example.com", "3333333333"),
+	}
+
+	ownerRole, err := roleRepo.Create(ctx, accessentities.Role{Name: accessentities.OwnerRoleName, Description: "Owner", ShopID: fixture.shopID})
+	require.NoError(t, err)
+	cashierRole, err := roleRepo.Create(ctx, accessentities.Role{Name: "Cashier", Description: "Cashier", ShopID: fixture.shopID})
+	require.NoError(t, err)
+	require.NoError(t, rolePermissionRepo.ReplaceForRole(ctx, ownerRole.ID, accessentities.AllPermissions()))
+
+	_, err = staffRepo.Create(ctx, accessentities.Staff{UserID: fixture.owner.ID, RoleID: ownerRole.ID, ShopID: fixture.shopID})
+	require.NoError(t, err)
+	_, err = staffRepo.Create(ctx, accessentities.Staff{UserID: fixture.cashier.ID, RoleID: cashierRole.ID, ShopID: fixture.shopID})
+	require.NoError(t, err)
+
+	return NewUnlockAccountUsecase(userRepo, staffRepo, accessservices.NewAuthorizer(staffRepo, rolePermissionRepo, accessrepositories.NewStaffPermissionRepository(db)), fixture.throttle), fixture
+}
+
+func lockOut(t *testing.T, throttle *services.LoginThrottleService, identifier string) {
+	for i := 0; i < 3; i++ {
+		require.NoError(t, throttle.RecordFailure(context.Background(), identifier, ""))
+	}
+	require.NotZero(t, throttle.Check(context.Background(), identifier, ""))
+}
+
+func TestUnlockAccountUsecase_Execute(t *testing.T) {
+	t.Run("owner unlocks a staff member", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupUnlockAccountTest(t)
+
+		lockOut(t, fixture.throttle, fixture.cashier.Email)
+		lockOut(t, fixture.throttle, fixture.cashier.Phone)
+		lockOut(t, fixture.throttle, MfaThrottleIdentifier(fixture.cashier.ID))
+		lockOut(t, fixture.throttle, PasswordThrottleIdentifier(fixture.cashier.ID))
+
+		err := usecase.Execute(ctx, UnlockAccountParam{ActorID: fixture.owner.ID, ShopID: fixture.shopID, UserID: fixture.cashier.ID})
+		require.NoError(t, err)
+
+		assert.Zero(t, fixture.throttle.Check(ctx, fixture.cashier.Email, ""))
+		assert.Zero(t, fixture.throttle.Check(ctx, fixture.cashier.Phone, ""))
+		assert.Zero(t, fixture.throttle.Check(ctx, MfaThrottleIdentifier(fixture.cashier.ID), ""))
+		assert.Zero(t, fixture.throttle.Check(ctx, PasswordThrottleIdentifier(fixture.cashier.ID), ""))
+	})
+
+	t.Run("refuses staff without the unlock permission", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupUnlockAccountTest(t)
+
+		lockOut(t, fixture.throttle, fixture.owner.Email)
+
+		err := usecase.Execute(ctx, UnlockAccountParam{ActorID: fixture.cashier.ID, ShopID: fixture.shopID, UserID: fixture.owner.ID})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+		assert.NotZero(t, fixture.throttle.Check(ctx, fixture.owner.Email, ""))
+	})
+
+	t.Run("refuses users outside the shop", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupUnlockAccountTest(t)
+
+		err := usecase.Execute(ctx, UnlockAccountParam{ActorID: fixture.outsider.ID, ShopID: fixture.shopID, UserID: fixture.cashier.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "forbidden: missing permission staff.unlock", err.Error())
+	})
+
+	t.Run("returns error when user is not staff of the shop", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupUnlockAccountTest(t)
+
+		err := usecase.Execute(ctx, UnlockAccountParam{ActorID: fixture.owner.ID, ShopID: fixture.shopID, UserID: fixture.outsider.ID})
+		assert.Error(t, err)
+		assert.Equal(t, "staff not found", err.Error())
+	})
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type VerifyMfaUsecase struct {
+	userRepository         repositories.UserRepository
+	sessionRepository      authrepositories.SessionRepository
+	refreshTokenStore      authrepositories.RefreshTokenStore
+	totpFactorRepository   authrepositories.TotpFactorRepository
+	recoveryCodeRepository authrepositories.RecoveryCodeRepository
+	tokenService           *services.TokenService
+	totpService            *services.TotpService
+	loginThrottle          *services.LoginThrottleService
+	validator              *validator.Validate
+}
+
+func NewVerifyMfaUsecase(userRepository repositories.UserRepository, sessionRepository authrepositories.SessionRepository, refreshTokenStore authrepositories.RefreshTokenStore, totpFactorRepository authrepositories.TotpFactorRepository, recoveryCodeRepository authrepositories.RecoveryCodeRepository, tokenService *services.TokenService, totpService *services.TotpService, loginThrottle *services.LoginThrottleService) *VerifyMfaUsecase {
+	return &VerifyMfaUsecase{
+		userRepository:         userRepository,
+		sessionRepository:      sessionRepository,
+		refreshTokenStore:      refreshTokenStore,
+		totpFactorRepository:   totpFactorRepository,
+		recoveryCodeRepository: recoveryCodeRepository,
+		tokenService:           tokenService,
+		totpService:            totpService,
+		loginThrottle:          loginThrottle,
+		validator:              validator.New(),
+	}
+}
+
+// VerifyMfaParam takes the challenge token from the login step and either an
+// authenticator code or one of the recovery codes.
+type VerifyMfaParam struct {
+	MfaToken     string `validate:"required"`
+	Code         string `validate:"omitempty,len=6,numeric"`
+	RecoveryCode string `validate:"omitempty,max=32"`
+	UserAgent    string
+	IPAddress    string
+}
+
+type VerifyMfaResult struct {
+	User         *entities.User
+	AccessToken  string
+	RefreshToken string
+}
+
+func (u *VerifyMfaUsecase) Execute(ctx context.Context, param VerifyMfaParam) (*VerifyMfaResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	if param.Code == "" && param.RecoveryCode == "" {
+		return nil, errors.New("code or recovery code is required")
+	}
+
+	claims, err := u.tokenService.VerifyMfaToken(param.MfaToken)
+	if err != nil {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	userID, err := u.tokenService.GetUserID(claims)
+	if err != nil {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	user, err := u.userRepository.FindByID(ctx, userID)
+	if err != nil || user.IsSuspended() {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	factor, err := u.totpFactorRepository.FindByUserID(ctx, user.ID)
+	if err != nil || !factor.IsConfirmed() {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	// Codes are only six digits, so guesses are throttled per user just like
+	// passwords are.
+	identifier := MfaThrottleIdentifier(user.ID)
+	if wait := u.loginThrottle.Check(ctx, identifier, param.IPAddress); wait > 0 {
+		return nil, &RetryAfterError{
+			Message:    "too many failed two-factor attempts, try again later",
+			RetryAfter: wait,
+		}
+	}
+
+	if !u.verifyCode(ctx, factor, param) {
+		if err := u.loginThrottle.RecordFailure(ctx, identifier, param.IPAddress); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	if err := u.loginThrottle.Reset(ctx, identifier); err != nil {
+		return nil, err
+	}
+
+	tokenPair, err := startSession(ctx, u.sessionRepository, u.refreshTokenStore, u.tokenService, user, param.UserAgent, param.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifyMfaResult{
+		User:         &user,
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+	}, nil
+}
+
+// verifyCode consumes either the authenticator code or the recovery code.
+func (u *VerifyMfaUsecase) verifyCode(ctx context.Context, factor authentities.TotpFactor, param VerifyMfaParam) bool {
+	if param.Code != "" {
+		step, ok := u.totpService.Validate(factor.Secret, param.Code)
+		if !ok {
+			return false
+		}
+		return u.totpFactorRepository.MarkStepUsed(ctx, factor.ID, step) == nil
+	}
+
+	return u.recoveryCodeRepository.Consume(ctx, factor.UserID, services.HashRecoveryCode(param.RecoveryCode), time.Now()) == nil
+}
+
+// MfaThrottleIdentifier is the identifier failed two-factor attempts of a
+// user are counted under.
+func MfaThrottleIdentifier(userID uint64) string {
+	return fmt.Sprintf("mfa:%d", userID)
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+type verifyMfaFixture struct {
+	db           *gorm.DB
+	totpService  *services.TotpService
+	tokenService *services.TokenService
+	sessionRepo  authrepositories.SessionRepository
+	user         entities.User
+	secret       string
+	recovery     []string
+}
+
+func setupVerifyMfaTest(t *testing.T) (*VerifyMfaUsecase, *verifyMfaFixture) {
+	ctx := context.Background()
+	db, totpService, user := setupTotpTest(t)
+	secret, recovery := createConfirmedTotpFactor(t, ctx, db, totpService, user.ID)
+
+	tokenService, err := services.NewTokenService(&config.Config{
+		JwtSecret:         "test-secret-key-minimum-32-characters-long",
+		JwtIssuer:         "test-issuer",
+		JwtAccessExpires:  "15m",
+		JwtRefreshExpires: "168h",
+	})
+	require.NoError(t, err)
+
+	fixture := &verifyMfaFixture{
+		db:           db,
+		totpService:  totpService,
+		tokenService: tokenService,
+		sessionRepo:  authrepositories.NewSessionRepository(db),
+		user:         user,
+		secret:       secret,
+		recovery:     recovery,
+	}
+
+	usecase := NewVerifyMfaUsecase(
+		repositories.NewUserRepository(db),
+		fixture.sessionRepo,
+		authrepositories.NewInMemoryRefreshTokenStore(),
+		authrepositories.NewTotpFactorRepository(db),
+		authrepositories.NewRecoveryCodeRepository(db),
+		tokenService,
+		totpService,
+		newTestLoginThrottle(),
+	)
+
+	return usecase, fixture
+}
+
+func (f *verifyMfaFixture) mfaToken(t *testing.T) string {
+	token, err := f.tokenService.GenerateMfaToken(f.user.ID, f.user.Email, f.user.Phone)
+	require.NoError(t, err)
+	return token
+}
+
+func TestVerifyMfaUsecase_Execute(t *testing.T) {
+	t.Run("issues tokens for a valid code", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		code, err := fixture.totpService.Code(fixture.secret)
+		require.NoError(t, err)
+
+		result, err := usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), Code: code})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.user.ID, result.User.ID)
+		assert.NotEmpty(t, result.AccessToken)
+		assert.NotEmpty(t, result.RefreshToken)
+		assert.Len(t, fixture.sessionRepo.FindActiveByUserID(ctx, fixture.user.ID), 1)
+	})
+
+	t.Run("rejects a replayed code", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		code, err := fixture.totpService.Code(fixture.secret)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), Code: code})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), Code: code})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid two-factor code", err.Error())
+	})
+
+	t.Run("accepts each recovery code once", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		result, err := usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), RecoveryCode: fixture.recovery[0]})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.AccessToken)
+
+		_, err = usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), RecoveryCode: fixture.recovery[0]})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid two-factor code", err.Error())
+	})
+
+	t.Run("returns error for a wrong code", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		code, err := fixture.totpService.Code(fixture.secret)
+		require.NoError(t, err)
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		_, err = usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), Code: wrong})
+		assert.Error(t, err)
+		assert.Empty(t, fixture.sessionRepo.FindActiveByUserID(ctx, fixture.user.ID))
+	})
+
+	t.Run("rejects an access token in place of the MFA token", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		accessToken, err := fixture.tokenService.GenerateAccessToken(fixture.user.ID, fixture.user.Email, fixture.user.Phone, 1)
+		require.NoError(t, err)
+		code, err := fixture.totpService.Code(fixture.secret)
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, VerifyMfaParam{MfaToken: accessToken, Code: code})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid or expired MFA token", err.Error())
+	})
+
+	t.Run("returns error when no code is given", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		_, err := usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t)})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is required")
+	})
+
+	t.Run("locks out guessing of codes", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, fixture := setupVerifyMfaTest(t)
+
+		for i := 0; i < 3; i++ {
+			_, err := usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), RecoveryCode: "wrong-guess"})
+			assert.Equal(t, "invalid two-factor code", err.Error())
+		}
+
+		_, err := usecase.Execute(ctx, VerifyMfaParam{MfaToken: fixture.mfaToken(t), RecoveryCode: fixture.recovery[0]})
+		var retryErr *RetryAfterError
+		assert.ErrorAs(t, err, &retryErr)
+	})
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+)
+
+// Category groups the products of a shop. Categories nest through ParentID;
+// top-level categories have none.
+type Category struct {
+	ID          uint64    `gorm:"primaryKey;column:id" json:"id"`
+	ShopID      uint64    `gorm:"column:shop_id;not null;index:idx_categories_shop_id" json:"shop_id"`
+	ParentID    *uint64   `gorm:"column:parent_id" json:"parent_id"`
+	Name        string    `gorm:"column:name;not null" json:"name"`
+	Description string    `gorm:"column:description;not null" json:"description"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
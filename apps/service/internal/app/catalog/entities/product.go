@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Product is an item a shop sells. What is actually sold and stocked are its
+// variants: one per combination of the product's option values, such as
+// every size and colour of a shirt. Products without options have a single
+// variant.
+type Product struct {
+	ID          uint64          `gorm:"primaryKey;column:id" json:"id"`
+	ShopID      uint64          `gorm:"column:shop_id;not null;index:idx_products_shop_id" json:"shop_id"`
+	CategoryID  *uint64         `gorm:"column:category_id;index:idx_products_category_id" json:"category_id"`
+	Name        string          `gorm:"column:name;not null" json:"name"`
+	Description string          `gorm:"column:description;not null" json:"description"`
+	Options     []ProductOption `gorm:"column:options;type:jsonb;serializer:json;not null" json:"options"`
+	CreatedAt   time.Time       `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time       `gorm:"column:updated_at" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `gorm:"column:deleted_at;index" json:"deleted_at"`
+
+	Variants []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
+}
+
+func (Product) TableName() string {
+	return "products"
+}
+
+// ProductOption is a dimension the variants of a product differ in, like
+// Size, with the values it can take.
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariant is a sellable version of a product. Options holds its value
+// for every option of the product, keyed by option name. Price is in the
+// minor unit of the shop's currency, such as cents. SKUs are unique among
+// the live variants of a shop.
+type ProductVariant struct {
+	ID        uint64            `gorm:"primaryKey;column:id" json:"id"`
+	ShopID    uint64            `gorm:"column:shop_id;not null;uniqueIndex:idx_product_variants_shop_id_sku,where:deleted_at IS NULL;index:idx_product_variants_shop_id_barcode" json:"shop_id"`
+	ProductID uint64            `gorm:"column:product_id;not null;index:idx_product_variants_product_id" json:"product_id"`
+	SKU       string            `gorm:"column:sku;not null;uniqueIndex:idx_product_variants_shop_id_sku,where:deleted_at IS NULL" json:"sku"`
+	Barcode   string            `gorm:"column:barcode;not null;index:idx_product_variants_shop_id_barcode" json:"barcode"`
+	Price     int64             `gorm:"column:price;not null" json:"price"`
+	Options   map[string]string `gorm:"column:options;type:jsonb;serializer:json;not null" json:"options"`
+	CreatedAt time.Time         `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time         `gorm:"column:updated_at" json:"updated_at"`
+	DeletedAt gorm.DeletedAt    `gorm:"column:deleted_at;index" json:"deleted_at"`
+}
+
+func (ProductVariant) TableName() string {
+	return "product_variants"
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+)
+
+type CategoryRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Category, error)
+	// FindByShopID returns every category of the shop, nested or not,
+	// ordered by name.
+	FindByShopID(ctx context.Context, shopID uint64) []entities.Category
+	Create(ctx context.Context, category entities.Category) (entities.Category, error)
+	Update(ctx context.Context, category entities.Category) (entities.Category, error)
+	Delete(ctx context.Context, category entities.Category) error
+}
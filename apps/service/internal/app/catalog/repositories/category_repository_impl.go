@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id uint64) (entities.Category, error) {
+	var category entities.Category
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return category, errors.New("category not found")
+		}
+		return category, err
+	}
+	return category, nil
+}
+
+func (r *categoryRepository) FindByShopID(ctx context.Context, shopID uint64) []entities.Category {
+	var categories []entities.Category
+	r.db.WithContext(ctx).Where("shop_id = ?", shopID).Order("name").Order("id").Find(&categories)
+	return categories
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category entities.Category) (entities.Category, error) {
+	err := r.db.WithContext(ctx).Create(&category).Error
+	if err != nil {
+		return category, err
+	}
+	return category, nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category entities.Category) (entities.Category, error) {
+	err := r.db.WithContext(ctx).Save(&category).Error
+	if err != nil {
+		return category, err
+	}
+	return category, nil
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, category entities.Category) error {
+	return r.db.WithContext(ctx).Delete(&category).Error
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupCategoryTest(t *testing.T) CategoryRepository {
+	db := testutil.SetupTestDB(t, &entities.Category{})
+	return NewCategoryRepository(db)
+}
+
+func TestCategoryRepository_FindByShopID(t *testing.T) {
+	t.Run("returns the categories of the shop ordered by name", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupCategoryTest(t)
+
+		drinks, err := repo.Create(ctx, entities.Category{ShopID: 1, Name: "Drinks"})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, entities.Category{ShopID: 1, ParentID: &drinks.ID, Name: "Coffee"})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, entities.Category{ShopID: 2, Name: "Bakery"})
+		require.NoError(t, err)
+
+		categories := repo.FindByShopID(ctx, 1)
+		require.Len(t, categories, 2)
+		assert.Equal(t, "Coffee", categories[0].Name)
+		assert.Equal(t, drinks.ID, *categories[0].ParentID)
+		assert.Equal(t, "Drinks", categories[1].Name)
+		assert.Nil(t, categories[1].ParentID)
+	})
+}
+
+func TestCategoryRepository_FindByID(t *testing.T) {
+	t.Run("returns error when category not found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupCategoryTest(t)
+
+		_, err := repo.FindByID(ctx, 999)
+		assert.Error(t, err)
+		assert.Equal(t, "category not found", err.Error())
+	})
+}
+
+func TestCategoryRepository_Delete(t *testing.T) {
+	t.Run("deletes the category", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupCategoryTest(t)
+
+		category, err := repo.Create(ctx, entities.Category{ShopID: 1, Name: "Drinks"})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, category))
+
+		_, err = repo.FindByID(ctx, category.ID)
+		assert.Error(t, err)
+	})
+}
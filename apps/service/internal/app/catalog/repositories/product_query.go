@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// ProductSortName is the order product lists come in.
+const ProductSortName = "name"
+
+// ProductQuery filters and pages the products of a shop, ordered by name.
+// Search matches the product name case-insensitively or a variant's SKU or
+// barcode exactly, and After, when set, resumes the list past the product it
+// points to.
+type ProductQuery struct {
+	ShopID     uint64
+	CategoryID *uint64
+	Search     string
+	After      *pagination.Cursor
+	Limit      int
+}
+
+// ProductQueryScope applies the query to a statement selecting from the
+// products table.
+func ProductQueryScope(query ProductQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("products.shop_id = ?", query.ShopID)
+		if query.CategoryID != nil {
+			db = db.Where("products.category_id = ?", *query.CategoryID)
+		}
+		if search := strings.TrimSpace(query.Search); search != "" {
+			db = db.Where(
+				`(LOWER(products.name) LIKE ? ESCAPE '\' OR EXISTS (SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL AND (product_variants.sku = ? OR product_variants.barcode = ?)))`,
+				"%"+escapeLike(strings.ToLower(search))+"%", search, search,
+			)
+		}
+		if query.After != nil {
+			db = db.Where(
+				"(products.name > ? OR (products.name = ? AND products.id > ?))",
+				query.After.Value, query.After.Value, query.After.ID,
+			)
+		}
+
+		db = db.Order("products.name ASC").Order("products.id ASC")
+		if query.Limit > 0 {
+			db = db.Limit(query.Limit)
+		}
+		return db
+	}
+}
+
+// ProductCursor returns the cursor resuming a product list after product.
+func ProductCursor(product entities.Product) pagination.Cursor {
+	return pagination.Cursor{Sort: ProductSortName, Value: product.Name, ID: product.ID}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+)
+
+// ProductRepository loads products with their live variants, ordered by ID.
+type ProductRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Product, error)
+	Search(ctx context.Context, query ProductQuery) []entities.Product
+	// FindVariantByID, FindVariantBySKU and FindVariantByBarcode only
+	// consider live variants.
+	FindVariantByID(ctx context.Context, id uint64) (entities.ProductVariant, error)
+	FindVariantBySKU(ctx context.Context, shopID uint64, sku string) (entities.ProductVariant, error)
+	FindVariantByBarcode(ctx context.Context, shopID uint64, barcode string) (entities.ProductVariant, error)
+	// Create stores the product together with its Variants.
+	Create(ctx context.Context, product entities.Product) (entities.Product, error)
+	// Update saves the product's own fields; its variants are saved with
+	// CreateVariant, UpdateVariant and DeleteVariant.
+	Update(ctx context.Context, product entities.Product) (entities.Product, error)
+	// Delete removes the product and its variants.
+	Delete(ctx context.Context, product entities.Product) error
+	CreateVariant(ctx context.Context, variant entities.ProductVariant) (entities.ProductVariant, error)
+	UpdateVariant(ctx context.Context, variant entities.ProductVariant) (entities.ProductVariant, error)
+	DeleteVariant(ctx context.Context, variant entities.ProductVariant) error
+	// ClearCategory leaves the products of the category uncategorized.
+	ClearCategory(ctx context.Context, categoryID uint64) error
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+)
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+func NewProductRepository(db *gorm.DB) ProductRepository {
+	return &productRepository{
+		db: db,
+	}
+}
+
+func (r *productRepository) FindByID(ctx context.Context, id uint64) (entities.Product, error) {
+	var product entities.Product
+	err := r.db.WithContext(ctx).Where("id = ?", id).Preload("Variants", orderVariants).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return product, errors.New("product not found")
+		}
+		return product, err
+	}
+	return product, nil
+}
+
+func (r *productRepository) Search(ctx context.Context, query ProductQuery) []entities.Product {
+	var products []entities.Product
+	r.db.WithContext(ctx).Scopes(ProductQueryScope(query)).Preload("Variants", orderVariants).Find(&products)
+	return products
+}
+
+func (r *productRepository) FindVariantByID(ctx context.Context, id uint64) (entities.ProductVariant, error) {
+	return r.firstVariant(ctx, "id = ?", id)
+}
+
+func (r *productRepository) FindVariantBySKU(ctx context.Context, shopID uint64, sku string) (entities.ProductVariant, error) {
+	return r.firstVariant(ctx, "shop_id = ? AND sku = ?", shopID, sku)
+}
+
+func (r *productRepository) FindVariantByBarcode(ctx context.Context, shopID uint64, barcode string) (entities.ProductVariant, error) {
+	return r.firstVariant(ctx, "shop_id = ? AND barcode = ?", shopID, barcode)
+}
+
+func (r *productRepository) firstVariant(ctx context.Context, query string, args ...any) (entities.ProductVariant, error) {
+	var variant entities.ProductVariant
+	err := r.db.WithContext(ctx).Where(query, args...).First(&variant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return variant, errors.New("variant not found")
+		}
+		return variant, err
+	}
+	return variant, nil
+}
+
+func (r *productRepository) Create(ctx context.Context, product entities.Product) (entities.Product, error) {
+	err := r.db.WithContext(ctx).Create(&product).Error
+	if err != nil {
+		return product, err
+	}
+	return product, nil
+}
+
+func (r *productRepository) Update(ctx context.Context, product entities.Product) (entities.Product, error) {
+	err := r.db.WithContext(ctx).Omit("Variants").Save(&product).Error
+	if err != nil {
+		return product, err
+	}
+	return product, nil
+}
+
+func (r *productRepository) Delete(ctx context.Context, product entities.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", product.ID).Delete(&entities.ProductVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&product).Error
+	})
+}
+
+func (r *productRepository) CreateVariant(ctx context.Context, variant entities.ProductVariant) (entities.ProductVariant, error) {
+	err := r.db.WithContext(ctx).Create(&variant).Error
+	if err != nil {
+		return variant, err
+	}
+	return variant, nil
+}
+
+func (r *productRepository) UpdateVariant(ctx context.Context, variant entities.ProductVariant) (entities.ProductVariant, error) {
+	err := r.db.WithContext(ctx).Save(&variant).Error
+	if err != nil {
+		return variant, err
+	}
+	return variant, nil
+}
+
+func (r *productRepository) DeleteVariant(ctx context.Context, variant entities.ProductVariant) error {
+	return r.db.WithContext(ctx).Delete(&variant).Error
+}
+
+func (r *productRepository) ClearCategory(ctx context.Context, categoryID uint64) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.Product{}).
+		Where("category_id = ?", categoryID).
+		Update("category_id", nil).Error
+}
+
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
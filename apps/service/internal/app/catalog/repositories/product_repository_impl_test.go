@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func newTestProduct(shopID uint64, name string, skus ...string) entities.Product {
+	product := entities.Product{
+		ShopID:  shopID,
+		Name:    name,
+		Options: []entities.ProductOption{},
+	}
+	for _, sku := range skus {
+		product.Variants = append(product.Variants, entities.ProductVariant{
+			ShopID:  shopID,
+			SKU:     sku,
+			Price:   1000,
+			Options: map[string]string{},
+		})
+	}
+	return product
+}
+
+func setupProductTest(t *testing.T) ProductRepository {
+	db := testutil.SetupTestDB(t, &entities.Product{}, &entities.ProductVariant{})
+	return NewProductRepository(db)
+}
+
+func TestProductRepository_Create(t *testing.T) {
+	t.Run("stores the product with its options and variants", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupProductTest(t)
+
+		product := entities.Product{
+			ShopID:  1,
+			Name:    "T-Shirt",
+			Options: []entities.ProductOption{{Name: "Size", Values: []string{"S", "M"}}},
+			Variants: []entities.ProductVariant{
+				{ShopID: 1, SKU: "TS-S", Price: 1500, Options: map[string]string{"Size": "S"}},
+				{ShopID: 1, SKU: "TS-M", Barcode: "4006381333931", Price: 1500, Options: map[string]string{"Size": "M"}},
+			},
+		}
+
+		created, err := repo.Create(ctx, product)
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, product.Options, found.Options)
+		require.Len(t, found.Variants, 2)
+		assert.Equal(t, "TS-S", found.Variants[0].SKU)
+		assert.Equal(t, map[string]string{"Size": "M"}, found.Variants[1].Options)
+
+		variant, err := repo.FindVariantByBarcode(ctx, 1, "4006381333931")
+		require.NoError(t, err)
+		assert.Equal(t, "TS-M", variant.SKU)
+	})
+
+	t.Run("fails for a SKU taken by a live variant of the shop", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupProductTest(t)
+
+		_, err := repo.Create(ctx, newTestProduct(1, "Espresso", "COF-1"))
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, newTestProduct(1, "Latte", "COF-1"))
+		assert.Error(t, err)
+
+		_, err = repo.Create(ctx, newTestProduct(2, "Latte", "COF-1"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestProductRepository_Delete(t *testing.T) {
+	t.Run("removes the product and frees the SKUs of its variants", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupProductTest(t)
+
+		created, err := repo.Create(ctx, newTestProduct(1, "Espresso", "COF-1"))
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, created))
+
+		_, err = repo.FindByID(ctx, created.ID)
+		assert.Error(t, err)
+		assert.Equal(t, "product not found", err.Error())
+
+		_, err = repo.FindVariantBySKU(ctx, 1, "COF-1")
+		assert.Error(t, err)
+		assert.Equal(t, "variant not found", err.Error())
+
+		_, err = repo.FindVariantByID(ctx, created.Variants[0].ID)
+		assert.Error(t, err)
+
+		_, err = repo.Create(ctx, newTestProduct(1, "Espresso", "COF-1"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestProductRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	repo := setupProductTest(t)
+
+	categoryID := uint64(7)
+	latte := newTestProduct(1, "Latte", "COF-2")
+	latte.CategoryID = &categoryID
+	for _, product := range []entities.Product{newTestProduct(1, "Espresso", "COF-1"), latte, newTestProduct(1, "Croissant", "BAK-1"), newTestProduct(2, "Mocha", "COF-3")} {
+		_, err := repo.Create(ctx, product)
+		require.NoError(t, err)
+	}
+
+	names := func(products []entities.Product) []string {
+		result := make([]string, len(products))
+		for i, product := range products {
+			result[i] = product.Name
+		}
+		return result
+	}
+
+	t.Run("lists the products of the shop by name", func(t *testing.T) {
+		products := repo.Search(ctx, ProductQuery{ShopID: 1})
+		assert.Equal(t, []string{"Croissant", "Espresso", "Latte"}, names(products))
+		assert.Len(t, products[0].Variants, 1)
+	})
+
+	t.Run("filters by category", func(t *testing.T) {
+		products := repo.Search(ctx, ProductQuery{ShopID: 1, CategoryID: &categoryID})
+		assert.Equal(t, []string{"Latte"}, names(products))
+	})
+
+	t.Run("searches names and SKUs", func(t *testing.T) {
+		assert.Equal(t, []string{"Espresso"}, names(repo.Search(ctx, ProductQuery{ShopID: 1, Search: "PRESS"})))
+		assert.Equal(t, []string{"Croissant"}, names(repo.Search(ctx, ProductQuery{ShopID: 1, Search: "BAK-1"})))
+	})
+
+	t.Run("resumes after a cursor", func(t *testing.T) {
+		first := repo.Search(ctx, ProductQuery{ShopID: 1, Limit: 1})
+		require.Len(t, first, 1)
+
+		after := ProductCursor(first[0])
+		products := repo.Search(ctx, ProductQuery{ShopID: 1, After: &after})
+		assert.Equal(t, []string{"Espresso", "Latte"}, names(products))
+	})
+}
+
+func TestProductRepository_ClearCategory(t *testing.T) {
+	t.Run("leaves the products of the category uncategorized", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setupProductTest(t)
+
+		categoryID := uint64(7)
+		product := newTestProduct(1, "Latte", "COF-2")
+		product.CategoryID = &categoryID
+		created, err := repo.Create(ctx, product)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.ClearCategory(ctx, categoryID))
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found.CategoryID)
+	})
+}
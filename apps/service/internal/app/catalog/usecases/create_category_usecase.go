@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CreateCategoryUsecase struct {
+	categoryRepository repositories.CategoryRepository
+	validator          *validator.Validate
+}
+
+func NewCreateCategoryUsecase(categoryRepository repositories.CategoryRepository) *CreateCategoryUsecase {
+	return &CreateCategoryUsecase{
+		categoryRepository: categoryRepository,
+		validator:          validator.New(),
+	}
+}
+
+// CreateCategoryParam creates a category, nested under ParentID when set.
+type CreateCategoryParam struct {
+	ShopID      uint64 `validate:"required"`
+	ParentID    *uint64
+	Name        string `validate:"required,min=1,max=100"`
+	Description string `validate:"omitempty,max=1000"`
+}
+
+type CreateCategoryResult struct {
+	Category *entities.Category
+}
+
+// Execute creates a category of the shop. Names are unique among the
+// categories sharing a parent.
+func (u *CreateCategoryUsecase) Execute(ctx context.Context, param CreateCategoryParam) (*CreateCategoryResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	categories := u.categoryRepository.FindByShopID(ctx, param.ShopID)
+	if param.ParentID != nil && !containsCategory(categories, *param.ParentID) {
+		return nil, errors.New("validation failed: parent category not found")
+	}
+
+	name := strings.TrimSpace(param.Name)
+	if err := ensureCategoryNameAvailable(categories, param.ParentID, name, 0); err != nil {
+		return nil, err
+	}
+
+	category, err := u.categoryRepository.Create(ctx, entities.Category{
+		ShopID:      param.ShopID,
+		ParentID:    param.ParentID,
+		Name:        name,
+		Description: strings.TrimSpace(param.Description),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return &CreateCategoryResult{
+		Category: &category,
+	}, nil
+}
+
+// findShopCategory loads a category, treating categories of other shops as
+// missing.
+func findShopCategory(ctx context.Context, categoryRepository repositories.CategoryRepository, shopID, categoryID uint64) (entities.Category, error) {
+	category, err := categoryRepository.FindByID(ctx, categoryID)
+	if err != nil {
+		return category, err
+	}
+	if category.ShopID != shopID {
+		return entities.Category{}, errors.New("category not found")
+	}
+	return category, nil
+}
+
+func containsCategory(categories []entities.Category, id uint64) bool {
+	for _, category := range categories {
+		if category.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func ensureCategoryNameAvailable(categories []entities.Category, parentID *uint64, name string, exceptID uint64) error {
+	for _, category := range categories {
+		if category.ID != exceptID && sameParent(category.ParentID, parentID) && strings.EqualFold(category.Name, name) {
+			return errors.New("category with this name already exists")
+		}
+	}
+	return nil
+}
+
+func sameParent(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCategoryUsecase_Execute(t *testing.T) {
+	t.Run("creates a nested category", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateCategoryUsecase(fixture.categoryRepo)
+
+		result, err := usecase.Execute(ctx, CreateCategoryParam{
+			ShopID:   1,
+			ParentID: &fixture.drinks.ID,
+			Name:     " Tea ",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Tea", result.Category.Name)
+		assert.Equal(t, fixture.drinks.ID, *result.Category.ParentID)
+	})
+
+	t.Run("returns error for a parent of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateCategoryUsecase(fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, CreateCategoryParam{
+			ShopID:   2,
+			ParentID: &fixture.drinks.ID,
+			Name:     "Tea",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: parent category not found", err.Error())
+	})
+
+	t.Run("returns error when a sibling has the name", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateCategoryUsecase(fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, CreateCategoryParam{
+			ShopID:   1,
+			ParentID: &fixture.drinks.ID,
+			Name:     "coffee",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "category with this name already exists", err.Error())
+
+		_, err = usecase.Execute(ctx, CreateCategoryParam{ShopID: 1, Name: "Coffee"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("validates name is required", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateCategoryUsecase(fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, CreateCategoryParam{ShopID: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
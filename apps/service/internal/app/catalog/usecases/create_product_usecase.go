@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CreateProductUsecase struct {
+	productRepository  repositories.ProductRepository
+	categoryRepository repositories.CategoryRepository
+	validator          *validator.Validate
+}
+
+func NewCreateProductUsecase(productRepository repositories.ProductRepository, categoryRepository repositories.CategoryRepository) *CreateProductUsecase {
+	return &CreateProductUsecase{
+		productRepository:  productRepository,
+		categoryRepository: categoryRepository,
+		validator:          validator.New(),
+	}
+}
+
+// ProductOptionParam names an option of a product and the values it takes.
+type ProductOptionParam struct {
+	Name   string   `validate:"required,max=50"`
+	Values []string `validate:"required,min=1,dive,required,max=50"`
+}
+
+// ProductVariantParam describes a variant of a product. Options holds its
+// value for every option of the product. ID is only set when updating an
+// existing variant.
+type ProductVariantParam struct {
+	ID      uint64
+	SKU     string `validate:"required,max=64"`
+	Barcode string `validate:"omitempty,max=64"`
+	Price   int64  `validate:"min=0"`
+	Options map[string]string
+}
+
+type CreateProductParam struct {
+	ShopID      uint64 `validate:"required"`
+	CategoryID  *uint64
+	Name        string                `validate:"required,min=1,max=255"`
+	Description string                `validate:"omitempty,max=2000"`
+	Options     []ProductOptionParam  `validate:"dive"`
+	Variants    []ProductVariantParam `validate:"required,min=1,dive"`
+}
+
+type CreateProductResult struct {
+	Product *entities.Product
+}
+
+// Execute creates a product of the shop with its variants. Every variant
+// must pick one value of each option, no two variants may pick the same
+// combination, and SKUs and barcodes must not be in use by another variant
+// of the shop.
+func (u *CreateProductUsecase) Execute(ctx context.Context, param CreateProductParam) (*CreateProductResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	if param.CategoryID != nil {
+		if _, err := findShopCategory(ctx, u.categoryRepository, param.ShopID, *param.CategoryID); err != nil {
+			return nil, errors.New("validation failed: category not found")
+		}
+	}
+
+	options, err := normalizeOptions(param.Options)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := buildVariants(param.ShopID, options, param.Variants)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureCodesAvailable(ctx, u.productRepository, param.ShopID, 0, variants); err != nil {
+		return nil, err
+	}
+
+	product, err := u.productRepository.Create(ctx, entities.Product{
+		ShopID:      param.ShopID,
+		CategoryID:  param.CategoryID,
+		Name:        strings.TrimSpace(param.Name),
+		Description: strings.TrimSpace(param.Description),
+		Options:     options,
+		Variants:    variants,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return &CreateProductResult{
+		Product: &product,
+	}, nil
+}
+
+// normalizeOptions trims option names and values and rejects duplicates.
+func normalizeOptions(params []ProductOptionParam) ([]entities.ProductOption, error) {
+	options := make([]entities.ProductOption, 0, len(params))
+	for _, param := range params {
+		name := strings.TrimSpace(param.Name)
+		for _, option := range options {
+			if strings.EqualFold(option.Name, name) {
+				return nil, fmt.Errorf("validation failed: option %s is listed twice", name)
+			}
+		}
+
+		option := entities.ProductOption{Name: name, Values: make([]string, 0, len(param.Values))}
+		for _, value := range param.Values {
+			value = strings.TrimSpace(value)
+			if slices.Contains(option.Values, value) {
+				return nil, fmt.Errorf("validation failed: value %s of option %s is listed twice", value, name)
+			}
+			option.Values = append(option.Values, value)
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// buildVariants checks the variants against the options of their product
+// and returns them ready to be stored. IDs are carried over.
+func buildVariants(shopID uint64, options []entities.ProductOption, params []ProductVariantParam) ([]entities.ProductVariant, error) {
+	if len(options) == 0 && len(params) > 1 {
+		return nil, errors.New("validation failed: a product without options has a single variant")
+	}
+
+	variants := make([]entities.ProductVariant, 0, len(params))
+	skus := make(map[string]bool, len(params))
+	barcodes := make(map[string]bool, len(params))
+	combinations := make(map[string]string, len(params))
+	for _, param := range params {
+		sku := strings.TrimSpace(param.SKU)
+		if skus[sku] {
+			return nil, fmt.Errorf("validation failed: SKU %s is used by more than one variant", sku)
+		}
+		skus[sku] = true
+
+		barcode := strings.TrimSpace(param.Barcode)
+		if barcode != "" {
+			if barcodes[barcode] {
+				return nil, fmt.Errorf("validation failed: barcode %s is used by more than one variant", barcode)
+			}
+			barcodes[barcode] = true
+		}
+
+		values, err := variantOptions(options, sku, param.Options)
+		if err != nil {
+			return nil, err
+		}
+		key := combinationKey(values)
+		if other, ok := combinations[key]; ok {
+			return nil, fmt.Errorf("validation failed: variants %s and %s have the same options", other, sku)
+		}
+		combinations[key] = sku
+
+		variants = append(variants, entities.ProductVariant{
+			ID:      param.ID,
+			ShopID:  shopID,
+			SKU:     sku,
+			Barcode: barcode,
+			Price:   param.Price,
+			Options: values,
+		})
+	}
+	return variants, nil
+}
+
+// variantOptions matches the option values of a variant to the options of
+// its product, keyed by the product's spelling of each option name.
+func variantOptions(options []entities.ProductOption, sku string, params map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(options))
+	for _, option := range options {
+		value, ok := lookupOption(params, option.Name)
+		if !ok || !slices.Contains(option.Values, value) {
+			return nil, fmt.Errorf("validation failed: variant %s must have one of the values of option %s", sku, option.Name)
+		}
+		values[option.Name] = value
+	}
+	if len(params) != len(values) {
+		return nil, fmt.Errorf("validation failed: variant %s has options the product does not have", sku)
+	}
+	return values, nil
+}
+
+func lookupOption(params map[string]string, name string) (string, bool) {
+	for key, value := range params {
+		if strings.EqualFold(strings.TrimSpace(key), name) {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+func combinationKey(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for name, value := range values {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
+
+// ensureCodesAvailable fails when a SKU or barcode of the variants is in use
+// by a variant of another product of the shop than productID.
+func ensureCodesAvailable(ctx context.Context, productRepository repositories.ProductRepository, shopID, productID uint64, variants []entities.ProductVariant) error {
+	for _, variant := range variants {
+		existing, err := productRepository.FindVariantBySKU(ctx, shopID, variant.SKU)
+		if err == nil && existing.ProductID != productID {
+			return fmt.Errorf("sku %s already exists", variant.SKU)
+		}
+		if variant.Barcode == "" {
+			continue
+		}
+		existing, err = productRepository.FindVariantByBarcode(ctx, shopID, variant.Barcode)
+		if err == nil && existing.ProductID != productID {
+			return fmt.Errorf("barcode %s already exists", variant.Barcode)
+		}
+	}
+	return nil
+}
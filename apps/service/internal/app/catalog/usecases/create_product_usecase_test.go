@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+// catalogFixture is shop 1 with a Drinks category holding a Coffee
+// subcategory.
+type catalogFixture struct {
+	db           *gorm.DB
+	categoryRepo repositories.CategoryRepository
+	productRepo  repositories.ProductRepository
+	drinks       entities.Category
+	coffee       entities.Category
+}
+
+func setupCatalogTest(t *testing.T) *catalogFixture {
+	db := testutil.SetupTestDB(t, &entities.Category{}, &entities.Product{}, &entities.ProductVariant{})
+	ctx := context.Background()
+
+	fixture := &catalogFixture{
+		db:           db,
+		categoryRepo: repositories.NewCategoryRepository(db),
+		productRepo:  repositories.NewProductRepository(db),
+	}
+
+	var err error
+	fixture.drinks, err = fixture.categoryRepo.Create(ctx, entities.Category{ShopID: 1, Name: "Drinks"})
+	require.NoError(t, err)
+	fixture.coffee, err = fixture.categoryRepo.Create(ctx, entities.Category{ShopID: 1, ParentID: &fixture.drinks.ID, Name: "Coffee"})
+	require.NoError(t, err)
+
+	return fixture
+}
+
+// createTestProduct creates a product of shop 1 without options.
+func createTestProduct(t *testing.T, fixture *catalogFixture, categoryID *uint64, name, sku string) entities.Product {
+	result, err := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo).Execute(context.Background(), CreateProductParam{
+		ShopID:     1,
+		CategoryID: categoryID,
+		Name:       name,
+		Variants:   []ProductVariantParam{{SKU: sku, Price: 300}},
+	})
+	require.NoError(t, err)
+	return *result.Product
+}
+
+func shirtParam() CreateProductParam {
+	return CreateProductParam{
+		ShopID: 1,
+		Name:   "T-Shirt",
+		Options: []ProductOptionParam{
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Colour", Values: []string{"Red"}},
+		},
+		Variants: []ProductVariantParam{
+			{SKU: "TS-S-RED", Price: 1500, Options: map[string]string{"Size": "S", "Colour": "Red"}},
+			{SKU: "TS-M-RED", Barcode: "4006381333931", Price: 1700, Options: map[string]string{"size": "M", "colour": "Red"}},
+		},
+	}
+}
+
+func TestCreateProductUsecase_Execute(t *testing.T) {
+	t.Run("creates a product with a variant per option combination", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		param := shirtParam()
+		param.CategoryID = &fixture.drinks.ID
+		result, err := usecase.Execute(ctx, param)
+		require.NoError(t, err)
+
+		found, err := fixture.productRepo.FindByID(ctx, result.Product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fixture.drinks.ID, *found.CategoryID)
+		assert.Equal(t, []entities.ProductOption{
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Colour", Values: []string{"Red"}},
+		}, found.Options)
+		require.Len(t, found.Variants, 2)
+		assert.Equal(t, map[string]string{"Size": "M", "Colour": "Red"}, found.Variants[1].Options)
+		assert.Equal(t, int64(1700), found.Variants[1].Price)
+		assert.Equal(t, uint64(1), found.Variants[1].ShopID)
+	})
+
+	t.Run("returns error when a variant misses an option", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		param := shirtParam()
+		param.Variants[0].Options = map[string]string{"Size": "S"}
+		_, err := usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: variant TS-S-RED must have one of the values of option Colour", err.Error())
+
+		param.Variants[0].Options = map[string]string{"Size": "XL", "Colour": "Red"}
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: variant TS-S-RED must have one of the values of option Size", err.Error())
+
+		param.Variants[0].Options = map[string]string{"Size": "S", "Colour": "Red", "Fit": "Slim"}
+		_, err = usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: variant TS-S-RED has options the product does not have", err.Error())
+	})
+
+	t.Run("returns error when two variants have the same options", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		param := shirtParam()
+		param.Variants[1].Options = map[string]string{"Size": "S", "Colour": "Red"}
+		_, err := usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: variants TS-S-RED and TS-M-RED have the same options", err.Error())
+	})
+
+	t.Run("returns error for more than one variant without options", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, CreateProductParam{
+			ShopID:   1,
+			Name:     "Espresso",
+			Variants: []ProductVariantParam{{SKU: "COF-1"}, {SKU: "COF-2"}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: a product without options has a single variant", err.Error())
+	})
+
+	t.Run("returns error when a SKU or barcode is in use in the shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, shirtParam())
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, CreateProductParam{
+			ShopID:   1,
+			Name:     "Polo",
+			Variants: []ProductVariantParam{{SKU: "TS-M-RED"}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "sku TS-M-RED already exists", err.Error())
+
+		_, err = usecase.Execute(ctx, CreateProductParam{
+			ShopID:   1,
+			Name:     "Polo",
+			Variants: []ProductVariantParam{{SKU: "POLO", Barcode: "4006381333931"}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "barcode 4006381333931 already exists", err.Error())
+
+		param := shirtParam()
+		param.ShopID = 2
+		_, err = usecase.Execute(ctx, param)
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error for a category of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		param := shirtParam()
+		param.ShopID = 2
+		param.CategoryID = &fixture.drinks.ID
+		_, err := usecase.Execute(ctx, param)
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: category not found", err.Error())
+	})
+
+	t.Run("validates variants are required", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, CreateProductParam{ShopID: 1, Name: "Espresso"})
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: Variants is required", err.Error())
+	})
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+)
+
+type DeleteCategoryUsecase struct {
+	db *gorm.DB
+}
+
+func NewDeleteCategoryUsecase(db *gorm.DB) *DeleteCategoryUsecase {
+	return &DeleteCategoryUsecase{
+		db: db,
+	}
+}
+
+// Execute deletes a category of the shop. Its products are kept and left
+// uncategorized; categories with subcategories cannot be deleted until those
+// are moved or deleted.
+func (u *DeleteCategoryUsecase) Execute(ctx context.Context, shopID, categoryID uint64) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCategoryRepo := repositories.NewCategoryRepository(tx)
+		txProductRepo := repositories.NewProductRepository(tx)
+
+		category, err := findShopCategory(ctx, txCategoryRepo, shopID, categoryID)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range txCategoryRepo.FindByShopID(ctx, shopID) {
+			if other.ParentID != nil && *other.ParentID == category.ID {
+				return errors.New("category still has subcategories")
+			}
+		}
+
+		if err := txProductRepo.ClearCategory(ctx, category.ID); err != nil {
+			return fmt.Errorf("failed to uncategorize products: %w", err)
+		}
+		if err := txCategoryRepo.Delete(ctx, category); err != nil {
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
+		return nil
+	})
+}
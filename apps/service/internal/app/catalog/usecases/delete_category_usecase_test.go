@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteCategoryUsecase_Execute(t *testing.T) {
+	t.Run("leaves the products of the category uncategorized", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewDeleteCategoryUsecase(fixture.db)
+
+		product := createTestProduct(t, fixture, &fixture.coffee.ID, "Espresso", "COF-1")
+
+		require.NoError(t, usecase.Execute(ctx, 1, fixture.coffee.ID))
+
+		found, err := fixture.productRepo.FindByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found.CategoryID)
+
+		_, err = fixture.categoryRepo.FindByID(ctx, fixture.coffee.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("refuses to delete a category with subcategories", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewDeleteCategoryUsecase(fixture.db)
+
+		err := usecase.Execute(ctx, 1, fixture.drinks.ID)
+		assert.Error(t, err)
+		assert.Equal(t, "category still has subcategories", err.Error())
+	})
+}
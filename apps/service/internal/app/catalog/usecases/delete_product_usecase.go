@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+)
+
+type DeleteProductUsecase struct {
+	productRepository repositories.ProductRepository
+}
+
+func NewDeleteProductUsecase(productRepository repositories.ProductRepository) *DeleteProductUsecase {
+	return &DeleteProductUsecase{
+		productRepository: productRepository,
+	}
+}
+
+// Execute deletes a product of the shop with its variants. Their SKUs and
+// barcodes become available to other products.
+func (u *DeleteProductUsecase) Execute(ctx context.Context, shopID, productID uint64) error {
+	product, err := findShopProduct(ctx, u.productRepository, shopID, productID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.productRepository.Delete(ctx, product); err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+	return nil
+}
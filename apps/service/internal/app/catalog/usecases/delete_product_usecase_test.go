@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteProductUsecase_Execute(t *testing.T) {
+	t.Run("deletes a product of the shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		espresso := createTestProduct(t, fixture, nil, "Espresso", "COF-1")
+		usecase := NewDeleteProductUsecase(fixture.productRepo)
+
+		err := usecase.Execute(ctx, 2, espresso.ID)
+		assert.Error(t, err)
+		assert.Equal(t, "product not found", err.Error())
+
+		require.NoError(t, usecase.Execute(ctx, 1, espresso.ID))
+
+		_, err = NewGetProductUsecase(fixture.productRepo).Execute(ctx, 1, espresso.ID)
+		assert.Error(t, err)
+		assert.Equal(t, "product not found", err.Error())
+	})
+}
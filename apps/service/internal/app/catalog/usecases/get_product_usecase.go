@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+)
+
+type GetProductUsecase struct {
+	productRepository repositories.ProductRepository
+}
+
+func NewGetProductUsecase(productRepository repositories.ProductRepository) *GetProductUsecase {
+	return &GetProductUsecase{
+		productRepository: productRepository,
+	}
+}
+
+type GetProductResult struct {
+	Product *entities.Product
+}
+
+func (u *GetProductUsecase) Execute(ctx context.Context, shopID, productID uint64) (*GetProductResult, error) {
+	product, err := findShopProduct(ctx, u.productRepository, shopID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetProductResult{
+		Product: &product,
+	}, nil
+}
+
+// findShopProduct loads a product with its variants, treating products of
+// other shops as missing.
+func findShopProduct(ctx context.Context, productRepository repositories.ProductRepository, shopID, productID uint64) (entities.Product, error) {
+	product, err := productRepository.FindByID(ctx, productID)
+	if err != nil {
+		return product, err
+	}
+	if product.ShopID != shopID {
+		return entities.Product{}, errors.New("product not found")
+	}
+	return product, nil
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+)
+
+type ListCategoriesUsecase struct {
+	categoryRepository repositories.CategoryRepository
+}
+
+func NewListCategoriesUsecase(categoryRepository repositories.CategoryRepository) *ListCategoriesUsecase {
+	return &ListCategoriesUsecase{
+		categoryRepository: categoryRepository,
+	}
+}
+
+type ListCategoriesResult struct {
+	// Categories holds every category of the shop in a flat list; nested
+	// ones point to theirs through ParentID.
+	Categories []entities.Category
+}
+
+func (u *ListCategoriesUsecase) Execute(ctx context.Context, shopID uint64) ListCategoriesResult {
+	return ListCategoriesResult{
+		Categories: u.categoryRepository.FindByShopID(ctx, shopID),
+	}
+}
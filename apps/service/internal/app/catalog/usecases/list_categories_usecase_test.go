@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+)
+
+func TestListCategoriesUsecase_Execute(t *testing.T) {
+	t.Run("lists the categories of the shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		_, err := fixture.categoryRepo.Create(ctx, entities.Category{ShopID: 2, Name: "Bakery"})
+		require.NoError(t, err)
+
+		result := NewListCategoriesUsecase(fixture.categoryRepo).Execute(ctx, 1)
+		require.Len(t, result.Categories, 2)
+		assert.Equal(t, "Coffee", result.Categories[0].Name)
+		assert.Equal(t, "Drinks", result.Categories[1].Name)
+	})
+}
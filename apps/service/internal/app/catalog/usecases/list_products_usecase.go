@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListProductsUsecase struct {
+	productRepository repositories.ProductRepository
+	validator         *validator.Validate
+}
+
+func NewListProductsUsecase(productRepository repositories.ProductRepository) *ListProductsUsecase {
+	return &ListProductsUsecase{
+		productRepository: productRepository,
+		validator:         validator.New(),
+	}
+}
+
+type ListProductsParam struct {
+	ShopID     uint64 `validate:"required"`
+	CategoryID *uint64
+	Search     string `validate:"omitempty,max=100"`
+	Cursor     string
+	Limit      int
+}
+
+type ListProductsResult struct {
+	Products   []entities.Product
+	NextCursor string
+}
+
+// Execute lists the products of the shop by name, one page at a time.
+// NextCursor is empty on the last page.
+func (u *ListProductsUsecase) Execute(ctx context.Context, param ListProductsParam) (*ListProductsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	// The limit is one past the page size to tell whether another page
+	// follows.
+	query := repositories.ProductQuery{
+		ShopID:     param.ShopID,
+		CategoryID: param.CategoryID,
+		Search:     strings.TrimSpace(param.Search),
+		Limit:      pagination.Limit(param.Limit) + 1,
+	}
+	if param.Cursor != "" {
+		after, err := pagination.Decode(param.Cursor)
+		if err != nil || after.Sort != repositories.ProductSortName {
+			return nil, errors.New("validation failed: invalid cursor")
+		}
+		query.After = &after
+	}
+
+	products := u.productRepository.Search(ctx, query)
+
+	result := &ListProductsResult{Products: products}
+	if len(products) == query.Limit {
+		result.Products = products[:query.Limit-1]
+		result.NextCursor = repositories.ProductCursor(result.Products[len(result.Products)-1]).Encode()
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProductsUsecase_Execute(t *testing.T) {
+	t.Run("pages through the products of the shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		createTestProduct(t, fixture, nil, "Latte", "COF-2")
+		createTestProduct(t, fixture, &fixture.coffee.ID, "Espresso", "COF-1")
+		createTestProduct(t, fixture, nil, "Croissant", "BAK-1")
+		usecase := NewListProductsUsecase(fixture.productRepo)
+
+		first, err := usecase.Execute(ctx, ListProductsParam{ShopID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, first.Products, 2)
+		assert.Equal(t, "Croissant", first.Products[0].Name)
+		assert.Equal(t, "Espresso", first.Products[1].Name)
+		assert.NotEmpty(t, first.NextCursor)
+
+		second, err := usecase.Execute(ctx, ListProductsParam{ShopID: 1, Limit: 2, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, second.Products, 1)
+		assert.Equal(t, "Latte", second.Products[0].Name)
+		assert.Empty(t, second.NextCursor)
+
+		filtered, err := usecase.Execute(ctx, ListProductsParam{ShopID: 1, CategoryID: &fixture.coffee.ID})
+		require.NoError(t, err)
+		require.Len(t, filtered.Products, 1)
+		assert.Equal(t, "Espresso", filtered.Products[0].Name)
+	})
+
+	t.Run("returns error for an invalid cursor", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+
+		_, err := NewListProductsUsecase(fixture.productRepo).Execute(ctx, ListProductsParam{ShopID: 1, Cursor: "bogus"})
+		assert.Error(t, err)
+		assert.Equal(t, "validation failed: invalid cursor", err.Error())
+	})
+}
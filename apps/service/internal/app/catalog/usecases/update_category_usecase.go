@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateCategoryUsecase struct {
+	categoryRepository repositories.CategoryRepository
+	validator          *validator.Validate
+}
+
+func NewUpdateCategoryUsecase(categoryRepository repositories.CategoryRepository) *UpdateCategoryUsecase {
+	return &UpdateCategoryUsecase{
+		categoryRepository: categoryRepository,
+		validator:          validator.New(),
+	}
+}
+
+// UpdateCategoryParam replaces the name, description and parent of a
+// category. A nil ParentID moves it to the top level.
+type UpdateCategoryParam struct {
+	ShopID      uint64 `validate:"required"`
+	CategoryID  uint64 `validate:"required"`
+	ParentID    *uint64
+	Name        string `validate:"required,min=1,max=100"`
+	Description string `validate:"omitempty,max=1000"`
+}
+
+type UpdateCategoryResult struct {
+	Category *entities.Category
+}
+
+// Execute updates a category of the shop. It cannot be moved under itself or
+// any of its subcategories.
+func (u *UpdateCategoryUsecase) Execute(ctx context.Context, param UpdateCategoryParam) (*UpdateCategoryResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	category, err := findShopCategory(ctx, u.categoryRepository, param.ShopID, param.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := u.categoryRepository.FindByShopID(ctx, param.ShopID)
+	if param.ParentID != nil {
+		if !containsCategory(categories, *param.ParentID) {
+			return nil, errors.New("validation failed: parent category not found")
+		}
+		if isWithin(categories, *param.ParentID, category.ID) {
+			return nil, errors.New("validation failed: a category cannot be nested under itself or its subcategories")
+		}
+	}
+
+	name := strings.TrimSpace(param.Name)
+	if err := ensureCategoryNameAvailable(categories, param.ParentID, name, category.ID); err != nil {
+		return nil, err
+	}
+
+	category.ParentID = param.ParentID
+	category.Name = name
+	category.Description = strings.TrimSpace(param.Description)
+
+	updated, err := u.categoryRepository.Update(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return &UpdateCategoryResult{
+		Category: &updated,
+	}, nil
+}
+
+// isWithin reports whether the category with id is ancestorID or nested
+// somewhere below it.
+func isWithin(categories []entities.Category, id, ancestorID uint64) bool {
+	parents := make(map[uint64]*uint64, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+
+	// The walk is bounded by the number of categories in case the stored
+	// tree already holds a cycle.
+	current := &id
+	for range len(categories) + 1 {
+		if current == nil {
+			return false
+		}
+		if *current == ancestorID {
+			return true
+		}
+		current = parents[*current]
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCategoryUsecase_Execute(t *testing.T) {
+	t.Run("moves a category to the top level", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewUpdateCategoryUsecase(fixture.categoryRepo)
+
+		result, err := usecase.Execute(ctx, UpdateCategoryParam{
+			ShopID:     1,
+			CategoryID: fixture.coffee.ID,
+			Name:       "Coffee",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, result.Category.ParentID)
+	})
+
+	t.Run("refuses to nest a category under its subcategories", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewUpdateCategoryUsecase(fixture.categoryRepo)
+
+		for _, parentID := range []uint64{fixture.drinks.ID, fixture.coffee.ID} {
+			_, err := usecase.Execute(ctx, UpdateCategoryParam{
+				ShopID:     1,
+				CategoryID: fixture.drinks.ID,
+				ParentID:   &parentID,
+				Name:       "Drinks",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "validation failed: a category cannot be nested under itself or its subcategories", err.Error())
+		}
+	})
+
+	t.Run("returns error for categories of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		usecase := NewUpdateCategoryUsecase(fixture.categoryRepo)
+
+		_, err := usecase.Execute(ctx, UpdateCategoryParam{
+			ShopID:     2,
+			CategoryID: fixture.drinks.ID,
+			Name:       "Drinks",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "category not found", err.Error())
+	})
+}
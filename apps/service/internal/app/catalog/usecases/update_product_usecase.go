@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateProductUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewUpdateProductUsecase(db *gorm.DB) *UpdateProductUsecase {
+	return &UpdateProductUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+// UpdateProductParam replaces a product with its options and variants.
+// Variants with an ID update that variant of the product, variants without
+// one are added, and variants of the product that are left out are deleted.
+type UpdateProductParam struct {
+	ShopID      uint64 `validate:"required"`
+	ProductID   uint64 `validate:"required"`
+	CategoryID  *uint64
+	Name        string                `validate:"required,min=1,max=255"`
+	Description string                `validate:"omitempty,max=2000"`
+	Options     []ProductOptionParam  `validate:"dive"`
+	Variants    []ProductVariantParam `validate:"required,min=1,dive"`
+}
+
+type UpdateProductResult struct {
+	Product *entities.Product
+}
+
+// Execute updates a product of the shop under the same rules as creating
+// one.
+func (u *UpdateProductUsecase) Execute(ctx context.Context, param UpdateProductParam) (*UpdateProductResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	options, err := normalizeOptions(param.Options)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := buildVariants(param.ShopID, options, param.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *UpdateProductResult
+	err = u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txProductRepo := repositories.NewProductRepository(tx)
+
+		product, err := findShopProduct(ctx, txProductRepo, param.ShopID, param.ProductID)
+		if err != nil {
+			return err
+		}
+		if param.CategoryID != nil {
+			if _, err := findShopCategory(ctx, repositories.NewCategoryRepository(tx), param.ShopID, *param.CategoryID); err != nil {
+				return errors.New("validation failed: category not found")
+			}
+		}
+
+		current := make(map[uint64]entities.ProductVariant, len(product.Variants))
+		for _, variant := range product.Variants {
+			current[variant.ID] = variant
+		}
+		removed := maps.Clone(current)
+		for _, variant := range variants {
+			if variant.ID == 0 {
+				continue
+			}
+			if _, ok := current[variant.ID]; !ok {
+				return fmt.Errorf("validation failed: variant %d is not a variant of the product", variant.ID)
+			}
+			delete(removed, variant.ID)
+		}
+		if err := ensureCodesAvailable(ctx, txProductRepo, param.ShopID, product.ID, variants); err != nil {
+			return err
+		}
+
+		product.CategoryID = param.CategoryID
+		product.Name = strings.TrimSpace(param.Name)
+		product.Description = strings.TrimSpace(param.Description)
+		product.Options = options
+		product.Variants = nil
+		if _, err := txProductRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+
+		// Variants are deleted first so the SKUs they free can be taken by
+		// the others.
+		for _, variant := range removed {
+			if err := txProductRepo.DeleteVariant(ctx, variant); err != nil {
+				return fmt.Errorf("failed to delete variant: %w", err)
+			}
+		}
+		for _, variant := range variants {
+			if variant.ID == 0 {
+				variant.ProductID = product.ID
+				_, err = txProductRepo.CreateVariant(ctx, variant)
+			} else {
+				saved := current[variant.ID]
+				saved.SKU = variant.SKU
+				saved.Barcode = variant.Barcode
+				saved.Price = variant.Price
+				saved.Options = variant.Options
+				_, err = txProductRepo.UpdateVariant(ctx, saved)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to save variant %s: %w", variant.SKU, err)
+			}
+		}
+
+		updated, err := txProductRepo.FindByID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		result = &UpdateProductResult{
+			Product: &updated,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateProductUsecase_Execute(t *testing.T) {
+	t.Run("updates, adds and removes variants", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		created, err := NewCreateProductUsecase(fixture.productRepo, fixture.categoryRepo).Execute(ctx, shirtParam())
+		require.NoError(t, err)
+		small, medium := created.Product.Variants[0], created.Product.Variants[1]
+
+		result, err := NewUpdateProductUsecase(fixture.db).Execute(ctx, UpdateProductParam{
+			ShopID:    1,
+			ProductID: created.Product.ID,
+			Name:      "Tee",
+			Options: []ProductOptionParam{
+				{Name: "Size", Values: []string{"S", "L"}},
+			},
+			Variants: []ProductVariantParam{
+				{ID: small.ID, SKU: "TEE-S", Price: 1400, Options: map[string]string{"Size": "S"}},
+				{SKU: "TS-M-RED", Price: 1900, Options: map[string]string{"Size": "L"}},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Tee", result.Product.Name)
+		require.Len(t, result.Product.Variants, 2)
+		assert.Equal(t, small.ID, result.Product.Variants[0].ID)
+		assert.Equal(t, "TEE-S", result.Product.Variants[0].SKU)
+		assert.Equal(t, map[string]string{"Size": "S"}, result.Product.Variants[0].Options)
+		assert.Equal(t, small.CreatedAt.Unix(), result.Product.Variants[0].CreatedAt.Unix())
+		assert.NotEqual(t, medium.ID, result.Product.Variants[1].ID)
+		assert.Equal(t, "TS-M-RED", result.Product.Variants[1].SKU)
+
+		_, err = fixture.productRepo.FindVariantBySKU(ctx, 1, "TS-S-RED")
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error for a variant of another product", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		espresso := createTestProduct(t, fixture, nil, "Espresso", "COF-1")
+		latte := createTestProduct(t, fixture, nil, "Latte", "COF-2")
+
+		_, err := NewUpdateProductUsecase(fixture.db).Execute(ctx, UpdateProductParam{
+			ShopID:    1,
+			ProductID: espresso.ID,
+			Name:      "Espresso",
+			Variants:  []ProductVariantParam{{ID: latte.Variants[0].ID, SKU: "COF-2"}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, fmt.Sprintf("validation failed: variant %d is not a variant of the product", latte.Variants[0].ID), err.Error())
+	})
+
+	t.Run("returns error when a SKU is taken by another product", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		espresso := createTestProduct(t, fixture, nil, "Espresso", "COF-1")
+		createTestProduct(t, fixture, nil, "Latte", "COF-2")
+
+		_, err := NewUpdateProductUsecase(fixture.db).Execute(ctx, UpdateProductParam{
+			ShopID:    1,
+			ProductID: espresso.ID,
+			Name:      "Espresso",
+			Variants:  []ProductVariantParam{{ID: espresso.Variants[0].ID, SKU: "COF-2"}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "sku COF-2 already exists", err.Error())
+	})
+
+	t.Run("returns error for products of another shop", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupCatalogTest(t)
+		espresso := createTestProduct(t, fixture, nil, "Espresso", "COF-1")
+
+		_, err := NewUpdateProductUsecase(fixture.db).Execute(ctx, UpdateProductParam{
+			ShopID:    2,
+			ProductID: espresso.ID,
+			Name:      "Espresso",
+			Variants:  []ProductVariantParam{{SKU: "COF-1"}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "product not found", err.Error())
+	})
+}
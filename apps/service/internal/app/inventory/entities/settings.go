@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+)
+
+// Settings holds the inventory policy of a shop. Shops that never changed
+// it have the zero value: stock cannot go negative.
+type Settings struct {
+	ShopID             uint64    `gorm:"primaryKey;column:shop_id;autoIncrement:false" json:"shop_id"`
+	AllowNegativeStock bool      `gorm:"column:allow_negative_stock;not null" json:"allow_negative_stock"`
+	UpdatedAt          time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (Settings) TableName() string {
+	return "inventory_settings"
+}
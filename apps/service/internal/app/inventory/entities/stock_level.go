@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+)
+
+// StockLevel is the quantity of a variant on hand at a location: the sum of
+// its movements there, kept up to date as they are posted. It only goes below
+// zero in shops that allow negative stock.
+type StockLevel struct {
+	LocationID uint64    `gorm:"primaryKey;column:location_id;autoIncrement:false" json:"location_id"`
+	VariantID  uint64    `gorm:"primaryKey;column:variant_id;autoIncrement:false" json:"variant_id"`
+	ShopID     uint64    `gorm:"column:shop_id;not null;index:idx_stock_levels_shop_id" json:"shop_id"`
+	OnHand     int64     `gorm:"column:on_hand;not null" json:"on_hand"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (StockLevel) TableName() string {
+	return "stock_levels"
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+)
+
+// MovementType tells why the stock of a variant changed.
+type MovementType string
+
+const (
+	MovementReceipt    MovementType = "receipt"
+	MovementSale       MovementType = "sale"
+	MovementAdjustment MovementType = "adjustment"
+	MovementTransfer   MovementType = "transfer"
+	MovementReturn     MovementType = "return"
+)
+
+// StockMovement is an entry in the stock ledger: a change in the quantity of
+// a variant at a location of a shop. Quantity is positive for stock coming in
+// and negative for stock going out, and BalanceAfter is the quantity on hand
+// right after the movement. Movements are never changed or removed; mistakes
+// are corrected with further movements.
+type StockMovement struct {
+	ID           uint64       `gorm:"primaryKey;column:id" json:"id"`
+	ShopID       uint64       `gorm:"column:shop_id;not null;index:idx_stock_movements_shop_id" json:"shop_id"`
+	LocationID   uint64       `gorm:"column:location_id;not null;index:idx_stock_movements_location_id_variant_id" json:"location_id"`
+	VariantID    uint64       `gorm:"column:variant_id;not null;index:idx_stock_movements_location_id_variant_id" json:"variant_id"`
+	Type         MovementType `gorm:"column:type;type:varchar(20);not null" json:"type"`
+	Quantity     int64        `gorm:"column:quantity;not null" json:"quantity"`
+	BalanceAfter int64        `gorm:"column:balance_after;not null" json:"balance_after"`
+	// TransferLocationID is the other side of a transfer: where the stock
+	// went for the sending location, and where it came from for the
+	// receiving one.
+	TransferLocationID *uint64   `gorm:"column:transfer_location_id" json:"transfer_location_id"`
+	Reference          string    `gorm:"column:reference;not null" json:"reference"`
+	Note               string    `gorm:"column:note;not null" json:"note"`
+	UserID             uint64    `gorm:"column:user_id;not null" json:"user_id"`
+	CreatedAt          time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+type SettingsRepository interface {
+	// Find returns the default settings for shops that never saved any.
+	Find(ctx context.Context, shopID uint64) (entities.Settings, error)
+	Save(ctx context.Context, settings entities.Settings) (entities.Settings, error)
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+type settingsRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingsRepository(db *gorm.DB) SettingsRepository {
+	return &settingsRepository{
+		db: db,
+	}
+}
+
+func (r *settingsRepository) Find(ctx context.Context, shopID uint64) (entities.Settings, error) {
+	var settings entities.Settings
+	err := r.db.WithContext(ctx).Where("shop_id = ?", shopID).First(&settings).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Settings{ShopID: shopID}, nil
+		}
+		return settings, err
+	}
+	return settings, nil
+}
+
+func (r *settingsRepository) Save(ctx context.Context, settings entities.Settings) (entities.Settings, error) {
+	err := r.db.WithContext(ctx).Save(&settings).Error
+	if err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestSettingsRepository(t *testing.T) {
+	t.Run("finds the defaults until settings are saved", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Settings{})
+		repo := NewSettingsRepository(db)
+
+		settings, err := repo.Find(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), settings.ShopID)
+		assert.False(t, settings.AllowNegativeStock)
+
+		settings.AllowNegativeStock = true
+		_, err = repo.Save(ctx, settings)
+		require.NoError(t, err)
+
+		settings, err = repo.Find(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, settings.AllowNegativeStock)
+
+		settings.AllowNegativeStock = false
+		_, err = repo.Save(ctx, settings)
+		require.NoError(t, err)
+
+		settings, err = repo.Find(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, settings.AllowNegativeStock)
+	})
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// StockLevelSortLocation is the order stock level lists come in: by
+// location, then by variant.
+const StockLevelSortLocation = "location"
+
+// StockLevelQuery filters and pages the stock levels of a shop. After, when
+// set, resumes the list past the level it points to.
+// LocationIDs, when not empty, limits the list to those locations.
+type StockLevelQuery struct {
+	ShopID      uint64
+	LocationID  *uint64
+	LocationIDs []uint64
+	VariantID   *uint64
+	After       *pagination.Cursor
+	Limit       int
+}
+
+// StockLevelQueryScope applies the query to a statement selecting from the
+// stock_levels table.
+func StockLevelQueryScope(query StockLevelQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("shop_id = ?", query.ShopID)
+		if query.LocationID != nil {
+			db = db.Where("location_id = ?", *query.LocationID)
+		}
+		if len(query.LocationIDs) > 0 {
+			db = db.Where("location_id IN ?", query.LocationIDs)
+		}
+		if query.VariantID != nil {
+			db = db.Where("variant_id = ?", *query.VariantID)
+		}
+		if query.After != nil {
+			locationID, _ := strconv.ParseUint(query.After.Value, 10, 64)
+			db = db.Where(
+				"(location_id > ? OR (location_id = ? AND variant_id > ?))",
+				locationID, locationID, query.After.ID,
+			)
+		}
+
+		db = db.Order("location_id ASC").Order("variant_id ASC")
+		if query.Limit > 0 {
+			db = db.Limit(query.Limit)
+		}
+		return db
+	}
+}
+
+// StockLevelCursor returns the cursor resuming a stock level list after
+// level.
+func StockLevelCursor(level entities.StockLevel) pagination.Cursor {
+	return pagination.Cursor{
+		Sort:  StockLevelSortLocation,
+		Value: strconv.FormatUint(level.LocationID, 10),
+		ID:    level.VariantID,
+	}
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+// StockLevelRepository keeps the quantity on hand of every variant at every
+// location. Levels are only changed through Add, as movements are posted.
+type StockLevelRepository interface {
+	// Find returns a zero level for variants that never moved at the
+	// location.
+	Find(ctx context.Context, locationID uint64, variantID uint64) (entities.StockLevel, error)
+	Search(ctx context.Context, query StockLevelQuery) []entities.StockLevel
+	// Add changes the quantity on hand by quantity, creating the level when
+	// needed, and returns the level after the change. The row stays locked
+	// until the surrounding transaction ends.
+	Add(ctx context.Context, shopID uint64, locationID uint64, variantID uint64, quantity int64) (entities.StockLevel, error)
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+type stockLevelRepository struct {
+	db *gorm.DB
+}
+
+func NewStockLevelRepository(db *gorm.DB) StockLevelRepository {
+	return &stockLevelRepository{
+		db: db,
+	}
+}
+
+func (r *stockLevelRepository) Find(ctx context.Context, locationID uint64, variantID uint64) (entities.StockLevel, error) {
+	var level entities.StockLevel
+	err := r.db.WithContext(ctx).Where("location_id = ? AND variant_id = ?", locationID, variantID).First(&level).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.StockLevel{LocationID: locationID, VariantID: variantID}, nil
+		}
+		return level, err
+	}
+	return level, nil
+}
+
+func (r *stockLevelRepository) Search(ctx context.Context, query StockLevelQuery) []entities.StockLevel {
+	var levels []entities.StockLevel
+	r.db.WithContext(ctx).Scopes(StockLevelQueryScope(query)).Find(&levels)
+	return levels
+}
+
+func (r *stockLevelRepository) Add(ctx context.Context, shopID uint64, locationID uint64, variantID uint64, quantity int64) (entities.StockLevel, error) {
+	level := entities.StockLevel{
+		ShopID:     shopID,
+		LocationID: locationID,
+		VariantID:  variantID,
+		OnHand:     quantity,
+		UpdatedAt:  time.Now(),
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "location_id"}, {Name: "variant_id"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"on_hand":    gorm.Expr("stock_levels.on_hand + excluded.on_hand"),
+					"updated_at": gorm.Expr("excluded.updated_at"),
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(&level).Error
+	if err != nil {
+		return level, err
+	}
+	return level, nil
+}
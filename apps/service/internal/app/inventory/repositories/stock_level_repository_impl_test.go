@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestStockLevelRepository(t *testing.T) {
+	t.Run("adds to the quantity on hand", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StockLevel{})
+		repo := NewStockLevelRepository(db)
+
+		level, err := repo.Add(ctx, 1, 10, 100, 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), level.OnHand)
+
+		level, err = repo.Add(ctx, 1, 10, 100, -7)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-2), level.OnHand)
+
+		found, err := repo.Find(ctx, 10, 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-2), found.OnHand)
+		assert.Equal(t, uint64(1), found.ShopID)
+	})
+
+	t.Run("finds a zero level for variants that never moved", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StockLevel{})
+		repo := NewStockLevelRepository(db)
+
+		level, err := repo.Find(ctx, 10, 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), level.OnHand)
+	})
+
+	t.Run("searches the levels of a shop by location and variant", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StockLevel{})
+		repo := NewStockLevelRepository(db)
+
+		for _, level := range []entities.StockLevel{
+			{ShopID: 1, LocationID: 11, VariantID: 100, OnHand: 1},
+			{ShopID: 1, LocationID: 10, VariantID: 101, OnHand: 2},
+			{ShopID: 1, LocationID: 10, VariantID: 100, OnHand: 3},
+			{ShopID: 2, LocationID: 20, VariantID: 200, OnHand: 4},
+		} {
+			_, err := repo.Add(ctx, level.ShopID, level.LocationID, level.VariantID, level.OnHand)
+			require.NoError(t, err)
+		}
+
+		levels := repo.Search(ctx, StockLevelQuery{ShopID: 1, Limit: 2})
+		require.Len(t, levels, 2)
+		assert.Equal(t, int64(3), levels[0].OnHand)
+		assert.Equal(t, int64(2), levels[1].OnHand)
+
+		after := StockLevelCursor(levels[1])
+		levels = repo.Search(ctx, StockLevelQuery{ShopID: 1, After: &after})
+		require.Len(t, levels, 1)
+		assert.Equal(t, int64(1), levels[0].OnHand)
+
+		variantID := uint64(100)
+		levels = repo.Search(ctx, StockLevelQuery{ShopID: 1, VariantID: &variantID})
+		assert.Len(t, levels, 2)
+
+		levels = repo.Search(ctx, StockLevelQuery{ShopID: 1, LocationIDs: []uint64{11, 20}})
+		require.Len(t, levels, 1)
+		assert.Equal(t, uint64(11), levels[0].LocationID)
+	})
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// StockMovementSortRecent is the order movement lists come in: newest first.
+const StockMovementSortRecent = "recent"
+
+// StockMovementQuery filters and pages the stock movements of a shop. After,
+// when set, resumes the list past the movement it points to.
+// LocationIDs, when not empty, limits the list to those locations.
+type StockMovementQuery struct {
+	ShopID      uint64
+	LocationID  *uint64
+	LocationIDs []uint64
+	VariantID   *uint64
+	Type        entities.MovementType
+	After       *pagination.Cursor
+	Limit       int
+}
+
+// StockMovementQueryScope applies the query to a statement selecting from
+// the stock_movements table.
+func StockMovementQueryScope(query StockMovementQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("shop_id = ?", query.ShopID)
+		if query.LocationID != nil {
+			db = db.Where("location_id = ?", *query.LocationID)
+		}
+		if len(query.LocationIDs) > 0 {
+			db = db.Where("location_id IN ?", query.LocationIDs)
+		}
+		if query.VariantID != nil {
+			db = db.Where("variant_id = ?", *query.VariantID)
+		}
+		if query.Type != "" {
+			db = db.Where("type = ?", query.Type)
+		}
+		if query.After != nil {
+			db = db.Where("id < ?", query.After.ID)
+		}
+
+		db = db.Order("id DESC")
+		if query.Limit > 0 {
+			db = db.Limit(query.Limit)
+		}
+		return db
+	}
+}
+
+// StockMovementCursor returns the cursor resuming a movement list after
+// movement.
+func StockMovementCursor(movement entities.StockMovement) pagination.Cursor {
+	return pagination.Cursor{Sort: StockMovementSortRecent, ID: movement.ID}
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+// StockMovementRepository stores the stock ledger. It has no way to change
+// or remove a movement once created.
+type StockMovementRepository interface {
+	Create(ctx context.Context, movement entities.StockMovement) (entities.StockMovement, error)
+	Search(ctx context.Context, query StockMovementQuery) []entities.StockMovement
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{
+		db: db,
+	}
+}
+
+func (r *stockMovementRepository) Create(ctx context.Context, movement entities.StockMovement) (entities.StockMovement, error) {
+	err := r.db.WithContext(ctx).Create(&movement).Error
+	if err != nil {
+		return movement, err
+	}
+	return movement, nil
+}
+
+func (r *stockMovementRepository) Search(ctx context.Context, query StockMovementQuery) []entities.StockMovement {
+	var movements []entities.StockMovement
+	r.db.WithContext(ctx).Scopes(StockMovementQueryScope(query)).Find(&movements)
+	return movements
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestStockMovementRepository(t *testing.T) {
+	t.Run("searches the movements of a shop newest first", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.StockMovement{})
+		repo := NewStockMovementRepository(db)
+
+		for _, movement := range []entities.StockMovement{
+			{ShopID: 1, LocationID: 10, VariantID: 100, Type: entities.MovementReceipt, Quantity: 5, BalanceAfter: 5},
+			{ShopID: 1, LocationID: 10, VariantID: 100, Type: entities.MovementSale, Quantity: -1, BalanceAfter: 4},
+			{ShopID: 1, LocationID: 10, VariantID: 101, Type: entities.MovementReceipt, Quantity: 2, BalanceAfter: 2},
+			{ShopID: 2, LocationID: 20, VariantID: 200, Type: entities.MovementReceipt, Quantity: 1, BalanceAfter: 1},
+		} {
+			_, err := repo.Create(ctx, movement)
+			require.NoError(t, err)
+		}
+
+		movements := repo.Search(ctx, StockMovementQuery{ShopID: 1, Limit: 2})
+		require.Len(t, movements, 2)
+		assert.Equal(t, uint64(101), movements[0].VariantID)
+		assert.Equal(t, entities.MovementSale, movements[1].Type)
+
+		after := StockMovementCursor(movements[1])
+		movements = repo.Search(ctx, StockMovementQuery{ShopID: 1, After: &after})
+		require.Len(t, movements, 1)
+		assert.Equal(t, int64(5), movements[0].Quantity)
+
+		variantID := uint64(100)
+		movements = repo.Search(ctx, StockMovementQuery{ShopID: 1, VariantID: &variantID, Type: entities.MovementReceipt})
+		require.Len(t, movements, 1)
+		assert.Equal(t, int64(5), movements[0].BalanceAfter)
+	})
+}
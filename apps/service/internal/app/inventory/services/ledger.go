@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+)
+
+// Ledger posts stock movements: it records them and moves the stock levels
+// they touch, enforcing the shop's negative stock policy. Build it from
+// repositories bound to a transaction, so movements and levels are saved
+// together or not at all.
+type Ledger struct {
+	stockLevelRepository    repositories.StockLevelRepository
+	stockMovementRepository repositories.StockMovementRepository
+	settingsRepository      repositories.SettingsRepository
+}
+
+func NewLedger(stockLevelRepository repositories.StockLevelRepository, stockMovementRepository repositories.StockMovementRepository, settingsRepository repositories.SettingsRepository) *Ledger {
+	return &Ledger{
+		stockLevelRepository:    stockLevelRepository,
+		stockMovementRepository: stockMovementRepository,
+		settingsRepository:      settingsRepository,
+	}
+}
+
+// Post records the movements of shopID and returns them with their IDs and
+// balances. Levels are changed in location and variant order, whatever the
+// order of movements, so concurrent posts lock them in the same order. It
+// fails with an "insufficient stock: ..." error when a movement takes a level
+// below zero in a shop that does not allow negative stock.
+func (l *Ledger) Post(ctx context.Context, shopID uint64, movements []entities.StockMovement) ([]entities.StockMovement, error) {
+	settings, err := l.settingsRepository.Find(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory settings: %w", err)
+	}
+
+	order := make([]int, len(movements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ma, mb := movements[order[a]], movements[order[b]]
+		if ma.LocationID != mb.LocationID {
+			return ma.LocationID < mb.LocationID
+		}
+		return ma.VariantID < mb.VariantID
+	})
+
+	posted := make([]entities.StockMovement, len(movements))
+	for _, i := range order {
+		movement := movements[i]
+		movement.ShopID = shopID
+
+		level, err := l.stockLevelRepository.Add(ctx, shopID, movement.LocationID, movement.VariantID, movement.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update stock level: %w", err)
+		}
+		if movement.Quantity < 0 && level.OnHand < 0 && !settings.AllowNegativeStock {
+			return nil, fmt.Errorf("insufficient stock: variant %d has %d on hand at location %d", movement.VariantID, level.OnHand-movement.Quantity, movement.LocationID)
+		}
+
+		movement.BalanceAfter = level.OnHand
+		movement, err = l.stockMovementRepository.Create(ctx, movement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stock movement: %w", err)
+		}
+		posted[i] = movement
+	}
+	return posted, nil
+}
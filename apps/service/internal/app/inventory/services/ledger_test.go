@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupLedger(t *testing.T) (*Ledger, *gorm.DB) {
+	db := testutil.SetupTestDB(t, &entities.StockLevel{}, &entities.StockMovement{}, &entities.Settings{})
+	ledger := NewLedger(
+		repositories.NewStockLevelRepository(db),
+		repositories.NewStockMovementRepository(db),
+		repositories.NewSettingsRepository(db),
+	)
+	return ledger, db
+}
+
+func TestLedger_Post(t *testing.T) {
+	t.Run("records movements with running balances", func(t *testing.T) {
+		ctx := context.Background()
+		ledger, db := setupLedger(t)
+
+		posted, err := ledger.Post(ctx, 1, []entities.StockMovement{
+			{LocationID: 20, VariantID: 100, Type: entities.MovementReceipt, Quantity: 3},
+			{LocationID: 10, VariantID: 100, Type: entities.MovementReceipt, Quantity: 10},
+			{LocationID: 10, VariantID: 100, Type: entities.MovementSale, Quantity: -4},
+		})
+		require.NoError(t, err)
+		require.Len(t, posted, 3)
+		assert.Equal(t, int64(3), posted[0].BalanceAfter)
+		assert.Equal(t, int64(10), posted[1].BalanceAfter)
+		assert.Equal(t, int64(6), posted[2].BalanceAfter)
+		for _, movement := range posted {
+			assert.NotZero(t, movement.ID)
+			assert.Equal(t, uint64(1), movement.ShopID)
+		}
+
+		level, err := repositories.NewStockLevelRepository(db).Find(ctx, 10, 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), level.OnHand)
+	})
+
+	t.Run("refuses to take stock below zero", func(t *testing.T) {
+		ctx := context.Background()
+		ledger, _ := setupLedger(t)
+
+		_, err := ledger.Post(ctx, 1, []entities.StockMovement{
+			{LocationID: 10, VariantID: 100, Type: entities.MovementReceipt, Quantity: 2},
+		})
+		require.NoError(t, err)
+
+		_, err = ledger.Post(ctx, 1, []entities.StockMovement{
+			{LocationID: 10, VariantID: 100, Type: entities.MovementSale, Quantity: -3},
+		})
+		assert.EqualError(t, err, "insufficient stock: variant 100 has 2 on hand at location 10")
+	})
+
+	t.Run("allows negative stock when the shop does", func(t *testing.T) {
+		ctx := context.Background()
+		ledger, db := setupLedger(t)
+
+		_, err := repositories.NewSettingsRepository(db).Save(ctx, entities.Settings{ShopID: 1, AllowNegativeStock: true})
+		require.NoError(t, err)
+
+		posted, err := ledger.Post(ctx, 1, []entities.StockMovement{
+			{LocationID: 10, VariantID: 100, Type: entities.MovementSale, Quantity: -3},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(-3), posted[0].BalanceAfter)
+	})
+}
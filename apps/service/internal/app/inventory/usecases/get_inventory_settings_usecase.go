@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+)
+
+type GetInventorySettingsUsecase struct {
+	settingsRepository repositories.SettingsRepository
+}
+
+func NewGetInventorySettingsUsecase(settingsRepository repositories.SettingsRepository) *GetInventorySettingsUsecase {
+	return &GetInventorySettingsUsecase{
+		settingsRepository: settingsRepository,
+	}
+}
+
+type GetInventorySettingsResult struct {
+	Settings entities.Settings
+}
+
+func (u *GetInventorySettingsUsecase) Execute(ctx context.Context, shopID uint64) (*GetInventorySettingsResult, error) {
+	settings, err := u.settingsRepository.Find(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory settings: %w", err)
+	}
+	return &GetInventorySettingsResult{Settings: settings}, nil
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListStockLevelsUsecase struct {
+	stockLevelRepository repositories.StockLevelRepository
+	validator            *validator.Validate
+}
+
+func NewListStockLevelsUsecase(stockLevelRepository repositories.StockLevelRepository) *ListStockLevelsUsecase {
+	return &ListStockLevelsUsecase{
+		stockLevelRepository: stockLevelRepository,
+		validator:            validator.New(),
+	}
+}
+
+type ListStockLevelsParam struct {
+	ShopID     uint64 `validate:"required"`
+	LocationID *uint64
+	VariantID  *uint64
+	Cursor     string
+	Limit      int
+	// AllowedLocationIDs are the locations the user works at, when limited
+	// to some. The list is kept to those.
+	AllowedLocationIDs []uint64
+}
+
+type ListStockLevelsResult struct {
+	Levels     []entities.StockLevel
+	NextCursor string
+}
+
+// Execute lists the stock on hand in the shop by location and variant, one
+// page at a time. Variants that never moved at a location have no level
+// there. NextCursor is empty on the last page.
+func (u *ListStockLevelsUsecase) Execute(ctx context.Context, param ListStockLevelsParam) (*ListStockLevelsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.LocationID != nil {
+		if err := ensureLocationAllowed(param.AllowedLocationIDs, *param.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The limit is one past the page size to tell whether another page
+	// follows.
+	query := repositories.StockLevelQuery{
+		ShopID:      param.ShopID,
+		LocationID:  param.LocationID,
+		LocationIDs: param.AllowedLocationIDs,
+		VariantID:   param.VariantID,
+		Limit:       pagination.Limit(param.Limit) + 1,
+	}
+	if param.Cursor != "" {
+		after, err := pagination.Decode(param.Cursor)
+		if err != nil || after.Sort != repositories.StockLevelSortLocation {
+			return nil, errors.New("validation failed: invalid cursor")
+		}
+		query.After = &after
+	}
+
+	levels := u.stockLevelRepository.Search(ctx, query)
+
+	result := &ListStockLevelsResult{Levels: levels}
+	if len(levels) == query.Limit {
+		result.Levels = levels[:query.Limit-1]
+		result.NextCursor = repositories.StockLevelCursor(result.Levels[len(result.Levels)-1]).Encode()
+	}
+	return result, nil
+}
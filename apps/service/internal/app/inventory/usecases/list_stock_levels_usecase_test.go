@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+)
+
+func TestListStockLevelsUsecase_Execute(t *testing.T) {
+	t.Run("pages through the levels by location", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 1)
+		receive(t, fixture, fixture.main, fixture.large, 2)
+		receive(t, fixture, fixture.storage, fixture.small, 3)
+		usecase := NewListStockLevelsUsecase(repositories.NewStockLevelRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, ListStockLevelsParam{ShopID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, result.Levels, 2)
+		assert.Equal(t, int64(1), result.Levels[0].OnHand)
+		assert.Equal(t, int64(2), result.Levels[1].OnHand)
+		require.NotEmpty(t, result.NextCursor)
+
+		result, err = usecase.Execute(ctx, ListStockLevelsParam{ShopID: 1, Limit: 2, Cursor: result.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, result.Levels, 1)
+		assert.Equal(t, int64(3), result.Levels[0].OnHand)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("filters by location", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 1)
+		receive(t, fixture, fixture.storage, fixture.small, 3)
+
+		result, err := NewListStockLevelsUsecase(repositories.NewStockLevelRepository(fixture.db)).Execute(ctx, ListStockLevelsParam{
+			ShopID:     1,
+			LocationID: &fixture.storage.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Levels, 1)
+		assert.Equal(t, int64(3), result.Levels[0].OnHand)
+	})
+	t.Run("keeps staff to the locations they work at", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 1)
+		receive(t, fixture, fixture.storage, fixture.small, 3)
+		usecase := NewListStockLevelsUsecase(repositories.NewStockLevelRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, ListStockLevelsParam{
+			ShopID:             1,
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Levels, 1)
+		assert.Equal(t, fixture.main.ID, result.Levels[0].LocationID)
+
+		_, err = usecase.Execute(ctx, ListStockLevelsParam{
+			ShopID:             1,
+			LocationID:         &fixture.storage.ID,
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+	})
+}
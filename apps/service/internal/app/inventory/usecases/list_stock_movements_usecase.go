@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListStockMovementsUsecase struct {
+	stockMovementRepository repositories.StockMovementRepository
+	validator               *validator.Validate
+}
+
+func NewListStockMovementsUsecase(stockMovementRepository repositories.StockMovementRepository) *ListStockMovementsUsecase {
+	return &ListStockMovementsUsecase{
+		stockMovementRepository: stockMovementRepository,
+		validator:               validator.New(),
+	}
+}
+
+type ListStockMovementsParam struct {
+	ShopID     uint64 `validate:"required"`
+	LocationID *uint64
+	VariantID  *uint64
+	Type       entities.MovementType `validate:"omitempty,oneof=receipt sale adjustment transfer return"`
+	Cursor     string
+	Limit      int
+	// AllowedLocationIDs are the locations the user works at, when limited
+	// to some. The list is kept to those.
+	AllowedLocationIDs []uint64
+}
+
+type ListStockMovementsResult struct {
+	Movements  []entities.StockMovement
+	NextCursor string
+}
+
+// Execute lists the stock movements of the shop, newest first, one page at
+// a time. NextCursor is empty on the last page.
+func (u *ListStockMovementsUsecase) Execute(ctx context.Context, param ListStockMovementsParam) (*ListStockMovementsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.LocationID != nil {
+		if err := ensureLocationAllowed(param.AllowedLocationIDs, *param.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The limit is one past the page size to tell whether another page
+	// follows.
+	query := repositories.StockMovementQuery{
+		ShopID:      param.ShopID,
+		LocationID:  param.LocationID,
+		LocationIDs: param.AllowedLocationIDs,
+		VariantID:   param.VariantID,
+		Type:        param.Type,
+		Limit:       pagination.Limit(param.Limit) + 1,
+	}
+	if param.Cursor != "" {
+		after, err := pagination.Decode(param.Cursor)
+		if err != nil || after.Sort != repositories.StockMovementSortRecent {
+			return nil, errors.New("validation failed: invalid cursor")
+		}
+		query.After = &after
+	}
+
+	movements := u.stockMovementRepository.Search(ctx, query)
+
+	result := &ListStockMovementsResult{Movements: movements}
+	if len(movements) == query.Limit {
+		result.Movements = movements[:query.Limit-1]
+		result.NextCursor = repositories.StockMovementCursor(result.Movements[len(result.Movements)-1]).Encode()
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+)
+
+func TestListStockMovementsUsecase_Execute(t *testing.T) {
+	t.Run("pages through the movements newest first", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		for _, quantity := range []int64{1, 2, 3} {
+			receive(t, fixture, fixture.main, fixture.small, quantity)
+		}
+		usecase := NewListStockMovementsUsecase(repositories.NewStockMovementRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, ListStockMovementsParam{ShopID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, result.Movements, 2)
+		assert.Equal(t, int64(3), result.Movements[0].Quantity)
+		assert.Equal(t, int64(6), result.Movements[0].BalanceAfter)
+		require.NotEmpty(t, result.NextCursor)
+
+		result, err = usecase.Execute(ctx, ListStockMovementsParam{ShopID: 1, Limit: 2, Cursor: result.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, result.Movements, 1)
+		assert.Equal(t, int64(1), result.Movements[0].Quantity)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("rejects unknown types and cursors", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		usecase := NewListStockMovementsUsecase(repositories.NewStockMovementRepository(fixture.db))
+
+		_, err := usecase.Execute(ctx, ListStockMovementsParam{ShopID: 1, Type: entities.MovementType("theft")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, ListStockMovementsParam{ShopID: 1, Cursor: repositories.StockLevelCursor(entities.StockLevel{LocationID: 1, VariantID: 1}).Encode()})
+		assert.EqualError(t, err, "validation failed: invalid cursor")
+	})
+}
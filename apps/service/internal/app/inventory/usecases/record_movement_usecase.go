@@ -0,0 +1,181 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	catalogrepositories "github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/services"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type RecordMovementUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewRecordMovementUsecase(db *gorm.DB) *RecordMovementUsecase {
+	return &RecordMovementUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type RecordMovementParam struct {
+	ShopID     uint64                `validate:"required"`
+	UserID     uint64                `validate:"required"`
+	LocationID uint64                `validate:"required"`
+	Type       entities.MovementType `validate:"required,oneof=receipt sale adjustment return"`
+	Reference  string                `validate:"max=100"`
+	Note       string                `validate:"max=500"`
+	Lines      []MovementLineParam   `validate:"required,min=1,max=100,dive"`
+	// AllowedLocationIDs are the locations the user works at, when limited
+	// to some; see accessentities.Staff.
+	AllowedLocationIDs []uint64
+}
+
+// MovementLineParam is the quantity of a variant that moves. It is positive
+// for every type but adjustments, which are negative to write stock off.
+type MovementLineParam struct {
+	VariantID uint64 `validate:"required"`
+	Quantity  int64  `validate:"required"`
+}
+
+type RecordMovementResult struct {
+	Movements []entities.StockMovement
+}
+
+// Execute records stock coming in or going out of a location of the shop,
+// one movement per line. Transfers between locations are recorded with
+// TransferStockUsecase.
+func (u *RecordMovementUsecase) Execute(ctx context.Context, param RecordMovementParam) (*RecordMovementResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if err := ensureLocationAllowed(param.AllowedLocationIDs, param.LocationID); err != nil {
+		return nil, err
+	}
+
+	movements := make([]entities.StockMovement, len(param.Lines))
+	for i, line := range param.Lines {
+		quantity := line.Quantity
+		switch param.Type {
+		case entities.MovementSale:
+			if quantity < 0 {
+				return nil, fmt.Errorf("validation failed: quantity of variant %d must be positive", line.VariantID)
+			}
+			quantity = -quantity
+		case entities.MovementReceipt, entities.MovementReturn:
+			if quantity < 0 {
+				return nil, fmt.Errorf("validation failed: quantity of variant %d must be positive", line.VariantID)
+			}
+		}
+
+		movements[i] = entities.StockMovement{
+			LocationID: param.LocationID,
+			VariantID:  line.VariantID,
+			Type:       param.Type,
+			Quantity:   quantity,
+			Reference:  strings.TrimSpace(param.Reference),
+			Note:       strings.TrimSpace(param.Note),
+			UserID:     param.UserID,
+		}
+	}
+
+	var result *RecordMovementResult
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findShopLocation(ctx, shoprepositories.NewLocationRepository(tx), param.ShopID, param.LocationID); err != nil {
+			return err
+		}
+		if err := ensureShopVariants(ctx, catalogrepositories.NewProductRepository(tx), param.ShopID, movements); err != nil {
+			return err
+		}
+
+		posted, err := newLedger(tx).Post(ctx, param.ShopID, movements)
+		if err != nil {
+			return err
+		}
+
+		result = &RecordMovementResult{
+			Movements: posted,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func newLedger(tx *gorm.DB) *services.Ledger {
+	return services.NewLedger(
+		repositories.NewStockLevelRepository(tx),
+		repositories.NewStockMovementRepository(tx),
+		repositories.NewSettingsRepository(tx),
+	)
+}
+
+// findShopLocation loads a location of the shop. Locations of other shops
+// are reported as missing.
+func findShopLocation(ctx context.Context, locationRepository shoprepositories.LocationRepository, shopID, locationID uint64) (shopentities.Location, error) {
+	location, err := locationRepository.FindByID(ctx, locationID)
+	if err != nil {
+		if err.Error() == "location not found" {
+			return location, errors.New("validation failed: location not found")
+		}
+		return location, fmt.Errorf("failed to get location: %w", err)
+	}
+	if location.ShopID != shopID {
+		return location, errors.New("validation failed: location not found")
+	}
+	return location, nil
+}
+
+// ensureLocationAllowed checks that a user limited to the allowed locations
+// works at the location. No allowed locations means every location.
+func ensureLocationAllowed(allowed []uint64, locationID uint64) error {
+	if len(allowed) == 0 || slices.Contains(allowed, locationID) {
+		return nil
+	}
+	return fmt.Errorf("forbidden: no access to location %d", locationID)
+}
+
+// ensureShopVariants checks that the movements are of live variants of the
+// shop.
+func ensureShopVariants(ctx context.Context, productRepository catalogrepositories.ProductRepository, shopID uint64, movements []entities.StockMovement) error {
+	checked := make(map[uint64]bool)
+	for _, movement := range movements {
+		if checked[movement.VariantID] {
+			continue
+		}
+
+		variant, err := productRepository.FindVariantByID(ctx, movement.VariantID)
+		if err != nil {
+			if err.Error() == "variant not found" {
+				return fmt.Errorf("validation failed: variant %d not found", movement.VariantID)
+			}
+			return fmt.Errorf("failed to get variant: %w", err)
+		}
+		if variant.ShopID != shopID {
+			return fmt.Errorf("validation failed: variant %d not found", movement.VariantID)
+		}
+		checked[movement.VariantID] = true
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	catalogentities "github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+// inventoryFixture is shop 1 with a Main and a Storage location and a
+// product with a small and a large variant, next to shop 2 with a location
+// and a variant of its own.
+type inventoryFixture struct {
+	db           *gorm.DB
+	main         shopentities.Location
+	storage      shopentities.Location
+	small        catalogentities.ProductVariant
+	large        catalogentities.ProductVariant
+	otherShop    shopentities.Location
+	otherVariant catalogentities.ProductVariant
+}
+
+func setupInventoryTest(t *testing.T) *inventoryFixture {
+	db := testutil.SetupTestDB(t,
+		&shopentities.Location{},
+		&catalogentities.Product{},
+		&catalogentities.ProductVariant{},
+		&entities.StockLevel{},
+		&entities.StockMovement{},
+		&entities.Settings{},
+	)
+
+	fixture := &inventoryFixture{
+		db:        db,
+		main:      shopentities.Location{ShopID: 1, Name: "Main"},
+		storage:   shopentities.Location{ShopID: 1, Name: "Storage"},
+		otherShop: shopentities.Location{ShopID: 2, Name: "Main"},
+	}
+	for _, location := range []*shopentities.Location{&fixture.main, &fixture.storage, &fixture.otherShop} {
+		require.NoError(t, db.Create(location).Error)
+	}
+
+	coffee := catalogentities.Product{
+		ShopID:   1,
+		Name:     "Coffee",
+		Variants: []catalogentities.ProductVariant{{ShopID: 1, SKU: "COF-S", Price: 300}, {ShopID: 1, SKU: "COF-L", Price: 450}},
+	}
+	require.NoError(t, db.Create(&coffee).Error)
+	fixture.small, fixture.large = coffee.Variants[0], coffee.Variants[1]
+
+	tea := catalogentities.Product{
+		ShopID:   2,
+		Name:     "Tea",
+		Variants: []catalogentities.ProductVariant{{ShopID: 2, SKU: "TEA", Price: 250}},
+	}
+	require.NoError(t, db.Create(&tea).Error)
+	fixture.otherVariant = tea.Variants[0]
+
+	return fixture
+}
+
+// receive records a receipt of quantity of variant at location.
+func receive(t *testing.T, fixture *inventoryFixture, location shopentities.Location, variant catalogentities.ProductVariant, quantity int64) {
+	_, err := NewRecordMovementUsecase(fixture.db).Execute(context.Background(), RecordMovementParam{
+		ShopID:     1,
+		UserID:     1,
+		LocationID: location.ID,
+		Type:       entities.MovementReceipt,
+		Lines:      []MovementLineParam{{VariantID: variant.ID, Quantity: quantity}},
+	})
+	require.NoError(t, err)
+}
+
+func onHand(t *testing.T, fixture *inventoryFixture, location shopentities.Location, variant catalogentities.ProductVariant) int64 {
+	level, err := repositories.NewStockLevelRepository(fixture.db).Find(context.Background(), location.ID, variant.ID)
+	require.NoError(t, err)
+	return level.OnHand
+}
+
+func TestRecordMovementUsecase_Execute(t *testing.T) {
+	t.Run("records a receipt", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+
+		result, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     7,
+			LocationID: fixture.main.ID,
+			Type:       entities.MovementReceipt,
+			Reference:  " PO-1001 ",
+			Lines: []MovementLineParam{
+				{VariantID: fixture.small.ID, Quantity: 10},
+				{VariantID: fixture.large.ID, Quantity: 4},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Movements, 2)
+		assert.Equal(t, int64(10), result.Movements[0].BalanceAfter)
+		assert.Equal(t, "PO-1001", result.Movements[0].Reference)
+		assert.Equal(t, uint64(7), result.Movements[0].UserID)
+		assert.Equal(t, int64(4), onHand(t, fixture, fixture.main, fixture.large))
+	})
+
+	t.Run("takes sales out of stock", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 10)
+
+		result, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.main.ID,
+			Type:       entities.MovementSale,
+			Lines:      []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 3}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(-3), result.Movements[0].Quantity)
+		assert.Equal(t, int64(7), onHand(t, fixture, fixture.main, fixture.small))
+	})
+
+	t.Run("adjusts stock either way", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 10)
+
+		for _, quantity := range []int64{-4, 1} {
+			_, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+				ShopID:     1,
+				UserID:     1,
+				LocationID: fixture.main.ID,
+				Type:       entities.MovementAdjustment,
+				Note:       "stock count",
+				Lines:      []MovementLineParam{{VariantID: fixture.small.ID, Quantity: quantity}},
+			})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int64(7), onHand(t, fixture, fixture.main, fixture.small))
+	})
+
+	t.Run("rolls back every line when one lacks stock", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 10)
+		receive(t, fixture, fixture.main, fixture.large, 1)
+
+		_, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.main.ID,
+			Type:       entities.MovementSale,
+			Lines: []MovementLineParam{
+				{VariantID: fixture.small.ID, Quantity: 2},
+				{VariantID: fixture.large.ID, Quantity: 2},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient stock")
+		assert.Equal(t, int64(10), onHand(t, fixture, fixture.main, fixture.small))
+
+		var count int64
+		fixture.db.Model(&entities.StockMovement{}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("rejects negative quantities for receipts, sales and returns", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+
+		for _, movementType := range []entities.MovementType{entities.MovementReceipt, entities.MovementSale, entities.MovementReturn} {
+			_, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+				ShopID:     1,
+				UserID:     1,
+				LocationID: fixture.main.ID,
+				Type:       movementType,
+				Lines:      []MovementLineParam{{VariantID: fixture.small.ID, Quantity: -1}},
+			})
+			require.Error(t, err, movementType)
+			assert.Contains(t, err.Error(), "must be positive")
+		}
+	})
+
+	t.Run("rejects transfers", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+
+		_, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.main.ID,
+			Type:       entities.MovementTransfer,
+			Lines:      []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("rejects locations and variants of other shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+
+		_, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.otherShop.ID,
+			Type:       entities.MovementReceipt,
+			Lines:      []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+		})
+		assert.EqualError(t, err, "validation failed: location not found")
+
+		_, err = NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.main.ID,
+			Type:       entities.MovementReceipt,
+			Lines:      []MovementLineParam{{VariantID: fixture.otherVariant.ID, Quantity: 1}},
+		})
+		assert.EqualError(t, err, "validation failed: variant 3 not found")
+	})
+	t.Run("keeps staff to the locations they work at", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		param := RecordMovementParam{
+			ShopID:             1,
+			UserID:             1,
+			LocationID:         fixture.storage.ID,
+			Type:               entities.MovementReceipt,
+			Lines:              []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		}
+
+		_, err := NewRecordMovementUsecase(fixture.db).Execute(ctx, param)
+		assert.EqualError(t, err, fmt.Sprintf("forbidden: no access to location %d", fixture.storage.ID))
+
+		param.LocationID = fixture.main.ID
+		_, err = NewRecordMovementUsecase(fixture.db).Execute(ctx, param)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), onHand(t, fixture, fixture.main, fixture.small))
+	})
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	catalogrepositories "github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type TransferStockUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewTransferStockUsecase(db *gorm.DB) *TransferStockUsecase {
+	return &TransferStockUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type TransferStockParam struct {
+	ShopID         uint64              `validate:"required"`
+	UserID         uint64              `validate:"required"`
+	FromLocationID uint64              `validate:"required"`
+	ToLocationID   uint64              `validate:"required"`
+	Reference      string              `validate:"max=100"`
+	Note           string              `validate:"max=500"`
+	Lines          []MovementLineParam `validate:"required,min=1,max=100,dive"`
+	// AllowedLocationIDs are the locations the user works at, when limited
+	// to some. Only the sending location has to be one of them.
+	AllowedLocationIDs []uint64
+}
+
+type TransferStockResult struct {
+	// Movements holds the movement out of the sending location and the
+	// movement into the receiving one for every line, in that order.
+	Movements []entities.StockMovement
+}
+
+// Execute moves stock between two locations of the shop. Both sides are
+// posted together, so stock is never counted at both or at neither.
+func (u *TransferStockUsecase) Execute(ctx context.Context, param TransferStockParam) (*TransferStockResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.FromLocationID == param.ToLocationID {
+		return nil, errors.New("validation failed: stock cannot be transferred to the location it is at")
+	}
+	if err := ensureLocationAllowed(param.AllowedLocationIDs, param.FromLocationID); err != nil {
+		return nil, err
+	}
+
+	movements := make([]entities.StockMovement, 0, 2*len(param.Lines))
+	for _, line := range param.Lines {
+		if line.Quantity < 0 {
+			return nil, fmt.Errorf("validation failed: quantity of variant %d must be positive", line.VariantID)
+		}
+
+		out := entities.StockMovement{
+			LocationID:         param.FromLocationID,
+			VariantID:          line.VariantID,
+			Type:               entities.MovementTransfer,
+			Quantity:           -line.Quantity,
+			TransferLocationID: &param.ToLocationID,
+			Reference:          strings.TrimSpace(param.Reference),
+			Note:               strings.TrimSpace(param.Note),
+			UserID:             param.UserID,
+		}
+		in := out
+		in.LocationID = param.ToLocationID
+		in.Quantity = line.Quantity
+		in.TransferLocationID = &param.FromLocationID
+		movements = append(movements, out, in)
+	}
+
+	var result *TransferStockResult
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txLocationRepo := shoprepositories.NewLocationRepository(tx)
+		if _, err := findShopLocation(ctx, txLocationRepo, param.ShopID, param.FromLocationID); err != nil {
+			return err
+		}
+		if _, err := findShopLocation(ctx, txLocationRepo, param.ShopID, param.ToLocationID); err != nil {
+			return err
+		}
+		if err := ensureShopVariants(ctx, catalogrepositories.NewProductRepository(tx), param.ShopID, movements); err != nil {
+			return err
+		}
+
+		posted, err := newLedger(tx).Post(ctx, param.ShopID, movements)
+		if err != nil {
+			return err
+		}
+
+		result = &TransferStockResult{
+			Movements: posted,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
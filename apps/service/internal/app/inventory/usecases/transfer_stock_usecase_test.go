@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+)
+
+func TestTransferStockUsecase_Execute(t *testing.T) {
+	t.Run("moves stock between locations", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.storage, fixture.small, 10)
+
+		result, err := NewTransferStockUsecase(fixture.db).Execute(ctx, TransferStockParam{
+			ShopID:         1,
+			UserID:         1,
+			FromLocationID: fixture.storage.ID,
+			ToLocationID:   fixture.main.ID,
+			Lines:          []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 4}},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Movements, 2)
+
+		out, in := result.Movements[0], result.Movements[1]
+		assert.Equal(t, entities.MovementTransfer, out.Type)
+		assert.Equal(t, int64(-4), out.Quantity)
+		assert.Equal(t, fixture.main.ID, *out.TransferLocationID)
+		assert.Equal(t, int64(4), in.Quantity)
+		assert.Equal(t, fixture.storage.ID, *in.TransferLocationID)
+
+		assert.Equal(t, int64(6), onHand(t, fixture, fixture.storage, fixture.small))
+		assert.Equal(t, int64(4), onHand(t, fixture, fixture.main, fixture.small))
+	})
+
+	t.Run("leaves both locations alone when stock is short", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.storage, fixture.small, 1)
+
+		_, err := NewTransferStockUsecase(fixture.db).Execute(ctx, TransferStockParam{
+			ShopID:         1,
+			UserID:         1,
+			FromLocationID: fixture.storage.ID,
+			ToLocationID:   fixture.main.ID,
+			Lines:          []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 2}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient stock")
+		assert.Equal(t, int64(1), onHand(t, fixture, fixture.storage, fixture.small))
+		assert.Equal(t, int64(0), onHand(t, fixture, fixture.main, fixture.small))
+	})
+
+	t.Run("rejects transfers to the same location", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+
+		_, err := NewTransferStockUsecase(fixture.db).Execute(ctx, TransferStockParam{
+			ShopID:         1,
+			UserID:         1,
+			FromLocationID: fixture.main.ID,
+			ToLocationID:   fixture.main.ID,
+			Lines:          []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("rejects locations of other shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+
+		_, err := NewTransferStockUsecase(fixture.db).Execute(ctx, TransferStockParam{
+			ShopID:         1,
+			UserID:         1,
+			FromLocationID: fixture.main.ID,
+			ToLocationID:   fixture.otherShop.ID,
+			Lines:          []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+		})
+		assert.EqualError(t, err, "validation failed: location not found")
+	})
+	t.Run("only needs access to the sending location", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		receive(t, fixture, fixture.main, fixture.small, 5)
+		receive(t, fixture, fixture.storage, fixture.small, 5)
+
+		_, err := NewTransferStockUsecase(fixture.db).Execute(ctx, TransferStockParam{
+			ShopID:             1,
+			UserID:             1,
+			FromLocationID:     fixture.storage.ID,
+			ToLocationID:       fixture.main.ID,
+			Lines:              []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+
+		_, err = NewTransferStockUsecase(fixture.db).Execute(ctx, TransferStockParam{
+			ShopID:             1,
+			UserID:             1,
+			FromLocationID:     fixture.main.ID,
+			ToLocationID:       fixture.storage.ID,
+			Lines:              []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 1}},
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), onHand(t, fixture, fixture.storage, fixture.small))
+	})
+}
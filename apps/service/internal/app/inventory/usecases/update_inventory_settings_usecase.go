@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+)
+
+type UpdateInventorySettingsUsecase struct {
+	settingsRepository repositories.SettingsRepository
+}
+
+func NewUpdateInventorySettingsUsecase(settingsRepository repositories.SettingsRepository) *UpdateInventorySettingsUsecase {
+	return &UpdateInventorySettingsUsecase{
+		settingsRepository: settingsRepository,
+	}
+}
+
+type UpdateInventorySettingsParam struct {
+	ShopID             uint64
+	AllowNegativeStock bool
+}
+
+type UpdateInventorySettingsResult struct {
+	Settings entities.Settings
+}
+
+// Execute replaces the inventory policy of the shop. Turning negative stock
+// off leaves levels already below zero as they are; they only block further
+// movements out.
+func (u *UpdateInventorySettingsUsecase) Execute(ctx context.Context, param UpdateInventorySettingsParam) (*UpdateInventorySettingsResult, error) {
+	settings, err := u.settingsRepository.Save(ctx, entities.Settings{
+		ShopID:             param.ShopID,
+		AllowNegativeStock: param.AllowNegativeStock,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update inventory settings: %w", err)
+	}
+	return &UpdateInventorySettingsResult{Settings: settings}, nil
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+)
+
+func TestUpdateInventorySettingsUsecase_Execute(t *testing.T) {
+	t.Run("lets stock go negative once allowed", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupInventoryTest(t)
+		settingsRepo := repositories.NewSettingsRepository(fixture.db)
+
+		current, err := NewGetInventorySettingsUsecase(settingsRepo).Execute(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, current.Settings.AllowNegativeStock)
+
+		result, err := NewUpdateInventorySettingsUsecase(settingsRepo).Execute(ctx, UpdateInventorySettingsParam{ShopID: 1, AllowNegativeStock: true})
+		require.NoError(t, err)
+		assert.True(t, result.Settings.AllowNegativeStock)
+
+		_, err = NewRecordMovementUsecase(fixture.db).Execute(ctx, RecordMovementParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.main.ID,
+			Type:       entities.MovementSale,
+			Lines:      []MovementLineParam{{VariantID: fixture.small.ID, Quantity: 2}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(-2), onHand(t, fixture, fixture.main, fixture.small))
+	})
+}
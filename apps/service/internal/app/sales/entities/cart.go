@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	CartStatusOpen       = "open"
+	CartStatusCheckedOut = "checked_out"
+)
+
+// TaxRateScale is what a TaxRate of 100% amounts to: rates are kept in basis
+// points, so 1000 is 10%.
+const TaxRateScale = 10000
+
+// Cart is a sale being rung up at a location of a shop. Lines are priced as
+// they are added, so later price changes leave open carts alone.
+// DiscountAmount is taken off the whole cart after the discounts of its
+// lines, and tax is charged at TaxRate on what is left. Amounts are in the
+// minor unit of the shop's currency. Checked out carts are kept but no
+// longer change; their sale is the record of what was sold.
+type Cart struct {
+	ID             uint64    `gorm:"primaryKey;column:id" json:"id"`
+	ShopID         uint64    `gorm:"column:shop_id;not null;index:idx_carts_shop_id" json:"shop_id"`
+	LocationID     uint64    `gorm:"column:location_id;not null" json:"location_id"`
+	UserID         uint64    `gorm:"column:user_id;not null" json:"user_id"`
+	Status         string    `gorm:"column:status;type:varchar(20);not null;default:open" json:"status"`
+	DiscountAmount int64     `gorm:"column:discount_amount;not null" json:"discount_amount"`
+	TaxRate        int64     `gorm:"column:tax_rate;not null" json:"tax_rate"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at" json:"updated_at"`
+
+	Lines []CartLine `gorm:"foreignKey:CartID" json:"lines,omitempty"`
+}
+
+func (Cart) TableName() string {
+	return "carts"
+}
+
+func (c Cart) IsOpen() bool {
+	return c.Status == CartStatusOpen
+}
+
+// Totals adds up the lines of the cart and applies its discount and tax.
+func (c Cart) Totals() Totals {
+	var totals Totals
+	for _, line := range c.Lines {
+		totals.Subtotal += line.Amount()
+		totals.Discount += line.DiscountAmount
+	}
+	totals.Discount += c.DiscountAmount
+
+	taxable := totals.Subtotal - totals.Discount
+	// Tax is rounded half up to the minor unit.
+	totals.Tax = (taxable*c.TaxRate + TaxRateScale/2) / TaxRateScale
+	totals.Total = taxable + totals.Tax
+	return totals
+}
+
+// CartLine is a quantity of a variant in a cart. SKU, Name and UnitPrice are
+// copied from the catalog when the line is added. DiscountAmount is taken off
+// the line as a whole.
+type CartLine struct {
+	ID             uint64    `gorm:"primaryKey;column:id" json:"id"`
+	CartID         uint64    `gorm:"column:cart_id;not null;uniqueIndex:idx_cart_lines_cart_id_variant_id" json:"cart_id"`
+	VariantID      uint64    `gorm:"column:variant_id;not null;uniqueIndex:idx_cart_lines_cart_id_variant_id" json:"variant_id"`
+	SKU            string    `gorm:"column:sku;not null" json:"sku"`
+	Name           string    `gorm:"column:name;not null" json:"name"`
+	UnitPrice      int64     `gorm:"column:unit_price;not null" json:"unit_price"`
+	Quantity       int64     `gorm:"column:quantity;not null" json:"quantity"`
+	DiscountAmount int64     `gorm:"column:discount_amount;not null" json:"discount_amount"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (CartLine) TableName() string {
+	return "cart_lines"
+}
+
+// Amount is the price of the line before its discount.
+func (l CartLine) Amount() int64 {
+	return l.UnitPrice * l.Quantity
+}
+
+// Total is the price of the line after its discount.
+func (l CartLine) Total() int64 {
+	return l.Amount() - l.DiscountAmount
+}
+
+// Totals sums up a cart. Discount covers the discounts of the lines and of
+// the cart, and Total is what the customer pays: Subtotal less Discount plus
+// Tax.
+type Totals struct {
+	Subtotal int64
+	Discount int64
+	Tax      int64
+	Total    int64
+}
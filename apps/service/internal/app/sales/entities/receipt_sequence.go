@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+)
+
+// ReceiptSequence keeps the last receipt number handed out at a location.
+type ReceiptSequence struct {
+	LocationID uint64    `gorm:"primaryKey;column:location_id;autoIncrement:false" json:"location_id"`
+	ShopID     uint64    `gorm:"column:shop_id;not null" json:"shop_id"`
+	LastNumber int64     `gorm:"column:last_number;not null" json:"last_number"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (ReceiptSequence) TableName() string {
+	return "receipt_sequences"
+}
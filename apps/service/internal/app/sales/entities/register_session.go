@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	RegisterSessionOpen   = "open"
+	RegisterSessionClosed = "closed"
+)
+
+// CashMovementType tells whether cash was put into or taken out of the
+// drawer outside of a sale.
+type CashMovementType string
+
+const (
+	CashIn  CashMovementType = "in"
+	CashOut CashMovementType = "out"
+)
+
+// RegisterSession is a shift on the till of an outlet, from the staff member
+// who counts in the OpeningFloat to the one who counts the drawer at close.
+// A location has at most one open session, and the sales made there while it
+// is open belong to it. ExpectedCash, CountedCash and Variance are set when
+// the session is closed; Variance is what was counted less what was
+// expected, so a short drawer has a negative variance.
+type RegisterSession struct {
+	ID              uint64     `gorm:"primaryKey;column:id" json:"id"`
+	ShopID          uint64     `gorm:"column:shop_id;not null;index:idx_register_sessions_shop_id" json:"shop_id"`
+	LocationID      uint64     `gorm:"column:location_id;not null;uniqueIndex:idx_register_sessions_open_location_id,where:status = 'open'" json:"location_id"`
+	Status          string     `gorm:"column:status;type:varchar(20);not null;default:open" json:"status"`
+	OpeningFloat    int64      `gorm:"column:opening_float;not null" json:"opening_float"`
+	OpenedByStaffID uint64     `gorm:"column:opened_by_staff_id;not null" json:"opened_by_staff_id"`
+	OpenedAt        time.Time  `gorm:"column:opened_at;not null" json:"opened_at"`
+	ClosedByStaffID *uint64    `gorm:"column:closed_by_staff_id" json:"closed_by_staff_id"`
+	ClosedAt        *time.Time `gorm:"column:closed_at" json:"closed_at"`
+	ExpectedCash    int64      `gorm:"column:expected_cash;not null" json:"expected_cash"`
+	CountedCash     int64      `gorm:"column:counted_cash;not null" json:"counted_cash"`
+	Variance        int64      `gorm:"column:variance;not null" json:"variance"`
+	Note            string     `gorm:"column:note;not null" json:"note"`
+
+	CashMovements []RegisterCashMovement `gorm:"foreignKey:SessionID" json:"cash_movements,omitempty"`
+}
+
+func (RegisterSession) TableName() string {
+	return "register_sessions"
+}
+
+func (s RegisterSession) IsOpen() bool {
+	return s.Status == RegisterSessionOpen
+}
+
+// CashTotals sums the cash put into and taken out of the drawer.
+func (s RegisterSession) CashTotals() (int64, int64) {
+	var in, out int64
+	for _, movement := range s.CashMovements {
+		switch movement.Type {
+		case CashIn:
+			in += movement.Amount
+		case CashOut:
+			out += movement.Amount
+		}
+	}
+	return in, out
+}
+
+// RegisterCashMovement is cash put into or taken out of the drawer during a
+// session other than through a sale, such as change brought from the bank
+// or a payout to a supplier. Amount is always positive. Movements are never
+// changed or removed.
+type RegisterCashMovement struct {
+	ID        uint64           `gorm:"primaryKey;column:id" json:"id"`
+	SessionID uint64           `gorm:"column:session_id;not null;index:idx_register_cash_movements_session_id" json:"session_id"`
+	Type      CashMovementType `gorm:"column:type;type:varchar(10);not null" json:"type"`
+	Amount    int64            `gorm:"column:amount;not null" json:"amount"`
+	Reason    string           `gorm:"column:reason;not null" json:"reason"`
+	StaffID   uint64           `gorm:"column:staff_id;not null" json:"staff_id"`
+	CreatedAt time.Time        `gorm:"column:created_at" json:"created_at"`
+}
+
+func (RegisterCashMovement) TableName() string {
+	return "register_cash_movements"
+}
+
+// RegisterReport sums up a register session: what was sold, how it was paid
+// and how much cash the drawer should hold. Once the session is closed it is
+// its Z-report.
+type RegisterReport struct {
+	Sales        SaleTotals
+	Payments     []PaymentTotal
+	CashIn       int64
+	CashOut      int64
+	ExpectedCash int64
+}
+
+// SaleTotals adds up a set of sales.
+type SaleTotals struct {
+	Count         int64
+	Subtotal      int64
+	DiscountTotal int64
+	TaxTotal      int64
+	Total         int64
+	Change        int64
+}
+
+// PaymentTotal adds up the payments of a set of sales made with Method.
+type PaymentTotal struct {
+	Method PaymentMethod
+	Count  int64
+	Amount int64
+}
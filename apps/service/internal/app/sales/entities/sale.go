@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+)
+
+// PaymentMethod tells how the customer paid for a sale.
+type PaymentMethod string
+
+const (
+	PaymentCash     PaymentMethod = "cash"
+	PaymentCard     PaymentMethod = "card"
+	PaymentTransfer PaymentMethod = "transfer"
+)
+
+// PaymentMethods lists every payment method, in the order reports show them.
+var PaymentMethods = []PaymentMethod{PaymentCash, PaymentCard, PaymentTransfer}
+
+// Sale is a checked out cart. ReceiptNumber counts the sales of the location
+// from 1. Paid is what the customer handed over across all payments and
+// Change what was given back, which only cash payments can call for.
+// RegisterSessionID is the register session open at the location when the
+// sale was made, if any. Sales, their lines and their payments are never
+// changed or removed.
+type Sale struct {
+	ID                uint64    `gorm:"primaryKey;column:id" json:"id"`
+	ShopID            uint64    `gorm:"column:shop_id;not null;index:idx_sales_shop_id" json:"shop_id"`
+	LocationID        uint64    `gorm:"column:location_id;not null;uniqueIndex:idx_sales_location_id_receipt_number" json:"location_id"`
+	CartID            uint64    `gorm:"column:cart_id;not null;uniqueIndex:idx_sales_cart_id" json:"cart_id"`
+	UserID            uint64    `gorm:"column:user_id;not null" json:"user_id"`
+	RegisterSessionID *uint64   `gorm:"column:register_session_id;index:idx_sales_register_session_id" json:"register_session_id"`
+	ReceiptNumber     int64     `gorm:"column:receipt_number;not null;uniqueIndex:idx_sales_location_id_receipt_number" json:"receipt_number"`
+	Subtotal          int64     `gorm:"column:subtotal;not null" json:"subtotal"`
+	DiscountTotal     int64     `gorm:"column:discount_total;not null" json:"discount_total"`
+	TaxRate           int64     `gorm:"column:tax_rate;not null" json:"tax_rate"`
+	TaxTotal          int64     `gorm:"column:tax_total;not null" json:"tax_total"`
+	Total             int64     `gorm:"column:total;not null" json:"total"`
+	Paid              int64     `gorm:"column:paid;not null" json:"paid"`
+	Change            int64     `gorm:"column:change;not null" json:"change"`
+	CreatedAt         time.Time `gorm:"column:created_at" json:"created_at"`
+
+	Lines    []SaleLine    `gorm:"foreignKey:SaleID" json:"lines,omitempty"`
+	Payments []SalePayment `gorm:"foreignKey:SaleID" json:"payments,omitempty"`
+}
+
+func (Sale) TableName() string {
+	return "sales"
+}
+
+// SaleLine is a line of the cart as it was checked out.
+type SaleLine struct {
+	ID             uint64 `gorm:"primaryKey;column:id" json:"id"`
+	SaleID         uint64 `gorm:"column:sale_id;not null;index:idx_sale_lines_sale_id" json:"sale_id"`
+	VariantID      uint64 `gorm:"column:variant_id;not null" json:"variant_id"`
+	SKU            string `gorm:"column:sku;not null" json:"sku"`
+	Name           string `gorm:"column:name;not null" json:"name"`
+	UnitPrice      int64  `gorm:"column:unit_price;not null" json:"unit_price"`
+	Quantity       int64  `gorm:"column:quantity;not null" json:"quantity"`
+	DiscountAmount int64  `gorm:"column:discount_amount;not null" json:"discount_amount"`
+	Total          int64  `gorm:"column:total;not null" json:"total"`
+}
+
+func (SaleLine) TableName() string {
+	return "sale_lines"
+}
+
+// SalePayment is an amount paid towards a sale. Reference identifies card
+// and transfer payments with the payment provider or bank.
+type SalePayment struct {
+	ID        uint64        `gorm:"primaryKey;column:id" json:"id"`
+	SaleID    uint64        `gorm:"column:sale_id;not null;index:idx_sale_payments_sale_id" json:"sale_id"`
+	Method    PaymentMethod `gorm:"column:method;type:varchar(20);not null" json:"method"`
+	Amount    int64         `gorm:"column:amount;not null" json:"amount"`
+	Reference string        `gorm:"column:reference;not null" json:"reference"`
+}
+
+func (SalePayment) TableName() string {
+	return "sale_payments"
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+// CartRepository loads carts with their lines, ordered by ID.
+type CartRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Cart, error)
+	Create(ctx context.Context, cart entities.Cart) (entities.Cart, error)
+	// Update saves the cart's own fields; its lines are saved with
+	// CreateLine, UpdateLine and DeleteLine.
+	Update(ctx context.Context, cart entities.Cart) (entities.Cart, error)
+	// Delete removes the cart and its lines.
+	Delete(ctx context.Context, cart entities.Cart) error
+	// CheckOut marks an open cart checked out. Of two calls racing for the
+	// same cart only one succeeds; the other gets "cart is already checked
+	// out".
+	CheckOut(ctx context.Context, cartID uint64) error
+	CreateLine(ctx context.Context, line entities.CartLine) (entities.CartLine, error)
+	UpdateLine(ctx context.Context, line entities.CartLine) (entities.CartLine, error)
+	DeleteLine(ctx context.Context, line entities.CartLine) error
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepository{
+		db: db,
+	}
+}
+
+func (r *cartRepository) FindByID(ctx context.Context, id uint64) (entities.Cart, error) {
+	var cart entities.Cart
+	err := r.db.WithContext(ctx).Where("id = ?", id).Preload("Lines", orderByID).First(&cart).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return cart, errors.New("cart not found")
+		}
+		return cart, err
+	}
+	return cart, nil
+}
+
+func (r *cartRepository) Create(ctx context.Context, cart entities.Cart) (entities.Cart, error) {
+	err := r.db.WithContext(ctx).Create(&cart).Error
+	if err != nil {
+		return cart, err
+	}
+	return cart, nil
+}
+
+func (r *cartRepository) Update(ctx context.Context, cart entities.Cart) (entities.Cart, error) {
+	err := r.db.WithContext(ctx).Omit("Lines").Save(&cart).Error
+	if err != nil {
+		return cart, err
+	}
+	return cart, nil
+}
+
+func (r *cartRepository) Delete(ctx context.Context, cart entities.Cart) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cart_id = ?", cart.ID).Delete(&entities.CartLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&cart).Error
+	})
+}
+
+func (r *cartRepository) CheckOut(ctx context.Context, cartID uint64) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.Cart{}).
+		Where("id = ? AND status = ?", cartID, entities.CartStatusOpen).
+		Update("status", entities.CartStatusCheckedOut)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("cart is already checked out")
+	}
+	return nil
+}
+
+func (r *cartRepository) CreateLine(ctx context.Context, line entities.CartLine) (entities.CartLine, error) {
+	err := r.db.WithContext(ctx).Create(&line).Error
+	if err != nil {
+		return line, err
+	}
+	return line, nil
+}
+
+func (r *cartRepository) UpdateLine(ctx context.Context, line entities.CartLine) (entities.CartLine, error) {
+	err := r.db.WithContext(ctx).Save(&line).Error
+	if err != nil {
+		return line, err
+	}
+	return line, nil
+}
+
+func (r *cartRepository) DeleteLine(ctx context.Context, line entities.CartLine) error {
+	return r.db.WithContext(ctx).Delete(&line).Error
+}
+
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
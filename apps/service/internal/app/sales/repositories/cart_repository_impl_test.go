@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestCartRepository(t *testing.T) {
+	t.Run("loads a cart with its lines", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Cart{}, &entities.CartLine{})
+		repo := NewCartRepository(db)
+
+		cart, err := repo.Create(ctx, entities.Cart{ShopID: 1, LocationID: 10, UserID: 1, Status: entities.CartStatusOpen})
+		require.NoError(t, err)
+		_, err = repo.CreateLine(ctx, entities.CartLine{CartID: cart.ID, VariantID: 101, SKU: "B", UnitPrice: 200, Quantity: 1})
+		require.NoError(t, err)
+		_, err = repo.CreateLine(ctx, entities.CartLine{CartID: cart.ID, VariantID: 100, SKU: "A", UnitPrice: 100, Quantity: 2})
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(ctx, cart.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Lines, 2)
+		assert.Equal(t, "B", found.Lines[0].SKU)
+		assert.Equal(t, int64(400), found.Totals().Total)
+
+		_, err = repo.FindByID(ctx, cart.ID+1)
+		assert.EqualError(t, err, "cart not found")
+	})
+
+	t.Run("checks a cart out once", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Cart{}, &entities.CartLine{})
+		repo := NewCartRepository(db)
+
+		cart, err := repo.Create(ctx, entities.Cart{ShopID: 1, LocationID: 10, UserID: 1, Status: entities.CartStatusOpen})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.CheckOut(ctx, cart.ID))
+		assert.EqualError(t, repo.CheckOut(ctx, cart.ID), "cart is already checked out")
+
+		found, err := repo.FindByID(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsOpen())
+	})
+
+	t.Run("deletes a cart with its lines", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Cart{}, &entities.CartLine{})
+		repo := NewCartRepository(db)
+
+		cart, err := repo.Create(ctx, entities.Cart{ShopID: 1, LocationID: 10, UserID: 1, Status: entities.CartStatusOpen})
+		require.NoError(t, err)
+		_, err = repo.CreateLine(ctx, entities.CartLine{CartID: cart.ID, VariantID: 100, SKU: "A", UnitPrice: 100, Quantity: 1})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, cart))
+
+		var lines int64
+		require.NoError(t, db.Model(&entities.CartLine{}).Count(&lines).Error)
+		assert.Zero(t, lines)
+		_, err = repo.FindByID(ctx, cart.ID)
+		assert.EqualError(t, err, "cart not found")
+	})
+}
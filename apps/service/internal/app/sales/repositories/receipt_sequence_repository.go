@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+)
+
+// ReceiptSequenceRepository hands out receipt numbers, counting up from 1 at
+// every location.
+type ReceiptSequenceRepository interface {
+	// Next returns the next receipt number of the location. The sequence
+	// stays locked until the surrounding transaction ends, so numbers are
+	// neither skipped nor handed out twice.
+	Next(ctx context.Context, shopID uint64, locationID uint64) (int64, error)
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+type receiptSequenceRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptSequenceRepository(db *gorm.DB) ReceiptSequenceRepository {
+	return &receiptSequenceRepository{
+		db: db,
+	}
+}
+
+func (r *receiptSequenceRepository) Next(ctx context.Context, shopID uint64, locationID uint64) (int64, error) {
+	sequence := entities.ReceiptSequence{
+		LocationID: locationID,
+		ShopID:     shopID,
+		LastNumber: 1,
+		UpdatedAt:  time.Now(),
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "location_id"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"last_number": gorm.Expr("receipt_sequences.last_number + 1"),
+					"updated_at":  gorm.Expr("excluded.updated_at"),
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(&sequence).Error
+	if err != nil {
+		return 0, err
+	}
+	return sequence.LastNumber, nil
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestReceiptSequenceRepository(t *testing.T) {
+	t.Run("counts up from 1 at every location", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.ReceiptSequence{})
+		repo := NewReceiptSequenceRepository(db)
+
+		for _, want := range []int64{1, 2, 3} {
+			number, err := repo.Next(ctx, 1, 10)
+			require.NoError(t, err)
+			assert.Equal(t, want, number)
+		}
+
+		number, err := repo.Next(ctx, 1, 11)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), number)
+	})
+}
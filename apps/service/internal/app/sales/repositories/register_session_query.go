@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// RegisterSessionSortRecent is the order session lists come in: newest
+// first.
+const RegisterSessionSortRecent = "recent"
+
+// RegisterSessionQuery filters and pages the register sessions of a shop.
+// After, when set, resumes the list past the session it points to.
+// LocationIDs, when not empty, limits the list to those locations.
+type RegisterSessionQuery struct {
+	ShopID      uint64
+	LocationID  *uint64
+	LocationIDs []uint64
+	Status      string
+	After       *pagination.Cursor
+	Limit       int
+}
+
+// RegisterSessionQueryScope applies the query to a statement selecting from
+// the register_sessions table.
+func RegisterSessionQueryScope(query RegisterSessionQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("shop_id = ?", query.ShopID)
+		if query.LocationID != nil {
+			db = db.Where("location_id = ?", *query.LocationID)
+		}
+		if len(query.LocationIDs) > 0 {
+			db = db.Where("location_id IN ?", query.LocationIDs)
+		}
+		if query.Status != "" {
+			db = db.Where("status = ?", query.Status)
+		}
+		if query.After != nil {
+			db = db.Where("id < ?", query.After.ID)
+		}
+
+		db = db.Order("id DESC")
+		if query.Limit > 0 {
+			db = db.Limit(query.Limit)
+		}
+		return db
+	}
+}
+
+// RegisterSessionCursor returns the cursor resuming a session list after
+// session.
+func RegisterSessionCursor(session entities.RegisterSession) pagination.Cursor {
+	return pagination.Cursor{Sort: RegisterSessionSortRecent, ID: session.ID}
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+// RegisterSessionRepository loads register sessions with their cash
+// movements, ordered by ID.
+type RegisterSessionRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.RegisterSession, error)
+	// FindOpenByLocationID loads the open session of the location and,
+	// within a transaction, keeps it from being closed until the
+	// transaction ends, so whatever the transaction records against it
+	// makes it into its totals.
+	FindOpenByLocationID(ctx context.Context, locationID uint64) (entities.RegisterSession, error)
+	Search(ctx context.Context, query RegisterSessionQuery) []entities.RegisterSession
+	Create(ctx context.Context, session entities.RegisterSession) (entities.RegisterSession, error)
+	// Close marks an open session closed by ClosedByStaffID at ClosedAt,
+	// with its CountedCash and Note. Of two calls racing for the same
+	// session only one succeeds; the other gets "register session is
+	// already closed".
+	Close(ctx context.Context, session entities.RegisterSession) error
+	// Update saves the session's own fields.
+	Update(ctx context.Context, session entities.RegisterSession) (entities.RegisterSession, error)
+	CreateCashMovement(ctx context.Context, movement entities.RegisterCashMovement) (entities.RegisterCashMovement, error)
+}
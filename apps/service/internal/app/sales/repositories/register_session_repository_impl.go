@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+type registerSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegisterSessionRepository(db *gorm.DB) RegisterSessionRepository {
+	return &registerSessionRepository{
+		db: db,
+	}
+}
+
+func (r *registerSessionRepository) FindByID(ctx context.Context, id uint64) (entities.RegisterSession, error) {
+	var session entities.RegisterSession
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Preload("CashMovements", orderByID).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, errors.New("register session not found")
+		}
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *registerSessionRepository) FindOpenByLocationID(ctx context.Context, locationID uint64) (entities.RegisterSession, error) {
+	// Closing updates the row, which waits for the share lock taken here.
+	var session entities.RegisterSession
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthShare}).
+		Where("location_id = ? AND status = ?", locationID, entities.RegisterSessionOpen).
+		Preload("CashMovements", orderByID).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, errors.New("register session not found")
+		}
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *registerSessionRepository) Search(ctx context.Context, query RegisterSessionQuery) []entities.RegisterSession {
+	var sessions []entities.RegisterSession
+	r.db.WithContext(ctx).
+		Scopes(RegisterSessionQueryScope(query)).
+		Preload("CashMovements", orderByID).
+		Find(&sessions)
+	return sessions
+}
+
+func (r *registerSessionRepository) Create(ctx context.Context, session entities.RegisterSession) (entities.RegisterSession, error) {
+	err := r.db.WithContext(ctx).Omit("CashMovements").Create(&session).Error
+	if err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *registerSessionRepository) Close(ctx context.Context, session entities.RegisterSession) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.RegisterSession{}).
+		Where("id = ? AND status = ?", session.ID, entities.RegisterSessionOpen).
+		Updates(map[string]any{
+			"status":             entities.RegisterSessionClosed,
+			"closed_by_staff_id": session.ClosedByStaffID,
+			"closed_at":          session.ClosedAt,
+			"counted_cash":       session.CountedCash,
+			"note":               session.Note,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("register session is already closed")
+	}
+	return nil
+}
+
+func (r *registerSessionRepository) Update(ctx context.Context, session entities.RegisterSession) (entities.RegisterSession, error) {
+	err := r.db.WithContext(ctx).Omit("CashMovements").Save(&session).Error
+	if err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+func (r *registerSessionRepository) CreateCashMovement(ctx context.Context, movement entities.RegisterCashMovement) (entities.RegisterCashMovement, error) {
+	err := r.db.WithContext(ctx).Create(&movement).Error
+	if err != nil {
+		return movement, err
+	}
+	return movement, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestRegisterSessionRepository(t *testing.T) {
+	t.Run("finds the open session of a location", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RegisterSession{}, &entities.RegisterCashMovement{})
+		repo := NewRegisterSessionRepository(db)
+
+		session, err := repo.Create(ctx, entities.RegisterSession{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionOpen, OpeningFloat: 5000, OpenedByStaffID: 3, OpenedAt: time.Now()})
+		require.NoError(t, err)
+		_, err = repo.CreateCashMovement(ctx, entities.RegisterCashMovement{SessionID: session.ID, Type: entities.CashOut, Amount: 700, Reason: "Window cleaner", StaffID: 3})
+		require.NoError(t, err)
+
+		found, err := repo.FindOpenByLocationID(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, session.ID, found.ID)
+		require.Len(t, found.CashMovements, 1)
+		assert.Equal(t, int64(700), found.CashMovements[0].Amount)
+
+		_, err = repo.FindOpenByLocationID(ctx, 11)
+		assert.EqualError(t, err, "register session not found")
+	})
+
+	t.Run("a location has one open session at a time", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RegisterSession{}, &entities.RegisterCashMovement{})
+		repo := NewRegisterSessionRepository(db)
+
+		_, err := repo.Create(ctx, entities.RegisterSession{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionClosed, OpenedAt: time.Now()})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, entities.RegisterSession{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionOpen, OpenedAt: time.Now()})
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, entities.RegisterSession{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionOpen, OpenedAt: time.Now()})
+		assert.Error(t, err)
+	})
+
+	t.Run("closes a session once", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RegisterSession{}, &entities.RegisterCashMovement{})
+		repo := NewRegisterSessionRepository(db)
+
+		session, err := repo.Create(ctx, entities.RegisterSession{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionOpen, OpenedAt: time.Now()})
+		require.NoError(t, err)
+
+		staffID := uint64(4)
+		closedAt := time.Now()
+		session.ClosedByStaffID = &staffID
+		session.ClosedAt = &closedAt
+		session.CountedCash = 4200
+		require.NoError(t, repo.Close(ctx, session))
+		assert.EqualError(t, repo.Close(ctx, session), "register session is already closed")
+
+		found, err := repo.FindByID(ctx, session.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsOpen())
+		assert.Equal(t, &staffID, found.ClosedByStaffID)
+		assert.Equal(t, int64(4200), found.CountedCash)
+
+		_, err = repo.FindOpenByLocationID(ctx, 10)
+		assert.EqualError(t, err, "register session not found")
+	})
+
+	t.Run("searches the sessions of a shop newest first", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.RegisterSession{}, &entities.RegisterCashMovement{})
+		repo := NewRegisterSessionRepository(db)
+
+		for _, session := range []entities.RegisterSession{
+			{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionClosed, OpeningFloat: 100},
+			{ShopID: 1, LocationID: 11, Status: entities.RegisterSessionOpen, OpeningFloat: 200},
+			{ShopID: 1, LocationID: 10, Status: entities.RegisterSessionOpen, OpeningFloat: 300},
+			{ShopID: 2, LocationID: 20, Status: entities.RegisterSessionOpen, OpeningFloat: 400},
+		} {
+			session.OpenedAt = time.Now()
+			_, err := repo.Create(ctx, session)
+			require.NoError(t, err)
+		}
+
+		sessions := repo.Search(ctx, RegisterSessionQuery{ShopID: 1, Limit: 2})
+		require.Len(t, sessions, 2)
+		assert.Equal(t, int64(300), sessions[0].OpeningFloat)
+		assert.Equal(t, int64(200), sessions[1].OpeningFloat)
+
+		after := RegisterSessionCursor(sessions[1])
+		sessions = repo.Search(ctx, RegisterSessionQuery{ShopID: 1, After: &after})
+		require.Len(t, sessions, 1)
+		assert.Equal(t, int64(100), sessions[0].OpeningFloat)
+
+		sessions = repo.Search(ctx, RegisterSessionQuery{ShopID: 1, LocationIDs: []uint64{10}, Status: entities.RegisterSessionOpen})
+		require.Len(t, sessions, 1)
+		assert.Equal(t, int64(300), sessions[0].OpeningFloat)
+	})
+}
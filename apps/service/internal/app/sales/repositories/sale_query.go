@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// SaleSortRecent is the order sale lists come in: newest first.
+const SaleSortRecent = "recent"
+
+// SaleQuery filters and pages the sales of a shop. After, when set, resumes
+// the list past the sale it points to. LocationIDs, when not empty, limits
+// the list to those locations.
+type SaleQuery struct {
+	ShopID      uint64
+	LocationID  *uint64
+	LocationIDs []uint64
+	After       *pagination.Cursor
+	Limit       int
+}
+
+// SaleQueryScope applies the query to a statement selecting from the sales
+// table.
+func SaleQueryScope(query SaleQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("shop_id = ?", query.ShopID)
+		if query.LocationID != nil {
+			db = db.Where("location_id = ?", *query.LocationID)
+		}
+		if len(query.LocationIDs) > 0 {
+			db = db.Where("location_id IN ?", query.LocationIDs)
+		}
+		if query.After != nil {
+			db = db.Where("id < ?", query.After.ID)
+		}
+
+		db = db.Order("id DESC")
+		if query.Limit > 0 {
+			db = db.Limit(query.Limit)
+		}
+		return db
+	}
+}
+
+// SaleCursor returns the cursor resuming a sale list after sale.
+func SaleCursor(sale entities.Sale) pagination.Cursor {
+	return pagination.Cursor{Sort: SaleSortRecent, ID: sale.ID}
+}
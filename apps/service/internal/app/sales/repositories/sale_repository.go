@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+// SaleRepository loads sales with their lines and payments, ordered by ID.
+type SaleRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Sale, error)
+	Search(ctx context.Context, query SaleQuery) []entities.Sale
+	// Create stores the sale together with its Lines and Payments.
+	Create(ctx context.Context, sale entities.Sale) (entities.Sale, error)
+	// SessionTotals adds up the sales of a register session.
+	SessionTotals(ctx context.Context, sessionID uint64) (entities.SaleTotals, error)
+	// SessionPayments adds up the payments of the sales of a register
+	// session by method. Methods nobody paid with are left out.
+	SessionPayments(ctx context.Context, sessionID uint64) ([]entities.PaymentTotal, error)
+}
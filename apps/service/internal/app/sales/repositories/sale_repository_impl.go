@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+type saleRepository struct {
+	db *gorm.DB
+}
+
+func NewSaleRepository(db *gorm.DB) SaleRepository {
+	return &saleRepository{
+		db: db,
+	}
+}
+
+func (r *saleRepository) FindByID(ctx context.Context, id uint64) (entities.Sale, error) {
+	var sale entities.Sale
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Preload("Lines", orderByID).
+		Preload("Payments", orderByID).
+		First(&sale).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return sale, errors.New("sale not found")
+		}
+		return sale, err
+	}
+	return sale, nil
+}
+
+func (r *saleRepository) Search(ctx context.Context, query SaleQuery) []entities.Sale {
+	var sales []entities.Sale
+	r.db.WithContext(ctx).
+		Scopes(SaleQueryScope(query)).
+		Preload("Lines", orderByID).
+		Preload("Payments", orderByID).
+		Find(&sales)
+	return sales
+}
+
+func (r *saleRepository) Create(ctx context.Context, sale entities.Sale) (entities.Sale, error) {
+	err := r.db.WithContext(ctx).Create(&sale).Error
+	if err != nil {
+		return sale, err
+	}
+	return sale, nil
+}
+
+func (r *saleRepository) SessionTotals(ctx context.Context, sessionID uint64) (entities.SaleTotals, error) {
+	var totals entities.SaleTotals
+	err := r.db.WithContext(ctx).
+		Model(&entities.Sale{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(subtotal), 0) AS subtotal, COALESCE(SUM(discount_total), 0) AS discount_total, COALESCE(SUM(tax_total), 0) AS tax_total, COALESCE(SUM(total), 0) AS total, COALESCE(SUM(change), 0) AS change").
+		Where("register_session_id = ?", sessionID).
+		Scan(&totals).Error
+	if err != nil {
+		return totals, err
+	}
+	return totals, nil
+}
+
+func (r *saleRepository) SessionPayments(ctx context.Context, sessionID uint64) ([]entities.PaymentTotal, error) {
+	var payments []entities.PaymentTotal
+	err := r.db.WithContext(ctx).
+		Model(&entities.SalePayment{}).
+		Select("sale_payments.method AS method, COUNT(*) AS count, SUM(sale_payments.amount) AS amount").
+		Joins("JOIN sales ON sales.id = sale_payments.sale_id").
+		Where("sales.register_session_id = ?", sessionID).
+		Group("sale_payments.method").
+		Order("sale_payments.method").
+		Scan(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestSaleRepository(t *testing.T) {
+	t.Run("stores a sale with its lines and payments", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Sale{}, &entities.SaleLine{}, &entities.SalePayment{})
+		repo := NewSaleRepository(db)
+
+		sale, err := repo.Create(ctx, entities.Sale{
+			ShopID:        1,
+			LocationID:    10,
+			CartID:        5,
+			UserID:        1,
+			ReceiptNumber: 1,
+			Subtotal:      300,
+			Total:         300,
+			Paid:          500,
+			Change:        200,
+			Lines: []entities.SaleLine{
+				{VariantID: 100, SKU: "A", UnitPrice: 100, Quantity: 3, Total: 300},
+			},
+			Payments: []entities.SalePayment{
+				{Method: entities.PaymentCash, Amount: 500},
+			},
+		})
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(ctx, sale.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Lines, 1)
+		require.Len(t, found.Payments, 1)
+		assert.Equal(t, entities.PaymentCash, found.Payments[0].Method)
+
+		_, err = repo.FindByID(ctx, sale.ID+1)
+		assert.EqualError(t, err, "sale not found")
+	})
+
+	t.Run("searches the sales of a shop newest first", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Sale{}, &entities.SaleLine{}, &entities.SalePayment{})
+		repo := NewSaleRepository(db)
+
+		for i, sale := range []entities.Sale{
+			{ShopID: 1, LocationID: 10, ReceiptNumber: 1, Total: 100},
+			{ShopID: 1, LocationID: 11, ReceiptNumber: 1, Total: 200},
+			{ShopID: 1, LocationID: 10, ReceiptNumber: 2, Total: 300},
+			{ShopID: 2, LocationID: 20, ReceiptNumber: 1, Total: 400},
+		} {
+			sale.CartID = uint64(i + 1)
+			_, err := repo.Create(ctx, sale)
+			require.NoError(t, err)
+		}
+
+		sales := repo.Search(ctx, SaleQuery{ShopID: 1, Limit: 2})
+		require.Len(t, sales, 2)
+		assert.Equal(t, int64(300), sales[0].Total)
+		assert.Equal(t, int64(200), sales[1].Total)
+
+		after := SaleCursor(sales[1])
+		sales = repo.Search(ctx, SaleQuery{ShopID: 1, After: &after})
+		require.Len(t, sales, 1)
+		assert.Equal(t, int64(100), sales[0].Total)
+
+		sales = repo.Search(ctx, SaleQuery{ShopID: 1, LocationIDs: []uint64{11}})
+		require.Len(t, sales, 1)
+		assert.Equal(t, int64(200), sales[0].Total)
+	})
+
+	t.Run("adds up the sales of a register session", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Sale{}, &entities.SaleLine{}, &entities.SalePayment{})
+		repo := NewSaleRepository(db)
+
+		session := uint64(7)
+		other := uint64(8)
+		for i, sale := range []entities.Sale{
+			{RegisterSessionID: &session, ReceiptNumber: 1, Subtotal: 1000, DiscountTotal: 100, TaxTotal: 90, Total: 990, Change: 10, Payments: []entities.SalePayment{
+				{Method: entities.PaymentCash, Amount: 1000},
+			}},
+			{RegisterSessionID: &session, ReceiptNumber: 2, Subtotal: 500, Total: 500, Payments: []entities.SalePayment{
+				{Method: entities.PaymentCard, Amount: 300},
+				{Method: entities.PaymentCash, Amount: 200},
+			}},
+			{RegisterSessionID: &other, ReceiptNumber: 3, Subtotal: 400, Total: 400, Payments: []entities.SalePayment{
+				{Method: entities.PaymentTransfer, Amount: 400},
+			}},
+			{ReceiptNumber: 4, Subtotal: 900, Total: 900, Payments: []entities.SalePayment{
+				{Method: entities.PaymentCash, Amount: 900},
+			}},
+		} {
+			sale.ShopID = 1
+			sale.LocationID = 10
+			sale.CartID = uint64(i + 1)
+			_, err := repo.Create(ctx, sale)
+			require.NoError(t, err)
+		}
+
+		totals, err := repo.SessionTotals(ctx, session)
+		require.NoError(t, err)
+		assert.Equal(t, entities.SaleTotals{Count: 2, Subtotal: 1500, DiscountTotal: 100, TaxTotal: 90, Total: 1490, Change: 10}, totals)
+
+		payments, err := repo.SessionPayments(ctx, session)
+		require.NoError(t, err)
+		assert.Equal(t, []entities.PaymentTotal{
+			{Method: entities.PaymentCard, Count: 1, Amount: 300},
+			{Method: entities.PaymentCash, Count: 2, Amount: 1200},
+		}, payments)
+
+		totals, err = repo.SessionTotals(ctx, 99)
+		require.NoError(t, err)
+		assert.Equal(t, entities.SaleTotals{}, totals)
+	})
+}
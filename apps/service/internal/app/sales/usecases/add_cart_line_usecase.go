@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	catalogentities "github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	catalogrepositories "github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type AddCartLineUsecase struct {
+	cartRepository    repositories.CartRepository
+	productRepository catalogrepositories.ProductRepository
+	validator         *validator.Validate
+}
+
+func NewAddCartLineUsecase(cartRepository repositories.CartRepository, productRepository catalogrepositories.ProductRepository) *AddCartLineUsecase {
+	return &AddCartLineUsecase{
+		cartRepository:    cartRepository,
+		productRepository: productRepository,
+		validator:         validator.New(),
+	}
+}
+
+// AddCartLineParam names the variant by SKU or by barcode, not both.
+type AddCartLineParam struct {
+	ShopID             uint64 `validate:"required"`
+	CartID             uint64 `validate:"required"`
+	SKU                string `validate:"required_without=Barcode,excluded_with=Barcode,max=64"`
+	Barcode            string `validate:"max=64"`
+	Quantity           int64  `validate:"required,min=1"`
+	DiscountAmount     int64  `validate:"min=0"`
+	AllowedLocationIDs []uint64
+}
+
+type AddCartLineResult struct {
+	Cart *entities.Cart
+}
+
+// Execute adds a variant of the shop to an open cart at its current price.
+// Adding a variant the cart already has adds to that line instead, along
+// with the discount.
+func (u *AddCartLineUsecase) Execute(ctx context.Context, param AddCartLineParam) (*AddCartLineResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	cart, err := findOpenCart(ctx, u.cartRepository, param.ShopID, param.CartID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var variant catalogentities.ProductVariant
+	if sku := strings.TrimSpace(param.SKU); sku != "" {
+		variant, err = u.productRepository.FindVariantBySKU(ctx, param.ShopID, sku)
+	} else {
+		variant, err = u.productRepository.FindVariantByBarcode(ctx, param.ShopID, strings.TrimSpace(param.Barcode))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, line := range cart.Lines {
+		if line.VariantID == variant.ID {
+			index = i
+			break
+		}
+	}
+
+	if index >= 0 {
+		cart.Lines[index].Quantity += param.Quantity
+		cart.Lines[index].DiscountAmount += param.DiscountAmount
+	} else {
+		product, err := u.productRepository.FindByID(ctx, variant.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+		cart.Lines = append(cart.Lines, entities.CartLine{
+			CartID:         cart.ID,
+			VariantID:      variant.ID,
+			SKU:            variant.SKU,
+			Name:           variantName(product, variant),
+			UnitPrice:      variant.Price,
+			Quantity:       param.Quantity,
+			DiscountAmount: param.DiscountAmount,
+		})
+		index = len(cart.Lines) - 1
+	}
+	if err := ensureDiscounts(cart); err != nil {
+		return nil, err
+	}
+
+	var line entities.CartLine
+	if cart.Lines[index].ID != 0 {
+		line, err = u.cartRepository.UpdateLine(ctx, cart.Lines[index])
+	} else {
+		line, err = u.cartRepository.CreateLine(ctx, cart.Lines[index])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save cart line: %w", err)
+	}
+	cart.Lines[index] = line
+
+	return &AddCartLineResult{
+		Cart: &cart,
+	}, nil
+}
+
+// variantName is how a variant reads on a receipt: the name of its product,
+// followed by its option values in the order of the product's options.
+func variantName(product catalogentities.Product, variant catalogentities.ProductVariant) string {
+	var values []string
+	for _, option := range product.Options {
+		if value, ok := variant.Options[option.Name]; ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return product.Name
+	}
+	return fmt.Sprintf("%s (%s)", product.Name, strings.Join(values, " / "))
+}
+
+// findCartLine returns the index of a line of the cart.
+func findCartLine(cart entities.Cart, lineID uint64) (int, error) {
+	for i, line := range cart.Lines {
+		if line.ID == lineID {
+			return i, nil
+		}
+	}
+	return -1, errors.New("cart line not found")
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogrepositories "github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+func TestAddCartLineUsecase_Execute(t *testing.T) {
+	t.Run("adds variants by SKU or barcode at their current price", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		usecase := NewAddCartLineUsecase(repositories.NewCartRepository(fixture.db), catalogrepositories.NewProductRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, AddCartLineParam{ShopID: 1, CartID: cart.ID, Barcode: "4006381333931", Quantity: 2})
+		require.NoError(t, err)
+		require.Len(t, result.Cart.Lines, 1)
+		line := result.Cart.Lines[0]
+		assert.Equal(t, "TS-S", line.SKU)
+		assert.Equal(t, "T-Shirt (S)", line.Name)
+		assert.Equal(t, int64(1500), line.UnitPrice)
+
+		result, err = usecase.Execute(ctx, AddCartLineParam{ShopID: 1, CartID: cart.ID, SKU: "TS-M", Quantity: 1, DiscountAmount: 200})
+		require.NoError(t, err)
+		require.Len(t, result.Cart.Lines, 2)
+		assert.Equal(t, int64(1500), result.Cart.Lines[1].Total())
+		assert.Equal(t, int64(4500), result.Cart.Totals().Total)
+	})
+
+	t.Run("adds to the line the variant already has", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 1)
+
+		result, err := NewAddCartLineUsecase(repositories.NewCartRepository(fixture.db), catalogrepositories.NewProductRepository(fixture.db)).Execute(ctx, AddCartLineParam{
+			ShopID:   1,
+			CartID:   cart.ID,
+			SKU:      "TS-S",
+			Quantity: 2,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Cart.Lines, 1)
+		assert.Equal(t, int64(3), result.Cart.Lines[0].Quantity)
+	})
+
+	t.Run("rejects variants of other shops and unknown codes", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		usecase := NewAddCartLineUsecase(repositories.NewCartRepository(fixture.db), catalogrepositories.NewProductRepository(fixture.db))
+
+		_, err := usecase.Execute(ctx, AddCartLineParam{ShopID: 1, CartID: cart.ID, SKU: fixture.otherVariant.SKU, Quantity: 1})
+		assert.EqualError(t, err, "variant not found")
+
+		_, err = usecase.Execute(ctx, AddCartLineParam{ShopID: 1, CartID: cart.ID, Barcode: "0000000000000", Quantity: 1})
+		assert.EqualError(t, err, "variant not found")
+	})
+
+	t.Run("takes either a SKU or a barcode", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		usecase := NewAddCartLineUsecase(repositories.NewCartRepository(fixture.db), catalogrepositories.NewProductRepository(fixture.db))
+
+		_, err := usecase.Execute(ctx, AddCartLineParam{ShopID: 1, CartID: cart.ID, Quantity: 1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = usecase.Execute(ctx, AddCartLineParam{ShopID: 1, CartID: cart.ID, SKU: "TS-S", Barcode: "4006381333931", Quantity: 1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("rejects discounts over the line amount", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+
+		_, err := NewAddCartLineUsecase(repositories.NewCartRepository(fixture.db), catalogrepositories.NewProductRepository(fixture.db)).Execute(ctx, AddCartLineParam{
+			ShopID:         1,
+			CartID:         cart.ID,
+			SKU:            "TS-S",
+			Quantity:       1,
+			DiscountAmount: 1501,
+		})
+		assert.EqualError(t, err, "validation failed: discount of TS-S exceeds its amount")
+	})
+}
@@ -0,0 +1,200 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	inventoryentities "github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	inventoryrepositories "github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	inventoryservices "github.com/reno1r/weiss/apps/service/internal/app/inventory/services"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CheckoutCartUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewCheckoutCartUsecase(db *gorm.DB) *CheckoutCartUsecase {
+	return &CheckoutCartUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type CheckoutCartParam struct {
+	ShopID             uint64         `validate:"required"`
+	UserID             uint64         `validate:"required"`
+	CartID             uint64         `validate:"required"`
+	Payments           []PaymentParam `validate:"max=10,dive"`
+	AllowedLocationIDs []uint64
+}
+
+type PaymentParam struct {
+	Method    entities.PaymentMethod `validate:"required,oneof=cash card transfer"`
+	Amount    int64                  `validate:"required,min=1"`
+	Reference string                 `validate:"max=100"`
+}
+
+type CheckoutCartResult struct {
+	Sale *entities.Sale
+}
+
+// Execute turns an open cart into a sale. The payments must cover the total
+// of the cart; cash may go over it and the difference is given back as
+// change. The sale takes the next receipt number of the cart's location and
+// its lines are taken out of stock there, all in one transaction: if the
+// shop does not allow negative stock and a line is short, nothing is sold.
+func (u *CheckoutCartUsecase) Execute(ctx context.Context, param CheckoutCartParam) (*CheckoutCartResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	var result *CheckoutCartResult
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCartRepo := repositories.NewCartRepository(tx)
+
+		cart, err := findOpenCart(ctx, txCartRepo, param.ShopID, param.CartID, param.AllowedLocationIDs)
+		if err != nil {
+			return err
+		}
+		if len(cart.Lines) == 0 {
+			return errors.New("validation failed: cart is empty")
+		}
+		if err := ensureDiscounts(cart); err != nil {
+			return err
+		}
+
+		totals := cart.Totals()
+		paid, change, err := settle(totals.Total, param.Payments)
+		if err != nil {
+			return err
+		}
+
+		// Claiming the cart first makes a concurrent checkout of the same
+		// cart fail here rather than sell it twice.
+		if err := txCartRepo.CheckOut(ctx, cart.ID); err != nil {
+			return err
+		}
+
+		receiptNumber, err := repositories.NewReceiptSequenceRepository(tx).Next(ctx, cart.ShopID, cart.LocationID)
+		if err != nil {
+			return fmt.Errorf("failed to number receipt: %w", err)
+		}
+
+		// The sale belongs to the register session open at the location, if
+		// any. Looking it up keeps the session from closing before the sale
+		// is recorded.
+		var sessionID *uint64
+		session, err := repositories.NewRegisterSessionRepository(tx).FindOpenByLocationID(ctx, cart.LocationID)
+		if err != nil && err.Error() != "register session not found" {
+			return fmt.Errorf("failed to get register session: %w", err)
+		}
+		if err == nil {
+			sessionID = &session.ID
+		}
+
+		sale := entities.Sale{
+			ShopID:            cart.ShopID,
+			LocationID:        cart.LocationID,
+			CartID:            cart.ID,
+			UserID:            param.UserID,
+			RegisterSessionID: sessionID,
+			ReceiptNumber:     receiptNumber,
+			Subtotal:          totals.Subtotal,
+			DiscountTotal:     totals.Discount,
+			TaxRate:           cart.TaxRate,
+			TaxTotal:          totals.Tax,
+			Total:             totals.Total,
+			Paid:              paid,
+			Change:            change,
+		}
+		for _, line := range cart.Lines {
+			sale.Lines = append(sale.Lines, entities.SaleLine{
+				VariantID:      line.VariantID,
+				SKU:            line.SKU,
+				Name:           line.Name,
+				UnitPrice:      line.UnitPrice,
+				Quantity:       line.Quantity,
+				DiscountAmount: line.DiscountAmount,
+				Total:          line.Total(),
+			})
+		}
+		for _, payment := range param.Payments {
+			sale.Payments = append(sale.Payments, entities.SalePayment{
+				Method:    payment.Method,
+				Amount:    payment.Amount,
+				Reference: strings.TrimSpace(payment.Reference),
+			})
+		}
+
+		created, err := repositories.NewSaleRepository(tx).Create(ctx, sale)
+		if err != nil {
+			return fmt.Errorf("failed to create sale: %w", err)
+		}
+
+		movements := make([]inventoryentities.StockMovement, len(cart.Lines))
+		for i, line := range cart.Lines {
+			movements[i] = inventoryentities.StockMovement{
+				LocationID: cart.LocationID,
+				VariantID:  line.VariantID,
+				Type:       inventoryentities.MovementSale,
+				Quantity:   -line.Quantity,
+				Reference:  fmt.Sprintf("sale %d", created.ID),
+				UserID:     param.UserID,
+			}
+		}
+		ledger := inventoryservices.NewLedger(
+			inventoryrepositories.NewStockLevelRepository(tx),
+			inventoryrepositories.NewStockMovementRepository(tx),
+			inventoryrepositories.NewSettingsRepository(tx),
+		)
+		if _, err := ledger.Post(ctx, cart.ShopID, movements); err != nil {
+			return err
+		}
+
+		result = &CheckoutCartResult{
+			Sale: &created,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// settle checks that the payments cover total and returns what was paid and
+// the change due. Only cash can be paid over the total, and the change never
+// exceeds the cash handed over.
+func settle(total int64, payments []PaymentParam) (int64, int64, error) {
+	var paid, cash int64
+	for _, payment := range payments {
+		paid += payment.Amount
+		if payment.Method == entities.PaymentCash {
+			cash += payment.Amount
+		}
+	}
+
+	if paid < total {
+		return 0, 0, fmt.Errorf("validation failed: payments of %d do not cover the total of %d", paid, total)
+	}
+	change := paid - total
+	if change > cash {
+		return 0, 0, errors.New("validation failed: only cash can be paid over the total")
+	}
+	return paid, change, nil
+}
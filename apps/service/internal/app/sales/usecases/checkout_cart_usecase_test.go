@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inventoryentities "github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	inventoryrepositories "github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+func TestCheckoutCartUsecase_Execute(t *testing.T) {
+	t.Run("sells the cart and takes it out of stock", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		stock(t, fixture, fixture.main, fixture.small, 5)
+		stock(t, fixture, fixture.main, fixture.medium, 5)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 2)
+		addLine(t, fixture, cart, "TS-M", 1)
+		_, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:  1,
+			CartID:  cart.ID,
+			TaxRate: 1000,
+		})
+		require.NoError(t, err)
+
+		result, err := NewCheckoutCartUsecase(fixture.db).Execute(ctx, CheckoutCartParam{
+			ShopID: 1,
+			UserID: 2,
+			CartID: cart.ID,
+			Payments: []PaymentParam{
+				{Method: entities.PaymentCard, Amount: 3000, Reference: "AUTH-1"},
+				{Method: entities.PaymentCash, Amount: 2500},
+			},
+		})
+		require.NoError(t, err)
+		sale := result.Sale
+		assert.Equal(t, int64(1), sale.ReceiptNumber)
+		assert.Equal(t, uint64(2), sale.UserID)
+		assert.Equal(t, int64(4700), sale.Subtotal)
+		assert.Equal(t, int64(470), sale.TaxTotal)
+		assert.Equal(t, int64(5170), sale.Total)
+		assert.Equal(t, int64(5500), sale.Paid)
+		assert.Equal(t, int64(330), sale.Change)
+		assert.Len(t, sale.Lines, 2)
+		assert.Len(t, sale.Payments, 2)
+
+		assert.Equal(t, int64(3), onHand(t, fixture, fixture.main, fixture.small))
+		assert.Equal(t, int64(4), onHand(t, fixture, fixture.main, fixture.medium))
+		movements := inventoryrepositories.NewStockMovementRepository(fixture.db).Search(ctx, inventoryrepositories.StockMovementQuery{ShopID: 1})
+		require.Len(t, movements, 2)
+		assert.Equal(t, inventoryentities.MovementSale, movements[0].Type)
+		assert.Equal(t, fmt.Sprintf("sale %d", sale.ID), movements[0].Reference)
+
+		found, err := repositories.NewCartRepository(fixture.db).FindByID(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.CartStatusCheckedOut, found.Status)
+	})
+
+	t.Run("numbers receipts per location", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		require.NoError(t, fixture.db.Create(&inventoryentities.Settings{ShopID: 1, AllowNegativeStock: true}).Error)
+
+		checkout := func(location shopentities.Location) int64 {
+			cart := addLine(t, fixture, openCart(t, fixture, location), "TS-S", 1)
+			result, err := NewCheckoutCartUsecase(fixture.db).Execute(ctx, CheckoutCartParam{
+				ShopID:   1,
+				UserID:   1,
+				CartID:   cart.ID,
+				Payments: []PaymentParam{{Method: entities.PaymentCash, Amount: 1500}},
+			})
+			require.NoError(t, err)
+			return result.Sale.ReceiptNumber
+		}
+
+		assert.Equal(t, int64(1), checkout(fixture.main))
+		assert.Equal(t, int64(2), checkout(fixture.main))
+		assert.Equal(t, int64(1), checkout(fixture.airport))
+		assert.Equal(t, int64(3), checkout(fixture.main))
+	})
+
+	t.Run("sells nothing when a line lacks stock", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		stock(t, fixture, fixture.main, fixture.small, 5)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 1)
+		addLine(t, fixture, cart, "TS-M", 1)
+
+		_, err := NewCheckoutCartUsecase(fixture.db).Execute(ctx, CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: entities.PaymentCash, Amount: 3200}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient stock")
+
+		assert.Equal(t, int64(5), onHand(t, fixture, fixture.main, fixture.small))
+		found, err := repositories.NewCartRepository(fixture.db).FindByID(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.True(t, found.IsOpen())
+		var sales int64
+		require.NoError(t, fixture.db.Model(&entities.Sale{}).Count(&sales).Error)
+		assert.Zero(t, sales)
+	})
+
+	t.Run("checks the payments against the total", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		stock(t, fixture, fixture.main, fixture.small, 5)
+		cart := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 1)
+		usecase := NewCheckoutCartUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: entities.PaymentCash, Amount: 1000}},
+		})
+		assert.EqualError(t, err, "validation failed: payments of 1000 do not cover the total of 1500")
+
+		_, err = usecase.Execute(ctx, CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: entities.PaymentCard, Amount: 1000}, {Method: entities.PaymentTransfer, Amount: 600}},
+		})
+		assert.EqualError(t, err, "validation failed: only cash can be paid over the total")
+
+		_, err = usecase.Execute(ctx, CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: "voucher", Amount: 1500}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("rejects empty and checked out carts", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		stock(t, fixture, fixture.main, fixture.small, 5)
+		usecase := NewCheckoutCartUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, CheckoutCartParam{ShopID: 1, UserID: 1, CartID: openCart(t, fixture, fixture.main).ID})
+		assert.EqualError(t, err, "validation failed: cart is empty")
+
+		cart := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 1)
+		param := CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: entities.PaymentCash, Amount: 1500}},
+		}
+		_, err = usecase.Execute(ctx, param)
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, param)
+		assert.EqualError(t, err, "cart is already checked out")
+		assert.Equal(t, int64(4), onHand(t, fixture, fixture.main, fixture.small))
+	})
+}
@@ -0,0 +1,148 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CloseRegisterSessionUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewCloseRegisterSessionUsecase(db *gorm.DB) *CloseRegisterSessionUsecase {
+	return &CloseRegisterSessionUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type CloseRegisterSessionParam struct {
+	ShopID             uint64 `validate:"required"`
+	StaffID            uint64 `validate:"required"`
+	SessionID          uint64 `validate:"required"`
+	CountedCash        int64  `validate:"min=0"`
+	Note               string `validate:"max=1000"`
+	AllowedLocationIDs []uint64
+}
+
+type CloseRegisterSessionResult struct {
+	Session *entities.RegisterSession
+	Report  *entities.RegisterReport
+}
+
+// Execute closes an open register session with the cash counted in the
+// drawer, and records what the drawer should have held and the variance
+// between the two.
+func (u *CloseRegisterSessionUsecase) Execute(ctx context.Context, param CloseRegisterSessionParam) (*CloseRegisterSessionResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	var result *CloseRegisterSessionResult
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txSessionRepo := repositories.NewRegisterSessionRepository(tx)
+
+		session, err := findShopSession(ctx, txSessionRepo, param.ShopID, param.SessionID, param.AllowedLocationIDs)
+		if err != nil {
+			return err
+		}
+		if !session.IsOpen() {
+			return errors.New("register session is already closed")
+		}
+
+		closedAt := time.Now()
+		session.ClosedByStaffID = &param.StaffID
+		session.ClosedAt = &closedAt
+		session.CountedCash = param.CountedCash
+		session.Note = strings.TrimSpace(param.Note)
+
+		// Closing first waits out checkouts still recording sales against
+		// the session, and keeps later ones off it, so the report below
+		// sees every sale of the session.
+		if err := txSessionRepo.Close(ctx, session); err != nil {
+			return err
+		}
+
+		session, err = txSessionRepo.FindByID(ctx, session.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reload register session: %w", err)
+		}
+
+		report, err := buildRegisterReport(ctx, repositories.NewSaleRepository(tx), session)
+		if err != nil {
+			return err
+		}
+
+		session.ExpectedCash = report.ExpectedCash
+		session.Variance = session.CountedCash - report.ExpectedCash
+		session, err = txSessionRepo.Update(ctx, session)
+		if err != nil {
+			return fmt.Errorf("failed to close register session: %w", err)
+		}
+
+		result = &CloseRegisterSessionResult{
+			Session: &session,
+			Report:  &report,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// buildRegisterReport sums up the sales and cash of a register session. The
+// drawer should hold the opening float, plus the cash put in and taken for
+// sales, less the cash taken out and the change given.
+func buildRegisterReport(ctx context.Context, saleRepository repositories.SaleRepository, session entities.RegisterSession) (entities.RegisterReport, error) {
+	totals, err := saleRepository.SessionTotals(ctx, session.ID)
+	if err != nil {
+		return entities.RegisterReport{}, fmt.Errorf("failed to total sales: %w", err)
+	}
+	paid, err := saleRepository.SessionPayments(ctx, session.ID)
+	if err != nil {
+		return entities.RegisterReport{}, fmt.Errorf("failed to total payments: %w", err)
+	}
+
+	// Every method is listed, in the same order, whether or not it was used.
+	payments := make([]entities.PaymentTotal, len(entities.PaymentMethods))
+	var cash int64
+	for i, method := range entities.PaymentMethods {
+		payments[i] = entities.PaymentTotal{Method: method}
+		for _, total := range paid {
+			if total.Method == method {
+				payments[i] = total
+			}
+		}
+		if method == entities.PaymentCash {
+			cash = payments[i].Amount
+		}
+	}
+
+	cashIn, cashOut := session.CashTotals()
+	return entities.RegisterReport{
+		Sales:        totals,
+		Payments:     payments,
+		CashIn:       cashIn,
+		CashOut:      cashOut,
+		ExpectedCash: session.OpeningFloat + cashIn - cashOut + cash - totals.Change,
+	}, nil
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+// sell checks out a cart of quantity of the variant with sku at location,
+// paid with payments.
+func sell(t *testing.T, fixture *salesFixture, location shopentities.Location, sku string, quantity int64, payments ...PaymentParam) entities.Sale {
+	cart := openCart(t, fixture, location)
+	addLine(t, fixture, cart, sku, quantity)
+	result, err := NewCheckoutCartUsecase(fixture.db).Execute(context.Background(), CheckoutCartParam{
+		ShopID:   1,
+		UserID:   1,
+		CartID:   cart.ID,
+		Payments: payments,
+	})
+	require.NoError(t, err)
+	return *result.Sale
+}
+
+func TestCloseRegisterSessionUsecase_Execute(t *testing.T) {
+	t.Run("reconciles the drawer with the sales of the session", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		stock(t, fixture, fixture.main, fixture.small, 10)
+		stock(t, fixture, fixture.airport, fixture.small, 10)
+
+		// Sales made before the session opened, or at another register,
+		// are not part of it.
+		before := sell(t, fixture, fixture.main, "TS-S", 1, PaymentParam{Method: entities.PaymentCash, Amount: 1500})
+		assert.Nil(t, before.RegisterSessionID)
+
+		session := openSession(t, fixture, fixture.main, 10000)
+		sale := sell(t, fixture, fixture.main, "TS-S", 1, PaymentParam{Method: entities.PaymentCash, Amount: 2000})
+		require.NotNil(t, sale.RegisterSessionID)
+		assert.Equal(t, session.ID, *sale.RegisterSessionID)
+		sell(t, fixture, fixture.main, "TS-S", 2, PaymentParam{Method: entities.PaymentCard, Amount: 3000})
+		sell(t, fixture, fixture.main, "TS-S", 1,
+			PaymentParam{Method: entities.PaymentTransfer, Amount: 1000},
+			PaymentParam{Method: entities.PaymentCash, Amount: 500},
+		)
+		sell(t, fixture, fixture.airport, "TS-S", 1, PaymentParam{Method: entities.PaymentCash, Amount: 1500})
+
+		_, err := NewRecordCashMovementUsecase(fixture.db).Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 1, SessionID: session.ID, Type: entities.CashOut, Amount: 700, Reason: "Window cleaner"})
+		require.NoError(t, err)
+
+		result, err := NewCloseRegisterSessionUsecase(fixture.db).Execute(ctx, CloseRegisterSessionParam{
+			ShopID:      1,
+			StaffID:     4,
+			SessionID:   session.ID,
+			CountedCash: 11000,
+			Note:        "Short by a coin roll",
+		})
+		require.NoError(t, err)
+
+		// 10000 float + 2500 cash taken - 500 change - 700 paid out.
+		closed := result.Session
+		assert.False(t, closed.IsOpen())
+		require.NotNil(t, closed.ClosedByStaffID)
+		assert.Equal(t, uint64(4), *closed.ClosedByStaffID)
+		assert.NotNil(t, closed.ClosedAt)
+		assert.Equal(t, int64(11300), closed.ExpectedCash)
+		assert.Equal(t, int64(11000), closed.CountedCash)
+		assert.Equal(t, int64(-300), closed.Variance)
+		assert.Equal(t, "Short by a coin roll", closed.Note)
+
+		report := result.Report
+		assert.Equal(t, entities.SaleTotals{Count: 3, Subtotal: 6000, Total: 6000, Change: 500}, report.Sales)
+		assert.Equal(t, []entities.PaymentTotal{
+			{Method: entities.PaymentCash, Count: 2, Amount: 2500},
+			{Method: entities.PaymentCard, Count: 1, Amount: 3000},
+			{Method: entities.PaymentTransfer, Count: 1, Amount: 1000},
+		}, report.Payments)
+		assert.Equal(t, int64(700), report.CashOut)
+
+		// Later sales are left out of the closed session.
+		after := sell(t, fixture, fixture.main, "TS-S", 1, PaymentParam{Method: entities.PaymentCash, Amount: 1500})
+		assert.Nil(t, after.RegisterSessionID)
+	})
+
+	t.Run("closes a session once", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		session := openSession(t, fixture, fixture.main, 0)
+		usecase := NewCloseRegisterSessionUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, CloseRegisterSessionParam{ShopID: 1, StaffID: 1, SessionID: session.ID})
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, CloseRegisterSessionParam{ShopID: 1, StaffID: 1, SessionID: session.ID})
+		assert.EqualError(t, err, "register session is already closed")
+
+		// The register can be opened again for the next shift.
+		openSession(t, fixture, fixture.main, 0)
+	})
+
+	t.Run("keeps staff to the locations they work at", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		session := openSession(t, fixture, fixture.airport, 0)
+
+		_, err := NewCloseRegisterSessionUsecase(fixture.db).Execute(ctx, CloseRegisterSessionParam{
+			ShopID:             1,
+			StaffID:            1,
+			SessionID:          session.ID,
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		assert.EqualError(t, err, fmt.Sprintf("forbidden: no access to location %d", fixture.airport.ID))
+	})
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CreateCartUsecase struct {
+	cartRepository     repositories.CartRepository
+	locationRepository shoprepositories.LocationRepository
+	validator          *validator.Validate
+}
+
+func NewCreateCartUsecase(cartRepository repositories.CartRepository, locationRepository shoprepositories.LocationRepository) *CreateCartUsecase {
+	return &CreateCartUsecase{
+		cartRepository:     cartRepository,
+		locationRepository: locationRepository,
+		validator:          validator.New(),
+	}
+}
+
+type CreateCartParam struct {
+	ShopID     uint64 `validate:"required"`
+	UserID     uint64 `validate:"required"`
+	LocationID uint64 `validate:"required"`
+	// AllowedLocationIDs are the locations the user works at, when limited
+	// to some; see accessentities.Staff.
+	AllowedLocationIDs []uint64
+}
+
+type CreateCartResult struct {
+	Cart *entities.Cart
+}
+
+// Execute opens an empty cart at an outlet of the shop.
+func (u *CreateCartUsecase) Execute(ctx context.Context, param CreateCartParam) (*CreateCartResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if err := ensureLocationAllowed(param.AllowedLocationIDs, param.LocationID); err != nil {
+		return nil, err
+	}
+
+	location, err := findOutlet(ctx, u.locationRepository, param.ShopID, param.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := u.cartRepository.Create(ctx, entities.Cart{
+		ShopID:     param.ShopID,
+		LocationID: location.ID,
+		UserID:     param.UserID,
+		Status:     entities.CartStatusOpen,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cart: %w", err)
+	}
+
+	return &CreateCartResult{
+		Cart: &cart,
+	}, nil
+}
+
+// ensureLocationAllowed checks that a user limited to the allowed locations
+// works at the location. No allowed locations means every location.
+func ensureLocationAllowed(allowed []uint64, locationID uint64) error {
+	if len(allowed) == 0 || slices.Contains(allowed, locationID) {
+		return nil
+	}
+	return fmt.Errorf("forbidden: no access to location %d", locationID)
+}
+
+// findOutlet loads an outlet of the shop, where sales are made.
+func findOutlet(ctx context.Context, locationRepository shoprepositories.LocationRepository, shopID, locationID uint64) (shopentities.Location, error) {
+	location, err := locationRepository.FindByID(ctx, locationID)
+	if err != nil && err.Error() != "location not found" {
+		return location, fmt.Errorf("failed to get location: %w", err)
+	}
+	if err != nil || location.ShopID != shopID {
+		return shopentities.Location{}, errors.New("validation failed: location not found")
+	}
+	if location.IsWarehouse() {
+		return shopentities.Location{}, errors.New("validation failed: sales are only made at outlets")
+	}
+	return location, nil
+}
+
+// findShopCart loads a cart with its lines, treating carts of other shops as
+// missing. Carts at locations the user does not work at are forbidden.
+func findShopCart(ctx context.Context, cartRepository repositories.CartRepository, shopID, cartID uint64, allowedLocationIDs []uint64) (entities.Cart, error) {
+	cart, err := cartRepository.FindByID(ctx, cartID)
+	if err != nil {
+		return cart, err
+	}
+	if cart.ShopID != shopID {
+		return entities.Cart{}, errors.New("cart not found")
+	}
+	if err := ensureLocationAllowed(allowedLocationIDs, cart.LocationID); err != nil {
+		return entities.Cart{}, err
+	}
+	return cart, nil
+}
+
+// findOpenCart is findShopCart for carts about to change, which must not be
+// checked out yet.
+func findOpenCart(ctx context.Context, cartRepository repositories.CartRepository, shopID, cartID uint64, allowedLocationIDs []uint64) (entities.Cart, error) {
+	cart, err := findShopCart(ctx, cartRepository, shopID, cartID, allowedLocationIDs)
+	if err != nil {
+		return cart, err
+	}
+	if !cart.IsOpen() {
+		return entities.Cart{}, errors.New("cart is already checked out")
+	}
+	return cart, nil
+}
+
+// ensureDiscounts checks that no discount takes a line, or the cart as a
+// whole, below zero.
+func ensureDiscounts(cart entities.Cart) error {
+	for _, line := range cart.Lines {
+		if line.DiscountAmount > line.Amount() {
+			return fmt.Errorf("validation failed: discount of %s exceeds its amount", line.SKU)
+		}
+	}
+	totals := cart.Totals()
+	if totals.Discount > totals.Subtotal {
+		return errors.New("validation failed: discount exceeds the cart amount")
+	}
+	return nil
+}
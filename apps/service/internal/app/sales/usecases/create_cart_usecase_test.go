@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	catalogentities "github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	catalogrepositories "github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	inventoryentities "github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	inventoryrepositories "github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+// salesFixture is shop 1 with two outlets and a warehouse, selling a shirt
+// in two sizes, next to shop 2 with an outlet and a variant of its own.
+type salesFixture struct {
+	db           *gorm.DB
+	main         shopentities.Location
+	airport      shopentities.Location
+	warehouse    shopentities.Location
+	otherShop    shopentities.Location
+	small        catalogentities.ProductVariant
+	medium       catalogentities.ProductVariant
+	otherVariant catalogentities.ProductVariant
+}
+
+func setupSalesTest(t *testing.T) *salesFixture {
+	db := testutil.SetupTestDB(t,
+		&shopentities.Location{},
+		&catalogentities.Product{},
+		&catalogentities.ProductVariant{},
+		&inventoryentities.StockLevel{},
+		&inventoryentities.StockMovement{},
+		&inventoryentities.Settings{},
+		&entities.Cart{},
+		&entities.CartLine{},
+		&entities.Sale{},
+		&entities.SaleLine{},
+		&entities.SalePayment{},
+		&entities.ReceiptSequence{},
+		&entities.RegisterSession{},
+		&entities.RegisterCashMovement{},
+	)
+
+	fixture := &salesFixture{
+		db:        db,
+		main:      shopentities.Location{ShopID: 1, Name: "Main", Kind: shopentities.LocationKindOutlet},
+		airport:   shopentities.Location{ShopID: 1, Name: "Airport", Kind: shopentities.LocationKindOutlet},
+		warehouse: shopentities.Location{ShopID: 1, Name: "Warehouse", Kind: shopentities.LocationKindWarehouse},
+		otherShop: shopentities.Location{ShopID: 2, Name: "Main", Kind: shopentities.LocationKindOutlet},
+	}
+	for _, location := range []*shopentities.Location{&fixture.main, &fixture.airport, &fixture.warehouse, &fixture.otherShop} {
+		require.NoError(t, db.Create(location).Error)
+	}
+
+	shirt := catalogentities.Product{
+		ShopID:  1,
+		Name:    "T-Shirt",
+		Options: []catalogentities.ProductOption{{Name: "Size", Values: []string{"S", "M"}}},
+		Variants: []catalogentities.ProductVariant{
+			{ShopID: 1, SKU: "TS-S", Barcode: "4006381333931", Price: 1500, Options: map[string]string{"Size": "S"}},
+			{ShopID: 1, SKU: "TS-M", Price: 1700, Options: map[string]string{"Size": "M"}},
+		},
+	}
+	require.NoError(t, db.Create(&shirt).Error)
+	fixture.small, fixture.medium = shirt.Variants[0], shirt.Variants[1]
+
+	tea := catalogentities.Product{
+		ShopID:   2,
+		Name:     "Tea",
+		Variants: []catalogentities.ProductVariant{{ShopID: 2, SKU: "TEA", Price: 250}},
+	}
+	require.NoError(t, db.Create(&tea).Error)
+	fixture.otherVariant = tea.Variants[0]
+
+	return fixture
+}
+
+// openCart opens a cart at location.
+func openCart(t *testing.T, fixture *salesFixture, location shopentities.Location) entities.Cart {
+	result, err := NewCreateCartUsecase(repositories.NewCartRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db)).Execute(context.Background(), CreateCartParam{
+		ShopID:     1,
+		UserID:     1,
+		LocationID: location.ID,
+	})
+	require.NoError(t, err)
+	return *result.Cart
+}
+
+// addLine adds quantity of the variant with sku to the cart.
+func addLine(t *testing.T, fixture *salesFixture, cart entities.Cart, sku string, quantity int64) entities.Cart {
+	result, err := NewAddCartLineUsecase(repositories.NewCartRepository(fixture.db), catalogrepositories.NewProductRepository(fixture.db)).Execute(context.Background(), AddCartLineParam{
+		ShopID:   1,
+		CartID:   cart.ID,
+		SKU:      sku,
+		Quantity: quantity,
+	})
+	require.NoError(t, err)
+	return *result.Cart
+}
+
+// stock puts quantity of variant on hand at location.
+func stock(t *testing.T, fixture *salesFixture, location shopentities.Location, variant catalogentities.ProductVariant, quantity int64) {
+	_, err := inventoryrepositories.NewStockLevelRepository(fixture.db).Add(context.Background(), 1, location.ID, variant.ID, quantity)
+	require.NoError(t, err)
+}
+
+// onHand returns the quantity of variant on hand at location.
+func onHand(t *testing.T, fixture *salesFixture, location shopentities.Location, variant catalogentities.ProductVariant) int64 {
+	level, err := inventoryrepositories.NewStockLevelRepository(fixture.db).Find(context.Background(), location.ID, variant.ID)
+	require.NoError(t, err)
+	return level.OnHand
+}
+
+func TestCreateCartUsecase_Execute(t *testing.T) {
+	t.Run("opens a cart at an outlet", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+
+		result, err := NewCreateCartUsecase(repositories.NewCartRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db)).Execute(ctx, CreateCartParam{
+			ShopID:     1,
+			UserID:     1,
+			LocationID: fixture.airport.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.airport.ID, result.Cart.LocationID)
+		assert.True(t, result.Cart.IsOpen())
+		assert.Empty(t, result.Cart.Lines)
+	})
+
+	t.Run("rejects warehouses and locations of other shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		usecase := NewCreateCartUsecase(repositories.NewCartRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db))
+
+		_, err := usecase.Execute(ctx, CreateCartParam{ShopID: 1, UserID: 1, LocationID: fixture.warehouse.ID})
+		assert.EqualError(t, err, "validation failed: sales are only made at outlets")
+
+		_, err = usecase.Execute(ctx, CreateCartParam{ShopID: 1, UserID: 1, LocationID: fixture.otherShop.ID})
+		assert.EqualError(t, err, "validation failed: location not found")
+	})
+
+	t.Run("keeps staff to the locations they work at", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+
+		_, err := NewCreateCartUsecase(repositories.NewCartRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db)).Execute(ctx, CreateCartParam{
+			ShopID:             1,
+			UserID:             1,
+			LocationID:         fixture.airport.ID,
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		assert.EqualError(t, err, fmt.Sprintf("forbidden: no access to location %d", fixture.airport.ID))
+	})
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+type DeleteCartUsecase struct {
+	cartRepository repositories.CartRepository
+}
+
+func NewDeleteCartUsecase(cartRepository repositories.CartRepository) *DeleteCartUsecase {
+	return &DeleteCartUsecase{
+		cartRepository: cartRepository,
+	}
+}
+
+type DeleteCartParam struct {
+	ShopID             uint64
+	CartID             uint64
+	AllowedLocationIDs []uint64
+}
+
+// Execute abandons an open cart. Checked out carts stay with their sale.
+func (u *DeleteCartUsecase) Execute(ctx context.Context, param DeleteCartParam) error {
+	cart, err := findOpenCart(ctx, u.cartRepository, param.ShopID, param.CartID, param.AllowedLocationIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := u.cartRepository.Delete(ctx, cart); err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	return nil
+}
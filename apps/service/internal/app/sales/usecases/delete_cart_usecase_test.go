@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+func TestDeleteCartUsecase_Execute(t *testing.T) {
+	t.Run("abandons an open cart", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 1)
+
+		err := NewDeleteCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, DeleteCartParam{ShopID: 1, CartID: cart.ID})
+		require.NoError(t, err)
+
+		_, err = repositories.NewCartRepository(fixture.db).FindByID(ctx, cart.ID)
+		assert.EqualError(t, err, "cart not found")
+	})
+
+	t.Run("keeps checked out carts", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 1)
+		stock(t, fixture, fixture.main, fixture.small, 1)
+		_, err := NewCheckoutCartUsecase(fixture.db).Execute(ctx, CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: entities.PaymentCard, Amount: 1500}},
+		})
+		require.NoError(t, err)
+
+		err = NewDeleteCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, DeleteCartParam{ShopID: 1, CartID: cart.ID})
+		assert.EqualError(t, err, "cart is already checked out")
+	})
+}
@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+type GetCartUsecase struct {
+	cartRepository repositories.CartRepository
+}
+
+func NewGetCartUsecase(cartRepository repositories.CartRepository) *GetCartUsecase {
+	return &GetCartUsecase{
+		cartRepository: cartRepository,
+	}
+}
+
+type GetCartParam struct {
+	ShopID             uint64
+	CartID             uint64
+	AllowedLocationIDs []uint64
+}
+
+type GetCartResult struct {
+	Cart *entities.Cart
+}
+
+func (u *GetCartUsecase) Execute(ctx context.Context, param GetCartParam) (*GetCartResult, error) {
+	cart, err := findShopCart(ctx, u.cartRepository, param.ShopID, param.CartID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCartResult{
+		Cart: &cart,
+	}, nil
+}
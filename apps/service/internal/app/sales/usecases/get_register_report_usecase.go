@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+type GetRegisterReportUsecase struct {
+	sessionRepository repositories.RegisterSessionRepository
+	saleRepository    repositories.SaleRepository
+}
+
+func NewGetRegisterReportUsecase(sessionRepository repositories.RegisterSessionRepository, saleRepository repositories.SaleRepository) *GetRegisterReportUsecase {
+	return &GetRegisterReportUsecase{
+		sessionRepository: sessionRepository,
+		saleRepository:    saleRepository,
+	}
+}
+
+type GetRegisterReportParam struct {
+	ShopID             uint64
+	SessionID          uint64
+	AllowedLocationIDs []uint64
+}
+
+type GetRegisterReportResult struct {
+	Session *entities.RegisterSession
+	Report  *entities.RegisterReport
+}
+
+// Execute sums up a register session of the shop. For a closed session this
+// is its Z-report; for an open one it runs up to now.
+func (u *GetRegisterReportUsecase) Execute(ctx context.Context, param GetRegisterReportParam) (*GetRegisterReportResult, error) {
+	session, err := findShopSession(ctx, u.sessionRepository, param.ShopID, param.SessionID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := buildRegisterReport(ctx, u.saleRepository, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRegisterReportResult{
+		Session: &session,
+		Report:  &report,
+	}, nil
+}
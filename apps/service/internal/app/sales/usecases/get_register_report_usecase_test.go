@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+func TestGetRegisterReportUsecase_Execute(t *testing.T) {
+	t.Run("sums up an open session so far", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		stock(t, fixture, fixture.main, fixture.small, 10)
+		session := openSession(t, fixture, fixture.main, 5000)
+		sell(t, fixture, fixture.main, "TS-S", 1, PaymentParam{Method: entities.PaymentCash, Amount: 2000})
+		_, err := NewRecordCashMovementUsecase(fixture.db).Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 1, SessionID: session.ID, Type: entities.CashIn, Amount: 1000, Reason: "Coins"})
+		require.NoError(t, err)
+
+		result, err := NewGetRegisterReportUsecase(repositories.NewRegisterSessionRepository(fixture.db), repositories.NewSaleRepository(fixture.db)).Execute(ctx, GetRegisterReportParam{
+			ShopID:    1,
+			SessionID: session.ID,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Session.IsOpen())
+		assert.Equal(t, int64(1), result.Report.Sales.Count)
+		assert.Equal(t, int64(1500), result.Report.Sales.Total)
+		assert.Equal(t, int64(1000), result.Report.CashIn)
+		assert.Equal(t, int64(7500), result.Report.ExpectedCash)
+		require.Len(t, result.Report.Payments, 3)
+		assert.Equal(t, entities.PaymentTotal{Method: entities.PaymentCard}, result.Report.Payments[1])
+	})
+
+	t.Run("treats sessions of other shops as missing", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		session := openSession(t, fixture, fixture.main, 0)
+
+		_, err := NewGetRegisterReportUsecase(repositories.NewRegisterSessionRepository(fixture.db), repositories.NewSaleRepository(fixture.db)).Execute(ctx, GetRegisterReportParam{
+			ShopID:    2,
+			SessionID: session.ID,
+		})
+		assert.EqualError(t, err, "register session not found")
+	})
+}
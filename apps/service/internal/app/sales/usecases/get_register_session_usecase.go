@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+type GetRegisterSessionUsecase struct {
+	sessionRepository repositories.RegisterSessionRepository
+}
+
+func NewGetRegisterSessionUsecase(sessionRepository repositories.RegisterSessionRepository) *GetRegisterSessionUsecase {
+	return &GetRegisterSessionUsecase{
+		sessionRepository: sessionRepository,
+	}
+}
+
+type GetRegisterSessionParam struct {
+	ShopID             uint64
+	SessionID          uint64
+	AllowedLocationIDs []uint64
+}
+
+type GetRegisterSessionResult struct {
+	Session *entities.RegisterSession
+}
+
+// Execute loads a register session of the shop with its cash movements.
+func (u *GetRegisterSessionUsecase) Execute(ctx context.Context, param GetRegisterSessionParam) (*GetRegisterSessionResult, error) {
+	session, err := findShopSession(ctx, u.sessionRepository, param.ShopID, param.SessionID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRegisterSessionResult{
+		Session: &session,
+	}, nil
+}
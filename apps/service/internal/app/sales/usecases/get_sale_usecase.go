@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+type GetSaleUsecase struct {
+	saleRepository repositories.SaleRepository
+}
+
+func NewGetSaleUsecase(saleRepository repositories.SaleRepository) *GetSaleUsecase {
+	return &GetSaleUsecase{
+		saleRepository: saleRepository,
+	}
+}
+
+type GetSaleParam struct {
+	ShopID             uint64
+	SaleID             uint64
+	AllowedLocationIDs []uint64
+}
+
+type GetSaleResult struct {
+	Sale *entities.Sale
+}
+
+// Execute loads a sale of the shop with its lines and payments, treating
+// sales of other shops as missing.
+func (u *GetSaleUsecase) Execute(ctx context.Context, param GetSaleParam) (*GetSaleResult, error) {
+	sale, err := u.saleRepository.FindByID(ctx, param.SaleID)
+	if err != nil {
+		return nil, err
+	}
+	if sale.ShopID != param.ShopID {
+		return nil, errors.New("sale not found")
+	}
+	if err := ensureLocationAllowed(param.AllowedLocationIDs, sale.LocationID); err != nil {
+		return nil, err
+	}
+
+	return &GetSaleResult{
+		Sale: &sale,
+	}, nil
+}
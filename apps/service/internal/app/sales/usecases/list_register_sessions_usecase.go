@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListRegisterSessionsUsecase struct {
+	sessionRepository repositories.RegisterSessionRepository
+	validator         *validator.Validate
+}
+
+func NewListRegisterSessionsUsecase(sessionRepository repositories.RegisterSessionRepository) *ListRegisterSessionsUsecase {
+	return &ListRegisterSessionsUsecase{
+		sessionRepository: sessionRepository,
+		validator:         validator.New(),
+	}
+}
+
+type ListRegisterSessionsParam struct {
+	ShopID     uint64 `validate:"required"`
+	LocationID *uint64
+	Status     string `validate:"omitempty,oneof=open closed"`
+	Cursor     string
+	Limit      int
+	// AllowedLocationIDs are the locations the staff member works at, when
+	// limited to some; sessions at other locations are left out.
+	AllowedLocationIDs []uint64
+}
+
+type ListRegisterSessionsResult struct {
+	Sessions   []entities.RegisterSession
+	NextCursor string
+}
+
+// Execute lists the register sessions of the shop, newest first, one page
+// at a time. NextCursor is empty on the last page.
+func (u *ListRegisterSessionsUsecase) Execute(ctx context.Context, param ListRegisterSessionsParam) (*ListRegisterSessionsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.LocationID != nil {
+		if err := ensureLocationAllowed(param.AllowedLocationIDs, *param.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The limit is one past the page size to tell whether another page
+	// follows.
+	query := repositories.RegisterSessionQuery{
+		ShopID:      param.ShopID,
+		LocationID:  param.LocationID,
+		LocationIDs: param.AllowedLocationIDs,
+		Status:      param.Status,
+		Limit:       pagination.Limit(param.Limit) + 1,
+	}
+	if param.Cursor != "" {
+		after, err := pagination.Decode(param.Cursor)
+		if err != nil || after.Sort != repositories.RegisterSessionSortRecent {
+			return nil, errors.New("validation failed: invalid cursor")
+		}
+		query.After = &after
+	}
+
+	sessions := u.sessionRepository.Search(ctx, query)
+
+	result := &ListRegisterSessionsResult{Sessions: sessions}
+	if len(sessions) == query.Limit {
+		result.Sessions = sessions[:query.Limit-1]
+		result.NextCursor = repositories.RegisterSessionCursor(result.Sessions[len(result.Sessions)-1]).Encode()
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListSalesUsecase struct {
+	saleRepository repositories.SaleRepository
+	validator      *validator.Validate
+}
+
+func NewListSalesUsecase(saleRepository repositories.SaleRepository) *ListSalesUsecase {
+	return &ListSalesUsecase{
+		saleRepository: saleRepository,
+		validator:      validator.New(),
+	}
+}
+
+type ListSalesParam struct {
+	ShopID     uint64 `validate:"required"`
+	LocationID *uint64
+	Cursor     string
+	Limit      int
+	// AllowedLocationIDs are the locations the user works at, when limited
+	// to some. The list is kept to those.
+	AllowedLocationIDs []uint64
+}
+
+type ListSalesResult struct {
+	Sales      []entities.Sale
+	NextCursor string
+}
+
+// Execute lists the sales of the shop, newest first, one page at a time.
+// NextCursor is empty on the last page.
+func (u *ListSalesUsecase) Execute(ctx context.Context, param ListSalesParam) (*ListSalesResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if param.LocationID != nil {
+		if err := ensureLocationAllowed(param.AllowedLocationIDs, *param.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The limit is one past the page size to tell whether another page
+	// follows.
+	query := repositories.SaleQuery{
+		ShopID:      param.ShopID,
+		LocationID:  param.LocationID,
+		LocationIDs: param.AllowedLocationIDs,
+		Limit:       pagination.Limit(param.Limit) + 1,
+	}
+	if param.Cursor != "" {
+		after, err := pagination.Decode(param.Cursor)
+		if err != nil || after.Sort != repositories.SaleSortRecent {
+			return nil, errors.New("validation failed: invalid cursor")
+		}
+		query.After = &after
+	}
+
+	sales := u.saleRepository.Search(ctx, query)
+
+	result := &ListSalesResult{Sales: sales}
+	if len(sales) == query.Limit {
+		result.Sales = sales[:query.Limit-1]
+		result.NextCursor = repositories.SaleCursor(result.Sales[len(result.Sales)-1]).Encode()
+	}
+	return result, nil
+}
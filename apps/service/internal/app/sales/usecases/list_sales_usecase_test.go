@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+func TestListSalesUsecase_Execute(t *testing.T) {
+	// sell checks out a cart of quantity small shirts at location.
+	sell := func(t *testing.T, fixture *salesFixture, location shopentities.Location, quantity int64) {
+		stock(t, fixture, location, fixture.small, quantity)
+		cart := addLine(t, fixture, openCart(t, fixture, location), "TS-S", quantity)
+		_, err := NewCheckoutCartUsecase(fixture.db).Execute(context.Background(), CheckoutCartParam{
+			ShopID:   1,
+			UserID:   1,
+			CartID:   cart.ID,
+			Payments: []PaymentParam{{Method: entities.PaymentCash, Amount: 1500 * quantity}},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("pages through the sales newest first", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		sell(t, fixture, fixture.main, 1)
+		sell(t, fixture, fixture.airport, 2)
+		sell(t, fixture, fixture.main, 3)
+		usecase := NewListSalesUsecase(repositories.NewSaleRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, ListSalesParam{ShopID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, result.Sales, 2)
+		assert.Equal(t, int64(4500), result.Sales[0].Total)
+		assert.Equal(t, int64(3000), result.Sales[1].Total)
+		require.NotEmpty(t, result.NextCursor)
+
+		result, err = usecase.Execute(ctx, ListSalesParam{ShopID: 1, Limit: 2, Cursor: result.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, result.Sales, 1)
+		assert.Equal(t, int64(1500), result.Sales[0].Total)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("keeps staff to the locations they work at", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		sell(t, fixture, fixture.main, 1)
+		sell(t, fixture, fixture.airport, 2)
+		usecase := NewListSalesUsecase(repositories.NewSaleRepository(fixture.db))
+
+		result, err := usecase.Execute(ctx, ListSalesParam{ShopID: 1, AllowedLocationIDs: []uint64{fixture.airport.ID}})
+		require.NoError(t, err)
+		require.Len(t, result.Sales, 1)
+		assert.Equal(t, fixture.airport.ID, result.Sales[0].LocationID)
+
+		_, err = usecase.Execute(ctx, ListSalesParam{ShopID: 1, LocationID: &fixture.main.ID, AllowedLocationIDs: []uint64{fixture.airport.ID}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+	})
+}
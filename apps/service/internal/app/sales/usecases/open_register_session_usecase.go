@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type OpenRegisterSessionUsecase struct {
+	sessionRepository  repositories.RegisterSessionRepository
+	locationRepository shoprepositories.LocationRepository
+	validator          *validator.Validate
+}
+
+func NewOpenRegisterSessionUsecase(sessionRepository repositories.RegisterSessionRepository, locationRepository shoprepositories.LocationRepository) *OpenRegisterSessionUsecase {
+	return &OpenRegisterSessionUsecase{
+		sessionRepository:  sessionRepository,
+		locationRepository: locationRepository,
+		validator:          validator.New(),
+	}
+}
+
+type OpenRegisterSessionParam struct {
+	ShopID       uint64 `validate:"required"`
+	StaffID      uint64 `validate:"required"`
+	LocationID   uint64 `validate:"required"`
+	OpeningFloat int64  `validate:"min=0"`
+	// AllowedLocationIDs are the locations the staff member works at, when
+	// limited to some; see accessentities.Staff.
+	AllowedLocationIDs []uint64
+}
+
+type OpenRegisterSessionResult struct {
+	Session *entities.RegisterSession
+}
+
+// Execute opens the register of an outlet of the shop with the cash counted
+// into the drawer. An outlet's register is opened by one session at a time.
+func (u *OpenRegisterSessionUsecase) Execute(ctx context.Context, param OpenRegisterSessionParam) (*OpenRegisterSessionResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+	if err := ensureLocationAllowed(param.AllowedLocationIDs, param.LocationID); err != nil {
+		return nil, err
+	}
+
+	location, err := findOutlet(ctx, u.locationRepository, param.ShopID, param.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = u.sessionRepository.FindOpenByLocationID(ctx, location.ID)
+	if err == nil {
+		return nil, fmt.Errorf("register is already open at location %d", location.ID)
+	}
+	if err.Error() != "register session not found" {
+		return nil, fmt.Errorf("failed to get register session: %w", err)
+	}
+
+	session, err := u.sessionRepository.Create(ctx, entities.RegisterSession{
+		ShopID:          param.ShopID,
+		LocationID:      location.ID,
+		Status:          entities.RegisterSessionOpen,
+		OpeningFloat:    param.OpeningFloat,
+		OpenedByStaffID: param.StaffID,
+		OpenedAt:        time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open register session: %w", err)
+	}
+
+	return &OpenRegisterSessionResult{
+		Session: &session,
+	}, nil
+}
+
+// findShopSession loads a register session with its cash movements,
+// treating sessions of other shops as missing. Sessions at locations the
+// staff member does not work at are forbidden.
+func findShopSession(ctx context.Context, sessionRepository repositories.RegisterSessionRepository, shopID, sessionID uint64, allowedLocationIDs []uint64) (entities.RegisterSession, error) {
+	session, err := sessionRepository.FindByID(ctx, sessionID)
+	if err != nil {
+		return session, err
+	}
+	if session.ShopID != shopID {
+		return entities.RegisterSession{}, errors.New("register session not found")
+	}
+	if err := ensureLocationAllowed(allowedLocationIDs, session.LocationID); err != nil {
+		return entities.RegisterSession{}, err
+	}
+	return session, nil
+}
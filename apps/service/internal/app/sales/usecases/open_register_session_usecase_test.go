@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+)
+
+// openSession opens the register at location with openingFloat in the
+// drawer.
+func openSession(t *testing.T, fixture *salesFixture, location shopentities.Location, openingFloat int64) entities.RegisterSession {
+	result, err := NewOpenRegisterSessionUsecase(repositories.NewRegisterSessionRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db)).Execute(context.Background(), OpenRegisterSessionParam{
+		ShopID:       1,
+		StaffID:      1,
+		LocationID:   location.ID,
+		OpeningFloat: openingFloat,
+	})
+	require.NoError(t, err)
+	return *result.Session
+}
+
+func TestOpenRegisterSessionUsecase_Execute(t *testing.T) {
+	t.Run("opens the register of an outlet", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+
+		result, err := NewOpenRegisterSessionUsecase(repositories.NewRegisterSessionRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db)).Execute(ctx, OpenRegisterSessionParam{
+			ShopID:       1,
+			StaffID:      3,
+			LocationID:   fixture.main.ID,
+			OpeningFloat: 10000,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Session.IsOpen())
+		assert.Equal(t, uint64(3), result.Session.OpenedByStaffID)
+		assert.Equal(t, int64(10000), result.Session.OpeningFloat)
+		assert.Nil(t, result.Session.ClosedByStaffID)
+	})
+
+	t.Run("opens one session per register at a time", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		openSession(t, fixture, fixture.main, 0)
+		usecase := NewOpenRegisterSessionUsecase(repositories.NewRegisterSessionRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db))
+
+		_, err := usecase.Execute(ctx, OpenRegisterSessionParam{ShopID: 1, StaffID: 1, LocationID: fixture.main.ID})
+		assert.EqualError(t, err, fmt.Sprintf("register is already open at location %d", fixture.main.ID))
+
+		_, err = usecase.Execute(ctx, OpenRegisterSessionParam{ShopID: 1, StaffID: 1, LocationID: fixture.airport.ID})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects warehouses and locations of other shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		usecase := NewOpenRegisterSessionUsecase(repositories.NewRegisterSessionRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db))
+
+		_, err := usecase.Execute(ctx, OpenRegisterSessionParam{ShopID: 1, StaffID: 1, LocationID: fixture.warehouse.ID})
+		assert.EqualError(t, err, "validation failed: sales are only made at outlets")
+
+		_, err = usecase.Execute(ctx, OpenRegisterSessionParam{ShopID: 1, StaffID: 1, LocationID: fixture.otherShop.ID})
+		assert.EqualError(t, err, "validation failed: location not found")
+
+		_, err = usecase.Execute(ctx, OpenRegisterSessionParam{ShopID: 1, StaffID: 1, LocationID: fixture.main.ID, OpeningFloat: -1})
+		assert.ErrorContains(t, err, "validation failed")
+	})
+
+	t.Run("keeps staff to the locations they work at", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+
+		_, err := NewOpenRegisterSessionUsecase(repositories.NewRegisterSessionRepository(fixture.db), shoprepositories.NewLocationRepository(fixture.db)).Execute(ctx, OpenRegisterSessionParam{
+			ShopID:             1,
+			StaffID:            1,
+			LocationID:         fixture.airport.ID,
+			AllowedLocationIDs: []uint64{fixture.main.ID},
+		})
+		assert.EqualError(t, err, fmt.Sprintf("forbidden: no access to location %d", fixture.airport.ID))
+	})
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type RecordCashMovementUsecase struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+func NewRecordCashMovementUsecase(db *gorm.DB) *RecordCashMovementUsecase {
+	return &RecordCashMovementUsecase{
+		db:        db,
+		validator: validator.New(),
+	}
+}
+
+type RecordCashMovementParam struct {
+	ShopID             uint64                    `validate:"required"`
+	StaffID            uint64                    `validate:"required"`
+	SessionID          uint64                    `validate:"required"`
+	Type               entities.CashMovementType `validate:"required,oneof=in out"`
+	Amount             int64                     `validate:"required,min=1"`
+	Reason             string                    `validate:"required,max=255"`
+	AllowedLocationIDs []uint64
+}
+
+type RecordCashMovementResult struct {
+	Session *entities.RegisterSession
+}
+
+// Execute records cash put into or taken out of the drawer of an open
+// register session and returns the session with all its cash movements.
+func (u *RecordCashMovementUsecase) Execute(ctx context.Context, param RecordCashMovementParam) (*RecordCashMovementResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	reason := strings.TrimSpace(param.Reason)
+	if reason == "" {
+		return nil, errors.New("validation failed: reason is required")
+	}
+
+	var result *RecordCashMovementResult
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txSessionRepo := repositories.NewRegisterSessionRepository(tx)
+
+		session, err := findOpenSession(ctx, txSessionRepo, param.ShopID, param.SessionID, param.AllowedLocationIDs)
+		if err != nil {
+			return err
+		}
+
+		_, err = txSessionRepo.CreateCashMovement(ctx, entities.RegisterCashMovement{
+			SessionID: session.ID,
+			Type:      param.Type,
+			Amount:    param.Amount,
+			Reason:    reason,
+			StaffID:   param.StaffID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record cash movement: %w", err)
+		}
+
+		updated, err := txSessionRepo.FindByID(ctx, session.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reload register session: %w", err)
+		}
+
+		result = &RecordCashMovementResult{
+			Session: &updated,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// findOpenSession is findShopSession for sessions about to have something
+// recorded against them, which must still be open. The session then stays
+// open until the transaction ends; see
+// repositories.RegisterSessionRepository.FindOpenByLocationID.
+func findOpenSession(ctx context.Context, sessionRepository repositories.RegisterSessionRepository, shopID, sessionID uint64, allowedLocationIDs []uint64) (entities.RegisterSession, error) {
+	session, err := findShopSession(ctx, sessionRepository, shopID, sessionID, allowedLocationIDs)
+	if err != nil {
+		return session, err
+	}
+
+	open, err := sessionRepository.FindOpenByLocationID(ctx, session.LocationID)
+	if err != nil && err.Error() != "register session not found" {
+		return entities.RegisterSession{}, fmt.Errorf("failed to get register session: %w", err)
+	}
+	if err != nil || open.ID != session.ID {
+		return entities.RegisterSession{}, errors.New("register session is already closed")
+	}
+	return open, nil
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+)
+
+func TestRecordCashMovementUsecase_Execute(t *testing.T) {
+	t.Run("records cash in and out of the drawer", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		session := openSession(t, fixture, fixture.main, 5000)
+		usecase := NewRecordCashMovementUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 2, SessionID: session.ID, Type: entities.CashIn, Amount: 2000, Reason: "Change from the bank"})
+		require.NoError(t, err)
+		result, err := usecase.Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 3, SessionID: session.ID, Type: entities.CashOut, Amount: 700, Reason: " Window cleaner "})
+		require.NoError(t, err)
+
+		require.Len(t, result.Session.CashMovements, 2)
+		assert.Equal(t, "Window cleaner", result.Session.CashMovements[1].Reason)
+		assert.Equal(t, uint64(3), result.Session.CashMovements[1].StaffID)
+		in, out := result.Session.CashTotals()
+		assert.Equal(t, int64(2000), in)
+		assert.Equal(t, int64(700), out)
+	})
+
+	t.Run("validates the movement", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		session := openSession(t, fixture, fixture.main, 0)
+		usecase := NewRecordCashMovementUsecase(fixture.db)
+
+		_, err := usecase.Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 1, SessionID: session.ID, Type: "sideways", Amount: 100, Reason: "Float"})
+		assert.ErrorContains(t, err, "validation failed")
+
+		_, err = usecase.Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 1, SessionID: session.ID, Type: entities.CashIn, Amount: 0, Reason: "Float"})
+		assert.ErrorContains(t, err, "validation failed")
+
+		_, err = usecase.Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 1, SessionID: session.ID, Type: entities.CashIn, Amount: 100, Reason: "   "})
+		assert.EqualError(t, err, "validation failed: reason is required")
+	})
+
+	t.Run("rejects closed sessions and sessions of other shops", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		session := openSession(t, fixture, fixture.main, 0)
+		_, err := NewCloseRegisterSessionUsecase(fixture.db).Execute(ctx, CloseRegisterSessionParam{ShopID: 1, StaffID: 1, SessionID: session.ID})
+		require.NoError(t, err)
+		usecase := NewRecordCashMovementUsecase(fixture.db)
+
+		_, err = usecase.Execute(ctx, RecordCashMovementParam{ShopID: 1, StaffID: 1, SessionID: session.ID, Type: entities.CashIn, Amount: 100, Reason: "Float"})
+		assert.EqualError(t, err, "register session is already closed")
+
+		_, err = usecase.Execute(ctx, RecordCashMovementParam{ShopID: 2, StaffID: 1, SessionID: session.ID, Type: entities.CashIn, Amount: 100, Reason: "Float"})
+		assert.EqualError(t, err, "register session not found")
+	})
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+type RemoveCartLineUsecase struct {
+	cartRepository repositories.CartRepository
+}
+
+func NewRemoveCartLineUsecase(cartRepository repositories.CartRepository) *RemoveCartLineUsecase {
+	return &RemoveCartLineUsecase{
+		cartRepository: cartRepository,
+	}
+}
+
+type RemoveCartLineParam struct {
+	ShopID             uint64
+	CartID             uint64
+	LineID             uint64
+	AllowedLocationIDs []uint64
+}
+
+type RemoveCartLineResult struct {
+	Cart *entities.Cart
+}
+
+// Execute takes a line off an open cart. The cart's own discount must still
+// fit what is left.
+func (u *RemoveCartLineUsecase) Execute(ctx context.Context, param RemoveCartLineParam) (*RemoveCartLineResult, error) {
+	cart, err := findOpenCart(ctx, u.cartRepository, param.ShopID, param.CartID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+	index, err := findCartLine(cart, param.LineID)
+	if err != nil {
+		return nil, err
+	}
+
+	line := cart.Lines[index]
+	cart.Lines = slices.Delete(cart.Lines, index, index+1)
+	if err := ensureDiscounts(cart); err != nil {
+		return nil, err
+	}
+
+	if err := u.cartRepository.DeleteLine(ctx, line); err != nil {
+		return nil, fmt.Errorf("failed to remove cart line: %w", err)
+	}
+
+	return &RemoveCartLineResult{
+		Cart: &cart,
+	}, nil
+}
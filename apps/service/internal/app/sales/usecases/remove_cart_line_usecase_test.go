@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+func TestRemoveCartLineUsecase_Execute(t *testing.T) {
+	t.Run("takes a line off the cart", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 1)
+		cart = addLine(t, fixture, cart, "TS-M", 1)
+
+		result, err := NewRemoveCartLineUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, RemoveCartLineParam{
+			ShopID: 1,
+			CartID: cart.ID,
+			LineID: cart.Lines[0].ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Cart.Lines, 1)
+		assert.Equal(t, "TS-M", result.Cart.Lines[0].SKU)
+
+		found, err := repositories.NewCartRepository(fixture.db).FindByID(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.Len(t, found.Lines, 1)
+	})
+
+	t.Run("keeps the cart discount within the cart amount", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 1)
+		cart = addLine(t, fixture, cart, "TS-M", 1)
+		_, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:         1,
+			CartID:         cart.ID,
+			DiscountAmount: 1600,
+		})
+		require.NoError(t, err)
+
+		_, err = NewRemoveCartLineUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, RemoveCartLineParam{
+			ShopID: 1,
+			CartID: cart.ID,
+			LineID: cart.Lines[1].ID,
+		})
+		assert.EqualError(t, err, "validation failed: discount exceeds the cart amount")
+	})
+}
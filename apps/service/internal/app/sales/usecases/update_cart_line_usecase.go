@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateCartLineUsecase struct {
+	cartRepository repositories.CartRepository
+	validator      *validator.Validate
+}
+
+func NewUpdateCartLineUsecase(cartRepository repositories.CartRepository) *UpdateCartLineUsecase {
+	return &UpdateCartLineUsecase{
+		cartRepository: cartRepository,
+		validator:      validator.New(),
+	}
+}
+
+type UpdateCartLineParam struct {
+	ShopID             uint64 `validate:"required"`
+	CartID             uint64 `validate:"required"`
+	LineID             uint64 `validate:"required"`
+	Quantity           int64  `validate:"required,min=1"`
+	DiscountAmount     int64  `validate:"min=0"`
+	AllowedLocationIDs []uint64
+}
+
+type UpdateCartLineResult struct {
+	Cart *entities.Cart
+}
+
+// Execute sets the quantity and discount of a line of an open cart. Its
+// price stays the one it was added at.
+func (u *UpdateCartLineUsecase) Execute(ctx context.Context, param UpdateCartLineParam) (*UpdateCartLineResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	cart, err := findOpenCart(ctx, u.cartRepository, param.ShopID, param.CartID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+	index, err := findCartLine(cart, param.LineID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart.Lines[index].Quantity = param.Quantity
+	cart.Lines[index].DiscountAmount = param.DiscountAmount
+	if err := ensureDiscounts(cart); err != nil {
+		return nil, err
+	}
+
+	line, err := u.cartRepository.UpdateLine(ctx, cart.Lines[index])
+	if err != nil {
+		return nil, fmt.Errorf("failed to update cart line: %w", err)
+	}
+	cart.Lines[index] = line
+
+	return &UpdateCartLineResult{
+		Cart: &cart,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+func TestUpdateCartLineUsecase_Execute(t *testing.T) {
+	t.Run("sets the quantity and discount of a line", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 1)
+
+		result, err := NewUpdateCartLineUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartLineParam{
+			ShopID:         1,
+			CartID:         cart.ID,
+			LineID:         cart.Lines[0].ID,
+			Quantity:       3,
+			DiscountAmount: 500,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4000), result.Cart.Lines[0].Total())
+	})
+
+	t.Run("keeps the cart discount within the cart amount", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 2)
+		_, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:         1,
+			CartID:         cart.ID,
+			DiscountAmount: 2000,
+		})
+		require.NoError(t, err)
+
+		_, err = NewUpdateCartLineUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartLineParam{
+			ShopID:   1,
+			CartID:   cart.ID,
+			LineID:   cart.Lines[0].ID,
+			Quantity: 1,
+		})
+		assert.EqualError(t, err, "validation failed: discount exceeds the cart amount")
+	})
+
+	t.Run("rejects lines of other carts", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		other := addLine(t, fixture, openCart(t, fixture, fixture.main), "TS-S", 1)
+
+		_, err := NewUpdateCartLineUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartLineParam{
+			ShopID:   1,
+			CartID:   cart.ID,
+			LineID:   other.Lines[0].ID,
+			Quantity: 2,
+		})
+		assert.EqualError(t, err, "cart line not found")
+	})
+}
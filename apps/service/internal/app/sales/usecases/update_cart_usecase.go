@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateCartUsecase struct {
+	cartRepository repositories.CartRepository
+	validator      *validator.Validate
+}
+
+func NewUpdateCartUsecase(cartRepository repositories.CartRepository) *UpdateCartUsecase {
+	return &UpdateCartUsecase{
+		cartRepository: cartRepository,
+		validator:      validator.New(),
+	}
+}
+
+type UpdateCartParam struct {
+	ShopID             uint64 `validate:"required"`
+	CartID             uint64 `validate:"required"`
+	DiscountAmount     int64  `validate:"min=0"`
+	TaxRate            int64  `validate:"min=0,max=10000"`
+	AllowedLocationIDs []uint64
+}
+
+type UpdateCartResult struct {
+	Cart *entities.Cart
+}
+
+// Execute sets the discount taken off an open cart as a whole and the rate
+// its tax is charged at.
+func (u *UpdateCartUsecase) Execute(ctx context.Context, param UpdateCartParam) (*UpdateCartResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	cart, err := findOpenCart(ctx, u.cartRepository, param.ShopID, param.CartID, param.AllowedLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cart.DiscountAmount = param.DiscountAmount
+	cart.TaxRate = param.TaxRate
+	if err := ensureDiscounts(cart); err != nil {
+		return nil, err
+	}
+
+	updated, err := u.cartRepository.Update(ctx, cart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update cart: %w", err)
+	}
+	updated.Lines = cart.Lines
+
+	return &UpdateCartResult{
+		Cart: &updated,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+)
+
+func TestUpdateCartUsecase_Execute(t *testing.T) {
+	t.Run("applies a discount and tax to the cart", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 1)
+		addLine(t, fixture, cart, "TS-M", 1)
+
+		result, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:         1,
+			CartID:         cart.ID,
+			DiscountAmount: 199,
+			TaxRate:        1000,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, entities.Totals{Subtotal: 3200, Discount: 199, Tax: 300, Total: 3301}, result.Cart.Totals())
+	})
+
+	t.Run("rejects discounts over the cart amount", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+		addLine(t, fixture, cart, "TS-S", 1)
+
+		_, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:         1,
+			CartID:         cart.ID,
+			DiscountAmount: 1501,
+		})
+		assert.EqualError(t, err, "validation failed: discount exceeds the cart amount")
+	})
+
+	t.Run("rejects tax rates over 100%", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+
+		_, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:  1,
+			CartID:  cart.ID,
+			TaxRate: entities.TaxRateScale + 1,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("leaves carts of other shops alone", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupSalesTest(t)
+		cart := openCart(t, fixture, fixture.main)
+
+		_, err := NewUpdateCartUsecase(repositories.NewCartRepository(fixture.db)).Execute(ctx, UpdateCartParam{
+			ShopID:  2,
+			CartID:  cart.ID,
+			TaxRate: 1000,
+		})
+		assert.EqualError(t, err, "cart not found")
+	})
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+)
+
+// DefaultLocationName names the location every shop starts with.
+const DefaultLocationName = "Main"
+
+const (
+	LocationKindOutlet    = "outlet"
+	LocationKindWarehouse = "warehouse"
+)
+
+// Location is a branch of a shop: an outlet selling to customers or a
+// warehouse holding stock for the outlets. Every location keeps its own
+// stock.
+type Location struct {
+	ID        uint64    `gorm:"primaryKey;column:id" json:"id"`
+	ShopID    uint64    `gorm:"column:shop_id;not null;index:idx_locations_shop_id" json:"shop_id"`
+	Name      string    `gorm:"column:name;not null" json:"name"`
+	Kind      string    `gorm:"column:kind;type:varchar(20);not null;default:outlet" json:"kind"`
+	Address   string    `gorm:"column:address;not null" json:"address"`
+	Phone     string    `gorm:"column:phone;not null" json:"phone"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (Location) TableName() string {
+	return "locations"
+}
+
+func (l Location) IsWarehouse() bool {
+	return l.Kind == LocationKindWarehouse
+}
@@ -6,6 +6,8 @@ import (
 	"gorm.io/gorm"
 )
 
+// Shop is a business on the platform. A suspended shop is closed to all of
+// its staff until an administrator restores it.
 type Shop struct {
 	ID          uint64         `gorm:"primaryKey;column:id" json:"id"`
 	Name        string         `gorm:"column:name;not null" json:"name"`
@@ -18,8 +20,13 @@ type Shop struct {
 	CreatedAt   time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
+	SuspendedAt *time.Time     `gorm:"column:suspended_at" json:"suspended_at"`
 }
 
 func (Shop) TableName() string {
 	return "shops"
 }
+
+func (s Shop) IsSuspended() bool {
+	return s.SuspendedAt != nil
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+type LocationRepository interface {
+	FindByID(ctx context.Context, id uint64) (entities.Location, error)
+	// FindByShopID returns the locations of the shop ordered by name.
+	FindByShopID(ctx context.Context, shopID uint64) []entities.Location
+	Create(ctx context.Context, location entities.Location) (entities.Location, error)
+	Update(ctx context.Context, location entities.Location) (entities.Location, error)
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+func NewLocationRepository(db *gorm.DB) LocationRepository {
+	return &locationRepository{
+		db: db,
+	}
+}
+
+func (r *locationRepository) FindByID(ctx context.Context, id uint64) (entities.Location, error) {
+	var location entities.Location
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&location).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return location, errors.New("location not found")
+		}
+		return location, err
+	}
+	return location, nil
+}
+
+func (r *locationRepository) FindByShopID(ctx context.Context, shopID uint64) []entities.Location {
+	var locations []entities.Location
+	r.db.WithContext(ctx).Where("shop_id = ?", shopID).Order("name").Order("id").Find(&locations)
+	return locations
+}
+
+func (r *locationRepository) Create(ctx context.Context, location entities.Location) (entities.Location, error) {
+	err := r.db.WithContext(ctx).Create(&location).Error
+	if err != nil {
+		return location, err
+	}
+	return location, nil
+}
+
+func (r *locationRepository) Update(ctx context.Context, location entities.Location) (entities.Location, error) {
+	err := r.db.WithContext(ctx).Save(&location).Error
+	if err != nil {
+		return location, err
+	}
+	return location, nil
+}
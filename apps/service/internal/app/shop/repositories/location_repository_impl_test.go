@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func TestLocationRepository(t *testing.T) {
+	t.Run("lists the locations of a shop by name", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Location{})
+		repo := NewLocationRepository(db)
+
+		for _, location := range []entities.Location{{ShopID: 1, Name: "Storage"}, {ShopID: 1, Name: "Main"}, {ShopID: 2, Name: "Main"}} {
+			_, err := repo.Create(ctx, location)
+			require.NoError(t, err)
+		}
+
+		locations := repo.FindByShopID(ctx, 1)
+		require.Len(t, locations, 2)
+		assert.Equal(t, "Main", locations[0].Name)
+		assert.Equal(t, "Storage", locations[1].Name)
+
+		found, err := repo.FindByID(ctx, locations[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Storage", found.Name)
+	})
+
+	t.Run("returns error when location not found", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.Location{})
+		repo := NewLocationRepository(db)
+
+		_, err := repo.FindByID(ctx, 999)
+		assert.Error(t, err)
+		assert.Equal(t, "location not found", err.Error())
+	})
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+)
+
+// Orders shop lists can be sorted in. A leading dash sorts descending.
+const (
+	ShopSortName          = "name"
+	ShopSortNameDesc      = "-name"
+	ShopSortCreatedAt     = "created_at"
+	ShopSortCreatedAtDesc = "-created_at"
+)
+
+// ShopQuery filters, sorts and pages a list of shops. Search matches the
+// shop name case-insensitively, and After, when set, resumes the list past
+// the shop it points to.
+type ShopQuery struct {
+	Search string
+	Sort   string
+	After  *pagination.Cursor
+	Limit  int
+}
+
+// ShopQueryScope applies the query to a statement selecting from, or
+// joined with, the shops table.
+func ShopQueryScope(query ShopQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search := strings.TrimSpace(query.Search); search != "" {
+			db = db.Where(`LOWER(shops.name) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(search))+"%")
+		}
+
+		column, descending := shopSortColumn(query.Sort)
+		comparison, direction := ">", "ASC"
+		if descending {
+			comparison, direction = "<", "DESC"
+		}
+
+		if query.After != nil {
+			value := any(query.After.Value)
+			if column == "shops.created_at" {
+				at, err := time.Parse(time.RFC3339Nano, query.After.Value)
+				if err != nil {
+					_ = db.AddError(err)
+					return db
+				}
+				value = at
+			}
+			db = db.Where(
+				"("+column+" "+comparison+" ? OR ("+column+" = ? AND shops.id "+comparison+" ?))",
+				value, value, query.After.ID,
+			)
+		}
+
+		db = db.Order(column + " " + direction).Order("shops.id " + direction)
+		if query.Limit > 0 {
+			db = db.Limit(query.Limit)
+		}
+		return db
+	}
+}
+
+// ShopCursor returns the cursor resuming a list sorted by sort after shop.
+func ShopCursor(shop entities.Shop, sort string) pagination.Cursor {
+	cursor := pagination.Cursor{Sort: sort, Value: shop.Name, ID: shop.ID}
+	if column, _ := shopSortColumn(sort); column == "shops.created_at" {
+		cursor.Value = shop.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+func shopSortColumn(sort string) (string, bool) {
+	switch sort {
+	case ShopSortNameDesc:
+		return "shops.name", true
+	case ShopSortCreatedAt:
+		return "shops.created_at", false
+	case ShopSortCreatedAtDesc:
+		return "shops.created_at", true
+	default:
+		return "shops.name", false
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -8,6 +8,7 @@ import (
 
 type ShopRepository interface {
 	All(ctx context.Context) []entities.Shop
+	Search(ctx context.Context, query ShopQuery) []entities.Shop
 	FindByID(ctx context.Context, id uint64) (entities.Shop, error)
 	FindByPhone(ctx context.Context, phone string) (entities.Shop, error)
 	FindByEmail(ctx context.Context, email string) (entities.Shop, error)
@@ -25,6 +25,12 @@ func (r *shopRepository) All(ctx context.Context) []entities.Shop {
 	return shops
 }
 
+func (r *shopRepository) Search(ctx context.Context, query ShopQuery) []entities.Shop {
+	var shops []entities.Shop
+	r.db.WithContext(ctx).Scopes(ShopQueryScope(query)).Find(&shops)
+	return shops
+}
+
 func (r *shopRepository) FindByID(ctx context.Context, id uint64) (entities.Shop, error) {
 	var shop entities.Shop
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&shop).Error
@@ -82,6 +82,80 @@ func TestShopRepository_All(t *testing.T) {
 	})
 }
 
+func TestShopRepository_Search(t *testing.T) {
+	createShops := func(t *testing.T, repo ShopRepository, names ...string) []entities.Shop {
+		shops := make([]entities.Shop, len(names))
+		for i, name := range names {
+			shop, err := repo.Create(context.Background(), entities.Shop{Name: name, Phone: "1234567890", Email: "shop@example.com"})
+			require.NoError(t, err)
+			shops[i] = shop
+		}
+		return shops
+	}
+	names := func(shops []entities.Shop) []string {
+		result := make([]string, len(shops))
+		for i, shop := range shops {
+			result[i] = shop.Name
+		}
+		return result
+	}
+
+	t.Run("sorts by name by default", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewShopRepository(testutil.SetupTestDB(t, &entities.Shop{}))
+		createShops(t, repo, "Bakery", "Apothecary", "Chandlery")
+
+		assert.Equal(t, []string{"Apothecary", "Bakery", "Chandlery"}, names(repo.Search(ctx, ShopQuery{})))
+		assert.Equal(t, []string{"Chandlery", "Bakery", "Apothecary"}, names(repo.Search(ctx, ShopQuery{Sort: ShopSortNameDesc})))
+	})
+
+	t.Run("matches the name case-insensitively", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewShopRepository(testutil.SetupTestDB(t, &entities.Shop{}))
+		createShops(t, repo, "Corner Bakery", "Bakery Express", "Chandlery", "100% Organic")
+
+		assert.Equal(t, []string{"Bakery Express", "Corner Bakery"}, names(repo.Search(ctx, ShopQuery{Search: "bAKERY"})))
+		assert.Equal(t, []string{"100% Organic"}, names(repo.Search(ctx, ShopQuery{Search: "0%"})))
+	})
+
+	t.Run("pages with a cursor", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewShopRepository(testutil.SetupTestDB(t, &entities.Shop{}))
+		shops := createShops(t, repo, "Alpha", "Beta", "Beta", "Gamma")
+
+		first := repo.Search(ctx, ShopQuery{Limit: 2})
+		assert.Equal(t, []string{"Alpha", "Beta"}, names(first))
+
+		cursor := ShopCursor(first[1], ShopSortName)
+		second := repo.Search(ctx, ShopQuery{After: &cursor, Limit: 2})
+		require.Len(t, second, 2)
+		assert.Equal(t, shops[2].ID, second[0].ID)
+		assert.Equal(t, "Gamma", second[1].Name)
+	})
+
+	t.Run("pages by creation time", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewShopRepository(testutil.SetupTestDB(t, &entities.Shop{}))
+		shops := createShops(t, repo, "First", "Second", "Third")
+
+		first := repo.Search(ctx, ShopQuery{Sort: ShopSortCreatedAtDesc, Limit: 1})
+		require.Len(t, first, 1)
+		assert.Equal(t, shops[2].ID, first[0].ID)
+
+		cursor := ShopCursor(first[0], ShopSortCreatedAtDesc)
+		assert.Equal(t, []string{"Second", "First"}, names(repo.Search(ctx, ShopQuery{Sort: ShopSortCreatedAtDesc, After: &cursor})))
+	})
+
+	t.Run("excludes soft deleted shops", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewShopRepository(testutil.SetupTestDB(t, &entities.Shop{}))
+		shops := createShops(t, repo, "Open", "Closed")
+		require.NoError(t, repo.Delete(ctx, shops[1]))
+
+		assert.Equal(t, []string{"Open"}, names(repo.Search(ctx, ShopQuery{})))
+	})
+}
+
 func TestShopRepository_FindByID(t *testing.T) {
 	t.Run("returns shop when found", func(t *testing.T) {
 		ctx := context.Background()
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type CreateLocationUsecase struct {
+	locationRepository repositories.LocationRepository
+	validator          *validator.Validate
+}
+
+func NewCreateLocationUsecase(locationRepository repositories.LocationRepository) *CreateLocationUsecase {
+	return &CreateLocationUsecase{
+		locationRepository: locationRepository,
+		validator:          validator.New(),
+	}
+}
+
+// CreateLocationParam describes a branch. Kind defaults to an outlet.
+type CreateLocationParam struct {
+	ShopID  uint64 `validate:"required"`
+	Name    string `validate:"required,min=1,max=100"`
+	Kind    string `validate:"omitempty,oneof=outlet warehouse"`
+	Address string `validate:"max=255"`
+	Phone   string `validate:"max=20"`
+}
+
+type CreateLocationResult struct {
+	Location *entities.Location
+}
+
+// Execute adds a location to the shop. Names are unique within a shop.
+func (u *CreateLocationUsecase) Execute(ctx context.Context, param CreateLocationParam) (*CreateLocationResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	name := strings.TrimSpace(param.Name)
+	if err := ensureLocationNameAvailable(ctx, u.locationRepository, param.ShopID, name, 0); err != nil {
+		return nil, err
+	}
+
+	kind := param.Kind
+	if kind == "" {
+		kind = entities.LocationKindOutlet
+	}
+
+	location, err := u.locationRepository.Create(ctx, entities.Location{
+		ShopID:  param.ShopID,
+		Name:    name,
+		Kind:    kind,
+		Address: strings.TrimSpace(param.Address),
+		Phone:   strings.TrimSpace(param.Phone),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create location: %w", err)
+	}
+
+	return &CreateLocationResult{
+		Location: &location,
+	}, nil
+}
+
+// ensureLocationNameAvailable fails when another location of the shop than
+// exceptID has name, ignoring case.
+func ensureLocationNameAvailable(ctx context.Context, locationRepository repositories.LocationRepository, shopID uint64, name string, exceptID uint64) error {
+	for _, location := range locationRepository.FindByShopID(ctx, shopID) {
+		if location.ID != exceptID && strings.EqualFold(location.Name, name) {
+			return errors.New("location with this name already exists")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupLocationTest(t *testing.T) repositories.LocationRepository {
+	db := testutil.SetupTestDB(t, &entities.Location{})
+	return repositories.NewLocationRepository(db)
+}
+
+func TestCreateLocationUsecase_Execute(t *testing.T) {
+	t.Run("adds a location to the shop", func(t *testing.T) {
+		ctx := context.Background()
+		locationRepo := setupLocationTest(t)
+		usecase := NewCreateLocationUsecase(locationRepo)
+
+		result, err := usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: " Back Room "})
+		require.NoError(t, err)
+		assert.Equal(t, "Back Room", result.Location.Name)
+		assert.Equal(t, entities.LocationKindOutlet, result.Location.Kind)
+
+		locations := NewListLocationsUsecase(locationRepo).Execute(ctx, 1).Locations
+		require.Len(t, locations, 1)
+		assert.Equal(t, result.Location.ID, locations[0].ID)
+	})
+
+	t.Run("returns error when the shop has a location with the name", func(t *testing.T) {
+		ctx := context.Background()
+		locationRepo := setupLocationTest(t)
+		usecase := NewCreateLocationUsecase(locationRepo)
+
+		_, err := usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: "Main"})
+		require.NoError(t, err)
+
+		_, err = usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: "main"})
+		assert.Error(t, err)
+		assert.Equal(t, "location with this name already exists", err.Error())
+
+		_, err = usecase.Execute(ctx, CreateLocationParam{ShopID: 2, Name: "Main"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("adds a warehouse", func(t *testing.T) {
+		ctx := context.Background()
+		usecase := NewCreateLocationUsecase(setupLocationTest(t))
+
+		result, err := usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: "Depot", Kind: entities.LocationKindWarehouse, Address: "1 Dock Road"})
+		require.NoError(t, err)
+		assert.True(t, result.Location.IsWarehouse())
+		assert.Equal(t, "1 Dock Road", result.Location.Address)
+
+		_, err = usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: "Kiosk", Kind: "kiosk"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("validates name is required", func(t *testing.T) {
+		ctx := context.Background()
+		usecase := NewCreateLocationUsecase(setupLocationTest(t))
+
+		_, err := usecase.Execute(ctx, CreateLocationParam{ShopID: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
@@ -65,6 +65,8 @@ func (u *CreateShopUsecase) Execute(ctx context.Context, param CreateShopParam)
 		txShopRepo := repositories.NewShopRepository(tx)
 		txRoleRepo := accessrepositories.NewRoleRepository(tx)
 		txStaffRepo := accessrepositories.NewStaffRepository(tx)
+		txRolePermissionRepo := accessrepositories.NewRolePermissionRepository(tx)
+		txLocationRepo := repositories.NewLocationRepository(tx)
 
 		// Create shop
 		shop := entities.Shop{
@@ -82,18 +84,31 @@ func (u *CreateShopUsecase) Execute(ctx context.Context, param CreateShopParam)
 			return fmt.Errorf("failed to create shop: %w", err)
 		}
 
-		// Create default owner role for the shop
-		ownerRole := accessentities.Role{
-			Name:        "Owner",
-			Description: "Shop owner with full access to manage the shop",
-			ShopID:      createdShop.ID,
+		// Give the shop its first branch, at the shop's own address
+		_, err = txLocationRepo.Create(ctx, entities.Location{
+			ShopID:  createdShop.ID,
+			Name:    entities.DefaultLocationName,
+			Kind:    entities.LocationKindOutlet,
+			Address: createdShop.Address,
+			Phone:   createdShop.Phone,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create location: %w", err)
 		}
 
-		createdOwnerRole, err := txRoleRepo.Create(ctx, ownerRole)
+		// Create the owner role and the default roles for the shop
+		createdOwnerRole, err := createRoleFromTemplate(ctx, txRoleRepo, txRolePermissionRepo, createdShop.ID, accessentities.OwnerRoleTemplate, true)
 		if err != nil {
 			return fmt.Errorf("failed to create owner role: %w", err)
 		}
 
+		for _, template := range accessentities.DefaultRoleTemplates {
+			_, err := createRoleFromTemplate(ctx, txRoleRepo, txRolePermissionRepo, createdShop.ID, template, false)
+			if err != nil {
+				return fmt.Errorf("failed to create %s role: %w", template.Name, err)
+			}
+		}
+
 		// Assign the authenticated user as the owner
 		staff := accessentities.Staff{
 			UserID: param.UserID,
@@ -122,3 +137,22 @@ func (u *CreateShopUsecase) Execute(ctx context.Context, param CreateShopParam)
 
 	return result, nil
 }
+
+func createRoleFromTemplate(ctx context.Context, roleRepository accessrepositories.RoleRepository, rolePermissionRepository accessrepositories.RolePermissionRepository, shopID uint64, template accessentities.RoleTemplate, isSystem bool) (accessentities.Role, error) {
+	role, err := roleRepository.Create(ctx, accessentities.Role{
+		Name:        template.Name,
+		Description: template.Description,
+		ShopID:      shopID,
+		IsSystem:    isSystem,
+	})
+	if err != nil {
+		return role, err
+	}
+
+	err = rolePermissionRepository.ReplaceForRole(ctx, role.ID, template.Permissions)
+	if err != nil {
+		return role, fmt.Errorf("failed to grant permissions: %w", err)
+	}
+
+	return role, nil
+}
@@ -17,7 +17,7 @@ import (
 )
 
 func setupCreateShopTest(t *testing.T) (*CreateShopUsecase, repositories.ShopRepository, accessrepositories.RoleRepository, accessrepositories.StaffRepository, userrepositories.UserRepository) {
-	db := testutil.SetupTestDB(t, &entities.Shop{}, &accessentities.Role{}, &accessentities.Staff{}, &userentities.User{})
+	db := testutil.SetupTestDB(t, &entities.Shop{}, &accessentities.Role{}, &accessentities.Staff{}, &userentities.User{}, &accessentities.RolePermission{}, &entities.Location{})
 	shopRepo := repositories.NewShopRepository(db)
 	roleRepo := accessrepositories.NewRoleRepository(db)
 	staffRepo := accessrepositories.NewStaffRepository(db)
@@ -82,12 +82,22 @@ func TestCreateShopUsecase_Execute(t *testing.T) {
 		assert.Equal(t, result.Shop.ID, found.ID)
 		assert.Equal(t, "My Shop", found.Name)
 
-		// Verify owner role was created for the shop
+		// Verify owner role and the default roles were created for the shop
 		roles := roleRepo.FindByShopID(ctx, result.Shop.ID)
-		require.Len(t, roles, 1)
+		require.Len(t, roles, 1+len(accessentities.DefaultRoleTemplates))
 		assert.Equal(t, "Owner", roles[0].Name)
 		assert.Equal(t, "Shop owner with full access to manage the shop", roles[0].Description)
 		assert.Equal(t, result.Shop.ID, roles[0].ShopID)
+		assert.True(t, roles[0].IsSystem)
+		for i, template := range accessentities.DefaultRoleTemplates {
+			assert.Equal(t, template.Name, roles[i+1].Name)
+			assert.False(t, roles[i+1].IsSystem)
+			assert.ElementsMatch(t, template.Permissions, accessrepositories.NewRolePermissionRepository(usecase.db).FindByRoleID(ctx, roles[i+1].ID))
+		}
+
+		// Verify the owner role holds every permission
+		permissions := accessrepositories.NewRolePermissionRepository(usecase.db).FindByRoleID(ctx, roles[0].ID)
+		assert.ElementsMatch(t, accessentities.AllPermissions(), permissions)
 
 		// Verify user was assigned as owner
 		staffs := staffRepo.FindByShopID(ctx, result.Shop.ID)
@@ -95,6 +105,13 @@ func TestCreateShopUsecase_Execute(t *testing.T) {
 		assert.Equal(t, param.UserID, staffs[0].UserID)
 		assert.Equal(t, result.Shop.ID, staffs[0].ShopID)
 		assert.Equal(t, roles[0].ID, staffs[0].RoleID)
+
+		// Verify the shop got its first location
+		locations := repositories.NewLocationRepository(usecase.db).FindByShopID(ctx, result.Shop.ID)
+		require.Len(t, locations, 1)
+		assert.Equal(t, entities.DefaultLocationName, locations[0].Name)
+		assert.Equal(t, entities.LocationKindOutlet, locations[0].Kind)
+		assert.Equal(t, param.Address, locations[0].Address)
 	})
 
 	t.Run("rolls back all changes when staff assignment fails", func(t *testing.T) {
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type ListAllShopsUsecase struct {
+	shopRepository repositories.ShopRepository
+	validator      *validator.Validate
+}
+
+func NewListAllShopsUsecase(shopRepository repositories.ShopRepository) *ListAllShopsUsecase {
+	return &ListAllShopsUsecase{
+		shopRepository: shopRepository,
+		validator:      validator.New(),
+	}
+}
+
+type ListAllShopsParam struct {
+	Search string `validate:"omitempty,max=100"`
+	Sort   string `validate:"omitempty,oneof=name -name created_at -created_at"`
+	Cursor string
+	Limit  int
+}
+
+type ListAllShopsResult struct {
+	Shops      []entities.Shop
+	NextCursor string
+}
+
+// Execute lists every shop on the platform, one page at a time. It is meant
+// for platform administrators only; shop members use ListShopsUsecase.
+func (u *ListAllShopsUsecase) Execute(ctx context.Context, param ListAllShopsParam) (*ListAllShopsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	query, err := newShopQuery(param.Search, param.Sort, param.Cursor, param.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	shops := u.shopRepository.Search(ctx, query)
+
+	result := &ListAllShopsResult{Shops: shops}
+	if len(shops) == query.Limit {
+		result.Shops = shops[:query.Limit-1]
+		result.NextCursor = repositories.ShopCursor(result.Shops[len(result.Shops)-1], query.Sort).Encode()
+	}
+	return result, nil
+}
@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+)
+
+type ListLocationsUsecase struct {
+	locationRepository repositories.LocationRepository
+}
+
+func NewListLocationsUsecase(locationRepository repositories.LocationRepository) *ListLocationsUsecase {
+	return &ListLocationsUsecase{
+		locationRepository: locationRepository,
+	}
+}
+
+type ListLocationsResult struct {
+	Locations []entities.Location
+}
+
+func (u *ListLocationsUsecase) Execute(ctx context.Context, shopID uint64) ListLocationsResult {
+	return ListLocationsResult{
+		Locations: u.locationRepository.FindByShopID(ctx, shopID),
+	}
+}
@@ -2,28 +2,92 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
-	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/go-playground/validator/v10"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
 )
 
 type ListShopsUsecase struct {
-	shopRepository repositories.ShopRepository
+	staffRepository accessrepositories.StaffRepository
+	validator       *validator.Validate
 }
 
-func NewListShopsUsecase(shopRepository repositories.ShopRepository) *ListShopsUsecase {
+func NewListShopsUsecase(staffRepository accessrepositories.StaffRepository) *ListShopsUsecase {
 	return &ListShopsUsecase{
-		shopRepository: shopRepository,
+		staffRepository: staffRepository,
+		validator:       validator.New(),
 	}
 }
 
+type ListShopsParam struct {
+	UserID uint64 `validate:"required"`
+	Search string `validate:"omitempty,max=100"`
+	Sort   string `validate:"omitempty,oneof=name -name created_at -created_at"`
+	Cursor string
+	Limit  int
+}
+
 type ListShopsResult struct {
-	Shops []entities.Shop
+	// Memberships holds the staff record of the user in every listed shop,
+	// with the shop and the role of the user in it.
+	Memberships []accessentities.Staff
+	NextCursor  string
 }
 
-func (u *ListShopsUsecase) Execute(ctx context.Context) *ListShopsResult {
-	shops := u.shopRepository.All(ctx)
-	return &ListShopsResult{
-		Shops: shops,
+// Execute lists the shops the user is a staff member of, one page at a
+// time. NextCursor is empty on the last page.
+func (u *ListShopsUsecase) Execute(ctx context.Context, param ListShopsParam) (*ListShopsResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	query, err := newShopQuery(param.Search, param.Sort, param.Cursor, param.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := u.staffRepository.FindMemberships(ctx, param.UserID, query)
+
+	result := &ListShopsResult{Memberships: memberships}
+	if len(memberships) == query.Limit {
+		result.Memberships = memberships[:query.Limit-1]
+		result.NextCursor = repositories.ShopCursor(*result.Memberships[len(result.Memberships)-1].Shop, query.Sort).Encode()
+	}
+	return result, nil
+}
+
+// newShopQuery builds the repository query for a page of shops. The limit
+// is one past the page size so callers can tell whether another page
+// follows.
+func newShopQuery(search, sort, cursor string, limit int) (repositories.ShopQuery, error) {
+	if sort == "" {
+		sort = repositories.ShopSortName
+	}
+
+	query := repositories.ShopQuery{
+		Search: strings.TrimSpace(search),
+		Sort:   sort,
+		Limit:  pagination.Limit(limit) + 1,
+	}
+
+	if cursor != "" {
+		after, err := pagination.Decode(cursor)
+		if err != nil || after.Sort != sort {
+			return query, errors.New("validation failed: invalid cursor")
+		}
+		query.After = &after
 	}
+	return query, nil
 }
@@ -2,90 +2,187 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/testutil"
 )
 
-func setupListShopsTest(t *testing.T) (*ListShopsUsecase, repositories.ShopRepository) {
-	db := testutil.SetupTestDB(t, &entities.Shop{})
+type listShopsFixture struct {
+	listShops    *ListShopsUsecase
+	listAllShops *ListAllShopsUsecase
+	createShop   *CreateShopUsecase
+	shopRepo     repositories.ShopRepository
+	userRepo     userrepositories.UserRepository
+}
+
+func setupListShopsTest(t *testing.T) *listShopsFixture {
+	db := testutil.SetupTestDB(t, &entities.Shop{}, &accessentities.Role{}, &accessentities.Staff{}, &userentities.User{}, &accessentities.RolePermission{}, &entities.Location{})
 	shopRepo := repositories.NewShopRepository(db)
-	usecase := NewListShopsUsecase(shopRepo)
-	return usecase, shopRepo
+	roleRepo := accessrepositories.NewRoleRepository(db)
+	staffRepo := accessrepositories.NewStaffRepository(db)
+	return &listShopsFixture{
+		listShops:    NewListShopsUsecase(staffRepo),
+		listAllShops: NewListAllShopsUsecase(shopRepo),
+		createShop:   NewCreateShopUsecase(db, shopRepo, roleRepo, staffRepo),
+		shopRepo:     shopRepo,
+		userRepo:     userrepositories.NewUserRepository(db),
+	}
+}
+
+func (f *listShopsFixture) user(t *testing.T, email, phone string) userentities.User {
+	user, err := f.userRepo.Create(context.Background(), userentities.User{FullName: "Test User", Email: email, Phone: phone, Password: "hashedpassword"})
+	require.NoError(t, err)
+	return user
+}
+
+func (f *listShopsFixture) shop(t *testing.T, ownerID uint64, name string) entities.Shop {
+	result, err := f.createShop.Execute(context.Background(), CreateShopParam{
+		UserID:      ownerID,
+		Name:        name,
+		Description: "A shop used in tests",
+		Address:     "123 Main St",
+		Phone:       "1234567890",
+		Email:       "shop@example.com",
+		Website:     "https://shop.example.com",
+		Logo:        "logo.png",
+	})
+	require.NoError(t, err)
+	return *result.Shop
 }
 
 func TestListShopsUsecase_Execute(t *testing.T) {
-	t.Run("returns empty list when no shops exist", func(t *testing.T) {
-		ctx := context.Background()
-		usecase, _ := setupListShopsTest(t)
+	t.Run("returns empty list when the user has no shops", func(t *testing.T) {
+		fixture := setupListShopsTest(t)
+		user := fixture.user(t, "owner@example.com", "1111111111")
+
+		result, err := fixture.listShops.Execute(context.Background(), ListShopsParam{UserID: user.ID})
+		require.NoError(t, err)
+		assert.Empty(t, result.Memberships)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("returns only the shops of the user with their role", func(t *testing.T) {
+		fixture := setupListShopsTest(t)
+		owner := fixture.user(t, "owner@example.com", "1111111111")
+		other := fixture.user(t, "other@example.com", "2222222222")
+		fixture.shop(t, owner.ID, "Shop Two")
+		fixture.shop(t, owner.ID, "Shop One")
+		fixture.shop(t, other.ID, "Not Mine")
+
+		result, err := fixture.listShops.Execute(context.Background(), ListShopsParam{UserID: owner.ID})
+		require.NoError(t, err)
+		require.Len(t, result.Memberships, 2)
+		assert.Equal(t, "Shop One", result.Memberships[0].Shop.Name)
+		assert.Equal(t, "Shop Two", result.Memberships[1].Shop.Name)
+		require.NotNil(t, result.Memberships[0].Role)
+		assert.Equal(t, accessentities.OwnerRoleName, result.Memberships[0].Role.Name)
+	})
 
-		result := usecase.Execute(ctx)
-		assert.NotNil(t, result)
-		assert.Empty(t, result.Shops)
+	t.Run("searches and sorts", func(t *testing.T) {
+		fixture := setupListShopsTest(t)
+		owner := fixture.user(t, "owner@example.com", "1111111111")
+		fixture.shop(t, owner.ID, "Corner Bakery")
+		fixture.shop(t, owner.ID, "Bakery Express")
+		fixture.shop(t, owner.ID, "Hardware")
+
+		result, err := fixture.listShops.Execute(context.Background(), ListShopsParam{UserID: owner.ID, Search: "bakery", Sort: "-name"})
+		require.NoError(t, err)
+		require.Len(t, result.Memberships, 2)
+		assert.Equal(t, "Corner Bakery", result.Memberships[0].Shop.Name)
+		assert.Equal(t, "Bakery Express", result.Memberships[1].Shop.Name)
 	})
 
-	t.Run("returns all shops", func(t *testing.T) {
+	t.Run("pages through the shops", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, shopRepo := setupListShopsTest(t)
-
-		shop1 := entities.Shop{
-			Name:        "Shop One",
-			Description: "First shop description",
-			Address:     "123 Main St",
-			Phone:       "1234567890",
-			Email:       "shop1@example.com",
-			Website:     "https://shop1.com",
-			Logo:        "logo1.png",
+		fixture := setupListShopsTest(t)
+		owner := fixture.user(t, "owner@example.com", "1111111111")
+		for i := 1; i <= 5; i++ {
+			fixture.shop(t, owner.ID, fmt.Sprintf("Shop %d", i))
 		}
-		shop2 := entities.Shop{
-			Name:        "Shop Two",
-			Description: "Second shop description",
-			Address:     "456 Oak Ave",
-			Phone:       "0987654321",
-			Email:       "shop2@example.com",
-			Website:     "https://shop2.com",
-			Logo:        "logo2.png",
+
+		var names []string
+		param := ListShopsParam{UserID: owner.ID, Sort: "created_at", Limit: 2}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 3)
+			result, err := fixture.listShops.Execute(ctx, param)
+			require.NoError(t, err)
+			for _, membership := range result.Memberships {
+				names = append(names, membership.Shop.Name)
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			param.Cursor = result.NextCursor
 		}
+		assert.Equal(t, []string{"Shop 1", "Shop 2", "Shop 3", "Shop 4", "Shop 5"}, names)
+	})
 
-		_, err := shopRepo.Create(ctx, shop1)
-		require.NoError(t, err)
-		_, err = shopRepo.Create(ctx, shop2)
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		ctx := context.Background()
+		fixture := setupListShopsTest(t)
+		owner := fixture.user(t, "owner@example.com", "1111111111")
+		fixture.shop(t, owner.ID, "Shop 1")
+		fixture.shop(t, owner.ID, "Shop 2")
+
+		_, err := fixture.listShops.Execute(ctx, ListShopsParam{UserID: owner.ID, Sort: "revenue"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+
+		_, err = fixture.listShops.Execute(ctx, ListShopsParam{UserID: owner.ID, Cursor: "garbage"})
+		assert.EqualError(t, err, "validation failed: invalid cursor")
+
+		result, err := fixture.listShops.Execute(ctx, ListShopsParam{UserID: owner.ID, Limit: 1})
 		require.NoError(t, err)
+		require.NotEmpty(t, result.NextCursor)
+		_, err = fixture.listShops.Execute(ctx, ListShopsParam{UserID: owner.ID, Sort: "-name", Cursor: result.NextCursor})
+		assert.EqualError(t, err, "validation failed: invalid cursor")
+	})
+}
+
+func TestListAllShopsUsecase_Execute(t *testing.T) {
+	t.Run("returns the shops of every user", func(t *testing.T) {
+		fixture := setupListShopsTest(t)
+		owner := fixture.user(t, "owner@example.com", "1111111111")
+		other := fixture.user(t, "other@example.com", "2222222222")
+		fixture.shop(t, owner.ID, "Shop One")
+		fixture.shop(t, other.ID, "Shop Two")
 
-		result := usecase.Execute(ctx)
-		assert.NotNil(t, result)
-		assert.Len(t, result.Shops, 2)
+		result, err := fixture.listAllShops.Execute(context.Background(), ListAllShopsParam{})
+		require.NoError(t, err)
+		require.Len(t, result.Shops, 2)
 		assert.Equal(t, "Shop One", result.Shops[0].Name)
 		assert.Equal(t, "Shop Two", result.Shops[1].Name)
+		assert.Empty(t, result.NextCursor)
 	})
 
-	t.Run("excludes soft deleted shops", func(t *testing.T) {
+	t.Run("pages and excludes soft deleted shops", func(t *testing.T) {
 		ctx := context.Background()
-		usecase, shopRepo := setupListShopsTest(t)
-
-		shop := entities.Shop{
-			Name:        "Deleted Shop",
-			Description: "Deleted shop description",
-			Address:     "789 Elm St",
-			Phone:       "1111111111",
-			Email:       "deleted@example.com",
-			Website:     "https://deleted.com",
-			Logo:        "deleted.png",
-		}
+		fixture := setupListShopsTest(t)
+		owner := fixture.user(t, "owner@example.com", "1111111111")
+		fixture.shop(t, owner.ID, "Alpha")
+		deleted := fixture.shop(t, owner.ID, "Beta")
+		fixture.shop(t, owner.ID, "Gamma")
+		require.NoError(t, fixture.shopRepo.Delete(ctx, deleted))
 
-		created, err := shopRepo.Create(ctx, shop)
+		first, err := fixture.listAllShops.Execute(ctx, ListAllShopsParam{Limit: 1})
 		require.NoError(t, err)
+		require.Len(t, first.Shops, 1)
+		assert.Equal(t, "Alpha", first.Shops[0].Name)
 
-		err = shopRepo.Delete(ctx, created)
+		second, err := fixture.listAllShops.Execute(ctx, ListAllShopsParam{Limit: 1, Cursor: first.NextCursor})
 		require.NoError(t, err)
-
-		result := usecase.Execute(ctx)
-		assert.NotNil(t, result)
-		assert.Empty(t, result.Shops)
+		require.Len(t, second.Shops, 1)
+		assert.Equal(t, "Gamma", second.Shops[0].Name)
+		assert.Empty(t, second.NextCursor)
 	})
 }
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateLocationUsecase struct {
+	locationRepository repositories.LocationRepository
+	validator          *validator.Validate
+}
+
+func NewUpdateLocationUsecase(locationRepository repositories.LocationRepository) *UpdateLocationUsecase {
+	return &UpdateLocationUsecase{
+		locationRepository: locationRepository,
+		validator:          validator.New(),
+	}
+}
+
+type UpdateLocationParam struct {
+	ShopID     uint64 `validate:"required"`
+	LocationID uint64 `validate:"required"`
+	Name       string `validate:"required,min=1,max=100"`
+	Kind       string `validate:"required,oneof=outlet warehouse"`
+	Address    string `validate:"max=255"`
+	Phone      string `validate:"max=20"`
+}
+
+type UpdateLocationResult struct {
+	Location *entities.Location
+}
+
+// Execute replaces the details of a location of the shop. Its stock stays
+// where it is.
+func (u *UpdateLocationUsecase) Execute(ctx context.Context, param UpdateLocationParam) (*UpdateLocationResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	location, err := u.locationRepository.FindByID(ctx, param.LocationID)
+	if err != nil {
+		if err.Error() == "location not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get location: %w", err)
+	}
+	if location.ShopID != param.ShopID {
+		return nil, errors.New("location not found")
+	}
+
+	name := strings.TrimSpace(param.Name)
+	if err := ensureLocationNameAvailable(ctx, u.locationRepository, param.ShopID, name, location.ID); err != nil {
+		return nil, err
+	}
+
+	location.Name = name
+	location.Kind = param.Kind
+	location.Address = strings.TrimSpace(param.Address)
+	location.Phone = strings.TrimSpace(param.Phone)
+
+	updated, err := u.locationRepository.Update(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update location: %w", err)
+	}
+
+	return &UpdateLocationResult{
+		Location: &updated,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+)
+
+func TestUpdateLocationUsecase_Execute(t *testing.T) {
+	t.Run("replaces the details of a location", func(t *testing.T) {
+		ctx := context.Background()
+		locationRepo := setupLocationTest(t)
+		created, err := NewCreateLocationUsecase(locationRepo).Execute(ctx, CreateLocationParam{ShopID: 1, Name: "Back Room"})
+		require.NoError(t, err)
+
+		result, err := NewUpdateLocationUsecase(locationRepo).Execute(ctx, UpdateLocationParam{
+			ShopID:     1,
+			LocationID: created.Location.ID,
+			Name:       "Depot",
+			Kind:       entities.LocationKindWarehouse,
+			Address:    "1 Dock Road",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Depot", result.Location.Name)
+		assert.True(t, result.Location.IsWarehouse())
+
+		found, err := locationRepo.FindByID(ctx, created.Location.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "1 Dock Road", found.Address)
+	})
+
+	t.Run("keeps names unique within the shop", func(t *testing.T) {
+		ctx := context.Background()
+		locationRepo := setupLocationTest(t)
+		usecase := NewCreateLocationUsecase(locationRepo)
+		main, err := usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: "Main"})
+		require.NoError(t, err)
+		_, err = usecase.Execute(ctx, CreateLocationParam{ShopID: 1, Name: "Depot"})
+		require.NoError(t, err)
+
+		_, err = NewUpdateLocationUsecase(locationRepo).Execute(ctx, UpdateLocationParam{ShopID: 1, LocationID: main.Location.ID, Name: "depot", Kind: entities.LocationKindOutlet})
+		assert.EqualError(t, err, "location with this name already exists")
+
+		_, err = NewUpdateLocationUsecase(locationRepo).Execute(ctx, UpdateLocationParam{ShopID: 1, LocationID: main.Location.ID, Name: "MAIN", Kind: entities.LocationKindOutlet})
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error when the location belongs to another shop", func(t *testing.T) {
+		ctx := context.Background()
+		locationRepo := setupLocationTest(t)
+		created, err := NewCreateLocationUsecase(locationRepo).Execute(ctx, CreateLocationParam{ShopID: 2, Name: "Main"})
+		require.NoError(t, err)
+
+		_, err = NewUpdateLocationUsecase(locationRepo).Execute(ctx, UpdateLocationParam{ShopID: 1, LocationID: created.Location.ID, Name: "Main", Kind: entities.LocationKindOutlet})
+		assert.EqualError(t, err, "location not found")
+	})
+}
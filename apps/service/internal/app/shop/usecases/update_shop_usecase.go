@@ -48,22 +48,19 @@ func (u *UpdateShopUsecase) Execute(ctx context.Context, param UpdateShopParam)
 		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
 	}
 
-	// Check if shop exists
-	_, err := u.shopRepository.FindByID(ctx, param.ID)
+	// Fields the caller does not control, like the suspension, are kept.
+	shop, err := u.shopRepository.FindByID(ctx, param.ID)
 	if err != nil {
 		return nil, errors.New("shop not found")
 	}
 
-	shop := entities.Shop{
-		ID:          param.ID,
-		Name:        param.Name,
-		Description: param.Description,
-		Address:     param.Address,
-		Phone:       param.Phone,
-		Email:       param.Email,
-		Website:     param.Website,
-		Logo:        param.Logo,
-	}
+	shop.Name = param.Name
+	shop.Description = param.Description
+	shop.Address = param.Address
+	shop.Phone = param.Phone
+	shop.Email = param.Email
+	shop.Website = param.Website
+	shop.Logo = param.Logo
 
 	updatedShop, err := u.shopRepository.Update(ctx, shop)
 	if err != nil {
@@ -6,6 +6,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// User is an account on the platform. IsAdmin marks platform operators, who
+// may use the /admin endpoints; it is not related to roles within shops.
+// A suspended user cannot sign in until an administrator restores them.
 type User struct {
 	ID        uint64         `gorm:"primaryKey;column:id" json:"id"`
 	FullName  string         `gorm:"column:full_name;not null" json:"full_name"`
@@ -15,8 +18,24 @@ type User struct {
 	CreatedAt time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
+
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at" json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `gorm:"column:phone_verified_at" json:"phone_verified_at"`
+
+	IsAdmin     bool       `gorm:"column:is_admin;not null;default:false" json:"is_admin"`
+	SuspendedAt *time.Time `gorm:"column:suspended_at" json:"suspended_at"`
 }
 
 func (User) TableName() string {
 	return "users"
 }
+
+// IsVerified reports whether the user has proven ownership of at least one
+// contact channel.
+func (u User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil || u.PhoneVerifiedAt != nil
+}
+
+func (u User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
@@ -4,14 +4,29 @@ import (
 	"context"
 
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
 )
 
+// UserQuery filters and pages a list of users. Search matches the full
+// name, email or phone case-insensitively. Users are listed by ID, and
+// After, when set, resumes the list past the user it points to.
+type UserQuery struct {
+	Search string
+	After  *pagination.Cursor
+	Limit  int
+}
+
 type UserRepository interface {
 	All(ctx context.Context) []entities.User
+	Search(ctx context.Context, query UserQuery) []entities.User
 	FindByID(ctx context.Context, id uint64) (entities.User, error)
 	FindByPhone(ctx context.Context, phone string) (entities.User, error)
 	FindByEmail(ctx context.Context, email string) (entities.User, error)
 	Create(ctx context.Context, user entities.User) (entities.User, error)
 	Update(ctx context.Context, user entities.User) (entities.User, error)
+	// ReplacePasswordHash swaps the stored hash only while it still equals
+	// current, so an upgrade computed at login never undoes a password change
+	// that happened in between. It reports whether the hash was replaced.
+	ReplacePasswordHash(ctx context.Context, id uint64, current string, replacement string) (bool, error)
 	Delete(ctx context.Context, user entities.User) error
 }
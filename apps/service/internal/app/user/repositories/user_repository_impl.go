@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -25,6 +26,24 @@ func (r *userRepository) All(ctx context.Context) []entities.User {
 	return users
 }
 
+func (r *userRepository) Search(ctx context.Context, query UserQuery) []entities.User {
+	db := r.db.WithContext(ctx)
+	if search := strings.TrimSpace(query.Search); search != "" {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(search)) + "%"
+		db = db.Where(`(LOWER(full_name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\' OR phone LIKE ? ESCAPE '\')`, pattern, pattern, pattern)
+	}
+	if query.After != nil {
+		db = db.Where("id > ?", query.After.ID)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var users []entities.User
+	db.Order("id").Find(&users)
+	return users
+}
+
 func (r *userRepository) FindByID(ctx context.Context, id uint64) (entities.User, error) {
 	var user entities.User
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
@@ -77,6 +96,17 @@ func (r *userRepository) Update(ctx context.Context, user entities.User) (entiti
 	return user, nil
 }
 
+func (r *userRepository) ReplacePasswordHash(ctx context.Context, id uint64, current string, replacement string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.User{}).
+		Where("id = ? AND password = ?", id, current).
+		Update("password", replacement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, user entities.User) error {
 	return r.db.WithContext(ctx).Delete(&user).Error
 }
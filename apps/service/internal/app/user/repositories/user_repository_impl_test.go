@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/pagination"
 	"github.com/reno1r/weiss/apps/service/internal/testutil"
 )
 
@@ -73,6 +74,44 @@ func TestUserRepository_All(t *testing.T) {
 	})
 }
 
+func TestUserRepository_Search(t *testing.T) {
+	setup := func(t *testing.T) UserRepository {
+		repo := NewUserRepository(testutil.SetupTestDB(t, &entities.User{}))
+		for _, user := range []entities.User{
+			{FullName: "John Doe", Phone: "1234567890", Email: "john@example.com", Password: "password"},
+			{FullName: "Jane Smith", Phone: "0987654321", Email: "jane@shop.test", Password: "password"},
+			{FullName: "Johnny Walker", Phone: "5555555555", Email: "walker@example.com", Password: "password"},
+		} {
+			_, err := repo.Create(context.Background(), user)
+			require.NoError(t, err)
+		}
+		return repo
+	}
+
+	t.Run("matches name, email or phone", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setup(t)
+
+		assert.Len(t, repo.Search(ctx, UserQuery{Search: "JOHN"}), 2)
+		assert.Len(t, repo.Search(ctx, UserQuery{Search: "shop.test"}), 1)
+		assert.Len(t, repo.Search(ctx, UserQuery{Search: "0987"}), 1)
+		assert.Len(t, repo.Search(ctx, UserQuery{}), 3)
+	})
+
+	t.Run("pages by ID", func(t *testing.T) {
+		ctx := context.Background()
+		repo := setup(t)
+
+		first := repo.Search(ctx, UserQuery{Search: "john", Limit: 1})
+		require.Len(t, first, 1)
+		assert.Equal(t, "John Doe", first[0].FullName)
+
+		second := repo.Search(ctx, UserQuery{Search: "john", After: &pagination.Cursor{ID: first[0].ID}})
+		require.Len(t, second, 1)
+		assert.Equal(t, "Johnny Walker", second[0].FullName)
+	})
+}
+
 func TestUserRepository_FindByPhone(t *testing.T) {
 	t.Run("returns user when found", func(t *testing.T) {
 		db := testutil.SetupTestDB(t, &entities.User{})
@@ -315,6 +354,52 @@ func TestUserRepository_Update(t *testing.T) {
 	})
 }
 
+func TestUserRepository_ReplacePasswordHash(t *testing.T) {
+	t.Run("replaces the hash when it is unchanged", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.User{})
+		repo := NewUserRepository(db)
+
+		created, err := repo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "oldhash",
+		})
+		require.NoError(t, err)
+
+		replaced, err := repo.ReplacePasswordHash(ctx, created.ID, "oldhash", "newhash")
+		require.NoError(t, err)
+		assert.True(t, replaced)
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "newhash", found.Password)
+	})
+
+	t.Run("leaves a concurrently changed hash alone", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &entities.User{})
+		repo := NewUserRepository(db)
+
+		created, err := repo.Create(ctx, entities.User{
+			FullName: "John Doe",
+			Phone:    "1234567890",
+			Email:    "john@example.com",
+			Password: "changedhash",
+		})
+		require.NoError(t, err)
+
+		replaced, err := repo.ReplacePasswordHash(ctx, created.ID, "oldhash", "newhash")
+		require.NoError(t, err)
+		assert.False(t, replaced)
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "changedhash", found.Password)
+	})
+}
+
 func TestUserRepository_Delete(t *testing.T) {
 	t.Run("soft deletes user successfully", func(t *testing.T) {
 		ctx := context.Background()
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/validationutil"
+)
+
+type UpdateProfileUsecase struct {
+	userRepository repositories.UserRepository
+	validator      *validator.Validate
+}
+
+func NewUpdateProfileUsecase(userRepository repositories.UserRepository) *UpdateProfileUsecase {
+	return &UpdateProfileUsecase{
+		userRepository: userRepository,
+		validator:      validator.New(),
+	}
+}
+
+// UpdateProfileParam holds the profile fields a user may change directly.
+// Nil fields are left untouched. Email and phone are changed through a
+// separate flow that verifies the new contact first.
+type UpdateProfileParam struct {
+	UserID   uint64  `validate:"required"`
+	FullName *string `validate:"omitempty,min=2,max=255"`
+}
+
+type UpdateProfileResult struct {
+	User *entities.User
+}
+
+func (u *UpdateProfileUsecase) Execute(ctx context.Context, param UpdateProfileParam) (*UpdateProfileResult, error) {
+	if err := u.validator.Struct(param); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, validationutil.GetValidationErrorMessage(err))
+		}
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(validationErrors, ", "))
+	}
+
+	user, err := u.userRepository.FindByID(ctx, param.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if param.FullName != nil {
+		user.FullName = strings.TrimSpace(*param.FullName)
+	}
+
+	updatedUser, err := u.userRepository.Update(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return &UpdateProfileResult{
+		User: &updatedUser,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+func setupUpdateProfileTest(t *testing.T) (*UpdateProfileUsecase, repositories.UserRepository, entities.User) {
+	db := testutil.SetupTestDB(t, &entities.User{})
+	userRepo := repositories.NewUserRepository(db)
+
+	user, err := userRepo.Create(context.Background(), entities.User{
+		FullName: "John Doe",
+		Phone:    "1234567890",
+		Email:    "john@example.com",
+		Password: "hashedpassword",
+	})
+	require.NoError(t, err)
+
+	return NewUpdateProfileUsecase(userRepo), userRepo, user
+}
+
+func TestUpdateProfileUsecase_Execute(t *testing.T) {
+	t.Run("updates full name", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, userRepo, user := setupUpdateProfileTest(t)
+
+		fullName := "Johnny Doe"
+		result, err := usecase.Execute(ctx, UpdateProfileParam{UserID: user.ID, FullName: &fullName})
+		require.NoError(t, err)
+		assert.Equal(t, "Johnny Doe", result.User.FullName)
+
+		found, err := userRepo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Johnny Doe", found.FullName)
+		assert.Equal(t, "john@example.com", found.Email)
+		assert.Equal(t, "hashedpassword", found.Password)
+	})
+
+	t.Run("leaves omitted fields untouched", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, user := setupUpdateProfileTest(t)
+
+		result, err := usecase.Execute(ctx, UpdateProfileParam{UserID: user.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "John Doe", result.User.FullName)
+	})
+
+	t.Run("validates full name length", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, user := setupUpdateProfileTest(t)
+
+		fullName := "J"
+		_, err := usecase.Execute(ctx, UpdateProfileParam{UserID: user.ID, FullName: &fullName})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("returns error when user not found", func(t *testing.T) {
+		ctx := context.Background()
+		usecase, _, _ := setupUpdateProfileTest(t)
+
+		_, err := usecase.Execute(ctx, UpdateProfileParam{UserID: 999})
+		assert.Error(t, err)
+		assert.Equal(t, "user not found", err.Error())
+	})
+}
@@ -17,14 +17,52 @@ type Config struct {
 	DatabaseMaxConnections      int    `mapstructure:"DB_MAX_CONNECTIONS"`
 	DatabaseMaxIdleConnections  int    `mapstructure:"DB_MAX_IDLE_CONNECTIONS"`
 	DatabaseConnectionTimeoutMs int    `mapstructure:"DB_CONNECTION_TIMEOUT_MS"`
+	DatabaseRowLevelSecurity    bool   `mapstructure:"DB_ROW_LEVEL_SECURITY"`
+
+	PasswordHashAlgorithm string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	BcryptCost            int    `mapstructure:"BCRYPT_COST"`
+	Argon2Memory          int    `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations      int    `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism     int    `mapstructure:"ARGON2_PARALLELISM"`
 
-	BcryptCost         int    `mapstructure:"BCRYPT_COST"`
 	CorsAllowedOrigins string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	TrustedProxies     string `mapstructure:"TRUSTED_PROXIES"`
+
+	JwtAlgorithm       string `mapstructure:"JWT_ALGORITHM"`
+	JwtSecret          string `mapstructure:"JWT_SECRET"`
+	JwtActiveKeyID     string `mapstructure:"JWT_ACTIVE_KEY_ID"`
+	JwtPrivateKeyFiles string `mapstructure:"JWT_PRIVATE_KEY_FILES"`
+	JwtIssuer          string `mapstructure:"JWT_ISSUER"`
+	JwtAccessExpires   string `mapstructure:"JWT_ACCESS_EXPIRES_IN"`
+	JwtRefreshExpires  string `mapstructure:"JWT_REFRESH_EXPIRES_IN"`
+
+	MailDriver   string `mapstructure:"MAIL_DRIVER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailFileDir  string `mapstructure:"MAIL_FILE_DIR"`
+	SmtpHost     string `mapstructure:"SMTP_HOST"`
+	SmtpPort     int    `mapstructure:"SMTP_PORT"`
+	SmtpUsername string `mapstructure:"SMTP_USERNAME"`
+	SmtpPassword string `mapstructure:"SMTP_PASSWORD"`
+	SmsDriver    string `mapstructure:"SMS_DRIVER"`
+
+	PasswordResetURL     string `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetExpires string `mapstructure:"PASSWORD_RESET_EXPIRES_IN"`
+
+	InvitationURL     string `mapstructure:"INVITATION_URL"`
+	InvitationExpires string `mapstructure:"INVITATION_EXPIRES_IN"`
+
+	VerificationRequiredFor    string `mapstructure:"VERIFICATION_REQUIRED_FOR"`
+	VerificationExpires        string `mapstructure:"VERIFICATION_EXPIRES_IN"`
+	VerificationResendInterval string `mapstructure:"VERIFICATION_RESEND_INTERVAL"`
+
+	LoginAttemptStore       string `mapstructure:"LOGIN_ATTEMPT_STORE"`
+	LoginMaxAttempts        int    `mapstructure:"LOGIN_MAX_ATTEMPTS"`
+	LoginMaxAttemptsPerIP   int    `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_IP"`
+	LoginLockoutDuration    string `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginMaxLockoutDuration string `mapstructure:"LOGIN_MAX_LOCKOUT_DURATION"`
+	LoginAttemptWindow      string `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
 
-	JwtSecret         string `mapstructure:"JWT_SECRET"`
-	JwtIssuer         string `mapstructure:"JWT_ISSUER"`
-	JwtAccessExpires  string `mapstructure:"JWT_ACCESS_EXPIRES_IN"`
-	JwtRefreshExpires string `mapstructure:"JWT_REFRESH_EXPIRES_IN"`
+	IdempotencyStore string `mapstructure:"IDEMPOTENCY_STORE"`
 }
 
 var config *Config
@@ -47,6 +47,15 @@ func NewDatabase(config *config.Config) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	// In row-level security mode the same connections are reached through a
+	// pool that knows about the transactions of RunAsTenant.
+	if config.DatabaseRowLevelSecurity {
+		db, err = gorm.Open(postgres.New(postgres.Config{Conn: newTenantConnPool(sqlDB)}), gormConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to enable row-level security mode: %w", err)
+		}
+	}
+
 	return &Database{
 		db:     db,
 		config: config,
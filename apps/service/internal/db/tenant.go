@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Tenant is the shop, and the user within it, that a unit of work acts for.
+// In row-level security mode they are exposed to Postgres as the
+// app.current_shop_id and app.current_user_id settings, which the policies
+// of the tenant tables compare rows against.
+type Tenant struct {
+	ShopID uint64
+	UserID uint64
+}
+
+// tenantSettingsQuery applies a tenant to the current transaction only, so
+// nothing is left behind on the connection once it returns to the pool.
+var tenantSettingsQuery = "SELECT set_config('app.current_shop_id', $1, true), set_config('app.current_user_id', $2, true)"
+
+// RunAsTenant runs fn in a transaction carrying tenant's settings and
+// commits it when fn succeeds. Every query made with the context passed to
+// fn joins that transaction, and transactions started inside it become
+// savepoints. Without row-level security mode fn simply runs with ctx.
+func RunAsTenant(ctx context.Context, db *gorm.DB, tenant Tenant, fn func(ctx context.Context) error) (err error) {
+	pool, ok := db.ConnPool.(*tenantConnPool)
+	if !ok {
+		return fn(ctx)
+	}
+
+	tx, err := pool.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tenant transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tenantSettingsQuery, strconv.FormatUint(tenant.ShopID, 10), strconv.FormatUint(tenant.UserID, 10)); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to apply tenant: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, tenantTxKey{}, &tenantTx{tx: tx})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tenant transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+type tenantTxKey struct{}
+
+// tenantTx is the transaction RunAsTenant opened for a context.
+type tenantTx struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+// tenantConnPool sends the queries of a context prepared by RunAsTenant to
+// its transaction and everything else to the pool.
+type tenantConnPool struct {
+	db *sql.DB
+}
+
+func newTenantConnPool(db *sql.DB) *tenantConnPool {
+	return &tenantConnPool{db: db}
+}
+
+func (p *tenantConnPool) conn(ctx context.Context) gorm.ConnPool {
+	if current, ok := ctx.Value(tenantTxKey{}).(*tenantTx); ok {
+		return current.tx
+	}
+	return p.db
+}
+
+func (p *tenantConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.conn(ctx).PrepareContext(ctx, query)
+}
+
+func (p *tenantConnPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return p.conn(ctx).ExecContext(ctx, query, args...)
+}
+
+func (p *tenantConnPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return p.conn(ctx).QueryContext(ctx, query, args...)
+}
+
+func (p *tenantConnPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return p.conn(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx implements gorm.ConnPoolBeginner. Inside RunAsTenant it opens a
+// savepoint instead of a second transaction, which would not see the
+// tenant's settings nor the work done so far.
+func (p *tenantConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	current, ok := ctx.Value(tenantTxKey{}).(*tenantTx)
+	if !ok {
+		return p.db.BeginTx(ctx, opts)
+	}
+
+	current.savepoints++
+	savepoint := &tenantSavepoint{tx: current.tx, name: fmt.Sprintf("tenant_savepoint_%d", current.savepoints)}
+	if _, err := current.tx.ExecContext(ctx, "SAVEPOINT "+savepoint.name); err != nil {
+		return nil, err
+	}
+	return savepoint, nil
+}
+
+// GetDBConn implements gorm.GetDBConnector so that gorm.DB.DB keeps working.
+func (p *tenantConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// tenantSavepoint stands in for a transaction begun inside RunAsTenant.
+type tenantSavepoint struct {
+	tx   *sql.Tx
+	name string
+}
+
+func (s *tenantSavepoint) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return s.tx.PrepareContext(ctx, query)
+}
+
+func (s *tenantSavepoint) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return s.tx.ExecContext(ctx, query, args...)
+}
+
+func (s *tenantSavepoint) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return s.tx.QueryContext(ctx, query, args...)
+}
+
+func (s *tenantSavepoint) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return s.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (s *tenantSavepoint) Commit() error {
+	_, err := s.tx.Exec("RELEASE SAVEPOINT " + s.name)
+	return err
+}
+
+func (s *tenantSavepoint) Rollback() error {
+	_, err := s.tx.Exec("ROLLBACK TO SAVEPOINT " + s.name)
+	return err
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/reno1r/weiss/apps/service/internal/testutil"
+)
+
+type tenantTestRecord struct {
+	ID   uint64 `gorm:"primaryKey"`
+	Name string
+}
+
+// setupTenantTest returns a database in row-level security mode. SQLite has
+// no settings, so the tenant is applied with a query that only checks the
+// arguments bind.
+func setupTenantTest(t *testing.T) *gorm.DB {
+	original := tenantSettingsQuery
+	tenantSettingsQuery = "SELECT ?, ?"
+	t.Cleanup(func() { tenantSettingsQuery = original })
+
+	sqlDB, err := testutil.SetupTestDB(t, &tenantTestRecord{}).DB()
+	require.NoError(t, err)
+	// Every connection to :memory: opens a database of its own.
+	sqlDB.SetMaxOpenConns(1)
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: newTenantConnPool(sqlDB)}, &gorm.Config{})
+	require.NoError(t, err)
+	return db
+}
+
+func countTenantTestRecords(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&tenantTestRecord{}).Count(&count).Error)
+	return count
+}
+
+func TestRunAsTenant(t *testing.T) {
+	tenant := Tenant{ShopID: 1, UserID: 2}
+
+	t.Run("commits the work of fn", func(t *testing.T) {
+		ctx := context.Background()
+		db := setupTenantTest(t)
+
+		err := RunAsTenant(ctx, db, tenant, func(ctx context.Context) error {
+			require.NoError(t, db.WithContext(ctx).Create(&tenantTestRecord{Name: "first"}).Error)
+			require.NoError(t, db.WithContext(ctx).Create(&tenantTestRecord{Name: "second"}).Error)
+
+			var count int64
+			require.NoError(t, db.WithContext(ctx).Model(&tenantTestRecord{}).Count(&count).Error)
+			assert.Equal(t, int64(2), count)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), countTenantTestRecords(t, db))
+	})
+
+	t.Run("rolls back everything when fn fails", func(t *testing.T) {
+		ctx := context.Background()
+		db := setupTenantTest(t)
+
+		err := RunAsTenant(ctx, db, tenant, func(ctx context.Context) error {
+			require.NoError(t, db.WithContext(ctx).Create(&tenantTestRecord{Name: "first"}).Error)
+			return errors.New("handler failed")
+		})
+		assert.EqualError(t, err, "handler failed")
+		assert.Zero(t, countTenantTestRecords(t, db))
+	})
+
+	t.Run("turns transactions inside fn into savepoints", func(t *testing.T) {
+		ctx := context.Background()
+		db := setupTenantTest(t)
+
+		err := RunAsTenant(ctx, db, tenant, func(ctx context.Context) error {
+			require.NoError(t, db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return tx.Create(&tenantTestRecord{Name: "kept"}).Error
+			}))
+
+			err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				require.NoError(t, tx.Create(&tenantTestRecord{Name: "discarded"}).Error)
+				return errors.New("usecase failed")
+			})
+			assert.EqualError(t, err, "usecase failed")
+			return nil
+		})
+		require.NoError(t, err)
+
+		var records []tenantTestRecord
+		require.NoError(t, db.Find(&records).Error)
+		require.Len(t, records, 1)
+		assert.Equal(t, "kept", records[0].Name)
+	})
+
+	t.Run("runs fn directly outside row-level security mode", func(t *testing.T) {
+		ctx := context.Background()
+		db := testutil.SetupTestDB(t, &tenantTestRecord{})
+
+		err := RunAsTenant(ctx, db, tenant, func(ctx context.Context) error {
+			return db.WithContext(ctx).Create(&tenantTestRecord{Name: "plain"}).Error
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), countTenantTestRecords(t, db))
+	})
+
+	t.Run("keeps the connection pool reachable", func(t *testing.T) {
+		db := setupTenantTest(t)
+
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.NoError(t, sqlDB.Ping())
+	})
+}
@@ -0,0 +1,209 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+)
+
+func TestAdminListShops(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	adminID := registerUser(t, env, "admin@example.com", "+1234567891")
+	createShop(t, env, ownerID)
+
+	resp := env.RequestWithAuth(t, http.MethodGet, "/api/admin/shops", nil, adminID)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	makeAdmin(t, env, adminID)
+
+	resp = env.RequestWithAuth(t, http.MethodGet, "/api/admin/shops", nil, adminID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	shops := body["data"].(map[string]any)["shops"].([]any)
+	require.Len(t, shops, 1)
+	assert.Nil(t, shops[0].(map[string]any)["membership"])
+}
+
+func TestAdminUsers(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	adminID := registerUser(t, env, "admin@example.com", "+1234567890")
+	userID := registerUser(t, env, "customer@example.com", "+1234567891")
+	makeAdmin(t, env, adminID)
+	require.NoError(t, env.DB.Model(&userentities.User{}).Where("id = ?", userID).Update("email_verified_at", time.Now()).Error)
+
+	login := map[string]string{"email": "customer@example.com", "password": "SecurePass123!"}
+	reason := map[string]string{"reason": "Chargeback fraud"}
+
+	t.Run("searches users", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/admin/users?q=customer", nil, adminID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		users := body["data"].(map[string]any)["users"].([]any)
+		require.Len(t, users, 1)
+		assert.Equal(t, float64(userID), users[0].(map[string]any)["id"])
+
+		resp = env.RequestWithAuth(t, http.MethodGet, "/api/admin/users", nil, userID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("suspends and restores a user", func(t *testing.T) {
+		resp := env.Request(t, http.MethodPost, "/api/auth/login", login)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var loginBody map[string]any
+		resp.JSON(t, &loginBody)
+		accessToken := loginBody["data"].(map[string]any)["access_token"].(string)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/suspend", userID), map[string]string{}, adminID)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/suspend", userID), reason, adminID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// A token issued before the suspension no longer works.
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/me", nil, accessToken)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "account suspended")
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/suspend", userID), reason, adminID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/login", login)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "account suspended")
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/restore", userID), map[string]string{"reason": "Dispute resolved"}, adminID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/login", login)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/suspend", adminID), reason, adminID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, "/api/admin/users/999/suspend", reason, adminID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("impersonates a user", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/impersonate", userID), map[string]string{"reason": "Support ticket #4521"}, adminID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		data := body["data"].(map[string]any)
+		token := data["access_token"].(string)
+		assert.Equal(t, "Bearer", data["token_type"])
+
+		claims, err := env.TokenService.VerifyAccessToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%d", userID), claims.Subject)
+		assert.Equal(t, adminID, claims.ImpersonatorID)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/me", nil, token)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body = nil
+		resp.JSON(t, &body)
+		assert.Equal(t, "customer@example.com", body["data"].(map[string]any)["user"].(map[string]any)["email"])
+
+		resp = env.RequestWithToken(t, http.MethodPost, "/api/me/password", map[string]string{"current_password": "SecurePass123!", "new_password": "Hijacked123!"}, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/admin/users", nil, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// The user owns a shop, yet API keys cannot be managed in their name.
+		shopID := createShop(t, env, userID)
+		keysPath := fmt.Sprintf("/api/shops/%d/api-keys", shopID)
+		resp = env.RequestWithToken(t, http.MethodPost, keysPath, map[string]any{"name": "Backdoor", "permissions": []string{"staff.view"}}, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "not allowed while impersonating")
+
+		resp = env.RequestWithToken(t, http.MethodGet, keysPath, nil, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// Nor can the shop be deleted or handed over.
+		resp = env.RequestWithToken(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/transfer-ownership", shopID), map[string]any{"staff_id": 1, "role_id": 1}, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "not allowed while impersonating")
+
+		resp = env.RequestWithToken(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d", shopID), nil, token)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "not allowed while impersonating")
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, userID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, keysPath, nil, userID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body = nil
+		resp.JSON(t, &body)
+		assert.Empty(t, body["data"].(map[string]any)["api_keys"])
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/admin/audit-logs?target_type=user&target_id=%d", userID), nil, adminID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body = nil
+		resp.JSON(t, &body)
+		logs := body["data"].(map[string]any)["logs"].([]any)
+		require.Len(t, logs, 3)
+		latest := logs[0].(map[string]any)
+		assert.Equal(t, "impersonate", latest["action"])
+		assert.Equal(t, claims.ID, latest["token_id"])
+		assert.Equal(t, "Support ticket #4521", latest["reason"])
+	})
+}
+
+func TestAdminShopSuspension(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	adminID := registerUser(t, env, "admin@example.com", "+1234567890")
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567891")
+	makeAdmin(t, env, adminID)
+	shopID := createShop(t, env, ownerID)
+
+	resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/shops/%d/suspend", shopID), map[string]string{"reason": "Unpaid invoices"}, adminID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	assert.NotNil(t, body["data"].(map[string]any)["shop"].(map[string]any)["suspended_at"])
+
+	resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, ownerID)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/shops/%d/restore", shopID), map[string]string{"reason": "Invoices paid"}, adminID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, ownerID)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/admin/shops/%d/restore", shopID), map[string]string{"reason": "Invoices paid"}, adminID)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+// makeAdmin turns a user into a platform administrator. There is no API
+// for it; operators set the flag in the database.
+func makeAdmin(t *testing.T, env *TestEnv, userID uint64) {
+	require.NoError(t, env.DB.Model(&userentities.User{}).Where("id = ?", userID).Update("is_admin", true).Error)
+}
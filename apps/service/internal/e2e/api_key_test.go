@@ -0,0 +1,154 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	shopID := createShop(t, env, ownerID)
+	otherShopID := createShop(t, env, ownerID)
+	keysPath := fmt.Sprintf("/api/shops/%d/api-keys", shopID)
+
+	resp := env.RequestWithAuth(t, http.MethodPost, keysPath, map[string]any{
+		"name":        "Staff sync",
+		"permissions": []string{"staff.view"},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	data := body["data"].(map[string]any)
+	key := data["key"].(string)
+	apiKey := data["api_key"].(map[string]any)
+	assert.True(t, strings.HasPrefix(key, apiKey["prefix"].(string)+"_"))
+	assert.Equal(t, []any{"staff.view"}, apiKey["permissions"])
+
+	t.Run("calls the routes of its shop within its permissions", func(t *testing.T) {
+		resp := env.RequestWithToken(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs", shopID), nil, key)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, key)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodPut, fmt.Sprintf("/api/shops/%d", shopID), map[string]string{"name": "Renamed"}, key)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "api key lacks permission shop.update")
+	})
+
+	t.Run("is limited to its shop", func(t *testing.T) {
+		resp := env.RequestWithToken(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs", otherShopID), nil, key)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/me", nil, key)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/shops", nil, key)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("cannot manage keys", func(t *testing.T) {
+		resp := env.RequestWithToken(t, http.MethodPost, keysPath, map[string]any{
+			"name":        "Escalation",
+			"permissions": []string{"staff.view"},
+		}, key)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("lists keys with their last use but without the key", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodGet, keysPath, nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, string(resp.Body), key)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		keys := body["data"].(map[string]any)["api_keys"].([]any)
+		require.Len(t, keys, 1)
+		assert.NotNil(t, keys[0].(map[string]any)["last_used_at"])
+	})
+
+	t.Run("refuses permissions the creator does not hold", func(t *testing.T) {
+		memberID := registerUser(t, env, "member@example.com", "+1234567891")
+		resp := env.RequestWithAuth(t, http.MethodPost, keysPath, map[string]any{
+			"name":        "Outsider",
+			"permissions": []string{"staff.view"},
+		}, memberID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, keysPath, map[string]any{
+			"name":        "Unknown",
+			"permissions": []string{"shop.teleport"},
+		}, ownerID)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("cannot hand out access", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, keysPath, map[string]any{
+			"name":        "Staff admin",
+			"permissions": []string{"staff.assign"},
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var keyBody map[string]any
+		resp.JSON(t, &keyBody)
+		assignKey := keyBody["data"].(map[string]any)["key"].(string)
+
+		var roles []struct {
+			ID   uint64
+			Name string
+		}
+		require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id, name FROM roles WHERE shop_id = ?", shopID).Scan(&roles).Error)
+		roleIDs := map[string]uint64{}
+		for _, role := range roles {
+			roleIDs[role.Name] = role.ID
+		}
+
+		clerkID := registerUser(t, env, "clerk@example.com", "+1234567892")
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), map[string]any{
+			"user_id": clerkID,
+			"role_id": roleIDs["Cashier"],
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var assignBody map[string]any
+		resp.JSON(t, &assignBody)
+		clerkStaffID := uint64(assignBody["data"].(map[string]any)["staff"].(map[string]any)["id"].(float64))
+
+		// The owner holds every permission, but the key only staff.assign.
+		resp = env.RequestWithToken(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs/%d/access", shopID, clerkStaffID), map[string]any{"role_id": roleIDs["Owner"]}, assignKey)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "not allowed with an api key")
+
+		resp = env.RequestWithToken(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/invitations", shopID), map[string]any{
+			"email":   "newcomer@example.com",
+			"role_id": roleIDs["Owner"],
+		}, assignKey)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs/%d/access", shopID, clerkStaffID), nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var accessBody map[string]any
+		resp.JSON(t, &accessBody)
+		assert.Equal(t, "Cashier", accessBody["data"].(map[string]any)["staff"].(map[string]any)["role"].(map[string]any)["name"])
+	})
+
+	t.Run("stops working once revoked", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s/%d", keysPath, uint64(apiKey["id"].(float64))), nil, ownerID)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs", shopID), nil, key)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s/%d", keysPath, uint64(apiKey["id"].(float64))), nil, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
@@ -3,11 +3,14 @@ package e2e
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 )
 
 func TestHealthEndpoint(t *testing.T) {
@@ -23,6 +26,20 @@ func TestHealthEndpoint(t *testing.T) {
 	assert.Equal(t, "ok", body["status"])
 }
 
+func TestJWKSEndpoint(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	resp := env.Request(t, http.MethodGet, "/.well-known/jwks.json", nil)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	// The test environment signs with HS256, whose secret is never published.
+	assert.Empty(t, body["keys"])
+}
+
 func TestRegisterEndpoint(t *testing.T) {
 	env := SetupTestEnv(t)
 	defer env.Cleanup(t)
@@ -229,3 +246,585 @@ func TestAuthFlow(t *testing.T) {
 		assert.NotEqual(t, accessToken, refreshToken)
 	})
 }
+
+func TestAuthMiddleware(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		resp := env.Request(t, http.MethodGet, "/api/shops", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("malformed authorization header", func(t *testing.T) {
+		resp := env.RequestWithToken(t, http.MethodGet, "/api/shops", nil, "")
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		resp := env.RequestWithToken(t, http.MethodGet, "/api/shops", nil, "invalid.token.here")
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("rejects refresh token", func(t *testing.T) {
+		refreshToken, _, err := env.TokenService.GenerateRefreshToken(1, "john@example.com", "+1234567890")
+		require.NoError(t, err)
+
+		resp := env.RequestWithToken(t, http.MethodGet, "/api/shops", nil, refreshToken)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("accepts access token from login", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		registerPayload := map[string]string{
+			"full_name": "Jane Doe",
+			"email":     "jane@example.com",
+			"phone":     "+1234567890",
+			"password":  "SecurePass123!",
+		}
+		resp := env.Request(t, http.MethodPost, "/api/auth/register", registerPayload)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		loginPayload := map[string]string{
+			"email":    "jane@example.com",
+			"password": "SecurePass123!",
+		}
+		resp = env.Request(t, http.MethodPost, "/api/auth/login", loginPayload)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var loginBody map[string]any
+		resp.JSON(t, &loginBody)
+		data := loginBody["data"].(map[string]any)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/shops", nil, data["access_token"].(string))
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("public routes do not require authentication", func(t *testing.T) {
+		resp := env.Request(t, http.MethodGet, "/health", nil)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+// registerAndLogin registers a user and returns the login response data
+func registerAndLogin(t *testing.T, env *TestEnv, email, phone, password string) map[string]any {
+	registerPayload := map[string]string{
+		"full_name": "Session User",
+		"email":     email,
+		"phone":     phone,
+		"password":  password,
+	}
+	resp := env.Request(t, http.MethodPost, "/api/auth/register", registerPayload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	loginPayload := map[string]string{
+		"email":    email,
+		"password": password,
+	}
+	resp = env.Request(t, http.MethodPost, "/api/auth/login", loginPayload)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	return body["data"].(map[string]any)
+}
+
+func TestRefreshEndpoint(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("successful refresh", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "refresh@example.com", "+1234567890", "SecurePass123!")
+
+		resp := env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": tokens["refresh_token"].(string),
+		})
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		data := body["data"].(map[string]any)
+		assert.NotEmpty(t, data["access_token"])
+		assert.NotEmpty(t, data["refresh_token"])
+		assert.NotEqual(t, tokens["refresh_token"], data["refresh_token"])
+	})
+
+	t.Run("invalid refresh token", func(t *testing.T) {
+		resp := env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": "invalid.token.here",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("missing refresh token", func(t *testing.T) {
+		resp := env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("reused refresh token revokes the login", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "reuse@example.com", "+1234567890", "SecurePass123!")
+		stolenToken := tokens["refresh_token"].(string)
+
+		resp := env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": stolenToken,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		rotatedToken := body["data"].(map[string]any)["refresh_token"].(string)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": stolenToken,
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": rotatedToken,
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestLogoutAndSessionsEndpoints(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("lists and terminates sessions", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		first := registerAndLogin(t, env, "sessions@example.com", "+1234567890", "SecurePass123!")
+
+		loginPayload := map[string]string{
+			"email":    "sessions@example.com",
+			"password": "SecurePass123!",
+		}
+		resp := env.Request(t, http.MethodPost, "/api/auth/login", loginPayload)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var second map[string]any
+		resp.JSON(t, &second)
+		secondAccessToken := second["data"].(map[string]any)["access_token"].(string)
+
+		accessToken := first["access_token"].(string)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/auth/sessions", nil, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		sessions := body["data"].(map[string]any)["sessions"].([]any)
+		require.Len(t, sessions, 2)
+
+		// Terminate the most recent session by ID
+		sessionID := uint64(sessions[0].(map[string]any)["id"].(float64))
+		resp = env.RequestWithToken(t, http.MethodDelete, fmt.Sprintf("/api/auth/sessions/%d", sessionID), nil, accessToken)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		// Its access token stops working right away
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/auth/sessions", nil, secondAccessToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		// Only the other session remains
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/auth/sessions", nil, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.JSON(t, &body)
+		sessions = body["data"].(map[string]any)["sessions"].([]any)
+		assert.Len(t, sessions, 1)
+	})
+
+	t.Run("logout revokes the refresh token", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "logout@example.com", "+1234567890", "SecurePass123!")
+		refreshToken := tokens["refresh_token"].(string)
+
+		resp := env.RequestWithToken(t, http.MethodPost, "/api/auth/logout", map[string]string{
+			"refresh_token": refreshToken,
+		}, tokens["access_token"].(string))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": refreshToken,
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/auth/sessions", nil, tokens["access_token"].(string))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		resp := env.Request(t, http.MethodGet, "/api/auth/sessions", nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/logout", map[string]string{
+			"refresh_token": "anything",
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestPasswordResetEndpoints(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	if env.Mailer == nil {
+		t.Skip("password reset emails cannot be read when testing against a live API")
+	}
+
+	t.Run("resets password and terminates sessions", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "reset@example.com", "+1234567890", "SecurePass123!")
+
+		resp := env.Request(t, http.MethodPost, "/api/auth/password/forgot", map[string]string{
+			"email": "reset@example.com",
+		})
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		message, ok := env.Mailer.LastMessageTo("reset@example.com")
+		require.True(t, ok)
+		token := tokenFromLink(t, message.Body)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/password/reset", map[string]string{
+			"token":    token,
+			"password": "NewSecurePass456!",
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": tokens["refresh_token"].(string),
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/me", nil, tokens["access_token"].(string))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/login", map[string]string{
+			"email":    "reset@example.com",
+			"password": "NewSecurePass456!",
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/password/reset", map[string]string{
+			"token":    token,
+			"password": "AnotherPass789!",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("does not reveal unknown emails", func(t *testing.T) {
+		resp := env.Request(t, http.MethodPost, "/api/auth/password/forgot", map[string]string{
+			"email": "nobody@example.com",
+		})
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	})
+}
+
+func TestVerificationEndpoints(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	if env.Mailer == nil {
+		t.Skip("verification emails cannot be read when testing against a live API")
+	}
+
+	t.Run("verifies email with the emailed code", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		resp := env.Request(t, http.MethodPost, "/api/auth/register", map[string]string{
+			"full_name": "Verify User",
+			"email":     "verify@example.com",
+			"phone":     "+1234567890",
+			"password":  "SecurePass123!",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/verification/send", map[string]string{
+			"email": "verify@example.com",
+		})
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/verification/send", map[string]string{
+			"email": "verify@example.com",
+		})
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Headers.Get("Retry-After"))
+
+		message, ok := env.Mailer.LastMessageTo("verify@example.com")
+		require.True(t, ok)
+		code := message.Body[strings.Index(message.Body, "code is ")+len("code is "):][:6]
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/verification/confirm", map[string]string{
+			"email": "verify@example.com",
+			"code":  "000000",
+		})
+		if code != "000000" {
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		}
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/verification/confirm", map[string]string{
+			"email": "verify@example.com",
+			"code":  code,
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		user := body["data"].(map[string]any)["user"].(map[string]any)
+		assert.NotNil(t, user["email_verified_at"])
+		assert.Nil(t, user["phone_verified_at"])
+	})
+}
+
+func TestTotpEndpoints(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("requires a second factor after enrollment", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "mfa@example.com", "+1234567890", "SecurePass123!")
+		accessToken := tokens["access_token"].(string)
+
+		resp := env.RequestWithToken(t, http.MethodPost, "/api/auth/mfa/totp/enroll", nil, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		enrollment := body["data"].(map[string]any)
+		secret := enrollment["secret"].(string)
+		assert.True(t, strings.HasPrefix(enrollment["otpauth_uri"].(string), "otpauth://totp/"))
+
+		code, err := services.NewTotpService("").Code(secret)
+		require.NoError(t, err)
+
+		resp = env.RequestWithToken(t, http.MethodPost, "/api/auth/mfa/totp/confirm", map[string]string{"code": code}, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp.JSON(t, &body)
+		recoveryCodes := body["data"].(map[string]any)["recovery_codes"].([]any)
+		assert.Len(t, recoveryCodes, 10)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/login", map[string]string{
+			"email":    "mfa@example.com",
+			"password": "SecurePass123!",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp.JSON(t, &body)
+		challenge := body["data"].(map[string]any)
+		assert.Equal(t, true, challenge["mfa_required"])
+		assert.Nil(t, challenge["access_token"])
+		mfaToken := challenge["mfa_token"].(string)
+
+		// The MFA token is not an access token.
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/auth/sessions", nil, mfaToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		// The code used for confirmation cannot be replayed.
+		resp = env.Request(t, http.MethodPost, "/api/auth/mfa/verify", map[string]string{
+			"mfa_token": mfaToken,
+			"code":      code,
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/mfa/verify", map[string]string{
+			"mfa_token":     mfaToken,
+			"recovery_code": recoveryCodes[0].(string),
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp.JSON(t, &body)
+		assert.NotEmpty(t, body["data"].(map[string]any)["access_token"])
+		assert.NotEmpty(t, body["data"].(map[string]any)["refresh_token"])
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/mfa/verify", map[string]string{
+			"mfa_token":     mfaToken,
+			"recovery_code": recoveryCodes[0].(string),
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodPost, "/api/auth/mfa/totp/enroll", nil, accessToken)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("locks the account after repeated failures", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		registerAndLogin(t, env, "locked@example.com", "+1234567890", "SecurePass123!")
+
+		for i := 0; i < 5; i++ {
+			resp := env.Request(t, http.MethodPost, "/api/auth/login", map[string]string{
+				"email":    "locked@example.com",
+				"password": "WrongPass123!",
+			})
+			require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+
+		resp := env.Request(t, http.MethodPost, "/api/auth/login", map[string]string{
+			"email":    "locked@example.com",
+			"password": "SecurePass123!",
+		})
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Headers.Get("Retry-After"))
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Equal(t, float64(http.StatusTooManyRequests), body["status"])
+	})
+
+	t.Run("throttles wrong current passwords", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "guessed@example.com", "+1234567890", "SecurePass123!")
+		accessToken := tokens["access_token"].(string)
+
+		for i := 0; i < 5; i++ {
+			resp := env.RequestWithToken(t, http.MethodPost, "/api/me/email", map[string]string{
+				"email":    "attacker@example.com",
+				"password": "WrongPass123!",
+			}, accessToken)
+			require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+
+		resp := env.RequestWithToken(t, http.MethodPost, "/api/me/password", map[string]string{
+			"current_password": "SecurePass123!",
+			"new_password":     "NewSecurePass123!",
+		}, accessToken)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Headers.Get("Retry-After"))
+	})
+}
+
+// tokenFromLink extracts the token query parameter from the first link in an email body.
+func tokenFromLink(t *testing.T, body string) string {
+	start := strings.Index(body, "token=")
+	require.NotEqual(t, -1, start, "no token link in email")
+
+	token := body[start+len("token="):]
+	if end := strings.IndexAny(token, " \n"); end != -1 {
+		token = token[:end]
+	}
+	return token
+}
+
+func TestMeEndpoints(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("reads and updates the profile", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "me@example.com", "+1234567890", "SecurePass123!")
+		accessToken := tokens["access_token"].(string)
+
+		resp := env.RequestWithToken(t, http.MethodGet, "/api/me", nil, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		user := body["data"].(map[string]any)["user"].(map[string]any)
+		assert.Equal(t, "me@example.com", user["email"])
+		assert.Nil(t, user["password"])
+
+		resp = env.RequestWithToken(t, http.MethodPatch, "/api/me", map[string]string{"full_name": "Renamed User"}, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp.JSON(t, &body)
+		assert.Equal(t, "Renamed User", body["data"].(map[string]any)["user"].(map[string]any)["full_name"])
+	})
+
+	t.Run("changes the password and signs out", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "me@example.com", "+1234567890", "SecurePass123!")
+		accessToken := tokens["access_token"].(string)
+
+		resp := env.RequestWithToken(t, http.MethodPost, "/api/me/password", map[string]string{
+			"current_password": "WrongPass123!",
+			"new_password":     "NewSecurePass123!",
+		}, accessToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodPost, "/api/me/password", map[string]string{
+			"current_password": "SecurePass123!",
+			"new_password":     "NewSecurePass123!",
+		}, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/refresh", map[string]string{
+			"refresh_token": tokens["refresh_token"].(string),
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodGet, "/api/me", nil, accessToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = env.Request(t, http.MethodPost, "/api/auth/login", map[string]string{
+			"email":    "me@example.com",
+			"password": "NewSecurePass123!",
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("changes the email after verifying the new address", func(t *testing.T) {
+		if env.Mailer == nil {
+			t.Skip("verification emails cannot be read when testing against a live API")
+		}
+		env.CleanupDB(t)
+
+		tokens := registerAndLogin(t, env, "me@example.com", "+1234567890", "SecurePass123!")
+		accessToken := tokens["access_token"].(string)
+		registerAndLogin(t, env, "taken@example.com", "+1987654321", "SecurePass123!")
+
+		resp := env.RequestWithToken(t, http.MethodPost, "/api/me/email", map[string]string{
+			"email":    "taken@example.com",
+			"password": "SecurePass123!",
+		}, accessToken)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithToken(t, http.MethodPost, "/api/me/email", map[string]string{
+			"email":    "new@example.com",
+			"password": "SecurePass123!",
+		}, accessToken)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		message, ok := env.Mailer.LastMessageTo("new@example.com")
+		require.True(t, ok)
+		code := message.Body[strings.Index(message.Body, "code is ")+len("code is "):][:6]
+
+		resp = env.RequestWithToken(t, http.MethodPost, "/api/me/email/confirm", map[string]string{"code": code}, accessToken)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		user := body["data"].(map[string]any)["user"].(map[string]any)
+		assert.Equal(t, "new@example.com", user["email"])
+		assert.NotNil(t, user["email_verified_at"])
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		resp := env.Request(t, http.MethodGet, "/api/me", nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,121 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranches(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	keeperID := registerUser(t, env, "keeper@example.com", "+1987654321")
+	shopID := createShop(t, env, ownerID)
+	assignRole(t, env, shopID, ownerID, keeperID, "Stock Keeper")
+
+	locationsPath := fmt.Sprintf("/api/shops/%d/locations", shopID)
+	movementsPath := fmt.Sprintf("/api/shops/%d/inventory/movements", shopID)
+
+	resp := env.RequestWithAuth(t, http.MethodGet, locationsPath, nil, ownerID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var locationsBody map[string]any
+	resp.JSON(t, &locationsBody)
+	mainLocation := locationsBody["data"].(map[string]any)["locations"].([]any)[0].(map[string]any)
+	assert.Equal(t, "outlet", mainLocation["kind"])
+	assert.Equal(t, "123 Main St", mainLocation["address"])
+	mainID := uint64(mainLocation["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, locationsPath, map[string]any{
+		"name":    "Warehouse",
+		"kind":    "warehouse",
+		"address": "1 Dock Road",
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var locationBody map[string]any
+	resp.JSON(t, &locationBody)
+	warehouse := locationBody["data"].(map[string]any)["location"].(map[string]any)
+	assert.Equal(t, "warehouse", warehouse["kind"])
+	warehouseID := uint64(warehouse["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/products", shopID), map[string]any{
+		"name":     "Coffee Beans",
+		"variants": []map[string]any{{"sku": "BEANS", "price": 1200}},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var productBody map[string]any
+	resp.JSON(t, &productBody)
+	variants := productBody["data"].(map[string]any)["product"].(map[string]any)["variants"].([]any)
+	variantID := uint64(variants[0].(map[string]any)["id"].(float64))
+
+	var keeperStaffID uint64
+	require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id FROM staffs WHERE shop_id = ? AND user_id = ?", shopID, keeperID).Scan(&keeperStaffID).Error)
+	staffLocationsPath := fmt.Sprintf("/api/shops/%d/staffs/%d/locations", shopID, keeperStaffID)
+
+	t.Run("updates a branch", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPut, fmt.Sprintf("%s/%d", locationsPath, warehouseID), map[string]any{
+			"name":    "Central Warehouse",
+			"kind":    "warehouse",
+			"address": "2 Dock Road",
+			"phone":   "+15550100",
+		}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		location := body["data"].(map[string]any)["location"].(map[string]any)
+		assert.Equal(t, "Central Warehouse", location["name"])
+		assert.Equal(t, "2 Dock Road", location["address"])
+	})
+
+	t.Run("keeps staff to the branches they are assigned to", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPut, staffLocationsPath, map[string]any{
+			"location_ids": []uint64{warehouseID},
+		}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, movementsPath, map[string]any{
+			"location_id": mainID,
+			"type":        "receipt",
+			"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+		}, keeperID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, movementsPath, map[string]any{
+			"location_id": warehouseID,
+			"type":        "receipt",
+			"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+		}, keeperID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/inventory/levels?location_id=%d", shopID, mainID), nil, keeperID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("clearing the assignments opens every branch", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPut, staffLocationsPath, map[string]any{
+			"location_ids": []uint64{},
+		}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, movementsPath, map[string]any{
+			"location_id": mainID,
+			"type":        "receipt",
+			"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+		}, keeperID)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("staff cannot assign themselves branches", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPut, staffLocationsPath, map[string]any{
+			"location_ids": []uint64{mainID},
+		}, keeperID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
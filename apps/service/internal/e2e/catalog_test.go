@@ -0,0 +1,170 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	cashierID := registerUser(t, env, "cashier@example.com", "+1987654321")
+	shopID := createShop(t, env, ownerID)
+	otherShopID := createShop(t, env, ownerID)
+	assignRole(t, env, shopID, ownerID, cashierID, "Cashier")
+
+	categoriesPath := fmt.Sprintf("/api/shops/%d/categories", shopID)
+	productsPath := fmt.Sprintf("/api/shops/%d/products", shopID)
+
+	resp := env.RequestWithAuth(t, http.MethodPost, categoriesPath, map[string]any{"name": "Clothing"}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var categoryBody map[string]any
+	resp.JSON(t, &categoryBody)
+	clothingID := uint64(categoryBody["data"].(map[string]any)["category"].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, categoriesPath, map[string]any{"name": "Shirts", "parent_id": clothingID}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.JSON(t, &categoryBody)
+	shirtsID := uint64(categoryBody["data"].(map[string]any)["category"].(map[string]any)["id"].(float64))
+
+	shirt := map[string]any{
+		"category_id": shirtsID,
+		"name":        "T-Shirt",
+		"options": []map[string]any{
+			{"name": "Size", "values": []string{"S", "M"}},
+			{"name": "Colour", "values": []string{"Red", "Blue"}},
+		},
+		"variants": []map[string]any{
+			{"sku": "TS-S-RED", "price": 1500, "options": map[string]string{"Size": "S", "Colour": "Red"}},
+			{"sku": "TS-M-RED", "price": 1500, "options": map[string]string{"Size": "M", "Colour": "Red"}},
+			{"sku": "TS-M-BLUE", "barcode": "4006381333931", "price": 1700, "options": map[string]string{"Size": "M", "Colour": "Blue"}},
+		},
+	}
+
+	resp = env.RequestWithAuth(t, http.MethodPost, productsPath, shirt, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var productBody map[string]any
+	resp.JSON(t, &productBody)
+	product := productBody["data"].(map[string]any)["product"].(map[string]any)
+	productID := uint64(product["id"].(float64))
+	productPath := fmt.Sprintf("%s/%d", productsPath, productID)
+	assert.Len(t, product["variants"], 3)
+
+	t.Run("members browse the catalog", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("%s?q=4006381333931", productsPath), nil, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		products := body["data"].(map[string]any)["products"].([]any)
+		require.Len(t, products, 1)
+		assert.Equal(t, "T-Shirt", products[0].(map[string]any)["name"])
+
+		resp = env.RequestWithAuth(t, http.MethodGet, categoriesPath, nil, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.JSON(t, &body)
+		assert.Len(t, body["data"].(map[string]any)["categories"], 2)
+	})
+
+	t.Run("changing the catalog takes catalog.manage", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, categoriesPath, map[string]any{"name": "Shoes"}, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, productPath, nil, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("SKUs are unique per shop", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, productsPath, map[string]any{
+			"name":     "Polo",
+			"variants": []map[string]any{{"sku": "TS-S-RED", "price": 2000}},
+		}, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/products", otherShopID), map[string]any{
+			"name":     "Polo",
+			"variants": []map[string]any{{"sku": "TS-S-RED", "price": 2000}},
+		}, ownerID)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("variants must match the options", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, productsPath, map[string]any{
+			"name":    "Hoodie",
+			"options": []map[string]any{{"name": "Size", "values": []string{"S"}}},
+			"variants": []map[string]any{
+				{"sku": "HD-S", "price": 4000, "options": map[string]string{"Size": "S"}},
+				{"sku": "HD-S-2", "price": 4000, "options": map[string]string{"Size": "S"}},
+			},
+		}, ownerID)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("updates replace the variants", func(t *testing.T) {
+		variants := product["variants"].([]any)
+		resp := env.RequestWithAuth(t, http.MethodPut, productPath, map[string]any{
+			"category_id": shirtsID,
+			"name":        "T-Shirt",
+			"options":     []map[string]any{{"name": "Size", "values": []string{"S", "M"}}},
+			"variants": []map[string]any{
+				{"id": variants[0].(map[string]any)["id"], "sku": "TS-S", "price": 1600, "options": map[string]string{"Size": "S"}},
+				{"sku": "TS-M", "price": 1600, "options": map[string]string{"Size": "M"}},
+			},
+		}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		updated := body["data"].(map[string]any)["product"].(map[string]any)["variants"].([]any)
+		require.Len(t, updated, 2)
+		assert.Equal(t, variants[0].(map[string]any)["id"], updated[0].(map[string]any)["id"])
+		assert.Equal(t, "TS-S", updated[0].(map[string]any)["sku"])
+	})
+
+	t.Run("deleting a category keeps its products", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s/%d", categoriesPath, clothingID), nil, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s/%d", categoriesPath, shirtsID), nil, ownerID)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, productPath, nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Nil(t, body["data"].(map[string]any)["product"].(map[string]any)["category_id"])
+	})
+
+	t.Run("products of other shops are not found", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/products/%d", otherShopID, productID), nil, ownerID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("deletes a product", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodDelete, productPath, nil, ownerID)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, productPath, nil, ownerID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+// assignRole adds userID to the shop as staff with the named role.
+func assignRole(t *testing.T, env *TestEnv, shopID, ownerID, userID uint64, roleName string) {
+	var roleID uint64
+	require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id FROM roles WHERE shop_id = ? AND name = ?", shopID, roleName).Scan(&roleID).Error)
+
+	resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), map[string]any{
+		"user_id": userID,
+		"role_id": roleID,
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+}
@@ -0,0 +1,134 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	keeperID := registerUser(t, env, "keeper@example.com", "+1987654321")
+	cashierID := registerUser(t, env, "cashier@example.com", "+1555555555")
+	shopID := createShop(t, env, ownerID)
+	assignRole(t, env, shopID, ownerID, keeperID, "Stock Keeper")
+	assignRole(t, env, shopID, ownerID, cashierID, "Cashier")
+
+	locationsPath := fmt.Sprintf("/api/shops/%d/locations", shopID)
+	movementsPath := fmt.Sprintf("/api/shops/%d/inventory/movements", shopID)
+	levelsPath := fmt.Sprintf("/api/shops/%d/inventory/levels", shopID)
+
+	resp := env.RequestWithAuth(t, http.MethodGet, locationsPath, nil, cashierID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var locationsBody map[string]any
+	resp.JSON(t, &locationsBody)
+	locations := locationsBody["data"].(map[string]any)["locations"].([]any)
+	require.Len(t, locations, 1)
+	assert.Equal(t, "Main", locations[0].(map[string]any)["name"])
+	mainID := uint64(locations[0].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, locationsPath, map[string]any{"name": "Storage"}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var locationBody map[string]any
+	resp.JSON(t, &locationBody)
+	storageID := uint64(locationBody["data"].(map[string]any)["location"].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/products", shopID), map[string]any{
+		"name":     "Coffee Beans",
+		"variants": []map[string]any{{"sku": "BEANS", "price": 1200}},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var productBody map[string]any
+	resp.JSON(t, &productBody)
+	variants := productBody["data"].(map[string]any)["product"].(map[string]any)["variants"].([]any)
+	variantID := uint64(variants[0].(map[string]any)["id"].(float64))
+
+	t.Run("stock keepers receive stock", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, movementsPath, map[string]any{
+			"location_id": storageID,
+			"type":        "receipt",
+			"reference":   "PO-1001",
+			"lines":       []map[string]any{{"variant_id": variantID, "quantity": 10}},
+		}, keeperID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		movements := body["data"].(map[string]any)["movements"].([]any)
+		require.Len(t, movements, 1)
+		assert.Equal(t, float64(10), movements[0].(map[string]any)["balance_after"])
+	})
+
+	t.Run("cashiers cannot adjust stock", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, movementsPath, map[string]any{
+			"location_id": mainID,
+			"type":        "adjustment",
+			"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+		}, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("transfers move stock between locations", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/inventory/transfers", shopID), map[string]any{
+			"from_location_id": storageID,
+			"to_location_id":   mainID,
+			"lines":            []map[string]any{{"variant_id": variantID, "quantity": 4}},
+		}, keeperID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("%s?variant_id=%d", levelsPath, variantID), nil, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		levels := body["data"].(map[string]any)["levels"].([]any)
+		require.Len(t, levels, 2)
+		onHand := map[uint64]float64{}
+		for _, level := range levels {
+			level := level.(map[string]any)
+			onHand[uint64(level["location_id"].(float64))] = level["on_hand"].(float64)
+		}
+		assert.Equal(t, float64(4), onHand[mainID])
+		assert.Equal(t, float64(6), onHand[storageID])
+	})
+
+	t.Run("stock cannot go negative by default", func(t *testing.T) {
+		sale := map[string]any{
+			"location_id": mainID,
+			"type":        "sale",
+			"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+		}
+		resp := env.RequestWithAuth(t, http.MethodPost, movementsPath, sale, keeperID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPut, fmt.Sprintf("/api/shops/%d/inventory/settings", shopID), map[string]any{"allow_negative_stock": true}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, movementsPath, sale, keeperID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Equal(t, float64(-1), body["data"].(map[string]any)["movements"].([]any)[0].(map[string]any)["balance_after"])
+	})
+
+	t.Run("the history lists movements newest first", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("%s?location_id=%d", movementsPath, mainID), nil, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		movements := body["data"].(map[string]any)["movements"].([]any)
+		require.Len(t, movements, 2)
+		assert.Equal(t, "sale", movements[0].(map[string]any)["type"])
+		assert.Equal(t, "transfer", movements[1].(map[string]any)["type"])
+	})
+}
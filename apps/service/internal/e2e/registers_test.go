@@ -0,0 +1,129 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSessions(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	cashierID := registerUser(t, env, "cashier@example.com", "+1987654321")
+	shopID := createShop(t, env, ownerID)
+	assignRole(t, env, shopID, ownerID, cashierID, "Cashier")
+
+	sessionsPath := fmt.Sprintf("/api/shops/%d/register-sessions", shopID)
+
+	resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/locations", shopID), nil, ownerID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var locationsBody map[string]any
+	resp.JSON(t, &locationsBody)
+	mainID := uint64(locationsBody["data"].(map[string]any)["locations"].([]any)[0].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/products", shopID), map[string]any{
+		"name":     "Coffee Beans",
+		"variants": []map[string]any{{"sku": "BEANS", "price": 1200}},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var productBody map[string]any
+	resp.JSON(t, &productBody)
+	variantID := uint64(productBody["data"].(map[string]any)["product"].(map[string]any)["variants"].([]any)[0].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/inventory/movements", shopID), map[string]any{
+		"location_id": mainID,
+		"type":        "receipt",
+		"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = env.RequestWithAuth(t, http.MethodPost, sessionsPath, map[string]any{"location_id": mainID, "opening_float": 10000}, cashierID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var sessionBody map[string]any
+	resp.JSON(t, &sessionBody)
+	session := sessionBody["data"].(map[string]any)["session"].(map[string]any)
+	sessionID := uint64(session["id"].(float64))
+	sessionPath := fmt.Sprintf("%s/%d", sessionsPath, sessionID)
+	assert.Equal(t, "open", session["status"])
+	assert.NotZero(t, session["opened_by_staff_id"])
+
+	t.Run("a register has one open session", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, sessionsPath, map[string]any{"location_id": mainID}, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("sales made at the register join its session", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/carts", shopID), map[string]any{"location_id": mainID}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var cartBody map[string]any
+		resp.JSON(t, &cartBody)
+		cartPath := fmt.Sprintf("/api/shops/%d/carts/%d", shopID, uint64(cartBody["data"].(map[string]any)["cart"].(map[string]any)["id"].(float64)))
+
+		resp = env.RequestWithAuth(t, http.MethodPost, cartPath+"/lines", map[string]any{"sku": "BEANS", "quantity": 1}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, cartPath+"/checkout", map[string]any{
+			"payments": []map[string]any{{"method": "cash", "amount": 2000}},
+		}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var saleBody map[string]any
+		resp.JSON(t, &saleBody)
+		assert.Equal(t, float64(sessionID), saleBody["data"].(map[string]any)["sale"].(map[string]any)["register_session_id"])
+	})
+
+	t.Run("cashiers pay cash out of the drawer", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, sessionPath+"/cash-movements", map[string]any{
+			"type":   "out",
+			"amount": 500,
+			"reason": "Window cleaner",
+		}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Len(t, body["data"].(map[string]any)["session"].(map[string]any)["cash_movements"], 1)
+	})
+
+	t.Run("closing reconciles the count", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, sessionPath+"/close", map[string]any{"counted_cash": 10600}, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// 10000 float + 2000 cash - 800 change - 500 paid out.
+		var body map[string]any
+		resp.JSON(t, &body)
+		closed := body["data"].(map[string]any)["session"].(map[string]any)
+		assert.Equal(t, "closed", closed["status"])
+		assert.Equal(t, float64(10700), closed["expected_cash"])
+		assert.Equal(t, float64(-100), closed["variance"])
+		assert.Equal(t, session["opened_by_staff_id"], closed["closed_by_staff_id"])
+
+		resp = env.RequestWithAuth(t, http.MethodPost, sessionPath+"/close", map[string]any{"counted_cash": 10600}, cashierID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("the Z-report takes reports.view", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodGet, sessionPath+"/report", nil, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, sessionPath+"/report", nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		report := body["data"].(map[string]any)["report"].(map[string]any)
+		assert.Equal(t, float64(1), report["sales_count"])
+		assert.Equal(t, float64(1200), report["total"])
+		assert.Equal(t, float64(10600), report["counted_cash"])
+		payments := report["payments"].([]any)
+		require.Len(t, payments, 3)
+		assert.Equal(t, "cash", payments[0].(map[string]any)["method"])
+		assert.Equal(t, float64(2000), payments[0].(map[string]any)["amount"])
+	})
+}
@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/db"
+)
+
+func TestRowLevelSecurity(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	if env.isLive {
+		t.Skip("row-level security needs the test database")
+	}
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	otherOwnerID := registerUser(t, env, "other@example.com", "+1987654321")
+	shopID := createShop(t, env, ownerID)
+	otherShopID := createShop(t, env, otherOwnerID)
+
+	var shopRoles, otherShopRoles int64
+	require.NoError(t, env.DB.Model(&accessentities.Role{}).Where("shop_id = ?", shopID).Count(&shopRoles).Error)
+	require.NoError(t, env.DB.Model(&accessentities.Role{}).Where("shop_id = ?", otherShopID).Count(&otherShopRoles).Error)
+	require.NotZero(t, shopRoles)
+	require.NotZero(t, otherShopRoles)
+
+	rls := env.WithRowLevelSecurity(t)
+
+	t.Run("serves shop routes under the policies", func(t *testing.T) {
+		resp := rls.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/roles", shopID), nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Len(t, body["data"].(map[string]any)["roles"], int(shopRoles))
+
+		resp = rls.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/roles", otherShopID), nil, ownerID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("limits unfiltered queries to the current shop", func(t *testing.T) {
+		roleRepository := accessrepositories.NewRoleRepository(rls.DB)
+		assert.Len(t, roleRepository.All(rls.Ctx), int(shopRoles+otherShopRoles))
+
+		err := db.RunAsTenant(rls.Ctx, rls.DB, db.Tenant{ShopID: shopID, UserID: ownerID}, func(ctx context.Context) error {
+			roles := roleRepository.All(ctx)
+			assert.Len(t, roles, int(shopRoles))
+			for _, role := range roles {
+				assert.Equal(t, shopID, role.ShopID)
+			}
+
+			result := rls.DB.WithContext(ctx).Model(&accessentities.Role{}).Where("shop_id = ?", otherShopID).Update("name", "Hijacked")
+			require.NoError(t, result.Error)
+			assert.Zero(t, result.RowsAffected)
+			return nil
+		})
+		require.NoError(t, err)
+
+		var hijacked int64
+		require.NoError(t, env.DB.Model(&accessentities.Role{}).Where("name = ?", "Hijacked").Count(&hijacked).Error)
+		assert.Zero(t, hijacked)
+	})
+}
@@ -0,0 +1,173 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSales(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.CleanupDB(t)
+
+	ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+	cashierID := registerUser(t, env, "cashier@example.com", "+1987654321")
+	keeperID := registerUser(t, env, "keeper@example.com", "+1555555555")
+	shopID := createShop(t, env, ownerID)
+	assignRole(t, env, shopID, ownerID, cashierID, "Cashier")
+	assignRole(t, env, shopID, ownerID, keeperID, "Stock Keeper")
+
+	cartsPath := fmt.Sprintf("/api/shops/%d/carts", shopID)
+	salesPath := fmt.Sprintf("/api/shops/%d/sales", shopID)
+
+	resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/locations", shopID), nil, ownerID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var locationsBody map[string]any
+	resp.JSON(t, &locationsBody)
+	mainID := uint64(locationsBody["data"].(map[string]any)["locations"].([]any)[0].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/products", shopID), map[string]any{
+		"name":     "Coffee Beans",
+		"variants": []map[string]any{{"sku": "BEANS", "barcode": "4006381333931", "price": 1200}},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var productBody map[string]any
+	resp.JSON(t, &productBody)
+	variantID := uint64(productBody["data"].(map[string]any)["product"].(map[string]any)["variants"].([]any)[0].(map[string]any)["id"].(float64))
+
+	resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/inventory/movements", shopID), map[string]any{
+		"location_id": mainID,
+		"type":        "receipt",
+		"lines":       []map[string]any{{"variant_id": variantID, "quantity": 5}},
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	openCart := func(t *testing.T) string {
+		resp := env.RequestWithAuth(t, http.MethodPost, cartsPath, map[string]any{"location_id": mainID}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var body map[string]any
+		resp.JSON(t, &body)
+		return fmt.Sprintf("%s/%d", cartsPath, uint64(body["data"].(map[string]any)["cart"].(map[string]any)["id"].(float64)))
+	}
+
+	t.Run("cashiers ring up a sale", func(t *testing.T) {
+		cartPath := openCart(t)
+
+		resp := env.RequestWithAuth(t, http.MethodPost, cartPath+"/lines", map[string]any{"barcode": "4006381333931", "quantity": 2}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPut, cartPath, map[string]any{"discount_amount": 400, "tax_rate": 1000}, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var cartBody map[string]any
+		resp.JSON(t, &cartBody)
+		cart := cartBody["data"].(map[string]any)["cart"].(map[string]any)
+		assert.Equal(t, float64(2400), cart["subtotal"])
+		assert.Equal(t, float64(200), cart["tax_total"])
+		assert.Equal(t, float64(2200), cart["total"])
+
+		checkout := map[string]any{"payments": []map[string]any{
+			{"method": "card", "amount": 1000, "reference": "AUTH-1"},
+			{"method": "cash", "amount": 1500},
+		}}
+		key := "7f1c8a7e-2b1d-4c55-9a6e-3d1f0c9b8a21"
+		resp = checkoutWithKey(t, env, cartPath+"/checkout", checkout, cashierID, key)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var saleBody map[string]any
+		resp.JSON(t, &saleBody)
+		sale := saleBody["data"].(map[string]any)["sale"].(map[string]any)
+		assert.Equal(t, float64(1), sale["receipt_number"])
+		assert.Equal(t, float64(300), sale["change"])
+		assert.Len(t, sale["payments"], 2)
+
+		// A retry with the same key gets the first sale back.
+		resp = checkoutWithKey(t, env, cartPath+"/checkout", checkout, cashierID, key)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var retryBody map[string]any
+		resp.JSON(t, &retryBody)
+		assert.Equal(t, sale["id"], retryBody["data"].(map[string]any)["sale"].(map[string]any)["id"])
+
+		// The key is the cashier's: anyone else sending it gets no sale back.
+		resp = checkoutWithKey(t, env, cartPath+"/checkout", checkout, keeperID, key)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		resp = checkoutWithKey(t, env, cartPath+"/checkout", checkout, ownerID, key)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// Without it the cart is already gone.
+		resp = env.RequestWithAuth(t, http.MethodPost, cartPath+"/checkout", checkout, cashierID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/inventory/levels?location_id=%d", shopID, mainID), nil, keeperID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var levelsBody map[string]any
+		resp.JSON(t, &levelsBody)
+		levels := levelsBody["data"].(map[string]any)["levels"].([]any)
+		require.Len(t, levels, 1)
+		assert.Equal(t, float64(3), levels[0].(map[string]any)["on_hand"])
+
+		resp = env.RequestWithAuth(t, http.MethodGet, salesPath, nil, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var listBody map[string]any
+		resp.JSON(t, &listBody)
+		assert.Len(t, listBody["data"].(map[string]any)["sales"], 1)
+	})
+
+	t.Run("short stock sells nothing", func(t *testing.T) {
+		cartPath := openCart(t)
+
+		resp := env.RequestWithAuth(t, http.MethodPost, cartPath+"/lines", map[string]any{"sku": "BEANS", "quantity": 4}, cashierID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, cartPath+"/checkout", map[string]any{
+			"payments": []map[string]any{{"method": "cash", "amount": 5000}},
+		}, cashierID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, cartPath, nil, cashierID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Equal(t, "open", body["data"].(map[string]any)["cart"].(map[string]any)["status"])
+	})
+
+	t.Run("ringing up sales takes sales.create", func(t *testing.T) {
+		resp := env.RequestWithAuth(t, http.MethodPost, cartsPath, map[string]any{"location_id": mainID}, keeperID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+// checkoutWithKey checks out a cart sending key as the X-Idempotency-Key.
+func checkoutWithKey(t *testing.T, env *TestEnv, path string, body any, userID uint64, key string) *Response {
+	jsonBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, path, bytes.NewReader(jsonBytes))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+env.AccessToken(t, userID))
+	req.Header.Set("X-Idempotency-Key", key)
+
+	resp, err := env.App.Test(req, fiber.TestConfig{
+		Timeout: 10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		Body:       respBody,
+	}
+}
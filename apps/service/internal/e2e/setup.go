@@ -8,10 +8,12 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -19,20 +21,32 @@ import (
 	"gorm.io/gorm"
 
 	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	adminentities "github.com/reno1r/weiss/apps/service/internal/app/admin/entities"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+	catalogentities "github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	inventoryentities "github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	salesentities "github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
 	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 	"github.com/reno1r/weiss/apps/service/internal/config"
+	weissdb "github.com/reno1r/weiss/apps/service/internal/db"
 	weisshttp "github.com/reno1r/weiss/apps/service/internal/http"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
 )
 
 // TestEnv holds the test environment configuration
 type TestEnv struct {
-	App       *fiber.App
-	DB        *gorm.DB
-	Container testcontainers.Container
-	Ctx       context.Context
-	isLive    bool
-	liveURL   string
+	App          *fiber.App
+	DB           *gorm.DB
+	Container    testcontainers.Container
+	Ctx          context.Context
+	TokenService *services.TokenService
+	Mailer       *mail.MemoryMailer
+	config       *config.Config
+	isLive       bool
+	liveURL      string
 }
 
 // SetupTestEnv creates a new test environment with testcontainers
@@ -82,8 +96,39 @@ func SetupTestEnv(t *testing.T) *TestEnv {
 	err = db.AutoMigrate(
 		&entities.User{},
 		&shopentities.Shop{},
+		&shopentities.Location{},
 		&accessentities.Role{},
 		&accessentities.Staff{},
+		&accessentities.RolePermission{},
+		&accessentities.StaffPermission{},
+		&accessentities.StaffLocation{},
+		&accessentities.AccessAuditLog{},
+		&accessentities.Invitation{},
+		&accessentities.APIKey{},
+		&accessentities.APIKeyPermission{},
+		&catalogentities.Category{},
+		&catalogentities.Product{},
+		&catalogentities.ProductVariant{},
+		&inventoryentities.Settings{},
+		&inventoryentities.StockLevel{},
+		&inventoryentities.StockMovement{},
+		&salesentities.Cart{},
+		&salesentities.CartLine{},
+		&salesentities.ReceiptSequence{},
+		&salesentities.Sale{},
+		&salesentities.SaleLine{},
+		&salesentities.SalePayment{},
+		&salesentities.RegisterSession{},
+		&salesentities.RegisterCashMovement{},
+		&authentities.Session{},
+		&authentities.RefreshToken{},
+		&authentities.PasswordReset{},
+		&authentities.VerificationCode{},
+		&authentities.TotpFactor{},
+		&authentities.RecoveryCode{},
+		&authentities.LoginAttempt{},
+		&adminentities.AdminAuditLog{},
+		&weisshttp.IdempotencyRecord{},
 	)
 	require.NoError(t, err)
 
@@ -99,27 +144,85 @@ func SetupTestEnv(t *testing.T) *TestEnv {
 		JwtAccessExpires:   "15m",
 		JwtRefreshExpires:  "168h", // 7 days
 		CorsAllowedOrigins: "*",
+		MailDriver:         mail.DriverMemory,
+		SmsDriver:          sms.DriverMemory,
+		PasswordResetURL:   "http://localhost:3000/reset-password",
+		InvitationURL:      "http://localhost:3000/invitations",
 	}
 
-	// Create test middleware to set user_id from X-Test-User-ID header
-	testMiddleware := func(c fiber.Ctx) error {
-		if userIDHeader := c.Get("X-Test-User-ID"); userIDHeader != "" {
-			var userID uint64
-			if _, err := fmt.Sscanf(userIDHeader, "%d", &userID); err == nil && userID > 0 {
-				c.Locals("user_id", userID)
-			}
-		}
-		return c.Next()
+	server, err := weisshttp.NewServer(cfg, db)
+	require.NoError(t, err)
+	app := server.App()
+
+	tokenService, err := services.NewTokenService(cfg)
+	require.NoError(t, err)
+
+	return &TestEnv{
+		App:          app,
+		DB:           db,
+		Container:    pgContainer,
+		Ctx:          ctx,
+		TokenService: tokenService,
+		Mailer:       server.Mailer().(*mail.MemoryMailer),
+		config:       cfg,
 	}
+}
 
-	server := weisshttp.NewServerWithTestMiddleware(cfg, db, testMiddleware)
-	app := server.App()
+// rowLevelSecurityMigration holds the tenant policies. AutoMigrate does not
+// create them, and they never restrict the superuser the tests connect as.
+const rowLevelSecurityMigration = "../../migrations/20260126084500_add_tenant_row_level_security.sql"
+
+// WithRowLevelSecurity installs the tenant policies in the environment's
+// database and returns a second environment on the same database whose
+// server runs in row-level security mode, connected as a role without
+// SUPERUSER or BYPASSRLS so that the policies apply to it.
+func (e *TestEnv) WithRowLevelSecurity(t *testing.T) *TestEnv {
+	migration, err := os.ReadFile(rowLevelSecurityMigration)
+	require.NoError(t, err)
+	up, _, found := strings.Cut(string(migration), "-- +goose Down")
+	require.True(t, found)
+
+	sqlDB, err := e.DB.DB()
+	require.NoError(t, err)
+	_, err = sqlDB.ExecContext(e.Ctx, up)
+	require.NoError(t, err)
+	_, err = sqlDB.ExecContext(e.Ctx, `
+		CREATE ROLE weiss_app LOGIN PASSWORD 'weiss_app' NOSUPERUSER NOBYPASSRLS;
+		GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO weiss_app;
+		GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO weiss_app;
+	`)
+	require.NoError(t, err)
+
+	host, err := e.Container.Host(e.Ctx)
+	require.NoError(t, err)
+	port, err := e.Container.MappedPort(e.Ctx, "5432")
+	require.NoError(t, err)
+
+	cfg := *e.config
+	cfg.DatabaseHost = host
+	cfg.DatabasePort = port.Int()
+	cfg.DatabaseName = "test"
+	cfg.DatabaseUser = "weiss_app"
+	cfg.DatabasePassword = "weiss_app"
+	cfg.DatabaseSSL = "disable"
+	cfg.DatabaseRowLevelSecurity = true
+
+	database, err := weissdb.NewDatabase(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = database.Close()
+	})
+
+	server, err := weisshttp.NewServer(&cfg, database.DB())
+	require.NoError(t, err)
 
 	return &TestEnv{
-		App:       app,
-		DB:        db,
-		Container: pgContainer,
-		Ctx:       ctx,
+		App:          server.App(),
+		DB:           database.DB(),
+		Ctx:          e.Ctx,
+		TokenService: e.TokenService,
+		Mailer:       server.Mailer().(*mail.MemoryMailer),
+		config:       &cfg,
 	}
 }
 
@@ -142,7 +245,7 @@ func (e *TestEnv) CleanupDB(t *testing.T) {
 		return
 	}
 	// Truncate in order to respect foreign key constraints
-	err := e.DB.WithContext(e.Ctx).Exec("TRUNCATE TABLE staffs, roles, shops, users RESTART IDENTITY CASCADE").Error
+	err := e.DB.WithContext(e.Ctx).Exec("TRUNCATE TABLE idempotency_keys, sale_payments, sale_lines, sales, register_cash_movements, register_sessions, receipt_sequences, cart_lines, carts, stock_movements, stock_levels, inventory_settings, product_variants, products, categories, admin_audit_logs, api_key_permissions, api_keys, login_attempts, recovery_codes, totp_factors, verification_codes, password_resets, refresh_tokens, sessions, invitations, access_audit_logs, staff_locations, staff_permissions, role_permissions, staffs, roles, locations, shops, users RESTART IDENTITY CASCADE").Error
 	require.NoError(t, err)
 }
 
@@ -178,6 +281,7 @@ func (e *TestEnv) doTestRequest(t *testing.T, method, path string, body io.Reade
 
 	return &Response{
 		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
 		Body:       respBody,
 	}
 }
@@ -204,8 +308,54 @@ func (e *TestEnv) doTestRequestWithAuth(t *testing.T, method, path string, body
 	req.Header.Set("Content-Type", "application/json")
 
 	if userID > 0 {
-		req.Header.Set("X-Test-User-ID", fmt.Sprintf("%d", userID))
+		req.Header.Set("Authorization", "Bearer "+e.AccessToken(t, userID))
+	}
+
+	resp, err := e.App.Test(req, fiber.TestConfig{
+		Timeout: 10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		Body:       respBody,
+	}
+}
+
+// AccessToken starts a session for the given user and issues a signed access
+// token belonging to it
+func (e *TestEnv) AccessToken(t *testing.T, userID uint64) string {
+	session := authentities.Session{
+		UserID:     userID,
+		TokenID:    uuid.NewString(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastUsedAt: time.Now(),
 	}
+	require.NoError(t, e.DB.Create(&session).Error)
+
+	token, err := e.TokenService.GenerateAccessToken(userID, "", "", session.ID)
+	require.NoError(t, err)
+	return token
+}
+
+// RequestWithToken makes an HTTP request with the given bearer token
+func (e *TestEnv) RequestWithToken(t *testing.T, method, path string, body any, token string) *Response {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
+		require.NoError(t, err)
+		reqBody = bytes.NewReader(jsonBytes)
+	}
+
+	req, err := http.NewRequest(method, path, reqBody)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 
 	resp, err := e.App.Test(req, fiber.TestConfig{
 		Timeout: 10 * time.Second,
@@ -218,6 +368,7 @@ func (e *TestEnv) doTestRequestWithAuth(t *testing.T, method, path string, body
 
 	return &Response{
 		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
 		Body:       respBody,
 	}
 }
@@ -237,6 +388,7 @@ func (e *TestEnv) doLiveRequest(t *testing.T, method, url string, body io.Reader
 
 	return &Response{
 		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
 		Body:       respBody,
 	}
 }
@@ -244,6 +396,7 @@ func (e *TestEnv) doLiveRequest(t *testing.T, method, url string, body io.Reader
 // Response wraps an HTTP response
 type Response struct {
 	StatusCode int
+	Headers    http.Header
 	Body       []byte
 }
 
@@ -3,6 +3,8 @@ package e2e
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,7 +18,7 @@ func TestListShops(t *testing.T) {
 	t.Run("empty list", func(t *testing.T) {
 		env.CleanupDB(t)
 
-		resp := env.Request(t, http.MethodGet, "/api/shops", nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops", nil, 1)
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -74,7 +76,7 @@ func TestListShops(t *testing.T) {
 		require.Equal(t, http.StatusCreated, resp2.StatusCode)
 
 		// List shops
-		resp := env.Request(t, http.MethodGet, "/api/shops", nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops", nil, userID)
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -85,6 +87,73 @@ func TestListShops(t *testing.T) {
 		data := body["data"].(map[string]any)
 		shops := data["shops"].([]any)
 		assert.Len(t, shops, 2)
+
+		membership := shops[0].(map[string]any)["membership"].(map[string]any)
+		assert.Equal(t, "active", membership["status"])
+		assert.Equal(t, "Owner", membership["role"].(map[string]any)["name"])
+	})
+
+	t.Run("only lists the caller's shops", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		otherID := registerUser(t, env, "other@example.com", "+1234567891")
+		createShop(t, env, ownerID)
+
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops", nil, otherID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		assert.Empty(t, body["data"].(map[string]any)["shops"])
+	})
+
+	t.Run("searches and pages", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		for _, name := range []string{"Corner Bakery", "Bakery Express", "Hardware Store"} {
+			payload := map[string]string{
+				"name":        name,
+				"description": "A shop used in tests",
+				"address":     "123 Main St",
+				"phone":       "1111111111",
+				"email":       "shop@example.com",
+				"website":     "https://shop.example.com",
+				"logo":        "logo.png",
+			}
+			resp := env.RequestWithAuth(t, http.MethodPost, "/api/shops", payload, ownerID)
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops?q=bakery&limit=1", nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		resp.JSON(t, &body)
+		data := body["data"].(map[string]any)
+		shops := data["shops"].([]any)
+		require.Len(t, shops, 1)
+		assert.Equal(t, "Bakery Express", shops[0].(map[string]any)["name"])
+		cursor := data["next_cursor"].(string)
+		require.NotEmpty(t, cursor)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, "/api/shops?q=bakery&limit=1&cursor="+url.QueryEscape(cursor), nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body = nil
+		resp.JSON(t, &body)
+		data = body["data"].(map[string]any)
+		shops = data["shops"].([]any)
+		require.Len(t, shops, 1)
+		assert.Equal(t, "Corner Bakery", shops[0].(map[string]any)["name"])
+		assert.Nil(t, data["next_cursor"])
+
+		resp = env.RequestWithAuth(t, http.MethodGet, "/api/shops?sort=revenue", nil, ownerID)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, "/api/shops?limit=abc", nil, ownerID)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 }
 
@@ -131,7 +200,7 @@ func TestGetShop(t *testing.T) {
 		shopID := uint64(shop["id"].(float64))
 
 		// Get shop
-		resp := env.Request(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, userID)
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -150,16 +219,45 @@ func TestGetShop(t *testing.T) {
 		assert.Equal(t, "logo.png", retrievedShop["logo"])
 	})
 
+	t.Run("forbidden for users outside the shop", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		otherID := registerUser(t, env, "other@example.com", "+1987654321")
+
+		shopPayload := map[string]string{
+			"name":        "Private Shop",
+			"description": "A shop for its staff only",
+			"address":     "123 Main St",
+			"phone":       "1111111111",
+			"email":       "private@example.com",
+			"website":     "https://private.com",
+			"logo":        "private.png",
+		}
+		createResp := env.RequestWithAuth(t, http.MethodPost, "/api/shops", shopPayload, ownerID)
+		require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+		var createBody map[string]any
+		createResp.JSON(t, &createBody)
+		shopID := uint64(createBody["data"].(map[string]any)["shop"].(map[string]any)["id"].(float64))
+
+		resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, otherID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs", shopID), nil, otherID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		env.CleanupDB(t)
 
-		resp := env.Request(t, http.MethodGet, "/api/shops/99999", nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops/99999", nil, 1)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		resp := env.Request(t, http.MethodGet, "/api/shops/invalid", nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops/invalid", nil, 1)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
@@ -407,7 +505,7 @@ func TestUpdateShop(t *testing.T) {
 			"website":     "https://updated.com",
 			"logo":        "updated.png",
 		}
-		resp := env.Request(t, http.MethodPut, fmt.Sprintf("/api/shops/%d", shopID), updatePayload)
+		resp := env.RequestWithAuth(t, http.MethodPut, fmt.Sprintf("/api/shops/%d", shopID), updatePayload, userID)
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -426,6 +524,36 @@ func TestUpdateShop(t *testing.T) {
 		assert.Equal(t, "updated.png", updatedShop["logo"])
 	})
 
+	t.Run("forbidden for users outside the shop", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		otherID := registerUser(t, env, "other@example.com", "+1987654321")
+
+		shopPayload := map[string]string{
+			"name":        "Original Shop",
+			"description": "Original description",
+			"address":     "123 Main St",
+			"phone":       "1111111111",
+			"email":       "original@example.com",
+			"website":     "https://original.com",
+			"logo":        "original.png",
+		}
+		createResp := env.RequestWithAuth(t, http.MethodPost, "/api/shops", shopPayload, ownerID)
+		require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+		var createBody map[string]any
+		createResp.JSON(t, &createBody)
+		shopID := uint64(createBody["data"].(map[string]any)["shop"].(map[string]any)["id"].(float64))
+
+		shopPayload["name"] = "Hijacked Shop"
+		resp := env.RequestWithAuth(t, http.MethodPut, fmt.Sprintf("/api/shops/%d", shopID), shopPayload, otherID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d", shopID), nil, otherID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		env.CleanupDB(t)
 
@@ -438,7 +566,7 @@ func TestUpdateShop(t *testing.T) {
 			"website":     "https://updated.com",
 			"logo":        "updated.png",
 		}
-		resp := env.Request(t, http.MethodPut, "/api/shops/99999", updatePayload)
+		resp := env.RequestWithAuth(t, http.MethodPut, "/api/shops/99999", updatePayload, 1)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
@@ -453,7 +581,7 @@ func TestUpdateShop(t *testing.T) {
 			"website":     "https://updated.com",
 			"logo":        "updated.png",
 		}
-		resp := env.Request(t, http.MethodPut, "/api/shops/invalid", updatePayload)
+		resp := env.RequestWithAuth(t, http.MethodPut, "/api/shops/invalid", updatePayload, 1)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
@@ -495,7 +623,7 @@ func TestUpdateShop(t *testing.T) {
 		shop := createData["shop"].(map[string]any)
 		shopID := uint64(shop["id"].(float64))
 
-		resp := env.Request(t, http.MethodPut, fmt.Sprintf("/api/shops/%d", shopID), "invalid json")
+		resp := env.RequestWithAuth(t, http.MethodPut, fmt.Sprintf("/api/shops/%d", shopID), "invalid json", userID)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
@@ -544,25 +672,25 @@ func TestDeleteShop(t *testing.T) {
 		shopID := uint64(shop["id"].(float64))
 
 		// Delete shop
-		resp := env.Request(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d", shopID), nil)
+		resp := env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d", shopID), nil, userID)
 
 		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 
 		// Verify shop is deleted (soft delete)
-		getResp := env.Request(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil)
+		getResp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, userID)
 		assert.Equal(t, http.StatusNotFound, getResp.StatusCode)
 	})
 
 	t.Run("not found", func(t *testing.T) {
 		env.CleanupDB(t)
 
-		resp := env.Request(t, http.MethodDelete, "/api/shops/99999", nil)
+		resp := env.RequestWithAuth(t, http.MethodDelete, "/api/shops/99999", nil, 1)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		resp := env.Request(t, http.MethodDelete, "/api/shops/invalid", nil)
+		resp := env.RequestWithAuth(t, http.MethodDelete, "/api/shops/invalid", nil, 1)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
@@ -611,7 +739,7 @@ func TestGetStaff(t *testing.T) {
 		shopID := uint64(shop["id"].(float64))
 
 		// Get staff (shop owner is auto-assigned during shop creation)
-		resp := env.Request(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs", shopID), nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d/staffs", shopID), nil, userID)
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
@@ -625,16 +753,16 @@ func TestGetStaff(t *testing.T) {
 		assert.GreaterOrEqual(t, len(staffs), 1)
 	})
 
-	t.Run("shop not found", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		env.CleanupDB(t)
 
-		resp := env.Request(t, http.MethodGet, "/api/shops/99999/staffs", nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops/99999/staffs", nil, 1)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
 	t.Run("invalid shop id", func(t *testing.T) {
-		resp := env.Request(t, http.MethodGet, "/api/shops/invalid/staffs", nil)
+		resp := env.RequestWithAuth(t, http.MethodGet, "/api/shops/invalid/staffs", nil, 1)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
@@ -719,7 +847,7 @@ func TestAssignStaff(t *testing.T) {
 			"user_id": staffUserID,
 			"role_id": roleID,
 		}
-		resp := env.Request(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload)
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload, ownerID)
 
 		assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
@@ -734,14 +862,14 @@ func TestAssignStaff(t *testing.T) {
 		assert.Equal(t, "Staff Member", staffUserData["full_name"])
 	})
 
-	t.Run("shop not found", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		env.CleanupDB(t)
 
 		assignPayload := map[string]any{
 			"user_id": 1,
 			"role_id": 1,
 		}
-		resp := env.Request(t, http.MethodPost, "/api/shops/99999/staffs", assignPayload)
+		resp := env.RequestWithAuth(t, http.MethodPost, "/api/shops/99999/staffs", assignPayload, 1)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
@@ -788,7 +916,7 @@ func TestAssignStaff(t *testing.T) {
 			"user_id": 99999,
 			"role_id": 1,
 		}
-		resp := env.Request(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload)
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload, ownerID)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
@@ -851,7 +979,7 @@ func TestAssignStaff(t *testing.T) {
 			"user_id": staffUserID,
 			"role_id": 99999,
 		}
-		resp := env.Request(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload)
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload, ownerID)
 
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
@@ -894,7 +1022,7 @@ func TestAssignStaff(t *testing.T) {
 		shop := createData["shop"].(map[string]any)
 		shopID := uint64(shop["id"].(float64))
 
-		resp := env.Request(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), "invalid json")
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), "invalid json", ownerID)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
@@ -941,10 +1069,373 @@ func TestAssignStaff(t *testing.T) {
 			"user_id": 1,
 			// role_id is missing - this will cause the handler to try fetching role with ID 0, which returns 404
 		}
-		resp := env.Request(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload)
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), assignPayload, ownerID)
 
 		// Handler tries to fetch role with ID 0 (default uint64 value), which doesn't exist, so returns 404
 		// This is technically a validation issue in the handler, but we test the actual behavior
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 }
+
+func TestStaffAccess(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("grant and revoke", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		cashierID := registerUser(t, env, "cashier@example.com", "+1987654321")
+		shopID := createShop(t, env, ownerID)
+
+		var runnerRoleID uint64
+		err := env.DB.WithContext(env.Ctx).Raw(
+			"INSERT INTO roles (name, description, shop_id) VALUES ('Runner', 'Rings up sales', ?) RETURNING id",
+			shopID,
+		).Scan(&runnerRoleID).Error
+		require.NoError(t, err)
+		require.NoError(t, env.DB.WithContext(env.Ctx).Exec(
+			"INSERT INTO role_permissions (role_id, permission) VALUES (?, 'sales.create')", runnerRoleID,
+		).Error)
+
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), map[string]any{
+			"user_id": cashierID,
+			"role_id": runnerRoleID,
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var assignBody map[string]any
+		resp.JSON(t, &assignBody)
+		staffID := uint64(assignBody["data"].(map[string]any)["staff"].(map[string]any)["id"].(float64))
+		accessPath := fmt.Sprintf("/api/shops/%d/staffs/%d/access", shopID, staffID)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, accessPath, map[string]any{"permission": "reports.view"}, ownerID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, accessPath, map[string]any{"permission": "reports.view"}, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, accessPath, nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var accessBody map[string]any
+		resp.JSON(t, &accessBody)
+		data := accessBody["data"].(map[string]any)
+		assert.Equal(t, []any{"reports.view"}, data["permissions"])
+		assert.Equal(t, []any{"sales.create", "reports.view"}, data["effective_permissions"])
+		assert.Len(t, data["history"], 1)
+
+		// The cashier holds neither staff.view nor staff.assign.
+		resp = env.RequestWithAuth(t, http.MethodGet, accessPath, nil, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, accessPath, map[string]any{"role_id": runnerRoleID}, ownerID)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("cannot revoke the last owner", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		shopID := createShop(t, env, ownerID)
+
+		var owner struct {
+			ID     uint64
+			RoleID uint64
+		}
+		err := env.DB.WithContext(env.Ctx).Raw(
+			"SELECT id, role_id FROM staffs WHERE shop_id = ? AND user_id = ?", shopID, ownerID,
+		).Scan(&owner).Error
+		require.NoError(t, err)
+
+		resp := env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d/staffs/%d/access", shopID, owner.ID), map[string]any{"role_id": owner.RoleID}, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestRoles(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("manage roles", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		cashierID := registerUser(t, env, "cashier@example.com", "+1987654321")
+		shopID := createShop(t, env, ownerID)
+		rolesPath := fmt.Sprintf("/api/shops/%d/roles", shopID)
+
+		resp := env.RequestWithAuth(t, http.MethodGet, rolesPath, nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listBody map[string]any
+		resp.JSON(t, &listBody)
+		roles := listBody["data"].(map[string]any)["roles"].([]any)
+		require.Len(t, roles, 4)
+		roleIDs := map[string]uint64{}
+		for _, role := range roles {
+			role := role.(map[string]any)
+			roleIDs[role["name"].(string)] = uint64(role["id"].(float64))
+		}
+		assert.True(t, roles[0].(map[string]any)["is_system"].(bool))
+
+		resp = env.RequestWithAuth(t, http.MethodPost, rolesPath, map[string]any{
+			"name":        "Supervisor",
+			"description": "Looks after the floor",
+			"permissions": []string{"reports.view", "sales.create"},
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var createBody map[string]any
+		resp.JSON(t, &createBody)
+		created := createBody["data"].(map[string]any)["role"].(map[string]any)
+		assert.Equal(t, []any{"sales.create", "reports.view"}, created["permissions"])
+		supervisorPath := fmt.Sprintf("%s/%d", rolesPath, uint64(created["id"].(float64)))
+
+		resp = env.RequestWithAuth(t, http.MethodPost, rolesPath, map[string]any{"name": "supervisor"}, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPut, supervisorPath, map[string]any{
+			"name":        "Floor Supervisor",
+			"permissions": []string{"sales.create"},
+		}, ownerID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPut, fmt.Sprintf("%s/%d", rolesPath, roleIDs["Owner"]), map[string]any{"name": "Boss"}, ownerID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), map[string]any{
+			"user_id": cashierID,
+			"role_id": roleIDs["Cashier"],
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// The cashier holds no role.manage.
+		resp = env.RequestWithAuth(t, http.MethodDelete, supervisorPath, nil, cashierID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		cashierPath := fmt.Sprintf("%s/%d", rolesPath, roleIDs["Cashier"])
+		resp = env.RequestWithAuth(t, http.MethodDelete, cashierPath, nil, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s?reassign_to=%d", cashierPath, roleIDs["Stock Keeper"]), nil, ownerID)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, cashierPath, nil, ownerID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestStaffLifecycle(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("suspend, re-role, transfer ownership and remove", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		clerkID := registerUser(t, env, "clerk@example.com", "+1987654321")
+		shopID := createShop(t, env, ownerID)
+
+		var roles []struct {
+			ID   uint64
+			Name string
+		}
+		require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id, name FROM roles WHERE shop_id = ?", shopID).Scan(&roles).Error)
+		roleIDs := map[string]uint64{}
+		for _, role := range roles {
+			roleIDs[role.Name] = role.ID
+		}
+
+		var ownerStaffID uint64
+		require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id FROM staffs WHERE shop_id = ? AND user_id = ?", shopID, ownerID).Scan(&ownerStaffID).Error)
+
+		resp := env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/staffs", shopID), map[string]any{
+			"user_id": clerkID,
+			"role_id": roleIDs["Cashier"],
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var assignBody map[string]any
+		resp.JSON(t, &assignBody)
+		clerkStaffID := uint64(assignBody["data"].(map[string]any)["staff"].(map[string]any)["id"].(float64))
+		clerkPath := fmt.Sprintf("/api/shops/%d/staffs/%d", shopID, clerkStaffID)
+		shopPath := fmt.Sprintf("/api/shops/%d", shopID)
+
+		resp = env.RequestWithAuth(t, http.MethodPatch, clerkPath, map[string]any{"status": "suspended"}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, shopPath, nil, clerkID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPatch, clerkPath, map[string]any{"status": "active", "role_id": roleIDs["Manager"]}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var updateBody map[string]any
+		resp.JSON(t, &updateBody)
+		staff := updateBody["data"].(map[string]any)["staff"].(map[string]any)
+		assert.Equal(t, "active", staff["status"])
+		assert.Equal(t, "Manager", staff["role"].(map[string]any)["name"])
+
+		resp = env.RequestWithAuth(t, http.MethodGet, shopPath, nil, clerkID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d/staffs/%d", shopID, ownerStaffID), nil, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// A Manager holds staff.remove but cannot remove an Owner.
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d/staffs/%d", shopID, ownerStaffID), nil, clerkID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/transfer-ownership", shopID), map[string]any{
+			"staff_id": clerkStaffID,
+			"role_id":  roleIDs["Manager"],
+		}, clerkID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, fmt.Sprintf("/api/shops/%d/transfer-ownership", shopID), map[string]any{
+			"staff_id": clerkStaffID,
+			"role_id":  roleIDs["Manager"],
+		}, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// The clerk is now the Owner, so the former owner cannot remove them.
+		resp = env.RequestWithAuth(t, http.MethodDelete, clerkPath, nil, ownerID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("/api/shops/%d/staffs/%d", shopID, ownerStaffID), nil, clerkID)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, shopPath, nil, ownerID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestInvitations(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer env.Cleanup(t)
+
+	t.Run("invite before registration and accept", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		shopID := createShop(t, env, ownerID)
+		invitationsPath := fmt.Sprintf("/api/shops/%d/invitations", shopID)
+
+		var cashierRoleID uint64
+		require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id FROM roles WHERE shop_id = ? AND name = 'Cashier'", shopID).Scan(&cashierRoleID).Error)
+
+		resp := env.RequestWithAuth(t, http.MethodPost, invitationsPath, map[string]any{
+			"email":   "invitee@example.com",
+			"role_id": cashierRoleID,
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, invitationsPath, map[string]any{
+			"email":   "invitee@example.com",
+			"role_id": cashierRoleID,
+		}, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		message, ok := env.Mailer.LastMessageTo("invitee@example.com")
+		require.True(t, ok)
+		_, token, found := strings.Cut(message.Body, "?token=")
+		require.True(t, found)
+		token, _, _ = strings.Cut(token, "\n")
+		token, err := url.QueryUnescape(token)
+		require.NoError(t, err)
+
+		inviteeID := registerUser(t, env, "invitee@example.com", "+1987654321")
+
+		resp = env.RequestWithAuth(t, http.MethodGet, "/api/me/invitations", nil, inviteeID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listBody map[string]any
+		resp.JSON(t, &listBody)
+		invitations := listBody["data"].(map[string]any)["invitations"].([]any)
+		require.Len(t, invitations, 1)
+		assert.Equal(t, "Shop", invitations[0].(map[string]any)["shop_name"])
+
+		// Only the invitee can use the token.
+		resp = env.RequestWithAuth(t, http.MethodPost, "/api/invitations/accept", map[string]string{"token": token}, ownerID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, "/api/invitations/accept", map[string]string{"token": token}, inviteeID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodGet, fmt.Sprintf("/api/shops/%d", shopID), nil, inviteeID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodPost, "/api/invitations/decline", map[string]string{"token": token}, inviteeID)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("revoke a pending invitation", func(t *testing.T) {
+		env.CleanupDB(t)
+
+		ownerID := registerUser(t, env, "owner@example.com", "+1234567890")
+		shopID := createShop(t, env, ownerID)
+		invitationsPath := fmt.Sprintf("/api/shops/%d/invitations", shopID)
+
+		var cashierRoleID uint64
+		require.NoError(t, env.DB.WithContext(env.Ctx).Raw("SELECT id FROM roles WHERE shop_id = ? AND name = 'Cashier'", shopID).Scan(&cashierRoleID).Error)
+
+		resp := env.RequestWithAuth(t, http.MethodPost, invitationsPath, map[string]any{
+			"phone":   "+1555555555",
+			"role_id": cashierRoleID,
+		}, ownerID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var createBody map[string]any
+		resp.JSON(t, &createBody)
+		invitationID := uint64(createBody["data"].(map[string]any)["invitation"].(map[string]any)["id"].(float64))
+
+		resp = env.RequestWithAuth(t, http.MethodGet, invitationsPath, nil, ownerID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listBody map[string]any
+		resp.JSON(t, &listBody)
+		assert.Len(t, listBody["data"].(map[string]any)["invitations"], 1)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s/%d", invitationsPath, invitationID), nil, ownerID)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = env.RequestWithAuth(t, http.MethodDelete, fmt.Sprintf("%s/%d", invitationsPath, invitationID), nil, ownerID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
+
+// registerUser registers a user and returns its ID
+func registerUser(t *testing.T, env *TestEnv, email, phone string) uint64 {
+	resp := env.Request(t, http.MethodPost, "/api/auth/register", map[string]string{
+		"full_name": "Test User",
+		"email":     email,
+		"phone":     phone,
+		"password":  "SecurePass123!",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	return uint64(body["data"].(map[string]any)["user"].(map[string]any)["id"].(float64))
+}
+
+func createShop(t *testing.T, env *TestEnv, ownerID uint64) uint64 {
+	resp := env.RequestWithAuth(t, http.MethodPost, "/api/shops", map[string]string{
+		"name":        "Shop",
+		"description": "Description",
+		"address":     "123 Main St",
+		"phone":       "1111111111",
+		"email":       "shop@example.com",
+		"website":     "https://shop.com",
+		"logo":        "logo.png",
+	}, ownerID)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var body map[string]any
+	resp.JSON(t, &body)
+	return uint64(body["data"].(map[string]any)["shop"].(map[string]any)["id"].(float64))
+}
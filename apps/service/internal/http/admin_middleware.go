@@ -0,0 +1,30 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+// NewAdminMiddleware restricts a route to platform administrators who are
+// not suspended. It must run after the auth middleware.
+func NewAdminMiddleware(userRepository userrepositories.UserRepository) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+
+		user, err := userRepository.FindByID(c.Context(), principal.UserID)
+		if err != nil {
+			return unauthorized(c, "user not found")
+		}
+
+		if !user.IsAdmin || user.IsSuspended() {
+			return fiber.NewError(fiber.StatusForbidden, "forbidden: platform administrators only")
+		}
+
+		return c.Next()
+	}
+}
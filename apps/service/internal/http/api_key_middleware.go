@@ -0,0 +1,28 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+// NewNoAPIKeyMiddleware rejects requests made with an API key. It guards the
+// shop routes whose checks run in the usecase against the creator's own
+// permissions, where the permissions selected for a key would not apply:
+// assigning, changing and removing staff, granting and revoking access,
+// managing roles and invitations, transferring ownership, and the
+// management of keys themselves. It must run after the auth middleware.
+func NewNoAPIKeyMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+
+		if principal.UsesAPIKey() {
+			return fiber.NewError(fiber.StatusForbidden, "forbidden: not allowed with an api key")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+	authusecases "github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+const bearerScheme = "Bearer"
+
+// NewAuthMiddleware authenticates requests using the access token or API key
+// from the Authorization header and stores the resulting principal in the
+// context. API keys are only accepted on the routes of the shop they belong
+// to; anywhere else they would act with the whole account of their creator.
+// Access tokens of suspended users are refused with 403.
+func NewAuthMiddleware(authenticateAccessTokenUsecase *authusecases.AuthenticateAccessTokenUsecase, authenticateAPIKeyUsecase *accessusecases.AuthenticateAPIKeyUsecase) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		token, ok := extractBearerToken(c.Get(fiber.HeaderAuthorization))
+		if !ok {
+			return unauthorized(c, "missing or malformed bearer token")
+		}
+
+		if accessservices.IsAPIKey(token) {
+			apiKey, err := authenticateAPIKeyUsecase.Execute(c.Context(), token)
+			if err != nil {
+				if err.Error() == "invalid api key" {
+					return unauthorized(c, "invalid, expired or revoked api key")
+				}
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to authenticate api key")
+			}
+			if !isShopPath(c.Path(), apiKey.ShopID) {
+				return fiber.NewError(fiber.StatusForbidden, "forbidden: api key is limited to the routes of its shop")
+			}
+
+			handlers.SetPrincipal(c, &authentities.Principal{
+				UserID: apiKey.UserID,
+				APIKey: apiKey,
+			})
+			return c.Next()
+		}
+
+		principal, err := authenticateAccessTokenUsecase.Execute(c.Context(), token)
+		if err != nil {
+			switch {
+			case err.Error() == "invalid access token":
+				return unauthorized(c, "invalid or expired access token")
+			case strings.HasPrefix(err.Error(), "account suspended"):
+				return fiber.NewError(fiber.StatusForbidden, err.Error())
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to authenticate access token")
+		}
+
+		handlers.SetPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+func extractBearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, bearerScheme) {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", false
+	}
+
+	return token, true
+}
+
+// isShopPath reports whether path addresses shopID or one of its
+// sub-resources.
+func isShopPath(path string, shopID uint64) bool {
+	root := fmt.Sprintf("/api/shops/%d", shopID)
+	return path == root || strings.HasPrefix(path, root+"/")
+}
+
+func unauthorized(c fiber.Ctx, detail string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, bearerScheme)
+	return fiber.NewError(fiber.StatusUnauthorized, detail)
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+)
+
+type AccessHandler struct {
+	getStaffAccessUsecase *accessusecases.GetStaffAccessUsecase
+	grantAccessUsecase    *accessusecases.GrantAccessUsecase
+	revokeAccessUsecase   *accessusecases.RevokeAccessUsecase
+	setLocationsUsecase   *accessusecases.SetStaffLocationsUsecase
+}
+
+func NewAccessHandler(getStaffAccessUsecase *accessusecases.GetStaffAccessUsecase, grantAccessUsecase *accessusecases.GrantAccessUsecase, revokeAccessUsecase *accessusecases.RevokeAccessUsecase, setLocationsUsecase *accessusecases.SetStaffLocationsUsecase) *AccessHandler {
+	return &AccessHandler{
+		getStaffAccessUsecase: getStaffAccessUsecase,
+		grantAccessUsecase:    grantAccessUsecase,
+		revokeAccessUsecase:   revokeAccessUsecase,
+		setLocationsUsecase:   setLocationsUsecase,
+	}
+}
+
+// AccessPayload names the access to grant or revoke. Exactly one of the
+// fields must be set.
+type AccessPayload struct {
+	Permission string `json:"permission,omitempty" example:"reports.view"` // Permission to grant or revoke
+	RoleID     uint64 `json:"role_id,omitempty" example:"2"`               // Role to grant or revoke
+}
+
+type StaffLocationsPayload struct {
+	LocationIDs []uint64 `json:"location_ids" example:"1,2"` // Locations the staff member works at; empty for every location
+}
+
+type StaffLocationsResponse struct {
+	Message string                     `json:"message"`
+	Data    StaffLocationsResponseData `json:"data"`
+}
+
+type StaffLocationsResponseData struct {
+	LocationIDs []uint64 `json:"location_ids" example:"1,2"`
+}
+
+type AccessAuditLogDTO struct {
+	ID         uint64    `json:"id" example:"1"`
+	ActorID    uint64    `json:"actor_id" example:"1"`
+	Action     string    `json:"action" example:"grant"`
+	Permission string    `json:"permission,omitempty" example:"reports.view"`
+	RoleID     *uint64   `json:"role_id,omitempty" example:"2"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type StaffAccessResponse struct {
+	Message string                  `json:"message"`
+	Data    StaffAccessResponseData `json:"data"`
+}
+
+type StaffAccessResponseData struct {
+	Staff                StaffResponseDTO    `json:"staff"`
+	Permissions          []string            `json:"permissions" example:"reports.view"`
+	EffectivePermissions []string            `json:"effective_permissions,omitempty" example:"sales.create,reports.view"`
+	History              []AccessAuditLogDTO `json:"history,omitempty"`
+}
+
+// GetAccess godoc
+// @Summary      Get the access of a staff member
+// @Description  Get the role, the directly granted and the effective permissions and the locations of a staff member, and the history of changes to them
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int  true  "Shop ID"
+// @Param        staffId  path      int  true  "Staff ID"
+// @Success      200      {object}  StaffAccessResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or staff id"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop or missing the staff.view permission"
+// @Failure      404      {object}  map[string]string  "Shop or staff not found"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{staffId}/access [get]
+func (h *AccessHandler) GetAccess(c fiber.Ctx) error {
+	shopID, staffID, err := parseStaffParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getStaffAccessUsecase.Execute(c.Context(), shopID, staffID)
+	if err != nil {
+		return accessError(err, "failed to get access")
+	}
+
+	history := make([]AccessAuditLogDTO, len(result.History))
+	for i, log := range result.History {
+		history[i] = AccessAuditLogDTO{
+			ID:         log.ID,
+			ActorID:    log.ActorID,
+			Action:     log.Action,
+			Permission: string(log.Permission),
+			RoleID:     log.RoleID,
+			CreatedAt:  log.CreatedAt,
+		}
+	}
+
+	return c.JSON(StaffAccessResponse{
+		Message: "access retrieved successfully.",
+		Data: StaffAccessResponseData{
+			Staff:                toStaffResponseDTO(result.Staff),
+			Permissions:          permissionNames(result.Permissions),
+			EffectivePermissions: permissionNames(result.EffectivePermissions),
+			History:              history,
+		},
+	})
+}
+
+// GrantAccess godoc
+// @Summary      Grant access to a staff member
+// @Description  Grant a single permission to a staff member, or a role in place of their current one. Only permissions the caller holds can be granted, and the last Owner of a shop cannot be given another role.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int            true  "Shop ID"
+// @Param        staffId  path      int            true  "Staff ID"
+// @Param        request  body      AccessPayload  true  "Access to grant"
+// @Success      200      {object}  StaffAccessResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or staff id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the staff.assign permission, granting a permission the caller does not hold, or changing the role of an Owner or a staff member with permissions the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop, staff, or role not found"
+// @Failure      409      {object}  map[string]string  "Access already granted or last Owner"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{staffId}/access [post]
+func (h *AccessHandler) GrantAccess(c fiber.Ctx) error {
+	shopID, staffID, err := parseStaffParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request AccessPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.grantAccessUsecase.Execute(c.Context(), accessusecases.GrantAccessParam{
+		ActorID:    actorID,
+		ShopID:     shopID,
+		StaffID:    staffID,
+		Permission: accessentities.Permission(request.Permission),
+		RoleID:     request.RoleID,
+	})
+	if err != nil {
+		return accessError(err, "failed to grant access")
+	}
+
+	return c.JSON(StaffAccessResponse{
+		Message: "access granted successfully.",
+		Data: StaffAccessResponseData{
+			Staff:       toStaffResponseDTO(result.Staff),
+			Permissions: permissionNames(result.Permissions),
+		},
+	})
+}
+
+// RevokeAccess godoc
+// @Summary      Revoke access from a staff member
+// @Description  Revoke a permission granted to a staff member directly, or their role. Revoking the role removes the staff member from the shop and also requires the staff.remove permission. The last Owner of a shop cannot be revoked.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  int            true  "Shop ID"
+// @Param        staffId  path  int            true  "Staff ID"
+// @Param        request  body  AccessPayload  true  "Access to revoke"
+// @Success      204      "No Content"
+// @Failure      400      {object}  map[string]string  "Invalid shop or staff id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the staff.assign or staff.remove permission, or revoking access of an Owner or a staff member with permissions the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop or staff not found, or access not granted"
+// @Failure      409      {object}  map[string]string  "Last Owner"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{staffId}/access [delete]
+func (h *AccessHandler) RevokeAccess(c fiber.Ctx) error {
+	shopID, staffID, err := parseStaffParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request AccessPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	err = h.revokeAccessUsecase.Execute(c.Context(), accessusecases.RevokeAccessParam{
+		ActorID:    actorID,
+		ShopID:     shopID,
+		StaffID:    staffID,
+		Permission: accessentities.Permission(request.Permission),
+		RoleID:     request.RoleID,
+	})
+	if err != nil {
+		return accessError(err, "failed to revoke access")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// SetLocations godoc
+// @Summary      Set the locations of a staff member
+// @Description  Limit a staff member to some locations of the shop, or let them work at every location with an empty list. Staff limited to some locations only see and move stock there. Callers who are limited themselves can only assign their own locations.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                    true  "Shop ID"
+// @Param        staffId  path      int                    true  "Staff ID"
+// @Param        request  body      StaffLocationsPayload  true  "Locations"
+// @Success      200      {object}  StaffLocationsResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or staff id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the staff.assign permission, or assigning a location the caller does not work at"
+// @Failure      404      {object}  map[string]string  "Shop, staff, or location not found"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{staffId}/locations [put]
+func (h *AccessHandler) SetLocations(c fiber.Ctx) error {
+	shopID, staffID, err := parseStaffParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request StaffLocationsPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.setLocationsUsecase.Execute(c.Context(), accessusecases.SetStaffLocationsParam{
+		ActorID:     actorID,
+		ShopID:      shopID,
+		StaffID:     staffID,
+		LocationIDs: request.LocationIDs,
+	})
+	if err != nil {
+		return accessError(err, "failed to set locations")
+	}
+
+	locationIDs := result.Staff.LocationIDs
+	if locationIDs == nil {
+		locationIDs = []uint64{}
+	}
+
+	return c.JSON(StaffLocationsResponse{
+		Message: "locations updated successfully.",
+		Data: StaffLocationsResponseData{
+			LocationIDs: locationIDs,
+		},
+	})
+}
+
+func parseStaffParams(c fiber.Ctx) (uint64, uint64, error) {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	staffID, err := strconv.ParseUint(c.Params("staffId"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid staff id")
+	}
+
+	return shopID, staffID, nil
+}
+
+func accessError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case isForbiddenError(err):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case err.Error() == "staff not found" || err.Error() == "role not found" || err.Error() == "location not found" || strings.Contains(err.Error(), "not granted"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case isConflictError(err) || strings.Contains(err.Error(), "already granted") || strings.Contains(err.Error(), "last Owner") || strings.Contains(err.Error(), "in use") || strings.Contains(err.Error(), "suspended"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toStaffResponseDTO(staff *accessentities.Staff) StaffResponseDTO {
+	response := StaffResponseDTO{ID: staff.ID, Status: staff.Status, LocationIDs: staff.LocationIDs}
+	if staff.User != nil {
+		response.User = UserDTO{
+			ID:       staff.User.ID,
+			FullName: staff.User.FullName,
+			Phone:    staff.User.Phone,
+			Email:    staff.User.Email,
+		}
+	}
+	if staff.Role != nil {
+		response.Role = RoleDTO{
+			ID:          staff.Role.ID,
+			Name:        staff.Role.Name,
+			Description: staff.Role.Description,
+		}
+	}
+	return response
+}
+
+func permissionNames(permissions []accessentities.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type AccountLockHandler struct {
+	unlockAccountUsecase *usecases.UnlockAccountUsecase
+}
+
+func NewAccountLockHandler(unlockAccountUsecase *usecases.UnlockAccountUsecase) *AccountLockHandler {
+	return &AccountLockHandler{
+		unlockAccountUsecase: unlockAccountUsecase,
+	}
+}
+
+type AccountLockResponse struct {
+	Message string `json:"message"`
+}
+
+// UnlockStaff godoc
+// @Summary      Unlock a staff member's account
+// @Description  Lift the lockout caused by repeated failed logins of a staff member. Requires the staff.unlock permission in the shop.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Shop ID"
+// @Param        userId  path      int  true  "User ID of the staff member"
+// @Success      200     {object}  AccountLockResponse
+// @Failure      400     {object}  map[string]string  "Invalid shop or user id"
+// @Failure      401     {object}  map[string]string  "Authentication required"
+// @Failure      403     {object}  map[string]string  "Not a member of the shop or missing the staff.unlock permission"
+// @Failure      404     {object}  map[string]string  "Staff not found"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{userId}/unlock [post]
+func (h *AccountLockHandler) UnlockStaff(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	userID, err := strconv.ParseUint(c.Params("userId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Context()
+	err = h.unlockAccountUsecase.Execute(ctx, usecases.UnlockAccountParam{
+		ActorID: actorID,
+		ShopID:  shopID,
+		UserID:  userID,
+	})
+	if err != nil {
+		if isForbiddenError(err) {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
+		if err.Error() == "staff not found" || err.Error() == "user not found" {
+			return fiber.NewError(fiber.StatusNotFound, "staff not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to unlock account")
+	}
+
+	return c.JSON(AccountLockResponse{
+		Message: "account unlocked successfully.",
+	})
+}
+
+func isForbiddenError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "forbidden")
+}
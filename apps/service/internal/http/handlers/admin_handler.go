@@ -0,0 +1,511 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	adminusecases "github.com/reno1r/weiss/apps/service/internal/app/admin/usecases"
+	shopusecases "github.com/reno1r/weiss/apps/service/internal/app/shop/usecases"
+	userentities "github.com/reno1r/weiss/apps/service/internal/app/user/entities"
+)
+
+// AdminHandler serves the platform administration endpoints. Every route
+// must be guarded by the admin middleware.
+type AdminHandler struct {
+	listAllShopsUsecase         *shopusecases.ListAllShopsUsecase
+	searchUsersUsecase          *adminusecases.SearchUsersUsecase
+	updateUserSuspensionUsecase *adminusecases.UpdateUserSuspensionUsecase
+	updateShopSuspensionUsecase *adminusecases.UpdateShopSuspensionUsecase
+	impersonateUserUsecase      *adminusecases.ImpersonateUserUsecase
+	listAuditLogsUsecase        *adminusecases.ListAuditLogsUsecase
+}
+
+func NewAdminHandler(
+	listAllShopsUsecase *shopusecases.ListAllShopsUsecase,
+	searchUsersUsecase *adminusecases.SearchUsersUsecase,
+	updateUserSuspensionUsecase *adminusecases.UpdateUserSuspensionUsecase,
+	updateShopSuspensionUsecase *adminusecases.UpdateShopSuspensionUsecase,
+	impersonateUserUsecase *adminusecases.ImpersonateUserUsecase,
+	listAuditLogsUsecase *adminusecases.ListAuditLogsUsecase,
+) *AdminHandler {
+	return &AdminHandler{
+		listAllShopsUsecase:         listAllShopsUsecase,
+		searchUsersUsecase:          searchUsersUsecase,
+		updateUserSuspensionUsecase: updateUserSuspensionUsecase,
+		updateShopSuspensionUsecase: updateShopSuspensionUsecase,
+		impersonateUserUsecase:      impersonateUserUsecase,
+		listAuditLogsUsecase:        listAuditLogsUsecase,
+	}
+}
+
+// AdminActionPayload carries the reason an administrator gives for an
+// action. It is kept in the admin audit log.
+type AdminActionPayload struct {
+	Reason string `json:"reason" example:"Support ticket #4521" binding:"required"` // Why the action is taken
+}
+
+type AdminUserDTO struct {
+	ID          uint64     `json:"id" example:"1"`
+	FullName    string     `json:"full_name" example:"John Doe"`
+	Email       string     `json:"email" example:"john@example.com"`
+	Phone       string     `json:"phone" example:"1234567890"`
+	IsAdmin     bool       `json:"is_admin" example:"false"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type AdminUserListResponse struct {
+	Message string                    `json:"message"`
+	Data    AdminUserListResponseData `json:"data"`
+}
+
+type AdminUserListResponseData struct {
+	Users      []AdminUserDTO `json:"users"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"eyJzIjoiaWQiLCJ2IjoiIiwiaWQiOjF9"`
+}
+
+type AdminUserResponse struct {
+	Message string                `json:"message"`
+	Data    AdminUserResponseData `json:"data"`
+}
+
+type AdminUserResponseData struct {
+	User AdminUserDTO `json:"user"`
+}
+
+type ImpersonationResponse struct {
+	Message string                    `json:"message"`
+	Data    ImpersonationResponseData `json:"data"`
+}
+
+type ImpersonationResponseData struct {
+	AccessToken string       `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType   string       `json:"token_type" example:"Bearer"`
+	ExpiresAt   time.Time    `json:"expires_at" example:"2024-01-01T00:15:00Z"`
+	User        AdminUserDTO `json:"user"`
+}
+
+type AdminAuditLogDTO struct {
+	ID         uint64    `json:"id" example:"1"`
+	AdminID    uint64    `json:"admin_id" example:"1"`
+	Action     string    `json:"action" example:"impersonate"`
+	TargetType string    `json:"target_type" example:"user"`
+	TargetID   uint64    `json:"target_id" example:"2"`
+	Reason     string    `json:"reason" example:"Support ticket #4521"`
+	TokenID    string    `json:"token_id,omitempty" example:"6f1c2d3e-4b5a-6789-abcd-ef0123456789"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AdminAuditLogListResponse struct {
+	Message string                        `json:"message"`
+	Data    AdminAuditLogListResponseData `json:"data"`
+}
+
+type AdminAuditLogListResponseData struct {
+	Logs       []AdminAuditLogDTO `json:"logs"`
+	NextCursor string             `json:"next_cursor,omitempty" example:"eyJzIjoiaWQiLCJ2IjoiIiwiaWQiOjF9"`
+}
+
+// ListShops godoc
+// @Summary      List all shops
+// @Description  List every shop on the platform, one page at a time. Only platform administrators may call it. Pass next_cursor from a response as cursor to get the next page.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q       query     string  false  "Case-insensitive search on the shop name"
+// @Param        sort    query     string  false  "Sort order"  Enums(name, -name, created_at, -created_at)  default(name)
+// @Param        cursor  query     string  false  "Cursor returned by the previous page"
+// @Param        limit   query     int     false  "Page size, at most 100"  default(20)
+// @Success      200     {object}  ShopListResponse
+// @Failure      400     {object}  map[string]string  "Invalid limit"
+// @Failure      403     {object}  map[string]string  "Not a platform administrator"
+// @Failure      422     {object}  map[string]string  "Validation failed"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /admin/shops [get]
+func (h *AdminHandler) ListShops(c fiber.Ctx) error {
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.listAllShopsUsecase.Execute(c.Context(), shopusecases.ListAllShopsParam{
+		Search: c.Query("q"),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to list shops")
+	}
+
+	shops := make([]ShopResponseDTO, len(result.Shops))
+	for i, shop := range result.Shops {
+		shops[i] = toShopResponseDTO(shop)
+	}
+
+	return c.JSON(ShopListResponse{
+		Message: "shops retrieved successfully.",
+		Data: ShopListResponseData{
+			Shops:      shops,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// SearchUsers godoc
+// @Summary      Search users
+// @Description  Search every user of the platform by name, email or phone, one page at a time. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q       query     string  false  "Case-insensitive search on the full name, email or phone"
+// @Param        cursor  query     string  false  "Cursor returned by the previous page"
+// @Param        limit   query     int     false  "Page size, at most 100"  default(20)
+// @Success      200     {object}  AdminUserListResponse
+// @Failure      400     {object}  map[string]string  "Invalid limit"
+// @Failure      403     {object}  map[string]string  "Not a platform administrator"
+// @Failure      422     {object}  map[string]string  "Validation failed"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /admin/users [get]
+func (h *AdminHandler) SearchUsers(c fiber.Ctx) error {
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.searchUsersUsecase.Execute(c.Context(), adminusecases.SearchUsersParam{
+		Search: c.Query("q"),
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		return adminError(err, "failed to search users")
+	}
+
+	users := make([]AdminUserDTO, len(result.Users))
+	for i, user := range result.Users {
+		users[i] = toAdminUserDTO(user)
+	}
+
+	return c.JSON(AdminUserListResponse{
+		Message: "users retrieved successfully.",
+		Data: AdminUserListResponseData{
+			Users:      users,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// SuspendUser godoc
+// @Summary      Suspend a user
+// @Description  Suspend a user and sign them out of every device. A suspended user cannot sign in until restored. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "User ID"
+// @Param        request  body      AdminActionPayload  true  "Reason"
+// @Success      200      {object}  AdminUserResponse
+// @Failure      400      {object}  map[string]string  "Invalid user id or request body"
+// @Failure      403      {object}  map[string]string  "Not a platform administrator, or suspending yourself"
+// @Failure      404      {object}  map[string]string  "User not found"
+// @Failure      409      {object}  map[string]string  "User already suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(c fiber.Ctx) error {
+	return h.updateUserSuspension(c, true)
+}
+
+// RestoreUser godoc
+// @Summary      Restore a user
+// @Description  Lift the suspension of a user. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "User ID"
+// @Param        request  body      AdminActionPayload  true  "Reason"
+// @Success      200      {object}  AdminUserResponse
+// @Failure      400      {object}  map[string]string  "Invalid user id or request body"
+// @Failure      403      {object}  map[string]string  "Not a platform administrator"
+// @Failure      404      {object}  map[string]string  "User not found"
+// @Failure      409      {object}  map[string]string  "User not suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /admin/users/{id}/restore [post]
+func (h *AdminHandler) RestoreUser(c fiber.Ctx) error {
+	return h.updateUserSuspension(c, false)
+}
+
+func (h *AdminHandler) updateUserSuspension(c fiber.Ctx, suspend bool) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	var request AdminActionPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.updateUserSuspensionUsecase.Execute(c.Context(), adminusecases.UpdateUserSuspensionParam{
+		AdminID: adminID,
+		UserID:  userID,
+		Suspend: suspend,
+		Reason:  request.Reason,
+	})
+	if err != nil {
+		return adminError(err, "failed to update user")
+	}
+
+	message := "user restored successfully."
+	if suspend {
+		message = "user suspended successfully."
+	}
+
+	return c.JSON(AdminUserResponse{
+		Message: message,
+		Data: AdminUserResponseData{
+			User: toAdminUserDTO(*result.User),
+		},
+	})
+}
+
+// ImpersonateUser godoc
+// @Summary      Impersonate a user
+// @Description  Issue a short-lived access token to act as a user, for support. The token carries the administrator's ID, cannot be refreshed, and cannot change the user's credentials. Every impersonation is recorded in the admin audit log. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "User ID"
+// @Param        request  body      AdminActionPayload  true  "Reason"
+// @Success      200      {object}  ImpersonationResponse
+// @Failure      400      {object}  map[string]string  "Invalid user id or request body"
+// @Failure      403      {object}  map[string]string  "Not a platform administrator, or impersonating yourself or another administrator"
+// @Failure      404      {object}  map[string]string  "User not found"
+// @Failure      409      {object}  map[string]string  "User suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /admin/users/{id}/impersonate [post]
+func (h *AdminHandler) ImpersonateUser(c fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	var request AdminActionPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.impersonateUserUsecase.Execute(c.Context(), adminusecases.ImpersonateUserParam{
+		AdminID: adminID,
+		UserID:  userID,
+		Reason:  request.Reason,
+	})
+	if err != nil {
+		return adminError(err, "failed to impersonate user")
+	}
+
+	return c.JSON(ImpersonationResponse{
+		Message: "impersonation started.",
+		Data: ImpersonationResponseData{
+			AccessToken: result.AccessToken,
+			TokenType:   "Bearer",
+			ExpiresAt:   result.ExpiresAt,
+			User:        toAdminUserDTO(*result.User),
+		},
+	})
+}
+
+// SuspendShop godoc
+// @Summary      Suspend a shop
+// @Description  Suspend a shop, closing it to all of its staff until restored. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "Shop ID"
+// @Param        request  body      AdminActionPayload  true  "Reason"
+// @Success      200      {object}  ShopResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a platform administrator"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Shop already suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /admin/shops/{id}/suspend [post]
+func (h *AdminHandler) SuspendShop(c fiber.Ctx) error {
+	return h.updateShopSuspension(c, true)
+}
+
+// RestoreShop godoc
+// @Summary      Restore a shop
+// @Description  Lift the suspension of a shop. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "Shop ID"
+// @Param        request  body      AdminActionPayload  true  "Reason"
+// @Success      200      {object}  ShopResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a platform administrator"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Shop not suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /admin/shops/{id}/restore [post]
+func (h *AdminHandler) RestoreShop(c fiber.Ctx) error {
+	return h.updateShopSuspension(c, false)
+}
+
+func (h *AdminHandler) updateShopSuspension(c fiber.Ctx, suspend bool) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request AdminActionPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.updateShopSuspensionUsecase.Execute(c.Context(), adminusecases.UpdateShopSuspensionParam{
+		AdminID: adminID,
+		ShopID:  shopID,
+		Suspend: suspend,
+		Reason:  request.Reason,
+	})
+	if err != nil {
+		return adminError(err, "failed to update shop")
+	}
+
+	message := "shop restored successfully."
+	if suspend {
+		message = "shop suspended successfully."
+	}
+
+	shop := toShopResponseDTO(*result.Shop)
+	return c.JSON(ShopResponse{
+		Message: message,
+		Data: ShopResponseData{
+			Shop: &shop,
+		},
+	})
+}
+
+// ListAuditLogs godoc
+// @Summary      List the admin audit log
+// @Description  List the actions platform administrators took, newest first, one page at a time. Only platform administrators may call it.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        target_type  query     string  false  "Only actions on this kind of target"  Enums(user, shop)
+// @Param        target_id    query     int     false  "Only actions on this target"
+// @Param        cursor       query     string  false  "Cursor returned by the previous page"
+// @Param        limit        query     int     false  "Page size, at most 100"  default(20)
+// @Success      200          {object}  AdminAuditLogListResponse
+// @Failure      400          {object}  map[string]string  "Invalid target_id or limit"
+// @Failure      403          {object}  map[string]string  "Not a platform administrator"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /admin/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(c fiber.Ctx) error {
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+
+	var targetID uint64
+	if value := c.Query("target_id"); value != "" {
+		targetID, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid target_id")
+		}
+	}
+
+	result, err := h.listAuditLogsUsecase.Execute(c.Context(), adminusecases.ListAuditLogsParam{
+		TargetType: c.Query("target_type"),
+		TargetID:   targetID,
+		Cursor:     c.Query("cursor"),
+		Limit:      limit,
+	})
+	if err != nil {
+		return adminError(err, "failed to list audit logs")
+	}
+
+	logs := make([]AdminAuditLogDTO, len(result.Logs))
+	for i, log := range result.Logs {
+		logs[i] = AdminAuditLogDTO{
+			ID:         log.ID,
+			AdminID:    log.AdminID,
+			Action:     log.Action,
+			TargetType: log.TargetType,
+			TargetID:   log.TargetID,
+			Reason:     log.Reason,
+			TokenID:    log.TokenID,
+			CreatedAt:  log.CreatedAt,
+		}
+	}
+
+	return c.JSON(AdminAuditLogListResponse{
+		Message: "audit logs retrieved successfully.",
+		Data: AdminAuditLogListResponseData{
+			Logs:       logs,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+func adminError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case isForbiddenError(err):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case strings.HasSuffix(err.Error(), "not found"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "suspended"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toAdminUserDTO(user userentities.User) AdminUserDTO {
+	return AdminUserDTO{
+		ID:          user.ID,
+		FullName:    user.FullName,
+		Email:       user.Email,
+		Phone:       user.Phone,
+		IsAdmin:     user.IsAdmin,
+		SuspendedAt: user.SuspendedAt,
+		CreatedAt:   user.CreatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+)
+
+type APIKeyHandler struct {
+	createAPIKeyUsecase *accessusecases.CreateAPIKeyUsecase
+	listAPIKeysUsecase  *accessusecases.ListAPIKeysUsecase
+	revokeAPIKeyUsecase *accessusecases.RevokeAPIKeyUsecase
+}
+
+func NewAPIKeyHandler(createAPIKeyUsecase *accessusecases.CreateAPIKeyUsecase, listAPIKeysUsecase *accessusecases.ListAPIKeysUsecase, revokeAPIKeyUsecase *accessusecases.RevokeAPIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		createAPIKeyUsecase: createAPIKeyUsecase,
+		listAPIKeysUsecase:  listAPIKeysUsecase,
+		revokeAPIKeyUsecase: revokeAPIKeyUsecase,
+	}
+}
+
+type CreateAPIKeyPayload struct {
+	Name        string     `json:"name" example:"Accounting export" binding:"required"`   // Name to recognize the key by
+	Permissions []string   `json:"permissions" example:"reports.view" binding:"required"` // Permissions the key may use
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2025-01-01T00:00:00Z"`   // When the key stops working; never when left out
+}
+
+type APIKeyDTO struct {
+	ID          uint64     `json:"id" example:"1"`
+	Name        string     `json:"name" example:"Accounting export"`
+	Prefix      string     `json:"prefix" example:"wsk_3f9a1c0b7d2e"`
+	UserID      uint64     `json:"user_id" example:"1"`
+	Permissions []string   `json:"permissions" example:"reports.view"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type APIKeyListResponse struct {
+	Message string                 `json:"message"`
+	Data    APIKeyListResponseData `json:"data"`
+}
+
+type APIKeyListResponseData struct {
+	APIKeys []APIKeyDTO `json:"api_keys"`
+}
+
+type CreatedAPIKeyResponse struct {
+	Message string                    `json:"message"`
+	Data    CreatedAPIKeyResponseData `json:"data"`
+}
+
+// CreatedAPIKeyResponseData carries the full key. It is only ever returned
+// here; listings show the prefix alone.
+type CreatedAPIKeyResponseData struct {
+	APIKey APIKeyDTO `json:"api_key"`
+	Key    string    `json:"key" example:"wsk_3f9a1c0b7d2e_Jq0n3..."`
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys of a shop
+// @Description  List every API key of a shop, including revoked and expired keys, with when each was last used. The keys themselves are never shown again after creation.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Shop ID"
+// @Success      200  {object}  APIKeyListResponse
+// @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop, missing the api_key.manage permission, calling with an API key, or impersonating a user"
+// @Failure      404  {object}  map[string]string  "Shop not found"
+// @Router       /shops/{id}/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	result := h.listAPIKeysUsecase.Execute(c.Context(), shopID)
+
+	apiKeys := make([]APIKeyDTO, len(result.APIKeys))
+	for i, apiKey := range result.APIKeys {
+		apiKeys[i] = toAPIKeyDTO(apiKey)
+	}
+
+	return c.JSON(APIKeyListResponse{
+		Message: "api keys retrieved successfully.",
+		Data: APIKeyListResponseData{
+			APIKeys: apiKeys,
+		},
+	})
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Create an API key for integrations with the shop. The key acts as the caller, limited to the selected permissions, which the caller must hold. It is sent as a bearer token and only accepted on the routes of this shop. The key is returned once and cannot be retrieved again.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                  true  "Shop ID"
+// @Param        request  body      CreateAPIKeyPayload  true  "API key data"
+// @Success      201      {object}  CreatedAPIKeyResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the api_key.manage permission, calling with an API key or while impersonating a user, or selecting a permission the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request CreateAPIKeyPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.createAPIKeyUsecase.Execute(c.Context(), accessusecases.CreateAPIKeyParam{
+		ActorID:     actorID,
+		ShopID:      shopID,
+		Name:        request.Name,
+		Permissions: toPermissions(request.Permissions),
+		ExpiresAt:   request.ExpiresAt,
+	})
+	if err != nil {
+		return apiKeyError(err, "failed to create api key")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CreatedAPIKeyResponse{
+		Message: "api key created successfully. Store the key now, it will not be shown again.",
+		Data: CreatedAPIKeyResponseData{
+			APIKey: toAPIKeyDTO(*result.APIKey),
+			Key:    result.Key,
+		},
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Revoke an API key of a shop. Requests made with it are rejected from then on.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path  int  true  "Shop ID"
+// @Param        apiKeyId  path  int  true  "API key ID"
+// @Success      204       "No Content"
+// @Failure      400       {object}  map[string]string  "Invalid shop or API key id"
+// @Failure      403       {object}  map[string]string  "Not a member of the shop, missing the api_key.manage permission, calling with an API key, or impersonating a user"
+// @Failure      404       {object}  map[string]string  "Shop or API key not found"
+// @Failure      409       {object}  map[string]string  "API key already revoked"
+// @Failure      500       {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/api-keys/{apiKeyId} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	apiKeyID, err := strconv.ParseUint(c.Params("apiKeyId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid api key id")
+	}
+
+	if err := h.revokeAPIKeyUsecase.Execute(c.Context(), shopID, apiKeyID); err != nil {
+		return apiKeyError(err, "failed to revoke api key")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func apiKeyError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case isForbiddenError(err):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case strings.HasSuffix(err.Error(), "not found"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "already revoked"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toAPIKeyDTO(apiKey accessentities.APIKey) APIKeyDTO {
+	return APIKeyDTO{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		UserID:      apiKey.UserID,
+		Permissions: permissionNames(apiKey.PermissionNames()),
+		ExpiresAt:   apiKey.ExpiresAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+		RevokedAt:   apiKey.RevokedAt,
+		CreatedAt:   apiKey.CreatedAt,
+	}
+}
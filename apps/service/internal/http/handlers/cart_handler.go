@@ -0,0 +1,591 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	salesentities "github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	salesusecases "github.com/reno1r/weiss/apps/service/internal/app/sales/usecases"
+)
+
+type CartHandler struct {
+	createCartUsecase     *salesusecases.CreateCartUsecase
+	getCartUsecase        *salesusecases.GetCartUsecase
+	updateCartUsecase     *salesusecases.UpdateCartUsecase
+	deleteCartUsecase     *salesusecases.DeleteCartUsecase
+	addCartLineUsecase    *salesusecases.AddCartLineUsecase
+	updateCartLineUsecase *salesusecases.UpdateCartLineUsecase
+	removeCartLineUsecase *salesusecases.RemoveCartLineUsecase
+	checkoutCartUsecase   *salesusecases.CheckoutCartUsecase
+}
+
+func NewCartHandler(createCartUsecase *salesusecases.CreateCartUsecase, getCartUsecase *salesusecases.GetCartUsecase, updateCartUsecase *salesusecases.UpdateCartUsecase, deleteCartUsecase *salesusecases.DeleteCartUsecase, addCartLineUsecase *salesusecases.AddCartLineUsecase, updateCartLineUsecase *salesusecases.UpdateCartLineUsecase, removeCartLineUsecase *salesusecases.RemoveCartLineUsecase, checkoutCartUsecase *salesusecases.CheckoutCartUsecase) *CartHandler {
+	return &CartHandler{
+		createCartUsecase:     createCartUsecase,
+		getCartUsecase:        getCartUsecase,
+		updateCartUsecase:     updateCartUsecase,
+		deleteCartUsecase:     deleteCartUsecase,
+		addCartLineUsecase:    addCartLineUsecase,
+		updateCartLineUsecase: updateCartLineUsecase,
+		removeCartLineUsecase: removeCartLineUsecase,
+		checkoutCartUsecase:   checkoutCartUsecase,
+	}
+}
+
+type CreateCartPayload struct {
+	LocationID uint64 `json:"location_id" example:"1" binding:"required"` // Outlet the sale is rung up at
+}
+
+type UpdateCartPayload struct {
+	DiscountAmount int64 `json:"discount_amount" example:"500"` // Taken off the whole cart, after the discounts of its lines
+	TaxRate        int64 `json:"tax_rate" example:"1000"`       // In basis points: 1000 is 10%
+}
+
+type AddCartLinePayload struct {
+	SKU            string `json:"sku" example:"TS-M-BLUE"`         // SKU of the variant; leave out when scanning a barcode
+	Barcode        string `json:"barcode" example:"4006381333931"` // Barcode of the variant; leave out when giving a SKU
+	Quantity       int64  `json:"quantity" example:"1" binding:"required"`
+	DiscountAmount int64  `json:"discount_amount" example:"0"` // Taken off the line as a whole
+}
+
+type UpdateCartLinePayload struct {
+	Quantity       int64 `json:"quantity" example:"2" binding:"required"`
+	DiscountAmount int64 `json:"discount_amount" example:"0"`
+}
+
+type PaymentPayload struct {
+	Method    string `json:"method" example:"cash" enums:"cash,card,transfer" binding:"required"`
+	Amount    int64  `json:"amount" example:"2000" binding:"required"`
+	Reference string `json:"reference" example:"AUTH-123456"` // Authorization code or bank reference of card and transfer payments
+}
+
+type CheckoutPayload struct {
+	Payments []PaymentPayload `json:"payments"` // Together at least the total; only cash can go over it
+}
+
+type CartLineDTO struct {
+	ID             uint64 `json:"id" example:"1"`
+	VariantID      uint64 `json:"variant_id" example:"1"`
+	SKU            string `json:"sku" example:"TS-M-BLUE"`
+	Name           string `json:"name" example:"T-Shirt (M / Blue)"`
+	UnitPrice      int64  `json:"unit_price" example:"1700"`
+	Quantity       int64  `json:"quantity" example:"1"`
+	DiscountAmount int64  `json:"discount_amount" example:"0"`
+	Total          int64  `json:"total" example:"1700"`
+}
+
+type CartDTO struct {
+	ID             uint64        `json:"id" example:"1"`
+	LocationID     uint64        `json:"location_id" example:"1"`
+	UserID         uint64        `json:"user_id" example:"1"`
+	Status         string        `json:"status" example:"open"`
+	Lines          []CartLineDTO `json:"lines"`
+	DiscountAmount int64         `json:"discount_amount" example:"0"`
+	TaxRate        int64         `json:"tax_rate" example:"1000"`
+	Subtotal       int64         `json:"subtotal" example:"1700"`
+	DiscountTotal  int64         `json:"discount_total" example:"0"`
+	TaxTotal       int64         `json:"tax_total" example:"170"`
+	Total          int64         `json:"total" example:"1870"`
+	CreatedAt      time.Time     `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt      time.Time     `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type CartResponse struct {
+	Message string           `json:"message"`
+	Data    CartResponseData `json:"data"`
+}
+
+type CartResponseData struct {
+	Cart CartDTO `json:"cart"`
+}
+
+// CreateCart godoc
+// @Summary      Open a cart
+// @Description  Open an empty cart at an outlet of a shop to ring up a sale
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                true  "Shop ID"
+// @Param        request  body      CreateCartPayload  true  "Cart data"
+// @Success      201      {object}  CartResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the location"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts [post]
+func (h *CartHandler) CreateCart(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request CreateCartPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.createCartUsecase.Execute(c.Context(), salesusecases.CreateCartParam{
+		ShopID:             shopID,
+		UserID:             userID,
+		LocationID:         request.LocationID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to create cart")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CartResponse{
+		Message: "cart created successfully.",
+		Data: CartResponseData{
+			Cart: toCartDTO(*result.Cart),
+		},
+	})
+}
+
+// GetCart godoc
+// @Summary      Get a cart
+// @Description  Get a cart of a shop with its lines and totals
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Shop ID"
+// @Param        cartId  path      int  true  "Cart ID"
+// @Success      200     {object}  CartResponse
+// @Failure      400     {object}  map[string]string  "Invalid shop or cart id"
+// @Failure      403     {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404     {object}  map[string]string  "Shop or cart not found"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId} [get]
+func (h *CartHandler) GetCart(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getCartUsecase.Execute(c.Context(), salesusecases.GetCartParam{
+		ShopID:             shopID,
+		CartID:             cartID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to get cart")
+	}
+
+	return c.JSON(CartResponse{
+		Message: "cart retrieved successfully.",
+		Data: CartResponseData{
+			Cart: toCartDTO(*result.Cart),
+		},
+	})
+}
+
+// UpdateCart godoc
+// @Summary      Apply a discount and tax to a cart
+// @Description  Set the discount taken off an open cart as a whole and the rate its tax is charged at
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                true  "Shop ID"
+// @Param        cartId   path      int                true  "Cart ID"
+// @Param        request  body      UpdateCartPayload  true  "Discount and tax"
+// @Success      200      {object}  CartResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or cart id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404      {object}  map[string]string  "Shop or cart not found"
+// @Failure      409      {object}  map[string]string  "Cart already checked out"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId} [put]
+func (h *CartHandler) UpdateCart(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request UpdateCartPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.updateCartUsecase.Execute(c.Context(), salesusecases.UpdateCartParam{
+		ShopID:             shopID,
+		CartID:             cartID,
+		DiscountAmount:     request.DiscountAmount,
+		TaxRate:            request.TaxRate,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to update cart")
+	}
+
+	return c.JSON(CartResponse{
+		Message: "cart updated successfully.",
+		Data: CartResponseData{
+			Cart: toCartDTO(*result.Cart),
+		},
+	})
+}
+
+// DeleteCart godoc
+// @Summary      Abandon a cart
+// @Description  Delete an open cart of a shop. Checked out carts are kept with their sale.
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  int  true  "Shop ID"
+// @Param        cartId  path  int  true  "Cart ID"
+// @Success      204     "No Content"
+// @Failure      400     {object}  map[string]string  "Invalid shop or cart id"
+// @Failure      403     {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404     {object}  map[string]string  "Shop or cart not found"
+// @Failure      409     {object}  map[string]string  "Cart already checked out"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId} [delete]
+func (h *CartHandler) DeleteCart(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.deleteCartUsecase.Execute(c.Context(), salesusecases.DeleteCartParam{
+		ShopID:             shopID,
+		CartID:             cartID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to delete cart")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// AddCartLine godoc
+// @Summary      Add a line to a cart
+// @Description  Add a variant to an open cart by SKU or barcode, at its current price. Adding a variant the cart already has adds to its line.
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "Shop ID"
+// @Param        cartId   path      int                 true  "Cart ID"
+// @Param        request  body      AddCartLinePayload  true  "Line data"
+// @Success      201      {object}  CartResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or cart id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404      {object}  map[string]string  "Shop, cart or variant not found"
+// @Failure      409      {object}  map[string]string  "Cart already checked out"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId}/lines [post]
+func (h *CartHandler) AddCartLine(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request AddCartLinePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.addCartLineUsecase.Execute(c.Context(), salesusecases.AddCartLineParam{
+		ShopID:             shopID,
+		CartID:             cartID,
+		SKU:                request.SKU,
+		Barcode:            request.Barcode,
+		Quantity:           request.Quantity,
+		DiscountAmount:     request.DiscountAmount,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to add cart line")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CartResponse{
+		Message: "cart line added successfully.",
+		Data: CartResponseData{
+			Cart: toCartDTO(*result.Cart),
+		},
+	})
+}
+
+// UpdateCartLine godoc
+// @Summary      Update a line of a cart
+// @Description  Set the quantity and discount of a line of an open cart. Its price stays the one it was added at.
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                    true  "Shop ID"
+// @Param        cartId   path      int                    true  "Cart ID"
+// @Param        lineId   path      int                    true  "Line ID"
+// @Param        request  body      UpdateCartLinePayload  true  "Line data"
+// @Success      200      {object}  CartResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop, cart or line id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404      {object}  map[string]string  "Shop, cart or line not found"
+// @Failure      409      {object}  map[string]string  "Cart already checked out"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId}/lines/{lineId} [put]
+func (h *CartHandler) UpdateCartLine(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	lineID, err := strconv.ParseUint(c.Params("lineId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid line id")
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request UpdateCartLinePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.updateCartLineUsecase.Execute(c.Context(), salesusecases.UpdateCartLineParam{
+		ShopID:             shopID,
+		CartID:             cartID,
+		LineID:             lineID,
+		Quantity:           request.Quantity,
+		DiscountAmount:     request.DiscountAmount,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to update cart line")
+	}
+
+	return c.JSON(CartResponse{
+		Message: "cart line updated successfully.",
+		Data: CartResponseData{
+			Cart: toCartDTO(*result.Cart),
+		},
+	})
+}
+
+// RemoveCartLine godoc
+// @Summary      Remove a line from a cart
+// @Description  Take a line off an open cart
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Shop ID"
+// @Param        cartId  path      int  true  "Cart ID"
+// @Param        lineId  path      int  true  "Line ID"
+// @Success      200     {object}  CartResponse
+// @Failure      400     {object}  map[string]string  "Invalid shop, cart or line id"
+// @Failure      403     {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404     {object}  map[string]string  "Shop, cart or line not found"
+// @Failure      409     {object}  map[string]string  "Cart already checked out"
+// @Failure      422     {object}  map[string]string  "Cart discount would exceed what is left"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId}/lines/{lineId} [delete]
+func (h *CartHandler) RemoveCartLine(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	lineID, err := strconv.ParseUint(c.Params("lineId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid line id")
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.removeCartLineUsecase.Execute(c.Context(), salesusecases.RemoveCartLineParam{
+		ShopID:             shopID,
+		CartID:             cartID,
+		LineID:             lineID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to remove cart line")
+	}
+
+	return c.JSON(CartResponse{
+		Message: "cart line removed successfully.",
+		Data: CartResponseData{
+			Cart: toCartDTO(*result.Cart),
+		},
+	})
+}
+
+// CheckoutCart godoc
+// @Summary      Check out a cart
+// @Description  Take the payments for an open cart and turn it into a sale with the next receipt number of its location. The sold stock is taken out of the location in the same transaction; if a line is short and the shop does not allow negative stock, nothing is sold. Send an X-Idempotency-Key to retry safely: for 24 hours a retry of the same checkout by the same caller with the same key gets the first successful response back instead of selling again.
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id                 path      int              true   "Shop ID"
+// @Param        cartId             path      int              true   "Cart ID"
+// @Param        X-Idempotency-Key  header    string           false  "UUID identifying this checkout attempt"
+// @Param        request            body      CheckoutPayload  true   "Payments"
+// @Success      201                {object}  SaleResponse
+// @Failure      400                {object}  map[string]string  "Invalid shop or cart id, idempotency key or request body"
+// @Failure      403                {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the cart's location"
+// @Failure      404                {object}  map[string]string  "Shop or cart not found"
+// @Failure      409                {object}  map[string]string  "Cart already checked out or insufficient stock"
+// @Failure      422                {object}  map[string]string  "Validation failed"
+// @Failure      500                {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/carts/{cartId}/checkout [post]
+func (h *CartHandler) CheckoutCart(c fiber.Ctx) error {
+	shopID, cartID, err := parseCartParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request CheckoutPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	payments := make([]salesusecases.PaymentParam, len(request.Payments))
+	for i, payment := range request.Payments {
+		payments[i] = salesusecases.PaymentParam{
+			Method:    salesentities.PaymentMethod(payment.Method),
+			Amount:    payment.Amount,
+			Reference: payment.Reference,
+		}
+	}
+
+	result, err := h.checkoutCartUsecase.Execute(c.Context(), salesusecases.CheckoutCartParam{
+		ShopID:             shopID,
+		UserID:             userID,
+		CartID:             cartID,
+		Payments:           payments,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to check out cart")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(SaleResponse{
+		Message: "cart checked out successfully.",
+		Data: SaleResponseData{
+			Sale: toSaleDTO(*result.Sale),
+		},
+	})
+}
+
+func parseCartParams(c fiber.Ctx) (uint64, uint64, error) {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	cartID, err := strconv.ParseUint(c.Params("cartId"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid cart id")
+	}
+
+	return shopID, cartID, nil
+}
+
+// salesError maps errors of the cart, sale and register session usecases
+// to responses.
+func salesError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case isForbiddenError(err):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case strings.HasSuffix(err.Error(), "not found"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "already checked out") || strings.Contains(err.Error(), "already open") || strings.Contains(err.Error(), "already closed") || strings.HasPrefix(err.Error(), "insufficient stock"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toCartDTO(cart salesentities.Cart) CartDTO {
+	lines := make([]CartLineDTO, len(cart.Lines))
+	for i, line := range cart.Lines {
+		lines[i] = CartLineDTO{
+			ID:             line.ID,
+			VariantID:      line.VariantID,
+			SKU:            line.SKU,
+			Name:           line.Name,
+			UnitPrice:      line.UnitPrice,
+			Quantity:       line.Quantity,
+			DiscountAmount: line.DiscountAmount,
+			Total:          line.Total(),
+		}
+	}
+
+	totals := cart.Totals()
+	return CartDTO{
+		ID:             cart.ID,
+		LocationID:     cart.LocationID,
+		UserID:         cart.UserID,
+		Status:         cart.Status,
+		Lines:          lines,
+		DiscountAmount: cart.DiscountAmount,
+		TaxRate:        cart.TaxRate,
+		Subtotal:       totals.Subtotal,
+		DiscountTotal:  totals.Discount,
+		TaxTotal:       totals.Tax,
+		Total:          totals.Total,
+		CreatedAt:      cart.CreatedAt,
+		UpdatedAt:      cart.UpdatedAt,
+	}
+}
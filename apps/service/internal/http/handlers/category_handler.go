@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	catalogentities "github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	catalogusecases "github.com/reno1r/weiss/apps/service/internal/app/catalog/usecases"
+)
+
+type CategoryHandler struct {
+	listCategoriesUsecase *catalogusecases.ListCategoriesUsecase
+	createCategoryUsecase *catalogusecases.CreateCategoryUsecase
+	updateCategoryUsecase *catalogusecases.UpdateCategoryUsecase
+	deleteCategoryUsecase *catalogusecases.DeleteCategoryUsecase
+}
+
+func NewCategoryHandler(listCategoriesUsecase *catalogusecases.ListCategoriesUsecase, createCategoryUsecase *catalogusecases.CreateCategoryUsecase, updateCategoryUsecase *catalogusecases.UpdateCategoryUsecase, deleteCategoryUsecase *catalogusecases.DeleteCategoryUsecase) *CategoryHandler {
+	return &CategoryHandler{
+		listCategoriesUsecase: listCategoriesUsecase,
+		createCategoryUsecase: createCategoryUsecase,
+		updateCategoryUsecase: updateCategoryUsecase,
+		deleteCategoryUsecase: deleteCategoryUsecase,
+	}
+}
+
+type CategoryPayload struct {
+	ParentID    *uint64 `json:"parent_id,omitempty" example:"1"`             // Category to nest under; top level when left out
+	Name        string  `json:"name" example:"Coffee" binding:"required"`    // Name, unique among categories sharing a parent
+	Description string  `json:"description" example:"Espresso-based drinks"` // Category description
+}
+
+type CategoryDTO struct {
+	ID          uint64    `json:"id" example:"2"`
+	ParentID    *uint64   `json:"parent_id" example:"1"`
+	Name        string    `json:"name" example:"Coffee"`
+	Description string    `json:"description" example:"Espresso-based drinks"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type CategoryListResponse struct {
+	Message string                   `json:"message"`
+	Data    CategoryListResponseData `json:"data"`
+}
+
+type CategoryListResponseData struct {
+	Categories []CategoryDTO `json:"categories"`
+}
+
+type CategoryResponse struct {
+	Message string               `json:"message"`
+	Data    CategoryResponseData `json:"data"`
+}
+
+type CategoryResponseData struct {
+	Category CategoryDTO `json:"category"`
+}
+
+// ListCategories godoc
+// @Summary      List categories of a shop
+// @Description  List every category of a shop as a flat list ordered by name. Nested categories point to their parent with parent_id.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Shop ID"
+// @Success      200  {object}  CategoryListResponse
+// @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop"
+// @Failure      404  {object}  map[string]string  "Shop not found"
+// @Router       /shops/{id}/categories [get]
+func (h *CategoryHandler) ListCategories(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	result := h.listCategoriesUsecase.Execute(c.Context(), shopID)
+
+	categories := make([]CategoryDTO, len(result.Categories))
+	for i, category := range result.Categories {
+		categories[i] = toCategoryDTO(category)
+	}
+
+	return c.JSON(CategoryListResponse{
+		Message: "categories retrieved successfully.",
+		Data: CategoryListResponseData{
+			Categories: categories,
+		},
+	})
+}
+
+// CreateCategory godoc
+// @Summary      Create a category
+// @Description  Create a product category in a shop, optionally nested under another category of the shop
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int              true  "Shop ID"
+// @Param        request  body      CategoryPayload  true  "Category data"
+// @Success      201      {object}  CategoryResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop or missing the catalog.manage permission"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Category name already exists"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/categories [post]
+func (h *CategoryHandler) CreateCategory(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request CategoryPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.createCategoryUsecase.Execute(c.Context(), catalogusecases.CreateCategoryParam{
+		ShopID:      shopID,
+		ParentID:    request.ParentID,
+		Name:        request.Name,
+		Description: request.Description,
+	})
+	if err != nil {
+		return catalogError(err, "failed to create category")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CategoryResponse{
+		Message: "category created successfully.",
+		Data: CategoryResponseData{
+			Category: toCategoryDTO(*result.Category),
+		},
+	})
+}
+
+// UpdateCategory godoc
+// @Summary      Update a category
+// @Description  Replace the name, description and parent of a category. Leaving parent_id out moves the category to the top level. A category cannot be nested under itself or its subcategories.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int              true  "Shop ID"
+// @Param        categoryId  path      int              true  "Category ID"
+// @Param        request     body      CategoryPayload  true  "Category data"
+// @Success      200         {object}  CategoryResponse
+// @Failure      400         {object}  map[string]string  "Invalid shop or category id, or request body"
+// @Failure      403         {object}  map[string]string  "Not a member of the shop or missing the catalog.manage permission"
+// @Failure      404         {object}  map[string]string  "Shop or category not found"
+// @Failure      409         {object}  map[string]string  "Category name already exists"
+// @Failure      422         {object}  map[string]string  "Validation failed"
+// @Failure      500         {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/categories/{categoryId} [put]
+func (h *CategoryHandler) UpdateCategory(c fiber.Ctx) error {
+	shopID, categoryID, err := parseCategoryParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request CategoryPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.updateCategoryUsecase.Execute(c.Context(), catalogusecases.UpdateCategoryParam{
+		ShopID:      shopID,
+		CategoryID:  categoryID,
+		ParentID:    request.ParentID,
+		Name:        request.Name,
+		Description: request.Description,
+	})
+	if err != nil {
+		return catalogError(err, "failed to update category")
+	}
+
+	return c.JSON(CategoryResponse{
+		Message: "category updated successfully.",
+		Data: CategoryResponseData{
+			Category: toCategoryDTO(*result.Category),
+		},
+	})
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a category
+// @Description  Delete a category of a shop. Its products are kept and left uncategorized. Categories with subcategories cannot be deleted.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path  int  true  "Shop ID"
+// @Param        categoryId  path  int  true  "Category ID"
+// @Success      204         "No Content"
+// @Failure      400         {object}  map[string]string  "Invalid shop or category id"
+// @Failure      403         {object}  map[string]string  "Not a member of the shop or missing the catalog.manage permission"
+// @Failure      404         {object}  map[string]string  "Shop or category not found"
+// @Failure      409         {object}  map[string]string  "Category still has subcategories"
+// @Failure      500         {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/categories/{categoryId} [delete]
+func (h *CategoryHandler) DeleteCategory(c fiber.Ctx) error {
+	shopID, categoryID, err := parseCategoryParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.deleteCategoryUsecase.Execute(c.Context(), shopID, categoryID); err != nil {
+		return catalogError(err, "failed to delete category")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func parseCategoryParams(c fiber.Ctx) (uint64, uint64, error) {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	categoryID, err := strconv.ParseUint(c.Params("categoryId"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid category id")
+	}
+
+	return shopID, categoryID, nil
+}
+
+// catalogError maps errors of the catalog usecases to responses.
+func catalogError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case strings.HasSuffix(err.Error(), "not found"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case isConflictError(err) || strings.Contains(err.Error(), "still has subcategories"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toCategoryDTO(category catalogentities.Category) CategoryDTO {
+	return CategoryDTO{
+		ID:          category.ID,
+		ParentID:    category.ParentID,
+		Name:        category.Name,
+		Description: category.Description,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
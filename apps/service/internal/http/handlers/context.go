@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	authentities "github.com/reno1r/weiss/apps/service/internal/app/auth/entities"
+)
+
+const principalContextKey = "principal"
+
+// SetPrincipal stores the authenticated caller in the fiber context.
+// It is called by the authentication middleware once a token has been verified.
+func SetPrincipal(c fiber.Ctx, principal *authentities.Principal) {
+	c.Locals(principalContextKey, principal)
+}
+
+// GetPrincipal returns the authenticated caller stored by the authentication middleware.
+func GetPrincipal(c fiber.Ctx) (*authentities.Principal, error) {
+	principal, ok := c.Locals(principalContextKey).(*authentities.Principal)
+	if !ok || principal == nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	return principal, nil
+}
+
+// getUserIDFromContext extracts the authenticated user ID from the fiber context
+func getUserIDFromContext(c fiber.Ctx) (uint64, error) {
+	principal, err := GetPrincipal(c)
+	if err != nil {
+		return 0, err
+	}
+	return principal.UserID, nil
+}
+
+const membershipContextKey = "membership"
+
+// SetMembership stores the caller's staff record for the shop addressed by
+// the route, with its Role and Shop loaded. It is called by the shop
+// membership middleware.
+func SetMembership(c fiber.Ctx, staff *accessentities.Staff) {
+	c.Locals(membershipContextKey, staff)
+}
+
+// GetMembership returns the caller's staff record stored by the shop
+// membership middleware.
+func GetMembership(c fiber.Ctx) (*accessentities.Staff, error) {
+	staff, ok := c.Locals(membershipContextKey).(*accessentities.Staff)
+	if !ok || staff == nil {
+		return nil, fiber.NewError(fiber.StatusForbidden, "forbidden: not a member of this shop")
+	}
+	return staff, nil
+}
@@ -0,0 +1,514 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	inventoryentities "github.com/reno1r/weiss/apps/service/internal/app/inventory/entities"
+	inventoryusecases "github.com/reno1r/weiss/apps/service/internal/app/inventory/usecases"
+)
+
+type InventoryHandler struct {
+	listStockLevelsUsecase         *inventoryusecases.ListStockLevelsUsecase
+	listStockMovementsUsecase      *inventoryusecases.ListStockMovementsUsecase
+	recordMovementUsecase          *inventoryusecases.RecordMovementUsecase
+	transferStockUsecase           *inventoryusecases.TransferStockUsecase
+	getInventorySettingsUsecase    *inventoryusecases.GetInventorySettingsUsecase
+	updateInventorySettingsUsecase *inventoryusecases.UpdateInventorySettingsUsecase
+}
+
+func NewInventoryHandler(listStockLevelsUsecase *inventoryusecases.ListStockLevelsUsecase, listStockMovementsUsecase *inventoryusecases.ListStockMovementsUsecase, recordMovementUsecase *inventoryusecases.RecordMovementUsecase, transferStockUsecase *inventoryusecases.TransferStockUsecase, getInventorySettingsUsecase *inventoryusecases.GetInventorySettingsUsecase, updateInventorySettingsUsecase *inventoryusecases.UpdateInventorySettingsUsecase) *InventoryHandler {
+	return &InventoryHandler{
+		listStockLevelsUsecase:         listStockLevelsUsecase,
+		listStockMovementsUsecase:      listStockMovementsUsecase,
+		recordMovementUsecase:          recordMovementUsecase,
+		transferStockUsecase:           transferStockUsecase,
+		getInventorySettingsUsecase:    getInventorySettingsUsecase,
+		updateInventorySettingsUsecase: updateInventorySettingsUsecase,
+	}
+}
+
+type StockMovementLinePayload struct {
+	VariantID uint64 `json:"variant_id" example:"1" binding:"required"`
+	Quantity  int64  `json:"quantity" example:"12" binding:"required"` // Units moved; negative only for adjustments writing stock off
+}
+
+type StockMovementPayload struct {
+	LocationID uint64                     `json:"location_id" example:"1" binding:"required"`
+	Type       string                     `json:"type" example:"receipt" enums:"receipt,sale,adjustment,return" binding:"required"`
+	Reference  string                     `json:"reference" example:"PO-1001"`          // Document the movement comes from, such as a purchase order
+	Note       string                     `json:"note" example:"Delivered by supplier"` // Free text for the history
+	Lines      []StockMovementLinePayload `json:"lines" binding:"required"`
+}
+
+type StockTransferPayload struct {
+	FromLocationID uint64                     `json:"from_location_id" example:"2" binding:"required"`
+	ToLocationID   uint64                     `json:"to_location_id" example:"1" binding:"required"`
+	Reference      string                     `json:"reference" example:"TR-7"`
+	Note           string                     `json:"note" example:"Restocking the shop floor"`
+	Lines          []StockMovementLinePayload `json:"lines" binding:"required"` // Quantities are positive
+}
+
+type InventorySettingsPayload struct {
+	AllowNegativeStock bool `json:"allow_negative_stock" example:"false"` // Let sales and adjustments take stock below zero
+}
+
+type StockLevelDTO struct {
+	LocationID uint64    `json:"location_id" example:"1"`
+	VariantID  uint64    `json:"variant_id" example:"1"`
+	OnHand     int64     `json:"on_hand" example:"12"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type StockMovementDTO struct {
+	ID                 uint64    `json:"id" example:"1"`
+	LocationID         uint64    `json:"location_id" example:"1"`
+	VariantID          uint64    `json:"variant_id" example:"1"`
+	Type               string    `json:"type" example:"receipt"`
+	Quantity           int64     `json:"quantity" example:"12"`
+	BalanceAfter       int64     `json:"balance_after" example:"12"`
+	TransferLocationID *uint64   `json:"transfer_location_id" example:"2"`
+	Reference          string    `json:"reference" example:"PO-1001"`
+	Note               string    `json:"note" example:"Delivered by supplier"`
+	UserID             uint64    `json:"user_id" example:"1"`
+	CreatedAt          time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type InventorySettingsDTO struct {
+	AllowNegativeStock bool      `json:"allow_negative_stock" example:"false"`
+	UpdatedAt          time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type StockLevelListResponse struct {
+	Message string                     `json:"message"`
+	Data    StockLevelListResponseData `json:"data"`
+}
+
+type StockLevelListResponseData struct {
+	Levels     []StockLevelDTO `json:"levels"`
+	NextCursor string          `json:"next_cursor,omitempty" example:"eyJzIjoibG9jYXRpb24iLCJ2IjoiMSIsImlkIjoxfQ"`
+}
+
+type StockMovementListResponse struct {
+	Message string                        `json:"message"`
+	Data    StockMovementListResponseData `json:"data"`
+}
+
+type StockMovementListResponseData struct {
+	Movements  []StockMovementDTO `json:"movements"`
+	NextCursor string             `json:"next_cursor,omitempty" example:"eyJzIjoicmVjZW50IiwidiI6IiIsImlkIjoxfQ"`
+}
+
+type InventorySettingsResponse struct {
+	Message string                        `json:"message"`
+	Data    InventorySettingsResponseData `json:"data"`
+}
+
+type InventorySettingsResponseData struct {
+	Settings InventorySettingsDTO `json:"settings"`
+}
+
+// ListStockLevels godoc
+// @Summary      List stock levels
+// @Description  List the quantity on hand of every variant at every location of a shop, ordered by location and variant, one page at a time. Variants that never moved at a location are left out, and so are locations the caller does not work at. Pass next_cursor from a response as cursor to get the next page.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      int     true   "Shop ID"
+// @Param        location_id  query     int     false  "Only list levels at this location"
+// @Param        variant_id   query     int     false  "Only list levels of this variant"
+// @Param        cursor       query     string  false  "Cursor returned by the previous page"
+// @Param        limit        query     int     false  "Page size, at most 100"  default(20)
+// @Success      200          {object}  StockLevelListResponse
+// @Failure      400          {object}  map[string]string  "Invalid shop id, location_id, variant_id or limit"
+// @Failure      403          {object}  map[string]string  "Not a member of the shop, missing the inventory.view permission or not working at the location"
+// @Failure      404          {object}  map[string]string  "Shop not found"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/inventory/levels [get]
+func (h *InventoryHandler) ListStockLevels(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+	locationID, err := parseOptionalID(c, "location_id")
+	if err != nil {
+		return err
+	}
+	variantID, err := parseOptionalID(c, "variant_id")
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.listStockLevelsUsecase.Execute(c.Context(), inventoryusecases.ListStockLevelsParam{
+		ShopID:             shopID,
+		LocationID:         locationID,
+		VariantID:          variantID,
+		Cursor:             c.Query("cursor"),
+		Limit:              limit,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return inventoryError(err, "failed to list stock levels")
+	}
+
+	levels := make([]StockLevelDTO, len(result.Levels))
+	for i, level := range result.Levels {
+		levels[i] = StockLevelDTO{
+			LocationID: level.LocationID,
+			VariantID:  level.VariantID,
+			OnHand:     level.OnHand,
+			UpdatedAt:  level.UpdatedAt,
+		}
+	}
+
+	return c.JSON(StockLevelListResponse{
+		Message: "stock levels retrieved successfully.",
+		Data: StockLevelListResponseData{
+			Levels:     levels,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// ListStockMovements godoc
+// @Summary      List stock movements
+// @Description  List the stock ledger of a shop, newest first, one page at a time. Staff limited to some locations only see movements at those. Pass next_cursor from a response as cursor to get the next page.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      int     true   "Shop ID"
+// @Param        location_id  query     int     false  "Only list movements at this location"
+// @Param        variant_id   query     int     false  "Only list movements of this variant"
+// @Param        type         query     string  false  "Only list movements of this type"  Enums(receipt, sale, adjustment, transfer, return)
+// @Param        cursor       query     string  false  "Cursor returned by the previous page"
+// @Param        limit        query     int     false  "Page size, at most 100"  default(20)
+// @Success      200          {object}  StockMovementListResponse
+// @Failure      400          {object}  map[string]string  "Invalid shop id, location_id, variant_id or limit"
+// @Failure      403          {object}  map[string]string  "Not a member of the shop, missing the inventory.view permission or not working at the location"
+// @Failure      404          {object}  map[string]string  "Shop not found"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/inventory/movements [get]
+func (h *InventoryHandler) ListStockMovements(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+	locationID, err := parseOptionalID(c, "location_id")
+	if err != nil {
+		return err
+	}
+	variantID, err := parseOptionalID(c, "variant_id")
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.listStockMovementsUsecase.Execute(c.Context(), inventoryusecases.ListStockMovementsParam{
+		ShopID:             shopID,
+		LocationID:         locationID,
+		VariantID:          variantID,
+		Type:               inventoryentities.MovementType(c.Query("type")),
+		Cursor:             c.Query("cursor"),
+		Limit:              limit,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return inventoryError(err, "failed to list stock movements")
+	}
+
+	return c.JSON(StockMovementListResponse{
+		Message: "stock movements retrieved successfully.",
+		Data: StockMovementListResponseData{
+			Movements:  toStockMovementDTOs(result.Movements),
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// RecordStockMovement godoc
+// @Summary      Record a stock movement
+// @Description  Record stock received, sold, adjusted or returned at a location of a shop, one movement per line. All lines are recorded or none is. Movements cannot be edited or deleted; record another movement to correct one.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                   true  "Shop ID"
+// @Param        request  body      StockMovementPayload  true  "Movement data"
+// @Success      201      {object}  StockMovementListResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the inventory.adjust permission or not working at the location"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Insufficient stock"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/inventory/movements [post]
+func (h *InventoryHandler) RecordStockMovement(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request StockMovementPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.recordMovementUsecase.Execute(c.Context(), inventoryusecases.RecordMovementParam{
+		ShopID:             shopID,
+		UserID:             userID,
+		LocationID:         request.LocationID,
+		Type:               inventoryentities.MovementType(request.Type),
+		Reference:          request.Reference,
+		Note:               request.Note,
+		Lines:              toMovementLineParams(request.Lines),
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return inventoryError(err, "failed to record stock movement")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(StockMovementListResponse{
+		Message: "stock movement recorded successfully.",
+		Data: StockMovementListResponseData{
+			Movements: toStockMovementDTOs(result.Movements),
+		},
+	})
+}
+
+// TransferStock godoc
+// @Summary      Transfer stock between locations
+// @Description  Move stock from one location of a shop to another. Every line is recorded as a transfer out of the sending location followed by a transfer into the receiving one. Staff limited to some locations can only send stock from those.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                   true  "Shop ID"
+// @Param        request  body      StockTransferPayload  true  "Transfer data"
+// @Success      201      {object}  StockMovementListResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the inventory.adjust permission or not working at the location"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Insufficient stock"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/inventory/transfers [post]
+func (h *InventoryHandler) TransferStock(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request StockTransferPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.transferStockUsecase.Execute(c.Context(), inventoryusecases.TransferStockParam{
+		ShopID:             shopID,
+		UserID:             userID,
+		FromLocationID:     request.FromLocationID,
+		ToLocationID:       request.ToLocationID,
+		Reference:          request.Reference,
+		Note:               request.Note,
+		Lines:              toMovementLineParams(request.Lines),
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return inventoryError(err, "failed to transfer stock")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(StockMovementListResponse{
+		Message: "stock transferred successfully.",
+		Data: StockMovementListResponseData{
+			Movements: toStockMovementDTOs(result.Movements),
+		},
+	})
+}
+
+// GetInventorySettings godoc
+// @Summary      Get inventory settings
+// @Description  Get the inventory policy of a shop
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Shop ID"
+// @Success      200  {object}  InventorySettingsResponse
+// @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop, missing the inventory.view permission or not working at the location"
+// @Failure      404  {object}  map[string]string  "Shop not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/inventory/settings [get]
+func (h *InventoryHandler) GetInventorySettings(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	result, err := h.getInventorySettingsUsecase.Execute(c.Context(), shopID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to get inventory settings")
+	}
+
+	return c.JSON(InventorySettingsResponse{
+		Message: "inventory settings retrieved successfully.",
+		Data: InventorySettingsResponseData{
+			Settings: toInventorySettingsDTO(result.Settings),
+		},
+	})
+}
+
+// UpdateInventorySettings godoc
+// @Summary      Update inventory settings
+// @Description  Replace the inventory policy of a shop. When negative stock is not allowed, movements taking a level below zero are refused.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                       true  "Shop ID"
+// @Param        request  body      InventorySettingsPayload  true  "Settings"
+// @Success      200      {object}  InventorySettingsResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop or missing the shop.update permission"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/inventory/settings [put]
+func (h *InventoryHandler) UpdateInventorySettings(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request InventorySettingsPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.updateInventorySettingsUsecase.Execute(c.Context(), inventoryusecases.UpdateInventorySettingsParam{
+		ShopID:             shopID,
+		AllowNegativeStock: request.AllowNegativeStock,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update inventory settings")
+	}
+
+	return c.JSON(InventorySettingsResponse{
+		Message: "inventory settings updated successfully.",
+		Data: InventorySettingsResponseData{
+			Settings: toInventorySettingsDTO(result.Settings),
+		},
+	})
+}
+
+// parseOptionalID reads an optional ID query parameter.
+func parseOptionalID(c fiber.Ctx, name string) (*uint64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid "+name)
+	}
+	return &id, nil
+}
+
+// inventoryError maps errors of the inventory usecases to responses.
+func inventoryError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case isForbiddenError(err):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case strings.HasSuffix(err.Error(), "not found"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case strings.HasPrefix(err.Error(), "insufficient stock"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toMovementLineParams(lines []StockMovementLinePayload) []inventoryusecases.MovementLineParam {
+	params := make([]inventoryusecases.MovementLineParam, len(lines))
+	for i, line := range lines {
+		params[i] = inventoryusecases.MovementLineParam{
+			VariantID: line.VariantID,
+			Quantity:  line.Quantity,
+		}
+	}
+	return params
+}
+
+func toStockMovementDTOs(movements []inventoryentities.StockMovement) []StockMovementDTO {
+	dtos := make([]StockMovementDTO, len(movements))
+	for i, movement := range movements {
+		dtos[i] = StockMovementDTO{
+			ID:                 movement.ID,
+			LocationID:         movement.LocationID,
+			VariantID:          movement.VariantID,
+			Type:               string(movement.Type),
+			Quantity:           movement.Quantity,
+			BalanceAfter:       movement.BalanceAfter,
+			TransferLocationID: movement.TransferLocationID,
+			Reference:          movement.Reference,
+			Note:               movement.Note,
+			UserID:             movement.UserID,
+			CreatedAt:          movement.CreatedAt,
+		}
+	}
+	return dtos
+}
+
+func toInventorySettingsDTO(settings inventoryentities.Settings) InventorySettingsDTO {
+	return InventorySettingsDTO{
+		AllowNegativeStock: settings.AllowNegativeStock,
+		UpdatedAt:          settings.UpdatedAt,
+	}
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+)
+
+type InvitationHandler struct {
+	inviteStaffUsecase         *accessusecases.InviteStaffUsecase
+	listInvitationsUsecase     *accessusecases.ListInvitationsUsecase
+	listUserInvitationsUsecase *accessusecases.ListUserInvitationsUsecase
+	revokeInvitationUsecase    *accessusecases.RevokeInvitationUsecase
+	respondInvitationUsecase   *accessusecases.RespondInvitationUsecase
+}
+
+func NewInvitationHandler(inviteStaffUsecase *accessusecases.InviteStaffUsecase, listInvitationsUsecase *accessusecases.ListInvitationsUsecase, listUserInvitationsUsecase *accessusecases.ListUserInvitationsUsecase, revokeInvitationUsecase *accessusecases.RevokeInvitationUsecase, respondInvitationUsecase *accessusecases.RespondInvitationUsecase) *InvitationHandler {
+	return &InvitationHandler{
+		inviteStaffUsecase:         inviteStaffUsecase,
+		listInvitationsUsecase:     listInvitationsUsecase,
+		listUserInvitationsUsecase: listUserInvitationsUsecase,
+		revokeInvitationUsecase:    revokeInvitationUsecase,
+		respondInvitationUsecase:   respondInvitationUsecase,
+	}
+}
+
+// InviteStaffPayload names the person to invite by email or by phone.
+// Exactly one of the two must be set.
+type InviteStaffPayload struct {
+	Email  string `json:"email,omitempty" example:"jane@example.com"` // Email address to send the invitation to
+	Phone  string `json:"phone,omitempty" example:"1234567890"`       // Phone number to send the invitation to
+	RoleID uint64 `json:"role_id" example:"2" binding:"required"`     // Role offered to the invitee
+}
+
+type InvitationTokenPayload struct {
+	Token string `json:"token" example:"k3Jx..." binding:"required"` // Token from the invitation message
+}
+
+type InvitationDTO struct {
+	ID        uint64    `json:"id" example:"1"`
+	ShopID    uint64    `json:"shop_id" example:"1"`
+	ShopName  string    `json:"shop_name,omitempty" example:"My Shop"`
+	Role      RoleDTO   `json:"role"`
+	Email     string    `json:"email,omitempty" example:"jane@example.com"`
+	Phone     string    `json:"phone,omitempty" example:"1234567890"`
+	Status    string    `json:"status" example:"pending"`
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-08T00:00:00Z"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type InvitationListResponse struct {
+	Message string                     `json:"message"`
+	Data    InvitationListResponseData `json:"data"`
+}
+
+type InvitationListResponseData struct {
+	Invitations []InvitationDTO `json:"invitations"`
+}
+
+type InvitationResponse struct {
+	Message string                 `json:"message"`
+	Data    InvitationResponseData `json:"data"`
+}
+
+type InvitationResponseData struct {
+	Invitation InvitationDTO     `json:"invitation"`
+	Staff      *StaffResponseDTO `json:"staff,omitempty"`
+}
+
+// ListInvitations godoc
+// @Summary      List pending invitations of a shop
+// @Description  List the invitations of a shop that have been neither answered, revoked nor expired
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Shop ID"
+// @Success      200  {object}  InvitationListResponse
+// @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop or missing the staff.view permission"
+// @Failure      404  {object}  map[string]string  "Shop not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/invitations [get]
+func (h *InvitationHandler) ListInvitations(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	result := h.listInvitationsUsecase.Execute(c.Context(), shopID)
+
+	return c.JSON(InvitationListResponse{
+		Message: "invitations retrieved successfully.",
+		Data: InvitationListResponseData{
+			Invitations: toInvitationDTOs(result.Invitations),
+		},
+	})
+}
+
+// InviteStaff godoc
+// @Summary      Invite someone to a shop
+// @Description  Invite someone to join a shop with a role by email or phone. They do not need an account yet. A token is sent to the contact to accept or decline the invitation with. Only roles whose permissions the caller holds can be offered.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "Shop ID"
+// @Param        request  body      InviteStaffPayload  true  "Invitation data"
+// @Success      201      {object}  InvitationResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the staff.assign permission, or offering a permission the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop or role not found"
+// @Failure      409      {object}  map[string]string  "Already a staff member or already invited"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Failure      503      {object}  map[string]string  "SMS delivery is not configured"
+// @Router       /shops/{id}/invitations [post]
+func (h *InvitationHandler) InviteStaff(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request InviteStaffPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.inviteStaffUsecase.Execute(c.Context(), accessusecases.InviteStaffParam{
+		ActorID: actorID,
+		ShopID:  shopID,
+		Email:   request.Email,
+		Phone:   request.Phone,
+		RoleID:  request.RoleID,
+	})
+	if err != nil {
+		return invitationError(err, "failed to create invitation")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(InvitationResponse{
+		Message: "invitation sent successfully.",
+		Data: InvitationResponseData{
+			Invitation: toInvitationDTO(*result.Invitation),
+		},
+	})
+}
+
+// RevokeInvitation godoc
+// @Summary      Revoke an invitation
+// @Description  Withdraw a pending invitation of a shop so that it can no longer be accepted
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id            path  int  true  "Shop ID"
+// @Param        invitationId  path  int  true  "Invitation ID"
+// @Success      204           "No Content"
+// @Failure      400           {object}  map[string]string  "Invalid shop or invitation id"
+// @Failure      403           {object}  map[string]string  "Not a member of the shop or missing the staff.assign permission"
+// @Failure      404           {object}  map[string]string  "Shop or invitation not found"
+// @Failure      409           {object}  map[string]string  "Invitation no longer pending"
+// @Failure      500           {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/invitations/{invitationId} [delete]
+func (h *InvitationHandler) RevokeInvitation(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	invitationID, err := strconv.ParseUint(c.Params("invitationId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid invitation id")
+	}
+
+	if err := h.revokeInvitationUsecase.Execute(c.Context(), shopID, invitationID); err != nil {
+		return invitationError(err, "failed to revoke invitation")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// ListMyInvitations godoc
+// @Summary      List my pending invitations
+// @Description  List the pending invitations sent to the current user, including those sent before they registered
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  InvitationListResponse
+// @Failure      401  {object}  map[string]string  "Unauthorized"
+// @Router       /me/invitations [get]
+func (h *InvitationHandler) ListMyInvitations(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result := h.listUserInvitationsUsecase.Execute(c.Context(), userID)
+
+	return c.JSON(InvitationListResponse{
+		Message: "invitations retrieved successfully.",
+		Data: InvitationListResponseData{
+			Invitations: toInvitationDTOs(result.Invitations),
+		},
+	})
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept an invitation
+// @Description  Accept the invitation a token was sent for and join the shop with the offered role. The invitation must have been sent to the current user's email or phone.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      InvitationTokenPayload  true  "Invitation token"
+// @Success      200      {object}  InvitationResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body or invalid or expired invitation"
+// @Failure      403      {object}  map[string]string  "Invitation sent to someone else"
+// @Failure      404      {object}  map[string]string  "Offered role no longer exists"
+// @Failure      409      {object}  map[string]string  "Already a staff member of the shop"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /invitations/accept [post]
+func (h *InvitationHandler) AcceptInvitation(c fiber.Ctx) error {
+	return h.respond(c, true)
+}
+
+// DeclineInvitation godoc
+// @Summary      Decline an invitation
+// @Description  Decline the invitation a token was sent for. The invitation must have been sent to the current user's email or phone.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      InvitationTokenPayload  true  "Invitation token"
+// @Success      200      {object}  InvitationResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body or invalid or expired invitation"
+// @Failure      403      {object}  map[string]string  "Invitation sent to someone else"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /invitations/decline [post]
+func (h *InvitationHandler) DeclineInvitation(c fiber.Ctx) error {
+	return h.respond(c, false)
+}
+
+func (h *InvitationHandler) respond(c fiber.Ctx, accept bool) error {
+	var request InvitationTokenPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.respondInvitationUsecase.Execute(c.Context(), accessusecases.RespondInvitationParam{
+		UserID: userID,
+		Token:  request.Token,
+		Accept: accept,
+	})
+	if err != nil {
+		return invitationError(err, "failed to respond to invitation")
+	}
+
+	response := InvitationResponse{
+		Message: "invitation declined.",
+		Data: InvitationResponseData{
+			Invitation: toInvitationDTO(*result.Invitation),
+		},
+	}
+	if result.Staff != nil {
+		staff := toStaffResponseDTO(result.Staff)
+		response.Message = "invitation accepted."
+		response.Data.Staff = &staff
+	}
+	return c.JSON(response)
+}
+
+func invitationError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case isForbiddenError(err):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case err.Error() == "invalid or expired invitation":
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case strings.HasSuffix(err.Error(), "not found"):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "already") || strings.Contains(err.Error(), "no longer pending"):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case isChannelUnavailableError(err):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toInvitationDTO(invitation accessentities.Invitation) InvitationDTO {
+	response := InvitationDTO{
+		ID:        invitation.ID,
+		ShopID:    invitation.ShopID,
+		Email:     invitation.Email,
+		Phone:     invitation.Phone,
+		Status:    invitation.Status,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+	if invitation.Shop != nil {
+		response.ShopName = invitation.Shop.Name
+	}
+	if invitation.Role != nil {
+		response.Role = RoleDTO{
+			ID:          invitation.Role.ID,
+			Name:        invitation.Role.Name,
+			Description: invitation.Role.Description,
+		}
+	}
+	return response
+}
+
+func toInvitationDTOs(invitations []accessentities.Invitation) []InvitationDTO {
+	response := make([]InvitationDTO, len(invitations))
+	for i, invitation := range invitations {
+		response[i] = toInvitationDTO(invitation)
+	}
+	return response
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
+)
+
+type JWKSHandler struct {
+	tokenService *services.TokenService
+}
+
+func NewJWKSHandler(tokenService *services.TokenService) *JWKSHandler {
+	return &JWKSHandler{
+		tokenService: tokenService,
+	}
+}
+
+// Handle serves the public keys that verify tokens issued by this service,
+// identified by kid. The set is empty when tokens are signed with a shared
+// HS256 secret. It lives outside the /api base path, so it is not part of the
+// swagger document.
+func (h *JWKSHandler) Handle(c fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.tokenService.JWKS())
+}
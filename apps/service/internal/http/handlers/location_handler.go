@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
+	shopusecases "github.com/reno1r/weiss/apps/service/internal/app/shop/usecases"
+)
+
+type LocationHandler struct {
+	listLocationsUsecase  *shopusecases.ListLocationsUsecase
+	createLocationUsecase *shopusecases.CreateLocationUsecase
+	updateLocationUsecase *shopusecases.UpdateLocationUsecase
+}
+
+func NewLocationHandler(listLocationsUsecase *shopusecases.ListLocationsUsecase, createLocationUsecase *shopusecases.CreateLocationUsecase, updateLocationUsecase *shopusecases.UpdateLocationUsecase) *LocationHandler {
+	return &LocationHandler{
+		listLocationsUsecase:  listLocationsUsecase,
+		createLocationUsecase: createLocationUsecase,
+		updateLocationUsecase: updateLocationUsecase,
+	}
+}
+
+type LocationPayload struct {
+	Name    string `json:"name" example:"Airport" binding:"required"`      // Name, unique within the shop
+	Kind    string `json:"kind" example:"outlet" enums:"outlet,warehouse"` // Outlet selling to customers or warehouse; outlet when left out on creation
+	Address string `json:"address" example:"Terminal 2, Gate 14"`          // Street address of the branch
+	Phone   string `json:"phone" example:"+15550100"`                      // Phone number of the branch
+}
+
+type LocationDTO struct {
+	ID        uint64    `json:"id" example:"1"`
+	Name      string    `json:"name" example:"Main"`
+	Kind      string    `json:"kind" example:"outlet"`
+	Address   string    `json:"address" example:"123 Main St"`
+	Phone     string    `json:"phone" example:"+15550100"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type LocationListResponse struct {
+	Message string                   `json:"message"`
+	Data    LocationListResponseData `json:"data"`
+}
+
+type LocationListResponseData struct {
+	Locations []LocationDTO `json:"locations"`
+}
+
+type LocationResponse struct {
+	Message string               `json:"message"`
+	Data    LocationResponseData `json:"data"`
+}
+
+type LocationResponseData struct {
+	Location LocationDTO `json:"location"`
+}
+
+// ListLocations godoc
+// @Summary      List locations of a shop
+// @Description  List the branches of a shop, outlets and warehouses alike, ordered by name. Every shop starts with a Main outlet.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Shop ID"
+// @Success      200  {object}  LocationListResponse
+// @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop"
+// @Failure      404  {object}  map[string]string  "Shop not found"
+// @Router       /shops/{id}/locations [get]
+func (h *LocationHandler) ListLocations(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	result := h.listLocationsUsecase.Execute(c.Context(), shopID)
+
+	locations := make([]LocationDTO, len(result.Locations))
+	for i, location := range result.Locations {
+		locations[i] = toLocationDTO(location)
+	}
+
+	return c.JSON(LocationListResponse{
+		Message: "locations retrieved successfully.",
+		Data: LocationListResponseData{
+			Locations: locations,
+		},
+	})
+}
+
+// CreateLocation godoc
+// @Summary      Create a location
+// @Description  Add a branch to a shop: an outlet selling to customers or a warehouse holding stock
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int              true  "Shop ID"
+// @Param        request  body      LocationPayload  true  "Location data"
+// @Success      201      {object}  LocationResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop or missing the shop.update permission"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Location name already exists"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/locations [post]
+func (h *LocationHandler) CreateLocation(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request LocationPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.createLocationUsecase.Execute(c.Context(), shopusecases.CreateLocationParam{
+		ShopID:  shopID,
+		Name:    request.Name,
+		Kind:    request.Kind,
+		Address: request.Address,
+		Phone:   request.Phone,
+	})
+	if err != nil {
+		return locationError(err, "failed to create location")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(LocationResponse{
+		Message: "location created successfully.",
+		Data: LocationResponseData{
+			Location: toLocationDTO(*result.Location),
+		},
+	})
+}
+
+// UpdateLocation godoc
+// @Summary      Update a location
+// @Description  Replace the name, kind, address and phone of a branch. Its stock stays where it is.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int              true  "Shop ID"
+// @Param        locationId  path      int              true  "Location ID"
+// @Param        request     body      LocationPayload  true  "Location data"
+// @Success      200         {object}  LocationResponse
+// @Failure      400         {object}  map[string]string  "Invalid shop or location id, or request body"
+// @Failure      403         {object}  map[string]string  "Not a member of the shop or missing the shop.update permission"
+// @Failure      404         {object}  map[string]string  "Shop or location not found"
+// @Failure      409         {object}  map[string]string  "Location name already exists"
+// @Failure      422         {object}  map[string]string  "Validation failed"
+// @Failure      500         {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/locations/{locationId} [put]
+func (h *LocationHandler) UpdateLocation(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	locationID, err := strconv.ParseUint(c.Params("locationId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid location id")
+	}
+
+	var request LocationPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.updateLocationUsecase.Execute(c.Context(), shopusecases.UpdateLocationParam{
+		ShopID:     shopID,
+		LocationID: locationID,
+		Name:       request.Name,
+		Kind:       request.Kind,
+		Address:    request.Address,
+		Phone:      request.Phone,
+	})
+	if err != nil {
+		return locationError(err, "failed to update location")
+	}
+
+	return c.JSON(LocationResponse{
+		Message: "location updated successfully.",
+		Data: LocationResponseData{
+			Location: toLocationDTO(*result.Location),
+		},
+	})
+}
+
+// locationError maps errors of the location usecases to responses.
+func locationError(err error, fallback string) error {
+	switch {
+	case isValidationError(err):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case err.Error() == "location not found":
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case isConflictError(err):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, fallback)
+	}
+}
+
+func toLocationDTO(location shopentities.Location) LocationDTO {
+	return LocationDTO{
+		ID:        location.ID,
+		Name:      location.Name,
+		Kind:      location.Kind,
+		Address:   location.Address,
+		Phone:     location.Phone,
+		CreatedAt: location.CreatedAt,
+		UpdatedAt: location.UpdatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
@@ -28,14 +29,18 @@ type LoginResponse struct {
 	Data    LoginResponseData `json:"data"`
 }
 
+// LoginResponseData holds either the tokens or, for accounts with two-factor
+// authentication, the MFA token to exchange at /auth/mfa/verify.
 type LoginResponseData struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MfaRequired  bool   `json:"mfa_required,omitempty"`
+	MfaToken     string `json:"mfa_token,omitempty"`
 }
 
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate user with email/phone and password, returns JWT tokens
+// @Description  Authenticate user with email/phone and password, returns JWT tokens. Accounts with two-factor authentication get an MFA token instead, to be exchanged at /auth/mfa/verify.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -43,7 +48,9 @@ type LoginResponseData struct {
 // @Success      200         {object}  LoginResponse
 // @Failure      400         {object}  map[string]string  "Invalid request body"
 // @Failure      401         {object}  map[string]string  "Invalid credentials"
+// @Failure      403         {object}  map[string]string  "Account not verified or suspended"
 // @Failure      422         {object}  map[string]string  "Validation failed"
+// @Failure      429         {object}  map[string]string  "Too many failed attempts"
 // @Failure      500         {object}  map[string]string  "Internal server error"
 // @Router       /auth/login [post]
 func (h *LoginHandler) Handle(c fiber.Ctx) error {
@@ -55,20 +62,39 @@ func (h *LoginHandler) Handle(c fiber.Ctx) error {
 
 	ctx := c.Context()
 	response, err := h.loginUsecase.Execute(ctx, usecases.LoginParam{
-		Email:    credentials.Email,
-		Phone:    credentials.Phone,
-		Password: credentials.Password,
+		Email:     credentials.Email,
+		Phone:     credentials.Phone,
+		Password:  credentials.Password,
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
 	})
 	if err != nil {
+		var retryErr *usecases.RetryAfterError
+		if errors.As(err, &retryErr) {
+			return tooManyRequests(c, retryErr)
+		}
 		if isValidationError(err) {
 			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
 		}
 		if isInvalidCredentialsError(err) {
 			return fiber.NewError(fiber.StatusUnauthorized, "invalid credentials")
 		}
+		if isNotVerifiedError(err) || isSuspendedAccountError(err) {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to process login request")
 	}
 
+	if response.MfaRequired {
+		return c.JSON(LoginResponse{
+			Message: "two-factor authentication required.",
+			Data: LoginResponseData{
+				MfaRequired: true,
+				MfaToken:    response.MfaToken,
+			},
+		})
+	}
+
 	return c.JSON(LoginResponse{
 		Message: "authorized.",
 		Data: LoginResponseData{
@@ -85,3 +111,7 @@ func isValidationError(err error) bool {
 func isInvalidCredentialsError(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "invalid credentials")
 }
+
+func isSuspendedAccountError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "account suspended")
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type LogoutHandler struct {
+	logoutUsecase *usecases.LogoutUsecase
+}
+
+func NewLogoutHandler(logoutUsecase *usecases.LogoutUsecase) *LogoutHandler {
+	return &LogoutHandler{
+		logoutUsecase: logoutUsecase,
+	}
+}
+
+type LogoutPayload struct {
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." binding:"required"` // Refresh token of the session to terminate
+}
+
+// Logout godoc
+// @Summary      Logout
+// @Description  Terminate the session that owns the given refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      LogoutPayload  true  "Refresh token"
+// @Success      204      "No Content"
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Invalid refresh token"
+// @Failure      404      {object}  map[string]string  "Session not found"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/logout [post]
+func (h *LogoutHandler) Handle(c fiber.Ctx) error {
+	var request LogoutPayload
+
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Context()
+	err = h.logoutUsecase.Execute(ctx, usecases.LogoutParam{
+		UserID:       userID,
+		RefreshToken: request.RefreshToken,
+	})
+	if err != nil {
+		if isRequiredError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidRefreshTokenError(err) {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid refresh token")
+		}
+		if err.Error() == "session not found" {
+			return fiber.NewError(fiber.StatusNotFound, "session not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to logout")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
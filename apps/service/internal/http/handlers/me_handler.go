@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+	userusecases "github.com/reno1r/weiss/apps/service/internal/app/user/usecases"
+)
+
+type MeHandler struct {
+	getUserUsecase              *userusecases.GetUserUsecase
+	updateProfileUsecase        *userusecases.UpdateProfileUsecase
+	changePasswordUsecase       *usecases.ChangePasswordUsecase
+	requestContactChangeUsecase *usecases.RequestContactChangeUsecase
+	confirmContactChangeUsecase *usecases.ConfirmContactChangeUsecase
+}
+
+func NewMeHandler(getUserUsecase *userusecases.GetUserUsecase, updateProfileUsecase *userusecases.UpdateProfileUsecase, changePasswordUsecase *usecases.ChangePasswordUsecase, requestContactChangeUsecase *usecases.RequestContactChangeUsecase, confirmContactChangeUsecase *usecases.ConfirmContactChangeUsecase) *MeHandler {
+	return &MeHandler{
+		getUserUsecase:              getUserUsecase,
+		updateProfileUsecase:        updateProfileUsecase,
+		changePasswordUsecase:       changePasswordUsecase,
+		requestContactChangeUsecase: requestContactChangeUsecase,
+		confirmContactChangeUsecase: confirmContactChangeUsecase,
+	}
+}
+
+type UpdateMePayload struct {
+	FullName *string `json:"full_name,omitempty" example:"John Doe"` // New full name
+}
+
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"current_password" example:"password123" binding:"required"` // Current password
+	NewPassword     string `json:"new_password" example:"newpassword123" binding:"required"`  // New password
+}
+
+type ChangeEmailPayload struct {
+	Email    string `json:"email" example:"new@example.com" binding:"required"` // New email address
+	Password string `json:"password" example:"password123" binding:"required"`  // Current password
+}
+
+type ChangePhonePayload struct {
+	Phone    string `json:"phone" example:"0987654321" binding:"required"`     // New phone number
+	Password string `json:"password" example:"password123" binding:"required"` // Current password
+}
+
+type ConfirmContactChangePayload struct {
+	Code string `json:"code" example:"123456" binding:"required"` // Six digit code sent to the new contact
+}
+
+type MeResponse struct {
+	Message string         `json:"message"`
+	Data    MeResponseData `json:"data"`
+}
+
+type MeResponseData struct {
+	User *UserResponse `json:"user"`
+}
+
+type MeMessageResponse struct {
+	Message string `json:"message"`
+}
+
+// GetMe godoc
+// @Summary      Get current user
+// @Description  Get the profile of the authenticated user
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  MeResponse
+// @Failure      401  {object}  map[string]string  "Authentication required"
+// @Failure      404  {object}  map[string]string  "User not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /me [get]
+func (h *MeHandler) GetMe(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Context()
+	result, err := h.getUserUsecase.Execute(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to get user")
+	}
+
+	return c.JSON(MeResponse{
+		Message: "user retrieved successfully.",
+		Data: MeResponseData{
+			User: toUserResponse(result.User),
+		},
+	})
+}
+
+// UpdateMe godoc
+// @Summary      Update current user
+// @Description  Update the profile of the authenticated user. Email and phone are changed through their own verified flows.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      UpdateMePayload  true  "Profile fields to update"
+// @Success      200      {object}  MeResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Authentication required"
+// @Failure      404      {object}  map[string]string  "User not found"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /me [patch]
+func (h *MeHandler) UpdateMe(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	var request UpdateMePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	result, err := h.updateProfileUsecase.Execute(ctx, userusecases.UpdateProfileParam{
+		UserID:   userID,
+		FullName: request.FullName,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if err.Error() == "user not found" {
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update user")
+	}
+
+	return c.JSON(MeResponse{
+		Message: "user updated successfully.",
+		Data: MeResponseData{
+			User: toUserResponse(result.User),
+		},
+	})
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Change the password of the authenticated user. Every session is signed out afterwards.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      ChangePasswordPayload  true  "Current and new password"
+// @Success      200      {object}  MeMessageResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Invalid credentials"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      429      {object}  map[string]string  "Too many failed password attempts"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /me/password [post]
+func (h *MeHandler) ChangePassword(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	var request ChangePasswordPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	err = h.changePasswordUsecase.Execute(ctx, usecases.ChangePasswordParam{
+		UserID:          userID,
+		CurrentPassword: request.CurrentPassword,
+		NewPassword:     request.NewPassword,
+	})
+	if err != nil {
+		var retryErr *usecases.RetryAfterError
+		if errors.As(err, &retryErr) {
+			return tooManyRequests(c, retryErr)
+		}
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidCredentialsError(err) {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid credentials")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to change password")
+	}
+
+	return c.JSON(MeMessageResponse{
+		Message: "password changed successfully. please log in again.",
+	})
+}
+
+// RequestEmailChange godoc
+// @Summary      Request email change
+// @Description  Send a verification code to a new email address. The email is switched once the code is confirmed.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      ChangeEmailPayload  true  "New email and current password"
+// @Success      202      {object}  MeMessageResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Invalid credentials"
+// @Failure      409      {object}  map[string]string  "Email already in use"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      429      {object}  map[string]string  "Code sent recently or too many failed password attempts"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /me/email [post]
+func (h *MeHandler) RequestEmailChange(c fiber.Ctx) error {
+	var request ChangeEmailPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	return h.requestContactChange(c, usecases.RequestContactChangeParam{
+		Email:    request.Email,
+		Password: request.Password,
+	})
+}
+
+// RequestPhoneChange godoc
+// @Summary      Request phone change
+// @Description  Send a verification code to a new phone number. The phone is switched once the code is confirmed.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      ChangePhonePayload  true  "New phone and current password"
+// @Success      202      {object}  MeMessageResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Invalid credentials"
+// @Failure      409      {object}  map[string]string  "Phone already in use"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      429      {object}  map[string]string  "Code sent recently or too many failed password attempts"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Failure      503      {object}  map[string]string  "SMS delivery is not configured"
+// @Router       /me/phone [post]
+func (h *MeHandler) RequestPhoneChange(c fiber.Ctx) error {
+	var request ChangePhonePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	return h.requestContactChange(c, usecases.RequestContactChangeParam{
+		Phone:    request.Phone,
+		Password: request.Password,
+	})
+}
+
+// ConfirmEmailChange godoc
+// @Summary      Confirm email change
+// @Description  Switch to the new email address with the code that was sent to it
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      ConfirmContactChangePayload  true  "Verification code"
+// @Success      200      {object}  MeResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body or code"
+// @Failure      401      {object}  map[string]string  "Authentication required"
+// @Failure      409      {object}  map[string]string  "Email already in use"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /me/email/confirm [post]
+func (h *MeHandler) ConfirmEmailChange(c fiber.Ctx) error {
+	return h.confirmContactChange(c, "email")
+}
+
+// ConfirmPhoneChange godoc
+// @Summary      Confirm phone change
+// @Description  Switch to the new phone number with the code that was sent to it
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      ConfirmContactChangePayload  true  "Verification code"
+// @Success      200      {object}  MeResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body or code"
+// @Failure      401      {object}  map[string]string  "Authentication required"
+// @Failure      409      {object}  map[string]string  "Phone already in use"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /me/phone/confirm [post]
+func (h *MeHandler) ConfirmPhoneChange(c fiber.Ctx) error {
+	return h.confirmContactChange(c, "phone")
+}
+
+func (h *MeHandler) requestContactChange(c fiber.Ctx, param usecases.RequestContactChangeParam) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	param.UserID = userID
+
+	ctx := c.Context()
+	err = h.requestContactChangeUsecase.Execute(ctx, param)
+	if err != nil {
+		var retryErr *usecases.RetryAfterError
+		if errors.As(err, &retryErr) {
+			return tooManyRequests(c, retryErr)
+		}
+		if isValidationError(err) || isRequiredError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidCredentialsError(err) {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid credentials")
+		}
+		if isConflictError(err) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		if isChannelUnavailableError(err) {
+			return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to send verification code")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(MeMessageResponse{
+		Message: "a verification code has been sent to the new contact.",
+	})
+}
+
+func (h *MeHandler) confirmContactChange(c fiber.Ctx, channel string) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	var request ConfirmContactChangePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	result, err := h.confirmContactChangeUsecase.Execute(ctx, usecases.ConfirmContactChangeParam{
+		UserID:  userID,
+		Channel: channel,
+		Code:    request.Code,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if err.Error() == "invalid or expired verification code" {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if isConflictError(err) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to change contact")
+	}
+
+	return c.JSON(MeResponse{
+		Message: "contact changed successfully.",
+		Data: MeResponseData{
+			User: toUserResponse(result.User),
+		},
+	})
+}
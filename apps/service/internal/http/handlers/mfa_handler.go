@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type MfaHandler struct {
+	enrollTotpUsecase  *usecases.EnrollTotpUsecase
+	confirmTotpUsecase *usecases.ConfirmTotpUsecase
+	disableTotpUsecase *usecases.DisableTotpUsecase
+	verifyMfaUsecase   *usecases.VerifyMfaUsecase
+}
+
+func NewMfaHandler(enrollTotpUsecase *usecases.EnrollTotpUsecase, confirmTotpUsecase *usecases.ConfirmTotpUsecase, disableTotpUsecase *usecases.DisableTotpUsecase, verifyMfaUsecase *usecases.VerifyMfaUsecase) *MfaHandler {
+	return &MfaHandler{
+		enrollTotpUsecase:  enrollTotpUsecase,
+		confirmTotpUsecase: confirmTotpUsecase,
+		disableTotpUsecase: disableTotpUsecase,
+		verifyMfaUsecase:   verifyMfaUsecase,
+	}
+}
+
+type ConfirmTotpPayload struct {
+	Code string `json:"code" example:"123456" binding:"required"` // Current code from the authenticator app
+}
+
+type DisableTotpPayload struct {
+	Password string `json:"password" example:"password123" binding:"required"` // Current password
+	Code     string `json:"code" example:"123456" binding:"required"`          // Current code from the authenticator app
+}
+
+type VerifyMfaPayload struct {
+	MfaToken     string `json:"mfa_token" example:"eyJhbGciOiJIUzI1NiIs..." binding:"required"` // Challenge token returned by login
+	Code         string `json:"code,omitempty" example:"123456"`                                // Current code from the authenticator app
+	RecoveryCode string `json:"recovery_code,omitempty" example:"abcde-23456"`                  // One of the recovery codes, instead of a code
+}
+
+type EnrollTotpResponse struct {
+	Message string                 `json:"message"`
+	Data    EnrollTotpResponseData `json:"data"`
+}
+
+type EnrollTotpResponseData struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	URI    string `json:"otpauth_uri" example:"otpauth://totp/Weiss:john@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Weiss"`
+}
+
+type ConfirmTotpResponse struct {
+	Message string                  `json:"message"`
+	Data    ConfirmTotpResponseData `json:"data"`
+}
+
+type ConfirmTotpResponseData struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MfaResponse struct {
+	Message string `json:"message"`
+}
+
+// EnrollTotp godoc
+// @Summary      Start TOTP enrollment
+// @Description  Generate a new authenticator secret and otpauth URI for a QR code. Two-factor authentication is enabled once the enrollment is confirmed.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  EnrollTotpResponse
+// @Failure      401  {object}  map[string]string  "Authentication required"
+// @Failure      409  {object}  map[string]string  "Two-factor authentication already enabled"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /auth/mfa/totp/enroll [post]
+func (h *MfaHandler) EnrollTotp(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Context()
+	result, err := h.enrollTotpUsecase.Execute(ctx, usecases.EnrollTotpParam{UserID: userID})
+	if err != nil {
+		if isMfaStateError(err) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to start two-factor enrollment")
+	}
+
+	return c.JSON(EnrollTotpResponse{
+		Message: "two-factor enrollment started successfully.",
+		Data: EnrollTotpResponseData{
+			Secret: result.Secret,
+			URI:    result.URI,
+		},
+	})
+}
+
+// ConfirmTotp godoc
+// @Summary      Confirm TOTP enrollment
+// @Description  Enable two-factor authentication with a code from the authenticator app. The recovery codes are only shown in this response.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      ConfirmTotpPayload  true  "Authenticator code"
+// @Success      200      {object}  ConfirmTotpResponse
+// @Failure      400      {object}  map[string]string  "Invalid code"
+// @Failure      401      {object}  map[string]string  "Authentication required"
+// @Failure      409      {object}  map[string]string  "Enrollment not started or already enabled"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/mfa/totp/confirm [post]
+func (h *MfaHandler) ConfirmTotp(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	var request ConfirmTotpPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	result, err := h.confirmTotpUsecase.Execute(ctx, usecases.ConfirmTotpParam{
+		UserID: userID,
+		Code:   request.Code,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidMfaCodeError(err) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if isMfaStateError(err) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to confirm two-factor enrollment")
+	}
+
+	return c.JSON(ConfirmTotpResponse{
+		Message: "two-factor authentication enabled successfully.",
+		Data: ConfirmTotpResponseData{
+			RecoveryCodes: result.RecoveryCodes,
+		},
+	})
+}
+
+// DisableTotp godoc
+// @Summary      Disable TOTP
+// @Description  Turn two-factor authentication off and discard the recovery codes. Requires the password and a current code.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      DisableTotpPayload  true  "Password and authenticator code"
+// @Success      200      {object}  MfaResponse
+// @Failure      400      {object}  map[string]string  "Invalid code"
+// @Failure      401      {object}  map[string]string  "Invalid credentials"
+// @Failure      409      {object}  map[string]string  "Two-factor authentication not enabled"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/mfa/totp/disable [post]
+func (h *MfaHandler) DisableTotp(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	var request DisableTotpPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	err = h.disableTotpUsecase.Execute(ctx, usecases.DisableTotpParam{
+		UserID:   userID,
+		Password: request.Password,
+		Code:     request.Code,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidCredentialsError(err) {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid credentials")
+		}
+		if isInvalidMfaCodeError(err) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if isMfaStateError(err) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to disable two-factor authentication")
+	}
+
+	return c.JSON(MfaResponse{
+		Message: "two-factor authentication disabled successfully.",
+	})
+}
+
+// VerifyMfa godoc
+// @Summary      Complete a two-factor login
+// @Description  Exchange the MFA token returned by login together with an authenticator code or a recovery code for JWT tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      VerifyMfaPayload  true  "MFA token and code"
+// @Success      200      {object}  LoginResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Invalid MFA token or code"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      429      {object}  map[string]string  "Too many failed attempts"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/mfa/verify [post]
+func (h *MfaHandler) VerifyMfa(c fiber.Ctx) error {
+	var request VerifyMfaPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	result, err := h.verifyMfaUsecase.Execute(ctx, usecases.VerifyMfaParam{
+		MfaToken:     request.MfaToken,
+		Code:         request.Code,
+		RecoveryCode: request.RecoveryCode,
+		UserAgent:    c.Get(fiber.HeaderUserAgent),
+		IPAddress:    c.IP(),
+	})
+	if err != nil {
+		var retryErr *usecases.RetryAfterError
+		if errors.As(err, &retryErr) {
+			return tooManyRequests(c, retryErr)
+		}
+		if isValidationError(err) || isRequiredError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidMfaCodeError(err) || isInvalidMfaTokenError(err) {
+			return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to verify two-factor code")
+	}
+
+	return c.JSON(LoginResponse{
+		Message: "authorized.",
+		Data: LoginResponseData{
+			AccessToken:  result.AccessToken,
+			RefreshToken: result.RefreshToken,
+		},
+	})
+}
+
+func isInvalidMfaCodeError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "invalid two-factor code")
+}
+
+func isInvalidMfaTokenError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "invalid or expired MFA token")
+}
+
+func isMfaStateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "two-factor authentication is already enabled") ||
+		strings.Contains(msg, "two-factor authentication is not enabled") ||
+		strings.Contains(msg, "two-factor enrollment not started")
+}
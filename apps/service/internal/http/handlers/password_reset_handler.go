@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type PasswordResetHandler struct {
+	forgotPasswordUsecase *usecases.ForgotPasswordUsecase
+	resetPasswordUsecase  *usecases.ResetPasswordUsecase
+}
+
+func NewPasswordResetHandler(forgotPasswordUsecase *usecases.ForgotPasswordUsecase, resetPasswordUsecase *usecases.ResetPasswordUsecase) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		forgotPasswordUsecase: forgotPasswordUsecase,
+		resetPasswordUsecase:  resetPasswordUsecase,
+	}
+}
+
+type ForgotPasswordPayload struct {
+	Email string `json:"email" example:"john@example.com" binding:"required,email"` // Email address of the account
+}
+
+type ResetPasswordPayload struct {
+	Token    string `json:"token" example:"q9Vb1k..." binding:"required"`               // Token from the reset email
+	Password string `json:"password" example:"newpassword123" binding:"required,min=6"` // New password (minimum 6 characters)
+}
+
+type PasswordResetResponse struct {
+	Message string `json:"message"`
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Email a single-use password reset link. The response is the same whether or not the email is registered.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ForgotPasswordPayload  true  "Account email"
+// @Success      202      {object}  PasswordResetResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/password/forgot [post]
+func (h *PasswordResetHandler) ForgotPassword(c fiber.Ctx) error {
+	var request ForgotPasswordPayload
+
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	err := h.forgotPasswordUsecase.Execute(ctx, usecases.ForgotPasswordParam{
+		Email: request.Email,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to process password reset request")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(PasswordResetResponse{
+		Message: "if the email is registered, a password reset link has been sent.",
+	})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password using a reset token. Every active session of the user is terminated.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ResetPasswordPayload  true  "Reset token and new password"
+// @Success      200      {object}  PasswordResetResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body or token"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/password/reset [post]
+func (h *PasswordResetHandler) ResetPassword(c fiber.Ctx) error {
+	var request ResetPasswordPayload
+
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	err := h.resetPasswordUsecase.Execute(ctx, usecases.ResetPasswordParam{
+		Token:    request.Token,
+		Password: request.Password,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if err.Error() == "invalid or expired reset token" {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to reset password")
+	}
+
+	return c.JSON(PasswordResetResponse{
+		Message: "password reset successfully.",
+	})
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	catalogentities "github.com/reno1r/weiss/apps/service/internal/app/catalog/entities"
+	catalogusecases "github.com/reno1r/weiss/apps/service/internal/app/catalog/usecases"
+)
+
+type ProductHandler struct {
+	listProductsUsecase  *catalogusecases.ListProductsUsecase
+	getProductUsecase    *catalogusecases.GetProductUsecase
+	createProductUsecase *catalogusecases.CreateProductUsecase
+	updateProductUsecase *catalogusecases.UpdateProductUsecase
+	deleteProductUsecase *catalogusecases.DeleteProductUsecase
+}
+
+func NewProductHandler(listProductsUsecase *catalogusecases.ListProductsUsecase, getProductUsecase *catalogusecases.GetProductUsecase, createProductUsecase *catalogusecases.CreateProductUsecase, updateProductUsecase *catalogusecases.UpdateProductUsecase, deleteProductUsecase *catalogusecases.DeleteProductUsecase) *ProductHandler {
+	return &ProductHandler{
+		listProductsUsecase:  listProductsUsecase,
+		getProductUsecase:    getProductUsecase,
+		createProductUsecase: createProductUsecase,
+		updateProductUsecase: updateProductUsecase,
+		deleteProductUsecase: deleteProductUsecase,
+	}
+}
+
+type ProductPayload struct {
+	CategoryID  *uint64                 `json:"category_id,omitempty" example:"2"`              // Category of the product; uncategorized when left out
+	Name        string                  `json:"name" example:"T-Shirt" binding:"required"`      // Product name
+	Description string                  `json:"description" example:"Organic cotton crew neck"` // Product description
+	Options     []ProductOptionDTO      `json:"options"`                                        // Options the variants differ in; none for a single variant
+	Variants    []ProductVariantPayload `json:"variants" binding:"required"`                    // One variant per combination of option values on sale
+}
+
+type ProductVariantPayload struct {
+	ID      uint64            `json:"id,omitempty" example:"1"`                  // Variant to update; a new variant when left out
+	SKU     string            `json:"sku" example:"TS-M-RED" binding:"required"` // Stock keeping unit, unique within the shop
+	Barcode string            `json:"barcode" example:"4006381333931"`           // Barcode, unique within the shop when set
+	Price   int64             `json:"price" example:"1500"`                      // Price in the minor unit of the currency
+	Options map[string]string `json:"options"`                                   // Value of every option of the product
+}
+
+type ProductOptionDTO struct {
+	Name   string   `json:"name" example:"Size"`
+	Values []string `json:"values" example:"S,M,L"`
+}
+
+type ProductVariantDTO struct {
+	ID      uint64            `json:"id" example:"1"`
+	SKU     string            `json:"sku" example:"TS-M-RED"`
+	Barcode string            `json:"barcode" example:"4006381333931"`
+	Price   int64             `json:"price" example:"1500"`
+	Options map[string]string `json:"options"`
+}
+
+type ProductDTO struct {
+	ID          uint64              `json:"id" example:"1"`
+	CategoryID  *uint64             `json:"category_id" example:"2"`
+	Name        string              `json:"name" example:"T-Shirt"`
+	Description string              `json:"description" example:"Organic cotton crew neck"`
+	Options     []ProductOptionDTO  `json:"options"`
+	Variants    []ProductVariantDTO `json:"variants"`
+	CreatedAt   time.Time           `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time           `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type ProductListResponse struct {
+	Message string                  `json:"message"`
+	Data    ProductListResponseData `json:"data"`
+}
+
+type ProductListResponseData struct {
+	Products   []ProductDTO `json:"products"`
+	NextCursor string       `json:"next_cursor,omitempty" example:"eyJzIjoibmFtZSIsInYiOiJUZWUiLCJpZCI6NH0"`
+}
+
+type ProductResponse struct {
+	Message string              `json:"message"`
+	Data    ProductResponseData `json:"data"`
+}
+
+type ProductResponseData struct {
+	Product ProductDTO `json:"product"`
+}
+
+// ListProducts godoc
+// @Summary      List products of a shop
+// @Description  List the products of a shop with their variants, ordered by name, one page at a time. Pass next_cursor from a response as cursor to get the next page.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      int     true   "Shop ID"
+// @Param        q            query     string  false  "Case-insensitive search on the product name, or an exact SKU or barcode"
+// @Param        category_id  query     int     false  "Only list products of this category"
+// @Param        cursor       query     string  false  "Cursor returned by the previous page"
+// @Param        limit        query     int     false  "Page size, at most 100"  default(20)
+// @Success      200          {object}  ProductListResponse
+// @Failure      400          {object}  map[string]string  "Invalid shop id, category_id or limit"
+// @Failure      403          {object}  map[string]string  "Not a member of the shop"
+// @Failure      404          {object}  map[string]string  "Shop not found"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/products [get]
+func (h *ProductHandler) ListProducts(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+
+	var categoryID *uint64
+	if value := c.Query("category_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid category_id")
+		}
+		categoryID = &id
+	}
+
+	result, err := h.listProductsUsecase.Execute(c.Context(), catalogusecases.ListProductsParam{
+		ShopID:     shopID,
+		CategoryID: categoryID,
+		Search:     c.Query("q"),
+		Cursor:     c.Query("cursor"),
+		Limit:      limit,
+	})
+	if err != nil {
+		return catalogError(err, "failed to list products")
+	}
+
+	products := make([]ProductDTO, len(result.Products))
+	for i, product := range result.Products {
+		products[i] = toProductDTO(product)
+	}
+
+	return c.JSON(ProductListResponse{
+		Message: "products retrieved successfully.",
+		Data: ProductListResponseData{
+			Products:   products,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// GetProduct godoc
+// @Summary      Get a product
+// @Description  Get a product of a shop with its variants
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true  "Shop ID"
+// @Param        productId  path      int  true  "Product ID"
+// @Success      200        {object}  ProductResponse
+// @Failure      400        {object}  map[string]string  "Invalid shop or product id"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop"
+// @Failure      404        {object}  map[string]string  "Shop or product not found"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/products/{productId} [get]
+func (h *ProductHandler) GetProduct(c fiber.Ctx) error {
+	shopID, productID, err := parseProductParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getProductUsecase.Execute(c.Context(), shopID, productID)
+	if err != nil {
+		return catalogError(err, "failed to get product")
+	}
+
+	return c.JSON(ProductResponse{
+		Message: "product retrieved successfully.",
+		Data: ProductResponseData{
+			Product: toProductDTO(*result.Product),
+		},
+	})
+}
+
+// CreateProduct godoc
+// @Summary      Create a product
+// @Description  Create a product in a shop with its variants. Every variant picks one value of each option, no two variants pick the same values, and SKUs and barcodes must not be used by another product of the shop. A product without options has a single variant.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int             true  "Shop ID"
+// @Param        request  body      ProductPayload  true  "Product data"
+// @Success      201      {object}  ProductResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop or missing the catalog.manage permission"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "SKU or barcode already exists"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/products [post]
+func (h *ProductHandler) CreateProduct(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request ProductPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.createProductUsecase.Execute(c.Context(), catalogusecases.CreateProductParam{
+		ShopID:      shopID,
+		CategoryID:  request.CategoryID,
+		Name:        request.Name,
+		Description: request.Description,
+		Options:     toProductOptionParams(request.Options),
+		Variants:    toProductVariantParams(request.Variants),
+	})
+	if err != nil {
+		return catalogError(err, "failed to create product")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ProductResponse{
+		Message: "product created successfully.",
+		Data: ProductResponseData{
+			Product: toProductDTO(*result.Product),
+		},
+	})
+}
+
+// UpdateProduct godoc
+// @Summary      Update a product
+// @Description  Replace a product with its options and variants. Variants with an id update that variant, variants without one are added, and variants left out are deleted. The rules of creating a product apply.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int             true  "Shop ID"
+// @Param        productId  path      int             true  "Product ID"
+// @Param        request    body      ProductPayload  true  "Product data"
+// @Success      200        {object}  ProductResponse
+// @Failure      400        {object}  map[string]string  "Invalid shop or product id, or request body"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop or missing the catalog.manage permission"
+// @Failure      404        {object}  map[string]string  "Shop or product not found"
+// @Failure      409        {object}  map[string]string  "SKU or barcode already exists"
+// @Failure      422        {object}  map[string]string  "Validation failed"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/products/{productId} [put]
+func (h *ProductHandler) UpdateProduct(c fiber.Ctx) error {
+	shopID, productID, err := parseProductParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request ProductPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.updateProductUsecase.Execute(c.Context(), catalogusecases.UpdateProductParam{
+		ShopID:      shopID,
+		ProductID:   productID,
+		CategoryID:  request.CategoryID,
+		Name:        request.Name,
+		Description: request.Description,
+		Options:     toProductOptionParams(request.Options),
+		Variants:    toProductVariantParams(request.Variants),
+	})
+	if err != nil {
+		return catalogError(err, "failed to update product")
+	}
+
+	return c.JSON(ProductResponse{
+		Message: "product updated successfully.",
+		Data: ProductResponseData{
+			Product: toProductDTO(*result.Product),
+		},
+	})
+}
+
+// DeleteProduct godoc
+// @Summary      Delete a product
+// @Description  Delete a product of a shop with its variants. Their SKUs and barcodes can be used again afterwards.
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  int  true  "Shop ID"
+// @Param        productId  path  int  true  "Product ID"
+// @Success      204        "No Content"
+// @Failure      400        {object}  map[string]string  "Invalid shop or product id"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop or missing the catalog.manage permission"
+// @Failure      404        {object}  map[string]string  "Shop or product not found"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/products/{productId} [delete]
+func (h *ProductHandler) DeleteProduct(c fiber.Ctx) error {
+	shopID, productID, err := parseProductParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.deleteProductUsecase.Execute(c.Context(), shopID, productID); err != nil {
+		return catalogError(err, "failed to delete product")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func parseProductParams(c fiber.Ctx) (uint64, uint64, error) {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	productID, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid product id")
+	}
+
+	return shopID, productID, nil
+}
+
+func toProductOptionParams(options []ProductOptionDTO) []catalogusecases.ProductOptionParam {
+	params := make([]catalogusecases.ProductOptionParam, len(options))
+	for i, option := range options {
+		params[i] = catalogusecases.ProductOptionParam{
+			Name:   option.Name,
+			Values: option.Values,
+		}
+	}
+	return params
+}
+
+func toProductVariantParams(variants []ProductVariantPayload) []catalogusecases.ProductVariantParam {
+	params := make([]catalogusecases.ProductVariantParam, len(variants))
+	for i, variant := range variants {
+		params[i] = catalogusecases.ProductVariantParam{
+			ID:      variant.ID,
+			SKU:     variant.SKU,
+			Barcode: variant.Barcode,
+			Price:   variant.Price,
+			Options: variant.Options,
+		}
+	}
+	return params
+}
+
+func toProductDTO(product catalogentities.Product) ProductDTO {
+	options := make([]ProductOptionDTO, len(product.Options))
+	for i, option := range product.Options {
+		options[i] = ProductOptionDTO{
+			Name:   option.Name,
+			Values: option.Values,
+		}
+	}
+
+	variants := make([]ProductVariantDTO, len(product.Variants))
+	for i, variant := range product.Variants {
+		variants[i] = ProductVariantDTO{
+			ID:      variant.ID,
+			SKU:     variant.SKU,
+			Barcode: variant.Barcode,
+			Price:   variant.Price,
+			Options: variant.Options,
+		}
+	}
+
+	return ProductDTO{
+		ID:          product.ID,
+		CategoryID:  product.CategoryID,
+		Name:        product.Name,
+		Description: product.Description,
+		Options:     options,
+		Variants:    variants,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+	}
+}
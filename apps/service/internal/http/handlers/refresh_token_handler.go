@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type RefreshTokenHandler struct {
+	refreshTokenUsecase *usecases.RefreshTokenUsecase
+}
+
+func NewRefreshTokenHandler(refreshTokenUsecase *usecases.RefreshTokenUsecase) *RefreshTokenHandler {
+	return &RefreshTokenHandler{
+		refreshTokenUsecase: refreshTokenUsecase,
+	}
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." binding:"required"` // Refresh token issued at login
+}
+
+type RefreshTokenResponse struct {
+	Message string                   `json:"message"`
+	Data    RefreshTokenResponseData `json:"data"`
+}
+
+type RefreshTokenResponseData struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh godoc
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new access and refresh token pair. Refresh tokens are single-use; presenting one that was already exchanged revokes every token of that login.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RefreshTokenPayload  true  "Refresh token"
+// @Success      200      {object}  RefreshTokenResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      401      {object}  map[string]string  "Invalid or reused refresh token"
+// @Failure      403      {object}  map[string]string  "Account suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/refresh [post]
+func (h *RefreshTokenHandler) Handle(c fiber.Ctx) error {
+	var request RefreshTokenPayload
+
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	result, err := h.refreshTokenUsecase.Execute(ctx, usecases.RefreshTokenParam{
+		RefreshToken: request.RefreshToken,
+		UserAgent:    c.Get(fiber.HeaderUserAgent),
+		IPAddress:    c.IP(),
+	})
+	if err != nil {
+		if isRequiredError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isInvalidRefreshTokenError(err) {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid refresh token")
+		}
+		if isSuspendedAccountError(err) {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to refresh tokens")
+	}
+
+	return c.JSON(RefreshTokenResponse{
+		Message: "tokens refreshed.",
+		Data: RefreshTokenResponseData{
+			AccessToken:  result.AccessToken,
+			RefreshToken: result.RefreshToken,
+		},
+	})
+}
+
+func isRequiredError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "is required")
+}
+
+func isInvalidRefreshTokenError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	return strings.Contains(message, "failed to verify refresh token") ||
+		strings.Contains(message, "invalid refresh token") ||
+		strings.Contains(message, "refresh token reuse detected") ||
+		strings.Contains(message, "invalid token claims") ||
+		strings.Contains(message, "user not found")
+}
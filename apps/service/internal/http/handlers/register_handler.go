@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+	"github.com/reno1r/weiss/apps/service/internal/app/user/entities"
 )
 
 type RegisterHandler struct {
@@ -35,12 +36,14 @@ type RegisterResponseData struct {
 }
 
 type UserResponse struct {
-	ID        uint64    `json:"id" example:"1"`
-	FullName  string    `json:"full_name" example:"John Doe"`
-	Phone     string    `json:"phone" example:"1234567890"`
-	Email     string    `json:"email" example:"john@example.com"`
-	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ID              uint64     `json:"id" example:"1"`
+	FullName        string     `json:"full_name" example:"John Doe"`
+	Phone           string     `json:"phone" example:"1234567890"`
+	Email           string     `json:"email" example:"john@example.com"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at" example:"2024-01-01T00:00:00Z"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at" example:"2024-01-01T00:00:00Z"`
+	CreatedAt       time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
 
 // Register godoc
@@ -83,23 +86,27 @@ func (h *RegisterHandler) Handle(c fiber.Ctx) error {
 
 	result.User.Password = ""
 
-	userResponse := &UserResponse{
-		ID:        result.User.ID,
-		FullName:  result.User.FullName,
-		Phone:     result.User.Phone,
-		Email:     result.User.Email,
-		CreatedAt: result.User.CreatedAt,
-		UpdatedAt: result.User.UpdatedAt,
-	}
-
 	return c.Status(fiber.StatusCreated).JSON(RegisterResponse{
 		Message: "user created successfully.",
 		Data: RegisterResponseData{
-			User: userResponse,
+			User: toUserResponse(result.User),
 		},
 	})
 }
 
+func toUserResponse(user *entities.User) *UserResponse {
+	return &UserResponse{
+		ID:              user.ID,
+		FullName:        user.FullName,
+		Phone:           user.Phone,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+}
+
 func isConflictError(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "already exists")
 }
@@ -0,0 +1,509 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	salesentities "github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	salesusecases "github.com/reno1r/weiss/apps/service/internal/app/sales/usecases"
+)
+
+type RegisterSessionHandler struct {
+	openRegisterSessionUsecase  *salesusecases.OpenRegisterSessionUsecase
+	listRegisterSessionsUsecase *salesusecases.ListRegisterSessionsUsecase
+	getRegisterSessionUsecase   *salesusecases.GetRegisterSessionUsecase
+	recordCashMovementUsecase   *salesusecases.RecordCashMovementUsecase
+	closeRegisterSessionUsecase *salesusecases.CloseRegisterSessionUsecase
+	getRegisterReportUsecase    *salesusecases.GetRegisterReportUsecase
+}
+
+func NewRegisterSessionHandler(openRegisterSessionUsecase *salesusecases.OpenRegisterSessionUsecase, listRegisterSessionsUsecase *salesusecases.ListRegisterSessionsUsecase, getRegisterSessionUsecase *salesusecases.GetRegisterSessionUsecase, recordCashMovementUsecase *salesusecases.RecordCashMovementUsecase, closeRegisterSessionUsecase *salesusecases.CloseRegisterSessionUsecase, getRegisterReportUsecase *salesusecases.GetRegisterReportUsecase) *RegisterSessionHandler {
+	return &RegisterSessionHandler{
+		openRegisterSessionUsecase:  openRegisterSessionUsecase,
+		listRegisterSessionsUsecase: listRegisterSessionsUsecase,
+		getRegisterSessionUsecase:   getRegisterSessionUsecase,
+		recordCashMovementUsecase:   recordCashMovementUsecase,
+		closeRegisterSessionUsecase: closeRegisterSessionUsecase,
+		getRegisterReportUsecase:    getRegisterReportUsecase,
+	}
+}
+
+type OpenRegisterSessionPayload struct {
+	LocationID   uint64 `json:"location_id" example:"1" binding:"required"` // Outlet whose register is opened
+	OpeningFloat int64  `json:"opening_float" example:"10000"`              // Cash counted into the drawer
+}
+
+type CashMovementPayload struct {
+	Type   string `json:"type" example:"out" enums:"in,out" binding:"required"`
+	Amount int64  `json:"amount" example:"700" binding:"required"`
+	Reason string `json:"reason" example:"Window cleaner" binding:"required"`
+}
+
+type CloseRegisterSessionPayload struct {
+	CountedCash int64  `json:"counted_cash" example:"11000"` // Cash counted in the drawer at close
+	Note        string `json:"note" example:"Short by a coin roll"`
+}
+
+type CashMovementDTO struct {
+	ID        uint64    `json:"id" example:"1"`
+	Type      string    `json:"type" example:"out"`
+	Amount    int64     `json:"amount" example:"700"`
+	Reason    string    `json:"reason" example:"Window cleaner"`
+	StaffID   uint64    `json:"staff_id" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type RegisterSessionDTO struct {
+	ID              uint64            `json:"id" example:"1"`
+	LocationID      uint64            `json:"location_id" example:"1"`
+	Status          string            `json:"status" example:"closed"`
+	OpeningFloat    int64             `json:"opening_float" example:"10000"`
+	OpenedByStaffID uint64            `json:"opened_by_staff_id" example:"1"`
+	OpenedAt        time.Time         `json:"opened_at" example:"2024-01-01T08:00:00Z"`
+	ClosedByStaffID *uint64           `json:"closed_by_staff_id" example:"2"`
+	ClosedAt        *time.Time        `json:"closed_at" example:"2024-01-01T20:00:00Z"`
+	ExpectedCash    int64             `json:"expected_cash" example:"11300"`
+	CountedCash     int64             `json:"counted_cash" example:"11000"`
+	Variance        int64             `json:"variance" example:"-300"` // Counted less expected; negative when the drawer is short
+	Note            string            `json:"note" example:"Short by a coin roll"`
+	CashMovements   []CashMovementDTO `json:"cash_movements"`
+}
+
+type PaymentTotalDTO struct {
+	Method string `json:"method" example:"cash"`
+	Count  int64  `json:"count" example:"2"`
+	Amount int64  `json:"amount" example:"2500"`
+}
+
+type RegisterReportDTO struct {
+	SalesCount    int64             `json:"sales_count" example:"3"`
+	Subtotal      int64             `json:"subtotal" example:"6000"`
+	DiscountTotal int64             `json:"discount_total" example:"0"`
+	TaxTotal      int64             `json:"tax_total" example:"0"`
+	Total         int64             `json:"total" example:"6000"`
+	Payments      []PaymentTotalDTO `json:"payments"` // One entry per payment method, used or not
+	ChangeGiven   int64             `json:"change_given" example:"500"`
+	OpeningFloat  int64             `json:"opening_float" example:"10000"`
+	CashIn        int64             `json:"cash_in" example:"0"`
+	CashOut       int64             `json:"cash_out" example:"700"`
+	ExpectedCash  int64             `json:"expected_cash" example:"11300"`
+	CountedCash   *int64            `json:"counted_cash" example:"11000"` // Set once the session is closed
+	Variance      *int64            `json:"variance" example:"-300"`      // Set once the session is closed
+}
+
+type RegisterSessionResponse struct {
+	Message string                      `json:"message"`
+	Data    RegisterSessionResponseData `json:"data"`
+}
+
+type RegisterSessionResponseData struct {
+	Session RegisterSessionDTO `json:"session"`
+}
+
+type RegisterSessionListResponse struct {
+	Message string                          `json:"message"`
+	Data    RegisterSessionListResponseData `json:"data"`
+}
+
+type RegisterSessionListResponseData struct {
+	Sessions   []RegisterSessionDTO `json:"sessions"`
+	NextCursor string               `json:"next_cursor,omitempty" example:"eyJzIjoicmVjZW50IiwidiI6IiIsImlkIjoxfQ"`
+}
+
+type RegisterReportResponse struct {
+	Message string                     `json:"message"`
+	Data    RegisterReportResponseData `json:"data"`
+}
+
+type RegisterReportResponseData struct {
+	Session RegisterSessionDTO `json:"session"`
+	Report  RegisterReportDTO  `json:"report"`
+}
+
+// OpenRegisterSession godoc
+// @Summary      Open a register
+// @Description  Open the register of an outlet of a shop for a shift, with the cash counted into the drawer. Sales made at the outlet while the session is open belong to it. An outlet has one open session at a time.
+// @Tags         registers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                         true  "Shop ID"
+// @Param        request  body      OpenRegisterSessionPayload  true  "Session data"
+// @Success      201      {object}  RegisterSessionResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the location"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Register already open"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/register-sessions [post]
+func (h *RegisterSessionHandler) OpenRegisterSession(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request OpenRegisterSessionPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.openRegisterSessionUsecase.Execute(c.Context(), salesusecases.OpenRegisterSessionParam{
+		ShopID:             shopID,
+		StaffID:            membership.ID,
+		LocationID:         request.LocationID,
+		OpeningFloat:       request.OpeningFloat,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to open register session")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(RegisterSessionResponse{
+		Message: "register session opened successfully.",
+		Data: RegisterSessionResponseData{
+			Session: toRegisterSessionDTO(*result.Session),
+		},
+	})
+}
+
+// ListRegisterSessions godoc
+// @Summary      List register sessions
+// @Description  List the register sessions of a shop, newest first, one page at a time. Staff limited to some locations only see sessions at those. Pass next_cursor from a response as cursor to get the next page.
+// @Tags         registers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      int     true   "Shop ID"
+// @Param        location_id  query     int     false  "Only list sessions at this location"
+// @Param        status       query     string  false  "Only list sessions with this status"  Enums(open, closed)
+// @Param        cursor       query     string  false  "Cursor returned by the previous page"
+// @Param        limit        query     int     false  "Page size, at most 100"  default(20)
+// @Success      200          {object}  RegisterSessionListResponse
+// @Failure      400          {object}  map[string]string  "Invalid shop id, location_id or limit"
+// @Failure      403          {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the location"
+// @Failure      404          {object}  map[string]string  "Shop not found"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/register-sessions [get]
+func (h *RegisterSessionHandler) ListRegisterSessions(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+	locationID, err := parseOptionalID(c, "location_id")
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.listRegisterSessionsUsecase.Execute(c.Context(), salesusecases.ListRegisterSessionsParam{
+		ShopID:             shopID,
+		LocationID:         locationID,
+		Status:             c.Query("status"),
+		Cursor:             c.Query("cursor"),
+		Limit:              limit,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to list register sessions")
+	}
+
+	sessions := make([]RegisterSessionDTO, len(result.Sessions))
+	for i, session := range result.Sessions {
+		sessions[i] = toRegisterSessionDTO(session)
+	}
+
+	return c.JSON(RegisterSessionListResponse{
+		Message: "register sessions retrieved successfully.",
+		Data: RegisterSessionListResponseData{
+			Sessions:   sessions,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// GetRegisterSession godoc
+// @Summary      Get a register session
+// @Description  Get a register session of a shop with its cash movements
+// @Tags         registers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true  "Shop ID"
+// @Param        sessionId  path      int  true  "Register session ID"
+// @Success      200        {object}  RegisterSessionResponse
+// @Failure      400        {object}  map[string]string  "Invalid shop or session id"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the session's location"
+// @Failure      404        {object}  map[string]string  "Shop or session not found"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/register-sessions/{sessionId} [get]
+func (h *RegisterSessionHandler) GetRegisterSession(c fiber.Ctx) error {
+	shopID, sessionID, err := parseRegisterSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getRegisterSessionUsecase.Execute(c.Context(), salesusecases.GetRegisterSessionParam{
+		ShopID:             shopID,
+		SessionID:          sessionID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to get register session")
+	}
+
+	return c.JSON(RegisterSessionResponse{
+		Message: "register session retrieved successfully.",
+		Data: RegisterSessionResponseData{
+			Session: toRegisterSessionDTO(*result.Session),
+		},
+	})
+}
+
+// RecordCashMovement godoc
+// @Summary      Record cash in or out of a register
+// @Description  Record cash put into or taken out of the drawer of an open register session other than through a sale, such as change from the bank or a payout
+// @Tags         registers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int                  true  "Shop ID"
+// @Param        sessionId  path      int                  true  "Register session ID"
+// @Param        request    body      CashMovementPayload  true  "Cash movement"
+// @Success      201        {object}  RegisterSessionResponse
+// @Failure      400        {object}  map[string]string  "Invalid shop or session id, or request body"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the session's location"
+// @Failure      404        {object}  map[string]string  "Shop or session not found"
+// @Failure      409        {object}  map[string]string  "Session already closed"
+// @Failure      422        {object}  map[string]string  "Validation failed"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/register-sessions/{sessionId}/cash-movements [post]
+func (h *RegisterSessionHandler) RecordCashMovement(c fiber.Ctx) error {
+	shopID, sessionID, err := parseRegisterSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request CashMovementPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.recordCashMovementUsecase.Execute(c.Context(), salesusecases.RecordCashMovementParam{
+		ShopID:             shopID,
+		StaffID:            membership.ID,
+		SessionID:          sessionID,
+		Type:               salesentities.CashMovementType(request.Type),
+		Amount:             request.Amount,
+		Reason:             request.Reason,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to record cash movement")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(RegisterSessionResponse{
+		Message: "cash movement recorded successfully.",
+		Data: RegisterSessionResponseData{
+			Session: toRegisterSessionDTO(*result.Session),
+		},
+	})
+}
+
+// CloseRegisterSession godoc
+// @Summary      Close a register
+// @Description  Close an open register session with the cash counted in the drawer. The session records the cash the drawer should hold, from the opening float, cash sales net of change and cash movements, and the variance of the count against it.
+// @Tags         registers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int                          true  "Shop ID"
+// @Param        sessionId  path      int                          true  "Register session ID"
+// @Param        request    body      CloseRegisterSessionPayload  true  "Count"
+// @Success      200        {object}  RegisterSessionResponse
+// @Failure      400        {object}  map[string]string  "Invalid shop or session id, or request body"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the session's location"
+// @Failure      404        {object}  map[string]string  "Shop or session not found"
+// @Failure      409        {object}  map[string]string  "Session already closed"
+// @Failure      422        {object}  map[string]string  "Validation failed"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/register-sessions/{sessionId}/close [post]
+func (h *RegisterSessionHandler) CloseRegisterSession(c fiber.Ctx) error {
+	shopID, sessionID, err := parseRegisterSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	var request CloseRegisterSessionPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.closeRegisterSessionUsecase.Execute(c.Context(), salesusecases.CloseRegisterSessionParam{
+		ShopID:             shopID,
+		StaffID:            membership.ID,
+		SessionID:          sessionID,
+		CountedCash:        request.CountedCash,
+		Note:               request.Note,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to close register session")
+	}
+
+	return c.JSON(RegisterSessionResponse{
+		Message: "register session closed successfully.",
+		Data: RegisterSessionResponseData{
+			Session: toRegisterSessionDTO(*result.Session),
+		},
+	})
+}
+
+// GetRegisterReport godoc
+// @Summary      Get the report of a register session
+// @Description  Sum up the sales of a register session by payment method, with its cash movements and the cash the drawer should hold. For a closed session this is its Z-report, with the counted cash and variance; for an open one it runs up to now.
+// @Tags         registers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true  "Shop ID"
+// @Param        sessionId  path      int  true  "Register session ID"
+// @Success      200        {object}  RegisterReportResponse
+// @Failure      400        {object}  map[string]string  "Invalid shop or session id"
+// @Failure      403        {object}  map[string]string  "Not a member of the shop, missing the reports.view permission or not working at the session's location"
+// @Failure      404        {object}  map[string]string  "Shop or session not found"
+// @Failure      500        {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/register-sessions/{sessionId}/report [get]
+func (h *RegisterSessionHandler) GetRegisterReport(c fiber.Ctx) error {
+	shopID, sessionID, err := parseRegisterSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getRegisterReportUsecase.Execute(c.Context(), salesusecases.GetRegisterReportParam{
+		ShopID:             shopID,
+		SessionID:          sessionID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to get register report")
+	}
+
+	return c.JSON(RegisterReportResponse{
+		Message: "register report retrieved successfully.",
+		Data: RegisterReportResponseData{
+			Session: toRegisterSessionDTO(*result.Session),
+			Report:  toRegisterReportDTO(*result.Session, *result.Report),
+		},
+	})
+}
+
+func parseRegisterSessionParams(c fiber.Ctx) (uint64, uint64, error) {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	sessionID, err := strconv.ParseUint(c.Params("sessionId"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid session id")
+	}
+
+	return shopID, sessionID, nil
+}
+
+func toRegisterSessionDTO(session salesentities.RegisterSession) RegisterSessionDTO {
+	movements := make([]CashMovementDTO, len(session.CashMovements))
+	for i, movement := range session.CashMovements {
+		movements[i] = CashMovementDTO{
+			ID:        movement.ID,
+			Type:      string(movement.Type),
+			Amount:    movement.Amount,
+			Reason:    movement.Reason,
+			StaffID:   movement.StaffID,
+			CreatedAt: movement.CreatedAt,
+		}
+	}
+
+	return RegisterSessionDTO{
+		ID:              session.ID,
+		LocationID:      session.LocationID,
+		Status:          session.Status,
+		OpeningFloat:    session.OpeningFloat,
+		OpenedByStaffID: session.OpenedByStaffID,
+		OpenedAt:        session.OpenedAt,
+		ClosedByStaffID: session.ClosedByStaffID,
+		ClosedAt:        session.ClosedAt,
+		ExpectedCash:    session.ExpectedCash,
+		CountedCash:     session.CountedCash,
+		Variance:        session.Variance,
+		Note:            session.Note,
+		CashMovements:   movements,
+	}
+}
+
+func toRegisterReportDTO(session salesentities.RegisterSession, report salesentities.RegisterReport) RegisterReportDTO {
+	payments := make([]PaymentTotalDTO, len(report.Payments))
+	for i, payment := range report.Payments {
+		payments[i] = PaymentTotalDTO{
+			Method: string(payment.Method),
+			Count:  payment.Count,
+			Amount: payment.Amount,
+		}
+	}
+
+	dto := RegisterReportDTO{
+		SalesCount:    report.Sales.Count,
+		Subtotal:      report.Sales.Subtotal,
+		DiscountTotal: report.Sales.DiscountTotal,
+		TaxTotal:      report.Sales.TaxTotal,
+		Total:         report.Sales.Total,
+		Payments:      payments,
+		ChangeGiven:   report.Sales.Change,
+		OpeningFloat:  session.OpeningFloat,
+		CashIn:        report.CashIn,
+		CashOut:       report.CashOut,
+		ExpectedCash:  report.ExpectedCash,
+	}
+	if !session.IsOpen() {
+		dto.CountedCash = &session.CountedCash
+		dto.Variance = &session.Variance
+	}
+	return dto
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+)
+
+type RoleHandler struct {
+	listRolesUsecase   *accessusecases.ListRolesUsecase
+	getShopRoleUsecase *accessusecases.GetShopRoleUsecase
+	createRoleUsecase  *accessusecases.CreateRoleUsecase
+	updateRoleUsecase  *accessusecases.UpdateRoleUsecase
+	deleteRoleUsecase  *accessusecases.DeleteRoleUsecase
+}
+
+func NewRoleHandler(listRolesUsecase *accessusecases.ListRolesUsecase, getShopRoleUsecase *accessusecases.GetShopRoleUsecase, createRoleUsecase *accessusecases.CreateRoleUsecase, updateRoleUsecase *accessusecases.UpdateRoleUsecase, deleteRoleUsecase *accessusecases.DeleteRoleUsecase) *RoleHandler {
+	return &RoleHandler{
+		listRolesUsecase:   listRolesUsecase,
+		getShopRoleUsecase: getShopRoleUsecase,
+		createRoleUsecase:  createRoleUsecase,
+		updateRoleUsecase:  updateRoleUsecase,
+		deleteRoleUsecase:  deleteRoleUsecase,
+	}
+}
+
+type RolePayload struct {
+	Name        string   `json:"name" example:"Supervisor" binding:"required"`    // Role name, unique within the shop
+	Description string   `json:"description" example:"Looks after the floor"`     // Role description
+	Permissions []string `json:"permissions" example:"sales.create,reports.view"` // Permissions granted by the role
+}
+
+type RoleDetailDTO struct {
+	ID          uint64   `json:"id" example:"1"`
+	Name        string   `json:"name" example:"Supervisor"`
+	Description string   `json:"description" example:"Looks after the floor"`
+	IsSystem    bool     `json:"is_system" example:"false"`
+	Permissions []string `json:"permissions" example:"sales.create,reports.view"`
+	StaffCount  int      `json:"staff_count" example:"2"`
+}
+
+type RoleListResponse struct {
+	Message string               `json:"message"`
+	Data    RoleListResponseData `json:"data"`
+}
+
+type RoleListResponseData struct {
+	Roles []RoleDetailDTO `json:"roles"`
+}
+
+type RoleResponse struct {
+	Message string           `json:"message"`
+	Data    RoleResponseData `json:"data"`
+}
+
+type RoleResponseData struct {
+	Role RoleDetailDTO `json:"role"`
+}
+
+// ListRoles godoc
+// @Summary      List roles of a shop
+// @Description  List every role of a shop with its permissions and the number of staff members holding it
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Shop ID"
+// @Success      200  {object}  RoleListResponse
+// @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop or missing the staff.view permission"
+// @Failure      404  {object}  map[string]string  "Shop not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/roles [get]
+func (h *RoleHandler) ListRoles(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	result, err := h.listRolesUsecase.Execute(c.Context(), shopID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to list roles")
+	}
+
+	roles := make([]RoleDetailDTO, len(result.Roles))
+	for i, role := range result.Roles {
+		roles[i] = toRoleDetailDTO(role)
+	}
+
+	return c.JSON(RoleListResponse{
+		Message: "roles retrieved successfully.",
+		Data: RoleListResponseData{
+			Roles: roles,
+		},
+	})
+}
+
+// GetRole godoc
+// @Summary      Get a role of a shop
+// @Description  Get a role of a shop with its permissions
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Shop ID"
+// @Param        roleId  path      int  true  "Role ID"
+// @Success      200     {object}  RoleResponse
+// @Failure      400     {object}  map[string]string  "Invalid shop or role id"
+// @Failure      403     {object}  map[string]string  "Not a member of the shop or missing the staff.view permission"
+// @Failure      404     {object}  map[string]string  "Shop or role not found"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/roles/{roleId} [get]
+func (h *RoleHandler) GetRole(c fiber.Ctx) error {
+	shopID, roleID, err := parseRoleParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getShopRoleUsecase.Execute(c.Context(), shopID, roleID)
+	if err != nil {
+		return accessError(err, "failed to get role")
+	}
+
+	return c.JSON(RoleResponse{
+		Message: "role retrieved successfully.",
+		Data: RoleResponseData{
+			Role: toRoleDetailDTO(result.Role),
+		},
+	})
+}
+
+// CreateRole godoc
+// @Summary      Create a role
+// @Description  Create a role in a shop. Only permissions the caller holds can be given to the role.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int          true  "Shop ID"
+// @Param        request  body      RolePayload  true  "Role data"
+// @Success      201      {object}  RoleResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the role.manage permission, or granting a permission the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop not found"
+// @Failure      409      {object}  map[string]string  "Role name already exists"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/roles [post]
+func (h *RoleHandler) CreateRole(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request RolePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.createRoleUsecase.Execute(c.Context(), accessusecases.CreateRoleParam{
+		ActorID:     actorID,
+		ShopID:      shopID,
+		Name:        request.Name,
+		Description: request.Description,
+		Permissions: toPermissions(request.Permissions),
+	})
+	if err != nil {
+		return accessError(err, "failed to create role")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(RoleResponse{
+		Message: "role created successfully.",
+		Data: RoleResponseData{
+			Role: toRoleDetailDTO(result.Role),
+		},
+	})
+}
+
+// UpdateRole godoc
+// @Summary      Update a role
+// @Description  Replace the name, description and permissions of a role. System roles cannot be changed, and only permissions the caller holds can be given to the role.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int          true  "Shop ID"
+// @Param        roleId   path      int          true  "Role ID"
+// @Param        request  body      RolePayload  true  "Role data"
+// @Success      200      {object}  RoleResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or role id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the role.manage permission, system role, or granting a permission the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop or role not found"
+// @Failure      409      {object}  map[string]string  "Role name already exists"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/roles/{roleId} [put]
+func (h *RoleHandler) UpdateRole(c fiber.Ctx) error {
+	shopID, roleID, err := parseRoleParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request RolePayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.updateRoleUsecase.Execute(c.Context(), accessusecases.UpdateRoleParam{
+		ActorID:     actorID,
+		ShopID:      shopID,
+		RoleID:      roleID,
+		Name:        request.Name,
+		Description: request.Description,
+		Permissions: toPermissions(request.Permissions),
+	})
+	if err != nil {
+		return accessError(err, "failed to update role")
+	}
+
+	return c.JSON(RoleResponse{
+		Message: "role updated successfully.",
+		Data: RoleResponseData{
+			Role: toRoleDetailDTO(result.Role),
+		},
+	})
+}
+
+// DeleteRole godoc
+// @Summary      Delete a role
+// @Description  Delete a role of a shop. Staff members holding the role must be moved to another role with reassign_to. System roles cannot be deleted.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path   int  true   "Shop ID"
+// @Param        roleId       path   int  true   "Role ID"
+// @Param        reassign_to  query  int  false  "Role to move staff members holding the deleted role to"
+// @Success      204          "No Content"
+// @Failure      400          {object}  map[string]string  "Invalid shop, role or reassign_to id"
+// @Failure      403          {object}  map[string]string  "Not a member of the shop, missing the role.manage permission, or system role"
+// @Failure      404          {object}  map[string]string  "Shop or role not found"
+// @Failure      409          {object}  map[string]string  "Role in use by staff"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/roles/{roleId} [delete]
+func (h *RoleHandler) DeleteRole(c fiber.Ctx) error {
+	shopID, roleID, err := parseRoleParams(c)
+	if err != nil {
+		return err
+	}
+
+	var reassignTo uint64
+	if value := c.Query("reassign_to"); value != "" {
+		reassignTo, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid reassign_to id")
+		}
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	err = h.deleteRoleUsecase.Execute(c.Context(), accessusecases.DeleteRoleParam{
+		ActorID:          actorID,
+		ShopID:           shopID,
+		RoleID:           roleID,
+		ReassignToRoleID: reassignTo,
+	})
+	if err != nil {
+		return accessError(err, "failed to delete role")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func parseRoleParams(c fiber.Ctx) (uint64, uint64, error) {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	roleID, err := strconv.ParseUint(c.Params("roleId"), 10, 64)
+	if err != nil {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+
+	return shopID, roleID, nil
+}
+
+func toRoleDetailDTO(info accessusecases.RoleInfo) RoleDetailDTO {
+	return RoleDetailDTO{
+		ID:          info.Role.ID,
+		Name:        info.Role.Name,
+		Description: info.Role.Description,
+		IsSystem:    info.Role.IsSystem,
+		Permissions: permissionNames(info.Permissions),
+		StaffCount:  info.StaffCount,
+	}
+}
+
+func toPermissions(names []string) []accessentities.Permission {
+	permissions := make([]accessentities.Permission, len(names))
+	for i, name := range names {
+		permissions[i] = accessentities.Permission(name)
+	}
+	return permissions
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	salesentities "github.com/reno1r/weiss/apps/service/internal/app/sales/entities"
+	salesusecases "github.com/reno1r/weiss/apps/service/internal/app/sales/usecases"
+)
+
+type SaleHandler struct {
+	listSalesUsecase *salesusecases.ListSalesUsecase
+	getSaleUsecase   *salesusecases.GetSaleUsecase
+}
+
+func NewSaleHandler(listSalesUsecase *salesusecases.ListSalesUsecase, getSaleUsecase *salesusecases.GetSaleUsecase) *SaleHandler {
+	return &SaleHandler{
+		listSalesUsecase: listSalesUsecase,
+		getSaleUsecase:   getSaleUsecase,
+	}
+}
+
+type SaleLineDTO struct {
+	ID             uint64 `json:"id" example:"1"`
+	VariantID      uint64 `json:"variant_id" example:"1"`
+	SKU            string `json:"sku" example:"TS-M-BLUE"`
+	Name           string `json:"name" example:"T-Shirt (M / Blue)"`
+	UnitPrice      int64  `json:"unit_price" example:"1700"`
+	Quantity       int64  `json:"quantity" example:"1"`
+	DiscountAmount int64  `json:"discount_amount" example:"0"`
+	Total          int64  `json:"total" example:"1700"`
+}
+
+type SalePaymentDTO struct {
+	Method    string `json:"method" example:"cash"`
+	Amount    int64  `json:"amount" example:"2000"`
+	Reference string `json:"reference" example:""`
+}
+
+type SaleDTO struct {
+	ID                uint64           `json:"id" example:"1"`
+	LocationID        uint64           `json:"location_id" example:"1"`
+	CartID            uint64           `json:"cart_id" example:"1"`
+	UserID            uint64           `json:"user_id" example:"1"`
+	RegisterSessionID *uint64          `json:"register_session_id" example:"1"` // Register session open at the location when the sale was made
+	ReceiptNumber     int64            `json:"receipt_number" example:"1"`
+	Lines             []SaleLineDTO    `json:"lines"`
+	Payments          []SalePaymentDTO `json:"payments"`
+	Subtotal          int64            `json:"subtotal" example:"1700"`
+	DiscountTotal     int64            `json:"discount_total" example:"0"`
+	TaxRate           int64            `json:"tax_rate" example:"1000"`
+	TaxTotal          int64            `json:"tax_total" example:"170"`
+	Total             int64            `json:"total" example:"1870"`
+	Paid              int64            `json:"paid" example:"2000"`
+	Change            int64            `json:"change" example:"130"`
+	CreatedAt         time.Time        `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type SaleResponse struct {
+	Message string           `json:"message"`
+	Data    SaleResponseData `json:"data"`
+}
+
+type SaleResponseData struct {
+	Sale SaleDTO `json:"sale"`
+}
+
+type SaleListResponse struct {
+	Message string               `json:"message"`
+	Data    SaleListResponseData `json:"data"`
+}
+
+type SaleListResponseData struct {
+	Sales      []SaleDTO `json:"sales"`
+	NextCursor string    `json:"next_cursor,omitempty" example:"eyJzIjoicmVjZW50IiwidiI6IiIsImlkIjoxfQ"`
+}
+
+// ListSales godoc
+// @Summary      List sales
+// @Description  List the sales of a shop, newest first, one page at a time. Staff limited to some locations only see sales made at those. Pass next_cursor from a response as cursor to get the next page.
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      int     true   "Shop ID"
+// @Param        location_id  query     int     false  "Only list sales made at this location"
+// @Param        cursor       query     string  false  "Cursor returned by the previous page"
+// @Param        limit        query     int     false  "Page size, at most 100"  default(20)
+// @Success      200          {object}  SaleListResponse
+// @Failure      400          {object}  map[string]string  "Invalid shop id, location_id or limit"
+// @Failure      403          {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the location"
+// @Failure      404          {object}  map[string]string  "Shop not found"
+// @Failure      422          {object}  map[string]string  "Validation failed"
+// @Failure      500          {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/sales [get]
+func (h *SaleHandler) ListSales(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+	locationID, err := parseOptionalID(c, "location_id")
+	if err != nil {
+		return err
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.listSalesUsecase.Execute(c.Context(), salesusecases.ListSalesParam{
+		ShopID:             shopID,
+		LocationID:         locationID,
+		Cursor:             c.Query("cursor"),
+		Limit:              limit,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to list sales")
+	}
+
+	sales := make([]SaleDTO, len(result.Sales))
+	for i, sale := range result.Sales {
+		sales[i] = toSaleDTO(sale)
+	}
+
+	return c.JSON(SaleListResponse{
+		Message: "sales retrieved successfully.",
+		Data: SaleListResponseData{
+			Sales:      sales,
+			NextCursor: result.NextCursor,
+		},
+	})
+}
+
+// GetSale godoc
+// @Summary      Get a sale
+// @Description  Get a sale of a shop with its lines and payments, as printed on its receipt
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Shop ID"
+// @Param        saleId  path      int  true  "Sale ID"
+// @Success      200     {object}  SaleResponse
+// @Failure      400     {object}  map[string]string  "Invalid shop or sale id"
+// @Failure      403     {object}  map[string]string  "Not a member of the shop, missing the sales.create permission or not working at the sale's location"
+// @Failure      404     {object}  map[string]string  "Shop or sale not found"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/sales/{saleId} [get]
+func (h *SaleHandler) GetSale(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	saleID, err := strconv.ParseUint(c.Params("saleId"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid sale id")
+	}
+
+	membership, err := GetMembership(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.getSaleUsecase.Execute(c.Context(), salesusecases.GetSaleParam{
+		ShopID:             shopID,
+		SaleID:             saleID,
+		AllowedLocationIDs: membership.LocationIDs,
+	})
+	if err != nil {
+		return salesError(err, "failed to get sale")
+	}
+
+	return c.JSON(SaleResponse{
+		Message: "sale retrieved successfully.",
+		Data: SaleResponseData{
+			Sale: toSaleDTO(*result.Sale),
+		},
+	})
+}
+
+func toSaleDTO(sale salesentities.Sale) SaleDTO {
+	lines := make([]SaleLineDTO, len(sale.Lines))
+	for i, line := range sale.Lines {
+		lines[i] = SaleLineDTO{
+			ID:             line.ID,
+			VariantID:      line.VariantID,
+			SKU:            line.SKU,
+			Name:           line.Name,
+			UnitPrice:      line.UnitPrice,
+			Quantity:       line.Quantity,
+			DiscountAmount: line.DiscountAmount,
+			Total:          line.Total,
+		}
+	}
+
+	payments := make([]SalePaymentDTO, len(sale.Payments))
+	for i, payment := range sale.Payments {
+		payments[i] = SalePaymentDTO{
+			Method:    string(payment.Method),
+			Amount:    payment.Amount,
+			Reference: payment.Reference,
+		}
+	}
+
+	return SaleDTO{
+		ID:                sale.ID,
+		LocationID:        sale.LocationID,
+		CartID:            sale.CartID,
+		UserID:            sale.UserID,
+		RegisterSessionID: sale.RegisterSessionID,
+		ReceiptNumber:     sale.ReceiptNumber,
+		Lines:             lines,
+		Payments:          payments,
+		Subtotal:          sale.Subtotal,
+		DiscountTotal:     sale.DiscountTotal,
+		TaxRate:           sale.TaxRate,
+		TaxTotal:          sale.TaxTotal,
+		Total:             sale.Total,
+		Paid:              sale.Paid,
+		Change:            sale.Change,
+		CreatedAt:         sale.CreatedAt,
+	}
+}
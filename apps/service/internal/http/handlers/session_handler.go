@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type SessionHandler struct {
+	listSessionsUsecase  *usecases.ListSessionsUsecase
+	revokeSessionUsecase *usecases.RevokeSessionUsecase
+}
+
+func NewSessionHandler(listSessionsUsecase *usecases.ListSessionsUsecase, revokeSessionUsecase *usecases.RevokeSessionUsecase) *SessionHandler {
+	return &SessionHandler{
+		listSessionsUsecase:  listSessionsUsecase,
+		revokeSessionUsecase: revokeSessionUsecase,
+	}
+}
+
+// ListSessions godoc
+// @Summary      List active sessions
+// @Description  Get the active sessions of the authenticated user
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  SessionListResponse
+// @Failure      401  {object}  map[string]string  "Authentication required"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /auth/sessions [get]
+func (h *SessionHandler) ListSessions(c fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Context()
+	result := h.listSessionsUsecase.Execute(ctx, userID)
+
+	sessions := make([]SessionResponseDTO, len(result.Sessions))
+	for i, session := range result.Sessions {
+		sessions[i] = SessionResponseDTO{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			ExpiresAt:  session.ExpiresAt,
+			LastUsedAt: session.LastUsedAt,
+			CreatedAt:  session.CreatedAt,
+		}
+	}
+
+	return c.JSON(SessionListResponse{
+		Message: "sessions retrieved successfully.",
+		Data: SessionListResponseData{
+			Sessions: sessions,
+		},
+	})
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a session
+// @Description  Terminate one of the authenticated user's sessions, e.g. a login from another device
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Session ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  map[string]string  "Invalid session id"
+// @Failure      401  {object}  map[string]string  "Authentication required"
+// @Failure      404  {object}  map[string]string  "Session not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid session id")
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	ctx := c.Context()
+	err = h.revokeSessionUsecase.Execute(ctx, usecases.RevokeSessionParam{
+		UserID:    userID,
+		SessionID: id,
+	})
+	if err != nil {
+		if err.Error() == "session not found" {
+			return fiber.NewError(fiber.StatusNotFound, "session not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to revoke session")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+type SessionResponseDTO struct {
+	ID         uint64    `json:"id" example:"1"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0"`
+	IPAddress  string    `json:"ip_address" example:"127.0.0.1"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2024-01-08T00:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2024-01-01T00:00:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type SessionListResponse struct {
+	Message string                  `json:"message"`
+	Data    SessionListResponseData `json:"data"`
+}
+
+type SessionListResponseData struct {
+	Sessions []SessionResponseDTO `json:"sessions"`
+}
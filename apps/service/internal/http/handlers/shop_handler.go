@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
 	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+	shopentities "github.com/reno1r/weiss/apps/service/internal/app/shop/entities"
 	"github.com/reno1r/weiss/apps/service/internal/app/shop/usecases"
 	userusecases "github.com/reno1r/weiss/apps/service/internal/app/user/usecases"
 )
@@ -49,38 +49,67 @@ func NewShopHandler(
 }
 
 // ListShops godoc
-// @Summary      List all shops
-// @Description  Get a list of all available shops
+// @Summary      List the caller's shops
+// @Description  List the shops the caller is a staff member of, with their role in each, one page at a time. Pass next_cursor from a response as cursor to get the next page.
 // @Tags         shops
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  ShopListResponse
-// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Param        q       query     string  false  "Case-insensitive search on the shop name"
+// @Param        sort    query     string  false  "Sort order"  Enums(name, -name, created_at, -created_at)  default(name)
+// @Param        cursor  query     string  false  "Cursor returned by the previous page"
+// @Param        limit   query     int     false  "Page size, at most 100"  default(20)
+// @Success      200     {object}  ShopListResponse
+// @Failure      400     {object}  map[string]string  "Invalid limit"
+// @Failure      422     {object}  map[string]string  "Validation failed"
+// @Failure      500     {object}  map[string]string  "Internal server error"
 // @Router       /shops [get]
 func (h *ShopHandler) ListShops(c fiber.Ctx) error {
-	ctx := c.Context()
-	result := h.listShopsUsecase.Execute(ctx)
-
-	shops := make([]ShopResponseDTO, len(result.Shops))
-	for i, shop := range result.Shops {
-		shops[i] = ShopResponseDTO{
-			ID:          shop.ID,
-			Name:        shop.Name,
-			Description: shop.Description,
-			Address:     shop.Address,
-			Phone:       shop.Phone,
-			Email:       shop.Email,
-			Website:     shop.Website,
-			Logo:        shop.Logo,
-			CreatedAt:   shop.CreatedAt,
-			UpdatedAt:   shop.UpdatedAt,
+	limit, err := parseLimit(c)
+	if err != nil {
+		return err
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.listShopsUsecase.Execute(c.Context(), usecases.ListShopsParam{
+		UserID: userID,
+		Search: c.Query("q"),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		if isValidationError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to list shops")
+	}
+
+	shops := make([]ShopResponseDTO, len(result.Memberships))
+	for i, membership := range result.Memberships {
+		shops[i] = toShopResponseDTO(*membership.Shop)
+		shops[i].Membership = &ShopMembershipDTO{
+			StaffID: membership.ID,
+			Status:  membership.Status,
+		}
+		if membership.Role != nil {
+			shops[i].Membership.Role = RoleDTO{
+				ID:          membership.Role.ID,
+				Name:        membership.Role.Name,
+				Description: membership.Role.Description,
+			}
 		}
 	}
 
 	return c.JSON(ShopListResponse{
 		Message: "shops retrieved successfully.",
 		Data: ShopListResponseData{
-			Shops: shops,
+			Shops:      shops,
+			NextCursor: result.NextCursor,
 		},
 	})
 }
@@ -91,9 +120,11 @@ func (h *ShopHandler) ListShops(c fiber.Ctx) error {
 // @Tags         shops
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Shop ID"
 // @Success      200  {object}  ShopResponse
 // @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop"
 // @Failure      404  {object}  map[string]string  "Shop not found"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /shops/{id} [get]
@@ -139,9 +170,11 @@ func (h *ShopHandler) GetShop(c fiber.Ctx) error {
 // @Tags         shops
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      CreateShopPayload  true  "Shop data"
 // @Success      201      {object}  ShopResponse
 // @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      403      {object}  map[string]string  "Account not verified"
 // @Failure      422      {object}  map[string]string  "Validation failed"
 // @Failure      500      {object}  map[string]string  "Internal server error"
 // @Router       /shops [post]
@@ -153,7 +186,6 @@ func (h *ShopHandler) CreateShop(c fiber.Ctx) error {
 	}
 
 	// Get authenticated user ID from context
-	// This should be set by authentication middleware
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
 		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
@@ -205,10 +237,12 @@ func (h *ShopHandler) CreateShop(c fiber.Ctx) error {
 // @Tags         shops
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int                true  "Shop ID"
 // @Param        request  body      UpdateShopPayload  true  "Shop data"
 // @Success      200      {object}  ShopResponse
 // @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop or missing the shop.update permission"
 // @Failure      404      {object}  map[string]string  "Shop not found"
 // @Failure      422      {object}  map[string]string  "Validation failed"
 // @Failure      500      {object}  map[string]string  "Internal server error"
@@ -274,9 +308,11 @@ func (h *ShopHandler) UpdateShop(c fiber.Ctx) error {
 // @Tags         shops
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Shop ID"
 // @Success      204  "No Content"
 // @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop, missing the shop.delete permission, or impersonating a user"
 // @Failure      404  {object}  map[string]string  "Shop not found"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /shops/{id} [delete]
@@ -304,9 +340,11 @@ func (h *ShopHandler) DeleteShop(c fiber.Ctx) error {
 // @Tags         shops
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Shop ID"
 // @Success      200  {object}  StaffListResponse
 // @Failure      400  {object}  map[string]string  "Invalid shop id"
+// @Failure      403  {object}  map[string]string  "Not a member of the shop or missing the staff.view permission"
 // @Failure      404  {object}  map[string]string  "Shop not found"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /shops/{id}/staffs [get]
@@ -340,6 +378,8 @@ func (h *ShopHandler) GetStaff(c fiber.Ctx) error {
 	staffs := make([]StaffResponseDTO, len(result.Staffs))
 	for i, staffInfo := range result.Staffs {
 		staffs[i] = StaffResponseDTO{
+			ID:     staffInfo.ID,
+			Status: staffInfo.Status,
 			User: UserDTO{
 				ID:       staffInfo.User.ID,
 				FullName: staffInfo.User.FullName,
@@ -364,14 +404,17 @@ func (h *ShopHandler) GetStaff(c fiber.Ctx) error {
 
 // AssignStaff godoc
 // @Summary      Assign staff to a shop
-// @Description  Assign a user with a role to a shop
+// @Description  Assign a user with a role to a shop. The caller must hold every permission of the role, and only an Owner can assign the Owner role.
 // @Tags         shops
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int                true  "Shop ID"
 // @Param        request  body      AssignStaffPayload  true  "Staff assignment data"
 // @Success      201      {object}  StaffResponse
 // @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      401      {object}  map[string]string  "Authentication required"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the staff.assign permission, or missing a permission of the role"
 // @Failure      404      {object}  map[string]string  "Shop, user, or role not found"
 // @Failure      409      {object}  map[string]string  "Staff already assigned"
 // @Failure      422      {object}  map[string]string  "Validation failed"
@@ -388,6 +431,11 @@ func (h *ShopHandler) AssignStaff(c fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
 	ctx := c.Context()
 	// Get shop first to validate it exists
 	shopResult, err := h.getShopUsecase.Execute(ctx, id)
@@ -423,14 +471,18 @@ func (h *ShopHandler) AssignStaff(c fiber.Ctx) error {
 
 	// Assign staff
 	result, err := h.assignStaffUsecase.Execute(ctx, accessusecases.AssignStaffParam{
-		User: userResult.User,
-		Shop: shopResult.Shop,
-		Role: roleResult.Role,
+		ActorID: actorID,
+		User:    userResult.User,
+		Shop:    shopResult.Shop,
+		Role:    roleResult.Role,
 	})
 	if err != nil {
 		if isValidationError(err) {
 			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
 		}
+		if isForbiddenError(err) {
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
 		if strings.Contains(err.Error(), "already assigned") {
 			return fiber.NewError(fiber.StatusConflict, err.Error())
 		}
@@ -438,6 +490,8 @@ func (h *ShopHandler) AssignStaff(c fiber.Ctx) error {
 	}
 
 	staffResponse := StaffResponseDTO{
+		ID:     result.Staff.ID,
+		Status: result.Staff.Status,
 		User: UserDTO{
 			ID:       result.Staff.User.ID,
 			FullName: result.Staff.User.FullName,
@@ -485,7 +539,8 @@ type ShopListResponse struct {
 }
 
 type ShopListResponseData struct {
-	Shops []ShopResponseDTO `json:"shops"`
+	Shops      []ShopResponseDTO `json:"shops"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"eyJzIjoibmFtZSIsInYiOiJNeSBTaG9wIiwiaWQiOjF9"`
 }
 
 type ShopResponse struct {
@@ -508,6 +563,16 @@ type ShopResponseDTO struct {
 	Logo        string    `json:"logo" example:"logo.png"`
 	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt   time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	SuspendedAt *time.Time         `json:"suspended_at,omitempty"`
+	Membership  *ShopMembershipDTO `json:"membership,omitempty"`
+}
+
+// ShopMembershipDTO is the caller's own staff record in a listed shop.
+type ShopMembershipDTO struct {
+	StaffID uint64  `json:"staff_id" example:"1"`
+	Status  string  `json:"status" example:"active"`
+	Role    RoleDTO `json:"role"`
 }
 
 type AssignStaffPayload struct {
@@ -529,8 +594,11 @@ type RoleDTO struct {
 }
 
 type StaffResponseDTO struct {
-	User UserDTO `json:"user"`
-	Role RoleDTO `json:"role"`
+	ID          uint64   `json:"id" example:"1"`
+	Status      string   `json:"status" example:"active"`
+	User        UserDTO  `json:"user"`
+	Role        RoleDTO  `json:"role"`
+	LocationIDs []uint64 `json:"location_ids,omitempty" example:"1,2"` // Locations the staff member is limited to, when any
 }
 
 type StaffListResponse struct {
@@ -551,35 +619,32 @@ type StaffResponseData struct {
 	Staff StaffResponseDTO `json:"staff"`
 }
 
-// getUserIDFromContext extracts the authenticated user ID from the fiber context
-// This expects the user ID to be set in context by authentication middleware
-// The key "user_id" is a common convention, but can be adjusted based on your auth implementation
-func getUserIDFromContext(c fiber.Ctx) (uint64, error) {
-	// Try to get user ID from locals (set by auth middleware)
-	userIDValue := c.Locals("user_id")
-	if userIDValue == nil {
-		return 0, fiber.NewError(fiber.StatusUnauthorized, "user ID not found in context")
-	}
-
-	// Handle different possible types
-	switch v := userIDValue.(type) {
-	case uint64:
-		return v, nil
-	case int:
-		return uint64(v), nil
-	case int64:
-		return uint64(v), nil
-	case float64:
-		return uint64(v), nil
-	case string:
-		// Try to parse as uint64
-		var id uint64
-		_, err := fmt.Sscanf(v, "%d", &id)
-		if err != nil {
-			return 0, fiber.NewError(fiber.StatusUnauthorized, "invalid user ID format")
-		}
-		return id, nil
-	default:
-		return 0, fiber.NewError(fiber.StatusUnauthorized, "invalid user ID type")
+func toShopResponseDTO(shop shopentities.Shop) ShopResponseDTO {
+	return ShopResponseDTO{
+		ID:          shop.ID,
+		Name:        shop.Name,
+		Description: shop.Description,
+		Address:     shop.Address,
+		Phone:       shop.Phone,
+		Email:       shop.Email,
+		Website:     shop.Website,
+		Logo:        shop.Logo,
+		CreatedAt:   shop.CreatedAt,
+		UpdatedAt:   shop.UpdatedAt,
+		SuspendedAt: shop.SuspendedAt,
+	}
+}
+
+// parseLimit reads the optional limit query parameter of list endpoints.
+func parseLimit(c fiber.Ctx) (int, error) {
+	value := c.Query("limit")
+	if value == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid limit")
 	}
+	return limit, nil
 }
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+)
+
+type StaffHandler struct {
+	updateStaffUsecase       *accessusecases.UpdateStaffUsecase
+	removeStaffUsecase       *accessusecases.RemoveStaffUsecase
+	transferOwnershipUsecase *accessusecases.TransferOwnershipUsecase
+}
+
+func NewStaffHandler(updateStaffUsecase *accessusecases.UpdateStaffUsecase, removeStaffUsecase *accessusecases.RemoveStaffUsecase, transferOwnershipUsecase *accessusecases.TransferOwnershipUsecase) *StaffHandler {
+	return &StaffHandler{
+		updateStaffUsecase:       updateStaffUsecase,
+		removeStaffUsecase:       removeStaffUsecase,
+		transferOwnershipUsecase: transferOwnershipUsecase,
+	}
+}
+
+// UpdateStaffPayload changes a staff member. Fields left out are not
+// changed.
+type UpdateStaffPayload struct {
+	RoleID uint64 `json:"role_id,omitempty" example:"2"`        // Role to move the staff member to
+	Status string `json:"status,omitempty" example:"suspended"` // active or suspended
+}
+
+type TransferOwnershipPayload struct {
+	StaffID uint64 `json:"staff_id" example:"2" binding:"required"` // Staff member to hand the Owner role to
+	RoleID  uint64 `json:"role_id" example:"3" binding:"required"`  // Role the current Owner moves to
+}
+
+type TransferOwnershipResponse struct {
+	Message string                        `json:"message"`
+	Data    TransferOwnershipResponseData `json:"data"`
+}
+
+type TransferOwnershipResponseData struct {
+	Owner    StaffResponseDTO `json:"owner"`
+	Previous StaffResponseDTO `json:"previous"`
+}
+
+// UpdateStaff godoc
+// @Summary      Update a staff member
+// @Description  Move a staff member to another role, or suspend or reinstate them. Changing the role requires the staff.assign permission and every permission of the new role; changing the status requires the staff.remove permission. Suspended staff members keep their role and history but cannot access the shop. The last active Owner of a shop can be neither moved nor suspended.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "Shop ID"
+// @Param        staffId  path      int                 true  "Staff ID"
+// @Param        request  body      UpdateStaffPayload  true  "Changes to the staff member"
+// @Success      200      {object}  StaffResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop or staff id, or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing a required permission, granting a permission the caller does not hold, or changing an Owner or a staff member with permissions the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop, staff, or role not found"
+// @Failure      409      {object}  map[string]string  "Role already granted or last Owner"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{staffId} [patch]
+func (h *StaffHandler) UpdateStaff(c fiber.Ctx) error {
+	shopID, staffID, err := parseStaffParams(c)
+	if err != nil {
+		return err
+	}
+
+	var request UpdateStaffPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.updateStaffUsecase.Execute(c.Context(), accessusecases.UpdateStaffParam{
+		ActorID: actorID,
+		ShopID:  shopID,
+		StaffID: staffID,
+		RoleID:  request.RoleID,
+		Status:  request.Status,
+	})
+	if err != nil {
+		return accessError(err, "failed to update staff")
+	}
+
+	return c.JSON(StaffResponse{
+		Message: "staff updated successfully.",
+		Data: StaffResponseData{
+			Staff: toStaffResponseDTO(result.Staff),
+		},
+	})
+}
+
+// RemoveStaff godoc
+// @Summary      Remove a staff member
+// @Description  Remove a staff member from a shop together with the permissions granted to them directly. Their access history is kept. The last active Owner of a shop cannot be removed.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  int  true  "Shop ID"
+// @Param        staffId  path  int  true  "Staff ID"
+// @Success      204      "No Content"
+// @Failure      400      {object}  map[string]string  "Invalid shop or staff id"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, missing the staff.remove permission, or removing an Owner or a staff member with permissions the caller does not hold"
+// @Failure      404      {object}  map[string]string  "Shop or staff not found"
+// @Failure      409      {object}  map[string]string  "Last Owner"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/staffs/{staffId} [delete]
+func (h *StaffHandler) RemoveStaff(c fiber.Ctx) error {
+	shopID, staffID, err := parseStaffParams(c)
+	if err != nil {
+		return err
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	err = h.removeStaffUsecase.Execute(c.Context(), accessusecases.RemoveStaffParam{
+		ActorID: actorID,
+		ShopID:  shopID,
+		StaffID: staffID,
+	})
+	if err != nil {
+		return accessError(err, "failed to remove staff")
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// TransferOwnership godoc
+// @Summary      Transfer shop ownership
+// @Description  Hand the Owner role of the caller to another active staff member of the shop and move the caller to another role, in one step. Only an Owner can transfer ownership.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                       true  "Shop ID"
+// @Param        request  body      TransferOwnershipPayload  true  "Transfer data"
+// @Success      200      {object}  TransferOwnershipResponse
+// @Failure      400      {object}  map[string]string  "Invalid shop id or request body"
+// @Failure      403      {object}  map[string]string  "Not a member of the shop, not an Owner, calling with an API key, or impersonating a user"
+// @Failure      404      {object}  map[string]string  "Shop, staff, or role not found"
+// @Failure      409      {object}  map[string]string  "Staff member is suspended"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /shops/{id}/transfer-ownership [post]
+func (h *StaffHandler) TransferOwnership(c fiber.Ctx) error {
+	shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+	}
+
+	var request TransferOwnershipPayload
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	result, err := h.transferOwnershipUsecase.Execute(c.Context(), accessusecases.TransferOwnershipParam{
+		ActorID: actorID,
+		ShopID:  shopID,
+		StaffID: request.StaffID,
+		RoleID:  request.RoleID,
+	})
+	if err != nil {
+		return accessError(err, "failed to transfer ownership")
+	}
+
+	return c.JSON(TransferOwnershipResponse{
+		Message: "ownership transferred successfully.",
+		Data: TransferOwnershipResponseData{
+			Owner:    toStaffResponseDTO(result.Owner),
+			Previous: toStaffResponseDTO(result.Previous),
+		},
+	})
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+)
+
+type VerificationHandler struct {
+	sendVerificationUsecase    *usecases.SendVerificationUsecase
+	confirmVerificationUsecase *usecases.ConfirmVerificationUsecase
+}
+
+func NewVerificationHandler(sendVerificationUsecase *usecases.SendVerificationUsecase, confirmVerificationUsecase *usecases.ConfirmVerificationUsecase) *VerificationHandler {
+	return &VerificationHandler{
+		sendVerificationUsecase:    sendVerificationUsecase,
+		confirmVerificationUsecase: confirmVerificationUsecase,
+	}
+}
+
+type SendVerificationPayload struct {
+	Email string `json:"email,omitempty" example:"john@example.com"` // Email address to verify
+	Phone string `json:"phone,omitempty" example:"1234567890"`       // Phone number to verify
+}
+
+type ConfirmVerificationPayload struct {
+	Email string `json:"email,omitempty" example:"john@example.com"` // Email address the code was sent to
+	Phone string `json:"phone,omitempty" example:"1234567890"`       // Phone number the code was sent to
+	Code  string `json:"code" example:"123456" binding:"required"`   // Six digit verification code
+}
+
+type VerificationResponse struct {
+	Message string `json:"message"`
+}
+
+type ConfirmVerificationResponse struct {
+	Message string                          `json:"message"`
+	Data    ConfirmVerificationResponseData `json:"data"`
+}
+
+type ConfirmVerificationResponseData struct {
+	User *UserResponse `json:"user"`
+}
+
+// SendVerification godoc
+// @Summary      Send verification code
+// @Description  Send a six digit verification code to an email address or phone number. Codes can be resent after a short interval.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SendVerificationPayload  true  "Email or phone to verify"
+// @Success      202      {object}  VerificationResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      429      {object}  map[string]string  "Code sent recently"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Failure      503      {object}  map[string]string  "SMS delivery is not configured"
+// @Router       /auth/verification/send [post]
+func (h *VerificationHandler) SendVerification(c fiber.Ctx) error {
+	var request SendVerificationPayload
+
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	err := h.sendVerificationUsecase.Execute(ctx, usecases.SendVerificationParam{
+		Email: request.Email,
+		Phone: request.Phone,
+	})
+	if err != nil {
+		var retryErr *usecases.RetryAfterError
+		if errors.As(err, &retryErr) {
+			return tooManyRequests(c, retryErr)
+		}
+		if isValidationError(err) || isRequiredError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if isChannelUnavailableError(err) {
+			return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to send verification code")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(VerificationResponse{
+		Message: "if the contact belongs to an unverified account, a verification code has been sent.",
+	})
+}
+
+// ConfirmVerification godoc
+// @Summary      Confirm verification code
+// @Description  Confirm ownership of an email address or phone number with the code that was sent to it
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ConfirmVerificationPayload  true  "Contact and code"
+// @Success      200      {object}  ConfirmVerificationResponse
+// @Failure      400      {object}  map[string]string  "Invalid request body or code"
+// @Failure      422      {object}  map[string]string  "Validation failed"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Router       /auth/verification/confirm [post]
+func (h *VerificationHandler) ConfirmVerification(c fiber.Ctx) error {
+	var request ConfirmVerificationPayload
+
+	if err := c.Bind().Body(&request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Context()
+	result, err := h.confirmVerificationUsecase.Execute(ctx, usecases.ConfirmVerificationParam{
+		Email: request.Email,
+		Phone: request.Phone,
+		Code:  request.Code,
+	})
+	if err != nil {
+		if isValidationError(err) || isRequiredError(err) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+		if err.Error() == "invalid or expired verification code" {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to confirm verification code")
+	}
+
+	return c.JSON(ConfirmVerificationResponse{
+		Message: "contact verified successfully.",
+		Data: ConfirmVerificationResponseData{
+			User: toUserResponse(result.User),
+		},
+	})
+}
+
+// tooManyRequests renders a throttled action as 429 with a Retry-After header
+// rounded up to whole seconds.
+func tooManyRequests(c fiber.Ctx, err *usecases.RetryAfterError) error {
+	seconds := int(math.Ceil(err.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+}
+
+func isChannelUnavailableError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "phone channel unavailable")
+}
+
+func isNotVerifiedError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "account not verified")
+}
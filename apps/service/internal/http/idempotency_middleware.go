@@ -0,0 +1,64 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/idempotency"
+	"github.com/google/uuid"
+
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+const idempotencyKeyHeader = "X-Idempotency-Key"
+
+// idempotencyKeyLifetime is how long a retry gets the first response back.
+const idempotencyKeyLifetime = 24 * time.Hour
+
+// NewIdempotencyMiddleware answers a request retried with the same
+// X-Idempotency-Key with the response of the first attempt instead of doing
+// the work again. The key is scoped to the caller and the route: it is
+// combined with the user or API key of the principal and with the method and
+// path, which name the shop, so the same key sent by someone else or to
+// another route is a different request. Only successful responses are kept.
+// It must run after the auth middleware, and after the membership and
+// permission checks of the route so that a cached response is never served
+// to a caller who lost access.
+func NewIdempotencyMiddleware(storage fiber.Storage) fiber.Handler {
+	replay := idempotency.New(idempotency.Config{
+		Storage:   storage,
+		Lifetime:  idempotencyKeyLifetime,
+		KeyHeader: idempotencyKeyHeader,
+		// The header holds the scoped key, checked below, by the time the
+		// cache reads it.
+		KeyHeaderValidate: func(string) error { return nil },
+	})
+
+	return func(c fiber.Ctx) error {
+		key := c.Get(idempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if _, err := uuid.Parse(key); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid idempotency key: must be a UUID")
+		}
+
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+		caller := fmt.Sprintf("user:%d", principal.UserID)
+		if principal.UsesAPIKey() {
+			caller = fmt.Sprintf("api-key:%d", principal.APIKey.ID)
+		}
+
+		scoped := sha256.Sum256([]byte(strings.Join([]string{caller, c.Method(), c.Path(), key}, "\n")))
+		c.Request().Header.Set(idempotencyKeyHeader, hex.EncodeToString(scoped[:]))
+
+		return replay(c)
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRecord is a response kept by the idempotency middleware under
+// its scoped key.
+type IdempotencyRecord struct {
+	Key       string    `gorm:"primaryKey;column:key"`
+	Response  []byte    `gorm:"column:response;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null;index:idx_idempotency_keys_expires_at"`
+}
+
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+type idempotencyStorage struct {
+	db *gorm.DB
+}
+
+// newIdempotencyStorage returns a fiber.Storage backed by the database, so a
+// retry that reaches another instance of the service still gets the first
+// response.
+func newIdempotencyStorage(db *gorm.DB) fiber.Storage {
+	return &idempotencyStorage{
+		db: db,
+	}
+}
+
+// requestContext returns the context of the request when the middleware
+// passes its fiber.Ctx, so that a response is stored in the same tenant
+// transaction as the work that produced it, see db.RunAsTenant.
+func requestContext(ctx context.Context) context.Context {
+	if c, ok := ctx.(fiber.Ctx); ok {
+		return c.Context()
+	}
+	return ctx
+}
+
+// GetWithContext returns nil for unknown keys, as fiber.Storage requires.
+// Most lookups miss, so Find is used rather than First, which would log
+// every miss as an error.
+func (s *idempotencyStorage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	var records []IdempotencyRecord
+	err := s.db.WithContext(requestContext(ctx)).Where("key = ? AND expires_at > ?", key, time.Now()).Limit(1).Find(&records).Error
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0].Response, nil
+}
+
+func (s *idempotencyStorage) Get(key string) ([]byte, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// SetWithContext stores a response and clears the expired ones.
+func (s *idempotencyStorage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	now := time.Now()
+	db := s.db.WithContext(requestContext(ctx))
+
+	if err := db.Where("expires_at <= ?", now).Delete(&IdempotencyRecord{}).Error; err != nil {
+		return err
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"response", "expires_at"}),
+	}).Create(&IdempotencyRecord{Key: key, Response: val, ExpiresAt: now.Add(exp)}).Error
+}
+
+func (s *idempotencyStorage) Set(key string, val []byte, exp time.Duration) error {
+	return s.SetWithContext(context.Background(), key, val, exp)
+}
+
+func (s *idempotencyStorage) DeleteWithContext(ctx context.Context, key string) error {
+	return s.db.WithContext(requestContext(ctx)).Where("key = ?", key).Delete(&IdempotencyRecord{}).Error
+}
+
+func (s *idempotencyStorage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+func (s *idempotencyStorage) ResetWithContext(ctx context.Context) error {
+	return s.db.WithContext(requestContext(ctx)).Where("1 = 1").Delete(&IdempotencyRecord{}).Error
+}
+
+func (s *idempotencyStorage) Reset() error {
+	return s.ResetWithContext(context.Background())
+}
+
+func (s *idempotencyStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+// NewNoImpersonationMiddleware rejects requests made with an impersonation
+// token. It guards the routes that change how a user signs in, issue
+// credentials in their name, or delete or hand over their shops, which an
+// administrator acting as the user must never touch. It must run after the
+// auth middleware.
+func NewNoImpersonationMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+
+		if principal.IsImpersonated() {
+			return fiber.NewError(fiber.StatusForbidden, "forbidden: not allowed while impersonating")
+		}
+
+		return c.Next()
+	}
+}
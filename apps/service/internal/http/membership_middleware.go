@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+
+	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/db"
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+// NewShopMembershipMiddleware resolves the shop named by the :id route
+// parameter and the caller's staff record in it, and stores both in the
+// context. Unknown shops are answered with 404 and callers who are not staff
+// of the shop, or whose membership or shop is suspended, with 403. The staff
+// record comes with its Role, Shop and LocationIDs. The rest of the request
+// runs as the shop's tenant, see db.RunAsTenant. It must run after the auth
+// middleware.
+func NewShopMembershipMiddleware(database *gorm.DB, shopRepository shoprepositories.ShopRepository, staffRepository accessrepositories.StaffRepository, roleRepository accessrepositories.RoleRepository, staffLocationRepository accessrepositories.StaffLocationRepository) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+
+		shopID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid shop id")
+		}
+
+		ctx := c.Context()
+		shop, err := shopRepository.FindByID(ctx, shopID)
+		if err != nil {
+			if err.Error() == "shop not found" {
+				return fiber.NewError(fiber.StatusNotFound, "shop not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get shop")
+		}
+
+		staff, err := staffRepository.FindByShopIDAndUserID(ctx, shop.ID, principal.UserID)
+		if err != nil {
+			if err.Error() == "staff not found" {
+				return fiber.NewError(fiber.StatusForbidden, "forbidden: not a member of this shop")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get membership")
+		}
+		if staff.IsSuspended() {
+			return fiber.NewError(fiber.StatusForbidden, "forbidden: membership is suspended")
+		}
+		if shop.IsSuspended() {
+			return fiber.NewError(fiber.StatusForbidden, "forbidden: shop is suspended")
+		}
+
+		role, err := roleRepository.FindByID(ctx, staff.RoleID)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get membership")
+		}
+
+		staff.Shop = &shop
+		staff.Role = &role
+		staff.LocationIDs = staffLocationRepository.FindByStaffID(ctx, staff.ID)
+		handlers.SetMembership(c, &staff)
+
+		return db.RunAsTenant(c.Context(), database, db.Tenant{ShopID: shop.ID, UserID: principal.UserID}, func(ctx context.Context) error {
+			c.SetContext(ctx)
+			return c.Next()
+		})
+	}
+}
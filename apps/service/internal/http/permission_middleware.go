@@ -0,0 +1,41 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+// NewPermissionMiddleware rejects callers whose membership in the shop does
+// not hold permission, and API keys the permission was not selected for. It
+// must run after the shop membership middleware.
+func NewPermissionMiddleware(authorizer *accessservices.Authorizer, permission accessentities.Permission) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		staff, err := handlers.GetMembership(c)
+		if err != nil {
+			return err
+		}
+
+		ok, err := authorizer.StaffCan(c.Context(), *staff, permission)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to check permissions")
+		}
+		if !ok {
+			return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("forbidden: missing permission %s", permission))
+		}
+
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+		if principal.UsesAPIKey() && !principal.APIKey.Allows(permission) {
+			return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("forbidden: api key lacks permission %s", permission))
+		}
+
+		return c.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -11,7 +12,6 @@ import (
 	"github.com/gofiber/fiber/v3/middleware/compress"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 	"github.com/gofiber/fiber/v3/middleware/helmet"
-	"github.com/gofiber/fiber/v3/middleware/idempotency"
 	"github.com/gofiber/fiber/v3/middleware/logger"
 	"github.com/gofiber/fiber/v3/middleware/recover"
 	"github.com/gofiber/swagger/v2"
@@ -19,29 +19,116 @@ import (
 
 	_ "github.com/reno1r/weiss/apps/service/docs/swagger"
 
+	accessentities "github.com/reno1r/weiss/apps/service/internal/app/access/entities"
 	accessrepositories "github.com/reno1r/weiss/apps/service/internal/app/access/repositories"
+	accessservices "github.com/reno1r/weiss/apps/service/internal/app/access/services"
 	accessusecases "github.com/reno1r/weiss/apps/service/internal/app/access/usecases"
+	adminrepositories "github.com/reno1r/weiss/apps/service/internal/app/admin/repositories"
+	adminusecases "github.com/reno1r/weiss/apps/service/internal/app/admin/usecases"
+	authrepositories "github.com/reno1r/weiss/apps/service/internal/app/auth/repositories"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/services"
 	"github.com/reno1r/weiss/apps/service/internal/app/auth/usecases"
+	catalogrepositories "github.com/reno1r/weiss/apps/service/internal/app/catalog/repositories"
+	catalogusecases "github.com/reno1r/weiss/apps/service/internal/app/catalog/usecases"
+	inventoryrepositories "github.com/reno1r/weiss/apps/service/internal/app/inventory/repositories"
+	inventoryusecases "github.com/reno1r/weiss/apps/service/internal/app/inventory/usecases"
+	salesrepositories "github.com/reno1r/weiss/apps/service/internal/app/sales/repositories"
+	salesusecases "github.com/reno1r/weiss/apps/service/internal/app/sales/usecases"
 	shoprepositories "github.com/reno1r/weiss/apps/service/internal/app/shop/repositories"
 	shopusecases "github.com/reno1r/weiss/apps/service/internal/app/shop/usecases"
 	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
 	userusecases "github.com/reno1r/weiss/apps/service/internal/app/user/usecases"
 	"github.com/reno1r/weiss/apps/service/internal/config"
 	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+	"github.com/reno1r/weiss/apps/service/internal/mail"
+	"github.com/reno1r/weiss/apps/service/internal/sms"
 )
 
 type Server struct {
-	app    *fiber.App
-	config *config.Config
-	db     *gorm.DB
+	app             *fiber.App
+	config          *config.Config
+	db              *gorm.DB
+	tokenService    *services.TokenService
+	passwordService *services.PasswordService
+	mailer          mail.Mailer
+	smsSender       sms.Sender
+	authMiddleware  fiber.Handler
+	loginThrottle   *services.LoginThrottleService
+	authorizer      *accessservices.Authorizer
+
+	// membershipMiddleware guards every /shops/:id route; see
+	// NewShopMembershipMiddleware.
+	membershipMiddleware fiber.Handler
+	// idempotencyMiddleware guards routes that must not run twice when a
+	// client retries; see NewIdempotencyMiddleware.
+	idempotencyMiddleware fiber.Handler
+
+	resetExpiresIn             time.Duration
+	verificationExpiresIn      time.Duration
+	verificationResendInterval time.Duration
+	invitationExpiresIn        time.Duration
 }
 
-func NewServer(config *config.Config, db *gorm.DB) *Server {
-	return NewServerWithTestMiddleware(config, db, nil)
-}
+func NewServer(config *config.Config, db *gorm.DB) (*Server, error) {
+	tokenService, err := services.NewTokenService(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token service: %w", err)
+	}
+
+	passwordService, err := services.NewPasswordService(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password service: %w", err)
+	}
+
+	mailer, err := mail.NewMailer(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mailer: %w", err)
+	}
+
+	smsSender, err := sms.NewSender(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SMS sender: %w", err)
+	}
+
+	resetExpiresIn, err := parseDuration(config.PasswordResetExpires, time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password reset expiration: %w", err)
+	}
+
+	verificationExpiresIn, err := parseDuration(config.VerificationExpires, 15*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification expiration: %w", err)
+	}
+
+	verificationResendInterval, err := parseDuration(config.VerificationResendInterval, time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification resend interval: %w", err)
+	}
+
+	invitationExpiresIn, err := parseDuration(config.InvitationExpires, 7*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation expiration: %w", err)
+	}
+
+	trustedProxies, err := parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	loginThrottle, err := newLoginThrottle(config, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create login throttle: %w", err)
+	}
+
+	idempotencyStorage, err := idempotencyStorageFor(config, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency store: %w", err)
+	}
+
+	staffRepo := accessrepositories.NewStaffRepository(db)
+	authorizer := accessservices.NewAuthorizer(staffRepo, accessrepositories.NewRolePermissionRepository(db), accessrepositories.NewStaffPermissionRepository(db))
+	membershipMiddleware := NewShopMembershipMiddleware(db, shoprepositories.NewShopRepository(db), staffRepo, accessrepositories.NewRoleRepository(db), accessrepositories.NewStaffLocationRepository(db))
 
-func NewServerWithTestMiddleware(config *config.Config, db *gorm.DB, testMiddleware fiber.Handler) *Server {
 	server := &Server{
 		app: fiber.New(fiber.Config{
 			AppName:         config.AppName,
@@ -54,23 +141,41 @@ func NewServerWithTestMiddleware(config *config.Config, db *gorm.DB, testMiddlew
 			ReadBufferSize:  4096,
 			WriteBufferSize: 4096,
 			ErrorHandler:    defaultErrorHandler,
-			TrustProxy:      true,
+			// The client IP feeds the login throttle and the session records,
+			// so X-Forwarded-For is only believed from the configured proxies.
+			// Without any, c.IP() is the address of the connection. Fiber reads
+			// the header from every caller when TrustProxy is off, so it stays on.
+			TrustProxy: true,
 			TrustProxyConfig: fiber.TrustProxyConfig{
-				Proxies: []string{"0.0.0.0/0"},
+				Proxies: trustedProxies,
 			},
 			ProxyHeader: fiber.HeaderXForwardedFor,
 		}),
-		config: config,
-		db:     db,
+		config:          config,
+		db:              db,
+		tokenService:    tokenService,
+		passwordService: passwordService,
+		mailer:          mailer,
+		smsSender:       smsSender,
+		authMiddleware:  NewAuthMiddleware(usecases.NewAuthenticateAccessTokenUsecase(userrepositories.NewUserRepository(db), authrepositories.NewSessionRepository(db), tokenService), accessusecases.NewAuthenticateAPIKeyUsecase(accessrepositories.NewAPIKeyRepository(db), userrepositories.NewUserRepository(db))),
+		loginThrottle:   loginThrottle,
+		authorizer:      authorizer,
+
+		membershipMiddleware:       membershipMiddleware,
+		idempotencyMiddleware:      NewIdempotencyMiddleware(idempotencyStorage),
+		resetExpiresIn:             resetExpiresIn,
+		verificationExpiresIn:      verificationExpiresIn,
+		verificationResendInterval: verificationResendInterval,
+		invitationExpiresIn:        invitationExpiresIn,
 	}
 
-	server.setupMiddleware(testMiddleware)
+	server.setupMiddleware()
 	server.setupRoutes()
 
-	return server
+	return server, nil
 }
 
-func (s *Server) setupMiddleware(testMiddleware fiber.Handler) {
+func (s *Server) setupMiddleware() {
 	s.app.Use(recover.New(recover.Config{
 		EnableStackTrace: s.config.AppDebug,
 	}))
@@ -88,16 +193,12 @@ func (s *Server) setupMiddleware(testMiddleware fiber.Handler) {
 	s.app.Use(compress.New(compress.Config{
 		Level: compress.LevelBestSpeed,
 	}))
-
-	s.app.Use(idempotency.New())
-
-	// Add test middleware if provided (for e2e tests)
-	if testMiddleware != nil {
-		s.app.Use(testMiddleware)
-	}
 }
 
 func (s *Server) setupRoutes() {
+	// Public routes must be registered before the protected group, since the
+	// group's middleware applies to every route under the same prefix that
+	// is registered after it.
 	s.app.Get("/health", func(c fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"status":  "ok",
@@ -105,49 +206,117 @@ func (s *Server) setupRoutes() {
 		})
 	})
 
+	s.app.Get("/.well-known/jwks.json", handlers.NewJWKSHandler(s.tokenService).Handle)
+
 	s.setupSwaggerRoutes()
 
 	s.setupAuthRoutes()
-	s.setupShopRoutes()
 
+	protected := s.app.Group("/api", s.authMiddleware)
+
+	s.setupMeRoutes(protected)
+	s.setupShopRoutes(protected)
+	s.setupInvitationRoutes(protected)
+	s.setupCatalogRoutes(protected)
+	s.setupInventoryRoutes(protected)
+	s.setupSalesRoutes(protected)
+	s.setupAdminRoutes(protected)
 }
 
 func (s *Server) setupAuthRoutes() {
 	userRepo := userrepositories.NewUserRepository(s.db)
-
-	passwordService := services.NewPasswordService(s.config)
-	tokenService, err := services.NewTokenService(s.config)
-
-	if err != nil {
-		return
-	}
-
-	registerUsecase := usecases.NewRegisterUsecase(userRepo, passwordService)
-	loginUsecase := usecases.NewLoginUsecase(userRepo, tokenService, passwordService)
+	sessionRepo := authrepositories.NewSessionRepository(s.db)
+	refreshTokenStore := authrepositories.NewRefreshTokenStore(s.db)
+	passwordResetRepo := authrepositories.NewPasswordResetRepository(s.db)
+	verificationCodeRepo := authrepositories.NewVerificationCodeRepository(s.db)
+	totpFactorRepo := authrepositories.NewTotpFactorRepository(s.db)
+	recoveryCodeRepo := authrepositories.NewRecoveryCodeRepository(s.db)
+
+	totpService := services.NewTotpService(s.config.AppName)
+
+	registerUsecase := usecases.NewRegisterUsecase(userRepo, accessrepositories.NewInvitationRepository(s.db), s.passwordService)
+	loginUsecase := usecases.NewLoginUsecase(userRepo, sessionRepo, refreshTokenStore, totpFactorRepo, s.tokenService, s.passwordService, s.loginThrottle, s.config.VerificationRequiredFor == verificationRequiredForLogin)
+	refreshTokenUsecase := usecases.NewRefreshTokenUsecase(userRepo, sessionRepo, refreshTokenStore, s.tokenService)
+	logoutUsecase := usecases.NewLogoutUsecase(sessionRepo, s.tokenService)
+	listSessionsUsecase := usecases.NewListSessionsUsecase(sessionRepo)
+	revokeSessionUsecase := usecases.NewRevokeSessionUsecase(sessionRepo)
+	forgotPasswordUsecase := usecases.NewForgotPasswordUsecase(userRepo, passwordResetRepo, s.mailer, s.resetExpiresIn, s.config.PasswordResetURL)
+	resetPasswordUsecase := usecases.NewResetPasswordUsecase(s.db, s.passwordService)
+	sendVerificationUsecase := usecases.NewSendVerificationUsecase(userRepo, verificationCodeRepo, s.mailer, s.smsSender, s.verificationExpiresIn, s.verificationResendInterval)
+	confirmVerificationUsecase := usecases.NewConfirmVerificationUsecase(s.db)
+	enrollTotpUsecase := usecases.NewEnrollTotpUsecase(userRepo, totpFactorRepo, totpService)
+	confirmTotpUsecase := usecases.NewConfirmTotpUsecase(s.db, totpService)
+	disableTotpUsecase := usecases.NewDisableTotpUsecase(s.db, s.passwordService, totpService)
+	verifyMfaUsecase := usecases.NewVerifyMfaUsecase(userRepo, sessionRepo, refreshTokenStore, totpFactorRepo, recoveryCodeRepo, s.tokenService, totpService, s.loginThrottle)
 
 	registerHandler := handlers.NewRegisterHandler(registerUsecase)
 	loginHandler := handlers.NewLoginHandler(loginUsecase)
+	refreshTokenHandler := handlers.NewRefreshTokenHandler(refreshTokenUsecase)
+	logoutHandler := handlers.NewLogoutHandler(logoutUsecase)
+	sessionHandler := handlers.NewSessionHandler(listSessionsUsecase, revokeSessionUsecase)
+	passwordResetHandler := handlers.NewPasswordResetHandler(forgotPasswordUsecase, resetPasswordUsecase)
+	verificationHandler := handlers.NewVerificationHandler(sendVerificationUsecase, confirmVerificationUsecase)
+	mfaHandler := handlers.NewMfaHandler(enrollTotpUsecase, confirmTotpUsecase, disableTotpUsecase, verifyMfaUsecase)
 
 	s.app.Post("/api/auth/register", registerHandler.Handle)
 	s.app.Post("/api/auth/login", loginHandler.Handle)
+	s.app.Post("/api/auth/refresh", refreshTokenHandler.Handle)
+	s.app.Post("/api/auth/password/forgot", passwordResetHandler.ForgotPassword)
+	s.app.Post("/api/auth/password/reset", passwordResetHandler.ResetPassword)
+	s.app.Post("/api/auth/verification/send", verificationHandler.SendVerification)
+	s.app.Post("/api/auth/verification/confirm", verificationHandler.ConfirmVerification)
+	s.app.Post("/api/auth/mfa/verify", mfaHandler.VerifyMfa)
+
+	// Session and two-factor management live under /api/auth but require an access token.
+	// Administrators impersonating a user cannot change how the user signs in.
+	notImpersonating := NewNoImpersonationMiddleware()
+	s.app.Post("/api/auth/logout", s.authMiddleware, logoutHandler.Handle)
+	s.app.Get("/api/auth/sessions", s.authMiddleware, sessionHandler.ListSessions)
+	s.app.Delete("/api/auth/sessions/:id", s.authMiddleware, notImpersonating, sessionHandler.RevokeSession)
+	s.app.Post("/api/auth/mfa/totp/enroll", s.authMiddleware, notImpersonating, mfaHandler.EnrollTotp)
+	s.app.Post("/api/auth/mfa/totp/confirm", s.authMiddleware, notImpersonating, mfaHandler.ConfirmTotp)
+	s.app.Post("/api/auth/mfa/totp/disable", s.authMiddleware, notImpersonating, mfaHandler.DisableTotp)
+}
+
+func (s *Server) setupMeRoutes(router fiber.Router) {
+	userRepo := userrepositories.NewUserRepository(s.db)
+	verificationCodeRepo := authrepositories.NewVerificationCodeRepository(s.db)
+
+	getUserUsecase := userusecases.NewGetUserUsecase(userRepo)
+	updateProfileUsecase := userusecases.NewUpdateProfileUsecase(userRepo)
+	changePasswordUsecase := usecases.NewChangePasswordUsecase(s.db, s.passwordService, s.loginThrottle)
+	requestContactChangeUsecase := usecases.NewRequestContactChangeUsecase(userRepo, verificationCodeRepo, s.passwordService, s.loginThrottle, s.mailer, s.smsSender, s.verificationExpiresIn, s.verificationResendInterval)
+	confirmContactChangeUsecase := usecases.NewConfirmContactChangeUsecase(s.db)
+
+	meHandler := handlers.NewMeHandler(getUserUsecase, updateProfileUsecase, changePasswordUsecase, requestContactChangeUsecase, confirmContactChangeUsecase)
+
+	router.Get("/me", meHandler.GetMe)
+	router.Patch("/me", meHandler.UpdateMe)
+	notImpersonating := NewNoImpersonationMiddleware()
+	router.Post("/me/password", notImpersonating, meHandler.ChangePassword)
+	router.Post("/me/email", notImpersonating, meHandler.RequestEmailChange)
+	router.Post("/me/email/confirm", notImpersonating, meHandler.ConfirmEmailChange)
+	router.Post("/me/phone", notImpersonating, meHandler.RequestPhoneChange)
+	router.Post("/me/phone/confirm", notImpersonating, meHandler.ConfirmPhoneChange)
 }
 
-func (s *Server) setupShopRoutes() {
+func (s *Server) setupShopRoutes(router fiber.Router) {
 	shopRepo := shoprepositories.NewShopRepository(s.db)
 	staffRepo := accessrepositories.NewStaffRepository(s.db)
 	userRepo := userrepositories.NewUserRepository(s.db)
 	roleRepo := accessrepositories.NewRoleRepository(s.db)
 
-	listShopsUsecase := shopusecases.NewListShopsUsecase(shopRepo)
+	listShopsUsecase := shopusecases.NewListShopsUsecase(staffRepo)
 	getShopUsecase := shopusecases.NewGetShopUsecase(shopRepo)
 	createShopUsecase := shopusecases.NewCreateShopUsecase(s.db, shopRepo, roleRepo, staffRepo)
 	updateShopUsecase := shopusecases.NewUpdateShopUsecase(shopRepo)
 	deleteShopUsecase := shopusecases.NewDeleteShopUsecase(shopRepo)
 
 	getStaffsUsecase := accessusecases.NewGetStaffsUsecase(staffRepo)
-	assignStaffUsecase := accessusecases.NewAssignStaffUsecase(staffRepo)
+	assignStaffUsecase := accessusecases.NewAssignStaffUsecase(s.db, s.authorizer)
 	getUserUsecase := userusecases.NewGetUserUsecase(userRepo)
 	getRoleUsecase := accessusecases.NewGetRoleUsecase(roleRepo)
+	unlockAccountUsecase := usecases.NewUnlockAccountUsecase(userRepo, staffRepo, s.authorizer, s.loginThrottle)
 
 	shopHandler := handlers.NewShopHandler(
 		listShopsUsecase,
@@ -161,14 +330,263 @@ func (s *Server) setupShopRoutes() {
 		getRoleUsecase,
 	)
 
-	s.app.Get("/api/shops", shopHandler.ListShops)
-	s.app.Get("/api/shops/:id", shopHandler.GetShop)
-	s.app.Post("/api/shops", shopHandler.CreateShop)
-	s.app.Put("/api/shops/:id", shopHandler.UpdateShop)
-	s.app.Delete("/api/shops/:id", shopHandler.DeleteShop)
+	accountLockHandler := handlers.NewAccountLockHandler(unlockAccountUsecase)
+
+	getStaffAccessUsecase := accessusecases.NewGetStaffAccessUsecase(staffRepo, accessrepositories.NewStaffPermissionRepository(s.db), accessrepositories.NewStaffLocationRepository(s.db), accessrepositories.NewAccessAuditLogRepository(s.db), s.authorizer)
+	grantAccessUsecase := accessusecases.NewGrantAccessUsecase(s.db, s.authorizer)
+	revokeAccessUsecase := accessusecases.NewRevokeAccessUsecase(s.db, s.authorizer)
+	accessHandler := handlers.NewAccessHandler(getStaffAccessUsecase, grantAccessUsecase, revokeAccessUsecase, accessusecases.NewSetStaffLocationsUsecase(s.db))
+
+	rolePermissionRepo := accessrepositories.NewRolePermissionRepository(s.db)
+	roleHandler := handlers.NewRoleHandler(
+		accessusecases.NewListRolesUsecase(roleRepo, rolePermissionRepo, staffRepo),
+		accessusecases.NewGetShopRoleUsecase(roleRepo, rolePermissionRepo, staffRepo),
+		accessusecases.NewCreateRoleUsecase(s.db, s.authorizer),
+		accessusecases.NewUpdateRoleUsecase(s.db, s.authorizer),
+		accessusecases.NewDeleteRoleUsecase(s.db, s.authorizer),
+	)
+
+	staffHandler := handlers.NewStaffHandler(
+		accessusecases.NewUpdateStaffUsecase(s.db, s.authorizer),
+		accessusecases.NewRemoveStaffUsecase(s.db, s.authorizer),
+		accessusecases.NewTransferOwnershipUsecase(s.db),
+	)
+
+	router.Get("/shops", shopHandler.ListShops)
+	if s.config.VerificationRequiredFor == verificationRequiredForShopCreation {
+		router.Post("/shops", NewVerifiedUserMiddleware(userRepo), shopHandler.CreateShop)
+	} else {
+		router.Post("/shops", shopHandler.CreateShop)
+	}
+
+	// Every route below addresses a single shop and is limited to its staff.
+	// API keys of the shop reach them too, except where noAPIKey says
+	// otherwise.
+	member := s.membershipMiddleware
+	noAPIKey := NewNoAPIKeyMiddleware()
+	notImpersonating := NewNoImpersonationMiddleware()
+	router.Get("/shops/:id", member, shopHandler.GetShop)
+	router.Put("/shops/:id", member, s.requirePermission(accessentities.PermissionShopUpdate), shopHandler.UpdateShop)
+	router.Delete("/shops/:id", member, notImpersonating, s.requirePermission(accessentities.PermissionShopDelete), shopHandler.DeleteShop)
+
+	router.Get("/shops/:id/staffs", member, s.requirePermission(accessentities.PermissionStaffView), shopHandler.GetStaff)
+	router.Post("/shops/:id/staffs", member, noAPIKey, s.requirePermission(accessentities.PermissionStaffAssign), shopHandler.AssignStaff)
+	router.Post("/shops/:id/staffs/:userId/unlock", member, s.requirePermission(accessentities.PermissionStaffUnlock), accountLockHandler.UnlockStaff)
+	router.Patch("/shops/:id/staffs/:staffId", member, noAPIKey, staffHandler.UpdateStaff)
+	router.Delete("/shops/:id/staffs/:staffId", member, noAPIKey, s.requirePermission(accessentities.PermissionStaffRemove), staffHandler.RemoveStaff)
+	router.Post("/shops/:id/transfer-ownership", member, noAPIKey, notImpersonating, staffHandler.TransferOwnership)
+
+	router.Get("/shops/:id/roles", member, s.requirePermission(accessentities.PermissionStaffView), roleHandler.ListRoles)
+	router.Post("/shops/:id/roles", member, noAPIKey, s.requirePermission(accessentities.PermissionRoleManage), roleHandler.CreateRole)
+	router.Get("/shops/:id/roles/:roleId", member, s.requirePermission(accessentities.PermissionStaffView), roleHandler.GetRole)
+	router.Put("/shops/:id/roles/:roleId", member, noAPIKey, s.requirePermission(accessentities.PermissionRoleManage), roleHandler.UpdateRole)
+	router.Delete("/shops/:id/roles/:roleId", member, noAPIKey, s.requirePermission(accessentities.PermissionRoleManage), roleHandler.DeleteRole)
+
+	router.Get("/shops/:id/staffs/:staffId/access", member, s.requirePermission(accessentities.PermissionStaffView), accessHandler.GetAccess)
+	router.Post("/shops/:id/staffs/:staffId/access", member, noAPIKey, s.requirePermission(accessentities.PermissionStaffAssign), accessHandler.GrantAccess)
+	router.Delete("/shops/:id/staffs/:staffId/access", member, noAPIKey, s.requirePermission(accessentities.PermissionStaffAssign), accessHandler.RevokeAccess)
+	router.Put("/shops/:id/staffs/:staffId/locations", member, s.requirePermission(accessentities.PermissionStaffAssign), accessHandler.SetLocations)
+
+	apiKeyRepo := accessrepositories.NewAPIKeyRepository(s.db)
+	apiKeyHandler := handlers.NewAPIKeyHandler(
+		accessusecases.NewCreateAPIKeyUsecase(apiKeyRepo, s.authorizer),
+		accessusecases.NewListAPIKeysUsecase(apiKeyRepo),
+		accessusecases.NewRevokeAPIKeyUsecase(apiKeyRepo),
+	)
+
+	// API keys are long-lived credentials, so administrators impersonating a
+	// user cannot manage them either.
+	router.Get("/shops/:id/api-keys", member, noAPIKey, notImpersonating, s.requirePermission(accessentities.PermissionAPIKeyManage), apiKeyHandler.ListAPIKeys)
+	router.Post("/shops/:id/api-keys", member, noAPIKey, notImpersonating, s.requirePermission(accessentities.PermissionAPIKeyManage), apiKeyHandler.CreateAPIKey)
+	router.Delete("/shops/:id/api-keys/:apiKeyId", member, noAPIKey, notImpersonating, s.requirePermission(accessentities.PermissionAPIKeyManage), apiKeyHandler.RevokeAPIKey)
+}
+
+func (s *Server) setupInvitationRoutes(router fiber.Router) {
+	invitationRepo := accessrepositories.NewInvitationRepository(s.db)
+
+	inviteStaffUsecase := accessusecases.NewInviteStaffUsecase(
+		invitationRepo,
+		accessrepositories.NewRoleRepository(s.db),
+		accessrepositories.NewRolePermissionRepository(s.db),
+		accessrepositories.NewStaffRepository(s.db),
+		userrepositories.NewUserRepository(s.db),
+		s.authorizer,
+		s.mailer,
+		s.smsSender,
+		s.invitationExpiresIn,
+		s.config.InvitationURL,
+	)
+	listInvitationsUsecase := accessusecases.NewListInvitationsUsecase(invitationRepo)
+	listUserInvitationsUsecase := accessusecases.NewListUserInvitationsUsecase(invitationRepo)
+	revokeInvitationUsecase := accessusecases.NewRevokeInvitationUsecase(invitationRepo)
+	respondInvitationUsecase := accessusecases.NewRespondInvitationUsecase(s.db)
+
+	invitationHandler := handlers.NewInvitationHandler(inviteStaffUsecase, listInvitationsUsecase, listUserInvitationsUsecase, revokeInvitationUsecase, respondInvitationUsecase)
+
+	member := s.membershipMiddleware
+	noAPIKey := NewNoAPIKeyMiddleware()
+	router.Get("/shops/:id/invitations", member, s.requirePermission(accessentities.PermissionStaffView), invitationHandler.ListInvitations)
+	router.Post("/shops/:id/invitations", member, noAPIKey, s.requirePermission(accessentities.PermissionStaffAssign), invitationHandler.InviteStaff)
+	router.Delete("/shops/:id/invitations/:invitationId", member, noAPIKey, s.requirePermission(accessentities.PermissionStaffAssign), invitationHandler.RevokeInvitation)
+
+	router.Get("/me/invitations", invitationHandler.ListMyInvitations)
+	router.Post("/invitations/accept", invitationHandler.AcceptInvitation)
+	router.Post("/invitations/decline", invitationHandler.DeclineInvitation)
+}
+
+func (s *Server) setupCatalogRoutes(router fiber.Router) {
+	categoryRepo := catalogrepositories.NewCategoryRepository(s.db)
+	productRepo := catalogrepositories.NewProductRepository(s.db)
+
+	categoryHandler := handlers.NewCategoryHandler(
+		catalogusecases.NewListCategoriesUsecase(categoryRepo),
+		catalogusecases.NewCreateCategoryUsecase(categoryRepo),
+		catalogusecases.NewUpdateCategoryUsecase(categoryRepo),
+		catalogusecases.NewDeleteCategoryUsecase(s.db),
+	)
+
+	productHandler := handlers.NewProductHandler(
+		catalogusecases.NewListProductsUsecase(productRepo),
+		catalogusecases.NewGetProductUsecase(productRepo),
+		catalogusecases.NewCreateProductUsecase(productRepo, categoryRepo),
+		catalogusecases.NewUpdateProductUsecase(s.db),
+		catalogusecases.NewDeleteProductUsecase(productRepo),
+	)
+
+	// Every member of the shop can browse the catalog, for instance to ring up
+	// sales; changing it takes catalog.manage.
+	member := s.membershipMiddleware
+	router.Get("/shops/:id/categories", member, categoryHandler.ListCategories)
+	router.Post("/shops/:id/categories", member, s.requirePermission(accessentities.PermissionCatalogManage), categoryHandler.CreateCategory)
+	router.Put("/shops/:id/categories/:categoryId", member, s.requirePermission(accessentities.PermissionCatalogManage), categoryHandler.UpdateCategory)
+	router.Delete("/shops/:id/categories/:categoryId", member, s.requirePermission(accessentities.PermissionCatalogManage), categoryHandler.DeleteCategory)
+
+	router.Get("/shops/:id/products", member, productHandler.ListProducts)
+	router.Post("/shops/:id/products", member, s.requirePermission(accessentities.PermissionCatalogManage), productHandler.CreateProduct)
+	router.Get("/shops/:id/products/:productId", member, productHandler.GetProduct)
+	router.Put("/shops/:id/products/:productId", member, s.requirePermission(accessentities.PermissionCatalogManage), productHandler.UpdateProduct)
+	router.Delete("/shops/:id/products/:productId", member, s.requirePermission(accessentities.PermissionCatalogManage), productHandler.DeleteProduct)
+}
+
+func (s *Server) setupInventoryRoutes(router fiber.Router) {
+	locationRepo := shoprepositories.NewLocationRepository(s.db)
+	settingsRepo := inventoryrepositories.NewSettingsRepository(s.db)
+
+	locationHandler := handlers.NewLocationHandler(
+		shopusecases.NewListLocationsUsecase(locationRepo),
+		shopusecases.NewCreateLocationUsecase(locationRepo),
+		shopusecases.NewUpdateLocationUsecase(locationRepo),
+	)
+
+	inventoryHandler := handlers.NewInventoryHandler(
+		inventoryusecases.NewListStockLevelsUsecase(inventoryrepositories.NewStockLevelRepository(s.db)),
+		inventoryusecases.NewListStockMovementsUsecase(inventoryrepositories.NewStockMovementRepository(s.db)),
+		inventoryusecases.NewRecordMovementUsecase(s.db),
+		inventoryusecases.NewTransferStockUsecase(s.db),
+		inventoryusecases.NewGetInventorySettingsUsecase(settingsRepo),
+		inventoryusecases.NewUpdateInventorySettingsUsecase(settingsRepo),
+	)
+
+	// Locations are part of the shop's setup, as is its negative stock
+	// policy; both are changed with shop.update.
+	member := s.membershipMiddleware
+	router.Get("/shops/:id/locations", member, locationHandler.ListLocations)
+	router.Post("/shops/:id/locations", member, s.requirePermission(accessentities.PermissionShopUpdate), locationHandler.CreateLocation)
+	router.Put("/shops/:id/locations/:locationId", member, s.requirePermission(accessentities.PermissionShopUpdate), locationHandler.UpdateLocation)
+
+	router.Get("/shops/:id/inventory/levels", member, s.requirePermission(accessentities.PermissionInventoryView), inventoryHandler.ListStockLevels)
+	router.Get("/shops/:id/inventory/movements", member, s.requirePermission(accessentities.PermissionInventoryView), inventoryHandler.ListStockMovements)
+	router.Post("/shops/:id/inventory/movements", member, s.requirePermission(accessentities.PermissionInventoryAdjust), inventoryHandler.RecordStockMovement)
+	router.Post("/shops/:id/inventory/transfers", member, s.requirePermission(accessentities.PermissionInventoryAdjust), inventoryHandler.TransferStock)
+	router.Get("/shops/:id/inventory/settings", member, s.requirePermission(accessentities.PermissionInventoryView), inventoryHandler.GetInventorySettings)
+	router.Put("/shops/:id/inventory/settings", member, s.requirePermission(accessentities.PermissionShopUpdate), inventoryHandler.UpdateInventorySettings)
+}
+
+func (s *Server) setupSalesRoutes(router fiber.Router) {
+	locationRepo := shoprepositories.NewLocationRepository(s.db)
+	cartRepo := salesrepositories.NewCartRepository(s.db)
+	saleRepo := salesrepositories.NewSaleRepository(s.db)
+	sessionRepo := salesrepositories.NewRegisterSessionRepository(s.db)
+
+	cartHandler := handlers.NewCartHandler(
+		salesusecases.NewCreateCartUsecase(cartRepo, locationRepo),
+		salesusecases.NewGetCartUsecase(cartRepo),
+		salesusecases.NewUpdateCartUsecase(cartRepo),
+		salesusecases.NewDeleteCartUsecase(cartRepo),
+		salesusecases.NewAddCartLineUsecase(cartRepo, catalogrepositories.NewProductRepository(s.db)),
+		salesusecases.NewUpdateCartLineUsecase(cartRepo),
+		salesusecases.NewRemoveCartLineUsecase(cartRepo),
+		salesusecases.NewCheckoutCartUsecase(s.db),
+	)
+
+	saleHandler := handlers.NewSaleHandler(
+		salesusecases.NewListSalesUsecase(saleRepo),
+		salesusecases.NewGetSaleUsecase(saleRepo),
+	)
+
+	registerSessionHandler := handlers.NewRegisterSessionHandler(
+		salesusecases.NewOpenRegisterSessionUsecase(sessionRepo, locationRepo),
+		salesusecases.NewListRegisterSessionsUsecase(sessionRepo),
+		salesusecases.NewGetRegisterSessionUsecase(sessionRepo),
+		salesusecases.NewRecordCashMovementUsecase(s.db),
+		salesusecases.NewCloseRegisterSessionUsecase(s.db),
+		salesusecases.NewGetRegisterReportUsecase(sessionRepo, saleRepo),
+	)
+
+	// Whoever rings up sales also runs the register and can look sales up
+	// again, for instance to reprint a receipt. The report of a register
+	// session takes reports.view.
+	member := s.membershipMiddleware
+	router.Post("/shops/:id/carts", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.CreateCart)
+	router.Get("/shops/:id/carts/:cartId", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.GetCart)
+	router.Put("/shops/:id/carts/:cartId", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.UpdateCart)
+	router.Delete("/shops/:id/carts/:cartId", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.DeleteCart)
+	router.Post("/shops/:id/carts/:cartId/lines", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.AddCartLine)
+	router.Put("/shops/:id/carts/:cartId/lines/:lineId", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.UpdateCartLine)
+	router.Delete("/shops/:id/carts/:cartId/lines/:lineId", member, s.requirePermission(accessentities.PermissionSalesCreate), cartHandler.RemoveCartLine)
+	router.Post("/shops/:id/carts/:cartId/checkout", member, s.requirePermission(accessentities.PermissionSalesCreate), s.idempotencyMiddleware, cartHandler.CheckoutCart)
+
+	router.Get("/shops/:id/sales", member, s.requirePermission(accessentities.PermissionSalesCreate), saleHandler.ListSales)
+	router.Get("/shops/:id/sales/:saleId", member, s.requirePermission(accessentities.PermissionSalesCreate), saleHandler.GetSale)
+
+	router.Post("/shops/:id/register-sessions", member, s.requirePermission(accessentities.PermissionSalesCreate), registerSessionHandler.OpenRegisterSession)
+	router.Get("/shops/:id/register-sessions", member, s.requirePermission(accessentities.PermissionSalesCreate), registerSessionHandler.ListRegisterSessions)
+	router.Get("/shops/:id/register-sessions/:sessionId", member, s.requirePermission(accessentities.PermissionSalesCreate), registerSessionHandler.GetRegisterSession)
+	router.Post("/shops/:id/register-sessions/:sessionId/cash-movements", member, s.requirePermission(accessentities.PermissionSalesCreate), registerSessionHandler.RecordCashMovement)
+	router.Post("/shops/:id/register-sessions/:sessionId/close", member, s.requirePermission(accessentities.PermissionSalesCreate), registerSessionHandler.CloseRegisterSession)
+	router.Get("/shops/:id/register-sessions/:sessionId/report", member, s.requirePermission(accessentities.PermissionReportsView), registerSessionHandler.GetRegisterReport)
+}
+
+// setupAdminRoutes registers the platform administration endpoints. They
+// are not tied to a shop, so the admin middleware replaces the membership
+// and permission checks.
+func (s *Server) setupAdminRoutes(router fiber.Router) {
+	userRepo := userrepositories.NewUserRepository(s.db)
+	adminAuditLogRepo := adminrepositories.NewAdminAuditLogRepository(s.db)
+
+	adminHandler := handlers.NewAdminHandler(
+		shopusecases.NewListAllShopsUsecase(shoprepositories.NewShopRepository(s.db)),
+		adminusecases.NewSearchUsersUsecase(userRepo),
+		adminusecases.NewUpdateUserSuspensionUsecase(s.db),
+		adminusecases.NewUpdateShopSuspensionUsecase(s.db),
+		adminusecases.NewImpersonateUserUsecase(userRepo, adminAuditLogRepo, s.tokenService),
+		adminusecases.NewListAuditLogsUsecase(adminAuditLogRepo),
+	)
+
+	admin := NewAdminMiddleware(userRepo)
+	router.Get("/admin/users", admin, adminHandler.SearchUsers)
+	router.Post("/admin/users/:id/suspend", admin, adminHandler.SuspendUser)
+	router.Post("/admin/users/:id/restore", admin, adminHandler.RestoreUser)
+	router.Post("/admin/users/:id/impersonate", admin, adminHandler.ImpersonateUser)
+	router.Get("/admin/shops", admin, adminHandler.ListShops)
+	router.Post("/admin/shops/:id/suspend", admin, adminHandler.SuspendShop)
+	router.Post("/admin/shops/:id/restore", admin, adminHandler.RestoreShop)
+	router.Get("/admin/audit-logs", admin, adminHandler.ListAuditLogs)
+}
 
-	s.app.Get("/api/shops/:id/staffs", shopHandler.GetStaff)
-	s.app.Post("/api/shops/:id/staffs", shopHandler.AssignStaff)
+// requirePermission must follow s.membershipMiddleware in a route.
+func (s *Server) requirePermission(permission accessentities.Permission) fiber.Handler {
+	return NewPermissionMiddleware(s.authorizer, permission)
 }
 
 func (s *Server) setupSwaggerRoutes() {
@@ -189,10 +607,96 @@ func (s *Server) Stop() error {
 	return s.app.Shutdown()
 }
 
+// newLoginThrottle builds the login throttle from the LOGIN_* settings.
+func newLoginThrottle(config *config.Config, db *gorm.DB) (*services.LoginThrottleService, error) {
+	var store authrepositories.LoginAttemptStore
+	switch config.LoginAttemptStore {
+	case "", "database":
+		store = authrepositories.NewLoginAttemptStore(db)
+	case "memory":
+		store = authrepositories.NewInMemoryLoginAttemptStore()
+	default:
+		return nil, fmt.Errorf("unsupported login attempt store %q", config.LoginAttemptStore)
+	}
+
+	lockout, err := parseDuration(config.LoginLockoutDuration, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid login lockout duration: %w", err)
+	}
+
+	maxLockout, err := parseDuration(config.LoginMaxLockoutDuration, 15*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid login max lockout duration: %w", err)
+	}
+
+	window, err := parseDuration(config.LoginAttemptWindow, time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("invalid login attempt window: %w", err)
+	}
+
+	policy := services.LoginThrottlePolicy{
+		MaxAttempts:        config.LoginMaxAttempts,
+		MaxAttemptsPerIP:   config.LoginMaxAttemptsPerIP,
+		LockoutDuration:    lockout,
+		MaxLockoutDuration: maxLockout,
+		Window:             window,
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = 5
+	}
+	if policy.MaxAttemptsPerIP == 0 {
+		policy.MaxAttemptsPerIP = 50
+	}
+
+	return services.NewLoginThrottleService(store, policy), nil
+}
+
+// idempotencyStorageFor picks where the idempotency middleware keeps
+// responses from IDEMPOTENCY_STORE. A nil storage keeps them in process.
+func idempotencyStorageFor(config *config.Config, db *gorm.DB) (fiber.Storage, error) {
+	switch config.IdempotencyStore {
+	case "", "database":
+		return newIdempotencyStorage(db), nil
+	case "memory":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported idempotency store %q", config.IdempotencyStore)
+	}
+}
+
+// parseDuration parses a duration setting, falling back to a default when unset.
+func parseDuration(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// parseTrustedProxies splits a comma-separated list of proxy IP addresses and
+// CIDR ranges, rejecting entries that are neither.
+func parseTrustedProxies(value string) ([]string, error) {
+	proxies := []string{}
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("%q is not an IP address or CIDR range", proxy)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
 func (s *Server) App() *fiber.App {
 	return s.app
 }
 
+func (s *Server) Mailer() mail.Mailer {
+	return s.mailer
+}
+
 func (s *Server) getCorsConfig() cors.Config {
 	allowedOrigins := s.parseCorsOrigins()
 	allowCredentials := true
@@ -204,7 +708,7 @@ func (s *Server) getCorsConfig() cors.Config {
 	return cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", idempotencyKeyHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: allowCredentials,
 		MaxAge:           3600,
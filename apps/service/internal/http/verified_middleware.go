@@ -0,0 +1,36 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	userrepositories "github.com/reno1r/weiss/apps/service/internal/app/user/repositories"
+	"github.com/reno1r/weiss/apps/service/internal/http/handlers"
+)
+
+// Values of VERIFICATION_REQUIRED_FOR.
+const (
+	verificationRequiredForLogin        = "login"
+	verificationRequiredForShopCreation = "shop_creation"
+)
+
+// NewVerifiedUserMiddleware rejects authenticated users that have not
+// verified their email or phone yet. It must run after the auth middleware.
+func NewVerifiedUserMiddleware(userRepository userrepositories.UserRepository) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		principal, err := handlers.GetPrincipal(c)
+		if err != nil {
+			return err
+		}
+
+		user, err := userRepository.FindByID(c.Context(), principal.UserID)
+		if err != nil {
+			return unauthorized(c, "user not found")
+		}
+
+		if !user.IsVerified() {
+			return fiber.NewError(fiber.StatusForbidden, "account not verified: verify your email or phone first")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+const (
+	DriverSMTP   = "smtp"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email such as password reset links.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailer creates the mailer selected by MAIL_DRIVER. Leaving it unset only
+// falls back to the file driver in debug mode, so development setups never
+// send real email by accident while a deployment never parks mail on disk.
+func NewMailer(config *config.Config) (Mailer, error) {
+	driver := config.MailDriver
+	if driver == "" {
+		if !config.AppDebug {
+			return nil, errors.New("MAIL_DRIVER is required unless APP_DEBUG is set")
+		}
+		driver = DriverFile
+	}
+
+	switch driver {
+	case DriverSMTP:
+		return NewSMTPMailer(config)
+	case DriverFile:
+		return NewFileMailer(config.MailFileDir, config.MailFrom), nil
+	case DriverMemory:
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", config.MailDriver)
+	}
+}
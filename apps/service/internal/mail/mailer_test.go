@@ -0,0 +1,115 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+func TestNewMailer(t *testing.T) {
+	t.Run("defaults to the file driver in debug mode", func(t *testing.T) {
+		mailer, err := NewMailer(&config.Config{AppDebug: true})
+		require.NoError(t, err)
+		assert.IsType(t, &FileMailer{}, mailer)
+	})
+
+	t.Run("requires a driver outside debug mode", func(t *testing.T) {
+		_, err := NewMailer(&config.Config{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "MAIL_DRIVER is required")
+
+		mailer, err := NewMailer(&config.Config{MailDriver: DriverFile})
+		require.NoError(t, err)
+		assert.IsType(t, &FileMailer{}, mailer)
+	})
+
+	t.Run("creates memory mailer", func(t *testing.T) {
+		mailer, err := NewMailer(&config.Config{MailDriver: DriverMemory})
+		require.NoError(t, err)
+		assert.IsType(t, &MemoryMailer{}, mailer)
+	})
+
+	t.Run("creates SMTP mailer", func(t *testing.T) {
+		mailer, err := NewMailer(&config.Config{
+			MailDriver: DriverSMTP,
+			MailFrom:   "no-reply@example.com",
+			SmtpHost:   "smtp.example.com",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "smtp.example.com:587", mailer.(*SMTPMailer).addr)
+	})
+
+	t.Run("returns error when SMTP host is missing", func(t *testing.T) {
+		_, err := NewMailer(&config.Config{MailDriver: DriverSMTP, MailFrom: "no-reply@example.com"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "SMTP host is required")
+	})
+
+	t.Run("returns error for unsupported driver", func(t *testing.T) {
+		_, err := NewMailer(&config.Config{MailDriver: "carrier-pigeon"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported mail driver")
+	})
+}
+
+func TestMemoryMailer(t *testing.T) {
+	t.Run("records sent messages", func(t *testing.T) {
+		mailer := NewMemoryMailer()
+
+		require.NoError(t, mailer.Send(context.Background(), Message{To: "a@example.com", Subject: "first"}))
+		require.NoError(t, mailer.Send(context.Background(), Message{To: "b@example.com", Subject: "second"}))
+		require.NoError(t, mailer.Send(context.Background(), Message{To: "a@example.com", Subject: "third"}))
+
+		assert.Len(t, mailer.Messages(), 3)
+
+		message, ok := mailer.LastMessageTo("a@example.com")
+		require.True(t, ok)
+		assert.Equal(t, "third", message.Subject)
+
+		_, ok = mailer.LastMessageTo("c@example.com")
+		assert.False(t, ok)
+	})
+}
+
+func TestFileMailer(t *testing.T) {
+	t.Run("writes message to directory", func(t *testing.T) {
+		dir := t.TempDir()
+		mailer := NewFileMailer(dir, "no-reply@example.com")
+
+		err := mailer.Send(context.Background(), Message{
+			To:      "john@example.com",
+			Subject: "Reset your password",
+			Body:    "line one\nline two",
+		})
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.True(t, strings.HasSuffix(entries[0].Name(), ".eml"))
+
+		data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "To: john@example.com\r\n")
+		assert.Contains(t, string(data), "Subject: Reset your password\r\n")
+		assert.Contains(t, string(data), "line one\r\nline two")
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	t.Run("strips line breaks from headers", func(t *testing.T) {
+		data := string(buildMessage("no-reply@example.com", Message{
+			To:      "john@example.com\r\nBcc: attacker@example.com",
+			Subject: "Hello",
+		}))
+
+		assert.NotContains(t, data, "\r\nBcc:")
+	})
+}
@@ -0,0 +1,81 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+type SMTPMailer struct {
+	addr string
+	host string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(config *config.Config) (*SMTPMailer, error) {
+	if config.SmtpHost == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+
+	if config.MailFrom == "" {
+		return nil, errors.New("mail sender address is required")
+	}
+
+	port := config.SmtpPort
+	if port == 0 {
+		port = 587
+	}
+
+	var auth smtp.Auth
+	if config.SmtpUsername != "" {
+		auth = smtp.PlainAuth("", config.SmtpUsername, config.SmtpPassword, config.SmtpHost)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(config.SmtpHost, strconv.Itoa(port)),
+		host: config.SmtpHost,
+		from: config.MailFrom,
+		auth: auth,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{message.To}, buildMessage(m.from, message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage renders an RFC 5322 message. Header values are stripped of line
+// breaks so that user supplied values cannot inject extra headers.
+func buildMessage(from string, message Message) []byte {
+	var builder strings.Builder
+
+	builder.WriteString("From: " + sanitizeHeader(from) + "\r\n")
+	builder.WriteString("To: " + sanitizeHeader(message.To) + "\r\n")
+	builder.WriteString("Subject: " + sanitizeHeader(message.Subject) + "\r\n")
+	builder.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(builder.String())
+}
+
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,54 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Cursor marks the last item of a page in keyset pagination: the value of
+// the sort key and the ID breaking ties between equal values. Sort records
+// the order the cursor was issued for, so it cannot be replayed against
+// another one.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint64 `json:"id"`
+}
+
+// Encode returns the cursor as an opaque, URL-safe string.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a string returned by Encode.
+func Decode(value string) (Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return cursor, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// Limit returns the page size to use for a requested limit, falling back
+// to DefaultLimit and capping it at MaxLimit.
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
@@ -0,0 +1,32 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round trips through Encode and Decode", func(t *testing.T) {
+		cursor := Cursor{Sort: "name", Value: "Corner Shop", ID: 42}
+
+		decoded, err := Decode(cursor.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	})
+
+	t.Run("rejects malformed cursors", func(t *testing.T) {
+		for _, value := range []string{"not a cursor!", "bm90IGpzb24", Cursor{Sort: "name"}.Encode()} {
+			_, err := Decode(value)
+			assert.EqualError(t, err, "invalid cursor", value)
+		}
+	})
+}
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, Limit(0))
+	assert.Equal(t, DefaultLimit, Limit(-5))
+	assert.Equal(t, 10, Limit(10))
+	assert.Equal(t, MaxLimit, Limit(MaxLimit+1))
+}
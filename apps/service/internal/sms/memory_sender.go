@@ -0,0 +1,42 @@
+package sms
+
+import (
+	"context"
+	"sync"
+)
+
+// MemorySender keeps sent messages in memory so tests can inspect them.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+// Delivers is true; tests read the messages back with LastMessageTo.
+func (s *MemorySender) Delivers() bool {
+	return true
+}
+
+// LastMessageTo returns the most recent message sent to the given number.
+func (s *MemorySender) LastMessageTo(to string) (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if s.messages[i].To == to {
+			return s.messages[i], true
+		}
+	}
+	return Message{}, false
+}
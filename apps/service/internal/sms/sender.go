@@ -0,0 +1,65 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+const (
+	DriverLog    = "log"
+	DriverMemory = "memory"
+)
+
+// Message is a plain text SMS.
+type Message struct {
+	To   string
+	Body string
+}
+
+// ErrUnavailable is the reason given when a phone flow is refused because the
+// configured sender does not deliver.
+var ErrUnavailable = errors.New("phone channel unavailable: SMS delivery is not configured")
+
+// Sender delivers text messages such as phone verification codes. No carrier
+// integration exists yet. The log driver writes bodies to the application log
+// in debug mode so phone flows can be exercised in development; elsewhere it
+// reaches nobody, and callers refuse the phone channel rather than report a
+// code as sent.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+	// Delivers reports whether sent messages reach someone who can read them.
+	Delivers() bool
+}
+
+// RequireDelivery returns ErrUnavailable when the sender does not deliver.
+func RequireDelivery(sender Sender) error {
+	if !sender.Delivers() {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// NewSender creates the sender selected by SMS_DRIVER. Leaving it unset only
+// falls back to the log driver in debug mode; elsewhere it is a
+// configuration error, so a deployment never drops messages silently.
+func NewSender(config *config.Config) (Sender, error) {
+	driver := config.SmsDriver
+	if driver == "" {
+		if !config.AppDebug {
+			return nil, errors.New("SMS_DRIVER is required unless APP_DEBUG is set")
+		}
+		driver = DriverLog
+	}
+
+	switch driver {
+	case DriverLog:
+		return NewLogSender(config.AppDebug), nil
+	case DriverMemory:
+		return NewMemorySender(), nil
+	default:
+		return nil, fmt.Errorf("unsupported SMS driver %q", config.SmsDriver)
+	}
+}
@@ -0,0 +1,78 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/reno1r/weiss/apps/service/internal/config"
+)
+
+func TestNewSender(t *testing.T) {
+	t.Run("defaults to the log driver in debug mode", func(t *testing.T) {
+		sender, err := NewSender(&config.Config{AppDebug: true})
+		require.NoError(t, err)
+		assert.IsType(t, &LogSender{}, sender)
+		assert.True(t, sender.Delivers())
+	})
+
+	t.Run("requires a driver outside debug mode", func(t *testing.T) {
+		_, err := NewSender(&config.Config{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "SMS_DRIVER is required")
+
+		sender, err := NewSender(&config.Config{SmsDriver: DriverLog})
+		require.NoError(t, err)
+		assert.IsType(t, &LogSender{}, sender)
+		assert.False(t, sender.Delivers())
+		assert.ErrorIs(t, RequireDelivery(sender), ErrUnavailable)
+	})
+
+	t.Run("returns error for unsupported driver", func(t *testing.T) {
+		_, err := NewSender(&config.Config{SmsDriver: "fax"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported SMS driver")
+	})
+}
+
+func TestLogSender(t *testing.T) {
+	t.Run("logs the recipient but not the body", func(t *testing.T) {
+		var output bytes.Buffer
+		sender := &LogSender{logger: log.New(&output, "", 0)}
+
+		require.NoError(t, sender.Send(context.Background(), Message{To: "1234567890", Body: "Your code is 493817"}))
+
+		assert.Equal(t, "sms to 1234567890: 19 characters\n", output.String())
+		assert.NotContains(t, output.String(), "493817")
+	})
+
+	t.Run("logs the body in debug mode", func(t *testing.T) {
+		var output bytes.Buffer
+		sender := &LogSender{logger: log.New(&output, "", 0), debug: true}
+
+		require.NoError(t, sender.Send(context.Background(), Message{To: "1234567890", Body: "Your code is 493817"}))
+
+		assert.Equal(t, "sms to 1234567890: Your code is 493817\n", output.String())
+		assert.NoError(t, RequireDelivery(sender))
+	})
+}
+
+func TestMemorySender(t *testing.T) {
+	t.Run("records sent messages", func(t *testing.T) {
+		sender := NewMemorySender()
+
+		require.NoError(t, sender.Send(context.Background(), Message{To: "1234567890", Body: "first"}))
+		require.NoError(t, sender.Send(context.Background(), Message{To: "1234567890", Body: "second"}))
+
+		message, ok := sender.LastMessageTo("1234567890")
+		require.True(t, ok)
+		assert.Equal(t, "second", message.Body)
+
+		_, ok = sender.LastMessageTo("0987654321")
+		assert.False(t, ok)
+	})
+}
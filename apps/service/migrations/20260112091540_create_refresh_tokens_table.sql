@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE refresh_tokens(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  token_id VARCHAR(64) NOT NULL,
+  family_id BIGINT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMP NOT NULL,
+  rotated_at TIMESTAMP,
+  replaced_by VARCHAR(64) NOT NULL DEFAULT '',
+  revoked_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_refresh_tokens_token_id ON refresh_tokens(token_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE refresh_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE users
+  ADD COLUMN email_verified_at TIMESTAMP,
+  ADD COLUMN phone_verified_at TIMESTAMP;
+
+CREATE TABLE verification_codes(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  channel VARCHAR(16) NOT NULL,
+  target VARCHAR(255) NOT NULL,
+  code_hash VARCHAR(64) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  expires_at TIMESTAMP NOT NULL,
+  consumed_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_verification_codes_user_channel ON verification_codes(user_id, channel);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE verification_codes;
+
+ALTER TABLE users
+  DROP COLUMN email_verified_at,
+  DROP COLUMN phone_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE login_attempts(
+  subject VARCHAR(320) PRIMARY KEY,
+  failures INT NOT NULL DEFAULT 0,
+  last_failed_at TIMESTAMP NOT NULL,
+  locked_until TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE login_attempts;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE role_permissions(
+  role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  permission VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (role_id, permission)
+);
+
+-- Owner roles of existing shops get the whole catalog, as new shops do.
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, catalog.permission
+FROM roles
+CROSS JOIN (VALUES
+  ('shop.update'),
+  ('shop.delete'),
+  ('staff.view'),
+  ('staff.assign'),
+  ('staff.remove'),
+  ('staff.unlock'),
+  ('role.manage'),
+  ('catalog.manage'),
+  ('inventory.view'),
+  ('inventory.adjust'),
+  ('sales.create'),
+  ('reports.view')
+) AS catalog(permission)
+WHERE roles.name = 'Owner' AND roles.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE role_permissions;
+-- +goose StatementEnd
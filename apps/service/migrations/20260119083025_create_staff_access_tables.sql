@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE staff_permissions(
+  staff_id BIGINT NOT NULL REFERENCES staffs(id) ON DELETE CASCADE,
+  permission VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (staff_id, permission)
+);
+
+CREATE TABLE access_audit_logs(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  staff_id BIGINT NOT NULL REFERENCES staffs(id) ON DELETE CASCADE,
+  actor_id BIGINT NOT NULL REFERENCES users(id),
+  action VARCHAR(20) NOT NULL,
+  permission VARCHAR(100),
+  role_id BIGINT REFERENCES roles(id) ON DELETE SET NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_access_audit_logs_staff_id ON access_audit_logs(staff_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE access_audit_logs;
+DROP TABLE staff_permissions;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE staffs ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE staffs DROP COLUMN status;
+-- +goose StatementEnd
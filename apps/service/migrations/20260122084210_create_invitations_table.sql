@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE invitations(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  inviter_id BIGINT NOT NULL REFERENCES users(id),
+  user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+  email VARCHAR(255) NOT NULL DEFAULT '',
+  phone VARCHAR(20) NOT NULL DEFAULT '',
+  token_hash VARCHAR(64) NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  responded_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_invitations_token_hash ON invitations(token_hash);
+CREATE INDEX idx_invitations_shop_id ON invitations(shop_id);
+CREATE INDEX idx_invitations_user_id ON invitations(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE invitations;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMP;
+ALTER TABLE shops ADD COLUMN suspended_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE shops DROP COLUMN suspended_at;
+ALTER TABLE users DROP COLUMN suspended_at;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE admin_audit_logs(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  admin_id BIGINT NOT NULL REFERENCES users(id),
+  action VARCHAR(20) NOT NULL,
+  target_type VARCHAR(20) NOT NULL,
+  target_id BIGINT NOT NULL,
+  reason TEXT NOT NULL,
+  token_id VARCHAR(64) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_admin_audit_logs_target ON admin_audit_logs(target_type, target_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE admin_audit_logs;
+-- +goose StatementEnd
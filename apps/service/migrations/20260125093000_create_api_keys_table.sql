@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE api_keys(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  prefix VARCHAR(32) NOT NULL,
+  key_hash VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP,
+  last_used_at TIMESTAMP,
+  revoked_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys(prefix);
+CREATE INDEX idx_api_keys_shop_id ON api_keys(shop_id);
+
+CREATE TABLE api_key_permissions(
+  api_key_id BIGINT NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+  permission VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (api_key_id, permission)
+);
+
+-- Owner roles hold the whole catalog, including the new permission.
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'api_key.manage'
+FROM roles
+WHERE roles.is_system AND roles.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DELETE FROM role_permissions WHERE permission = 'api_key.manage';
+DROP TABLE api_key_permissions;
+DROP TABLE api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,110 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- The service sets app.current_shop_id and app.current_user_id for the
+-- transactions of shop requests when DB_ROW_LEVEL_SECURITY is on. Without
+-- them, as for migrations, background work and requests that are not about a
+-- single shop, the policies below let every row through.
+CREATE FUNCTION app_current_shop_id() RETURNS BIGINT
+LANGUAGE SQL STABLE AS $$
+  SELECT NULLIF(current_setting('app.current_shop_id', true), '')::BIGINT
+$$;
+
+CREATE FUNCTION app_current_user_id() RETURNS BIGINT
+LANGUAGE SQL STABLE AS $$
+  SELECT NULLIF(current_setting('app.current_user_id', true), '')::BIGINT
+$$;
+
+-- FORCE makes the policies apply to the table owner too, which is usually
+-- the role the service connects as.
+ALTER TABLE shops ENABLE ROW LEVEL SECURITY;
+ALTER TABLE shops FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON shops
+  USING (app_current_shop_id() IS NULL OR id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR id = app_current_shop_id());
+
+ALTER TABLE roles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE roles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON roles
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE staffs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE staffs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON staffs
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE invitations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invitations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON invitations
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE access_audit_logs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE access_audit_logs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON access_audit_logs
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+-- Tables without a shop_id belong to the shop of their parent row, which
+-- the parent's own policy already limits to the current shop.
+ALTER TABLE role_permissions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE role_permissions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON role_permissions
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM roles WHERE roles.id = role_permissions.role_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM roles WHERE roles.id = role_permissions.role_id));
+
+ALTER TABLE staff_permissions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE staff_permissions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON staff_permissions
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM staffs WHERE staffs.id = staff_permissions.staff_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM staffs WHERE staffs.id = staff_permissions.staff_id));
+
+ALTER TABLE api_key_permissions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_key_permissions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_key_permissions
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM api_keys WHERE api_keys.id = api_key_permissions.api_key_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM api_keys WHERE api_keys.id = api_key_permissions.api_key_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP POLICY tenant_isolation ON api_key_permissions;
+ALTER TABLE api_key_permissions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE api_key_permissions DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON staff_permissions;
+ALTER TABLE staff_permissions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE staff_permissions DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON role_permissions;
+ALTER TABLE role_permissions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE role_permissions DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON api_keys;
+ALTER TABLE api_keys NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE api_keys DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON access_audit_logs;
+ALTER TABLE access_audit_logs NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE access_audit_logs DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON invitations;
+ALTER TABLE invitations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE invitations DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON staffs;
+ALTER TABLE staffs NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE staffs DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON roles;
+ALTER TABLE roles NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE roles DISABLE ROW LEVEL SECURITY;
+DROP POLICY tenant_isolation ON shops;
+ALTER TABLE shops NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE shops DISABLE ROW LEVEL SECURITY;
+DROP FUNCTION app_current_user_id();
+DROP FUNCTION app_current_shop_id();
+-- +goose StatementEnd
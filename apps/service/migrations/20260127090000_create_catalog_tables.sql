@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE categories(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  parent_id BIGINT REFERENCES categories(id) ON DELETE RESTRICT,
+  name VARCHAR(100) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_categories_shop_id ON categories(shop_id);
+
+CREATE TABLE products(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL,
+  name VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  options JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_products_shop_id ON products(shop_id);
+CREATE INDEX idx_products_category_id ON products(category_id);
+CREATE INDEX idx_products_deleted_at ON products(deleted_at);
+
+-- Variants are soft deleted with their product, so SKUs only need to be
+-- unique among the live ones.
+CREATE TABLE product_variants(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  sku VARCHAR(64) NOT NULL,
+  barcode VARCHAR(64) NOT NULL DEFAULT '',
+  price BIGINT NOT NULL DEFAULT 0,
+  options JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_product_variants_shop_id_sku ON product_variants(shop_id, sku) WHERE deleted_at IS NULL;
+CREATE INDEX idx_product_variants_shop_id_barcode ON product_variants(shop_id, barcode);
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+CREATE INDEX idx_product_variants_deleted_at ON product_variants(deleted_at);
+
+ALTER TABLE categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE categories FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON categories
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE products FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON products
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE product_variants ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_variants FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON product_variants
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE product_variants;
+DROP TABLE products;
+DROP TABLE categories;
+-- +goose StatementEnd
@@ -0,0 +1,100 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE locations(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_locations_shop_id_name ON locations(shop_id, name);
+
+-- New shops get their Main location when they are created; existing ones
+-- get it here.
+INSERT INTO locations(shop_id, name)
+SELECT id, 'Main' FROM shops WHERE deleted_at IS NULL;
+
+CREATE TABLE inventory_settings(
+  shop_id BIGINT PRIMARY KEY REFERENCES shops(id) ON DELETE CASCADE,
+  allow_negative_stock BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE stock_levels(
+  location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+  variant_id BIGINT NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  on_hand BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (location_id, variant_id)
+);
+
+CREATE INDEX idx_stock_levels_shop_id ON stock_levels(shop_id);
+
+-- The ledger keeps its rows for good: nothing cascades into it, and the
+-- trigger below refuses to change or remove them.
+CREATE TABLE stock_movements(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id),
+  location_id BIGINT NOT NULL REFERENCES locations(id),
+  variant_id BIGINT NOT NULL REFERENCES product_variants(id),
+  type VARCHAR(20) NOT NULL,
+  quantity BIGINT NOT NULL,
+  balance_after BIGINT NOT NULL,
+  transfer_location_id BIGINT REFERENCES locations(id),
+  reference VARCHAR(100) NOT NULL DEFAULT '',
+  note TEXT NOT NULL DEFAULT '',
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_shop_id ON stock_movements(shop_id);
+CREATE INDEX idx_stock_movements_location_id_variant_id ON stock_movements(location_id, variant_id);
+
+CREATE FUNCTION stock_movements_append_only() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'stock movements cannot be changed or deleted';
+END;
+$$;
+
+CREATE TRIGGER stock_movements_append_only
+  BEFORE UPDATE OR DELETE ON stock_movements
+  FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+ALTER TABLE locations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE locations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON locations
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE inventory_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory_settings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON inventory_settings
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE stock_levels ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_levels FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON stock_levels
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE stock_movements ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_movements FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON stock_movements
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE stock_movements;
+DROP FUNCTION stock_movements_append_only();
+DROP TABLE stock_levels;
+DROP TABLE inventory_settings;
+DROP TABLE locations;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE locations
+  ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'outlet',
+  ADD COLUMN address VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN phone VARCHAR(20) NOT NULL DEFAULT '';
+
+-- Main locations stand at the shop's own address, as they do for new shops.
+UPDATE locations
+SET address = shops.address, phone = shops.phone
+FROM shops
+WHERE shops.id = locations.shop_id AND locations.name = 'Main';
+
+-- Staff with no rows here work at every location of their shop.
+CREATE TABLE staff_locations(
+  staff_id BIGINT NOT NULL REFERENCES staffs(id) ON DELETE CASCADE,
+  location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (staff_id, location_id)
+);
+
+CREATE INDEX idx_staff_locations_location_id ON staff_locations(location_id);
+
+ALTER TABLE staff_locations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE staff_locations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON staff_locations
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM staffs WHERE staffs.id = staff_locations.staff_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM staffs WHERE staffs.id = staff_locations.staff_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE staff_locations;
+ALTER TABLE locations
+  DROP COLUMN phone,
+  DROP COLUMN address,
+  DROP COLUMN kind;
+-- +goose StatementEnd
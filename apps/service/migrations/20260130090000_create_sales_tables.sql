@@ -0,0 +1,156 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE carts(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  status VARCHAR(20) NOT NULL DEFAULT 'open',
+  discount_amount BIGINT NOT NULL DEFAULT 0,
+  tax_rate BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_carts_shop_id ON carts(shop_id);
+
+CREATE TABLE cart_lines(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  cart_id BIGINT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+  variant_id BIGINT NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+  sku VARCHAR(64) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  unit_price BIGINT NOT NULL,
+  quantity BIGINT NOT NULL,
+  discount_amount BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_cart_lines_cart_id_variant_id ON cart_lines(cart_id, variant_id);
+
+-- One row per location holds the last receipt number it handed out; the
+-- checkout increments it in the transaction that records the sale.
+CREATE TABLE receipt_sequences(
+  location_id BIGINT PRIMARY KEY REFERENCES locations(id),
+  shop_id BIGINT NOT NULL REFERENCES shops(id),
+  last_number BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sales keep their rows for good, like the stock ledger they post to:
+-- nothing cascades into them, and the trigger below refuses to change or
+-- remove them.
+CREATE TABLE sales(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id),
+  location_id BIGINT NOT NULL REFERENCES locations(id),
+  cart_id BIGINT NOT NULL REFERENCES carts(id),
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  receipt_number BIGINT NOT NULL,
+  subtotal BIGINT NOT NULL,
+  discount_total BIGINT NOT NULL,
+  tax_rate BIGINT NOT NULL,
+  tax_total BIGINT NOT NULL,
+  total BIGINT NOT NULL,
+  paid BIGINT NOT NULL,
+  change BIGINT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sales_shop_id ON sales(shop_id);
+CREATE UNIQUE INDEX idx_sales_cart_id ON sales(cart_id);
+CREATE UNIQUE INDEX idx_sales_location_id_receipt_number ON sales(location_id, receipt_number);
+
+CREATE TABLE sale_lines(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  sale_id BIGINT NOT NULL REFERENCES sales(id),
+  variant_id BIGINT NOT NULL REFERENCES product_variants(id),
+  sku VARCHAR(64) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  unit_price BIGINT NOT NULL,
+  quantity BIGINT NOT NULL,
+  discount_amount BIGINT NOT NULL,
+  total BIGINT NOT NULL
+);
+
+CREATE INDEX idx_sale_lines_sale_id ON sale_lines(sale_id);
+
+CREATE TABLE sale_payments(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  sale_id BIGINT NOT NULL REFERENCES sales(id),
+  method VARCHAR(20) NOT NULL,
+  amount BIGINT NOT NULL,
+  reference VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_sale_payments_sale_id ON sale_payments(sale_id);
+
+CREATE FUNCTION sales_append_only() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'sales cannot be changed or deleted';
+END;
+$$;
+
+CREATE TRIGGER sales_append_only
+  BEFORE UPDATE OR DELETE ON sales
+  FOR EACH ROW EXECUTE FUNCTION sales_append_only();
+
+CREATE TRIGGER sale_lines_append_only
+  BEFORE UPDATE OR DELETE ON sale_lines
+  FOR EACH ROW EXECUTE FUNCTION sales_append_only();
+
+CREATE TRIGGER sale_payments_append_only
+  BEFORE UPDATE OR DELETE ON sale_payments
+  FOR EACH ROW EXECUTE FUNCTION sales_append_only();
+
+ALTER TABLE carts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE carts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON carts
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE cart_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cart_lines FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON cart_lines
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM carts WHERE carts.id = cart_lines.cart_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM carts WHERE carts.id = cart_lines.cart_id));
+
+ALTER TABLE receipt_sequences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE receipt_sequences FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON receipt_sequences
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE sales ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sales FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sales
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE sale_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sale_lines FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sale_lines
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM sales WHERE sales.id = sale_lines.sale_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM sales WHERE sales.id = sale_lines.sale_id));
+
+ALTER TABLE sale_payments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sale_payments FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sale_payments
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM sales WHERE sales.id = sale_payments.sale_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM sales WHERE sales.id = sale_payments.sale_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE sale_payments;
+DROP TABLE sale_lines;
+DROP TABLE sales;
+DROP FUNCTION sales_append_only();
+DROP TABLE receipt_sequences;
+DROP TABLE cart_lines;
+DROP TABLE carts;
+-- +goose StatementEnd
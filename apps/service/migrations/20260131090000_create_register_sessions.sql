@@ -0,0 +1,75 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE register_sessions(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  shop_id BIGINT NOT NULL REFERENCES shops(id),
+  location_id BIGINT NOT NULL REFERENCES locations(id),
+  status VARCHAR(20) NOT NULL DEFAULT 'open',
+  opening_float BIGINT NOT NULL DEFAULT 0,
+  opened_by_staff_id BIGINT NOT NULL REFERENCES staffs(id),
+  opened_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  closed_by_staff_id BIGINT REFERENCES staffs(id),
+  closed_at TIMESTAMP,
+  expected_cash BIGINT NOT NULL DEFAULT 0,
+  counted_cash BIGINT NOT NULL DEFAULT 0,
+  variance BIGINT NOT NULL DEFAULT 0,
+  note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_register_sessions_shop_id ON register_sessions(shop_id);
+-- A location's register is opened by one session at a time.
+CREATE UNIQUE INDEX idx_register_sessions_open_location_id ON register_sessions(location_id) WHERE status = 'open';
+
+-- Cash movements are kept for good, like the sales they are reconciled
+-- with.
+CREATE TABLE register_cash_movements(
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  session_id BIGINT NOT NULL REFERENCES register_sessions(id),
+  type VARCHAR(10) NOT NULL,
+  amount BIGINT NOT NULL,
+  reason VARCHAR(255) NOT NULL,
+  staff_id BIGINT NOT NULL REFERENCES staffs(id),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_register_cash_movements_session_id ON register_cash_movements(session_id);
+
+CREATE FUNCTION register_cash_movements_append_only() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'register cash movements cannot be changed or deleted';
+END;
+$$;
+
+CREATE TRIGGER register_cash_movements_append_only
+  BEFORE UPDATE OR DELETE ON register_cash_movements
+  FOR EACH ROW EXECUTE FUNCTION register_cash_movements_append_only();
+
+-- Sales made while no register session was open have none.
+ALTER TABLE sales ADD COLUMN register_session_id BIGINT REFERENCES register_sessions(id);
+
+CREATE INDEX idx_sales_register_session_id ON sales(register_session_id);
+
+ALTER TABLE register_sessions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE register_sessions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON register_sessions
+  USING (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id())
+  WITH CHECK (app_current_shop_id() IS NULL OR shop_id = app_current_shop_id());
+
+ALTER TABLE register_cash_movements ENABLE ROW LEVEL SECURITY;
+ALTER TABLE register_cash_movements FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON register_cash_movements
+  USING (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM register_sessions WHERE register_sessions.id = register_cash_movements.session_id))
+  WITH CHECK (app_current_shop_id() IS NULL OR EXISTS (SELECT 1 FROM register_sessions WHERE register_sessions.id = register_cash_movements.session_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX idx_sales_register_session_id;
+ALTER TABLE sales DROP COLUMN register_session_id;
+DROP TABLE register_cash_movements;
+DROP FUNCTION register_cash_movements_append_only();
+DROP TABLE register_sessions;
+-- +goose StatementEnd